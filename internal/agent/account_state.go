@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// maxLocalPending bounds the sent transactions looked up on the node.
+const maxLocalPending = 20
+
+// StoredSentTx is a transaction clifi broadcast.
+type StoredSentTx struct {
+	Chain  string
+	Hash   string
+	Sender string
+	Nonce  uint64
+	Tx     *types.Transaction
+	// Relay names the private relay it went through, "" for the public
+	// mempool.
+	Relay  string
+	SentAt time.Time
+}
+
+// RecordSent remembers a broadcast transaction. Unlike a receipt, the
+// record exists whether or not the transaction is ever mined.
+func (s *ReceiptStore) RecordSent(chainName string, from common.Address, signed *types.Transaction, relay string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	raw, err := signed.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshal tx: %w", err)
+	}
+	_, err = s.db.Exec(`
+INSERT OR REPLACE INTO sent_txs (chain, tx_hash, sender, nonce, raw_json, relay, sent_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`, chainName, strings.ToLower(signed.Hash().Hex()), strings.ToLower(from.Hex()), signed.Nonce(), string(raw), relay, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("persist sent tx: %w", err)
+	}
+	return nil
+}
+
+// SentFrom returns from's transactions on chainName with a nonce of at
+// least minNonce, by nonce and then send time.
+func (s *ReceiptStore) SentFrom(chainName string, from common.Address, minNonce uint64) ([]StoredSentTx, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	rows, err := s.db.Query(`
+SELECT tx_hash, nonce, raw_json, relay, sent_at FROM sent_txs
+WHERE chain = ? AND sender = ? AND nonce >= ?
+ORDER BY nonce, sent_at
+`, chainName, strings.ToLower(from.Hex()), minNonce)
+	if err != nil {
+		return nil, fmt.Errorf("load sent txs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StoredSentTx
+	for rows.Next() {
+		st := StoredSentTx{Chain: chainName, Sender: from.Hex()}
+		var raw string
+		var at int64
+		if err := rows.Scan(&st.Hash, &st.Nonce, &raw, &st.Relay, &at); err != nil {
+			return nil, fmt.Errorf("load sent txs: %w", err)
+		}
+		st.Tx = new(types.Transaction)
+		if err := st.Tx.UnmarshalJSON([]byte(raw)); err != nil {
+			return nil, fmt.Errorf("load sent tx %s: %w", st.Hash, err)
+		}
+		st.SentAt = time.Unix(at, 0).UTC()
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// recordSent is best effort: failing to remember a transaction must not
+// fail the send that already happened.
+func (tr *ToolRegistry) recordSent(chainName string, from common.Address, signed *types.Transaction, relay *chain.PrivateRelay) {
+	name := ""
+	if relay != nil {
+		name = relay.Name
+	}
+	rs, err := tr.receiptStore()
+	if err == nil {
+		err = rs.RecordSent(chainName, from, signed, name)
+	}
+	if err != nil {
+		slog.Debug("sent tx not recorded", "chain", chainName, "tx", signed.Hash().Hex(), "err", err)
+	}
+}
+
+// What the node knows of a locally sent transaction.
+const (
+	nodeMempool = "in mempool"
+	nodeMined   = "mined"
+	nodeUnknown = "unknown to node"
+)
+
+// localTx is a sent transaction with what the node says of it.
+type localTx struct {
+	StoredSentTx
+	State string
+}
+
+type getAccountStateInput struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+// handleGetAccountState compares the mined and pending nonces with the
+// transactions clifi sent, to explain why a send is stuck.
+func (tr *ToolRegistry) handleGetAccountState(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params getAccountStateInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	addr, err := requireHexAddress("address", params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if _, err := tr.chainClient.GetChainConfig(params.Chain); err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	latest, err := tr.chainClient.GetLatestNonce(ctx, params.Chain, addr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	pending, err := tr.chainClient.GetNonce(ctx, params.Chain, addr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	var local []localTx
+	if rs, err := tr.receiptStore(); err == nil {
+		sent, err := rs.SentFrom(params.Chain, addr, latest)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		if len(sent) > maxLocalPending {
+			sent = sent[len(sent)-maxLocalPending:]
+		}
+		for _, st := range sent {
+			local = append(local, localTx{StoredSentTx: st, State: tr.nodeState(ctx, params.Chain, st)})
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Account %s on %s:\n", addr.Hex(), params.Chain)
+	fmt.Fprintf(&b, "- Latest nonce (mined): %d\n", latest)
+	fmt.Fprintf(&b, "- Pending nonce (incl. mempool): %d\n", pending)
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "Address", Value: addr.Hex()},
+		{Key: "Latest nonce", Value: strconv.FormatUint(latest, 10)},
+		{Key: "Pending nonce", Value: strconv.FormatUint(pending, 10)},
+	}
+	blocks := []UIBlock{kvBlock("Account state", items...)}
+
+	if len(local) > 0 {
+		b.WriteString("Sent by clifi and not yet mined:\n")
+		table := &UITable{Title: "Sent transactions", Headers: []string{"Nonce", "Tx", "Sent", "Max fee (gwei)", "Node"}}
+		for _, lt := range local {
+			sent := lt.SentAt.Format("2006-01-02 15:04 UTC")
+			fee := weiToGwei(lt.Tx.GasFeeCap())
+			fmt.Fprintf(&b, "- nonce %d %s sent %s, max fee %s gwei: %s\n", lt.Nonce, lt.Hash, sent, fee, lt.State)
+			table.Rows = append(table.Rows, []string{strconv.FormatUint(lt.Nonce, 10), lt.Hash, sent, fee, lt.State})
+		}
+		blocks = append(blocks, UIBlock{Kind: UIBlockTable, Table: table})
+	}
+	for _, line := range diagnoseNonces(latest, pending, local) {
+		b.WriteString(line + "\n")
+	}
+	return ToolOutput{Text: b.String(), Blocks: blocks}, nil
+}
+
+// nodeState asks the node about a sent transaction. A lookup error other
+// than not-found says nothing, so it is reported as is.
+func (tr *ToolRegistry) nodeState(ctx context.Context, chainName string, st StoredSentTx) string {
+	_, isPending, err := tr.chainClient.TransactionByHash(ctx, chainName, common.HexToHash(st.Hash))
+	switch {
+	case errors.Is(err, ethereum.NotFound) && st.Relay != "":
+		// Private transactions stay out of the public mempool until mined.
+		return "private via " + st.Relay
+	case errors.Is(err, ethereum.NotFound):
+		return nodeUnknown
+	case err != nil:
+		return "lookup failed: " + err.Error()
+	case isPending:
+		return nodeMempool
+	}
+	return nodeMined
+}
+
+// diagnoseNonces explains the nonces: transactions queued behind the
+// lowest unmined one, sent ones the node dropped, and nonce gaps.
+func diagnoseNonces(latest, pending uint64, local []localTx) []string {
+	var out []string
+	if pending > latest {
+		out = append(out, fmt.Sprintf(
+			"%d transaction(s) wait in the node's mempool (nonces %d-%d). None can be mined before nonce %d; if it is underpriced, a transaction with nonce %d and a higher fee replaces it.",
+			pending-latest, latest, pending-1, latest, latest))
+	}
+	var dropped []string
+	for _, lt := range local {
+		switch {
+		case lt.State == nodeUnknown:
+			dropped = append(dropped, fmt.Sprintf("%s (nonce %d)", lt.Hash, lt.Nonce))
+		case lt.State == nodeMempool && lt.Nonce > pending:
+			out = append(out, fmt.Sprintf(
+				"Nonce gap: %s has nonce %d, but nothing uses nonce %d, so it cannot be mined until a transaction with nonce %d is.",
+				lt.Hash, lt.Nonce, pending, pending))
+		}
+	}
+	if len(dropped) > 0 {
+		out = append(out, fmt.Sprintf(
+			"Not known to the node, so likely dropped from its mempool: %s. Sending again reuses nonce %d.",
+			strings.Join(dropped, ", "), pending))
+	}
+	if len(out) == 0 {
+		out = append(out, fmt.Sprintf("Nothing is pending; the next transaction will use nonce %d.", pending))
+	}
+	return out
+}
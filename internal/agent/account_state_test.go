@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptStore_SentFrom(t *testing.T) {
+	store, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	for nonce := uint64(3); nonce <= 5; nonce++ {
+		tx := types.NewTx(&types.DynamicFeeTx{Nonce: nonce, To: &to, GasFeeCap: big.NewInt(2e9), Gas: 21000})
+		require.NoError(t, store.RecordSent("base", from, tx, ""))
+	}
+	private := types.NewTx(&types.DynamicFeeTx{Nonce: 6, To: &to, Gas: 21000})
+	require.NoError(t, store.RecordSent("base", from, private, "flashbots"))
+	other := types.NewTx(&types.DynamicFeeTx{Nonce: 9, To: &to, Gas: 21000})
+	require.NoError(t, store.RecordSent("ethereum", from, other, ""))
+
+	sent, err := store.SentFrom("base", from, 4)
+	require.NoError(t, err)
+	require.Len(t, sent, 3)
+	assert.Equal(t, uint64(4), sent[0].Nonce)
+	assert.Equal(t, strings.ToLower(sent[0].Tx.Hash().Hex()), sent[0].Hash)
+	assert.Equal(t, big.NewInt(2e9), sent[0].Tx.GasFeeCap())
+	assert.Equal(t, "flashbots", sent[2].Relay)
+}
+
+func TestDiagnoseNonces(t *testing.T) {
+	assert.Equal(t, []string{"Nothing is pending; the next transaction will use nonce 7."}, diagnoseNonces(7, 7, nil))
+
+	lines := diagnoseNonces(7, 9, nil)
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "2 transaction(s) wait in the node's mempool (nonces 7-8)")
+
+	dropped := localTx{StoredSentTx: StoredSentTx{Hash: "0xaa", Nonce: 7}, State: nodeUnknown}
+	gapped := localTx{StoredSentTx: StoredSentTx{Hash: "0xbb", Nonce: 8}, State: nodeMempool}
+	lines = diagnoseNonces(7, 7, []localTx{dropped, gapped})
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "Nonce gap: 0xbb has nonce 8, but nothing uses nonce 7")
+	assert.Contains(t, lines[1], "likely dropped from its mempool: 0xaa (nonce 7). Sending again reuses nonce 7.")
+}
@@ -0,0 +1,235 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+	"github.com/yolodolo42/clifi/internal/permit"
+)
+
+// defaultAuditBlocks is how far back audit_approvals looks for approvals.
+const defaultAuditBlocks = 50_000
+
+const erc20ApprovalEvent = "Approval(address indexed owner,address indexed spender,uint256 value)"
+
+// unlimitedAllowance is where an ERC-20 allowance is treated as unlimited:
+// wallets grant 2^256-1, and no real balance comes near 2^255.
+var unlimitedAllowance = new(big.Int).Lsh(big.NewInt(1), 255)
+
+type auditApprovalsInput struct {
+	Chain  string `json:"chain"`
+	Owner  string `json:"owner"`
+	Blocks uint64 `json:"blocks"`
+}
+
+// grant is a token and spender the owner approved at some point.
+type grant struct {
+	token, spender common.Address
+	viaPermit2     bool
+}
+
+// handleAuditApprovals finds the spenders an owner approved in recent
+// blocks, both directly and through Permit2, and reports which allowances
+// are still live. Approvals are found from logs, so older ones than the
+// scanned range are missed.
+func (tr *ToolRegistry) handleAuditApprovals(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params auditApprovalsInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	owner, err := requireHexAddress("owner", params.Owner)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if _, err := tr.chainClient.GetChainConfig(params.Chain); err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+	blocks := params.Blocks
+	if blocks == 0 {
+		blocks = defaultAuditBlocks
+	}
+	if blocks > maxLogBlocks {
+		return ToolOutput{}, fmt.Errorf("scan at most %d blocks at a time", maxLogBlocks)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
+	defer cancel()
+	latest, err := tr.chainClient.BlockNumber(ctx, params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	from := saturatingSub(latest, blocks-1)
+	grants, scannedFrom, err := tr.findGrants(ctx, params.Chain, owner, from, latest)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	now := time.Now()
+	title := fmt.Sprintf("Approvals by %s on %s", owner.Hex(), params.Chain)
+	table := &UITable{Title: title, Headers: []string{"Token", "Spender", "Via", "Allowance", "Expires", "Note"}}
+	var b strings.Builder
+	revoked := 0
+	for _, g := range grants {
+		row, live := tr.auditGrant(ctx, params.Chain, owner, g, now)
+		if !live {
+			revoked++
+			continue
+		}
+		table.Rows = append(table.Rows, row)
+		line := fmt.Sprintf("- %s to %s via %s: %s, expires %s", row[0], row[1], row[2], row[3], row[4])
+		if row[5] != "" {
+			line += " (" + row[5] + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	var out strings.Builder
+	if len(table.Rows) == 0 {
+		fmt.Fprintf(&out, "%s: no live allowances from approvals in blocks %d-%d.\n", title, scannedFrom, latest)
+	} else {
+		fmt.Fprintf(&out, "%s, from approvals in blocks %d-%d:\n", title, scannedFrom, latest)
+		out.WriteString(b.String())
+	}
+	if revoked > 0 {
+		fmt.Fprintf(&out, "%d other approval(s) found are now zero or expired.\n", revoked)
+	}
+	fmt.Fprintf(&out, "Approvals granted before block %d are not covered; raise blocks to look further back.\n", scannedFrom)
+	output := ToolOutput{Text: out.String()}
+	if len(table.Rows) > 0 {
+		output.Blocks = []UIBlock{{Kind: UIBlockTable, Table: table}}
+	}
+	return output, nil
+}
+
+// findGrants collects the token and spender pairs owner approved in
+// from..to: ERC-20 Approval events, and Permit2's Approval and Permit
+// events. It also returns the lowest block both scans covered.
+func (tr *ToolRegistry) findGrants(ctx context.Context, chainName string, owner common.Address, from, to uint64) ([]grant, uint64, error) {
+	where := map[string][]string{"owner": {owner.Hex()}}
+	erc20Topics, err := decode.EventTopics(erc20ApprovalEvent, where)
+	if err != nil {
+		return nil, 0, err
+	}
+	approvalTopics, err := decode.EventTopics(permit.Permit2ApprovalEvent, where)
+	if err != nil {
+		return nil, 0, err
+	}
+	permitTopics, err := decode.EventTopics(permit.Permit2PermitEvent, where)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := make(map[grant]bool)
+	var grants []grant
+	add := func(g grant) {
+		if !seen[g] {
+			seen[g] = true
+			grants = append(grants, g)
+		}
+	}
+
+	logs, lowest, err := tr.scanLogs(ctx, chainName, ethereum.FilterQuery{Topics: erc20Topics}, from, to, maxLogLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	scannedFrom := lowest
+	for _, l := range logs {
+		// ERC-721 approvals index the token ID as a fourth topic.
+		if len(l.Topics) == 3 && len(l.Data) == 32 {
+			add(grant{token: l.Address, spender: topicAddress(l, 2)})
+		}
+	}
+
+	q := ethereum.FilterQuery{
+		Addresses: []common.Address{permit.Permit2Address},
+		Topics:    [][]common.Hash{{approvalTopics[0][0], permitTopics[0][0]}, approvalTopics[1]},
+	}
+	logs, lowest, err = tr.scanLogs(ctx, chainName, q, from, to, maxLogLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+	scannedFrom = max(scannedFrom, lowest)
+	for _, l := range logs {
+		if len(l.Topics) == 4 {
+			add(grant{token: topicAddress(l, 2), spender: topicAddress(l, 3), viaPermit2: true})
+		}
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].viaPermit2 != grants[j].viaPermit2 {
+			return !grants[i].viaPermit2
+		}
+		return grants[i].token.Cmp(grants[j].token) < 0
+	})
+	return grants, scannedFrom, nil
+}
+
+// auditGrant reads a grant's current allowance as a table row, and
+// whether it is still usable.
+func (tr *ToolRegistry) auditGrant(ctx context.Context, chainName string, owner common.Address, g grant, now time.Time) ([]string, bool) {
+	symbol, decimals := shortAddress(g.token), uint8(0)
+	if s, d, err := tr.chainClient.GetTokenSymbolDecimals(ctx, chainName, g.token); err == nil && s != "" {
+		symbol, decimals = s, d
+	}
+	row := []string{symbol, g.spender.Hex(), "ERC20", "", "never", ""}
+	if g.spender == permit.Permit2Address {
+		row[5] = "Permit2 itself; spenders also need a Permit2 allowance"
+	}
+
+	if !g.viaPermit2 {
+		amount, err := tr.allowance(ctx, chainName, g.token, owner, g.spender)
+		if err != nil {
+			row[3] = "read failed: " + err.Error()
+			return row, true
+		}
+		if amount.Sign() == 0 {
+			return nil, false
+		}
+		row[3] = allowanceAmount(amount, decimals, amount.Cmp(unlimitedAllowance) >= 0)
+		if amount.Cmp(unlimitedAllowance) >= 0 && row[5] == "" {
+			row[5] = "unlimited and never expires"
+		}
+		return row, true
+	}
+
+	row[2] = "Permit2"
+	a, err := tr.permit2Allowance(ctx, chainName, owner, g.token, g.spender)
+	if err != nil {
+		row[3] = "read failed: " + err.Error()
+		return row, true
+	}
+	if a.Amount.Sign() == 0 || a.Expired(now) {
+		return nil, false
+	}
+	row[3] = allowanceAmount(a.Amount, decimals, a.Amount.Cmp(permit.MaxPermit2Amount) == 0)
+	row[4] = time.Unix(int64(a.Expiration), 0).UTC().Format(time.DateOnly)
+	if days := time.Unix(int64(a.Expiration), 0).Sub(now).Hours() / 24; days > maxPermitDays {
+		row[5] = "expires in " + strconv.Itoa(int(days)) + " days"
+	}
+	return row, true
+}
+
+func allowanceAmount(amount *big.Int, decimals uint8, unlimited bool) string {
+	if unlimited {
+		return "unlimited"
+	}
+	return chain.FormatBalance(amount, decimals)
+}
+
+func topicAddress(l types.Log, i int) common.Address {
+	return common.BytesToAddress(l.Topics[i].Bytes())
+}
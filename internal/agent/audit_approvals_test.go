@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yolodolo42/clifi/internal/permit"
+)
+
+func TestHandleAuditApprovals_Validation(t *testing.T) {
+	tr := NewToolRegistry()
+	owner := "0x00000000000000000000000000000000000000aa"
+	for _, tc := range []struct {
+		input, want string
+	}{
+		{`{"owner":"` + owner + `"}`, "chain is required"},
+		{`{"chain":"base","owner":"nope"}`, "owner"},
+		{`{"chain":"base","owner":"` + owner + `","blocks":200000}`, "at most 100000 blocks"},
+	} {
+		_, err := tr.handleAuditApprovals(context.Background(), json.RawMessage(tc.input))
+		assert.ErrorContains(t, err, tc.want, tc.input)
+	}
+}
+
+func TestAllowanceAmount(t *testing.T) {
+	assert.Equal(t, "25.000000", allowanceAmount(big.NewInt(25e6), 6, false))
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	assert.Equal(t, "unlimited", allowanceAmount(maxUint256, 18, maxUint256.Cmp(unlimitedAllowance) >= 0))
+	assert.Equal(t, "unlimited", allowanceAmount(permit.MaxPermit2Amount, 6, true))
+}
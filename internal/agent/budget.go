@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// SpendFile records the month's estimated LLM spend, in the data dir.
+const SpendFile = "usage.json"
+
+// DefaultWarnPercent is the share of the budget at which the agent warns.
+const DefaultWarnPercent = 80
+
+// spendLockStale is the age at which a spend lock counts as left behind
+// by a process that died holding it.
+const spendLockStale = 10 * time.Second
+
+// ErrBudgetExceeded is returned for a paid request once the month's
+// estimated spend has reached the budget.
+var ErrBudgetExceeded = errors.New("monthly LLM budget reached")
+
+// Budget caps the estimated monthly spend on paid models. Free models
+// (listed at no cost, such as Copilot's) are never refused.
+type Budget struct {
+	// MonthlyUSD is the cap; zero turns it off.
+	MonthlyUSD float64
+	// WarnPercent is the share of the cap at which the agent warns;
+	// DefaultWarnPercent when zero.
+	WarnPercent float64
+}
+
+// Spend is the estimated LLM spend in one calendar month. Models without
+// prices count tokens but no cost.
+type Spend struct {
+	Month        string  `json:"month"` // e.g. 2026-10
+	CostUSD      float64 `json:"cost_usd"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Calls        int     `json:"calls"`
+}
+
+func currentMonth() string {
+	return time.Now().Format("2006-01")
+}
+
+// LoadSpend returns this month's spend from dataDir; an earlier month's
+// record counts as nothing spent.
+func LoadSpend(dataDir string) (Spend, error) {
+	s := Spend{Month: currentMonth()}
+	data, err := os.ReadFile(filepath.Join(dataDir, SpendFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read %s: %w", SpendFile, err)
+	}
+	var stored Spend
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %w", SpendFile, err)
+	}
+	if stored.Month == s.Month {
+		s = stored
+	}
+	return s, nil
+}
+
+// ResetSpend clears this month's spend, as `clifi usage reset` does.
+func ResetSpend(dataDir string) error {
+	unlock, err := lockSpend(dataDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	err = os.Remove(filepath.Join(dataDir, SpendFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func saveSpend(dataDir string, s Spend) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(dataDir, SpendFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SpendFile, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// lockSpend takes the spend file's lock, a file next to it, so clifi
+// processes sharing the data dir, such as the REPL and clifi serve, don't
+// overwrite each other's updates. It returns the function that releases it.
+func lockSpend(dataDir string) (func(), error) {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dataDir, SpendFile+".lock")
+	deadline := time.Now().Add(2 * spendLockStale)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", SpendFile, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > spendLockStale {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock %s: %s is held", SpendFile, path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// SetBudget replaces the spending budget. It applies from the next
+// request.
+func (a *Agent) SetBudget(b Budget) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if b.WarnPercent <= 0 {
+		b.WarnPercent = DefaultWarnPercent
+	}
+	a.budget = b
+}
+
+// Budget returns the spending budget.
+func (a *Agent) Budget() Budget {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.budget
+}
+
+// Spend returns this month's spend toward the budget.
+func (a *Agent) Spend() (Spend, error) {
+	return LoadSpend(a.dataDir)
+}
+
+// modelInfo returns the provider's listing for a model.
+func (c settings) modelInfo(modelID string) (llm.Model, bool) {
+	for _, m := range c.provider.Models() {
+		if m.ID == modelID {
+			return m, true
+		}
+	}
+	return llm.Model{}, false
+}
+
+// isFreeModel reports whether a model is listed at no cost. Models clifi
+// has no prices for count as paid.
+func (c settings) isFreeModel(modelID string) bool {
+	m, ok := c.modelInfo(modelID)
+	return ok && !m.Unlisted && m.InputCost == 0 && m.OutputCost == 0
+}
+
+// checkBudget refuses a paid model once the budget is spent. It runs
+// before every provider request, so a turn's tool rounds stop at the cap
+// too. Callers hold mu.
+func (s *Session) checkBudget(modelID string) error {
+	a, budget := s.agent, s.cur.budget
+	if budget.MonthlyUSD <= 0 || a.dataDir == "" || s.cur.isFreeModel(modelID) {
+		return nil
+	}
+	spend, err := LoadSpend(a.dataDir)
+	if err != nil {
+		return err
+	}
+	if spend.CostUSD < budget.MonthlyUSD {
+		return nil
+	}
+	return fmt.Errorf("%w: $%.2f of $%.2f spent this month. Switch to a free model, raise llm.budget.monthly_usd, or run clifi usage reset", ErrBudgetExceeded, spend.CostUSD, budget.MonthlyUSD)
+}
+
+// estimateCost prices a response from the model list; models without
+// prices cost nothing.
+func (c settings) estimateCost(modelID string, u llm.Usage) float64 {
+	m, ok := c.modelInfo(modelID)
+	if !ok {
+		return 0
+	}
+	return (float64(u.InputTokens)*m.InputCost + float64(u.OutputTokens)*m.OutputCost) / 1_000_000
+}
+
+// recordSpend adds a response from the provider in c to the usage history
+// and its estimated cost to the month's spend. Sessions record theirs one
+// at a time, and processes under the spend file's lock.
+func (a *Agent) recordSpend(c settings, modelID string, u llm.Usage) {
+	if u.InputTokens == 0 && u.OutputTokens == 0 {
+		return
+	}
+	a.spendMu.Lock()
+	defer a.spendMu.Unlock()
+	cost := c.estimateCost(modelID, u)
+	a.recordUsageHistory(c.provider.ID(), modelID, u, cost)
+	if a.dataDir == "" {
+		return
+	}
+	unlock, err := lockSpend(a.dataDir)
+	if err != nil {
+		slog.Warn("failed to record LLM spend", "err", err)
+		return
+	}
+	defer unlock()
+	s, err := LoadSpend(a.dataDir)
+	if err != nil {
+		slog.Warn("failed to record LLM spend", "err", err)
+		return
+	}
+	s.CostUSD += cost
+	s.InputTokens += int64(u.InputTokens)
+	s.OutputTokens += int64(u.OutputTokens)
+	s.Calls++
+	if err := saveSpend(a.dataDir, s); err != nil {
+		slog.Warn("failed to record LLM spend", "err", err)
+	}
+}
+
+// budgetNotice returns a warning when spend first crosses the warning
+// share of the budget, and again at the cap, or "". Callers hold mu.
+func (s *Session) budgetNotice() string {
+	a, budget := s.agent, s.cur.budget
+	if budget.MonthlyUSD <= 0 || a.dataDir == "" {
+		return ""
+	}
+	spend, err := LoadSpend(a.dataDir)
+	if err != nil {
+		return ""
+	}
+	percent := spend.CostUSD / budget.MonthlyUSD * 100
+	if percent < budget.WarnPercent {
+		return ""
+	}
+	// Warn once at the warning share and once more at the cap.
+	level := spend.Month + " warn"
+	if percent >= 100 {
+		level = spend.Month + " cap"
+	}
+	if s.budgetWarned == level {
+		return ""
+	}
+	s.budgetWarned = level
+	if percent >= 100 {
+		return fmt.Sprintf("Monthly LLM budget reached: $%.2f of $%.2f. Paid models are refused until next month; free models still work.", spend.CostUSD, budget.MonthlyUSD)
+	}
+	return fmt.Sprintf("%.0f%% of the monthly LLM budget used: $%.2f of $%.2f.", percent, spend.CostUSD, budget.MonthlyUSD)
+}
@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// pricedMock is the mock provider with a price on its model.
+type pricedMock struct {
+	*llm.MockProvider
+}
+
+func (pricedMock) Models() []llm.Model {
+	m := llm.MockModels[0]
+	m.InputCost, m.OutputCost = 1000, 1000
+	return []llm.Model{m}
+}
+
+func TestAgent_Budget(t *testing.T) {
+	dir := t.TempDir()
+	a := NewWithProvider(pricedMock{llm.NewMockProvider()}, dir)
+	defer a.Close()
+	a.SetBudget(Budget{MonthlyUSD: 0.2})
+
+	var notices []string
+	onEvent := func(e ChatEvent) {
+		if e.Type == "notice" {
+			notices = append(notices, e.Content)
+		}
+	}
+	_, err := a.ChatStream(context.Background(), "hi", onEvent)
+	require.NoError(t, err)
+	s, err := LoadSpend(dir)
+	require.NoError(t, err)
+	assert.Greater(t, s.CostUSD, 0.2)
+	assert.Equal(t, 1, s.Calls)
+	require.Len(t, notices, 1)
+	assert.Contains(t, notices[0], "budget reached")
+
+	_, err = a.ChatStream(context.Background(), "again", onEvent)
+	assert.ErrorIs(t, err, ErrBudgetExceeded)
+	assert.Len(t, notices, 1, "warned once")
+
+	require.NoError(t, ResetSpend(dir))
+	_, err = a.ChatStream(context.Background(), "after reset", onEvent)
+	assert.NoError(t, err)
+}
+
+func TestAgent_BudgetAllowsFreeModels(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, saveSpend(dir, Spend{Month: currentMonth(), CostUSD: 100}))
+	a := NewWithProvider(llm.NewMockProvider(), dir)
+	defer a.Close()
+	a.SetBudget(Budget{MonthlyUSD: 10})
+
+	_, err := a.Chat(context.Background(), "hi")
+	require.NoError(t, err)
+	s, err := LoadSpend(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, s.CostUSD, "free models add no cost")
+	assert.Equal(t, 1, s.Calls)
+}
+
+func TestLoadSpend_NewMonth(t *testing.T) {
+	dir := t.TempDir()
+	data, err := json.Marshal(Spend{Month: "2001-01", CostUSD: 50, Calls: 3})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SpendFile), data, 0600))
+
+	s, err := LoadSpend(dir)
+	require.NoError(t, err)
+	assert.Equal(t, Spend{Month: currentMonth()}, s)
+}
+
+func TestAgent_BudgetCheckedEveryRequest(t *testing.T) {
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{{ID: "1", Name: "list_chains", Input: json.RawMessage(`{}`)}}},
+		{Content: "done"},
+	}})
+	a := NewWithProvider(pricedMock{provider}, t.TempDir())
+	defer a.Close()
+	a.SetBudget(Budget{MonthlyUSD: 0.2})
+
+	_, err := a.Chat(context.Background(), "chains?")
+	assert.ErrorIs(t, err, ErrBudgetExceeded, "the first request spent the budget")
+	assert.Len(t, provider.Requests(), 1, "tool results were not sent")
+}
+
+func TestRecordSpend_SharedAcrossProcesses(t *testing.T) {
+	dir := t.TempDir()
+	// Each agent has its own spendMu, as separate processes would.
+	agents := []*Agent{NewWithProvider(pricedMock{llm.NewMockProvider()}, dir), NewWithProvider(pricedMock{llm.NewMockProvider()}, dir)}
+	const calls = 20
+	var wg sync.WaitGroup
+	for _, a := range agents {
+		defer a.Close()
+		c := settings{provider: a.provider}
+		wg.Go(func() {
+			for range calls {
+				a.recordSpend(c, llm.MockModels[0].ID, llm.Usage{InputTokens: 1})
+			}
+		})
+	}
+	wg.Wait()
+
+	s, err := LoadSpend(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 2*calls, s.Calls, "no update lost")
+	assert.NoFileExists(t, filepath.Join(dir, SpendFile+".lock"))
+}
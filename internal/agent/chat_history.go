@@ -0,0 +1,246 @@
+package agent
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// ChatHit is a stored message that matched a history search.
+type ChatHit struct {
+	ID      int64     `json:"id"`
+	Session string    `json:"session"`
+	Role    string    `json:"role"`
+	At      time.Time `json:"at"`
+	Snippet string    `json:"snippet"`
+}
+
+// AddChatMessage stores one message of a session's conversation and
+// returns its ID.
+func (s *ReceiptStore) AddChatMessage(session, role, content string, at time.Time) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, fmt.Errorf("receipt store not initialized")
+	}
+	res, err := s.db.Exec(`INSERT INTO chat_messages (session, role, content, at) VALUES (?, ?, ?, ?)`,
+		session, role, content, at.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("persist chat message: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// DropLastChatTurn deletes a session's last user message and the replies
+// after it, as UndoLastTurn does to the conversation.
+func (s *ReceiptStore) DropLastChatTurn(session string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	_, err := s.db.Exec(`
+DELETE FROM chat_messages WHERE session = ? AND id >= (
+	SELECT MAX(id) FROM chat_messages WHERE session = ? AND role = 'user'
+)`, session, session)
+	if err != nil {
+		return fmt.Errorf("delete chat turn: %w", err)
+	}
+	return nil
+}
+
+// SearchChat finds stored messages, questions and answers alike, holding
+// every word of query, best matches first. Words match their inflections:
+// "approval" finds "approvals".
+func (s *ReceiptStore) SearchChat(query string, limit int) ([]ChatHit, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, fmt.Errorf("nothing to search for")
+	}
+	rows, err := s.db.Query(`
+SELECT m.id, m.session, m.role, m.at, snippet(chat_fts, 0, '', '', '…', 16)
+FROM chat_fts JOIN chat_messages m ON m.id = chat_fts.rowid
+WHERE chat_fts MATCH ?
+ORDER BY rank, m.id DESC
+LIMIT ?`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search chat history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ChatHit
+	for rows.Next() {
+		var h ChatHit
+		var at int64
+		if err := rows.Scan(&h.ID, &h.Session, &h.Role, &at, &h.Snippet); err != nil {
+			return nil, fmt.Errorf("search chat history: %w", err)
+		}
+		h.At = time.Unix(at, 0)
+		h.Snippet = strings.Join(strings.Fields(h.Snippet), " ")
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// ftsQuery quotes each word of a search, so punctuation and FTS5
+// operators in it are matched as text.
+func ftsQuery(query string) string {
+	words := strings.Fields(query)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// ChatThread returns the conversation up to message id: its session's
+// messages before it, and those of the sessions it was resumed from. A
+// question is kept with its answer. It also returns the ID of the last
+// message included.
+func (s *ReceiptStore) ChatThread(id int64) ([]llm.Message, int64, error) {
+	if s == nil || s.db == nil {
+		return nil, 0, fmt.Errorf("receipt store not initialized")
+	}
+	var session, role string
+	err := s.db.QueryRow(`SELECT session, role FROM chat_messages WHERE id = ?`, id).Scan(&session, &role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, fmt.Errorf("no message #%d in the chat history", id)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("load chat history: %w", err)
+	}
+	if role == "user" {
+		var next int64
+		err := s.db.QueryRow(`SELECT id, role FROM chat_messages WHERE session = ? AND id > ? ORDER BY id LIMIT 1`, session, id).Scan(&next, &role)
+		if err == nil && role == "assistant" {
+			id = next
+		}
+	}
+
+	last := id
+	var thread []llm.Message
+	for {
+		msgs, err := s.sessionMessages(session, id)
+		if err != nil {
+			return nil, 0, err
+		}
+		thread = append(msgs, thread...)
+
+		// Resumed sessions start from a message of an earlier one, so the
+		// IDs only go down and this ends.
+		err = s.db.QueryRow(`
+SELECT c.resumed_from, m.session FROM chat_sessions c JOIN chat_messages m ON m.id = c.resumed_from
+WHERE c.session = ?`, session).Scan(&id, &session)
+		if errors.Is(err, sql.ErrNoRows) {
+			return thread, last, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("load chat history: %w", err)
+		}
+	}
+}
+
+func (s *ReceiptStore) sessionMessages(session string, upTo int64) ([]llm.Message, error) {
+	rows, err := s.db.Query(`SELECT role, content FROM chat_messages WHERE session = ? AND id <= ? ORDER BY id`, session, upTo)
+	if err != nil {
+		return nil, fmt.Errorf("load chat history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []llm.Message
+	for rows.Next() {
+		var m llm.Message
+		if err := rows.Scan(&m.Role, &m.Content); err != nil {
+			return nil, fmt.Errorf("load chat history: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// ResumeChatSession records that session continues the conversation
+// ending at message id.
+func (s *ReceiptStore) ResumeChatSession(session string, id int64) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO chat_sessions (session, resumed_from) VALUES (?, ?)`, session, id)
+	if err != nil {
+		return fmt.Errorf("persist chat session: %w", err)
+	}
+	return nil
+}
+
+// SearchHistory finds earlier messages from every session; see
+// ReceiptStore.SearchChat.
+func (a *Agent) SearchHistory(query string, limit int) ([]ChatHit, error) {
+	rs, err := a.toolRegistry.receiptStore()
+	if err != nil {
+		return nil, err
+	}
+	return rs.SearchChat(query, limit)
+}
+
+// Resume replaces the conversation with the stored one up to message id,
+// from any earlier session, and continues it from there. It returns the
+// messages restored.
+func (s *Session) Resume(id int64) ([]llm.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs, err := s.agent.toolRegistry.receiptStore()
+	if err != nil {
+		return nil, err
+	}
+	thread, last, err := rs.ChatThread(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.clear()
+	s.plan = nil
+	for _, m := range thread {
+		if m.Role == "user" {
+			s.transcript.AddUserMessage(m.Content)
+		} else {
+			s.transcript.AddAssistantMessage(m.Content, nil)
+		}
+	}
+	s.conversation = thread
+	s.recordContext(llm.Usage{}, s.agent.toolRegistry.GetTools())
+	if err := rs.ResumeChatSession(s.sessionID, last); err != nil {
+		return nil, err
+	}
+	s.log(sessionRecord{TS: nowTS(), Type: "resume", Content: fmt.Sprintf("#%d", last)})
+	return thread, nil
+}
+
+// addMessages appends to the conversation and stores the messages in the
+// chat history. Callers hold mu.
+func (s *Session) addMessages(msgs ...llm.Message) {
+	s.conversation = append(s.conversation, msgs...)
+	s.ensureSession()
+	rs, err := s.agent.toolRegistry.receiptStore()
+	for _, m := range msgs {
+		if err == nil {
+			_, err = rs.AddChatMessage(s.sessionID, m.Role, m.Content, time.Now())
+		}
+	}
+	if err != nil {
+		slog.Warn("failed to record chat history", "err", err)
+	}
+}
+
+// dropHistoryTurn removes the last turn from the chat history. Callers
+// hold mu.
+func (s *Session) dropHistoryTurn() {
+	rs, err := s.agent.toolRegistry.receiptStore()
+	if err == nil {
+		err = rs.DropLastChatTurn(s.sessionID)
+	}
+	if err != nil {
+		slog.Warn("failed to record chat history", "err", err)
+	}
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestReceiptStore_SearchChat(t *testing.T) {
+	rs, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rs.Close() })
+
+	at := time.Unix(1_780_000_000, 0)
+	_, err = rs.AddChatMessage("s1", "user", "any risky approvals on base?", at)
+	require.NoError(t, err)
+	answer, err := rs.AddChatMessage("s1", "assistant", "One unlimited approval on Base: USDC to 0xdef1.", at)
+	require.NoError(t, err)
+	_, err = rs.AddChatMessage("s2", "user", "gas price on arbitrum", at)
+	require.NoError(t, err)
+
+	hits, err := rs.SearchChat("approval base", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 2, "stems match: approval finds approvals")
+	assert.ElementsMatch(t, []string{"user", "assistant"}, []string{hits[0].Role, hits[1].Role})
+	assert.Equal(t, at, hits[0].At)
+
+	hits, err = rs.SearchChat(`"0xdef1" OR`, 10)
+	require.NoError(t, err, "operators and quotes are searched as text")
+	require.Len(t, hits, 0)
+
+	require.NoError(t, rs.DropLastChatTurn("s1"))
+	hits, err = rs.SearchChat("approval", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits, "an undone turn leaves the index")
+
+	_, _, err = rs.ChatThread(answer)
+	assert.ErrorContains(t, err, "no message")
+}
+
+func TestSession_ResumeFromHistory(t *testing.T) {
+	provider := llm.NewMockProvider(
+		llm.MockTurn{Match: "approvals", Responses: []llm.ChatResponse{{Content: "One approval on base."}}},
+		llm.MockTurn{Match: "revoke", Responses: []llm.ChatResponse{{Content: "Revoked."}}},
+	)
+	ag := NewWithProvider(provider, t.TempDir())
+	t.Cleanup(ag.Close)
+	ctx := context.Background()
+
+	_, err := ag.Chat(ctx, "any approvals on base?")
+	require.NoError(t, err)
+	_, err = ag.Chat(ctx, "revoke it")
+	require.NoError(t, err)
+
+	hits, err := ag.SearchHistory("approvals on base", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, hits)
+	var question int64
+	for _, h := range hits {
+		if h.Role == "user" {
+			question = h.ID
+		}
+	}
+	require.NotZero(t, question)
+
+	// Resuming at a question brings its answer along, not what followed.
+	s := ag.NewSession()
+	msgs, err := s.Resume(question)
+	require.NoError(t, err)
+	want := []llm.Message{{Role: "user", Content: "any approvals on base?"}, {Role: "assistant", Content: "One approval on base."}}
+	assert.Equal(t, want, msgs)
+	assert.Equal(t, want, s.Export().ToMessages())
+
+	_, err = s.Chat(ctx, "revoke it please")
+	require.NoError(t, err)
+	reqs := provider.Requests()
+	assert.Equal(t, append(want, llm.Message{Role: "user", Content: "revoke it please"}), reqs[len(reqs)-1].Messages)
+
+	// A resumed conversation resumes with what it was resumed from.
+	hits, err = ag.SearchHistory("revoke please", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	msgs, err = ag.Resume(hits[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, msgs, 4)
+	assert.Equal(t, "Revoked.", msgs[3].Content)
+}
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"encoding/json"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// charsPerToken is a rough average for English text and JSON, used when a
+// provider doesn't report token counts.
+const charsPerToken = 4
+
+// ContextUsage is how much of the model's context window the current
+// conversation fills.
+type ContextUsage struct {
+	Tokens    int  // prompt plus reply of the latest request
+	Window    int  // context window of the current model; 0 if unknown
+	Estimated bool // Tokens was estimated from text length
+}
+
+// Fraction returns Tokens as a share of Window, or 0 if the window is
+// unknown.
+func (u ContextUsage) Fraction() float64 {
+	if u.Window <= 0 {
+		return 0
+	}
+	return float64(u.Tokens) / float64(u.Window)
+}
+
+// ContextUsage reports the size of the conversation as of the last
+// request. Before the first request it estimates the system prompt and
+// tool definitions, which every request carries.
+func (s *Session) ContextUsage() ContextUsage {
+	a := s.agent
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	u := ContextUsage{
+		Tokens:    int(s.contextTokens.Load()),
+		Window:    a.contextWindow(),
+		Estimated: s.contextEstimated.Load(),
+	}
+	if u.Tokens == 0 {
+		u.Tokens = estimateTokens(a.systemPrompt, nil, a.toolRegistry.GetTools())
+		u.Estimated = true
+	}
+	return u
+}
+
+// contextWindow returns the current model's context window, if listed.
+// Callers hold mu for reading.
+func (a *Agent) contextWindow() int {
+	id := a.provider.DefaultModel()
+	for _, m := range a.provider.Models() {
+		if m.ID == id {
+			return m.ContextWindow
+		}
+	}
+	return 0
+}
+
+// recordContext stores the conversation size after a request, from the
+// provider's usage if reported and by estimate otherwise. Callers hold mu.
+func (s *Session) recordContext(last llm.Usage, tools []llm.Tool) {
+	if last.InputTokens > 0 {
+		s.contextTokens.Store(int64(last.InputTokens + last.OutputTokens))
+		s.contextEstimated.Store(false)
+		return
+	}
+	s.contextTokens.Store(int64(estimateTokens(s.agent.systemPrompt, s.conversation, tools)))
+	s.contextEstimated.Store(true)
+}
+
+// resetContext forgets the conversation size when the conversation is
+// cleared.
+func (s *Session) resetContext() {
+	s.contextTokens.Store(0)
+	s.contextEstimated.Store(false)
+}
+
+func estimateTokens(systemPrompt string, messages []llm.Message, tools []llm.Tool) int {
+	chars := len(systemPrompt)
+	for _, m := range messages {
+		chars += len(m.Content)
+	}
+	if len(tools) > 0 {
+		if b, err := json.Marshal(tools); err == nil {
+			chars += len(b)
+		}
+	}
+	return (chars + charsPerToken - 1) / charsPerToken
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// contractCheck describes what the preview should tell the user about a
+// contract they are about to grant rights to.
+type contractCheck struct {
+	Lines []string
+	// Warn is true when the user should think twice: the address has no code,
+	// or its source is not verified.
+	Warn bool
+}
+
+// checkContract inspects a counterparty address before an approval or contract
+// write. It never fails the tool call: an unreachable explorer only downgrades
+// the preview to "unknown", since blocking would make clifi unusable offline.
+func (tr *ToolRegistry) checkContract(ctx context.Context, chainName string, cfg *chain.ChainConfig, role string, addr common.Address) contractCheck {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	code, err := tr.chainClient.GetCode(ctx, chainName, addr)
+	if err != nil {
+		return contractCheck{Lines: []string{fmt.Sprintf("- %s contract check: unavailable (%v)", role, err)}}
+	}
+	if len(code) == 0 {
+		return contractCheck{
+			Warn:  true,
+			Lines: []string{fmt.Sprintf("⚠ WARNING: %s %s is not a contract (no code). Approvals to plain addresses are a common phishing pattern.", role, addr.Hex())},
+		}
+	}
+
+	res, err := tr.verifier.Check(ctx, cfg.ChainIDInt, addr)
+	if err != nil {
+		return contractCheck{Lines: []string{fmt.Sprintf("- %s verification: unknown (%v)", role, err)}}
+	}
+	return describeVerification(role, res)
+}
+
+// previewText renders the check for a tool preview. Warnings also instruct the
+// model to get explicit acknowledgement, so a warning can't be skipped by an
+// agent that confirms on the user's behalf.
+func (c contractCheck) previewText() string {
+	if len(c.Lines) == 0 {
+		return ""
+	}
+	text := strings.Join(c.Lines, "\n") + "\n"
+	if c.Warn {
+		text += "Ask the user to explicitly acknowledge the warning above before setting confirm=true.\n"
+	}
+	return text
+}
+
+// and combines two checks into one preview section.
+func (c contractCheck) and(other contractCheck) contractCheck {
+	return contractCheck{Lines: append(append([]string(nil), c.Lines...), other.Lines...), Warn: c.Warn || other.Warn}
+}
+
+func describeVerification(role string, res *chain.ContractVerification) contractCheck {
+	if !res.Verified {
+		return contractCheck{
+			Warn: true,
+			Lines: []string{
+				fmt.Sprintf("⚠ WARNING: %s %s is an UNVERIFIED contract (no published source on %s).", role, res.Address, res.Source),
+				"⚠ Its behavior cannot be inspected. Only continue if you trust it from another source.",
+			},
+		}
+	}
+
+	details := []string{}
+	if res.Name != "" {
+		details = append(details, res.Name)
+	}
+	if res.Compiler != "" {
+		details = append(details, res.Compiler)
+	}
+	line := fmt.Sprintf("- %s: verified on %s", role, res.Source)
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	return contractCheck{Lines: []string{line}}
+}
@@ -0,0 +1,26 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+func TestDescribeVerification(t *testing.T) {
+	t.Run("verified shows name and compiler", func(t *testing.T) {
+		c := describeVerification("Spender", &chain.ContractVerification{
+			Address: "0xabc", Verified: true, Name: "Permit2", Compiler: "0.8.17", Source: "sourcify",
+		})
+		assert.False(t, c.Warn)
+		assert.Equal(t, "- Spender: verified on sourcify (Permit2, 0.8.17)\n", c.previewText())
+	})
+
+	t.Run("unverified warns loudly", func(t *testing.T) {
+		c := describeVerification("Spender", &chain.ContractVerification{Address: "0xabc", Source: "etherscan"})
+		assert.True(t, c.Warn)
+		text := c.previewText()
+		assert.Contains(t, text, "UNVERIFIED")
+		assert.Contains(t, text, "explicitly acknowledge")
+	})
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/yolodolo42/clifi/internal/llm"
@@ -18,9 +20,19 @@ type ConversationTurn struct {
 
 // Conversation holds the full conversation state
 type Conversation struct {
-	ID        string             `json:"id"`
-	StartedAt time.Time          `json:"started_at"`
-	Turns     []ConversationTurn `json:"turns"`
+	ID        string                `json:"id"`
+	StartedAt time.Time             `json:"started_at"`
+	Turns     []ConversationTurn    `json:"turns"`
+	Receipts  []ConversationReceipt `json:"receipts,omitempty"`
+}
+
+// ConversationReceipt is a stored receipt for a transaction that appears in
+// the conversation's tool results.
+type ConversationReceipt struct {
+	Chain   string `json:"chain"`
+	TxHash  string `json:"tx_hash"`
+	Status  uint64 `json:"status"`
+	GasUsed uint64 `json:"gas_used"`
 }
 
 // NewConversation creates a new conversation
@@ -60,6 +72,19 @@ func (c *Conversation) AddToolResult(result llm.ToolResult) {
 	})
 }
 
+// TruncateLastTurn removes the last user message and the turns after it,
+// returning the message. ok is false if there is no user message.
+func (c *Conversation) TruncateLastTurn() (content string, ok bool) {
+	for i := len(c.Turns) - 1; i >= 0; i-- {
+		if c.Turns[i].Role == "user" {
+			content = c.Turns[i].Content
+			c.Turns = c.Turns[:i]
+			return content, true
+		}
+	}
+	return "", false
+}
+
 // ToMessages converts the conversation to LLM messages format
 func (c *Conversation) ToMessages() []llm.Message {
 	messages := make([]llm.Message, 0)
@@ -79,6 +104,71 @@ func (c *Conversation) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(c, "", "  ")
 }
 
+// ToMarkdown renders the conversation as a Markdown document: messages in
+// order, tool calls with their arguments, tool results as code blocks, and a
+// receipts table.
+func (c *Conversation) ToMarkdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# clifi conversation %s\n\nStarted %s\n", c.ID, c.StartedAt.Format(time.RFC3339))
+
+	toolNames := make(map[string]string)
+	for _, turn := range c.Turns {
+		switch turn.Role {
+		case "user":
+			fmt.Fprintf(&b, "\n## User (%s)\n\n%s\n", turn.Timestamp.Format(time.TimeOnly), turn.Content)
+
+		case "assistant":
+			fmt.Fprintf(&b, "\n## Assistant (%s)\n", turn.Timestamp.Format(time.TimeOnly))
+			if turn.Content != "" {
+				fmt.Fprintf(&b, "\n%s\n", turn.Content)
+			}
+			for _, tc := range turn.ToolCalls {
+				toolNames[tc.ID] = tc.Name
+				fmt.Fprintf(&b, "\n**Tool call:** `%s`\n", tc.Name)
+				if len(tc.Input) > 0 && string(tc.Input) != "null" {
+					fmt.Fprintf(&b, "\n```json\n%s\n```\n", tc.Input)
+				}
+			}
+
+		case "tool":
+			if turn.ToolResult == nil {
+				continue
+			}
+			name := toolNames[turn.ToolResult.ToolUseID]
+			if name == "" {
+				name = "tool"
+			}
+			label := "Result"
+			if turn.ToolResult.IsError {
+				label = "Error"
+			}
+			fmt.Fprintf(&b, "\n**%s:** `%s`\n\n%s\n", label, name, fence(turn.ToolResult.Content))
+		}
+	}
+
+	if len(c.Receipts) > 0 {
+		b.WriteString("\n## Receipts\n\n| Chain | Tx hash | Status | Gas used |\n| --- | --- | --- | --- |\n")
+		for _, r := range c.Receipts {
+			status := "failed"
+			if r.Status == 1 {
+				status = "success"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %d |\n", r.Chain, r.TxHash, status, r.GasUsed)
+		}
+	}
+	return b.String()
+}
+
+// fence wraps text in a code fence long enough not to be closed by any
+// backtick run inside it.
+func fence(text string) string {
+	ticks := "```"
+	for strings.Contains(text, ticks) {
+		ticks += "`"
+	}
+	return ticks + "\n" + strings.TrimRight(text, "\n") + "\n" + ticks
+}
+
 // generateID creates a simple unique ID for the conversation
 func generateID() string {
 	return time.Now().Format("20060102-150405")
@@ -206,3 +206,22 @@ func TestConversationTurn(t *testing.T) {
 		assert.Equal(t, "test", turn.Content)
 	})
 }
+
+func TestConversation_ToMarkdown(t *testing.T) {
+	conv := NewConversation()
+	conv.AddUserMessage("What's my balance?")
+	conv.AddAssistantMessage("", []llm.ToolCall{
+		{ID: "tc_1", Name: "get_balances", Input: json.RawMessage(`{"chain":"base"}`)},
+	})
+	conv.AddToolResult(llm.ToolResult{ToolUseID: "tc_1", Content: "ETH: 1.5\n```\nnested\n```"})
+	conv.AddAssistantMessage("You have 1.5 ETH on Base.", nil)
+	conv.Receipts = []ConversationReceipt{{Chain: "base", TxHash: "0xabc", Status: 1, GasUsed: 21000}}
+
+	md := conv.ToMarkdown()
+	assert.Contains(t, md, "# clifi conversation "+conv.ID)
+	assert.Contains(t, md, "What's my balance?")
+	assert.Contains(t, md, "**Tool call:** `get_balances`\n\n```json\n{\"chain\":\"base\"}\n```")
+	assert.Contains(t, md, "**Result:** `get_balances`\n\n````\nETH: 1.5\n```\nnested\n```\n````", "fence outlasts backticks in the result")
+	assert.Contains(t, md, "You have 1.5 ETH on Base.")
+	assert.Contains(t, md, "| base | `0xabc` | success | 21000 |")
+}
@@ -0,0 +1,377 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/cosmos"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+// cosmosGasAdjustment pads simulated gas, since execution can use slightly
+// more gas than simulation did.
+const cosmosGasAdjustment = 1.3
+
+func (tr *ToolRegistry) cosmosKeystore() (*cosmos.Keystore, error) {
+	tr.cosmosKsOnce.Do(func() {
+		if tr.dataDir == "" {
+			tr.cosmosKsErr = fmt.Errorf("data dir not configured")
+			return
+		}
+		tr.cosmosKs, tr.cosmosKsErr = cosmos.NewKeystore(tr.dataDir)
+	})
+	return tr.cosmosKs, tr.cosmosKsErr
+}
+
+// cosmosKey returns the stored key for address on a chain, defaulting to the
+// first stored key when address is empty.
+func (tr *ToolRegistry) cosmosKey(cfg *cosmos.ChainConfig, address string) (cosmos.Key, error) {
+	ks, err := tr.cosmosKeystore()
+	if err != nil {
+		return cosmos.Key{}, err
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return cosmos.Key{}, err
+	}
+	if len(keys) == 0 {
+		return cosmos.Key{}, fmt.Errorf("no cosmos wallets found; use 'clifi wallet cosmos create'")
+	}
+	if address == "" {
+		return keys[0], nil
+	}
+
+	raw, err := cfg.ParseAddress(address)
+	if err != nil {
+		return cosmos.Key{}, err
+	}
+	for _, k := range keys {
+		if bytes.Equal(k.Address, raw) {
+			return k, nil
+		}
+	}
+	return cosmos.Key{}, fmt.Errorf("address %s is not in the cosmos keystore", address)
+}
+
+// cosmosAddress resolves a balance address: explicit, or the first stored key.
+func (tr *ToolRegistry) cosmosAddress(cfg *cosmos.ChainConfig, address string) (string, error) {
+	if address != "" {
+		if _, err := cfg.ParseAddress(address); err != nil {
+			return "", err
+		}
+		return address, nil
+	}
+	key, err := tr.cosmosKey(cfg, "")
+	if err != nil {
+		return "", err
+	}
+	return cfg.Address(key.Address), nil
+}
+
+type getCosmosBalancesInput struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+func (tr *ToolRegistry) handleGetCosmosBalances(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var params getCosmosBalancesInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	cfg, err := tr.cosmosClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	address, err := tr.cosmosAddress(cfg, params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	balances, err := tr.cosmosClient.GetBalances(ctx, params.Chain, address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(balances) == 0 {
+		return ToolOutput{Text: fmt.Sprintf("No balances for %s on %s.", address, cfg.Name)}, nil
+	}
+
+	lines := []string{fmt.Sprintf("Balances for %s on %s:", address, cfg.Name)}
+	table := &UITable{
+		Title:   fmt.Sprintf("Balances (%s)", cfg.Name),
+		Headers: []string{"Asset", "Amount", "Denom"},
+		Rows:    make([][]string, 0, len(balances)),
+	}
+	for _, b := range balances {
+		asset, amount := describeCosmosBalance(cfg, b)
+		lines = append(lines, fmt.Sprintf("- %s: %s (%s)", asset, amount, b.Denom))
+		table.Rows = append(table.Rows, []string{asset, amount, b.Denom})
+	}
+	return ToolOutput{Text: strings.Join(lines, "\n"), Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+// describeCosmosBalance names a balance's asset and formats its amount. Only
+// the native denom's decimals are known; other denoms are shown in base
+// units, with IBC vouchers labelled by their origin.
+func describeCosmosBalance(cfg *cosmos.ChainConfig, b cosmos.Balance) (string, string) {
+	switch {
+	case b.Denom == cfg.Denom:
+		return cfg.DisplayDenom, chain.FormatBalance(b.Amount, cfg.Decimals)
+	case b.Trace != nil:
+		return fmt.Sprintf("%s via %s", b.Trace.BaseDenom, b.Trace.Path), b.Amount.String()
+	default:
+		return b.Denom, b.Amount.String()
+	}
+}
+
+func (tr *ToolRegistry) handleListCosmosWallets(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ks, err := tr.cosmosKeystore()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(keys) == 0 {
+		return ToolOutput{Text: "No Cosmos wallets found. Use 'clifi wallet cosmos create' to create one."}, nil
+	}
+
+	chains := tr.cosmosClient.ListChains()
+	table := &UITable{
+		Title:   fmt.Sprintf("Cosmos wallets (%d)", len(keys)),
+		Headers: append([]string{"#"}, chains...),
+		Rows:    make([][]string, 0, len(keys)),
+	}
+	var results []string
+	for i, k := range keys {
+		row := []string{fmt.Sprintf("%d", i+1)}
+		var addrs []string
+		for _, name := range chains {
+			cfg, err := tr.cosmosClient.GetChainConfig(name)
+			if err != nil {
+				continue
+			}
+			addr := cfg.Address(k.Address)
+			row = append(row, addr)
+			addrs = append(addrs, fmt.Sprintf("%s: %s", name, addr))
+		}
+		table.Rows = append(table.Rows, row)
+		results = append(results, fmt.Sprintf("%d. %s", i+1, strings.Join(addrs, ", ")))
+	}
+	text := fmt.Sprintf("Found %d Cosmos wallet(s):\n%s", len(keys), strings.Join(results, "\n"))
+	return ToolOutput{Text: text, Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+type sendCosmosInput struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Chain    string `json:"chain"`
+	Amount   string `json:"amount"`
+	Denom    string `json:"denom"`
+	Decimals *int   `json:"decimals"`
+	Memo     string `json:"memo"`
+	Password string `json:"password"`
+	Confirm  bool   `json:"confirm"`
+	Wait     *bool  `json:"wait"`
+}
+
+func (tr *ToolRegistry) handleSendCosmos(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var params sendCosmosInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	cfg, err := tr.cosmosClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if _, err := cfg.ParseAddress(params.To); err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid recipient: %w", err)
+	}
+	if params.Amount == "" {
+		return ToolOutput{}, fmt.Errorf("amount is required")
+	}
+
+	// The native denom may be given by its display name ("ATOM"). Other
+	// denoms need explicit decimals: guessing would risk sending 10^12 times
+	// too much of an 18-decimal asset.
+	denom, decimals := cfg.Denom, int(cfg.Decimals)
+	if params.Denom != "" && !strings.EqualFold(params.Denom, cfg.DisplayDenom) && params.Denom != cfg.Denom {
+		if params.Decimals == nil {
+			return ToolOutput{}, fmt.Errorf("decimals is required when sending %s", params.Denom)
+		}
+		if *params.Decimals < 0 || *params.Decimals > 36 {
+			return ToolOutput{}, fmt.Errorf("invalid decimals: %d", *params.Decimals)
+		}
+		denom, decimals = params.Denom, *params.Decimals
+	}
+	amount, err := decimalToWei(params.Amount, decimals)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid amount: %w", err)
+	}
+	if amount.Sign() <= 0 {
+		return ToolOutput{}, fmt.Errorf("amount must be greater than zero")
+	}
+
+	key, err := tr.cosmosKey(cfg, params.From)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	from := cfg.Address(key.Address)
+
+	intent := tx.CosmosIntent{Chain: params.Chain, From: from, To: params.To, Denom: denom, Amount: amount}
+	policy := loadPolicy()
+	if err := tx.ValidateCosmos(intent, policy); err != nil {
+		return ToolOutput{}, err
+	}
+	recipientCheck, err := tr.screenRecipient(ctx, "Recipient", params.To, policy)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	acct, err := tr.cosmosClient.GetAccount(ctx, params.Chain, from)
+	if errors.Is(err, cosmos.ErrNotFound) {
+		return ToolOutput{}, fmt.Errorf("account %s does not exist on %s yet; fund it before sending", from, cfg.Name)
+	}
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	sendParams := cosmos.SendParams{
+		ChainID:       cfg.ChainID,
+		AccountNumber: acct.AccountNumber,
+		Sequence:      acct.Sequence,
+		PubKey:        key.PubKey,
+		From:          from,
+		To:            params.To,
+		Amount:        cosmos.Coin{Denom: denom, Amount: amount},
+		Fee:           cosmos.Coin{Denom: cfg.Denom},
+		Memo:          params.Memo,
+	}
+	unsigned, err := cosmos.NewSendTx(sendParams)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	gasUsed, err := tr.cosmosClient.Simulate(ctx, params.Chain, unsigned.Raw(nil))
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("simulation failed: %w", err)
+	}
+	sendParams.GasLimit = uint64(float64(gasUsed) * cosmosGasAdjustment)
+	if sendParams.Fee.Amount, err = cosmos.FeeFor(sendParams.GasLimit, cfg.GasPrice); err != nil {
+		return ToolOutput{}, err
+	}
+	if unsigned, err = cosmos.NewSendTx(sendParams); err != nil {
+		return ToolOutput{}, err
+	}
+
+	// The fee is always paid in the native denom, so only a native send
+	// needs amount and fee to fit in the same balance.
+	needNative := new(big.Int).Set(sendParams.Fee.Amount)
+	if denom == cfg.Denom {
+		needNative.Add(needNative, amount)
+	}
+	nativeBal, err := tr.cosmosClient.GetBalance(ctx, params.Chain, from, cfg.Denom)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to read sender balance: %w", err)
+	}
+	if nativeBal.Cmp(needNative) < 0 {
+		return ToolOutput{}, fmt.Errorf("insufficient %s: have %s, need %s including fee", cfg.DisplayDenom,
+			chain.FormatBalance(nativeBal, cfg.Decimals), chain.FormatBalance(needNative, cfg.Decimals))
+	}
+
+	if denom != cfg.Denom {
+		bal, err := tr.cosmosClient.GetBalance(ctx, params.Chain, from, denom)
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("failed to read sender balance: %w", err)
+		}
+		if bal.Cmp(amount) < 0 {
+			return ToolOutput{}, fmt.Errorf("insufficient %s: have %s, need %s (base units)", denom, bal, amount)
+		}
+	}
+
+	amountStr := chain.FormatBalance(amount, cfg.Decimals) + " " + cfg.DisplayDenom
+	if denom != cfg.Denom {
+		amountStr = params.Amount + " " + denom
+	}
+	summary := fmt.Sprintf("Preview:\n- Chain: %s (%s)\n- From: %s\n- To: %s\n- Amount: %s\n- Gas limit: %d\n- Fee: %s %s\n",
+		cfg.Name,
+		cfg.ChainID,
+		from,
+		params.To,
+		amountStr,
+		sendParams.GasLimit,
+		chain.FormatBalance(sendParams.Fee.Amount, cfg.Decimals),
+		cfg.DisplayDenom,
+	)
+	if params.Memo != "" {
+		summary += fmt.Sprintf("- Memo: %s\n", params.Memo)
+	}
+	summary += recipientCheck.previewText()
+
+	if !params.Confirm {
+		if params.Password == "" {
+			return ToolOutput{Text: summary + "\nSet confirm=true and provide password to sign and broadcast."}, nil
+		}
+		return ToolOutput{Text: summary + "\nSet confirm=true to sign and broadcast."}, nil
+	}
+	if params.Password == "" {
+		return ToolOutput{}, fmt.Errorf("password required to sign")
+	}
+
+	ks, err := tr.cosmosKeystore()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	signer, err := ks.Signer(key.Address, params.Password)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to unlock signer: %w", err)
+	}
+	defer signer.Lock()
+
+	sig, err := signer.Sign(unsigned)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	res, err := tr.cosmosClient.Broadcast(ctx, params.Chain, unsigned.Raw(sig))
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to send tx: %w", err)
+	}
+
+	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, res.TxHash)
+	if url := cfg.TxURL(res.TxHash); url != "" {
+		result += "\nExplorer: " + url
+	}
+	if params.Wait == nil || *params.Wait {
+		waitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 90*time.Second)
+		defer cancel()
+		included, err := tr.cosmosClient.WaitForTx(waitCtx, params.Chain, res.TxHash, 2*time.Second)
+		if err != nil {
+			result += "\n" + err.Error()
+		} else {
+			result += "\nIncluded at height " + included.Height
+		}
+	}
+
+	return ToolOutput{
+		Text: result,
+		Blocks: []UIBlock{kvBlock("Cosmos send",
+			KVItem{Key: "Chain", Value: cfg.Name},
+			KVItem{Key: "From", Value: from},
+			KVItem{Key: "To", Value: params.To},
+			KVItem{Key: "Amount", Value: amountStr},
+			KVItem{Key: "Tx", Value: res.TxHash, URL: cfg.TxURL(res.TxHash)},
+		)},
+	}, nil
+}
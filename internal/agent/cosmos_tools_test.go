@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/cosmos"
+)
+
+func TestToolRegistry_CosmosValidation(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+
+	osmo, err := cosmos.EncodeBech32("osmo", make([]byte, 20))
+	require.NoError(t, err)
+	hub, err := cosmos.EncodeBech32("cosmos", make([]byte, 20))
+	require.NoError(t, err)
+
+	t.Run("rejects address from another chain", func(t *testing.T) {
+		input := json.RawMessage(`{"chain": "cosmoshub", "to": "` + osmo + `", "amount": "1"}`)
+		_, err := tr.ExecuteTool(context.Background(), "send_cosmos", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "expected prefix")
+	})
+
+	t.Run("requires decimals for non-native denoms", func(t *testing.T) {
+		input := json.RawMessage(`{"chain": "cosmoshub", "to": "` + hub + `", "amount": "1", "denom": "ibc/ABC"}`)
+		_, err := tr.ExecuteTool(context.Background(), "send_cosmos", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decimals is required")
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		_, err := tr.ExecuteTool(context.Background(), "get_cosmos_balances", json.RawMessage(`{"chain": "juno"}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown cosmos chain")
+	})
+
+	t.Run("balances need a wallet or address", func(t *testing.T) {
+		_, err := tr.ExecuteTool(context.Background(), "get_cosmos_balances", json.RawMessage(`{"chain": "osmosis"}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no cosmos wallets")
+	})
+}
+
+func TestDescribeCosmosBalance(t *testing.T) {
+	cfg := cosmos.DefaultChains()["osmosis"]
+
+	asset, amount := describeCosmosBalance(cfg, cosmos.Balance{Denom: "uosmo", Amount: big.NewInt(1500000)})
+	assert.Equal(t, "OSMO", asset)
+	assert.Equal(t, "1.500000", amount)
+
+	asset, amount = describeCosmosBalance(cfg, cosmos.Balance{
+		Denom:  "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2",
+		Amount: big.NewInt(42),
+		Trace:  &cosmos.DenomTrace{Path: "transfer/channel-0", BaseDenom: "uatom"},
+	})
+	assert.Equal(t, "uatom via transfer/channel-0", asset)
+	assert.Equal(t, "42", amount)
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/audit"
+	"github.com/yolodolo42/clifi/internal/dca"
+)
+
+type createDCAInput struct {
+	Plan        string  `json:"plan"`
+	Chain       string  `json:"chain"`
+	Wallet      string  `json:"wallet"`
+	SlippageBps *uint32 `json:"slippage_bps"`
+	Confirm     bool    `json:"confirm"`
+}
+
+func (tr *ToolRegistry) handleCreateDCA(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params createDCAInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("recurring swaps need a data directory")
+	}
+
+	plan, err := dca.ParsePlan(params.Plan)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	chainName := strings.ToLower(strings.TrimSpace(params.Chain))
+	switch {
+	case plan.Chain == "":
+		plan.Chain = chainName
+	case chainName != "" && chainName != plan.Chain:
+		return ToolOutput{}, fmt.Errorf("plan says %s but chain is %s", plan.Chain, chainName)
+	}
+	if plan.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	plan.SlippageBps = params.SlippageBps
+
+	req := SwapRequest{
+		Chain: plan.Chain, From: params.Wallet, FromToken: plan.FromToken, ToToken: plan.ToToken,
+		Amount: plan.Amount, SlippageBps: plan.SlippageBps,
+	}
+	sp, err := tr.resolveSwap(req, loadPolicy())
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	plan.Wallet = sp.from.Hex()
+
+	if !params.Confirm {
+		return ToolOutput{Text: fmt.Sprintf(
+			"Preview recurring swap:\n- Plan: %s\n- Wallet: %s\n- First run: at the next `clifi serve` check\n\nEach run swaps unattended under the policy limits. Set confirm=true to create it.",
+			plan, plan.Wallet)}, nil
+	}
+	plan, err = dca.NewStore(tr.dataDir).Add(plan)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	return ToolOutput{Text: fmt.Sprintf(
+		"Created DCA #%d: %s from %s. It runs while `clifi serve` is running (with the wallet password); every run is logged to %s. `clifi dca list` shows all plans.",
+		plan.ID, plan, plan.Wallet, audit.File)}, nil
+}
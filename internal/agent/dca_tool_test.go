@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/dca"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+func TestCreateDCA(t *testing.T) {
+	t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+	t.Setenv("CLIFI_MAX_SLIPPAGE_BPS", "")
+	dir := t.TempDir()
+	km, err := wallet.NewKeystoreManager(dir)
+	require.NoError(t, err)
+	acc, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "create_dca", json.RawMessage(`{"plan":"swap 50 USDC to ETH weekly","chain":"base"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Preview recurring swap")
+	plans, err := dca.NewStore(dir).List()
+	require.NoError(t, err)
+	assert.Empty(t, plans, "preview creates nothing")
+
+	out, err = tr.ExecuteTool(ctx, "create_dca", json.RawMessage(`{"plan":"swap 50 USDC to ETH weekly","chain":"base","confirm":true}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Created DCA #1: swap 50 USDC to ETH on base weekly")
+	plans, err = dca.NewStore(dir).List()
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, acc.Address.Hex(), plans[0].Wallet)
+
+	for name, input := range map[string]string{
+		"testnet":       `{"plan":"swap 1 ETH to USDC daily","chain":"sepolia","confirm":true}`,
+		"chain clash":   `{"plan":"swap 1 ETH to USDC on arbitrum daily","chain":"base","confirm":true}`,
+		"bad schedule":  `{"plan":"swap 1 ETH to USDC yearly","chain":"base","confirm":true}`,
+		"unknown token": `{"plan":"swap 1 ETH to ZZZ daily","chain":"base","confirm":true}`,
+	} {
+		_, err := tr.ExecuteTool(ctx, "create_dca", json.RawMessage(input))
+		assert.Error(t, err, name)
+	}
+}
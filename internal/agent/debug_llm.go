@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// llmDebugLog keeps the provider requests and responses of the current
+// turn and writes them to <data dir>/debug/<session>/turn-NNN.json, so
+// provider-specific tool calling bugs can be compared against the exact
+// payloads. The file is rewritten after every request so a crash mid-turn
+// still leaves the requests that led up to it.
+type llmDebugLog struct {
+	dataDir   string
+	sessionID string
+	turn      int
+	exchanges []llmExchange
+}
+
+// llmExchange is one provider round trip. Tool inputs are redacted the
+// same way as in the session log.
+type llmExchange struct {
+	TS          string            `json:"ts"`
+	Call        string            `json:"call"`
+	Provider    llm.ProviderID    `json:"provider"`
+	Model       string            `json:"model"`
+	DurationMS  int64             `json:"duration_ms"`
+	Request     *llm.ChatRequest  `json:"request"`
+	ToolCalls   []llm.ToolCall    `json:"tool_calls,omitempty"`
+	ToolResults []llm.ToolResult  `json:"tool_results,omitempty"`
+	Response    *llm.ChatResponse `json:"response,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// startTurn begins a new turn file. Turns are numbered per session.
+func (d *llmDebugLog) startTurn(sessionID string) {
+	if sessionID != d.sessionID {
+		d.sessionID = sessionID
+		d.turn = 0
+	}
+	d.turn++
+	d.exchanges = nil
+}
+
+func (d *llmDebugLog) path() string {
+	return filepath.Join(d.dataDir, "debug", d.sessionID, fmt.Sprintf("turn-%03d.json", d.turn))
+}
+
+func (d *llmDebugLog) record(e llmExchange) error {
+	d.exchanges = append(d.exchanges, sanitizeExchange(e))
+
+	b, err := json.MarshalIndent(d.exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := d.path()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o600)
+}
+
+// sanitizeExchange copies e with every tool input redacted. The request is
+// copied too: the agent keeps using it after the exchange is recorded.
+func sanitizeExchange(e llmExchange) llmExchange {
+	if e.Request != nil {
+		req := *e.Request
+		req.Messages = append([]llm.Message(nil), req.Messages...)
+		e.Request = &req
+	}
+	e.ToolCalls = redactToolCalls(e.ToolCalls)
+	if e.Response != nil {
+		resp := *e.Response
+		resp.ToolCalls = redactToolCalls(resp.ToolCalls)
+		e.Response = &resp
+	}
+	return e
+}
+
+func redactToolCalls(calls []llm.ToolCall) []llm.ToolCall {
+	if calls == nil {
+		return nil
+	}
+	out := make([]llm.ToolCall, len(calls))
+	for i, tc := range calls {
+		out[i] = tc
+		if redacted := RedactJSONArgs(string(tc.Input)); json.Valid([]byte(redacted)) {
+			out[i].Input = json.RawMessage(redacted)
+		}
+	}
+	return out
+}
+
+// SetDebugLLM turns the per-turn request/response dump on or off. It
+// applies from the next message.
+func (a *Agent) SetDebugLLM(on bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.debugLLM = on
+}
+
+// DebugLLMDir is where SetDebugLLM writes its files.
+func (a *Agent) DebugLLMDir() string {
+	return filepath.Join(a.dataDir, "debug")
+}
+
+// startDebugTurn begins the session's next turn file while the dump is
+// on. Callers hold mu.
+func (s *Session) startDebugTurn() {
+	if !s.cur.debugLLM {
+		s.debug = nil
+		return
+	}
+	if s.debug == nil {
+		s.debug = &llmDebugLog{dataDir: s.agent.dataDir}
+	}
+	s.debug.startTurn(s.sessionID)
+}
+
+func (s *Session) debugExchange(call string, start time.Time, req *llm.ChatRequest, toolCalls []llm.ToolCall, toolResults []llm.ToolResult, resp *llm.ChatResponse, err error) {
+	if s.debug == nil {
+		return
+	}
+	e := llmExchange{
+		TS:          nowTS(),
+		Call:        call,
+		Provider:    s.cur.provider.ID(),
+		Model:       s.cur.model,
+		DurationMS:  time.Since(start).Milliseconds(),
+		Request:     req,
+		ToolCalls:   toolCalls,
+		ToolResults: toolResults,
+		Response:    resp,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if err := s.debug.record(e); err != nil {
+		slog.Warn("writing llm debug file failed", "err", err)
+	}
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestAgent_DebugLLMWritesTurnFiles(t *testing.T) {
+	ag := newTestAgent()
+	ag.dataDir = t.TempDir()
+	ag.SetDebugLLM(true)
+	t.Cleanup(ag.Close)
+
+	_, err := ag.ChatWithEvents(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = ag.ChatWithEvents(context.Background(), "second")
+	require.NoError(t, err)
+
+	dir := filepath.Join(ag.DebugLLMDir(), ag.main.sessionID)
+	for turn, msg := range map[string]string{"turn-001.json": "first", "turn-002.json": "second"} {
+		path := filepath.Join(dir, turn)
+		st, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), st.Mode().Perm())
+
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var exchanges []llmExchange
+		require.NoError(t, json.Unmarshal(b, &exchanges))
+		require.Len(t, exchanges, 1)
+		assert.Equal(t, "chat", exchanges[0].Call)
+		assert.Equal(t, "test-model-a", exchanges[0].Model)
+		msgs := exchanges[0].Request.Messages
+		assert.Equal(t, msg, msgs[len(msgs)-1].Content)
+		assert.Equal(t, "ok", exchanges[0].Response.Content)
+	}
+}
+
+func TestAgent_DebugLLMOffWritesNothing(t *testing.T) {
+	ag := newTestAgent()
+	ag.dataDir = t.TempDir()
+	t.Cleanup(ag.Close)
+
+	_, err := ag.ChatWithEvents(context.Background(), "hi")
+	require.NoError(t, err)
+	_, err = os.Stat(ag.DebugLLMDir())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLLMDebugLog_RedactsToolInputs(t *testing.T) {
+	d := &llmDebugLog{dataDir: t.TempDir()}
+	d.startTurn("s1")
+
+	call := llm.ToolCall{ID: "1", Name: "send_native", Input: json.RawMessage(`{"to":"0xabc","password":"hunter2"}`)}
+	resp := &llm.ChatResponse{ToolCalls: []llm.ToolCall{call}}
+	require.NoError(t, d.record(llmExchange{
+		Call:      "chat_with_tool_results",
+		Request:   &llm.ChatRequest{},
+		ToolCalls: []llm.ToolCall{call},
+		Response:  resp,
+	}))
+
+	b, err := os.ReadFile(d.path())
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "hunter2")
+	assert.Contains(t, string(b), "0xabc")
+	// The caller's values are left alone.
+	assert.Contains(t, string(resp.ToolCalls[0].Input), "hunter2")
+}
+
+func TestLLMDebugLog_TurnsRestartPerSession(t *testing.T) {
+	d := &llmDebugLog{dataDir: t.TempDir()}
+	d.startTurn("s1")
+	d.startTurn("s1")
+	assert.Equal(t, 2, d.turn)
+	d.startTurn("s2")
+	assert.Equal(t, 1, d.turn)
+	assert.Equal(t, filepath.Join(d.dataDir, "debug", "s2", "turn-001.json"), d.path())
+}
@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+// DecodeRequest is calldata to decode, with any signature the caller
+// already knows.
+type DecodeRequest struct {
+	Data string
+	// Signature is a text signature such as "transfer(address,uint256)";
+	// when set, only it is used.
+	Signature string
+	// ABI is a contract's JSON ABI, tried before the built-in signatures.
+	ABI string
+	// Offline skips the 4byte.directory lookup of unknown selectors.
+	Offline bool
+}
+
+// DecodeCalldata decodes calldata without any chain connection: with the
+// given signature or ABI, the built-in signatures and, unless offline,
+// 4byte.directory.
+func DecodeCalldata(ctx context.Context, req DecodeRequest) (*decode.Resolution, error) {
+	data, err := decode.ParseHex(req.Data)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := decode.Selector(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Signature != "" {
+		want, err := decode.FunctionSelector(req.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if want != sel {
+			return nil, fmt.Errorf("%s has selector %s but the calldata starts with %s", req.Signature, hexutil.Encode(want[:]), hexutil.Encode(sel[:]))
+		}
+		d := decode.Empty()
+		if err := d.AddSignature(req.Signature, "given signature"); err != nil {
+			return nil, err
+		}
+		return d.Resolve(ctx, data, nil)
+	}
+
+	d := decode.New()
+	if req.ABI != "" {
+		if err := d.AddABI(req.ABI); err != nil {
+			return nil, err
+		}
+	}
+	var db *decode.SignatureDB
+	if !req.Offline {
+		db = decode.NewSignatureDB()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	r, err := d.Resolve(ctx, data, db)
+	if errors.Is(err, decode.ErrUnknownSelector) {
+		return nil, fmt.Errorf("decode %s: %w; the contract's signature or ABI would decode it", hexutil.Encode(sel[:]), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", hexutil.Encode(sel[:]), err)
+	}
+	return r, nil
+}
+
+type decodeCalldataInput struct {
+	Data      string `json:"data"`
+	Signature string `json:"signature"`
+	ABI       string `json:"abi"`
+	Offline   bool   `json:"offline"`
+}
+
+func (tr *ToolRegistry) handleDecodeCalldata(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params decodeCalldataInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Data == "" {
+		return ToolOutput{}, fmt.Errorf("data is required")
+	}
+	r, err := DecodeCalldata(ctx, DecodeRequest{Data: params.Data, Signature: params.Signature, ABI: params.ABI, Offline: params.Offline})
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	items := []KVItem{
+		{Key: "Function", Value: r.Signature},
+		{Key: "Selector", Value: r.Selector},
+		{Key: "Source", Value: r.Source},
+	}
+	for i, a := range r.Args {
+		name := a.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		items = append(items, KVItem{Key: name + " (" + a.Type + ")", Value: a.Value})
+	}
+	return ToolOutput{Text: r.Text(), Blocks: []UIBlock{kvBlock("Decoded calldata", items...)}}, nil
+}
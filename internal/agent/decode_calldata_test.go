@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+func TestDecodeCalldata(t *testing.T) {
+	ctx := context.Background()
+	const transfer = "0xa9059cbb" +
+		"00000000000000000000000000000000000000000000000000000000000000bb" +
+		"00000000000000000000000000000000000000000000000000000000000f4240"
+
+	r, err := DecodeCalldata(ctx, DecodeRequest{Data: transfer, Offline: true})
+	require.NoError(t, err)
+	assert.Equal(t, decode.SourceBuiltin, r.Source)
+	assert.Equal(t, "1000000", r.Args[1].Value)
+
+	// A given signature names the arguments its own way.
+	r, err = DecodeCalldata(ctx, DecodeRequest{Data: transfer, Signature: "transfer(address dst, uint wad)"})
+	require.NoError(t, err)
+	assert.Equal(t, "given signature", r.Source)
+	assert.Equal(t, "wad", r.Args[1].Name)
+
+	_, err = DecodeCalldata(ctx, DecodeRequest{Data: transfer, Signature: "approve(address,uint256)"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has selector 0x095ea7b3 but the calldata starts with 0xa9059cbb")
+
+	r, err = DecodeCalldata(ctx, DecodeRequest{Data: transfer, ABI: `[{"type":"function","name":"transfer","inputs":[{"name":"recipient","type":"address"},{"name":"value","type":"uint256"}]}]`, Offline: true})
+	require.NoError(t, err)
+	assert.Equal(t, "recipient", r.Args[0].Name)
+
+	_, err = DecodeCalldata(ctx, DecodeRequest{Data: "0x12345678", Offline: true})
+	assert.True(t, errors.Is(err, decode.ErrUnknownSelector))
+}
@@ -0,0 +1,226 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+type explainTxInput struct {
+	Chain  string `json:"chain"`
+	TxHash string `json:"tx_hash"`
+}
+
+// maxExplainContracts caps ABI lookups per transaction: a busy swap can
+// touch dozens of contracts, each an explorer request.
+const maxExplainContracts = 8
+
+// handleExplainTx decodes any transaction: its call and its event logs,
+// using the contracts' published ABIs where available and the built-in
+// signatures otherwise. Missing ABIs only leave parts undecoded.
+func (tr *ToolRegistry) handleExplainTx(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+
+	var params explainTxInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	cfg, err := tr.chainClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+	txHash, err := parseTxHash(params.TxHash)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	txn, pending, err := tr.chainClient.TransactionByHash(ctx, params.Chain, txHash)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("transaction %s not found on %s: %w", txHash.Hex(), params.Chain, err)
+	}
+	var receipt *types.Receipt
+	if !pending {
+		if receipt, err = tr.chainClient.GetTransactionReceipt(ctx, params.Chain, txHash); err != nil {
+			return ToolOutput{}, fmt.Errorf("receipt for %s: %w", txHash.Hex(), err)
+		}
+	}
+
+	var contracts []common.Address
+	if txn.To() != nil && len(txn.Data()) > 0 {
+		contracts = append(contracts, *txn.To())
+	}
+	if receipt != nil {
+		for _, l := range receipt.Logs {
+			contracts = append(contracts, l.Address)
+		}
+	}
+	dec := decode.New()
+	notes := tr.loadABIs(ctx, params.Chain, cfg, dec, contracts)
+
+	native := cfg.NativeCurrency
+	status := "pending"
+	if receipt != nil {
+		status = "success"
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = "reverted"
+		}
+	}
+	to := "contract creation"
+	if txn.To() != nil {
+		to = txn.To().Hex()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Transaction %s on %s:\n- Status: %s\n", txHash.Hex(), params.Chain, status)
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		tr.txItem(params.Chain, txHash.Hex()),
+		{Key: "Status", Value: status},
+	}
+	if receipt != nil {
+		fmt.Fprintf(&b, "- Block: %s\n", receipt.BlockNumber)
+		items = append(items, KVItem{Key: "Block", Value: receipt.BlockNumber.String()})
+	}
+	if from, err := types.Sender(types.LatestSignerForChainID(txn.ChainId()), txn); err == nil {
+		fmt.Fprintf(&b, "- From: %s\n", from.Hex())
+		items = append(items, KVItem{Key: "From", Value: from.Hex()})
+	}
+	value := weiToEth(txn.Value()) + " " + native
+	fmt.Fprintf(&b, "- To: %s\n- Value: %s\n", to, value)
+	items = append(items, KVItem{Key: "To", Value: to}, KVItem{Key: "Value", Value: value})
+	if receipt != nil && receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(receipt.EffectiveGasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+		feeText := fmt.Sprintf("%s %s (%d gas at %s gwei)", weiToEth(fee), native, receipt.GasUsed, weiToGwei(receipt.EffectiveGasPrice))
+		fmt.Fprintf(&b, "- Fee: %s\n", feeText)
+		items = append(items, KVItem{Key: "Fee", Value: feeText})
+	}
+
+	callText := describeCall(dec, txn)
+	b.WriteString(callText)
+	items = append(items, KVItem{Key: "Call", Value: strings.TrimPrefix(strings.SplitN(callText, "\n", 2)[0], "- Call: ")})
+
+	blocks := []UIBlock{kvBlock("Transaction", items...)}
+	if receipt != nil && len(receipt.Logs) > 0 {
+		table := &UITable{
+			Title:   fmt.Sprintf("Events (%d)", len(receipt.Logs)),
+			Headers: []string{"#", "Contract", "Event"},
+		}
+		fmt.Fprintf(&b, "- Events (%d):\n", len(receipt.Logs))
+		for i, l := range receipt.Logs {
+			line := tr.describeLog(ctx, params.Chain, dec, l)
+			fmt.Fprintf(&b, "  %d. %s %s\n", i+1, l.Address.Hex(), line)
+			table.Rows = append(table.Rows, []string{strconv.Itoa(i + 1), l.Address.Hex(), line})
+		}
+		blocks = append(blocks, UIBlock{Kind: UIBlockTable, Table: table})
+	}
+	for _, n := range notes {
+		fmt.Fprintf(&b, "- Note: %s\n", n)
+	}
+	if url := tr.txURL(params.Chain, txHash.Hex()); url != "" {
+		b.WriteString("Explorer: " + url + "\n")
+	}
+	return ToolOutput{Text: b.String(), Blocks: blocks}, nil
+}
+
+// loadABIs adds the published ABIs of contracts, and of the
+// implementations behind any EIP-1967 proxies among them, to dec. It
+// returns notes on what could not be fetched; once the explorer fails the
+// rest are skipped rather than each timing out.
+func (tr *ToolRegistry) loadABIs(ctx context.Context, chainName string, cfg *chain.ChainConfig, dec *decode.Decoder, contracts []common.Address) []string {
+	var notes []string
+	seen := make(map[common.Address]bool)
+	for _, addr := range contracts {
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		if len(seen) > maxExplainContracts {
+			notes = append(notes, fmt.Sprintf("ABIs were looked up for the first %d contracts only", maxExplainContracts))
+			break
+		}
+
+		targets := []common.Address{addr}
+		// A proxy's own ABI rarely has the functions being called.
+		if slot, err := tr.chainClient.StorageAt(ctx, chainName, addr, chain.EIP1967ImplementationSlot, nil); err == nil {
+			if impl := common.BytesToAddress(slot); impl != (common.Address{}) {
+				targets = append(targets, impl)
+			}
+		}
+		for _, target := range targets {
+			abiJSON, err := tr.verifier.ABI(ctx, cfg.ChainIDInt, target)
+			if errors.Is(err, chain.ErrNoABI) {
+				continue
+			}
+			if err != nil {
+				return append(notes, fmt.Sprintf("ABI lookup failed (%v); only built-in signatures were used", err))
+			}
+			if err := dec.AddABI(abiJSON); err != nil {
+				notes = append(notes, fmt.Sprintf("ABI of %s: %v", target.Hex(), err))
+			}
+		}
+	}
+	return notes
+}
+
+// describeCall renders the decoded calldata, one argument per line.
+func describeCall(dec *decode.Decoder, txn *types.Transaction) string {
+	data := txn.Data()
+	switch {
+	case txn.To() == nil:
+		return fmt.Sprintf("- Call: contract deployment (%d bytes of init code)\n", len(data))
+	case len(data) == 0:
+		return "- Call: none (plain value transfer)\n"
+	}
+	c, err := dec.Call(data)
+	if errors.Is(err, decode.ErrUnknownSelector) {
+		return fmt.Sprintf("- Call: %v (no ABI or known signature)\n", err)
+	}
+	if err != nil {
+		return fmt.Sprintf("- Call: undecodable (%v)\n", err)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Call: %s via %s\n", c.Signature, c.Source)
+	for _, a := range c.Args {
+		name := a.Name
+		if name == "" {
+			name = "arg"
+		}
+		fmt.Fprintf(&b, "  - %s (%s): %s\n", name, a.Type, a.Value)
+	}
+	return b.String()
+}
+
+// describeLog renders one event. ERC-20 amounts also get the token's
+// units, since raw integers are easy to misread by 10^decimals.
+func (tr *ToolRegistry) describeLog(ctx context.Context, chainName string, dec *decode.Decoder, l *types.Log) string {
+	e, err := dec.Log(l)
+	if err != nil {
+		if errors.Is(err, decode.ErrUnknownEvent) && len(l.Topics) > 0 {
+			return "unknown event " + l.Topics[0].Hex()
+		}
+		return "undecodable event (" + err.Error() + ")"
+	}
+	text := e.String()
+	if (e.Name == "Transfer" || e.Name == "Approval") && len(l.Topics) == 3 {
+		if v, ok := new(big.Int).SetString(e.Arg("value"), 10); ok {
+			if symbol, decimals, err := tr.chainClient.GetTokenSymbolDecimals(ctx, chainName, l.Address); err == nil && symbol != "" {
+				text += fmt.Sprintf(" = %s %s", chain.FormatBalance(v, decimals), symbol)
+			}
+		}
+	}
+	return text
+}
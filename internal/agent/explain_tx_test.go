@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+func TestDescribeCall(t *testing.T) {
+	dec := decode.New()
+	token := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	spender := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	txWith := func(to *common.Address, data []byte) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{To: to, Value: big.NewInt(0), Data: data})
+	}
+
+	data, err := buildERC20ApproveData(spender, big.NewInt(5_000_000))
+	require.NoError(t, err)
+	assert.Equal(t, "- Call: approve(address,uint256) via built-in\n"+
+		"  - spender (address): "+spender.Hex()+"\n"+
+		"  - amount (uint256): 5000000\n", describeCall(dec, txWith(&token, data)))
+
+	assert.Equal(t, "- Call: unknown function selector 0x12345678 (no ABI or known signature)\n",
+		describeCall(dec, txWith(&token, common.FromHex("0x12345678"))))
+	assert.Equal(t, "- Call: none (plain value transfer)\n", describeCall(dec, txWith(&token, nil)))
+	assert.Equal(t, "- Call: contract deployment (3 bytes of init code)\n", describeCall(dec, txWith(nil, []byte{1, 2, 3})))
+}
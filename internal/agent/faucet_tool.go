@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/faucet"
+)
+
+type requestFaucetInput struct {
+	Chain      string `json:"chain"`
+	Address    string `json:"address"`
+	Wait       *bool  `json:"wait"`
+	TimeoutSec int    `json:"timeout_sec"`
+}
+
+func (tr *ToolRegistry) handleRequestFaucet(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params requestFaucetInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+
+	addr, cfg, err := tr.prepareTxFrom(params.Chain, params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if !cfg.IsTestnet {
+		return ToolOutput{}, fmt.Errorf("faucets are only available on testnets; %s is a mainnet", params.Chain)
+	}
+	km, err := tr.keystore()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if !km.HasAccount(addr) {
+		return ToolOutput{}, fmt.Errorf("address %s is not in the keystore", addr.Hex())
+	}
+
+	baseline, err := tr.chainClient.GetBalance(ctx, params.Chain, addr)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to read starting balance: %w", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	res, err := faucet.Request(reqCtx, &http.Client{}, faucet.Configured(params.Chain), params.Chain, cfg.ChainIDInt, addr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "Address", Value: addr.Hex()},
+		{Key: "Faucet", Value: res.Faucet},
+	}
+	text := fmt.Sprintf("Faucet request accepted by %s for %s on %s.", res.Faucet, addr.Hex(), params.Chain)
+	if res.TxHash != "" {
+		items = append(items, tr.txItem(params.Chain, res.TxHash))
+		text += "\nFunding tx: " + res.TxHash
+	}
+
+	if params.Wait == nil || *params.Wait {
+		bal, err := tr.waitForFaucetFunds(ctx, params.Chain, addr, baseline, params.TimeoutSec)
+		if err != nil {
+			text += "\n" + err.Error() + " (the faucet may still deliver later)"
+		} else {
+			formatted := chain.FormatBalance(bal, 18) + " " + cfg.NativeCurrency
+			text += "\nFunds arrived. New balance: " + formatted
+			items = append(items, KVItem{Key: "Balance", Value: formatted})
+		}
+	}
+
+	return ToolOutput{Text: text, Blocks: []UIBlock{kvBlock("Faucet", items...)}}, nil
+}
+
+// waitForFaucetFunds polls until addr's balance exceeds baseline or the
+// timeout (default 120s, clamped to 5-600s) elapses.
+func (tr *ToolRegistry) waitForFaucetFunds(ctx context.Context, chainName string, addr common.Address, baseline *big.Int, timeoutSec int) (*big.Int, error) {
+	timeout := 120 * time.Second
+	if timeoutSec > 0 {
+		timeoutSec = max(5, min(timeoutSec, 600))
+		timeout = time.Duration(timeoutSec) * time.Second
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return faucet.WaitForFunds(waitCtx, tr.chainClient, chainName, addr, baseline, 3*time.Second)
+}
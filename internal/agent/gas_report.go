@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/gasreport"
+)
+
+// gasReceipt is a stored receipt with its cached gas context, if any.
+type gasReceipt struct {
+	chain, hash, raw string
+	sender           sql.NullString
+	blockTime        sql.NullInt64
+	baseFee          sql.NullString
+	feeUSD           float64
+}
+
+// gasReceipts returns every stored receipt with its gas context and the
+// USD value of its fee from the ledger, oldest first.
+func (s *ReceiptStore) gasReceipts() ([]gasReceipt, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	rows, err := s.db.Query(`
+SELECT r.chain, r.tx_hash, COALESCE(r.raw_json, ''), g.sender, g.block_time, g.base_fee,
+	COALESCE((SELECT SUM(-l.amount * l.price_usd) FROM ledger l
+		WHERE l.chain = r.chain AND l.tx_hash = lower(r.tx_hash) AND l.kind = 'fee'), 0)
+FROM receipts r LEFT JOIN gas_context g ON g.chain = r.chain AND g.tx_hash = r.tx_hash
+ORDER BY r.created_at
+`)
+	if err != nil {
+		return nil, fmt.Errorf("load receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []gasReceipt
+	for rows.Next() {
+		var g gasReceipt
+		if err := rows.Scan(&g.chain, &g.hash, &g.raw, &g.sender, &g.blockTime, &g.baseFee, &g.feeUSD); err != nil {
+			return nil, fmt.Errorf("load receipts: %w", err)
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// putGasContext caches a receipt's sender, block time and base fee; a nil
+// base fee is stored as ”.
+func (s *ReceiptStore) putGasContext(chainName, txHash string, sender common.Address, blockTime time.Time, baseFee *big.Int) error {
+	fee := ""
+	if baseFee != nil {
+		fee = baseFee.String()
+	}
+	_, err := s.db.Exec(`
+INSERT OR REPLACE INTO gas_context (chain, tx_hash, sender, block_time, base_fee)
+VALUES (?, ?, ?, ?, ?)
+`, chainName, txHash, sender.Hex(), blockTime.Unix(), fee)
+	if err != nil {
+		return fmt.Errorf("persist gas context: %w", err)
+	}
+	return nil
+}
+
+// GasReport summarizes the gas paid by senders' transactions among the
+// stored receipts, or by every keystore wallet's when senders is empty.
+// Receipts seen for the first time have their sender and block looked up
+// over RPC and cached; those that cannot be are counted as unresolved.
+func (tr *ToolRegistry) GasReport(ctx context.Context, senders []common.Address, threshold float64) (gasreport.Report, error) {
+	rs, err := tr.receiptStore()
+	if err != nil {
+		return gasreport.Report{}, err
+	}
+	stored, err := rs.gasReceipts()
+	if err != nil {
+		return gasreport.Report{}, err
+	}
+	if len(senders) == 0 {
+		km, err := tr.keystore()
+		if err != nil {
+			return gasreport.Report{}, err
+		}
+		for _, acc := range km.ListAccounts() {
+			senders = append(senders, acc.Address)
+		}
+	}
+	ours := make(map[common.Address]bool, len(senders))
+	for _, a := range senders {
+		ours[a] = true
+	}
+
+	var txs []gasreport.Tx
+	unresolved := 0
+	headers := make(map[string]*types.Header)
+	// After a chain's RPC fails, its other receipts are not tried, so an
+	// unreachable chain costs one timeout rather than one per receipt.
+	down := make(map[string]bool)
+	for _, g := range stored {
+		var receipt struct {
+			GasUsed           hexutil.Uint64 `json:"gasUsed"`
+			EffectiveGasPrice *hexutil.Big   `json:"effectiveGasPrice"`
+			BlockNumber       *hexutil.Big   `json:"blockNumber"`
+		}
+		if err := json.Unmarshal([]byte(g.raw), &receipt); err != nil || receipt.EffectiveGasPrice == nil || receipt.BlockNumber == nil {
+			continue
+		}
+		if !g.sender.Valid {
+			if down[g.chain] {
+				unresolved++
+				continue
+			}
+			if err := tr.resolveGasContext(ctx, rs, &g, receipt.BlockNumber.ToInt(), headers); err != nil {
+				slog.Debug("gas context unavailable", "chain", g.chain, "tx", g.hash, "err", err)
+				down[g.chain] = !errors.Is(err, ethereum.NotFound)
+				unresolved++
+				continue
+			}
+		}
+		if !ours[common.HexToAddress(g.sender.String)] {
+			continue
+		}
+
+		t := gasreport.Tx{
+			Chain:   g.chain,
+			Hash:    g.hash,
+			From:    g.sender.String,
+			At:      time.Unix(g.blockTime.Int64, 0).UTC(),
+			GasUsed: uint64(receipt.GasUsed),
+			Price:   receipt.EffectiveGasPrice.ToInt(),
+			FeeUSD:  g.feeUSD,
+			Symbol:  "ETH",
+		}
+		if fee, ok := new(big.Int).SetString(g.baseFee.String, 10); ok {
+			t.BaseFee = fee
+		}
+		if cfg, err := tr.chainClient.GetChainConfig(g.chain); err == nil {
+			t.Symbol = cfg.NativeCurrency
+		}
+		txs = append(txs, t)
+	}
+
+	report := gasreport.Build(txs, threshold)
+	report.Unresolved = unresolved
+	return report, nil
+}
+
+// resolveGasContext looks up g's sender and block header and caches them.
+// headers memoizes blocks shared by several receipts.
+func (tr *ToolRegistry) resolveGasContext(ctx context.Context, rs *ReceiptStore, g *gasReceipt, block *big.Int, headers map[string]*types.Header) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	tx, _, err := tr.chainClient.TransactionByHash(ctx, g.chain, common.HexToHash(g.hash))
+	if err != nil {
+		return fmt.Errorf("load transaction: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return fmt.Errorf("recover sender: %w", err)
+	}
+	key := g.chain + "/" + block.String()
+	header, ok := headers[key]
+	if !ok {
+		if header, err = tr.chainClient.HeaderByNumber(ctx, g.chain, block); err != nil {
+			return fmt.Errorf("load block %s: %w", block, err)
+		}
+		headers[key] = header
+	}
+
+	at := time.Unix(int64(header.Time), 0)
+	if err := rs.putGasContext(g.chain, g.hash, from, at, header.BaseFee); err != nil {
+		return err
+	}
+	g.sender = sql.NullString{String: from.Hex(), Valid: true}
+	g.blockTime = sql.NullInt64{Int64: at.Unix(), Valid: true}
+	g.baseFee = sql.NullString{Valid: true}
+	if header.BaseFee != nil {
+		g.baseFee.String = header.BaseFee.String()
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/permit"
+	"github.com/yolodolo42/clifi/internal/relay"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+const (
+	// relayGasLimit covers two transferWithAuthorization calls through
+	// Multicall3; the fee is quoted for it up front since the signed calls
+	// can't be simulated before they are signed.
+	relayGasLimit = 250_000
+	// gaslessValidity bounds how long a signed authorization stays usable
+	// if the relay drops the task.
+	gaslessValidity = 30 * time.Minute
+	relayWait       = 3 * time.Minute
+)
+
+type sendTokenGaslessInput struct {
+	Chain        string `json:"chain"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Token        string `json:"token"`
+	AmountTokens string `json:"amount_tokens"`
+	MaxFeeTokens string `json:"max_fee_tokens"`
+	Password     string `json:"password"`
+	Confirm      bool   `json:"confirm"`
+}
+
+// relayer creates the relay client on first use.
+func (tr *ToolRegistry) relayer() (*relay.Client, error) {
+	tr.relayOnce.Do(func() {
+		tr.relay, tr.relayErr = relay.New()
+	})
+	return tr.relay, tr.relayErr
+}
+
+// handleSendTokenGasless sends an EIP-3009 token (e.g. USDC) from a wallet
+// with no native gas: the owner signs transferWithAuthorization and the
+// relay submits it. The relay fee, quoted in the token, is taken out of the
+// amount and paid to CLIFI_RELAY_FEE_RECIPIENT in the same transaction, so
+// the recipient gets the amount less the fee.
+func (tr *ToolRegistry) handleSendTokenGasless(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params sendTokenGaslessInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	relayer, err := tr.relayer()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if !relayer.Enabled() {
+		return ToolOutput{}, relay.ErrNotConfigured
+	}
+	toAddr, err := requireHexAddress("recipient address", params.To)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	tokenAddr, err := tr.resolveToken(params.Chain, params.Token)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if params.AmountTokens == "" {
+		return ToolOutput{}, fmt.Errorf("amount_tokens is required")
+	}
+	fromAddr, cfg, err := tr.prepareTxFrom(params.Chain, params.From)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	// Waiting on the relay outlasts the lookups' timeout.
+	relayCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
+	defer cancel()
+	decimals, symbol, err := queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	amount, err := decimalToWei(params.AmountTokens, int(decimals))
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid amount_tokens: %w", err)
+	}
+	if amount.Sign() <= 0 {
+		return ToolOutput{}, fmt.Errorf("amount_tokens must be greater than zero")
+	}
+
+	info, err := tr.chainClient.GetPermitInfo(ctx, params.Chain, tokenAddr, fromAddr)
+	if errors.Is(err, chain.ErrPermitUnsupported) || (err == nil && !info.TransferAuthorization) {
+		return ToolOutput{}, fmt.Errorf("%s (%s) does not support gasless transfers (EIP-3009 transferWithAuthorization); use send_token", symbol, tokenAddr.Hex())
+	}
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	domain, err := permit.MatchDomain(info.Name, info.Version, cfg.ChainID, tokenAddr, info.DomainSeparator)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	balance, err := tr.chainClient.GetTokenBalance(ctx, params.Chain, tokenAddr, fromAddr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if balance.Balance.Cmp(amount) < 0 {
+		return ToolOutput{}, fmt.Errorf("insufficient %s: have %s, need %s", symbol, chain.FormatBalance(balance.Balance, decimals), params.AmountTokens)
+	}
+
+	fee, err := relayer.EstimateFee(ctx, cfg.ChainID, tokenAddr, relayGasLimit)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	net := new(big.Int).Set(amount)
+	if relayer.FeeRecipient != nil {
+		net.Sub(net, fee)
+		if net.Sign() <= 0 {
+			return ToolOutput{}, fmt.Errorf("the relay fee of %s %s is more than the %s %s sent", chain.FormatBalance(fee, decimals), symbol, params.AmountTokens, symbol)
+		}
+	}
+
+	policy := loadPolicy()
+	data, err := buildERC20TransferData(toAddr, net)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if err := tx.Validate(tx.Intent{Chain: params.Chain, From: fromAddr, To: tokenAddr, ValueWei: big.NewInt(0), Data: data}, policy); err != nil {
+		return ToolOutput{}, err
+	}
+	recipientCheck, err := tr.screenRecipient(ctx, "Recipient", toAddr.Hex(), policy)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview gasless ERC20 transfer:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- To: %s\n- Amount: %s %s\n",
+		tokenAddr.Hex(), symbol, params.Chain, fromAddr.Hex(), toAddr.Hex(), params.AmountTokens, symbol)
+	if relayer.FeeRecipient != nil {
+		fmt.Fprintf(&b, "- Relay fee: %s %s (quoted by Gelato, paid to %s)\n- Recipient gets: %s %s\n",
+			chain.FormatBalance(fee, decimals), symbol, relayer.FeeRecipient.Hex(), chain.FormatBalance(net, decimals), symbol)
+	} else {
+		fmt.Fprintf(&b, "- Relay fee: about %s %s, paid by the relay sponsor\n", chain.FormatBalance(fee, decimals), symbol)
+	}
+	b.WriteString("- Gas: none from your wallet; Gelato's relay submits a signed transferWithAuthorization\n")
+	b.WriteString(tr.checkContract(ctx, params.Chain, cfg, "Token", tokenAddr).and(recipientCheck).previewText())
+	summary := b.String()
+
+	if !params.Confirm {
+		if relayer.FeeRecipient != nil {
+			return ToolOutput{Text: summary + fmt.Sprintf("\nSet confirm=true and max_fee_tokens=%s, and provide password to sign and relay.", exactUnits(fee, decimals))}, nil
+		}
+		return ToolOutput{Text: summary + "\nSet confirm=true and provide password to sign and relay."}, nil
+	}
+	if relayer.FeeRecipient != nil {
+		if err := checkRelayFee(fee, params.MaxFeeTokens, decimals, symbol); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+	if err := tr.checkSigner(params.Chain, fromAddr, params.Password); err != nil {
+		return ToolOutput{}, err
+	}
+
+	validBefore := big.NewInt(time.Now().Add(gaslessValidity).Unix())
+	auths := []permit.TransferAuthorization{{From: fromAddr, To: toAddr, Value: net}}
+	if relayer.FeeRecipient != nil && fee.Sign() > 0 {
+		auths = append(auths, permit.TransferAuthorization{From: fromAddr, To: *relayer.FeeRecipient, Value: fee})
+	}
+	var calls []relay.Call
+	for _, auth := range auths {
+		auth.ValidAfter, auth.ValidBefore = big.NewInt(0), validBefore
+		if _, err := rand.Read(auth.Nonce[:]); err != nil {
+			return ToolOutput{}, err
+		}
+		sig, err := tr.signTypedData(fromAddr, params.Password, auth.TypedData(domain))
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		calldata, err := auth.Calldata(sig)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		calls = append(calls, relay.Call{Target: tokenAddr, Data: calldata})
+	}
+	target, calldata := tokenAddr, calls[0].Data
+	if len(calls) > 1 {
+		// One task for both, so the fee is only paid if the transfer lands.
+		target = relay.Multicall3Address
+		if calldata, err = relay.Batch(calls); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+
+	task, err := relayer.SponsoredCall(ctx, cfg.ChainID, target, calldata)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	text := summary + "\nRelay task: " + task
+	waitCtx, cancelWait := context.WithTimeout(relayCtx, relayWait)
+	defer cancelWait()
+	status, err := relayer.Wait(waitCtx, task, 3*time.Second)
+	if err != nil {
+		return ToolOutput{Text: text + fmt.Sprintf("\nStill pending (%s); the authorization expires %s.", status.State, time.Unix(validBefore.Int64(), 0).UTC().Format("15:04 UTC"))}, nil
+	}
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "From", Value: fromAddr.Hex()},
+		{Key: "To", Value: toAddr.Hex()},
+		{Key: "Amount", Value: chain.FormatBalance(net, decimals) + " " + symbol},
+	}
+	if status.TxHash != (common.Hash{}) {
+		text += "\nRelayed tx: " + status.TxHash.Hex()
+		if url := tr.txURL(params.Chain, status.TxHash.Hex()); url != "" {
+			text += "\nExplorer: " + url
+		}
+		items = append(items, tr.txItem(params.Chain, status.TxHash.Hex()))
+	}
+	if status.State != relay.StateSuccess {
+		msg := fmt.Sprintf("relay task %s ended %s", task, status.State)
+		if status.Message != "" {
+			msg += ": " + status.Message
+		}
+		return ToolOutput{Text: text}, errors.New(msg)
+	}
+	return ToolOutput{Text: text + "\nStatus: success", Blocks: []UIBlock{kvBlock("Gasless send", items...)}}, nil
+}
+
+// checkRelayFee refuses a relay fee quoted at confirm time above
+// maxFeeTokens, the fee the user saw in the preview. The fee comes out of
+// the amount sent, so a quote that rose since would quietly move more of
+// it to the fee recipient.
+func checkRelayFee(fee *big.Int, maxFeeTokens string, decimals uint8, symbol string) error {
+	if maxFeeTokens == "" {
+		return errors.New("max_fee_tokens is required to confirm: pass the relay fee from the preview")
+	}
+	maxFee, err := decimalToWei(maxFeeTokens, int(decimals))
+	if err != nil {
+		return fmt.Errorf("invalid max_fee_tokens: %w", err)
+	}
+	if fee.Cmp(maxFee) > 0 {
+		return fmt.Errorf("the relay fee rose to %s %s, above the %s %s confirmed; preview the transfer again", exactUnits(fee, decimals), symbol, maxFeeTokens, symbol)
+	}
+	return nil
+}
+
+// exactUnits renders a base-unit amount in whole tokens without rounding,
+// so it parses back to the same amount.
+func exactUnits(v *big.Int, decimals uint8) string {
+	s := new(big.Rat).SetFrac(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(int(decimals))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
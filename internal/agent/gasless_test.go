@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleSendTokenGasless_Validation(t *testing.T) {
+	t.Setenv("CLIFI_RELAY_FEE_RECIPIENT", "")
+	t.Setenv("CLIFI_RELAY_API_KEY", "")
+	_, err := NewToolRegistry().handleSendTokenGasless(context.Background(), json.RawMessage(`{"chain":"base"}`))
+	assert.ErrorContains(t, err, "CLIFI_RELAY_API_KEY")
+
+	t.Setenv("CLIFI_RELAY_API_KEY", "sponsor-key")
+	tr := NewToolRegistry()
+	to := "0x00000000000000000000000000000000000000bb"
+	token := "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	for _, tc := range []struct {
+		input, want string
+	}{
+		{`{"chain":"base","token":"` + token + `","amount_tokens":"1"}`, "recipient address"},
+		{`{"chain":"base","to":"` + to + `","token":"` + token + `"}`, "amount_tokens is required"},
+	} {
+		_, err := tr.handleSendTokenGasless(context.Background(), json.RawMessage(tc.input))
+		assert.ErrorContains(t, err, tc.want, tc.input)
+	}
+
+	t.Setenv("CLIFI_RELAY_FEE_RECIPIENT", "treasury")
+	_, err = NewToolRegistry().handleSendTokenGasless(context.Background(), json.RawMessage(`{"chain":"base"}`))
+	assert.ErrorContains(t, err, "CLIFI_RELAY_FEE_RECIPIENT")
+}
+
+func TestCheckRelayFee(t *testing.T) {
+	fee := big.NewInt(123_457) // 0.123457 USDC
+	assert.Equal(t, "0.123457", exactUnits(fee, 6))
+	assert.Equal(t, "2", exactUnits(big.NewInt(2_000_000), 6))
+
+	assert.NoError(t, checkRelayFee(fee, exactUnits(fee, 6), 6, "USDC"), "the previewed fee")
+	assert.NoError(t, checkRelayFee(fee, "0.2", 6, "USDC"))
+	assert.ErrorContains(t, checkRelayFee(fee, "0.12", 6, "USDC"), "rose to 0.123457 USDC")
+	assert.ErrorContains(t, checkRelayFee(fee, "", 6, "USDC"), "max_fee_tokens is required")
+	assert.ErrorContains(t, checkRelayFee(fee, "abc", 6, "USDC"), "invalid max_fee_tokens")
+}
@@ -0,0 +1,259 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+	"github.com/yolodolo42/clifi/internal/history"
+)
+
+// DefaultHistoryBlocks is how far back History scans Transfer logs.
+const DefaultHistoryBlocks = 10000
+
+const transferEvent = "Transfer(address indexed from,address indexed to,uint256 value)"
+
+// HistoryRequest selects a wallet's history. Blocks bounds the Transfer
+// log scan on each chain, counted back from the head; Limit caps the
+// entries returned.
+type HistoryRequest struct {
+	Address common.Address
+	Chains  []string
+	Blocks  uint64
+	Limit   int
+}
+
+// History lists address's ERC-20 transfers found in the last req.Blocks
+// blocks of each chain, merged with the transactions clifi sent from it.
+// Without an explorer API, incoming native transfers can't be found, so
+// they are not listed. A chain that fails is noted and skipped.
+func (tr *ToolRegistry) History(ctx context.Context, req HistoryRequest) (history.Report, error) {
+	if req.Blocks == 0 {
+		req.Blocks = DefaultHistoryBlocks
+	}
+	if req.Blocks > maxLogBlocks {
+		return history.Report{}, fmt.Errorf("scan at most %d blocks at a time", maxLogBlocks)
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultLogLimit
+	}
+
+	report := history.Report{Address: req.Address.Hex()}
+	var entries []history.Entry
+	failed := 0
+	for _, name := range req.Chains {
+		found, rng, err := tr.chainHistory(ctx, name, req)
+		if err != nil {
+			report.Notes = append(report.Notes, fmt.Sprintf("%s skipped: %v", name, err))
+			failed++
+			continue
+		}
+		entries = append(entries, found...)
+		report.Ranges = append(report.Ranges, rng)
+	}
+	if failed > 0 && failed == len(req.Chains) {
+		return history.Report{}, errors.New("no chain could be queried: " + strings.Join(report.Notes, "; "))
+	}
+	report.Entries, report.Truncated = history.Merge(entries, req.Limit)
+	report.Notes = append(report.Notes, "Native transfers are only those clifi sent; incoming native transfers leave no logs to find.")
+	return report, nil
+}
+
+// chainHistory returns the wallet's token transfers and sent transactions
+// on one chain, and the block range fully scanned for transfers.
+func (tr *ToolRegistry) chainHistory(ctx context.Context, chainName string, req HistoryRequest) ([]history.Entry, history.Range, error) {
+	cfg, err := tr.chainClient.GetChainConfig(chainName)
+	if err != nil {
+		return nil, history.Range{}, fmt.Errorf("unknown chain: %s", chainName)
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	latest, err := tr.chainClient.BlockNumber(ctx, chainName)
+	if err != nil {
+		return nil, history.Range{}, err
+	}
+	rng := history.Range{Chain: chainName, From: saturatingSub(latest, req.Blocks-1), To: latest}
+
+	times := blockTimes{tr: tr, chain: chainName, seen: make(map[uint64]time.Time)}
+	var entries []history.Entry
+	scannedFrom := rng.From
+	for _, side := range []string{"from", "to"} {
+		topics, err := decode.EventTopics(transferEvent, map[string][]string{side: {req.Address.Hex()}})
+		if err != nil {
+			return nil, history.Range{}, err
+		}
+		logs, lowest, err := tr.scanLogs(ctx, chainName, ethereum.FilterQuery{Topics: topics}, rng.From, rng.To, req.Limit)
+		if err != nil {
+			return nil, history.Range{}, err
+		}
+		scannedFrom = max(scannedFrom, lowest)
+		for i := range logs {
+			if e, ok := tr.tokenTransfer(ctx, chainName, req.Address, &logs[i]); ok {
+				e.Time = times.at(ctx, e.Block)
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	sent, err := tr.sentHistory(ctx, chainName, cfg.NativeCurrency, req.Address, rng.From)
+	if err != nil {
+		return nil, history.Range{}, err
+	}
+	for _, e := range sent {
+		if e.Block > 0 {
+			e.Time = times.at(ctx, e.Block)
+		}
+		entries = append(entries, e)
+	}
+	rng.From = scannedFrom
+	return entries, rng, nil
+}
+
+// tokenTransfer reads an ERC-20 Transfer log. ERC-721 transfers share the
+// event signature but index the token ID, so they have a fourth topic and
+// no data; they are skipped.
+func (tr *ToolRegistry) tokenTransfer(ctx context.Context, chainName string, wallet common.Address, l *types.Log) (history.Entry, bool) {
+	if l.Removed || len(l.Topics) != 3 || len(l.Data) != 32 {
+		return history.Entry{}, false
+	}
+	from := common.BytesToAddress(l.Topics[1].Bytes())
+	to := common.BytesToAddress(l.Topics[2].Bytes())
+	e := history.Entry{
+		Chain:    chainName,
+		Block:    l.BlockNumber,
+		TxHash:   l.TxHash.Hex(),
+		LogIndex: l.Index,
+		Kind:     history.KindToken,
+		Token:    l.Address.Hex(),
+		// Logs are only kept for transactions that succeeded.
+		Status: "success",
+	}
+	switch {
+	case from == wallet && to == wallet:
+		e.Direction, e.Counterparty = history.Self, wallet.Hex()
+	case from == wallet:
+		e.Direction, e.Counterparty = history.Out, to.Hex()
+	default:
+		e.Direction, e.Counterparty = history.In, from.Hex()
+	}
+
+	value := new(big.Int).SetBytes(l.Data)
+	symbol, decimals, err := tr.chainClient.GetTokenSymbolDecimals(ctx, chainName, l.Address)
+	if err != nil || symbol == "" {
+		// Without decimals the raw amount is the only honest one.
+		e.Asset, e.Amount = shortAddress(l.Address), value.String()
+		return e, true
+	}
+	e.Asset, e.Amount = symbol, chain.FormatBalance(value, decimals)
+	return e, true
+}
+
+// sentHistory lists the transactions clifi sent from wallet on chainName
+// that were mined at or after block from, or not mined yet. Receipts not
+// stored yet are fetched and stored.
+func (tr *ToolRegistry) sentHistory(ctx context.Context, chainName, symbol string, wallet common.Address, from uint64) ([]history.Entry, error) {
+	rs, err := tr.receiptStore()
+	if err != nil {
+		// No local database means nothing was sent through clifi.
+		return nil, nil
+	}
+	sent, err := rs.SentFrom(chainName, wallet, 0)
+	if err != nil {
+		return nil, err
+	}
+	var out []history.Entry
+	for _, st := range sent {
+		e := history.Entry{
+			Chain:        chainName,
+			TxHash:       common.HexToHash(st.Hash).Hex(),
+			Kind:         history.KindNative,
+			Direction:    history.Out,
+			Asset:        symbol,
+			Amount:       chain.FormatBalance(st.Tx.Value(), 18),
+			Counterparty: "contract creation",
+			Status:       "not mined",
+		}
+		if to := st.Tx.To(); to != nil {
+			e.Counterparty = to.Hex()
+			if *to == wallet {
+				e.Direction = history.Self
+			}
+		}
+		block, status, err := tr.sentOutcome(ctx, rs, chainName, st.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("receipt of %s: %w", st.Hash, err)
+		}
+		if block > 0 && block < from {
+			continue
+		}
+		if block > 0 {
+			e.Block, e.Status = block, status
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// sentOutcome returns the block a sent transaction was mined in and
+// whether it succeeded, or block zero while it isn't mined.
+func (tr *ToolRegistry) sentOutcome(ctx context.Context, rs *ReceiptStore, chainName, hash string) (uint64, string, error) {
+	status := func(s uint64) string {
+		if s == types.ReceiptStatusSuccessful {
+			return "success"
+		}
+		return "failed"
+	}
+	if stored, err := rs.Get(chainName, hash); err == nil {
+		var receipt struct {
+			BlockNumber *hexutil.Big `json:"blockNumber"`
+		}
+		if err := json.Unmarshal([]byte(stored.RawJSON), &receipt); err == nil && receipt.BlockNumber != nil {
+			return receipt.BlockNumber.ToInt().Uint64(), status(stored.Status), nil
+		}
+	}
+	receipt, err := tr.chainClient.GetTransactionReceipt(ctx, chainName, common.HexToHash(hash))
+	if errors.Is(err, ethereum.NotFound) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	_ = rs.Upsert(chainName, receipt)
+	return receipt.BlockNumber.Uint64(), status(receipt.Status), nil
+}
+
+// blockTimes looks up block timestamps once per block. A block that
+// can't be fetched has a zero time; the entry still shows its number.
+type blockTimes struct {
+	tr    *ToolRegistry
+	chain string
+	seen  map[uint64]time.Time
+}
+
+func (b blockTimes) at(ctx context.Context, block uint64) time.Time {
+	if t, ok := b.seen[block]; ok {
+		return t
+	}
+	var t time.Time
+	if h, err := b.tr.chainClient.HeaderByNumber(ctx, b.chain, new(big.Int).SetUint64(block)); err == nil {
+		t = time.Unix(int64(h.Time), 0).UTC()
+	}
+	b.seen[block] = t
+	return t
+}
+
+// shortAddress abbreviates a token without a symbol, e.g. 0x1234…abcd.
+func shortAddress(a common.Address) string {
+	h := a.Hex()
+	return h[:6] + "…" + h[len(h)-4:]
+}
@@ -0,0 +1,25 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTransfer_SkipsNonERC20(t *testing.T) {
+	tr := NewToolRegistry()
+	wallet := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	sig := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	from, to := common.BytesToHash(wallet.Bytes()), common.HexToHash("0xbb")
+
+	nft := &types.Log{Topics: []common.Hash{sig, from, to, common.HexToHash("0x01")}}
+	_, ok := tr.tokenTransfer(context.Background(), "base", wallet, nft)
+	assert.False(t, ok, "ERC-721 transfer")
+
+	removed := &types.Log{Topics: []common.Hash{sig, from, to}, Data: make([]byte, 32), Removed: true}
+	_, ok = tr.tokenTransfer(context.Background(), "base", wallet, removed)
+	assert.False(t, ok, "log removed by a reorg")
+}
@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yolodolo42/clifi/internal/pnl"
+	"github.com/yolodolo42/clifi/internal/quote"
+)
+
+// transferTopic is the ERC-20 Transfer(address,address,uint256) event.
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// Ledger indexes within a transaction: the native value, the gas fee, then
+// token transfers by log index.
+const (
+	ledgerNativeIdx   = 0
+	ledgerFeeIdx      = 1
+	ledgerTransferIdx = 2
+)
+
+// AddLedger records asset flows. Flows already recorded for the same
+// transaction are kept as they are, so re-fetching a receipt later does not
+// re-price it.
+func (s *ReceiptStore) AddLedger(events []pnl.Event) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("persist ledger: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, e := range events {
+		_, err := tx.Exec(`
+INSERT OR IGNORE INTO ledger (chain, tx_hash, address, idx, symbol, token, amount, price_usd, kind, at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`, e.Chain, strings.ToLower(e.TxHash), strings.ToLower(e.Address), e.Index, e.Symbol, e.Token, e.Amount, e.PriceUSD, string(e.Kind), e.At.Unix())
+		if err != nil {
+			return fmt.Errorf("persist ledger: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Ledger returns the flows recorded for address, oldest first.
+func (s *ReceiptStore) Ledger(address string) ([]pnl.Event, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	rows, err := s.db.Query(`
+SELECT chain, tx_hash, idx, symbol, token, amount, price_usd, kind, at
+FROM ledger WHERE address = ? ORDER BY at, tx_hash, idx
+`, strings.ToLower(address))
+	if err != nil {
+		return nil, fmt.Errorf("load ledger: %w", err)
+	}
+	defer rows.Close()
+
+	var out []pnl.Event
+	for rows.Next() {
+		e := pnl.Event{Address: address}
+		var kind string
+		var at int64
+		if err := rows.Scan(&e.Chain, &e.TxHash, &e.Index, &e.Symbol, &e.Token, &e.Amount, &e.PriceUSD, &kind, &at); err != nil {
+			return nil, fmt.Errorf("load ledger: %w", err)
+		}
+		e.Kind = pnl.Kind(kind)
+		e.At = time.Unix(at, 0).UTC()
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// persistReceipt stores a mined receipt and records the flows it caused
+// for keystore wallets. Both are best effort.
+func (tr *ToolRegistry) persistReceipt(ctx context.Context, chainName string, receipt *types.Receipt) {
+	rs, err := tr.receiptStore()
+	if err != nil {
+		return
+	}
+	_ = rs.Upsert(chainName, receipt)
+
+	events, err := tr.ledgerEvents(ctx, chainName, receipt)
+	if err == nil && len(events) > 0 {
+		err = rs.AddLedger(events)
+	}
+	if err != nil {
+		slog.Debug("ledger not recorded", "chain", chainName, "tx", receipt.TxHash.Hex(), "err", err)
+	}
+}
+
+// ledgerEvents derives the keystore wallets' flows from a receipt: the
+// native value and gas fee of the transaction, and ERC-20 transfers in its
+// logs. Each flow is priced at the current USD price.
+func (tr *ToolRegistry) ledgerEvents(ctx context.Context, chainName string, receipt *types.Receipt) ([]pnl.Event, error) {
+	km, err := tr.keystore()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := tr.chainClient.GetChainConfig(chainName)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	tx, _, err := tr.chainClient.TransactionByHash(ctx, chainName, receipt.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("load transaction: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("recover sender: %w", err)
+	}
+
+	now := time.Now().UTC()
+	prices := make(map[string]float64)
+	price := func(token common.Address) float64 {
+		key := token.Hex()
+		if p, ok := prices[key]; ok {
+			return p
+		}
+		var p float64
+		if !cfg.IsTestnet && tr.quotes != nil {
+			p, _ = tr.quotes.TokenPrice(ctx, cfg.ChainID.Int64(), key)
+		}
+		prices[key] = p
+		return p
+	}
+	event := func(owner common.Address, idx int, symbol string, token common.Address, amount *big.Int, decimals uint8, kind pnl.Kind) pnl.Event {
+		e := pnl.Event{
+			Chain:    chainName,
+			TxHash:   receipt.TxHash.Hex(),
+			Index:    idx,
+			Address:  owner.Hex(),
+			Symbol:   symbol,
+			Amount:   weiToFloat(amount, decimals),
+			PriceUSD: price(token),
+			Kind:     kind,
+			At:       now,
+		}
+		if token != quote.NativeToken {
+			e.Token = token.Hex()
+		}
+		return e
+	}
+
+	var events []pnl.Event
+	value := tx.Value()
+	if value.Sign() > 0 && receipt.Status == types.ReceiptStatusSuccessful {
+		if km.HasAccount(from) && (tx.To() == nil || *tx.To() != from) {
+			events = append(events, event(from, ledgerNativeIdx, cfg.NativeCurrency, quote.NativeToken, new(big.Int).Neg(value), 18, pnl.KindTransfer))
+		}
+		// A send to itself moves nothing.
+		if to := tx.To(); to != nil && *to != from && km.HasAccount(*to) {
+			events = append(events, event(*to, ledgerNativeIdx, cfg.NativeCurrency, quote.NativeToken, value, 18, pnl.KindTransfer))
+		}
+	}
+	if km.HasAccount(from) && receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		if fee.Sign() > 0 {
+			events = append(events, event(from, ledgerFeeIdx, cfg.NativeCurrency, quote.NativeToken, fee.Neg(fee), 18, pnl.KindFee))
+		}
+	}
+
+	for _, l := range receipt.Logs {
+		// ERC-721 Transfer has the same signature but indexes the token ID.
+		if len(l.Topics) != 3 || l.Topics[0] != transferTopic || len(l.Data) != 32 {
+			continue
+		}
+		src := common.BytesToAddress(l.Topics[1].Bytes())
+		dst := common.BytesToAddress(l.Topics[2].Bytes())
+		ours := km.HasAccount(src) || km.HasAccount(dst)
+		if !ours {
+			continue
+		}
+		symbol, decimals, err := tr.chainClient.GetTokenSymbolDecimals(ctx, chainName, l.Address)
+		if err != nil {
+			slog.Debug("ledger skipped token transfer", "token", l.Address.Hex(), "err", err)
+			continue
+		}
+		amount := new(big.Int).SetBytes(l.Data)
+		idx := ledgerTransferIdx + int(l.Index)
+		if km.HasAccount(src) {
+			events = append(events, event(src, idx, symbol, l.Address, new(big.Int).Neg(amount), decimals, pnl.KindTransfer))
+		}
+		if km.HasAccount(dst) {
+			events = append(events, event(dst, idx, symbol, l.Address, amount, decimals, pnl.KindTransfer))
+		}
+	}
+	return events, nil
+}
+
+// PnL computes the cost basis and realized and unrealized P&L of address
+// from its recorded flows, pricing current holdings live.
+func (tr *ToolRegistry) PnL(ctx context.Context, address common.Address) (pnl.Report, error) {
+	rs, err := tr.receiptStore()
+	if err != nil {
+		return pnl.Report{}, err
+	}
+	events, err := rs.Ledger(address.Hex())
+	if err != nil {
+		return pnl.Report{}, err
+	}
+	price := func(chainName, token string) float64 {
+		cfg, err := tr.chainClient.GetChainConfig(chainName)
+		if err != nil || cfg.IsTestnet || tr.quotes == nil {
+			return 0
+		}
+		if token == "" {
+			token = quote.NativeToken.Hex()
+		}
+		p, err := tr.quotes.TokenPrice(ctx, cfg.ChainID.Int64(), token)
+		if err != nil {
+			return 0
+		}
+		return p
+	}
+	return pnl.Compute(address.Hex(), events, price), nil
+}
+
+func weiToFloat(amount *big.Int, decimals uint8) float64 {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+	return v
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// ModelUsage is the LLM usage of one provider and model over a period.
+type ModelUsage struct {
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Calls        int     `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// AddLLMUsage adds one request to the day's totals for its provider and
+// model. day is a local date, e.g. 2026-10-16.
+func (s *ReceiptStore) AddLLMUsage(day, provider, model string, u llm.Usage, costUSD float64) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	_, err := s.db.Exec(`
+INSERT INTO llm_usage (day, provider, model, calls, input_tokens, output_tokens, cost_usd)
+VALUES (?, ?, ?, 1, ?, ?, ?)
+ON CONFLICT(day, provider, model) DO UPDATE SET
+	calls = calls + 1,
+	input_tokens = input_tokens + excluded.input_tokens,
+	output_tokens = output_tokens + excluded.output_tokens,
+	cost_usd = cost_usd + excluded.cost_usd
+`, day, provider, model, u.InputTokens, u.OutputTokens, costUSD)
+	if err != nil {
+		return fmt.Errorf("persist llm usage: %w", err)
+	}
+	return nil
+}
+
+// LLMUsage returns the month's usage per provider and model, costliest
+// first. month is e.g. 2026-10.
+func (s *ReceiptStore) LLMUsage(month string) ([]ModelUsage, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	rows, err := s.db.Query(`
+SELECT provider, model, SUM(calls), SUM(input_tokens), SUM(output_tokens), SUM(cost_usd)
+FROM llm_usage WHERE day LIKE ? || '-%'
+GROUP BY provider, model
+ORDER BY SUM(cost_usd) DESC, SUM(input_tokens) + SUM(output_tokens) DESC, provider, model
+`, month)
+	if err != nil {
+		return nil, fmt.Errorf("load llm usage: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ModelUsage
+	for rows.Next() {
+		var u ModelUsage
+		if err := rows.Scan(&u.Provider, &u.Model, &u.Calls, &u.InputTokens, &u.OutputTokens, &u.CostUSD); err != nil {
+			return nil, fmt.Errorf("load llm usage: %w", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// UsageHistory returns the month's LLM usage per provider and model, from
+// every session rather than just this one.
+func (a *Agent) UsageHistory(month string) ([]ModelUsage, error) {
+	rs, err := a.toolRegistry.receiptStore()
+	if err != nil {
+		return nil, err
+	}
+	return rs.LLMUsage(month)
+}
+
+// recordUsageHistory adds a request to the usage ledger in the receipt DB.
+func (a *Agent) recordUsageHistory(provider llm.ProviderID, model string, u llm.Usage, costUSD float64) {
+	rs, err := a.toolRegistry.receiptStore()
+	if err == nil {
+		err = rs.AddLLMUsage(time.Now().Format(time.DateOnly), string(provider), model, u, costUSD)
+	}
+	if err != nil {
+		slog.Warn("failed to record LLM usage", "err", err)
+	}
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestReceiptStore_LLMUsage(t *testing.T) {
+	rs, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	defer rs.Close()
+
+	u := llm.Usage{InputTokens: 1000, OutputTokens: 100}
+	require.NoError(t, rs.AddLLMUsage("2026-06-01", "anthropic", "claude-sonnet-4", u, 0.5))
+	require.NoError(t, rs.AddLLMUsage("2026-06-20", "anthropic", "claude-sonnet-4", u, 0.5))
+	require.NoError(t, rs.AddLLMUsage("2026-06-20", "copilot", "gpt-4o", u, 0))
+	require.NoError(t, rs.AddLLMUsage("2026-07-01", "anthropic", "claude-sonnet-4", u, 0.5))
+
+	june, err := rs.LLMUsage("2026-06")
+	require.NoError(t, err)
+	assert.Equal(t, []ModelUsage{
+		{Provider: "anthropic", Model: "claude-sonnet-4", Calls: 2, InputTokens: 2000, OutputTokens: 200, CostUSD: 1},
+		{Provider: "copilot", Model: "gpt-4o", Calls: 1, InputTokens: 1000, OutputTokens: 100},
+	}, june)
+
+	none, err := rs.LLMUsage("2026-05")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestAgent_UsageHistory(t *testing.T) {
+	a := NewWithProvider(pricedMock{llm.NewMockProvider()}, t.TempDir())
+	defer a.Close()
+
+	_, err := a.Chat(context.Background(), "hi")
+	require.NoError(t, err)
+	_, err = a.Chat(context.Background(), "again")
+	require.NoError(t, err)
+
+	rows, err := a.UsageHistory(currentMonth())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "mock", rows[0].Provider)
+	assert.Equal(t, "mock-1", rows[0].Model)
+	assert.Equal(t, 2, rows[0].Calls)
+	assert.Greater(t, rows[0].CostUSD, 0.0)
+
+	// Resetting the budget keeps the history.
+	require.NoError(t, ResetSpend(a.dataDir))
+	rows, err = a.UsageHistory(currentMonth())
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows[0].Calls)
+}
@@ -0,0 +1,126 @@
+//go:build integration
+// +build integration
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/testutil"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// These tests run the send, approve and receipt flows against an anvil
+// node on the local chain preset, so nothing real is spent:
+//
+//	go test -tags=integration ./internal/agent -run Local
+//
+// Set CLIFI_FORK_URL to a mainnet RPC to also run the tests that need
+// mainnet contracts.
+
+const localPassword = "testpassword"
+
+// newLocalRegistry starts anvil and returns a registry whose default wallet
+// is anvil's first funded account.
+func newLocalRegistry(t *testing.T) (*ToolRegistry, *testutil.Anvil) {
+	t.Helper()
+	node := testutil.StartAnvil(t)
+
+	dataDir := testutil.TempDir(t)
+	s := &chain.Settings{Local: &chain.LocalSettings{RPCURL: node.URL}}
+	require.NoError(t, s.Save(dataDir))
+
+	km, err := wallet.NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	_, err = km.ImportMnemonic(testutil.AnvilMnemonic, accounts.DefaultBaseDerivationPath, localPassword)
+	require.NoError(t, err)
+
+	for _, env := range []string{"CLIFI_MAX_TX_ETH", "CLIFI_ALLOW_TO", "CLIFI_DENY_TO", "CLIFI_PRIVATE_TX"} {
+		testutil.UnsetEnv(t, env)
+	}
+
+	tr := NewToolRegistryWithDataDir(dataDir)
+	t.Cleanup(tr.Close)
+	return tr, node
+}
+
+func TestLocal_SendNativeAndReceipt(t *testing.T) {
+	tr, _ := newLocalRegistry(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	before, err := tr.chainClient.GetBalance(ctx, chain.LocalChain, to)
+	require.NoError(t, err)
+
+	p, err := tr.PrepareSend(ctx, SendRequest{Chain: chain.LocalChain, To: to.Hex(), Amount: "1.5"})
+	require.NoError(t, err)
+	assert.Contains(t, p.Preview, "Amount: 1.5 ETH")
+
+	sent, err := tr.SendPrepared(ctx, p, localPassword, true)
+	require.NoError(t, err)
+	require.NotNil(t, sent.Confirmed, "anvil mines instantly, so the wait sees the receipt")
+	assert.True(t, sent.Confirmed.Success)
+
+	tr.chainClient.InvalidateBalance(chain.LocalChain, to)
+	after, err := tr.chainClient.GetBalance(ctx, chain.LocalChain, to)
+	require.NoError(t, err)
+	want, _ := new(big.Int).SetString("1500000000000000000", 10)
+	assert.Equal(t, want, new(big.Int).Sub(after, before))
+
+	input, _ := json.Marshal(getReceiptInput{Chain: chain.LocalChain, TxHash: sent.Hash.Hex()})
+	out, err := tr.ExecuteTool(ctx, "get_receipt", input)
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Status: 1")
+}
+
+func TestLocal_SendNativeRequiresConfirm(t *testing.T) {
+	tr, _ := newLocalRegistry(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	input, _ := json.Marshal(sendNativeInput{
+		Chain:     chain.LocalChain,
+		To:        "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		AmountETH: "1",
+	})
+	out, err := tr.ExecuteTool(ctx, "send_native", input)
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Set confirm=true")
+	assert.Empty(t, out.Confirmed, "nothing is broadcast without confirm")
+}
+
+func TestLocal_ApproveOnFork(t *testing.T) {
+	tr, node := newLocalRegistry(t)
+	if !node.Forked {
+		t.Skip("CLIFI_FORK_URL not set; approve needs a deployed token")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	usdc := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	router := "0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"
+	wait := true
+	input, _ := json.Marshal(approveTokenInput{
+		Chain:        chain.LocalChain,
+		Token:        usdc,
+		Spender:      router,
+		AmountTokens: "25",
+		Password:     localPassword,
+		Confirm:      true,
+		Wait:         &wait,
+	})
+	out, err := tr.ExecuteTool(ctx, "approve_token", input)
+	require.NoError(t, err)
+	require.NotNil(t, out.Confirmed)
+	assert.True(t, out.Confirmed.Success)
+}
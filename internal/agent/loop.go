@@ -2,48 +2,85 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/chain"
 	"github.com/yolodolo42/clifi/internal/llm"
 )
 
 // ChatEvent represents a single event in the chat flow (tool call, result, or content)
 type ChatEvent struct {
-	Type    string // "tool_call", "tool_result", "content"
+	Type    string // "tool_call", "tool_result", "content", "notice"
 	Tool    string // Tool name for tool_call/tool_result
 	Args    string // Tool arguments (summarized) for tool_call
 	Content string // Content for tool_result or final content
 	Blocks  []UIBlock
-	IsError bool // True if tool result was an error
+	IsError bool          // True if tool result was an error
+	Elapsed time.Duration // How long the tool ran, for tool_result
+	// Confirmed is the transaction a tool_result waited for, once mined.
+	Confirmed *TxConfirmation
 }
 
-// Agent is the core agent that orchestrates conversations and tool calls
+// Agent is the core agent that orchestrates conversations and tool calls.
+// It holds what every conversation shares: the provider, tools, settings
+// and spend. Conversations are Sessions; the Agent's own chat methods use
+// its main session.
 type Agent struct {
-	// mu protects conversation from concurrent access. Prevents concurrent Chat()
-	// calls from interleaving messages and corrupting conversation state.
-	mu           sync.Mutex
+	// mu guards the provider and the shared settings below. A turn copies
+	// them at its start and runs without it; see Session.sync. Lock a
+	// session's mu before this one, never after.
+	mu           sync.RWMutex
 	provider     llm.Provider
 	authManager  *auth.Manager
 	dataDir      string
 	toolRegistry *ToolRegistry
 	systemPrompt string
-	conversation []llm.Message
 
-	sessionID string
-	logger    *sessionLogger
+	// debugLLM turns on the per-turn request/response dump.
+	debugLLM bool
+	// routing picks a model per task; see SetRouting.
+	routing RoutingPolicy
+	// sampling holds configured generation parameters; sessions add their
+	// own /set values.
+	sampling SamplingPolicy
+	// budget caps the month's spend on paid models.
+	budget Budget
+	// generation counts model and provider switches; sessions held under
+	// an earlier one start over on their next turn.
+	generation uint64
+
+	// spendMu serializes updates to the month's spend.
+	spendMu sync.Mutex
+
+	// main is the session the Agent's own conversation methods use;
+	// sessions lists those opened with NewSession, for Close.
+	main       *Session
+	sessionsMu sync.Mutex
+	sessions   map[*Session]struct{}
+
+	// Token usage since the agent started; atomic so status reads don't
+	// wait on an in-flight Chat.
+	inputTokens  atomic.Int64
+	outputTokens atomic.Int64
 }
 
 // SystemPrompt is the default system prompt for the crypto agent
-const SystemPrompt = `You are clifi, a terminal-first crypto operator agent. You help users manage their crypto wallets and interact with EVM-compatible blockchains.
+const SystemPrompt = `You are clifi, a terminal-first crypto operator agent. You help users manage their crypto wallets and interact with EVM-compatible blockchains, Solana and Cosmos SDK chains.
 
 ## Your Capabilities
 - Query wallet balances across multiple chains (Ethereum, Base, Arbitrum, Optimism, Polygon)
+- Query SOL and SPL token balances and send SOL on Solana
+- Query bank balances and send tokens on Cosmos SDK chains (Cosmos Hub, Osmosis)
 - List and manage wallets in the local keystore
 - Provide information about supported chains
 
@@ -66,8 +103,8 @@ You have access to tools for querying blockchain state. Use them proactively whe
 
 Current limitations:
 - State-changing tools (send/approve) require explicit confirmation (confirm=true) before broadcasting
-- EVM chains only (no Solana, Bitcoin, etc.)
-- Native tokens and ERC20 tokens only`
+- EVM chains, Solana and Cosmos SDK chains only (no Bitcoin, etc.)
+- Native tokens and ERC20 tokens on EVM chains; SOL and SPL tokens on Solana; bank denoms, including IBC tokens, on Cosmos chains`
 
 // New creates a new agent with the default provider
 func New(providerID string) (*Agent, error) {
@@ -112,14 +149,28 @@ func New(providerID string) (*Agent, error) {
 		}
 	}
 
-	return &Agent{
+	a := &Agent{
 		provider:     provider,
 		authManager:  authManager,
 		dataDir:      dataDir,
 		toolRegistry: NewToolRegistryWithDataDir(dataDir),
 		systemPrompt: SystemPrompt,
-		conversation: make([]llm.Message, 0),
-	}, nil
+	}
+	a.main = newSession(a)
+	return a, nil
+}
+
+// NewWithProvider creates an agent that uses provider and keeps its wallets,
+// receipts and sessions in dataDir, without looking up credentials.
+func NewWithProvider(provider llm.Provider, dataDir string) *Agent {
+	a := &Agent{
+		provider:     provider,
+		dataDir:      dataDir,
+		toolRegistry: NewToolRegistryWithDataDir(dataDir),
+		systemPrompt: SystemPrompt,
+	}
+	a.main = newSession(a)
+	return a
 }
 
 // CreateProvider creates a provider instance based on available credentials.
@@ -130,7 +181,12 @@ func CreateProvider(authManager *auth.Manager, providerID llm.ProviderID) (llm.P
 	if err != nil {
 		return nil, err
 	}
+	return NewProviderWithKey(providerID, key)
+}
 
+// NewProviderWithKey creates a provider from an API key or OAuth access
+// token, using the provider's default model.
+func NewProviderWithKey(providerID llm.ProviderID, key string) (llm.Provider, error) {
 	switch providerID {
 	case llm.ProviderAnthropic:
 		return llm.NewAnthropicProvider(key, "")
@@ -175,88 +231,165 @@ func getProviderKey(authManager *auth.Manager, providerID llm.ProviderID) (strin
 
 // Chat sends a user message and returns the agent's response.
 // This is a thin wrapper around ChatWithEvents that discards event data.
-func (a *Agent) Chat(ctx context.Context, userMessage string) (string, error) {
-	events, err := a.ChatWithEvents(ctx, userMessage)
+func (s *Session) Chat(ctx context.Context, userMessage string) (string, error) {
+	events, err := s.ChatWithEvents(ctx, userMessage)
 	if err != nil {
 		return "", err
 	}
 
-	// Extract final content from events
-	for i := len(events) - 1; i >= 0; i-- {
-		if events[i].Type == "content" {
-			return events[i].Content, nil
-		}
-	}
-	return "", nil
+	return lastContent(events), nil
 }
 
 // ChatWithEvents sends a user message and returns structured events for UI rendering.
 // This exposes tool calls and results to the caller for visualization.
-func (a *Agent) ChatWithEvents(ctx context.Context, userMessage string) ([]ChatEvent, error) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+func (s *Session) ChatWithEvents(ctx context.Context, userMessage string) ([]ChatEvent, error) {
+	return s.ChatStream(ctx, userMessage, nil)
+}
 
-	if a.provider == nil {
-		return nil, fmt.Errorf("agent provider not initialized")
+// ChatStream is ChatWithEvents that also passes each event to onEvent as
+// it happens, so a UI can show tools while they run. onEvent is called
+// from the calling goroutine and must not call back into the session.
+func (s *Session) ChatStream(ctx context.Context, userMessage string, onEvent func(ChatEvent)) ([]ChatEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agent
+	if err := s.sync(); err != nil {
+		return nil, err
 	}
+	ctx = withSessionDefaults(ctx, &s.defaults)
+
+	modelID := s.cur.modelFor(TaskPlan)
+	openRouterKey := a.getOpenRouterAPIKey()
 
-	a.conversation = append(a.conversation, llm.Message{
+	tools := a.toolRegistry.GetTools()
+	supportsTools, knownTools := llm.SupportsToolsForModel(ctx, s.cur.provider, modelID, openRouterKey)
+	choice := toolChoiceFrom(ctx)
+	if err := checkToolChoice(choice, tools); err != nil {
+		return nil, err
+	}
+	if choice.Mode == llm.ToolChoiceForce && knownTools && !supportsTools {
+		return nil, fmt.Errorf("can't force %s: %s does not support tools", choice.Name, modelID)
+	}
+	if err := s.checkBudget(modelID); err != nil {
+		return nil, err
+	}
+
+	s.addMessages(llm.Message{
 		Role:    "user",
 		Content: userMessage,
 	})
 
-	a.ensureSession()
-	a.log(sessionRecord{TS: nowTS(), Type: "user", Content: userMessage, Provider: string(a.provider.ID()), Model: a.provider.DefaultModel()})
+	if s.transcript == nil {
+		s.transcript = NewConversation()
+	}
+	s.transcript.AddUserMessage(userMessage)
 
-	modelID := a.provider.DefaultModel()
-	openRouterKey := a.getOpenRouterAPIKey()
+	s.ensureSession()
+	s.startDebugTurn()
+	s.log(sessionRecord{TS: nowTS(), Type: "user", Content: userMessage, Provider: string(s.cur.provider.ID()), Model: s.cur.model})
 
-	tools := a.toolRegistry.GetTools()
-	supportsTools, knownTools := llm.SupportsToolsForModel(ctx, a.provider, modelID, openRouterKey)
 	var events []ChatEvent
-	if knownTools && !supportsTools {
+	emit := func(e ChatEvent) {
+		events = append(events, e)
+		if onEvent != nil {
+			onEvent(e)
+		}
+	}
+	if choice.Mode == llm.ToolChoiceNone {
+		tools = nil
+	} else if knownTools && !supportsTools {
 		tools = nil
-		suggestion := suggestToolModel(a.provider)
-		events = append(events, ChatEvent{
+		suggestion := suggestToolModel(s.cur.provider)
+		emit(ChatEvent{
 			Type:    "content",
 			Content: fmt.Sprintf("Tools disabled for model %s; running without on-chain tools. Switch to a tool-capable model%s for balances/wallet actions.", modelID, suggestion),
 		})
-		a.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: events[len(events)-1].Content, Provider: string(a.provider.ID()), Model: modelID})
+		s.transcript.AddAssistantMessage(events[len(events)-1].Content, nil)
+		s.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: events[len(events)-1].Content, Provider: string(s.cur.provider.ID()), Model: modelID})
 	}
 
+	systemPrompt := a.systemPrompt
+	if s.summary != "" {
+		systemPrompt += "\n\n## Earlier in this conversation\n" + s.summary
+	}
+	if m := a.toolRegistry.describeUserData(); m != "" {
+		systemPrompt += "\n\n" + m
+	}
+	if m := a.toolRegistry.describeSigners(); m != "" {
+		systemPrompt += "\n\n" + m
+	}
+	if d := s.defaults.get(); !d.IsZero() {
+		systemPrompt += "\n\n" + a.toolRegistry.describeDefaults(d)
+	}
+	schema := responseSchemaFrom(ctx)
+	if schema != nil {
+		systemPrompt += "\n\n" + describeAnswerFormat(schema)
+	}
 	req := &llm.ChatRequest{
-		SystemPrompt: a.systemPrompt,
-		Messages:     a.conversation,
+		SystemPrompt: systemPrompt,
+		Messages:     s.conversation,
 		Tools:        tools,
+		Model:        modelID,
+	}
+	if len(tools) > 0 {
+		req.ToolChoice = choice
+	}
+	if schema != nil {
+		req.ResponseSchema = schema.Raw()
 	}
+	s.applySampling(req)
 
-	response, err := a.provider.Chat(ctx, req)
+	start := time.Now()
+	response, err := s.cur.provider.Chat(ctx, req)
+	s.logProviderCall("chat", modelID, start, response, err)
+	s.debugExchange("chat", start, req, nil, nil, response, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get response: %w", err)
 	}
+	s.addUsage(modelID, response.Usage)
 
+	if len(response.ToolCalls) > 0 {
+		next := *req
+		next.Model = s.executeModel(ctx, modelID)
+		// A forced tool applies to the first reply only, or the
+		// model could never answer.
+		next.ToolChoice = llm.ToolChoice{}
+		s.applySampling(&next)
+		req = &next
+	}
 	for len(response.ToolCalls) > 0 {
 		toolCalls := response.ToolCalls
-		toolResults, toolEvents := a.executeToolCallsWithEvents(ctx, toolCalls)
-		events = append(events, toolEvents...)
+		toolResults := s.executeToolCallsInternal(ctx, toolCalls, emit)
+		s.transcript.AddAssistantMessage(response.Content, toolCalls)
+		for _, result := range toolResults {
+			s.transcript.AddToolResult(result)
+		}
 
-		response, err = a.continueWithToolResults(ctx, req, toolCalls, toolResults)
+		response, err = s.continueWithToolResults(ctx, req, toolCalls, toolResults)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if response.Content != "" {
-		a.conversation = append(a.conversation, llm.Message{
+		s.addMessages(llm.Message{
 			Role:    "assistant",
 			Content: response.Content,
 		})
+		s.transcript.AddAssistantMessage(response.Content, nil)
 
-		events = append(events, ChatEvent{
+		emit(ChatEvent{
 			Type:    "content",
 			Content: response.Content,
 		})
-		a.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: response.Content, Provider: string(a.provider.ID()), Model: modelID})
+		s.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: response.Content, Provider: string(s.cur.provider.ID()), Model: modelID})
+	}
+	s.recordContext(response.Usage, tools)
+	if s.switched() {
+		emit(ChatEvent{Type: "notice", Content: "The model or provider changed during this reply; the conversation starts over with your next message."})
+	}
+	if notice := s.budgetNotice(); notice != "" {
+		emit(ChatEvent{Type: "notice", Content: notice})
 	}
 
 	return events, nil
@@ -296,7 +429,9 @@ func suggestToolModel(p llm.Provider) string {
 }
 
 // executeToolCallsInternal runs tool calls with optional event emission.
-func (a *Agent) executeToolCallsInternal(ctx context.Context, toolCalls []llm.ToolCall, emitEvent func(ChatEvent)) []llm.ToolResult {
+func (s *Session) executeToolCallsInternal(ctx context.Context, toolCalls []llm.ToolCall, emitEvent func(ChatEvent)) []llm.ToolResult {
+	a := s.agent
+	provider := string(s.cur.provider.ID())
 	results := make([]llm.ToolResult, len(toolCalls))
 
 	for i, tc := range toolCalls {
@@ -308,9 +443,11 @@ func (a *Agent) executeToolCallsInternal(ctx context.Context, toolCalls []llm.To
 				Args: redactedArgs,
 			})
 		}
-		a.log(sessionRecord{TS: nowTS(), Type: "tool_call", ToolName: tc.Name, Args: redactedArgs, Provider: string(a.provider.ID()), Model: a.provider.DefaultModel()})
+		s.log(sessionRecord{TS: nowTS(), Type: "tool_call", ToolName: tc.Name, Args: redactedArgs, Provider: provider, Model: s.cur.model})
 
+		start := time.Now()
 		out, err := a.toolRegistry.ExecuteTool(ctx, tc.Name, tc.Input)
+		elapsed := time.Since(start)
 		if err != nil {
 			errContent := fmt.Sprintf("Error: %v", err)
 			results[i] = llm.ToolResult{
@@ -324,9 +461,10 @@ func (a *Agent) executeToolCallsInternal(ctx context.Context, toolCalls []llm.To
 					Tool:    tc.Name,
 					Content: errContent,
 					IsError: true,
+					Elapsed: elapsed,
 				})
 			}
-			a.log(sessionRecord{TS: nowTS(), Type: "tool_result", ToolName: tc.Name, Text: errContent, IsError: true, Provider: string(a.provider.ID()), Model: a.provider.DefaultModel()})
+			s.log(sessionRecord{TS: nowTS(), Type: "tool_result", ToolName: tc.Name, Text: errContent, IsError: true, Provider: provider, Model: s.cur.model})
 		} else {
 			results[i] = llm.ToolResult{
 				ToolUseID: tc.ID,
@@ -335,79 +473,201 @@ func (a *Agent) executeToolCallsInternal(ctx context.Context, toolCalls []llm.To
 			}
 			if emitEvent != nil {
 				emitEvent(ChatEvent{
-					Type:    "tool_result",
-					Tool:    tc.Name,
-					Content: out.Text,
-					Blocks:  out.Blocks,
-					IsError: false,
+					Type:      "tool_result",
+					Tool:      tc.Name,
+					Content:   out.Text,
+					Blocks:    out.Blocks,
+					IsError:   false,
+					Elapsed:   elapsed,
+					Confirmed: out.Confirmed,
 				})
 			}
-			a.log(sessionRecord{TS: nowTS(), Type: "tool_result", ToolName: tc.Name, Text: out.Text, Blocks: out.Blocks, IsError: false, Provider: string(a.provider.ID()), Model: a.provider.DefaultModel()})
+			s.log(sessionRecord{TS: nowTS(), Type: "tool_result", ToolName: tc.Name, Text: out.Text, Blocks: out.Blocks, IsError: false, Provider: provider, Model: s.cur.model})
 		}
 	}
 	return results
 }
 
-// executeToolCallsWithEvents runs all tool calls and returns results with events for UI.
-func (a *Agent) executeToolCallsWithEvents(ctx context.Context, toolCalls []llm.ToolCall) ([]llm.ToolResult, []ChatEvent) {
-	var events []ChatEvent
-	results := a.executeToolCallsInternal(ctx, toolCalls, func(e ChatEvent) {
-		events = append(events, e)
-	})
-	return results, events
-}
-
 // continueWithToolResults sends tool results to the provider and returns the next response.
-func (a *Agent) continueWithToolResults(ctx context.Context, req *llm.ChatRequest, toolCalls []llm.ToolCall, toolResults []llm.ToolResult) (*llm.ChatResponse, error) {
-	response, err := a.provider.ChatWithToolResults(ctx, req, toolCalls, toolResults)
+func (s *Session) continueWithToolResults(ctx context.Context, req *llm.ChatRequest, toolCalls []llm.ToolCall, toolResults []llm.ToolResult) (*llm.ChatResponse, error) {
+	if err := s.checkBudget(req.Model); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	response, err := s.cur.provider.ChatWithToolResults(ctx, req, toolCalls, toolResults)
+	s.logProviderCall("chat_with_tool_results", req.Model, start, response, err)
+	s.debugExchange("chat_with_tool_results", start, req, toolCalls, toolResults, response, err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to continue conversation: %w", err)
 	}
+	s.addUsage(req.Model, response.Usage)
 	return response, nil
 }
 
+// logProviderCall records the outcome of one LLM request. Message contents
+// are left out: they can hold addresses and balances the user typed.
+func (s *Session) logProviderCall(call, model string, start time.Time, resp *llm.ChatResponse, err error) {
+	attrs := []any{
+		"call", call,
+		"provider", s.cur.provider.ID(),
+		"model", model,
+		"duration", time.Since(start).Round(time.Millisecond),
+	}
+	if err != nil {
+		slog.Error("llm request failed", append(attrs, "err", err)...)
+		return
+	}
+	slog.Debug("llm request", append(attrs,
+		"input_tokens", resp.Usage.InputTokens,
+		"output_tokens", resp.Usage.OutputTokens,
+		"tool_calls", len(resp.ToolCalls),
+	)...)
+}
+
+// Tools returns the agent's tool registry.
+func (a *Agent) Tools() *ToolRegistry {
+	return a.toolRegistry
+}
+
 // GetProvider returns the current provider
 func (a *Agent) GetProvider() llm.Provider {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.provider
 }
 
 // SetModel switches the active model on the current provider.
-// Clears conversation history since prior messages may be incompatible.
+// Clears conversation history since prior messages may be incompatible;
+// other sessions start over on their next turn.
 func (a *Agent) SetModel(modelID string) error {
+	a.main.mu.Lock()
+	defer a.main.mu.Unlock()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	if err := a.provider.SetModel(modelID); err != nil {
 		return err
 	}
-	a.conversation = make([]llm.Message, 0)
-	a.rotateSession()
+	a.generation++
+	a.main.catchUp()
 	return nil
 }
 
 // CurrentModel returns the active model ID for the current provider.
 func (a *Agent) CurrentModel() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.provider.DefaultModel()
 }
 
 // ListModels returns the available models for the current provider.
 func (a *Agent) ListModels() []llm.Model {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.provider.Models()
 }
 
 // ProviderName returns the human-readable name of the current provider.
 func (a *Agent) ProviderName() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.provider.Name()
 }
 
 // CurrentProviderID returns the provider identifier for the active provider.
 func (a *Agent) CurrentProviderID() llm.ProviderID {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.provider.ID()
 }
 
-// SetProvider switches to a new provider and clears conversation history.
-// If initialization fails, the current provider remains unchanged.
+// ToolsEnabled reports whether the current model gets on-chain tools.
+// known is false when the model's tool support could not be determined;
+// tools are then sent anyway.
+func (a *Agent) ToolsEnabled(ctx context.Context) (enabled, known bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	supports, known := llm.SupportsToolsForModel(ctx, a.provider, a.provider.DefaultModel(), a.getOpenRouterAPIKey())
+	return supports || !known, known
+}
+
+// RunTool runs a tool directly, outside the conversation, for commands
+// that don't need the model. input is marshalled to JSON.
+func (s *Session) RunTool(ctx context.Context, name string, input any) (ToolOutput, error) {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid tool input: %w", err)
+	}
+	return s.agent.toolRegistry.ExecuteTool(withSessionDefaults(ctx, &s.defaults), name, raw)
+}
+
+// ConnectedChains returns the EVM chains with an open RPC connection.
+func (a *Agent) ConnectedChains() []string {
+	return a.toolRegistry.ConnectedChains()
+}
+
+// ThrottleStats reports how often RPC requests queued for the rate
+// limiter, per host.
+func (a *Agent) ThrottleStats() map[string]chain.ThrottleStats {
+	return a.toolRegistry.ThrottleStats()
+}
+
+// PolicySummary describes the transaction policy from the environment.
+func (a *Agent) PolicySummary() string {
+	return loadPolicy().Summary()
+}
+
+// Usage returns the tokens used since the agent started, across sessions.
+func (a *Agent) Usage() llm.Usage {
+	return llm.Usage{
+		InputTokens:  int(a.inputTokens.Load()),
+		OutputTokens: int(a.outputTokens.Load()),
+	}
+}
+
+func (s *Session) addUsage(model string, u llm.Usage) {
+	a := s.agent
+	a.inputTokens.Add(int64(u.InputTokens))
+	a.outputTokens.Add(int64(u.OutputTokens))
+	a.recordSpend(s.cur, model, u)
+}
+
+// ErrNoTurn is returned by UndoLastTurn when nothing has been sent yet.
+var ErrNoTurn = errors.New("no previous message in this conversation")
+
+// UndoLastTurn removes the last user message and the replies to it, so it
+// can be sent again as is or edited. It returns the removed message.
+func (s *Session) UndoLastTurn() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := -1
+	for i := len(s.conversation) - 1; i >= 0; i-- {
+		if s.conversation[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return "", ErrNoTurn
+	}
+	content := s.conversation[last].Content
+	s.conversation = s.conversation[:last]
+	if s.transcript != nil {
+		s.transcript.TruncateLastTurn()
+	}
+	s.dropHistoryTurn()
+	s.recordContext(llm.Usage{}, s.agent.toolRegistry.GetTools())
+	s.log(sessionRecord{TS: nowTS(), Type: "undo", Content: content})
+	return content, nil
+}
+
+// SetProvider switches to a new provider and clears conversation history;
+// other sessions start over on their next turn. If initialization fails,
+// the current provider remains unchanged.
 func (a *Agent) SetProvider(providerID llm.ProviderID) error {
+	a.main.mu.Lock()
+	defer a.main.mu.Unlock()
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -422,56 +682,70 @@ func (a *Agent) SetProvider(providerID llm.ProviderID) error {
 	}
 
 	a.provider = newProvider
-	a.conversation = make([]llm.Message, 0)
-	a.rotateSession()
+	a.generation++
+	a.main.catchUp()
 	return nil
 }
 
-// Reset clears the conversation history. Safe to call concurrently with Chat().
-func (a *Agent) Reset() {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.conversation = make([]llm.Message, 0)
-	a.rotateSession()
+// Reset clears the conversation history. Called during a turn, it waits
+// for the turn to finish.
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	s.plan = nil
+	s.defaults.set(SessionDefaults{})
+}
+
+// Export returns a copy of the conversation for sharing: tool call arguments
+// are redacted and stored receipts for its transactions are attached.
+func (s *Session) Export() *Conversation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.transcript == nil {
+		return NewConversation()
+	}
+	out := &Conversation{ID: s.transcript.ID, StartedAt: s.transcript.StartedAt}
+	for _, turn := range s.transcript.Turns {
+		if len(turn.ToolCalls) > 0 {
+			calls := make([]llm.ToolCall, len(turn.ToolCalls))
+			for i, tc := range turn.ToolCalls {
+				calls[i] = tc
+				calls[i].Input = nil
+				if redacted := RedactJSONArgs(string(tc.Input)); json.Valid([]byte(redacted)) {
+					calls[i].Input = json.RawMessage(redacted)
+				}
+			}
+			turn.ToolCalls = calls
+		}
+		out.Turns = append(out.Turns, turn)
+	}
+	if tr := s.agent.toolRegistry; tr != nil {
+		out.Receipts = tr.conversationReceipts(out.Turns)
+	}
+	return out
 }
 
-// Close cleans up agent resources
+// Close cleans up agent resources, its sessions' included.
 func (a *Agent) Close() {
 	if a.toolRegistry != nil {
 		a.toolRegistry.Close()
 	}
-	if a.logger != nil {
-		a.logger.Close()
+	a.sessionsMu.Lock()
+	sessions := make([]*Session, 0, len(a.sessions))
+	for s := range a.sessions {
+		sessions = append(sessions, s)
+	}
+	a.sessionsMu.Unlock()
+	for _, s := range sessions {
+		s.Close()
+	}
+	if a.main != nil {
+		a.main.Close()
 	}
 	// Close Gemini client if applicable
 	if gemini, ok := a.provider.(*llm.GeminiProvider); ok {
 		_ = gemini.Close()
 	}
 }
-
-func (a *Agent) ensureSession() {
-	if a.sessionID != "" {
-		return
-	}
-	a.rotateSession()
-}
-
-func (a *Agent) rotateSession() {
-	if a.logger != nil {
-		a.logger.Close()
-		a.logger = nil
-	}
-
-	a.sessionID = time.Now().UTC().Format("20060102-150405.000000000")
-	l, err := newSessionLogger(a.dataDir, a.sessionID)
-	if err == nil {
-		a.logger = l
-	}
-}
-
-func (a *Agent) log(rec sessionRecord) {
-	if a.logger == nil {
-		return
-	}
-	a.logger.logRecord(rec)
-}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,12 +47,13 @@ func (p *testProvider) SetModel(modelID string) error {
 }
 
 func newTestAgent() *Agent {
-	return &Agent{
+	a := &Agent{
 		provider:     newTestProvider(),
 		toolRegistry: NewToolRegistry(),
 		systemPrompt: "test",
-		conversation: make([]llm.Message, 0),
 	}
+	a.main = newSession(a)
+	return a
 }
 
 func TestAgent_CurrentModel(t *testing.T) {
@@ -98,26 +100,184 @@ func TestAgent_SetModel(t *testing.T) {
 
 	t.Run("clears conversation on switch", func(t *testing.T) {
 		ag := newTestAgent()
-		ag.conversation = append(ag.conversation, llm.Message{
+		ag.main.conversation = append(ag.main.conversation, llm.Message{
 			Role:    "user",
 			Content: "hello",
 		})
-		require.Len(t, ag.conversation, 1)
+		require.Len(t, ag.main.conversation, 1)
 
 		err := ag.SetModel("test-model-b")
 		require.NoError(t, err)
-		assert.Empty(t, ag.conversation)
+		assert.Empty(t, ag.main.conversation)
 	})
 
 	t.Run("does not clear conversation on failed switch", func(t *testing.T) {
 		ag := newTestAgent()
-		ag.conversation = append(ag.conversation, llm.Message{
+		ag.main.conversation = append(ag.main.conversation, llm.Message{
 			Role:    "user",
 			Content: "hello",
 		})
 
 		err := ag.SetModel("nonexistent")
 		require.Error(t, err)
-		assert.Len(t, ag.conversation, 1)
+		assert.Len(t, ag.main.conversation, 1)
 	})
 }
+
+// toolCallProvider asks for one tool call, then answers.
+type toolCallProvider struct {
+	testProvider
+	call llm.ToolCall
+}
+
+func (p *toolCallProvider) Chat(_ context.Context, _ *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return &llm.ChatResponse{ToolCalls: []llm.ToolCall{p.call}, Usage: llm.Usage{InputTokens: 120, OutputTokens: 15}}, nil
+}
+
+func (p *toolCallProvider) ChatWithToolResults(_ context.Context, _ *llm.ChatRequest, _ []llm.ToolCall, _ []llm.ToolResult) (*llm.ChatResponse, error) {
+	return &llm.ChatResponse{Content: "ok", Usage: llm.Usage{InputTokens: 200, OutputTokens: 40}}, nil
+}
+
+func TestAgent_Export(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+	ag.provider = &toolCallProvider{
+		testProvider: *newTestProvider(),
+		call:         llm.ToolCall{ID: "tc_1", Name: "get_gas_price", Input: json.RawMessage(`{"chain":"nonexistent","password":"hunter2"}`)},
+	}
+
+	_, err := ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+
+	conv := ag.Export()
+	require.Len(t, conv.Turns, 4) // user, tool call, tool result, answer
+	assert.Equal(t, "user", conv.Turns[0].Role)
+	require.Len(t, conv.Turns[1].ToolCalls, 1)
+	assert.NotContains(t, string(conv.Turns[1].ToolCalls[0].Input), "hunter2")
+	require.NotNil(t, conv.Turns[2].ToolResult)
+	assert.True(t, conv.Turns[2].ToolResult.IsError)
+	assert.Equal(t, "ok", conv.Turns[3].Content)
+
+	_, err = conv.ToJSON()
+	require.NoError(t, err)
+
+	ag.Reset()
+	assert.Empty(t, ag.Export().Turns)
+}
+
+func TestAgent_Usage(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+	ag.provider = &toolCallProvider{
+		testProvider: *newTestProvider(),
+		call:         llm.ToolCall{ID: "tc_1", Name: "get_gas_price", Input: json.RawMessage(`{"chain":"nonexistent"}`)},
+	}
+
+	_, err := ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+	assert.Equal(t, llm.Usage{InputTokens: 320, OutputTokens: 55}, ag.Usage())
+
+	// Usage covers the whole session, not just the current conversation.
+	ag.Reset()
+	assert.Equal(t, 320, ag.Usage().InputTokens)
+}
+
+func TestAgent_RunTool(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+
+	out, err := ag.RunTool(context.Background(), "list_chains", map[string]any{})
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "ethereum")
+	assert.Empty(t, ag.main.conversation, "direct runs stay out of the conversation")
+
+	_, err = ag.RunTool(context.Background(), "get_balances", map[string]any{"address": "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "chains": []string{"nochain"}})
+	assert.ErrorContains(t, err, "unknown chain: nochain")
+
+	_, err = ag.RunTool(context.Background(), "no_such_tool", nil)
+	assert.ErrorContains(t, err, "unknown tool")
+}
+
+func TestAgent_ChatStream(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+	ag.provider = &toolCallProvider{
+		testProvider: *newTestProvider(),
+		call:         llm.ToolCall{ID: "tc_1", Name: "list_chains", Input: json.RawMessage(`{}`)},
+	}
+
+	var streamed []ChatEvent
+	events, err := ag.ChatStream(context.Background(), "chains?", func(e ChatEvent) {
+		streamed = append(streamed, e)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, events, streamed)
+
+	require.Len(t, streamed, 3)
+	assert.Equal(t, "tool_call", streamed[0].Type)
+	assert.Equal(t, "tool_result", streamed[1].Type)
+	assert.Equal(t, "list_chains", streamed[1].Tool)
+	assert.Positive(t, streamed[1].Elapsed)
+	assert.Equal(t, "content", streamed[2].Type)
+}
+
+func TestAgent_ContextUsage(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+	p := &toolCallProvider{
+		testProvider: *newTestProvider(),
+		call:         llm.ToolCall{ID: "tc_1", Name: "get_gas_price", Input: json.RawMessage(`{"chain":"nonexistent"}`)},
+	}
+	p.models[0].ContextWindow = 1000
+	ag.provider = p
+
+	before := ag.ContextUsage()
+	assert.True(t, before.Estimated)
+	assert.Positive(t, before.Tokens, "system prompt and tools count before the first request")
+	assert.Equal(t, 1000, before.Window)
+
+	_, err := ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+	after := ag.ContextUsage()
+	assert.Equal(t, ContextUsage{Tokens: 240, Window: 1000}, after, "latest request's prompt plus reply")
+	assert.InDelta(t, 0.24, after.Fraction(), 1e-9)
+
+	ag.Reset()
+	assert.Equal(t, before, ag.ContextUsage())
+
+	// Providers that don't report usage are estimated.
+	ag.provider = newTestProvider()
+	_, err = ag.ChatWithEvents(context.Background(), "hello")
+	require.NoError(t, err)
+	est := ag.ContextUsage()
+	assert.True(t, est.Estimated)
+	assert.Greater(t, est.Tokens, before.Tokens)
+	assert.Zero(t, est.Fraction(), "unknown window")
+}
+
+func TestAgent_UndoLastTurn(t *testing.T) {
+	ag := newTestAgent()
+	defer ag.toolRegistry.Close()
+
+	_, err := ag.UndoLastTurn()
+	require.ErrorIs(t, err, ErrNoTurn)
+
+	_, err = ag.Chat(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = ag.Chat(context.Background(), "second")
+	require.NoError(t, err)
+	require.Len(t, ag.main.conversation, 4)
+
+	msg, err := ag.UndoLastTurn()
+	require.NoError(t, err)
+	assert.Equal(t, "second", msg)
+	assert.Equal(t, []llm.Message{{Role: "user", Content: "first"}, {Role: "assistant", Content: "ok"}}, ag.main.conversation)
+	assert.Equal(t, ag.main.conversation, ag.Export().ToMessages(), "transcript matches the conversation")
+
+	// A failed request leaves only the user message behind.
+	ag.main.conversation = append(ag.main.conversation, llm.Message{Role: "user", Content: "unanswered"})
+	msg, err = ag.UndoLastTurn()
+	require.NoError(t, err)
+	assert.Equal(t, "unanswered", msg)
+	assert.Len(t, ag.main.conversation, 2)
+}
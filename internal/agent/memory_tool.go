@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/memory"
+)
+
+type rememberInput struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Forget bool   `json:"forget"`
+}
+
+func (tr *ToolRegistry) handleRemember(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params rememberInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("memory needs a data directory")
+	}
+	store := memory.NewStore(tr.dataDir)
+
+	if params.Forget {
+		key := memory.NormalizeKey(params.Key)
+		ok, err := store.Remove(key)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		if !ok {
+			return ToolOutput{}, fmt.Errorf("nothing remembered under %q", key)
+		}
+		return ToolOutput{Text: fmt.Sprintf("Forgot %q.", key)}, nil
+	}
+	e, err := store.Set(params.Key, params.Value)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	return ToolOutput{Text: fmt.Sprintf("Remembered %s: %s. It persists across sessions; `clifi memory list` shows everything remembered.", e.Key, e.Value)}, nil
+}
+
+type recallInput struct {
+	Query string `json:"query"`
+}
+
+func (tr *ToolRegistry) handleRecall(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params recallInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("memory needs a data directory")
+	}
+	entries, err := memory.NewStore(tr.dataDir).Search(params.Query)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(entries) == 0 {
+		if params.Query != "" {
+			return ToolOutput{Text: fmt.Sprintf("Nothing remembered matching %q.", params.Query)}, nil
+		}
+		return ToolOutput{Text: "Nothing remembered yet."}, nil
+	}
+	items := make([]KVItem, len(entries))
+	for i, e := range entries {
+		items[i] = KVItem{Key: e.Key, Value: e.Value}
+	}
+	return ToolOutput{Text: formatMemory(entries), Blocks: []UIBlock{kvBlock("Memory", items...)}}, nil
+}
+
+func formatMemory(entries []memory.Entry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- %s: %s\n", e.Key, e.Value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// describeMemory is the system prompt section listing what the user asked
+// to be remembered, so preferences apply without a recall first.
+func (tr *ToolRegistry) describeMemory() string {
+	if tr.dataDir == "" {
+		return ""
+	}
+	entries, err := memory.NewStore(tr.dataDir).List()
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	return "## Remembered about the user\nNotes saved with the remember tool in earlier sessions. Follow stated preferences unless the user says otherwise; update or forget entries that turn out wrong.\n" + formatMemory(entries)
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/memory"
+)
+
+func TestRememberRecall(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "remember", json.RawMessage(`{"key":"Gas Preference","value":"always use fast gas"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Remembered gas-preference: always use fast gas")
+	_, err = tr.ExecuteTool(ctx, "remember", json.RawMessage(`{"key":"alice","value":"0x1111111111111111111111111111111111111111"}`))
+	require.NoError(t, err)
+
+	out, err = tr.ExecuteTool(ctx, "recall", json.RawMessage(`{"query":"gas"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "- gas-preference: always use fast gas", out.Text)
+	out, err = tr.ExecuteTool(ctx, "recall", json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "- alice: 0x1111")
+
+	_, err = tr.ExecuteTool(ctx, "remember", json.RawMessage(`{"key":"alice","forget":true}`))
+	require.NoError(t, err)
+	_, err = tr.ExecuteTool(ctx, "remember", json.RawMessage(`{"key":"alice","forget":true}`))
+	assert.Error(t, err)
+	_, err = tr.ExecuteTool(ctx, "remember", json.RawMessage(`{"key":"pk","value":"0x`+strings.Repeat("ab", 32)+`"}`))
+	assert.ErrorIs(t, err, memory.ErrSecret)
+}
+
+func TestAgent_MemoryInPrompt(t *testing.T) {
+	dataDir := t.TempDir()
+	_, err := memory.NewStore(dataDir).Set("gas-preference", "always use fast gas")
+	require.NoError(t, err)
+
+	provider := llm.NewMockProvider()
+	a := NewWithProvider(provider, dataDir)
+	defer a.Close()
+	_, err = a.Chat(context.Background(), "hi")
+	require.NoError(t, err)
+	reqs := provider.Requests()
+	require.NotEmpty(t, reqs)
+	assert.Contains(t, reqs[0].SystemPrompt, "## Remembered about the user\n")
+	assert.Contains(t, reqs[0].SystemPrompt, "- gas-preference: always use fast gas")
+}
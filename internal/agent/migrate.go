@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReceiptDBFile is the receipt DB's name in the data dir.
+const ReceiptDBFile = "receipts.db"
+
+// migrationFiles holds the receipt DB's schema changes, NNNN_name.sql
+// numbered from 1. A DB records the last one applied in its user_version.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one numbered change to the receipt DB's schema.
+type Migration struct {
+	Version int
+	Name    string
+	sql     string
+}
+
+var migrations = loadMigrations()
+
+// loadMigrations reads the embedded migrations in order. A misnamed or
+// missing file is a build mistake, so it panics.
+func loadMigrations() []Migration {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		panic(err)
+	}
+	var out []Migration
+	for _, e := range entries {
+		num, name, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".sql"), "_")
+		v, err := strconv.Atoi(num)
+		if !ok || err != nil {
+			panic(fmt.Sprintf("migration %s: want NNNN_name.sql", e.Name()))
+		}
+		if v != len(out)+1 {
+			panic(fmt.Sprintf("migration %s: want version %d", e.Name(), len(out)+1))
+		}
+		b, err := migrationFiles.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		out = append(out, Migration{Version: v, Name: name, sql: string(b)})
+	}
+	return out
+}
+
+// SchemaVersion is the receipt DB schema version this build migrates to.
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// dbFilePath returns the file a sqlite DSN opens, or "" for in-memory and
+// URI DSNs, which are never backed up.
+func dbFilePath(dsn string) string {
+	if dsn == ":memory:" || strings.HasPrefix(dsn, "file:") {
+		return ""
+	}
+	return dsn
+}
+
+func userVersion(db *sql.DB) (int, error) {
+	var v int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("read receipts db schema version: %w", err)
+	}
+	return v, nil
+}
+
+func errSchemaTooNew(version int) error {
+	return fmt.Errorf("receipts db is at schema version %d, newer than this clifi's %d; upgrade clifi", version, SchemaVersion())
+}
+
+// migrate applies db's pending migrations, each in its own transaction.
+// An existing DB at path is first copied to path.vN.bak, N being its
+// version before; migrate returns the copy's path.
+func migrate(db *sql.DB, path string) (string, error) {
+	current, err := userVersion(db)
+	if err != nil {
+		return "", err
+	}
+	if current > SchemaVersion() {
+		return "", errSchemaTooNew(current)
+	}
+	if current == SchemaVersion() {
+		return "", nil
+	}
+
+	var backup string
+	if path != "" {
+		var objects int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master`).Scan(&objects); err != nil {
+			return "", fmt.Errorf("read receipts db schema: %w", err)
+		}
+		if objects > 0 {
+			backup = fmt.Sprintf("%s.v%d.bak", path, current)
+			// VACUUM INTO refuses to overwrite.
+			if err := os.Remove(backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("back up receipts db: %w", err)
+			}
+			if _, err := db.Exec(`VACUUM INTO ?`, backup); err != nil {
+				return "", fmt.Errorf("back up receipts db: %w", err)
+			}
+		}
+	}
+
+	for _, m := range migrations[current:] {
+		if err := applyMigration(db, m); err != nil {
+			return backup, err
+		}
+	}
+	return backup, nil
+}
+
+func applyMigration(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("migrate receipts db to version %d: %w", m.Version, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(m.sql); err != nil {
+		return fmt.Errorf("migrate receipts db to version %d (%s): %w", m.Version, m.Name, err)
+	}
+	// PRAGMA takes no parameters.
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, m.Version)); err != nil {
+		return fmt.Errorf("migrate receipts db to version %d: %w", m.Version, err)
+	}
+	return tx.Commit()
+}
+
+// MigrationPlan is a receipt DB's schema version and the migrations it
+// lacks.
+type MigrationPlan struct {
+	Path    string
+	Exists  bool
+	Current int
+	Pending []Migration
+}
+
+// PlanMigrations reports what migrating the receipt DB under dataDir
+// would do, without changing it.
+func PlanMigrations(dataDir string) (MigrationPlan, error) {
+	p := MigrationPlan{Path: filepath.Join(dataDir, ReceiptDBFile)}
+	if _, err := os.Stat(p.Path); errors.Is(err, os.ErrNotExist) {
+		p.Pending = migrations
+		return p, nil
+	}
+	p.Exists = true
+
+	db, err := sql.Open("sqlite", "file:"+p.Path+"?mode=ro")
+	if err != nil {
+		return p, fmt.Errorf("open receipts db: %w", err)
+	}
+	defer db.Close()
+	if p.Current, err = userVersion(db); err != nil {
+		return p, err
+	}
+	if p.Current > SchemaVersion() {
+		return p, errSchemaTooNew(p.Current)
+	}
+	p.Pending = migrations[p.Current:]
+	return p, nil
+}
+
+// MigrateReceiptDB applies the pending migrations to the receipt DB under
+// dataDir, creating it if needed, as opening it would. It returns the
+// backup taken first, if any.
+func MigrateReceiptDB(dataDir string) (string, error) {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return "", err
+	}
+	s, backup, err := openReceiptStore(filepath.Join(dataDir, ReceiptDBFile))
+	if err != nil {
+		return backup, err
+	}
+	return backup, s.Close()
+}
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_FreshDB(t *testing.T) {
+	dir := t.TempDir()
+	plan, err := PlanMigrations(dir)
+	require.NoError(t, err)
+	assert.False(t, plan.Exists)
+	assert.Len(t, plan.Pending, SchemaVersion())
+
+	backup, err := MigrateReceiptDB(dir)
+	require.NoError(t, err)
+	assert.Empty(t, backup, "nothing to back up")
+
+	plan, err = PlanMigrations(dir)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion(), plan.Current)
+	assert.Empty(t, plan.Pending)
+}
+
+func TestMigrate_BacksUpExistingDB(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ReceiptDBFile)
+
+	// A DB from before migrations: the first tables, version 0.
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(migrations[0].sql)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO llm_usage VALUES ('2026-10-01', 'openai', 'gpt-4o', 1, 10, 5, 0.01)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	plan, err := PlanMigrations(dir)
+	require.NoError(t, err)
+	assert.True(t, plan.Exists)
+	assert.Equal(t, 0, plan.Current)
+	assert.Len(t, plan.Pending, SchemaVersion())
+
+	rs, err := OpenReceiptStore(dir)
+	require.NoError(t, err)
+	rows, err := rs.LLMUsage("2026-10")
+	require.NoError(t, err)
+	assert.Len(t, rows, 1, "data survives")
+	_, err = rs.AddChatMessage("s", "user", "hello", time.Now())
+	require.NoError(t, err, "later tables exist")
+	require.NoError(t, rs.Close())
+
+	_, err = os.Stat(path + ".v0.bak")
+	assert.NoError(t, err)
+}
+
+func TestMigrate_RefusesNewerSchema(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ReceiptDBFile)
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`PRAGMA user_version = 999`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = OpenReceiptStore(dir)
+	assert.ErrorContains(t, err, "upgrade clifi")
+	_, err = PlanMigrations(dir)
+	assert.ErrorContains(t, err, "upgrade clifi")
+}
+
+func TestMigrate_SentStatusFromReceipts(t *testing.T) {
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(dir, ReceiptDBFile))
+	require.NoError(t, err)
+	_, err = db.Exec(migrations[0].sql)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+INSERT INTO sent_txs VALUES ('base', '0xaa', '0x01', 1, '{}', '', 100), ('base', '0xbb', '0x01', 2, '{}', '', 200);
+INSERT INTO receipts (chain, tx_hash, status, gas_used, raw_json) VALUES ('base', '0xAA', 1, 21000, '{}');
+`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	rs, err := OpenReceiptStore(dir)
+	require.NoError(t, err)
+	defer rs.Close()
+	rows, err := rs.db.Query(`SELECT tx_hash, status FROM sent_txs ORDER BY nonce`)
+	require.NoError(t, err)
+	defer rows.Close()
+	got := map[string]string{}
+	for rows.Next() {
+		var hash, status string
+		require.NoError(t, rows.Scan(&hash, &status))
+		got[hash] = status
+	}
+	assert.Equal(t, map[string]string{"0xaa": SentConfirmed, "0xbb": SentPending}, got)
+}
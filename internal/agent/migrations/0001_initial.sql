@@ -0,0 +1,74 @@
+-- The receipt DB as it was before migrations: receipts, token metadata,
+-- the P&L ledger, what the gas report needs beyond the receipts, sent
+-- transactions and LLM usage. IF NOT EXISTS, since DBs from then already
+-- have these tables.
+
+CREATE TABLE IF NOT EXISTS receipts (
+	chain TEXT NOT NULL,
+	tx_hash TEXT NOT NULL,
+	status INTEGER,
+	gas_used INTEGER,
+	raw_json TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (chain, tx_hash)
+);
+
+CREATE TABLE IF NOT EXISTS token_metadata (
+	chain TEXT NOT NULL,
+	address TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	name TEXT NOT NULL,
+	decimals INTEGER NOT NULL,
+	PRIMARY KEY (chain, address)
+);
+
+CREATE TABLE IF NOT EXISTS ledger (
+	chain TEXT NOT NULL,
+	tx_hash TEXT NOT NULL,
+	address TEXT NOT NULL,
+	idx INTEGER NOT NULL,
+	symbol TEXT NOT NULL,
+	token TEXT NOT NULL,
+	amount REAL NOT NULL,
+	price_usd REAL NOT NULL,
+	kind TEXT NOT NULL,
+	at INTEGER NOT NULL,
+	PRIMARY KEY (chain, tx_hash, address, idx)
+);
+
+-- gas_context caches what a receipt lacks for the gas report: the
+-- sender, and the block's time and base fee ('' when it has none).
+CREATE TABLE IF NOT EXISTS gas_context (
+	chain TEXT NOT NULL,
+	tx_hash TEXT NOT NULL,
+	sender TEXT NOT NULL,
+	block_time INTEGER NOT NULL,
+	base_fee TEXT NOT NULL,
+	PRIMARY KEY (chain, tx_hash)
+);
+
+-- sent_txs remembers every transaction clifi broadcast, so one stuck
+-- in (or dropped from) a mempool can still be found by its nonce.
+CREATE TABLE IF NOT EXISTS sent_txs (
+	chain TEXT NOT NULL,
+	tx_hash TEXT NOT NULL,
+	sender TEXT NOT NULL,
+	nonce INTEGER NOT NULL,
+	raw_json TEXT NOT NULL,
+	relay TEXT NOT NULL,
+	sent_at INTEGER NOT NULL,
+	PRIMARY KEY (chain, tx_hash)
+);
+
+-- llm_usage totals LLM requests per local day, provider and model;
+-- see llm_usage.go.
+CREATE TABLE IF NOT EXISTS llm_usage (
+	day TEXT NOT NULL,
+	provider TEXT NOT NULL,
+	model TEXT NOT NULL,
+	calls INTEGER NOT NULL,
+	input_tokens INTEGER NOT NULL,
+	output_tokens INTEGER NOT NULL,
+	cost_usd REAL NOT NULL,
+	PRIMARY KEY (day, provider, model)
+);
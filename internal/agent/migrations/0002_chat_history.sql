@@ -0,0 +1,27 @@
+-- chat_messages keeps every conversation's messages, indexed for search
+-- by chat_fts; chat_sessions links a resumed conversation to the message
+-- it picked up from. See chat_history.go.
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	session TEXT NOT NULL,
+	role TEXT NOT NULL,
+	content TEXT NOT NULL,
+	at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS chat_messages_session ON chat_messages (session, id);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS chat_fts USING fts5(
+	content, content='chat_messages', content_rowid='id', tokenize='porter unicode61'
+);
+CREATE TRIGGER IF NOT EXISTS chat_messages_insert AFTER INSERT ON chat_messages BEGIN
+	INSERT INTO chat_fts (rowid, content) VALUES (new.id, new.content);
+END;
+CREATE TRIGGER IF NOT EXISTS chat_messages_delete AFTER DELETE ON chat_messages BEGIN
+	INSERT INTO chat_fts (chat_fts, rowid, content) VALUES ('delete', old.id, old.content);
+END;
+
+CREATE TABLE IF NOT EXISTS chat_sessions (
+	session TEXT PRIMARY KEY,
+	resumed_from INTEGER NOT NULL
+);
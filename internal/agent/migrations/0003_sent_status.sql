@@ -0,0 +1,13 @@
+-- sent_txs.status follows a broadcast transaction until it is mined
+-- (confirmed or failed) or another transaction takes its nonce
+-- (dropped), so one still pending when clifi exited is checked later.
+
+ALTER TABLE sent_txs ADD COLUMN status TEXT NOT NULL DEFAULT 'pending';
+
+UPDATE sent_txs SET status = (
+	SELECT CASE r.status WHEN 1 THEN 'confirmed' ELSE 'failed' END
+	FROM receipts r WHERE r.chain = sent_txs.chain AND lower(r.tx_hash) = sent_txs.tx_hash
+)
+WHERE EXISTS (SELECT 1 FROM receipts r WHERE r.chain = sent_txs.chain AND lower(r.tx_hash) = sent_txs.tx_hash);
+
+CREATE INDEX IF NOT EXISTS sent_txs_pending ON sent_txs (status) WHERE status = 'pending';
@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/notes"
+)
+
+const (
+	defaultNotesLimit = 5
+	maxNotesLimit     = 10
+)
+
+type searchNotesInput struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit"`
+}
+
+func (tr *ToolRegistry) handleSearchNotes(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params searchNotesInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("notes need a data directory")
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultNotesLimit
+	}
+	limit = min(limit, maxNotesLimit)
+
+	lib := notes.Open(tr.dataDir)
+	docs, err := lib.List()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(docs) == 0 {
+		return ToolOutput{Text: "The user has not imported any notes (`clifi notes import <file>`). Don't guess contract addresses; ask the user or say they are unknown."}, nil
+	}
+	results, err := lib.Search(params.Query, limit)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(results) == 0 {
+		return ToolOutput{Text: fmt.Sprintf("No notes match %q. Don't guess contract addresses; ask the user or say they are unknown.", params.Query)}, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Notes matching %q, best first. Quote addresses exactly as written here:\n", params.Query)
+	for _, r := range results {
+		source := r.Doc
+		if r.Section != "" {
+			source += " > " + r.Section
+		}
+		fmt.Fprintf(&b, "\n[%s]\n%s\n", source, r.Text)
+	}
+	return ToolOutput{Text: strings.TrimSuffix(b.String(), "\n")}, nil
+}
+
+// describeNotes tells the model that imported notes exist, so it searches
+// them before answering from what it recalls.
+func (tr *ToolRegistry) describeNotes() string {
+	if tr.dataDir == "" {
+		return ""
+	}
+	docs, err := notes.Open(tr.dataDir).List()
+	if err != nil || len(docs) == 0 {
+		return ""
+	}
+	names := make([]string, len(docs))
+	for i, d := range docs {
+		names[i] = d.Name
+	}
+	return "## Imported notes\nThe user imported notes on protocols and contracts (" + strings.Join(names, ", ") + "). Call search_notes before stating a contract address or protocol detail they may cover, and prefer them over what you recall."
+}
+
+// describeUserData is the system prompt section for the user's own data:
+// remembered notes and imported documents.
+func (tr *ToolRegistry) describeUserData() string {
+	var parts []string
+	for _, s := range []string{tr.describeMemory(), tr.describeNotes()} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
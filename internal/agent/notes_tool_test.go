@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/notes"
+)
+
+func TestSearchNotes(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "search_notes", json.RawMessage(`{"query":"aave pool"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "has not imported any notes")
+	assert.Empty(t, tr.describeNotes())
+
+	src := filepath.Join(t.TempDir(), "aave.md")
+	require.NoError(t, os.WriteFile(src, []byte("# Aave\n\nPool on base: 0xA238Dd80C259a72e81d7e4664a9801593F98d1c5\n"), 0600))
+	_, err = notes.Open(dir).Import(src)
+	require.NoError(t, err)
+
+	out, err = tr.ExecuteTool(ctx, "search_notes", json.RawMessage(`{"query":"aave pool"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "[aave.md > Aave]\nPool on base: 0xA238Dd80C259a72e81d7e4664a9801593F98d1c5")
+	out, err = tr.ExecuteTool(ctx, "search_notes", json.RawMessage(`{"query":"compound"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "No notes match")
+	assert.Contains(t, tr.describeNotes(), "(aave.md)")
+}
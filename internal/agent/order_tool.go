@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yolodolo42/clifi/internal/orders"
+)
+
+type createLimitOrderInput struct {
+	Order       string  `json:"order"`
+	Chain       string  `json:"chain"`
+	Wallet      string  `json:"wallet"`
+	SlippageBps *uint32 `json:"slippage_bps"`
+	Confirm     bool    `json:"confirm"`
+}
+
+func (tr *ToolRegistry) handleCreateLimitOrder(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params createLimitOrderInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("limit orders need a data directory")
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+
+	o, err := orders.ParseOrder(params.Order)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	o.SetChain(params.Chain)
+	o.SlippageBps = params.SlippageBps
+	// A buy is sized when it triggers; any positive amount checks the pair.
+	s, _ := o.Swap(1, 1)
+	sp, err := tr.resolveSwap(SwapRequest{
+		Chain: o.Chain, From: params.Wallet, FromToken: s.FromToken, ToToken: s.ToToken,
+		Amount: s.Amount, SlippageBps: o.SlippageBps,
+	}, loadPolicy())
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	o.Wallet = sp.from.Hex()
+
+	if !params.Confirm {
+		return ToolOutput{Text: fmt.Sprintf(
+			"Preview limit order:\n- Order: %s\n- Wallet: %s\n- When it triggers: it waits for `clifi order confirm`, or fills on its own if orders.auto_approve is set\n\nSet confirm=true to create it.",
+			o, o.Wallet)}, nil
+	}
+	o, err = orders.NewStore(tr.dataDir).Add(o)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	return ToolOutput{Text: fmt.Sprintf(
+		"Created limit order #%d: %s from %s. Prices are checked while `clifi serve` is running; `clifi order list` shows all orders.",
+		o.ID, o, o.Wallet)}, nil
+}
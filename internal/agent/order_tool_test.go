@@ -0,0 +1,40 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/orders"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+func TestCreateLimitOrder(t *testing.T) {
+	t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+	t.Setenv("CLIFI_MAX_SLIPPAGE_BPS", "")
+	dir := t.TempDir()
+	km, err := wallet.NewKeystoreManager(dir)
+	require.NoError(t, err)
+	_, err = km.CreateAccount("password123")
+	require.NoError(t, err)
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "create_limit_order", json.RawMessage(`{"order":"sell 1 ETH if price hits 4200","chain":"base"}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Preview limit order")
+
+	out, err = tr.ExecuteTool(ctx, "create_limit_order", json.RawMessage(`{"order":"buy 0.5 ETH if price drops to 3000","chain":"base","confirm":true}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Created limit order #1: buy 0.5 ETH with USDC when ETH <= 3000 on base")
+	list, err := orders.NewStore(dir).List()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "base", list[0].Trigger.Chain)
+
+	_, err = tr.ExecuteTool(ctx, "create_limit_order", json.RawMessage(`{"order":"sell 1 ETH if price hits 4200","chain":"sepolia","confirm":true}`))
+	assert.Error(t, err)
+}
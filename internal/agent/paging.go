@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// DefaultToolOutputLimit caps a tool result, in bytes, before it is split
+// into pages: roughly 4k tokens, enough for a wide portfolio without a few
+// results crowding out the conversation.
+const DefaultToolOutputLimit = 16000
+
+// pagerKeep is how many paged results are kept for follow-up page calls.
+const pagerKeep = 8
+
+const pageParamSchema = `{"type": "integer", "minimum": 1, "description": "Page of a result that was split into pages; call again with the same arguments and the next page"}`
+
+// outputPager splits long tool results into pages and keeps recent ones,
+// so a follow-up call with page=N is served from the first run rather than
+// re-running the tool.
+type outputPager struct {
+	mu     sync.Mutex
+	limit  int
+	recent []pagedOutput // oldest first
+}
+
+type pagedOutput struct {
+	key   string
+	pages []string
+}
+
+func newOutputPager() *outputPager {
+	limit := DefaultToolOutputLimit
+	if v := os.Getenv("CLIFI_TOOL_OUTPUT_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	return &outputPager{limit: limit}
+}
+
+// cached returns page of an earlier result for key.
+func (p *outputPager) cached(key string, page int) (string, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(p.recent) - 1; i >= 0; i-- {
+		if p.recent[i].key == key {
+			text, err := pageText(p.recent[i].pages, page)
+			return text, true, err
+		}
+	}
+	return "", false, nil
+}
+
+// paginate returns page of text, storing the pages when there is more than
+// one. A limit of 0 turns paging off.
+func (p *outputPager) paginate(key, text string, page int) (string, error) {
+	if p.limit <= 0 || len(text) <= p.limit {
+		if page > 1 {
+			return "", fmt.Errorf("page %d out of range: the result has 1 page", page)
+		}
+		return text, nil
+	}
+	pages := splitPages(text, p.limit)
+
+	p.mu.Lock()
+	p.recent = append(p.recent, pagedOutput{key: key, pages: pages})
+	if len(p.recent) > pagerKeep {
+		p.recent = p.recent[len(p.recent)-pagerKeep:]
+	}
+	p.mu.Unlock()
+
+	return pageText(pages, page)
+}
+
+func pageText(pages []string, page int) (string, error) {
+	if page < 1 {
+		page = 1
+	}
+	if page > len(pages) {
+		return "", fmt.Errorf("page %d out of range: the result has %d pages", page, len(pages))
+	}
+	text := pages[page-1]
+	if page < len(pages) {
+		text += fmt.Sprintf("\n\n[Truncated: page %d of %d. Call again with the same arguments and page=%d for more.]", page, len(pages), page+1)
+	} else {
+		text += fmt.Sprintf("\n\n[Page %d of %d.]", page, len(pages))
+	}
+	return text, nil
+}
+
+// splitPages cuts text into pages of at most limit bytes, at line breaks
+// where there is one and never inside a UTF-8 sequence.
+func splitPages(text string, limit int) []string {
+	var pages []string
+	for len(text) > limit {
+		cut := strings.LastIndexByte(text[:limit], '\n')
+		if cut <= 0 {
+			cut = limit
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		pages = append(pages, text[:cut])
+		text = strings.TrimPrefix(text[cut:], "\n")
+	}
+	return append(pages, text)
+}
+
+// splitPage removes the page argument from input. key identifies the call
+// without it, so page=2 finds the result of the first call.
+func splitPage(name string, input json.RawMessage) (page int, rest json.RawMessage, key string, err error) {
+	rest = input
+	var args map[string]json.RawMessage
+	if len(bytes.TrimSpace(input)) == 0 || json.Unmarshal(input, &args) != nil || args == nil {
+		return 0, rest, name + " " + string(input), nil
+	}
+	if raw, ok := args["page"]; ok {
+		if err := json.Unmarshal(raw, &page); err != nil || page < 1 {
+			return 0, nil, "", fmt.Errorf("invalid page: %s (want a number from 1)", raw)
+		}
+		delete(args, "page")
+		// Remarshaling a map of raw values sorts keys but keeps each value as
+		// written.
+		if rest, err = json.Marshal(args); err != nil {
+			return 0, nil, "", err
+		}
+	}
+	canonical, _ := json.Marshal(args)
+	return page, rest, name + " " + string(canonical), nil
+}
+
+// withPageParam adds the page parameter to each tool's schema.
+func withPageParam(tools []llm.Tool) []llm.Tool {
+	out := make([]llm.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = t
+		var schema map[string]json.RawMessage
+		if json.Unmarshal(t.InputSchema, &schema) != nil {
+			continue
+		}
+		props := make(map[string]json.RawMessage)
+		if raw, ok := schema["properties"]; ok {
+			if json.Unmarshal(raw, &props) != nil {
+				continue
+			}
+		}
+		props["page"] = json.RawMessage(pageParamSchema)
+		schema["properties"], _ = json.Marshal(props)
+		if b, err := json.Marshal(schema); err == nil {
+			out[i].InputSchema = b
+		}
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestSplitPages(t *testing.T) {
+	pages := splitPages("aaaa\nbbbb\ncccc", 10)
+	assert.Equal(t, []string{"aaaa\nbbbb", "cccc"}, pages)
+
+	// No line break: cut at the limit, backing off to a rune boundary.
+	pages = splitPages(strings.Repeat("é", 5), 5)
+	assert.Equal(t, []string{"éé", "éé", "é"}, pages)
+}
+
+func TestExecuteTool_PagesLongOutput(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+	tr.pager.limit = 20
+
+	runs := 0
+	big := llm.Tool{Name: "big", InputSchema: json.RawMessage(`{"type":"object","properties":{"n":{"type":"integer"}}}`)}
+	require.NoError(t, tr.RegisterTool(big, func(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+		runs++
+		return ToolOutput{Text: "line one\nline two\nline three\nline four"}, nil
+	}, ToolReadOnly))
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "big", json.RawMessage(`{"n": 1}`))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out.Text, "line one\nline two\n\n[Truncated: page 1 of 2."), out.Text)
+
+	out, err = tr.ExecuteTool(ctx, "big", json.RawMessage(`{"page":2,"n":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, "line three\nline four\n\n[Page 2 of 2.]", out.Text)
+	assert.Equal(t, 1, runs, "page 2 comes from the first run")
+
+	_, err = tr.ExecuteTool(ctx, "big", json.RawMessage(`{"n":1,"page":3}`))
+	assert.ErrorContains(t, err, "out of range")
+
+	// Different arguments miss the cache; a read-only tool is re-run.
+	_, err = tr.ExecuteTool(ctx, "big", json.RawMessage(`{"n":2,"page":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+
+	_, err = tr.ExecuteTool(ctx, "big", json.RawMessage(`{"page":0}`))
+	assert.ErrorContains(t, err, "invalid page")
+}
+
+func TestExecuteTool_NoRerunForBroadcastingTool(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+
+	_, err := tr.ExecuteTool(context.Background(), "send_native", json.RawMessage(`{"chain":"base","page":2}`))
+	assert.ErrorContains(t, err, "no longer available")
+}
+
+func TestToolSchemas_HavePageParam(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+	for _, tool := range tr.GetTools() {
+		var schema struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		}
+		require.NoError(t, json.Unmarshal(tool.InputSchema, &schema), tool.Name)
+		assert.Contains(t, schema.Properties, "page", tool.Name)
+	}
+}
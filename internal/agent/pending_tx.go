@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Statuses of a sent transaction.
+const (
+	SentPending   = "pending"
+	SentConfirmed = "confirmed"
+	SentFailed    = "failed"
+	// SentDropped means another transaction took its nonce.
+	SentDropped = "dropped"
+)
+
+// PendingSent returns the sent transactions not yet known to be mined or
+// dropped, oldest first.
+func (s *ReceiptStore) PendingSent() ([]StoredSentTx, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	rows, err := s.db.Query(`
+SELECT chain, tx_hash, sender, nonce, raw_json, relay, sent_at FROM sent_txs
+WHERE status = ?
+ORDER BY sent_at, nonce
+`, SentPending)
+	if err != nil {
+		return nil, fmt.Errorf("load pending txs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []StoredSentTx
+	for rows.Next() {
+		var st StoredSentTx
+		var raw string
+		var at int64
+		if err := rows.Scan(&st.Chain, &st.Hash, &st.Sender, &st.Nonce, &raw, &st.Relay, &at); err != nil {
+			return nil, fmt.Errorf("load pending txs: %w", err)
+		}
+		st.Tx = new(types.Transaction)
+		if err := st.Tx.UnmarshalJSON([]byte(raw)); err != nil {
+			return nil, fmt.Errorf("load sent tx %s: %w", st.Hash, err)
+		}
+		st.SentAt = time.Unix(at, 0).UTC()
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// MarkSent sets a sent transaction's status. Storing its receipt with
+// Upsert does so too.
+func (s *ReceiptStore) MarkSent(chainName, txHash, status string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	_, err := s.db.Exec(`UPDATE sent_txs SET status = ? WHERE chain = ? AND tx_hash = ?`, status, chainName, strings.ToLower(txHash))
+	if err != nil {
+		return fmt.Errorf("update sent tx: %w", err)
+	}
+	return nil
+}
+
+// SettledTx is a pending transaction found mined or dropped.
+type SettledTx struct {
+	StoredSentTx
+	Status  string
+	GasUsed uint64
+}
+
+// Message describes the outcome in one line.
+func (t SettledTx) Message() string {
+	what := fmt.Sprintf("Transaction %s on %s (nonce %d)", t.Hash, t.Chain, t.Nonce)
+	switch t.Status {
+	case SentConfirmed:
+		return fmt.Sprintf("%s confirmed, gas used %d", what, t.GasUsed)
+	case SentFailed:
+		return fmt.Sprintf("%s failed on chain, gas used %d", what, t.GasUsed)
+	default:
+		return fmt.Sprintf("%s was dropped: another transaction used its nonce", what)
+	}
+}
+
+// sentStatus decides a pending transaction's status from its receipt
+// lookup and its sender's mined nonce, read before the lookup. A lookup
+// error other than not-found leaves it pending.
+func sentStatus(receipt *types.Receipt, lookupErr error, nonce, minedNonce uint64) string {
+	switch {
+	case lookupErr == nil && receipt != nil && receipt.Status == types.ReceiptStatusSuccessful:
+		return SentConfirmed
+	case lookupErr == nil && receipt != nil:
+		return SentFailed
+	case errors.Is(lookupErr, ethereum.NotFound) && minedNonce > nonce:
+		return SentDropped
+	}
+	return SentPending
+}
+
+// ReconcilePending checks the transactions clifi sent but never saw
+// mined, e.g. because it exited first, and records the ones the chain has
+// settled since: their receipt and flows, or that they were dropped. It
+// returns those.
+func (tr *ToolRegistry) ReconcilePending(ctx context.Context) ([]SettledTx, error) {
+	rs, err := tr.receiptStore()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := rs.PendingSent()
+	if err != nil || len(pending) == 0 || tr.chainClient == nil {
+		return nil, err
+	}
+
+	mined := make(map[string]uint64)
+	var out []SettledTx
+	for _, st := range pending {
+		if _, err := tr.chainClient.GetChainConfig(st.Chain); err != nil {
+			continue
+		}
+		// The mined nonce is read before the receipt: a transaction mined
+		// in between then still has one.
+		key := st.Chain + "/" + st.Sender
+		minedNonce, ok := mined[key]
+		if !ok {
+			minedNonce, err = tr.chainClient.GetLatestNonce(ctx, st.Chain, common.HexToAddress(st.Sender))
+			if err != nil {
+				slog.Debug("pending tx not checked", "chain", st.Chain, "tx", st.Hash, "err", err)
+				continue
+			}
+			mined[key] = minedNonce
+		}
+		receipt, err := tr.chainClient.GetTransactionReceipt(ctx, st.Chain, common.HexToHash(st.Hash))
+		status := sentStatus(receipt, err, st.Nonce, minedNonce)
+		switch status {
+		case SentPending:
+			continue
+		case SentDropped:
+			if err := rs.MarkSent(st.Chain, st.Hash, status); err != nil {
+				return out, err
+			}
+			out = append(out, SettledTx{StoredSentTx: st, Status: status})
+		default:
+			tr.persistReceipt(ctx, st.Chain, receipt)
+			out = append(out, SettledTx{StoredSentTx: st, Status: status, GasUsed: receipt.GasUsed})
+		}
+	}
+	return out, nil
+}
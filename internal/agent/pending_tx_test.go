@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptStore_PendingSent(t *testing.T) {
+	store, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	var txs []*types.Transaction
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := types.NewTx(&types.DynamicFeeTx{Nonce: nonce, To: &to, GasFeeCap: big.NewInt(2e9), Gas: 21000})
+		require.NoError(t, store.RecordSent("base", from, tx, ""))
+		txs = append(txs, tx)
+	}
+
+	pending, err := store.PendingSent()
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, "base", pending[0].Chain)
+	assert.Equal(t, "0x00000000000000000000000000000000000000aa", pending[0].Sender)
+
+	require.NoError(t, store.Upsert("base", &types.Receipt{TxHash: txs[0].Hash(), Status: types.ReceiptStatusSuccessful, GasUsed: 21000}))
+	require.NoError(t, store.Upsert("base", &types.Receipt{TxHash: txs[1].Hash(), Status: types.ReceiptStatusFailed}))
+	pending, err = store.PendingSent()
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "stored receipts settle their transactions")
+	assert.Equal(t, uint64(2), pending[0].Nonce)
+
+	require.NoError(t, store.MarkSent("base", txs[2].Hash().Hex(), SentDropped))
+	pending, err = store.PendingSent()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	var status string
+	require.NoError(t, store.db.QueryRow(`SELECT status FROM sent_txs WHERE nonce = 1`).Scan(&status))
+	assert.Equal(t, SentFailed, status)
+}
+
+func TestSentStatus(t *testing.T) {
+	ok := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	reverted := &types.Receipt{Status: types.ReceiptStatusFailed}
+
+	assert.Equal(t, SentConfirmed, sentStatus(ok, nil, 5, 6))
+	assert.Equal(t, SentFailed, sentStatus(reverted, nil, 5, 6))
+	assert.Equal(t, SentPending, sentStatus(nil, ethereum.NotFound, 5, 5), "nonce not used yet")
+	assert.Equal(t, SentDropped, sentStatus(nil, ethereum.NotFound, 5, 6), "nonce used by another tx")
+	assert.Equal(t, SentPending, sentStatus(nil, errors.New("rpc down"), 5, 6))
+}
+
+func TestSettledTx_Message(t *testing.T) {
+	st := StoredSentTx{Chain: "base", Hash: "0xab", Nonce: 4}
+	assert.Equal(t, "Transaction 0xab on base (nonce 4) confirmed, gas used 21000", SettledTx{StoredSentTx: st, Status: SentConfirmed, GasUsed: 21000}.Message())
+	assert.Equal(t, "Transaction 0xab on base (nonce 4) was dropped: another transaction used its nonce", SettledTx{StoredSentTx: st, Status: SentDropped}.Message())
+}
@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/permit"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+const (
+	defaultPermitDays = 30
+	maxPermitDays     = 365
+	// permitSigValidity is how long the spender has to submit a permit.
+	permitSigValidity = 30 * time.Minute
+)
+
+// approveViaPermit2 grants spender an allowance through Permit2: the token
+// only needs an allowance to Permit2 (sent first when it falls short), and
+// the spender gets a signed permit with an expiry instead of a lasting
+// ERC-20 allowance. Protocols that accept Permit2, such as Uniswap's
+// Universal Router, submit the permit themselves.
+func (tr *ToolRegistry) approveViaPermit2(ctx context.Context, params approveTokenInput, fromAddr common.Address, cfg *chain.ChainConfig, tokenAddr, spenderAddr common.Address, amount *big.Int, symbol string) (ToolOutput, error) {
+	if amount.Cmp(permit.MaxPermit2Amount) > 0 {
+		return ToolOutput{}, fmt.Errorf("amount_tokens exceeds the largest Permit2 allowance")
+	}
+	days := params.ExpirationDays
+	if days == 0 {
+		days = defaultPermitDays
+	}
+	if days < 0 || days > maxPermitDays {
+		return ToolOutput{}, fmt.Errorf("expiration_days must be between 1 and %d", maxPermitDays)
+	}
+
+	current, err := tr.permit2Allowance(ctx, params.Chain, fromAddr, tokenAddr, spenderAddr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	toPermit2, err := tr.allowance(ctx, params.Chain, tokenAddr, fromAddr, permit.Permit2Address)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to read allowance: %w", err)
+	}
+
+	policy := loadPolicy()
+	screenCheck, err := tr.screenRecipient(ctx, "Spender", spenderAddr.Hex(), policy)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	relay, err := privateRelayFor(params.Chain, cfg, params.Private)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	now := time.Now()
+	p := permit.PermitSingle{
+		Token:       tokenAddr,
+		Amount:      amount,
+		Expiration:  uint64(now.AddDate(0, 0, days).Unix()),
+		Nonce:       current.Nonce,
+		Spender:     spenderAddr,
+		SigDeadline: big.NewInt(now.Add(permitSigValidity).Unix()),
+	}
+	if err := p.Validate(); err != nil {
+		return ToolOutput{}, err
+	}
+
+	summary := fmt.Sprintf("Preview Permit2 approval:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- Spender: %s\n- Allowance: %s %s through Permit2, expires %s\n- Permit nonce: %d\n- Signature valid until: %s\n",
+		tokenAddr.Hex(), symbol, params.Chain, fromAddr.Hex(), spenderAddr.Hex(), params.AmountTokens, symbol,
+		time.Unix(int64(p.Expiration), 0).UTC().Format("2006-01-02 15:04 UTC"), p.Nonce,
+		time.Unix(p.SigDeadline.Int64(), 0).UTC().Format("15:04 UTC"))
+
+	// Permit2 can only move what the token lets it. The allowance to it is
+	// exact, like the swap tool's, rather than the usual unlimited one.
+	var unsigned *types.Transaction
+	if toPermit2.Cmp(amount) < 0 {
+		data, err := buildERC20ApproveData(permit.Permit2Address, amount)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		intent := tx.Intent{Chain: params.Chain, From: fromAddr, To: tokenAddr, ValueWei: big.NewInt(0), Data: data}
+		if err := tx.Validate(intent, policy); err != nil {
+			return ToolOutput{}, err
+		}
+		built, fees, err := tx.BuildUnsignedTx(ctx, tr.chainClient, intent)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		unsigned = built
+		summary += fmt.Sprintf("- First: ERC20 approval of %s %s to Permit2 (%s)\n- Gas limit: %d\n- Max fee: %s gwei\n%s- Estimated total (gas only): %s ETH\n",
+			params.AmountTokens, symbol, permit.Permit2Address.Hex(), fees.GasLimit, weiToGwei(fees.MaxFeePerGas), l1FeeLine(fees), weiToEth(fees.EstimatedCostWei))
+		summary += submissionLine(relay)
+	} else {
+		summary += "- Permit2 already has a large enough ERC20 allowance: only the permit is signed, no transaction is sent\n"
+	}
+	spenderCheck := tr.checkContract(ctx, params.Chain, cfg, "Spender", spenderAddr)
+	tokenCheck := tr.tokenRiskCheck(ctx, params.Chain, cfg, "Token", tokenAddr, fromAddr)
+	summary += spenderCheck.and(screenCheck).and(tokenCheck).previewText()
+
+	if !params.Confirm {
+		return ToolOutput{Text: summary + "\nThe spender's protocol must submit the signed permit; it grants nothing until then.\nSet confirm=true and provide password to sign."}, nil
+	}
+	if err := tr.checkSigner(params.Chain, fromAddr, params.Password); err != nil {
+		return ToolOutput{}, err
+	}
+
+	result := summary
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "Spender", Value: spenderAddr.Hex()},
+		{Key: "Token", Value: tokenAddr.Hex()},
+		{Key: "Allowance", Value: params.AmountTokens + " " + symbol},
+		{Key: "Expires", Value: time.Unix(int64(p.Expiration), 0).UTC().Format(time.DateOnly)},
+	}
+	var confirmed *TxConfirmation
+	if unsigned != nil {
+		signed, err := tr.signAndSendTx(ctx, params.Chain, fromAddr, params.Password, unsigned, cfg.ChainID, relay)
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("approval of Permit2: %w", err)
+		}
+		result += "\nApproval tx: " + signed.Hash().Hex()
+		var line string
+		line, confirmed = tr.maybeWaitAndPersistReceipt(ctx, params.Chain, signed.Hash(), params.Wait)
+		if confirmed != nil && !confirmed.Success {
+			return ToolOutput{}, fmt.Errorf("approval of Permit2 %s reverted; permit not signed", signed.Hash().Hex())
+		}
+		if line != "" {
+			result += "\n" + line
+		}
+		items = append(items, tr.txItem(params.Chain, signed.Hash().Hex()))
+	}
+
+	td := p.TypedData(cfg.ChainID)
+	sig, err := tr.signTypedData(fromAddr, params.Password, td)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	message, err := json.Marshal(td)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	result += fmt.Sprintf("\nSigned permit (EIP-712):\n%s\nSignature: %s\nHand both to the spender's protocol before %s.",
+		message, hexutil.Encode(sig), time.Unix(p.SigDeadline.Int64(), 0).UTC().Format("15:04 UTC"))
+	items = append(items, KVItem{Key: "Signature", Value: hexutil.Encode(sig)})
+	return ToolOutput{Text: result, Confirmed: confirmed, Blocks: []UIBlock{kvBlock("Permit2 approval", items...)}}, nil
+}
+
+// permit2Allowance reads Permit2's allowance for owner's token and
+// spender.
+func (tr *ToolRegistry) permit2Allowance(ctx context.Context, chainName string, owner, token, spender common.Address) (permit.Allowance, error) {
+	out, err := tr.chainClient.CallContract(ctx, chainName, ethereum.CallMsg{
+		To:   &permit.Permit2Address,
+		Data: permit.AllowanceCalldata(owner, token, spender),
+	})
+	if err != nil {
+		return permit.Allowance{}, fmt.Errorf("read Permit2 allowance: %w", err)
+	}
+	if len(out) == 0 {
+		return permit.Allowance{}, fmt.Errorf("Permit2 is not deployed on %s", chainName)
+	}
+	return permit.ParseAllowance(out)
+}
+
+// signTypedData unlocks from and signs EIP-712 typed data.
+func (tr *ToolRegistry) signTypedData(from common.Address, password string, td apitypes.TypedData) ([]byte, error) {
+	km, err := tr.keystore()
+	if err != nil {
+		return nil, err
+	}
+	signer, err := km.GetSigner(from, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock signer: %w", err)
+	}
+	defer signer.Lock()
+	raw, err := json.Marshal(td)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.SignTypedData(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	return sig, nil
+}
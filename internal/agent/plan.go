@@ -0,0 +1,342 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// maxPlanRounds bounds the read-only lookups a planning pass may make
+// before it has to submit a plan.
+const maxPlanRounds = 8
+
+// planResultChars caps each step's output in the summary the conversation
+// keeps of a run.
+const planResultChars = 600
+
+const submitPlanTool = "submit_plan"
+
+const planPrompt = `
+
+## Planning
+The user wants a multi-step operation planned before anything runs. Use read-only tools to look up what the plan depends on (balances, allowances, prices), then call submit_plan once with every step as a concrete tool call with its complete input: exact amounts, addresses and chains, not placeholders, since steps can't read each other's results. Leave out confirm and password; the user approves the whole plan once. Order steps so each can run after the one before it, e.g. an approval before the swap that needs it. If the request is unclear or can't be done, say so instead of calling submit_plan.`
+
+var submitPlan = llm.Tool{
+	Name:        submitPlanTool,
+	Description: "Submit the step list for the user to approve. Each step is one tool call, run in order",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"steps": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"description": {"type": "string", "description": "What the step does, in a short sentence"},
+						"tool": {"type": "string", "description": "Tool to call"},
+						"input": {"type": "object", "description": "Complete tool input"}
+					},
+					"required": ["description", "tool", "input"]
+				}
+			}
+		},
+		"required": ["steps"]
+	}`),
+}
+
+// PlanStep is one tool call of a plan. Preview is the tool's own preview
+// for steps that sign, taken while planning.
+type PlanStep struct {
+	Description string          `json:"description"`
+	Tool        string          `json:"tool"`
+	Input       json.RawMessage `json:"input"`
+	Signing     bool            `json:"-"`
+	Preview     string          `json:"-"`
+}
+
+// Plan is a step list waiting for the user's approval.
+type Plan struct {
+	Goal  string
+	Steps []PlanStep
+}
+
+// Signing reports whether any step signs, which needs a password.
+func (p *Plan) Signing() bool {
+	for _, s := range p.Steps {
+		if s.Signing {
+			return true
+		}
+	}
+	return false
+}
+
+// Text lays the plan out for approval, with the preview of each signing
+// step.
+func (p *Plan) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan: %s\n", p.Goal)
+	for i, s := range p.Steps {
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, s.Description, s.Tool)
+		if s.Preview != "" {
+			b.WriteString("   " + strings.ReplaceAll(strings.TrimSpace(s.Preview), "\n", "\n   ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ErrNoPlan is returned by RunPlan when no plan is waiting.
+var ErrNoPlan = errors.New("no plan to run; make one with /plan <goal>")
+
+// MakePlan runs a planning pass for goal: the model may call read-only
+// tools, then submits the steps, which wait for RunPlan. Steps that sign
+// are previewed now, so policy and input problems show before approval.
+// When the model answers in text instead, it is emitted and no plan is
+// kept.
+func (s *Session) MakePlan(ctx context.Context, goal string, onEvent func(ChatEvent)) (*Plan, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agent
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	ctx = withSessionDefaults(ctx, &s.defaults)
+	s.plan = nil
+	emit := func(e ChatEvent) {
+		if onEvent != nil {
+			onEvent(e)
+		}
+	}
+
+	modelID := s.cur.modelFor(TaskPlan)
+	if supports, known := llm.SupportsToolsForModel(ctx, s.cur.provider, modelID, a.getOpenRouterAPIKey()); known && !supports {
+		return nil, fmt.Errorf("planning needs tools, which %s does not support", modelID)
+	}
+	if err := s.checkBudget(modelID); err != nil {
+		return nil, err
+	}
+	tools := []llm.Tool{submitPlan}
+	for _, t := range a.toolRegistry.GetTools() {
+		if c, _ := a.toolRegistry.ToolCapability(t.Name); c == ToolReadOnly {
+			tools = append(tools, t)
+		}
+	}
+
+	s.ensureSession()
+	s.log(sessionRecord{TS: nowTS(), Type: "user", Content: "/plan " + goal, Provider: string(s.cur.provider.ID()), Model: modelID})
+	messages := append(append([]llm.Message(nil), s.conversation...), llm.Message{Role: "user", Content: goal})
+	systemPrompt := a.systemPrompt + planPrompt
+	if m := a.toolRegistry.describeUserData(); m != "" {
+		systemPrompt += "\n\n" + m
+	}
+	req := &llm.ChatRequest{
+		SystemPrompt: systemPrompt,
+		Messages:     messages,
+		Tools:        tools,
+		Model:        modelID,
+	}
+	s.applySampling(req)
+	start := time.Now()
+	response, err := s.cur.provider.Chat(ctx, req)
+	s.logProviderCall("plan", modelID, start, response, err)
+	s.debugExchange("plan", start, req, nil, nil, response, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response: %w", err)
+	}
+	s.addUsage(modelID, response.Usage)
+
+	for round := 0; ; round++ {
+		for _, tc := range response.ToolCalls {
+			if tc.Name == submitPlanTool {
+				return s.acceptPlan(ctx, goal, tc.Input, emit)
+			}
+		}
+		if len(response.ToolCalls) == 0 {
+			if response.Content != "" {
+				s.addMessages(llm.Message{Role: "user", Content: goal}, llm.Message{Role: "assistant", Content: response.Content})
+				emit(ChatEvent{Type: "content", Content: response.Content})
+			}
+			return nil, nil
+		}
+		if round == maxPlanRounds {
+			return nil, fmt.Errorf("no plan after %d rounds of lookups", maxPlanRounds)
+		}
+		results := s.planLookups(ctx, response.ToolCalls, emit)
+		if err := s.checkBudget(modelID); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		response, err = s.cur.provider.ChatWithToolResults(ctx, req, response.ToolCalls, results)
+		s.logProviderCall("plan_with_tool_results", modelID, start, response, err)
+		s.debugExchange("plan_with_tool_results", start, req, nil, results, response, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to continue planning: %w", err)
+		}
+		s.addUsage(modelID, response.Usage)
+	}
+}
+
+// planLookups runs the read-only calls of a planning round. Anything else
+// belongs in the plan, so it is refused rather than run.
+func (s *Session) planLookups(ctx context.Context, calls []llm.ToolCall, emit func(ChatEvent)) []llm.ToolResult {
+	results := make([]llm.ToolResult, len(calls))
+	for i, tc := range calls {
+		if c, ok := s.agent.toolRegistry.ToolCapability(tc.Name); ok && c != ToolReadOnly {
+			results[i] = llm.ToolResult{ToolUseID: tc.ID, Content: fmt.Sprintf("Error: %s can't run while planning; add it to the plan as a step", tc.Name), IsError: true}
+			continue
+		}
+		results[i] = s.executeToolCallsInternal(ctx, []llm.ToolCall{tc}, emit)[0]
+	}
+	return results
+}
+
+// acceptPlan checks the submitted steps and previews the signing ones
+// without confirm, so nothing is signed. Callers hold mu.
+func (s *Session) acceptPlan(ctx context.Context, goal string, input json.RawMessage, emit func(ChatEvent)) (*Plan, error) {
+	a := s.agent
+	var submitted struct {
+		Steps []PlanStep `json:"steps"`
+	}
+	if err := json.Unmarshal(input, &submitted); err != nil {
+		return nil, fmt.Errorf("the model submitted a malformed plan: %w", err)
+	}
+	if len(submitted.Steps) == 0 {
+		return nil, fmt.Errorf("the model submitted an empty plan")
+	}
+	plan := &Plan{Goal: goal, Steps: submitted.Steps}
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		c, ok := a.toolRegistry.ToolCapability(step.Tool)
+		if !ok || step.Tool == submitPlanTool {
+			return nil, fmt.Errorf("step %d uses unknown tool %q", i+1, step.Tool)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(step.Input, &fields); err != nil {
+			return nil, fmt.Errorf("step %d: input is not a JSON object", i+1)
+		}
+		if c != ToolSigning {
+			continue
+		}
+		step.Signing = true
+		delete(fields, "confirm")
+		delete(fields, "password")
+		step.Input, _ = json.Marshal(fields)
+		// An earlier step may change what this one sees, such as a
+		// balance, so a failed preview is shown rather than fatal.
+		out, err := a.toolRegistry.ExecuteTool(ctx, step.Tool, step.Input)
+		if err != nil {
+			step.Preview = fmt.Sprintf("Preview failed: %v", err)
+			continue
+		}
+		step.Preview = stripConfirmHint(out.Text)
+	}
+
+	s.plan = plan
+	text := plan.Text()
+	s.addMessages(llm.Message{Role: "user", Content: goal}, llm.Message{Role: "assistant", Content: text})
+	if s.transcript != nil {
+		s.transcript.AddUserMessage(goal)
+		s.transcript.AddAssistantMessage(text, nil)
+	}
+	s.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: text, Provider: string(s.cur.provider.ID()), Model: s.cur.modelFor(TaskPlan)})
+	emit(ChatEvent{Type: "content", Content: text})
+	return plan, nil
+}
+
+// stripConfirmHint drops the "Set confirm=true ..." line that ends a
+// preview; the plan is approved as a whole instead.
+func stripConfirmHint(preview string) string {
+	lines := strings.Split(strings.TrimRight(preview, "\n"), "\n")
+	for len(lines) > 0 && (strings.HasPrefix(lines[len(lines)-1], "Set confirm=true") || strings.TrimSpace(lines[len(lines)-1]) == "") {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PendingPlan returns the plan waiting for approval, if any.
+func (s *Session) PendingPlan() *Plan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.plan
+}
+
+// DiscardPlan drops the pending plan.
+func (s *Session) DiscardPlan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.plan = nil
+}
+
+// RunPlan runs the approved plan's steps in order, emitting a plan_step
+// event before each, and stops at the first step that fails. Signing steps
+// run with confirm=true and password.
+func (s *Session) RunPlan(ctx context.Context, password string, onEvent func(ChatEvent)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	plan := s.plan
+	if plan == nil {
+		return ErrNoPlan
+	}
+	s.plan = nil
+	ctx = withSessionDefaults(ctx, &s.defaults)
+	emit := func(e ChatEvent) {
+		if onEvent != nil {
+			onEvent(e)
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Ran the plan: %s\n", plan.Goal)
+	failed := false
+	for i, step := range plan.Steps {
+		emit(ChatEvent{Type: "plan_step", Content: fmt.Sprintf("Step %d/%d: %s", i+1, len(plan.Steps), step.Description)})
+		input := step.Input
+		if step.Signing {
+			var fields map[string]any
+			if err := json.Unmarshal(input, &fields); err != nil {
+				return err
+			}
+			fields["confirm"] = true
+			if password != "" {
+				fields["password"] = password
+			}
+			input, _ = json.Marshal(fields)
+		}
+		result := s.executeToolCallsInternal(ctx, []llm.ToolCall{{ID: fmt.Sprintf("plan_%d", i+1), Name: step.Tool, Input: input}}, emit)[0]
+		out := result.Content
+		if len(out) > planResultChars {
+			out = out[:planResultChars] + "..."
+		}
+		fmt.Fprintf(&summary, "%d. %s: %s\n", i+1, step.Description, out)
+		if result.IsError {
+			failed = true
+			msg := fmt.Sprintf("Stopped at step %d of %d: %s", i+1, len(plan.Steps), step.Description)
+			if rest := len(plan.Steps) - i - 1; rest > 0 {
+				msg += fmt.Sprintf("; the %d steps after it were not run.", rest)
+			}
+			emit(ChatEvent{Type: "content", Content: msg})
+			fmt.Fprintln(&summary, msg)
+			break
+		}
+	}
+	if !failed {
+		emit(ChatEvent{Type: "content", Content: fmt.Sprintf("Plan complete: %d steps ran.", len(plan.Steps))})
+	}
+
+	// The conversation keeps what ran, so follow-up questions can use it.
+	text := summary.String()
+	s.addMessages(llm.Message{Role: "user", Content: "Run the approved plan."}, llm.Message{Role: "assistant", Content: text})
+	if s.transcript != nil {
+		s.transcript.AddUserMessage("Run the approved plan.")
+		s.transcript.AddAssistantMessage(text, nil)
+	}
+	s.log(sessionRecord{TS: nowTS(), Type: "assistant", Content: text, Provider: string(s.cur.provider.ID()), Model: s.cur.model})
+	return nil
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func planCall(id string, steps string) llm.ToolCall {
+	return llm.ToolCall{ID: id, Name: submitPlanTool, Input: json.RawMessage(`{"steps":` + steps + `}`)}
+}
+
+func TestAgent_Plan(t *testing.T) {
+	steps := `[
+		{"description":"Look up base","tool":"get_chain_info","input":{"chain":"base"}},
+		{"description":"Send 1 ETH","tool":"send_native","input":{"chain":"nonexistent","to":"0x00000000000000000000000000000000000000bb","amount_eth":"1","confirm":true}},
+		{"description":"Look up arbitrum","tool":"get_chain_info","input":{"chain":"arbitrum"}}
+	]`
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{
+			{ID: "t1", Name: "get_chain_info", Input: json.RawMessage(`{"chain":"base"}`)},
+			{ID: "t2", Name: "send_native", Input: json.RawMessage(`{"chain":"base"}`)},
+		}},
+		{ToolCalls: []llm.ToolCall{planCall("t3", steps)}},
+	}})
+	ag := NewWithProvider(provider, t.TempDir())
+	defer ag.Close()
+
+	var events []ChatEvent
+	plan, err := ag.MakePlan(context.Background(), "send 1 ETH", func(e ChatEvent) { events = append(events, e) })
+	require.NoError(t, err)
+	require.NotNil(t, plan)
+	require.Len(t, plan.Steps, 3)
+	assert.True(t, plan.Signing())
+	assert.True(t, plan.Steps[1].Signing)
+	assert.NotContains(t, string(plan.Steps[1].Input), "confirm", "confirm is only set when the plan runs")
+	assert.Contains(t, plan.Steps[1].Preview, "Preview failed")
+	assert.Same(t, plan, ag.PendingPlan())
+
+	// Only the lookup ran while planning; the send was refused.
+	reqs := provider.Requests()
+	require.Len(t, reqs, 2)
+	for _, tool := range reqs[0].Tools {
+		assert.NotEqual(t, "send_native", tool.Name)
+	}
+	require.Len(t, events, 3)
+	assert.Equal(t, "get_chain_info", events[0].Tool)
+	assert.Equal(t, "content", events[2].Type)
+	assert.Contains(t, events[2].Content, "1. Look up base (get_chain_info)")
+
+	events = nil
+	require.NoError(t, ag.RunPlan(context.Background(), "hunter2", func(e ChatEvent) { events = append(events, e) }))
+	assert.Nil(t, ag.PendingPlan())
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{"plan_step", "tool_call", "tool_result", "plan_step", "tool_call", "tool_result", "content"}, types)
+	assert.Contains(t, events[4].Args, `"confirm":true`)
+	assert.NotContains(t, events[4].Args, "hunter2")
+	assert.True(t, events[5].IsError)
+	assert.Contains(t, events[6].Content, "Stopped at step 2 of 3")
+
+	assert.ErrorIs(t, ag.RunPlan(context.Background(), "", nil), ErrNoPlan)
+}
+
+func TestAgent_PlanRejectsUnknownTool(t *testing.T) {
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{planCall("t1", `[{"description":"Bridge","tool":"bridge","input":{}}]`)}},
+	}})
+	ag := NewWithProvider(provider, t.TempDir())
+	defer ag.Close()
+
+	_, err := ag.MakePlan(context.Background(), "bridge it", nil)
+	assert.ErrorContains(t, err, `unknown tool "bridge"`)
+	assert.Nil(t, ag.PendingPlan())
+}
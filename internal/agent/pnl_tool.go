@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/pnl"
+)
+
+type getPnLInput struct {
+	Address string `json:"address"`
+	CSV     bool   `json:"csv"`
+}
+
+func (tr *ToolRegistry) handleGetPnL(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params getPnLInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+
+	var address common.Address
+	if params.Address == "" {
+		km, err := tr.keystore()
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		acc, err := km.DefaultAccount()
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("no address given and no wallets found")
+		}
+		address = acc.Address
+	} else {
+		var err error
+		if address, err = requireHexAddress("address", params.Address); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	report, err := tr.PnL(ctx, address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	text := report.Text()
+	if params.CSV {
+		path, err := tr.writePnLCSV(report)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		text += fmt.Sprintf("\nWrote %d disposals to %s\n", len(report.Disposals), path)
+	}
+	block := UIBlock{Kind: UIBlockTable, Table: &UITable{
+		Title:   report.Summary(),
+		Headers: pnl.Headers,
+		Rows:    report.Rows(),
+	}}
+	return ToolOutput{Text: text, Blocks: []UIBlock{block}}, nil
+}
+
+// writePnLCSV saves the report's disposals under dataDir/exports.
+func (tr *ToolRegistry) writePnLCSV(report pnl.Report) (string, error) {
+	if tr.dataDir == "" {
+		return "", fmt.Errorf("CSV export needs a data directory")
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(tr.dataDir, "exports")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("pnl-%s-%s.csv", strings.ToLower(report.Address[:10]), time.Now().Format("20060102"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
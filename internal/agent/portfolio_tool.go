@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/portfolio"
+)
+
+type portfolioDiffInput struct {
+	Address string   `json:"address"`
+	Since   string   `json:"since"`
+	Chains  []string `json:"chains"`
+}
+
+func (tr *ToolRegistry) handlePortfolioDiff(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params portfolioDiffInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("portfolio history needs a data directory")
+	}
+	if params.Since == "" {
+		params.Since = "7d"
+	}
+	since, err := portfolio.ParseSince(params.Since)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	var address common.Address
+	if params.Address == "" {
+		km, err := tr.keystore()
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		acc, err := km.DefaultAccount()
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("no address given and no wallets found")
+		}
+		address = acc.Address
+	} else if address, err = requireHexAddress("address", params.Address); err != nil {
+		return ToolOutput{}, err
+	}
+
+	store, err := portfolio.OpenStore(tr.dataDir)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	defer store.Close()
+
+	base, err := store.Baseline(address.Hex(), time.Now().Add(-since))
+	if errors.Is(err, portfolio.ErrNoSnapshot) {
+		// Start the history now so the next diff has something to compare.
+		snap, err := tr.takeSnapshot(ctx, store, address, params.Chains)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		return ToolOutput{Text: fmt.Sprintf("No earlier snapshot of %s; recorded the first one now (%d assets). Ask again later, or run `clifi serve` to snapshot daily.", address.Hex(), len(snap.Assets))}, nil
+	}
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	chains := params.Chains
+	if len(chains) == 0 {
+		chains = base.Chains
+	}
+	current, err := tr.takeSnapshot(ctx, store, address, chains)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	report := portfolio.Diff(base, current)
+	block := UIBlock{Kind: UIBlockTable, Table: &UITable{
+		Title:   report.Summary(),
+		Headers: portfolio.Headers,
+		Rows:    report.Rows(),
+	}}
+	return ToolOutput{Text: report.Text(), Blocks: []UIBlock{block}}, nil
+}
+
+func (tr *ToolRegistry) takeSnapshot(ctx context.Context, store *portfolio.Store, address common.Address, chains []string) (portfolio.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	snap, err := portfolio.Take(ctx, tr.chainClient, tr.quotes, address, chains)
+	if err != nil {
+		return portfolio.Snapshot{}, err
+	}
+	snap.ID, err = store.Save(snap)
+	return snap, err
+}
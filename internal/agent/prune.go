@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Retention bounds the history the receipt DB keeps. The P&L ledger and
+// token metadata are never pruned: cost basis needs every lot.
+type Retention struct {
+	// Months keeps each table's rows from the last N months; 0 keeps all.
+	Months int
+	// Rows keeps each table's newest N rows; 0 keeps all.
+	Rows int
+}
+
+// IsZero reports whether r keeps everything.
+func (r Retention) IsZero() bool {
+	return r.Months <= 0 && r.Rows <= 0
+}
+
+// AutoVacuumFreeRatio is the share of free pages at which the DB is
+// worth rewriting without being asked.
+const AutoVacuumFreeRatio = 0.25
+
+// PrunedTable is how many rows pruning removed from one table.
+type PrunedTable struct {
+	Table string
+	Rows  int64
+}
+
+// prunable lists the tables pruning trims, each with the column that
+// orders its rows by age, that column's value for a cutoff time and,
+// optionally, a condition for rows that are never pruned.
+var prunable = []struct {
+	table, age string
+	cutoff     func(time.Time) any
+	keep       string
+}{
+	{"receipts", "created_at", func(t time.Time) any { return t.UTC().Format(time.DateTime) }, ""},
+	// Pending transactions wait to be reconciled, however old.
+	{"sent_txs", "sent_at", func(t time.Time) any { return t.Unix() }, "status = '" + SentPending + "'"},
+	{"chat_messages", "at", func(t time.Time) any { return t.Unix() }, ""},
+	{"llm_usage", "day", func(t time.Time) any { return t.Format(time.DateOnly) }, ""},
+}
+
+// Prune deletes rows older than r.Months and beyond the newest r.Rows of
+// each prunable table, and what only served them: the gas report context
+// of deleted receipts and links to deleted chat messages. With dryRun
+// nothing is deleted and the counts say what would be.
+func (s *ReceiptStore) Prune(r Retention, now time.Time, dryRun bool) ([]PrunedTable, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("receipt store not initialized")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("prune receipts db: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var out []PrunedTable
+	for _, p := range prunable {
+		keep := ""
+		if p.keep != "" {
+			keep = " AND NOT (" + p.keep + ")"
+		}
+		var n int64
+		if r.Months > 0 {
+			deleted, err := execCount(tx, fmt.Sprintf(`DELETE FROM %s WHERE %s < ?%s`, p.table, p.age, keep), p.cutoff(now.AddDate(0, -r.Months, 0)))
+			if err != nil {
+				return nil, fmt.Errorf("prune %s: %w", p.table, err)
+			}
+			n += deleted
+		}
+		if r.Rows > 0 {
+			deleted, err := execCount(tx, fmt.Sprintf(`DELETE FROM %[1]s WHERE rowid NOT IN (SELECT rowid FROM %[1]s ORDER BY %[2]s DESC, rowid DESC LIMIT ?)%[3]s`, p.table, p.age, keep), r.Rows)
+			if err != nil {
+				return nil, fmt.Errorf("prune %s: %w", p.table, err)
+			}
+			n += deleted
+		}
+		out = append(out, PrunedTable{Table: p.table, Rows: n})
+	}
+
+	orphans := []struct{ table, query string }{
+		{"gas_context", `DELETE FROM gas_context WHERE NOT EXISTS (SELECT 1 FROM receipts r WHERE r.chain = gas_context.chain AND r.tx_hash = gas_context.tx_hash)`},
+		{"chat_sessions", `DELETE FROM chat_sessions WHERE resumed_from NOT IN (SELECT id FROM chat_messages)`},
+	}
+	for _, o := range orphans {
+		n, err := execCount(tx, o.query)
+		if err != nil {
+			return nil, fmt.Errorf("prune %s: %w", o.table, err)
+		}
+		out = append(out, PrunedTable{Table: o.table, Rows: n})
+	}
+
+	if dryRun {
+		return out, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("prune receipts db: %w", err)
+	}
+	return out, nil
+}
+
+func execCount(tx *sql.Tx, query string, args ...any) (int64, error) {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Size returns the DB's size in bytes and how much of it is free pages
+// left by deleted rows.
+func (s *ReceiptStore) Size() (total, free int64, err error) {
+	if s == nil || s.db == nil {
+		return 0, 0, fmt.Errorf("receipt store not initialized")
+	}
+	var pages, freePages, pageSize int64
+	err = s.db.QueryRow(`SELECT page_count, freelist_count, page_size FROM pragma_page_count(), pragma_freelist_count(), pragma_page_size()`).Scan(&pages, &freePages, &pageSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read receipts db size: %w", err)
+	}
+	return pages * pageSize, freePages * pageSize, nil
+}
+
+// Vacuum rewrites the DB to return free pages to the file system, when
+// they make up at least minFreeRatio of it; 0 rewrites whenever any page
+// is free. It reports whether it did.
+func (s *ReceiptStore) Vacuum(minFreeRatio float64) (bool, error) {
+	total, free, err := s.Size()
+	if err != nil {
+		return false, err
+	}
+	if free == 0 || float64(free) < minFreeRatio*float64(total) {
+		return false, nil
+	}
+	if _, err := s.db.Exec(`VACUUM`); err != nil {
+		return false, fmt.Errorf("vacuum receipts db: %w", err)
+	}
+	return true, nil
+}
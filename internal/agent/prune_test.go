@@ -0,0 +1,142 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func countRows(t *testing.T, rs *ReceiptStore, table string) int {
+	t.Helper()
+	var n int
+	require.NoError(t, rs.db.QueryRow(`SELECT COUNT(*) FROM `+table).Scan(&n))
+	return n
+}
+
+func prunedRows(pruned []PrunedTable) map[string]int64 {
+	out := map[string]int64{}
+	for _, p := range pruned {
+		out[p.Table] = p.Rows
+	}
+	return out
+}
+
+func TestReceiptStore_Prune(t *testing.T) {
+	rs, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rs.Close() })
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	// One receipt, message and usage day per month, the oldest 11 months back.
+	for i := range 12 {
+		at := now.AddDate(0, -i, 0)
+		hash := fmt.Sprintf("0x%02d", i)
+		_, err := rs.db.Exec(`INSERT INTO receipts (chain, tx_hash, status, gas_used, raw_json, created_at) VALUES ('base', ?, 1, 21000, '{}', ?)`, hash, at.Format(time.DateTime))
+		require.NoError(t, err)
+		_, err = rs.db.Exec(`INSERT INTO gas_context VALUES ('base', ?, '0xabc', ?, '')`, hash, at.Unix())
+		require.NoError(t, err)
+		_, err = rs.AddChatMessage("s", "user", "hello", at)
+		require.NoError(t, err)
+		require.NoError(t, rs.AddLLMUsage(at.Format(time.DateOnly), "openai", "gpt-4o", llm.Usage{InputTokens: 10}, 0.01))
+	}
+	_, err = rs.db.Exec(`INSERT INTO ledger VALUES ('base', '0x11', '0xabc', 0, 'ETH', '', 1, 2000, 'buy', ?)`, now.AddDate(-2, 0, 0).Unix())
+	require.NoError(t, err)
+
+	// Six months back to the day is still kept.
+	pruned, err := rs.Prune(Retention{Months: 6}, now, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), prunedRows(pruned)["receipts"])
+	assert.Equal(t, int64(5), prunedRows(pruned)["gas_context"])
+	assert.Equal(t, 12, countRows(t, rs, "receipts"), "a dry run deletes nothing")
+
+	pruned, err = rs.Prune(Retention{Months: 6}, now, false)
+	require.NoError(t, err)
+	got := prunedRows(pruned)
+	for _, table := range []string{"receipts", "gas_context", "chat_messages", "llm_usage"} {
+		assert.Equal(t, int64(5), got[table], table)
+		assert.Equal(t, 7, countRows(t, rs, table), table)
+	}
+	assert.Equal(t, 1, countRows(t, rs, "ledger"), "the ledger is kept")
+
+	hits, err := rs.SearchChat("hello", 10)
+	require.NoError(t, err)
+	assert.Len(t, hits, 7, "pruned messages leave the index")
+
+	pruned, err = rs.Prune(Retention{Rows: 2}, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), prunedRows(pruned)["receipts"])
+	stored, err := rs.Get("base", "0x00")
+	require.NoError(t, err)
+	assert.NotNil(t, stored, "the newest rows stay")
+}
+
+func TestReceiptStore_PruneKeepsPendingSent(t *testing.T) {
+	rs, err := OpenReceiptStoreDSN(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rs.Close() })
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	// Four sends a month apart, the oldest still pending.
+	for i := range 4 {
+		status := SentConfirmed
+		if i == 3 {
+			status = SentPending
+		}
+		_, err := rs.db.Exec(`INSERT INTO sent_txs (chain, tx_hash, sender, nonce, raw_json, relay, sent_at, status) VALUES ('base', ?, '0xaa', ?, '{}', '', ?, ?)`,
+			fmt.Sprintf("0x%02d", i), 10-i, now.AddDate(0, -i, 0).Unix(), status)
+		require.NoError(t, err)
+	}
+
+	pruned, err := rs.Prune(Retention{Months: 1}, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), prunedRows(pruned)["sent_txs"], "the old pending send stays")
+
+	pruned, err = rs.Prune(Retention{Rows: 1}, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), prunedRows(pruned)["sent_txs"])
+	var hashes []string
+	rows, err := rs.db.Query(`SELECT tx_hash FROM sent_txs ORDER BY sent_at DESC`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var h string
+		require.NoError(t, rows.Scan(&h))
+		hashes = append(hashes, h)
+	}
+	assert.Equal(t, []string{"0x00", "0x03"}, hashes, "the newest row and the pending one")
+}
+
+func TestReceiptStore_Vacuum(t *testing.T) {
+	rs, err := OpenReceiptStoreDSN(filepath.Join(t.TempDir(), ReceiptDBFile))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rs.Close() })
+
+	for i := range 500 {
+		_, err := rs.AddChatMessage("s", "assistant", fmt.Sprintf("message %d %0500d", i, i), time.Unix(int64(i), 0))
+		require.NoError(t, err)
+	}
+	did, err := rs.Vacuum(AutoVacuumFreeRatio)
+	require.NoError(t, err)
+	assert.False(t, did, "nothing to reclaim")
+
+	before, _, err := rs.Size()
+	require.NoError(t, err)
+	_, err = rs.Prune(Retention{Rows: 10}, time.Now(), false)
+	require.NoError(t, err)
+	_, free, err := rs.Size()
+	require.NoError(t, err)
+	assert.Greater(t, free, int64(0))
+
+	did, err = rs.Vacuum(AutoVacuumFreeRatio)
+	require.NoError(t, err)
+	assert.True(t, did)
+	after, free, err := rs.Size()
+	require.NoError(t, err)
+	assert.Less(t, after, before)
+	assert.Zero(t, free)
+}
@@ -0,0 +1,221 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+const (
+	// defaultLogBlocks is how far back a query without from_block looks.
+	defaultLogBlocks = 1000
+	// logChunkBlocks keeps each eth_getLogs under the range public RPCs
+	// accept.
+	logChunkBlocks = 2000
+	// maxLogBlocks bounds one query, so a careless range can't walk the
+	// whole chain.
+	maxLogBlocks    = 100_000
+	defaultLogLimit = 50
+	maxLogLimit     = 500
+)
+
+type queryLogsInput struct {
+	Chain     string              `json:"chain"`
+	Address   []string            `json:"address"`
+	Event     string              `json:"event"`
+	Where     map[string][]string `json:"where"`
+	Topics    [][]string          `json:"topics"`
+	FromBlock string              `json:"from_block"`
+	ToBlock   string              `json:"to_block"`
+	Limit     int                 `json:"limit"`
+}
+
+// handleQueryLogs runs eth_getLogs, newest blocks first, and decodes what
+// it finds. An event signature both selects the logs and names their
+// arguments; without one the contracts' ABIs are used, as in explain_tx.
+func (tr *ToolRegistry) handleQueryLogs(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var params queryLogsInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	cfg, err := tr.chainClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+	limit = min(limit, maxLogLimit)
+
+	var q ethereum.FilterQuery
+	for _, a := range params.Address {
+		addr, err := requireHexAddress("address", a)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		q.Addresses = append(q.Addresses, addr)
+	}
+	dec := decode.New()
+	switch {
+	case params.Event != "" && len(params.Topics) > 0:
+		return ToolOutput{}, fmt.Errorf("give either event (with where) or raw topics, not both")
+	case params.Event != "":
+		if q.Topics, err = decode.EventTopics(params.Event, params.Where); err != nil {
+			return ToolOutput{}, err
+		}
+		// The given signature's argument names win over built-in ones.
+		dec = decode.Empty()
+		if err := dec.AddEventSignature(params.Event, "given signature"); err != nil {
+			return ToolOutput{}, err
+		}
+	case len(params.Where) > 0:
+		return ToolOutput{}, fmt.Errorf("where needs event, the signature naming the arguments")
+	default:
+		for i, position := range params.Topics {
+			q.Topics = append(q.Topics, nil)
+			for _, v := range position {
+				h, err := parseTopic(v)
+				if err != nil {
+					return ToolOutput{}, fmt.Errorf("topics[%d]: %w", i, err)
+				}
+				q.Topics[i] = append(q.Topics[i], h)
+			}
+		}
+	}
+	if len(q.Addresses) == 0 && len(q.Topics) == 0 {
+		return ToolOutput{}, fmt.Errorf("give an address, an event or topics; querying every log on %s is too broad", params.Chain)
+	}
+
+	latest, err := tr.chainClient.BlockNumber(ctx, params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	to, err := parseBlockParam(params.ToBlock, latest, latest)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("to_block: %w", err)
+	}
+	from, err := parseBlockParam(params.FromBlock, to, saturatingSub(to, defaultLogBlocks-1))
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("from_block: %w", err)
+	}
+	if from > to {
+		return ToolOutput{}, fmt.Errorf("from_block %d is after to_block %d", from, to)
+	}
+	if to-from+1 > maxLogBlocks {
+		return ToolOutput{}, fmt.Errorf("block range %d-%d spans %d blocks; query at most %d at a time", from, to, to-from+1, maxLogBlocks)
+	}
+
+	logs, scannedFrom, err := tr.scanLogs(ctx, params.Chain, q, from, to, limit)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Event == "" {
+		tr.loadABIs(ctx, params.Chain, cfg, dec, q.Addresses)
+	}
+
+	title := fmt.Sprintf("Logs on %s, blocks %d-%d", params.Chain, scannedFrom, to)
+	if len(logs) == 0 {
+		return ToolOutput{Text: title + ": none found.\n"}, nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%d, newest first):\n", title, len(logs))
+	table := &UITable{Title: title, Headers: []string{"Block", "Tx", "Contract", "Event"}}
+	for i := range logs {
+		l := &logs[i]
+		line := tr.describeLog(ctx, params.Chain, dec, l)
+		fmt.Fprintf(&b, "- %d %s %s: %s\n", l.BlockNumber, l.TxHash.Hex(), l.Address.Hex(), line)
+		table.Rows = append(table.Rows, []string{strconv.FormatUint(l.BlockNumber, 10), l.TxHash.Hex(), l.Address.Hex(), line})
+	}
+	if scannedFrom > from {
+		fmt.Fprintf(&b, "Stopped at the %d-log limit; blocks %d-%d were not scanned.\n", limit, from, scannedFrom-1)
+	}
+	return ToolOutput{Text: b.String(), Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+// scanLogs queries from..to in chunks, newest first, until limit logs are
+// found. It returns the logs newest first and the lowest block scanned.
+func (tr *ToolRegistry) scanLogs(ctx context.Context, chainName string, q ethereum.FilterQuery, from, to uint64, limit int) ([]types.Log, uint64, error) {
+	var found []types.Log
+	hi := to
+	for {
+		lo := max(from, saturatingSub(hi, logChunkBlocks-1))
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(lo), new(big.Int).SetUint64(hi)
+		logs, err := tr.chainClient.FilterLogs(ctx, chainName, q)
+		if err != nil {
+			return nil, 0, fmt.Errorf("eth_getLogs %d-%d: %w", lo, hi, err)
+		}
+		sort.SliceStable(logs, func(i, j int) bool {
+			if logs[i].BlockNumber != logs[j].BlockNumber {
+				return logs[i].BlockNumber > logs[j].BlockNumber
+			}
+			return logs[i].Index > logs[j].Index
+		})
+		found = append(found, logs...)
+		if len(found) >= limit {
+			return found[:limit], lo, nil
+		}
+		if lo == from {
+			return found, from, nil
+		}
+		hi = lo - 1
+	}
+}
+
+// parseBlockParam reads a block number, "latest", or a negative offset
+// such as "-5000" counted back from base. Empty means def.
+func parseBlockParam(v string, base, def uint64) (uint64, error) {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "":
+		return def, nil
+	case v == "latest":
+		return base, nil
+	case strings.HasPrefix(v, "-"):
+		n, err := strconv.ParseUint(v[1:], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a block offset", v)
+		}
+		return saturatingSub(base, n), nil
+	}
+	n, err := strconv.ParseUint(v, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a block number", v)
+	}
+	return n, nil
+}
+
+// parseTopic reads a raw topic: a 32-byte hash, or an address to pad.
+func parseTopic(v string) (common.Hash, error) {
+	v = strings.TrimSpace(v)
+	switch {
+	case common.IsHexAddress(v):
+		return common.BytesToHash(common.HexToAddress(v).Bytes()), nil
+	case strings.HasPrefix(v, "0x") && len(v) == 66:
+		return common.HexToHash(v), nil
+	}
+	return common.Hash{}, fmt.Errorf("%q is neither a 32-byte topic nor an address", v)
+}
+
+func saturatingSub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlockParam(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{"", 7, false},
+		{"latest", 100, false},
+		{"42", 42, false},
+		{"0x10", 16, false},
+		{"-30", 70, false},
+		{"-500", 0, false},
+		{"-x", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseBlockParam(tt.in, 100, 7)
+		if tt.wantErr {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestParseTopic(t *testing.T) {
+	h, err := parseTopic("0x000000000000000000000000000000000000dEaD")
+	require.NoError(t, err)
+	assert.Equal(t, "0x000000000000000000000000000000000000000000000000000000000000dead", h.Hex())
+
+	topic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	h, err = parseTopic(topic)
+	require.NoError(t, err)
+	assert.Equal(t, topic, h.Hex())
+
+	_, err = parseTopic("0x1234")
+	assert.Error(t, err)
+}
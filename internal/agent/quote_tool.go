@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/quote"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+// maxQuoteRoutes caps how many alternatives get_quote shows.
+const maxQuoteRoutes = 3
+
+type getQuoteInput struct {
+	FromChain   string  `json:"from_chain"`
+	ToChain     string  `json:"to_chain"`
+	FromToken   string  `json:"from_token"`
+	ToToken     string  `json:"to_token"`
+	Amount      string  `json:"amount"`
+	SlippageBps *uint32 `json:"slippage_bps"`
+}
+
+// handleGetQuote compares swap and bridge routes. It never builds a
+// transaction, so it needs no wallet and no confirmation.
+func (tr *ToolRegistry) handleGetQuote(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params getQuoteInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.FromChain == "" {
+		return ToolOutput{}, fmt.Errorf("from_chain is required")
+	}
+	if params.ToChain == "" {
+		params.ToChain = params.FromChain
+	}
+	if strings.TrimSpace(params.Amount) == "" {
+		return ToolOutput{}, fmt.Errorf("amount is required")
+	}
+
+	fromCfg, err := tr.chainClient.GetChainConfig(params.FromChain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	toCfg, err := tr.chainClient.GetChainConfig(params.ToChain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if fromCfg.IsTestnet || toCfg.IsTestnet {
+		return ToolOutput{}, fmt.Errorf("quotes are only available on mainnets")
+	}
+
+	limits, err := swapLimits(params.SlippageBps, nil, loadPolicy())
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	fromToken, err := tr.resolveQuoteToken(params.FromChain, fromCfg, params.FromToken)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("from_token: %w", err)
+	}
+	toToken, err := tr.resolveQuoteToken(params.ToChain, toCfg, params.ToToken)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("to_token: %w", err)
+	}
+	if fromCfg.ChainIDInt == toCfg.ChainIDInt && fromToken == toToken {
+		return ToolOutput{}, fmt.Errorf("from_token and to_token are the same")
+	}
+
+	decimals := uint8(18)
+	if fromToken != quote.NativeToken {
+		decimals, _, err = queryTokenMeta(ctx, tr.chainClient, params.FromChain, fromToken)
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("failed to read token decimals: %w", err)
+		}
+	}
+	amount, err := decimalToWei(params.Amount, int(decimals))
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if amount.Sign() <= 0 {
+		return ToolOutput{}, fmt.Errorf("amount must be greater than zero")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	quotes, err := tr.quotes.Quotes(reqCtx, quote.Request{
+		FromChainID: fromCfg.ChainIDInt,
+		ToChainID:   toCfg.ChainIDInt,
+		FromToken:   fromToken,
+		ToToken:     toToken,
+		FromAmount:  amount,
+		SlippageBps: limits.SlippageBps,
+	})
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(quotes) == 0 {
+		return ToolOutput{Text: fmt.Sprintf("No routes found for %s %s on %s to %s on %s.",
+			params.Amount, params.FromToken, params.FromChain, params.ToToken, params.ToChain)}, nil
+	}
+	if len(quotes) > maxQuoteRoutes {
+		quotes = quotes[:maxQuoteRoutes]
+	}
+
+	chainName := tr.chainNamesByID()
+	table := &UITable{
+		Title:   fmt.Sprintf("Quotes (slippage %s)", tx.FormatBps(limits.SlippageBps)),
+		Headers: []string{"#", "Route", "Expected out", "Min received", "Price impact", "Gas (USD)", "Time"},
+		Rows:    [][]string{},
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "Quotes for %s %s (%s) -> %s (%s), slippage %s. No transaction was built.\n",
+		params.Amount, quotes[0].FromToken.Symbol, params.FromChain, quotes[0].ToToken.Symbol, params.ToChain, tx.FormatBps(limits.SlippageBps))
+	for i, q := range quotes {
+		out := chain.FormatBalance(q.ToAmount, q.ToToken.Decimals) + " " + q.ToToken.Symbol
+		minOut := chain.FormatBalance(tx.MinReceived(q.ToAmount, limits.SlippageBps), q.ToToken.Decimals) + " " + q.ToToken.Symbol
+		impact := "unknown"
+		if v, ok := q.PriceImpact(); ok {
+			impact = strconv.FormatFloat(v*100, 'f', 2, 64) + "%"
+		}
+		gas := "unknown"
+		if q.GasCostUSD != "" {
+			gas = "$" + q.GasCostUSD
+		}
+		route := q.Route(chainName)
+
+		table.Rows = append(table.Rows, []string{strconv.Itoa(i + 1), route, out, minOut, impact, gas, q.Duration.String()})
+		fmt.Fprintf(&text, "\n%d. %s\n   Expected: %s (min %s)\n   Price impact: %s\n   Gas: %s\n   Est. time: %s\n",
+			i+1, route, out, minOut, impact, gas, q.Duration)
+	}
+	return ToolOutput{Text: strings.TrimRight(text.String(), "\n"), Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+// resolveQuoteToken is resolveToken plus the native currency, which may be
+// given as "native" or by its symbol (e.g. "ETH" on base).
+func (tr *ToolRegistry) resolveQuoteToken(chainName string, cfg *chain.ChainConfig, token string) (common.Address, error) {
+	token = strings.TrimSpace(token)
+	if strings.EqualFold(token, "native") || strings.EqualFold(token, cfg.NativeCurrency) {
+		return quote.NativeToken, nil
+	}
+	return tr.resolveToken(chainName, token)
+}
+
+// chainNamesByID maps chain IDs back to configured chain names for display.
+func (tr *ToolRegistry) chainNamesByID() func(int64) string {
+	names := make(map[int64]string)
+	for _, name := range tr.chainClient.ListChains() {
+		if cfg, err := tr.chainClient.GetChainConfig(name); err == nil {
+			names[cfg.ChainIDInt] = name
+		}
+	}
+	return func(id int64) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		return strconv.FormatInt(id, 10)
+	}
+}
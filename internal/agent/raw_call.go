@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/yolodolo42/clifi/internal/decode"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// EnableRawCall offers the raw_call tool, which is off by default.
+func (tr *ToolRegistry) EnableRawCall() error {
+	return tr.RegisterTool(llm.RawCallTool(), tr.handleRawCall, ToolReadOnly)
+}
+
+type rawCallInput struct {
+	Chain string `json:"chain"`
+	To    string `json:"to"`
+	Data  string `json:"data"`
+	From  string `json:"from"`
+	Value string `json:"value"`
+	Block string `json:"block"`
+}
+
+func (tr *ToolRegistry) handleRawCall(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params rawCallInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	if _, err := tr.chainClient.GetChainConfig(params.Chain); err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+	to, err := requireHexAddress("to address", params.To)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	data, err := decode.ParseHex(params.Data)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	msg := ethereum.CallMsg{To: &to, Data: data}
+	if params.From != "" {
+		if msg.From, err = requireHexAddress("from address", params.From); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+	if params.Value != "" {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(params.Value), 10)
+		if !ok || v.Sign() < 0 {
+			return ToolOutput{}, fmt.Errorf("invalid value %q: want a whole number of wei", params.Value)
+		}
+		msg.Value = v
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	block, blockLabel, err := tr.stateBlock(ctx, params.Chain, params.Block)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	out, err := tr.chainClient.CallContractAt(ctx, params.Chain, msg, block)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("eth_call to %s: %w", to.Hex(), err)
+	}
+	result := hexutil.Encode(out)
+	text := fmt.Sprintf("eth_call on %s at block %s:\n- To: %s\n- Output (%d bytes): %s\n", params.Chain, blockLabel, to.Hex(), len(out), result)
+	return ToolOutput{Text: text, Blocks: []UIBlock{kvBlock("eth_call",
+		KVItem{Key: "Chain", Value: params.Chain},
+		KVItem{Key: "To", Value: to.Hex()},
+		KVItem{Key: "Block", Value: blockLabel},
+		KVItem{Key: "Output", Value: result},
+	)}}, nil
+}
+
+// stateBlock resolves a block argument for a state read: nil for latest,
+// as the RPC expects, and the label to show.
+func (tr *ToolRegistry) stateBlock(ctx context.Context, chainName, v string) (*big.Int, string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" || v == "latest" {
+		return nil, "latest", nil
+	}
+	latest, err := tr.chainClient.BlockNumber(ctx, chainName)
+	if err != nil {
+		return nil, "", err
+	}
+	n, err := parseBlockParam(v, latest, latest)
+	if err != nil {
+		return nil, "", fmt.Errorf("block: %w", err)
+	}
+	return new(big.Int).SetUint64(n), strconv.FormatUint(n, 10), nil
+}
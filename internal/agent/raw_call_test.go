@@ -0,0 +1,26 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableRawCall(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	_, ok := tr.ToolCapability("raw_call")
+	assert.False(t, ok, "raw_call must be opt-in")
+
+	require.NoError(t, tr.EnableRawCall())
+	capability, ok := tr.ToolCapability("raw_call")
+	require.True(t, ok)
+	assert.Equal(t, ToolReadOnly, capability)
+
+	_, err := tr.ExecuteTool(context.Background(), "raw_call", json.RawMessage(`{"chain":"ethereum","to":"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48","data":"0x7","value":"1"}`))
+	assert.ErrorContains(t, err, "odd number of hex digits")
+	_, err = tr.ExecuteTool(context.Background(), "raw_call", json.RawMessage(`{"chain":"ethereum","to":"0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48","data":"0x18160ddd","value":"-1"}`))
+	assert.ErrorContains(t, err, "whole number of wei")
+}
@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+// namedSlots are the slots read_storage knows by name.
+var namedSlots = map[string]common.Hash{
+	"implementation": chain.EIP1967ImplementationSlot,
+	"admin":          chain.EIP1967AdminSlot,
+	"beacon":         chain.EIP1967BeaconSlot,
+}
+
+type readStorageInput struct {
+	Chain   string   `json:"chain"`
+	Address string   `json:"address"`
+	Slot    string   `json:"slot"`
+	Keys    []string `json:"keys"`
+	Index   *uint64  `json:"index"`
+	Block   string   `json:"block"`
+}
+
+// handleReadStorage reads a contract's storage slot, computing mapping
+// and array slots from the declared slot. Without a slot it reads the
+// EIP-1967 proxy slots, which is what "what's behind this proxy?" needs.
+func (tr *ToolRegistry) handleReadStorage(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params readStorageInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	if _, err := tr.chainClient.GetChainConfig(params.Chain); err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+	addr, err := requireHexAddress("address", params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Slot == "" && (len(params.Keys) > 0 || params.Index != nil) {
+		return ToolOutput{}, fmt.Errorf("keys and index need slot, the slot the mapping or array is declared at")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	block, blockLabel, err := tr.stateBlock(ctx, params.Chain, params.Block)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Slot == "" {
+		return tr.readProxySlots(ctx, params.Chain, addr, block, blockLabel)
+	}
+
+	slot, err := storageSlot(params.Slot, params.Keys, params.Index)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	word, err := tr.chainClient.StorageAt(ctx, params.Chain, addr, slot, block)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("read storage of %s: %w", addr.Hex(), err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Storage of %s on %s at block %s:\n", addr.Hex(), params.Chain, blockLabel)
+	fmt.Fprintf(&b, "- Slot: %s\n", slot.Hex())
+	items := []KVItem{
+		{Key: "Contract", Value: addr.Hex()},
+		{Key: "Block", Value: blockLabel},
+		{Key: "Slot", Value: slot.Hex()},
+	}
+	for _, it := range wordItems(word) {
+		fmt.Fprintf(&b, "- %s: %s\n", it.Key, it.Value)
+		items = append(items, it)
+	}
+	return ToolOutput{Text: b.String(), Blocks: []UIBlock{kvBlock("Storage", items...)}}, nil
+}
+
+// readProxySlots reports the EIP-1967 implementation, admin and beacon. A
+// beacon proxy's implementation is whatever its beacon returns.
+func (tr *ToolRegistry) readProxySlots(ctx context.Context, chainName string, addr common.Address, block *big.Int, blockLabel string) (ToolOutput, error) {
+	read := make(map[string]common.Address, len(namedSlots))
+	for _, name := range []string{"implementation", "admin", "beacon"} {
+		word, err := tr.chainClient.StorageAt(ctx, chainName, addr, namedSlots[name], block)
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("read %s slot of %s: %w", name, addr.Hex(), err)
+		}
+		read[name] = common.BytesToAddress(word)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "EIP-1967 slots of %s on %s at block %s:\n", addr.Hex(), chainName, blockLabel)
+	items := []KVItem{{Key: "Contract", Value: addr.Hex()}, {Key: "Block", Value: blockLabel}}
+	if read["implementation"] == (common.Address{}) && read["beacon"] != (common.Address{}) {
+		if impl, err := tr.beaconImplementation(ctx, chainName, read["beacon"], block); err == nil {
+			read["implementation"] = impl
+			fmt.Fprintf(&b, "- Implementation comes from the beacon's implementation().\n")
+		} else {
+			fmt.Fprintf(&b, "- The beacon's implementation() failed: %v\n", err)
+		}
+	}
+
+	proxy := false
+	for _, name := range []string{"implementation", "admin", "beacon"} {
+		value := "empty"
+		if a := read[name]; a != (common.Address{}) {
+			value = a.Hex()
+			proxy = true
+		}
+		key := strings.ToUpper(name[:1]) + name[1:]
+		fmt.Fprintf(&b, "- %s: %s\n", key, value)
+		items = append(items, KVItem{Key: key, Value: value})
+	}
+	if !proxy {
+		b.WriteString("All three slots are empty, so this is not an EIP-1967 proxy (it may still be another kind of proxy).\n")
+	}
+	return ToolOutput{Text: b.String(), Blocks: []UIBlock{kvBlock("Proxy slots", items...)}}, nil
+}
+
+// beaconImplementation calls implementation() on an EIP-1967 beacon.
+func (tr *ToolRegistry) beaconImplementation(ctx context.Context, chainName string, beacon common.Address, block *big.Int) (common.Address, error) {
+	sel, err := decode.FunctionSelector("implementation()")
+	if err != nil {
+		return common.Address{}, err
+	}
+	out, err := tr.chainClient.CallContractAt(ctx, chainName, ethereum.CallMsg{To: &beacon, Data: sel[:]}, block)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(out) != 32 {
+		return common.Address{}, fmt.Errorf("returned %d bytes, not an address", len(out))
+	}
+	return common.BytesToAddress(out), nil
+}
+
+// storageSlot computes the slot to read: the declared slot, then one
+// mapping step per key (outermost first), then the array element.
+func storageSlot(slot string, keys []string, index *uint64) (common.Hash, error) {
+	s, ok := namedSlots[strings.ToLower(strings.TrimSpace(slot))]
+	if !ok {
+		var err error
+		if s, err = storageWord(slot); err != nil {
+			return common.Hash{}, fmt.Errorf("slot: %w", err)
+		}
+	}
+	for i, k := range keys {
+		key, err := storageWord(k)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("keys[%d]: %w", i, err)
+		}
+		s = chain.MappingSlot(key, s)
+	}
+	if index != nil {
+		s = chain.ArraySlot(s, *index)
+	}
+	return s, nil
+}
+
+// storageWord reads a slot or mapping key as the 32-byte word Solidity
+// hashes: an address, true/false, a 32-byte hex word, or an unsigned
+// integer in decimal or hex.
+func storageWord(v string) (common.Hash, error) {
+	v = strings.TrimSpace(v)
+	switch {
+	case common.IsHexAddress(v):
+		return common.BytesToHash(common.HexToAddress(v).Bytes()), nil
+	case strings.HasPrefix(v, "0x") && len(v) == 66:
+		return common.HexToHash(v), nil
+	}
+	switch v {
+	case "true":
+		return common.BigToHash(big.NewInt(1)), nil
+	case "false":
+		return common.Hash{}, nil
+	}
+	n, ok := new(big.Int).SetString(v, 0)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("%q is not an address, a 32-byte hex word or an unsigned integer", v)
+	}
+	return common.BigToHash(n), nil
+}
+
+// wordItems shows a storage word raw, as a number and, when it looks like
+// one, as an address: more than 64 bits is unlikely to be a count, and an
+// address fills at most the low 160.
+func wordItems(word []byte) []KVItem {
+	w := common.BytesToHash(word)
+	items := []KVItem{
+		{Key: "Raw", Value: w.Hex()},
+		{Key: "As uint256", Value: w.Big().String()},
+	}
+	if n := w.Big(); n.BitLen() > 64 && n.BitLen() <= 160 {
+		items = append(items, KVItem{Key: "As address", Value: common.BytesToAddress(word).Hex()})
+	}
+	if w == (common.Hash{}) {
+		items = append(items, KVItem{Key: "Note", Value: "empty slot: zero, false, or never written"})
+	}
+	return items
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+func TestStorageSlot(t *testing.T) {
+	s, err := storageSlot("Implementation", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, chain.EIP1967ImplementationSlot, s)
+
+	// balances[holder] with balances declared at slot 9.
+	holder := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	s, err = storageSlot("9", []string{holder.Hex()}, nil)
+	require.NoError(t, err)
+	want := crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), 32), common.LeftPadBytes([]byte{9}, 32))
+	assert.Equal(t, want, s)
+
+	// allowances[owner][spender] nests outermost first.
+	s, err = storageSlot("0x0a", []string{holder.Hex(), "1"}, nil)
+	require.NoError(t, err)
+	inner := crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), 32), common.LeftPadBytes([]byte{10}, 32))
+	assert.Equal(t, crypto.Keccak256Hash(common.LeftPadBytes([]byte{1}, 32), inner.Bytes()), s)
+
+	_, err = storageSlot("3", []string{"alice"}, nil)
+	assert.ErrorContains(t, err, "keys[0]")
+	_, err = storageSlot("-1", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestWordItems(t *testing.T) {
+	addr := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	items := wordItems(common.LeftPadBytes(addr.Bytes(), 32))
+	require.Len(t, items, 3)
+	assert.Equal(t, addr.Hex(), items[2].Value)
+
+	items = wordItems(common.LeftPadBytes([]byte{42}, 32))
+	require.Len(t, items, 2)
+	assert.Equal(t, "42", items[1].Value)
+}
@@ -5,15 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
 
 	_ "modernc.org/sqlite"
 )
 
 // ReceiptStore persists transaction receipts for later retrieval.
 // It is intentionally minimal: append-only table keyed by tx hash + chain.
+// The same DB also holds token metadata (see GetTokenMetadata), the
+// ledger of asset flows used for P&L (see ledger.go), what the gas report
+// needs beyond the receipts (see gas_report.go) and every transaction
+// sent, mined or not (see account_state.go), LLM usage per day (see
+// llm_usage.go) and chat history (see chat_history.go).
 type ReceiptStore struct {
 	db *sql.DB
 }
@@ -29,42 +38,64 @@ type StoredReceipt struct {
 
 // OpenReceiptStore opens (or creates) the receipt DB under dataDir/receipts.db.
 func OpenReceiptStore(dataDir string) (*ReceiptStore, error) {
-	dbPath := filepath.Join(dataDir, "receipts.db")
+	dbPath := filepath.Join(dataDir, ReceiptDBFile)
 	return OpenReceiptStoreDSN(dbPath)
 }
 
 // OpenReceiptStoreDSN opens (or creates) a receipt DB using the given sqlite DSN/path.
-// Tests may pass ":memory:" to avoid touching disk.
+// Tests may pass ":memory:" to avoid touching disk. Pending schema
+// migrations are applied first; see migrate.go.
 func OpenReceiptStoreDSN(dsn string) (*ReceiptStore, error) {
+	s, _, err := openReceiptStore(dsn)
+	return s, err
+}
+
+// openReceiptStore is OpenReceiptStoreDSN that also returns the backup
+// taken before migrating, if any.
+func openReceiptStore(dsn string) (*ReceiptStore, string, error) {
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
-		return nil, fmt.Errorf("open receipts db: %w", err)
+		return nil, "", fmt.Errorf("open receipts db: %w", err)
+	}
+	if dsn == ":memory:" {
+		// Every connection to :memory: gets its own empty database.
+		db.SetMaxOpenConns(1)
 	}
 
-	if err := ensureSchema(db); err != nil {
+	backup, err := migrate(db, dbFilePath(dsn))
+	if err != nil {
 		_ = db.Close()
-		return nil, err
+		return nil, "", err
+	}
+	if err := seedTokenMetadata(db); err != nil {
+		_ = db.Close()
+		return nil, "", err
 	}
 
-	return &ReceiptStore{db: db}, nil
+	return &ReceiptStore{db: db}, backup, nil
 }
 
-func ensureSchema(db *sql.DB) error {
-	_, err := db.Exec(`
-CREATE TABLE IF NOT EXISTS receipts (
-	chain TEXT NOT NULL,
-	tx_hash TEXT NOT NULL,
-	status INTEGER,
-	gas_used INTEGER,
-	raw_json TEXT,
-	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-	PRIMARY KEY (chain, tx_hash)
-);
-`)
+// seedTokenMetadata inserts chain.KnownTokens without overwriting rows that
+// are already present.
+func seedTokenMetadata(db *sql.DB) error {
+	tx, err := db.Begin()
 	if err != nil {
-		return fmt.Errorf("create receipts table: %w", err)
+		return fmt.Errorf("seed token metadata: %w", err)
 	}
-	return nil
+	defer func() { _ = tx.Rollback() }()
+
+	for chainName, tokens := range chain.KnownTokens() {
+		for _, meta := range tokens {
+			_, err := tx.Exec(
+				`INSERT OR IGNORE INTO token_metadata (chain, address, symbol, name, decimals) VALUES (?, ?, ?, ?, ?)`,
+				chainName, tokenKey(meta.Address), meta.Symbol, meta.Name, meta.Decimals,
+			)
+			if err != nil {
+				return fmt.Errorf("seed token metadata: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
 }
 
 // Close closes the underlying DB.
@@ -75,6 +106,8 @@ func (s *ReceiptStore) Close() error {
 	return s.db.Close()
 }
 
+// Upsert stores a mined receipt, and its outcome when clifi sent the
+// transaction.
 func (s *ReceiptStore) Upsert(chain string, receipt *types.Receipt) error {
 	if s == nil || s.db == nil {
 		return fmt.Errorf("receipt store not initialized")
@@ -102,7 +135,11 @@ ON CONFLICT(chain, tx_hash) DO UPDATE SET
 	if err != nil {
 		return fmt.Errorf("persist receipt: %w", err)
 	}
-	return nil
+	status := SentConfirmed
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = SentFailed
+	}
+	return s.MarkSent(chain, receipt.TxHash.Hex(), status)
 }
 
 func (s *ReceiptStore) Get(chain, txHash string) (*StoredReceipt, error) {
@@ -127,3 +164,98 @@ func (s *ReceiptStore) Get(chain, txHash string) (*StoredReceipt, error) {
 	}
 	return &out, nil
 }
+
+// tokenKey normalizes a token address for use as a key.
+func tokenKey(address string) string {
+	return strings.ToLower(common.HexToAddress(address).Hex())
+}
+
+// GetTokenMetadata implements chain.TokenMetadataStore.
+func (s *ReceiptStore) GetTokenMetadata(chainName string, token common.Address) (chain.TokenMetadata, bool) {
+	if s == nil || s.db == nil {
+		return chain.TokenMetadata{}, false
+	}
+	meta := chain.TokenMetadata{Address: token.Hex()}
+	err := s.db.QueryRow(
+		`SELECT symbol, name, decimals FROM token_metadata WHERE chain = ? AND address = ?`,
+		chainName, tokenKey(token.Hex()),
+	).Scan(&meta.Symbol, &meta.Name, &meta.Decimals)
+	if err != nil {
+		return chain.TokenMetadata{}, false
+	}
+	return meta, true
+}
+
+// PutTokenMetadata implements chain.TokenMetadataStore.
+func (s *ReceiptStore) PutTokenMetadata(chainName string, meta chain.TokenMetadata) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("receipt store not initialized")
+	}
+	_, err := s.db.Exec(`
+INSERT INTO token_metadata (chain, address, symbol, name, decimals)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT(chain, address) DO UPDATE SET
+	symbol=excluded.symbol,
+	name=excluded.name,
+	decimals=excluded.decimals
+`, chainName, tokenKey(meta.Address), meta.Symbol, meta.Name, meta.Decimals)
+	if err != nil {
+		return fmt.Errorf("persist token metadata: %w", err)
+	}
+	return nil
+}
+
+// txHashPattern finds transaction hashes in tool output.
+var txHashPattern = regexp.MustCompile(`0x[0-9a-fA-F]{64}\b`)
+
+// conversationReceipts looks up stored receipts for the transaction hashes in
+// the turns' tool results. The chain comes from the originating tool call's
+// "chain" argument; hashes without a stored receipt are skipped.
+func (tr *ToolRegistry) conversationReceipts(turns []ConversationTurn) []ConversationReceipt {
+	chains := make(map[string]string)
+	var hashes, hashChains []string
+	for _, turn := range turns {
+		for _, tc := range turn.ToolCalls {
+			var args struct {
+				Chain string `json:"chain"`
+			}
+			if json.Unmarshal(tc.Input, &args) == nil && args.Chain != "" {
+				chains[tc.ID] = args.Chain
+			}
+		}
+		if turn.ToolResult == nil || turn.ToolResult.IsError {
+			continue
+		}
+		chainName, ok := chains[turn.ToolResult.ToolUseID]
+		if !ok {
+			continue
+		}
+		for _, h := range txHashPattern.FindAllString(turn.ToolResult.Content, -1) {
+			hashes = append(hashes, strings.ToLower(h))
+			hashChains = append(hashChains, chainName)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	store, err := tr.receiptStore()
+	if err != nil {
+		return nil
+	}
+	var out []ConversationReceipt
+	seen := make(map[string]bool)
+	for i, h := range hashes {
+		key := hashChains[i] + "/" + h
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		r, err := store.Get(hashChains[i], h)
+		if err != nil {
+			continue
+		}
+		out = append(out, ConversationReceipt{Chain: r.Chain, TxHash: r.TxHash, Status: r.Status, GasUsed: r.GasUsed})
+	}
+	return out
+}
@@ -1,8 +1,19 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"math/big"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/pnl"
 )
 
 func TestReceiptStore_CreateAndClose(t *testing.T) {
@@ -21,3 +32,154 @@ func TestReceiptStore_CreateAndClose(t *testing.T) {
 		t.Fatalf("expected db file: %v", err)
 	}
 }
+
+func TestReceiptStore_TokenMetadata(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := OpenReceiptStore(dataDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	meta, ok := store.GetTokenMetadata("ethereum", usdc)
+	if !ok || meta.Symbol != "USDC" || meta.Decimals != 6 {
+		t.Fatalf("expected seeded USDC, got %+v (ok=%v)", meta, ok)
+	}
+	if meta.Address != usdc.Hex() {
+		t.Fatalf("expected checksummed address, got %s", meta.Address)
+	}
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	if _, ok := store.GetTokenMetadata("base", token); ok {
+		t.Fatalf("unexpected metadata for unknown token")
+	}
+	if err := store.PutTokenMetadata("base", chain.TokenMetadata{Address: token.Hex(), Symbol: "AA", Name: "Aa", Decimals: 9}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	_ = store.Close()
+
+	// Persisted across reopen, and seeding does not overwrite stored rows.
+	store, err = OpenReceiptStore(dataDir)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	meta, ok = store.GetTokenMetadata("base", token)
+	if !ok || meta.Symbol != "AA" || meta.Decimals != 9 {
+		t.Fatalf("expected persisted metadata, got %+v (ok=%v)", meta, ok)
+	}
+	if _, ok := store.GetTokenMetadata("ethereum", token); ok {
+		t.Fatalf("metadata must be keyed by chain")
+	}
+}
+
+func TestConversationReceipts(t *testing.T) {
+	tr := NewToolRegistryWithDataDir("")
+	defer tr.Close()
+	store, err := tr.receiptStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	hash := common.HexToHash("0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b")
+	if err := store.Upsert("base", &types.Receipt{TxHash: hash, Status: 1, GasUsed: 21000}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	turns := []ConversationTurn{
+		{Role: "assistant", ToolCalls: []llm.ToolCall{{ID: "tc_1", Name: "send_native", Input: json.RawMessage(`{"chain":"base"}`)}}},
+		{Role: "tool", ToolResult: &llm.ToolResult{ToolUseID: "tc_1", Content: "Tx hash: " + hash.Hex() + "\nAgain: " + hash.Hex()}},
+		{Role: "tool", ToolResult: &llm.ToolResult{ToolUseID: "unknown", Content: "Tx hash: " + hash.Hex()}},
+	}
+	receipts := tr.conversationReceipts(turns)
+	want := ConversationReceipt{Chain: "base", TxHash: hash.Hex(), Status: 1, GasUsed: 21000}
+	if len(receipts) != 1 || receipts[0] != want {
+		t.Fatalf("expected one receipt %+v, got %+v", want, receipts)
+	}
+}
+
+func TestReceiptStore_Ledger(t *testing.T) {
+	store, err := OpenReceiptStoreDSN(":memory:")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	addr := "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := []pnl.Event{
+		{Chain: "base", TxHash: "0xAB", Index: 0, Address: addr, Symbol: "ETH", Amount: -1, PriceUSD: 3000, Kind: pnl.KindTransfer, At: at},
+		{Chain: "base", TxHash: "0xAB", Index: 1, Address: addr, Symbol: "ETH", Amount: -0.001, PriceUSD: 3000, Kind: pnl.KindFee, At: at},
+	}
+	if err := store.AddLedger(first); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	// Recording the same tx again keeps the original prices.
+	again := append([]pnl.Event(nil), first...)
+	again[0].PriceUSD = 9999
+	if err := store.AddLedger(again); err != nil {
+		t.Fatalf("add again: %v", err)
+	}
+
+	got, err := store.Ledger(strings.ToLower(addr))
+	if err != nil {
+		t.Fatalf("ledger: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].PriceUSD != 3000 || got[1].Kind != pnl.KindFee || !got[0].At.Equal(at) {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+}
+
+func TestGasReport_CachedContext(t *testing.T) {
+	tr := NewToolRegistryWithDataDir("")
+	defer tr.Close()
+	store, err := tr.receiptStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	ours := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	gwei := big.NewInt(1e9)
+	add := func(chainName string, n int64, sender common.Address, cache bool) common.Hash {
+		hash := common.BigToHash(big.NewInt(n))
+		r := &types.Receipt{TxHash: hash, Status: 1, GasUsed: 21000, EffectiveGasPrice: new(big.Int).Mul(big.NewInt(30), gwei), BlockNumber: big.NewInt(100)}
+		if err := store.Upsert(chainName, r); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if cache {
+			if err := store.putGasContext(chainName, hash.Hex(), sender, at, new(big.Int).Mul(big.NewInt(10), gwei)); err != nil {
+				t.Fatalf("put gas context: %v", err)
+			}
+		}
+		return hash
+	}
+	mine := add("ethereum", 1, ours, true)
+	add("ethereum", 2, other, true)
+	// Not cached, and the chain is unknown, so it cannot be resolved.
+	add("nowhere", 3, ours, false)
+	if err := store.AddLedger([]pnl.Event{{Chain: "ethereum", TxHash: mine.Hex(), Index: 1, Address: ours.Hex(), Symbol: "ETH", Amount: -0.00063, PriceUSD: 3000, Kind: pnl.KindFee, At: at}}); err != nil {
+		t.Fatalf("add ledger: %v", err)
+	}
+
+	report, err := tr.GasReport(context.Background(), []common.Address{ours}, 1)
+	if err != nil {
+		t.Fatalf("gas report: %v", err)
+	}
+	if len(report.Months) != 1 || report.Months[0].Txs != 1 || report.Months[0].Month != "2026-03" {
+		t.Fatalf("expected one March tx, got %+v", report.Months)
+	}
+	if got := report.Months[0].FeeUSD; got < 1.88 || got > 1.9 {
+		t.Fatalf("expected the ledger's $1.89 fee, got %v", got)
+	}
+	if len(report.Overpaid) != 1 || report.Overpaid[0].Hash != mine.Hex() {
+		t.Fatalf("expected the 200%% premium flagged, got %+v", report.Overpaid)
+	}
+	if report.Unresolved != 1 {
+		t.Fatalf("expected one unresolved receipt, got %d", report.Unresolved)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"strings"
 )
 
+// redactedValue replaces secrets in redacted tool arguments.
+const redactedValue = "***REDACTED***"
+
 var redactKeys = map[string]struct{}{
 	"password":      {},
 	"api_key":       {},
@@ -40,7 +43,7 @@ func redactValue(v any) any {
 		out := make(map[string]any, len(t))
 		for k, vv := range t {
 			if _, ok := redactKeys[strings.ToLower(k)]; ok {
-				out[k] = "***REDACTED***"
+				out[k] = redactedValue
 				continue
 			}
 			out[k] = redactValue(vv)
@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// ToolHandler runs one tool call. ToolOutput.Text is returned to the LLM.
+type ToolHandler func(ctx context.Context, input json.RawMessage) (ToolOutput, error)
+
+// ToolCapability says what a tool can do, so callers can gate tools that
+// sign (plan modes, read-only embeddings) without knowing them by name.
+type ToolCapability int
+
+const (
+	// ToolReadOnly tools only read chain or local state.
+	ToolReadOnly ToolCapability = iota
+	// ToolSigning tools can sign and broadcast transactions.
+	ToolSigning
+)
+
+func (c ToolCapability) String() string {
+	if c == ToolSigning {
+		return "signing"
+	}
+	return "read-only"
+}
+
+// signingTools are the built-in tools that can broadcast. create_dca and
+// create_limit_order only schedule swaps, but those can be signed later
+// without a prompt.
+var signingTools = []string{"send_native", "send_token", "approve_token", "sign_permit", "send_token_gasless", "swap", "create_dca", "create_limit_order", "send_sol", "send_cosmos"}
+
+// RegisterTool adds a tool the LLM can call. The name must not already be
+// registered; remove a built-in first to replace it. Safe to call while
+// the agent is running: the tool is offered from the next request.
+func (tr *ToolRegistry) RegisterTool(tool llm.Tool, handler ToolHandler, capability ToolCapability) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %s: handler is required", tool.Name)
+	}
+	if len(tool.InputSchema) == 0 {
+		tool.InputSchema = json.RawMessage(`{"type": "object", "properties": {}}`)
+	}
+	if !json.Valid(tool.InputSchema) {
+		return fmt.Errorf("tool %s: input schema is not valid JSON", tool.Name)
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if _, ok := tr.handlers[tool.Name]; ok {
+		return fmt.Errorf("tool %s is already registered", tool.Name)
+	}
+	tr.tools = append(tr.tools, withPageParam([]llm.Tool{tool})...)
+	tr.handlers[tool.Name] = handler
+	tr.capabilities[tool.Name] = capability
+	return nil
+}
+
+// RemoveTool unregisters a tool, reporting whether it was registered.
+func (tr *ToolRegistry) RemoveTool(name string) bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if _, ok := tr.handlers[name]; !ok {
+		return false
+	}
+	tr.tools = slices.DeleteFunc(tr.tools, func(t llm.Tool) bool { return t.Name == name })
+	delete(tr.handlers, name)
+	delete(tr.capabilities, name)
+	return true
+}
+
+// ToolCapability returns a registered tool's capability.
+func (tr *ToolRegistry) ToolCapability(name string) (ToolCapability, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	if _, ok := tr.handlers[name]; !ok {
+		return 0, false
+	}
+	return tr.capabilities[name], true
+}
+
+func (tr *ToolRegistry) handler(name string) (ToolHandler, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	h, ok := tr.handlers[name]
+	return h, ok
+}
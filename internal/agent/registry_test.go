@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func echoHandler(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	return ToolOutput{Text: string(input)}, nil
+}
+
+func TestRegisterTool(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+	before := len(tr.GetTools())
+
+	require.NoError(t, tr.RegisterTool(llm.Tool{Name: "echo", Description: "Echo the input"}, echoHandler, ToolReadOnly))
+	assert.Len(t, tr.GetTools(), before+1)
+
+	out, err := tr.ExecuteTool(context.Background(), "echo", json.RawMessage(`{"x":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"x":1}`, out.Text)
+
+	assert.ErrorContains(t, tr.RegisterTool(llm.Tool{Name: "echo"}, echoHandler, ToolReadOnly), "already registered")
+	assert.ErrorContains(t, tr.RegisterTool(llm.Tool{Name: "bad", InputSchema: json.RawMessage(`{`)}, echoHandler, ToolReadOnly), "not valid JSON")
+	assert.Error(t, tr.RegisterTool(llm.Tool{Name: "nil"}, nil, ToolReadOnly))
+
+	assert.True(t, tr.RemoveTool("echo"))
+	assert.False(t, tr.RemoveTool("echo"))
+	assert.Len(t, tr.GetTools(), before)
+	_, err = tr.ExecuteTool(context.Background(), "echo", nil)
+	assert.ErrorContains(t, err, "unknown tool")
+}
+
+func TestToolCapability(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+
+	for _, tool := range tr.GetTools() {
+		c, ok := tr.ToolCapability(tool.Name)
+		require.True(t, ok, tool.Name)
+		// Built-in tools that broadcast all take confirm.
+		assert.Equal(t, hasConfirm(tool), c == ToolSigning, tool.Name)
+	}
+
+	// Replacing a built-in keeps the caller's capability.
+	require.True(t, tr.RemoveTool("send_native"))
+	require.NoError(t, tr.RegisterTool(llm.Tool{Name: "send_native"}, echoHandler, ToolReadOnly))
+	c, _ := tr.ToolCapability("send_native")
+	assert.Equal(t, ToolReadOnly, c)
+
+	_, ok := tr.ToolCapability("nope")
+	assert.False(t, ok)
+}
+
+func hasConfirm(tool llm.Tool) bool {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	_ = json.Unmarshal(tool.InputSchema, &schema)
+	_, ok := schema.Properties["confirm"]
+	return ok
+}
+
+func TestRegisterTool_Concurrent(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("tool_%d", i)
+			assert.NoError(t, tr.RegisterTool(llm.Tool{Name: name}, echoHandler, ToolReadOnly))
+			tr.RemoveTool(name)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = tr.GetTools()
+			_, _ = tr.ExecuteTool(context.Background(), "list_chains", nil)
+		}()
+	}
+	wg.Wait()
+}
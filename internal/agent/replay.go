@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// ReplayCall is a recorded tool call and the result it produced.
+type ReplayCall struct {
+	Name          string
+	Input         json.RawMessage
+	Recorded      string
+	RecordedError bool
+}
+
+// LoadReplay reads the tool calls from a recorded conversation: a /export
+// JSON file, or a session log (.jsonl) from the data dir's sessions folder.
+func LoadReplay(path string) ([]ReplayCall, error) {
+	if strings.EqualFold(filepath.Ext(path), ".jsonl") {
+		return loadSessionCalls(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return conversationCalls(&conv), nil
+}
+
+// conversationCalls pairs each tool call with its result by tool use ID.
+func conversationCalls(conv *Conversation) []ReplayCall {
+	results := make(map[string]*llm.ToolResult)
+	for _, turn := range conv.Turns {
+		if turn.ToolResult != nil {
+			results[turn.ToolResult.ToolUseID] = turn.ToolResult
+		}
+	}
+	var calls []ReplayCall
+	for _, turn := range conv.Turns {
+		for _, tc := range turn.ToolCalls {
+			call := ReplayCall{Name: tc.Name, Input: tc.Input}
+			if r := results[tc.ID]; r != nil {
+				call.Recorded = r.Content
+				call.RecordedError = r.IsError
+			}
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// loadSessionCalls pairs tool_call and tool_result records in order; the
+// agent runs a turn's tool calls one at a time, so each result follows its
+// call.
+func loadSessionCalls(path string) ([]ReplayCall, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var calls []ReplayCall
+	pending := -1
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(strings.TrimSpace(sc.Text())) == 0 {
+			continue
+		}
+		var rec sessionRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		switch rec.Type {
+		case "tool_call":
+			calls = append(calls, ReplayCall{Name: rec.ToolName, Input: json.RawMessage(rec.Args)})
+			pending = len(calls) - 1
+		case "tool_result":
+			if pending >= 0 && calls[pending].Name == rec.ToolName {
+				calls[pending].Recorded = rec.Text
+				calls[pending].RecordedError = rec.IsError
+				pending = -1
+			}
+		}
+	}
+	return calls, sc.Err()
+}
+
+// ReplayOptions controls how recorded calls are re-executed.
+type ReplayOptions struct {
+	// Forks points chains at fork RPC endpoints (e.g. anvil --fork-url).
+	// Confirmed sends run only on forked chains; elsewhere they are skipped.
+	Forks map[string]string
+	// Password unlocks the wallet for confirmed sends on forks, since
+	// recordings have passwords redacted. It is only called when needed.
+	Password func() (string, error)
+}
+
+// ReplayResult is the outcome of re-executing one recorded call.
+type ReplayResult struct {
+	Call    ReplayCall
+	Output  string
+	IsError bool
+	// Skipped explains why the call wasn't run.
+	Skipped string
+	// Diff is a line diff from the recorded result, empty when unchanged.
+	Diff string
+}
+
+// Replay re-executes calls against the current tool handlers and diffs
+// each result against the recorded one.
+func (tr *ToolRegistry) Replay(ctx context.Context, calls []ReplayCall, opts ReplayOptions) ([]ReplayResult, error) {
+	if len(opts.Forks) > 0 {
+		if err := tr.applyForks(opts.Forks); err != nil {
+			return nil, err
+		}
+	}
+
+	var password string
+	results := make([]ReplayResult, 0, len(calls))
+	for _, call := range calls {
+		res := ReplayResult{Call: call}
+		input, confirmed, err := replayInput(call.Input)
+		if err != nil {
+			res.Skipped = fmt.Sprintf("unreadable input: %v", err)
+			results = append(results, res)
+			continue
+		}
+		if confirmed != nil {
+			chainName, _ := confirmed["chain"].(string)
+			if _, ok := opts.Forks[chainName]; !ok {
+				res.Skipped = "would broadcast; fork the chain to replay it"
+				results = append(results, res)
+				continue
+			}
+			if password == "" && opts.Password != nil && hasRedacted(confirmed) {
+				if password, err = opts.Password(); err != nil {
+					return results, err
+				}
+			}
+			fillRedacted(confirmed, password)
+			input, _ = json.Marshal(confirmed)
+		}
+
+		out, err := tr.ExecuteTool(ctx, call.Name, input)
+		if err != nil {
+			res.Output = fmt.Sprintf("Error: %v", err)
+			res.IsError = true
+		} else {
+			res.Output = out.Text
+		}
+		res.Diff = lineDiff(call.Recorded, res.Output)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// applyForks points each forked chain at its fork for this registry only;
+// chains.yaml is left as is.
+func (tr *ToolRegistry) applyForks(forks map[string]string) error {
+	settings, err := chain.LoadSettings(tr.dataDir)
+	if err != nil {
+		return err
+	}
+	if settings.RPCURLs == nil {
+		settings.RPCURLs = make(map[string][]string)
+	}
+	for name, url := range forks {
+		if _, ok := chain.DefaultChains()[name]; !ok {
+			return fmt.Errorf("unknown chain to fork: %s", name)
+		}
+		settings.RPCURLs[name] = []string{url}
+	}
+	tr.chainClient.ApplySettings(settings)
+	return nil
+}
+
+// replayInput returns the call's input and, when it asks to broadcast
+// (confirm=true), the decoded arguments so they can be checked and
+// completed.
+func replayInput(raw json.RawMessage) (json.RawMessage, map[string]any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return json.RawMessage("{}"), nil, nil
+	}
+	var args map[string]any
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, nil, err
+	}
+	if confirm, _ := args["confirm"].(bool); confirm {
+		return raw, args, nil
+	}
+	return raw, nil, nil
+}
+
+func hasRedacted(args map[string]any) bool {
+	for _, v := range args {
+		if v == redactedValue {
+			return true
+		}
+	}
+	return false
+}
+
+func fillRedacted(args map[string]any, password string) {
+	for k, v := range args {
+		if v == redactedValue {
+			args[k] = password
+		}
+	}
+}
+
+// lineDiff returns a -/+ line diff of a and b, or "" when they are equal.
+func lineDiff(a, b string) string {
+	if a == b {
+		return ""
+	}
+	x := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	y := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] is the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(&out, "  %s\n", x[i])
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "- %s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "+ %s\n", y[j])
+			j++
+		}
+	}
+	return out.String()
+}
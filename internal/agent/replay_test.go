@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestReplay_SessionLogAndExport(t *testing.T) {
+	dataDir := t.TempDir()
+	send, _ := json.Marshal(map[string]any{
+		"chain": "base", "to": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"amount_eth": "1", "confirm": true, "password": "pw",
+	})
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{{Name: "list_chains", Input: json.RawMessage(`{}`)}}},
+		{ToolCalls: []llm.ToolCall{{Name: "no_such_tool", Input: json.RawMessage(`{}`)}}},
+		{ToolCalls: []llm.ToolCall{{Name: "send_native", Input: send}}},
+		{Content: "done"},
+	}})
+	a := NewWithProvider(provider, dataDir)
+	_, err := a.Chat(context.Background(), "go")
+	require.NoError(t, err)
+	a.Close()
+
+	logs, err := filepath.Glob(filepath.Join(dataDir, "sessions", "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	fromLog, err := LoadReplay(logs[0])
+	require.NoError(t, err)
+
+	exportPath := filepath.Join(t.TempDir(), "conv.json")
+	data, err := a.Export().ToJSON()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(exportPath, data, 0600))
+	fromExport, err := LoadReplay(exportPath)
+	require.NoError(t, err)
+
+	require.Len(t, fromLog, 3)
+	require.Len(t, fromExport, 3)
+	for i := range fromLog {
+		assert.Equal(t, fromLog[i].Name, fromExport[i].Name)
+		assert.Equal(t, fromLog[i].Recorded, fromExport[i].Recorded)
+	}
+	assert.True(t, fromLog[1].RecordedError)
+
+	tr := NewToolRegistryWithDataDir(dataDir)
+	defer tr.Close()
+
+	// A handler change shows up as a diff.
+	fromExport[0].Recorded += "\n- oldchain (Old, Chain ID: 1)"
+	results, err := tr.Replay(context.Background(), fromExport, ReplayOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Contains(t, results[0].Diff, "- - oldchain (Old, Chain ID: 1)")
+	assert.Empty(t, results[1].Diff, "errors replay too")
+	assert.Contains(t, results[2].Skipped, "would broadcast")
+	assert.Empty(t, results[2].Output)
+}
+
+func TestLineDiff(t *testing.T) {
+	assert.Empty(t, lineDiff("a\nb", "a\nb"))
+	assert.Equal(t, "  a\n- b\n+ B\n  c\n", lineDiff("a\nb\nc", "a\nB\nc"))
+	assert.Equal(t, "  a\n+ b\n", lineDiff("a", "a\nb"))
+}
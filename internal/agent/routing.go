@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// Task is the kind of work an LLM request does, which a RoutingPolicy can
+// send to a cheaper model than the one picked with /model.
+type Task string
+
+const (
+	// TaskPlan is the first request of a turn: it reads the user's message
+	// and decides which tools to call.
+	TaskPlan Task = "plan"
+	// TaskExecute covers the requests carrying tool results back. They may
+	// call further tools, but mostly phrase the results for the user.
+	TaskExecute Task = "execute"
+	// TaskSummarize condenses the conversation for Compact.
+	TaskSummarize Task = "summarize"
+)
+
+// Tasks lists every task a policy can route.
+var Tasks = []Task{TaskPlan, TaskExecute, TaskSummarize}
+
+// RoutingPolicy maps each provider's tasks to model IDs. Tasks left out
+// use the provider's current model.
+type RoutingPolicy map[llm.ProviderID]map[Task]string
+
+// SetRouting replaces the routing policy. It applies from the next
+// request.
+func (a *Agent) SetRouting(p RoutingPolicy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.routing = p
+}
+
+// modelFor returns the model a task runs on with the provider.
+func (c settings) modelFor(task Task) string {
+	if m := c.routing[c.provider.ID()][task]; m != "" {
+		return m
+	}
+	return c.model
+}
+
+// executeModel is the model for tool results. A routed model that can't
+// call tools would break a turn that still needs them, so the planning
+// model carries on instead. Callers hold mu.
+func (s *Session) executeModel(ctx context.Context, planModel string) string {
+	m := s.cur.modelFor(TaskExecute)
+	if m == planModel {
+		return m
+	}
+	if supports, known := llm.SupportsToolsForModel(ctx, s.cur.provider, m, s.agent.getOpenRouterAPIKey()); known && !supports {
+		return planModel
+	}
+	return m
+}
+
+const compactPrompt = `Summarize the conversation below for your own future reference, so it can continue without the full history. Keep every address, chain, token, amount, transaction hash and wallet the user mentioned or that came up, and any preference or decision the user stated. Leave out pleasantries. Reply with the summary only, as short bullet points.`
+
+// Compact replaces the conversation with a summary written by the
+// summarize model, freeing the context window. The transcript, and so
+// /export, keeps the full history.
+func (s *Session) Compact(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.agent
+	if err := s.sync(); err != nil {
+		return "", err
+	}
+	if len(s.conversation) == 0 {
+		return "", fmt.Errorf("nothing to compact yet")
+	}
+
+	var history strings.Builder
+	if s.summary != "" {
+		fmt.Fprintf(&history, "Earlier summary:\n%s\n\n", s.summary)
+	}
+	for _, m := range s.conversation {
+		fmt.Fprintf(&history, "%s: %s\n\n", m.Role, m.Content)
+	}
+	req := &llm.ChatRequest{
+		SystemPrompt: compactPrompt,
+		Messages:     []llm.Message{{Role: "user", Content: history.String()}},
+		Model:        s.cur.modelFor(TaskSummarize),
+	}
+	if err := s.checkBudget(req.Model); err != nil {
+		return "", err
+	}
+	s.applySampling(req)
+	start := time.Now()
+	response, err := s.cur.provider.Chat(ctx, req)
+	s.logProviderCall("compact", req.Model, start, response, err)
+	s.debugExchange("compact", start, req, nil, nil, response, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize the conversation: %w", err)
+	}
+	s.addUsage(req.Model, response.Usage)
+	summary := strings.TrimSpace(response.Content)
+	if summary == "" {
+		return "", fmt.Errorf("the model returned an empty summary")
+	}
+
+	s.summary = summary
+	s.conversation = make([]llm.Message, 0)
+	s.recordContext(llm.Usage{}, a.toolRegistry.GetTools())
+	return summary, nil
+}
@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// routedProvider records the model and system prompt of each request.
+type routedProvider struct {
+	toolCallProvider
+	models  []string
+	prompts []string
+}
+
+func (p *routedProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	p.models = append(p.models, req.Model)
+	p.prompts = append(p.prompts, req.SystemPrompt)
+	if len(req.Tools) == 0 {
+		return &llm.ChatResponse{Content: "- user sends to 0xabc on base"}, nil
+	}
+	return p.toolCallProvider.Chat(ctx, req)
+}
+
+func (p *routedProvider) ChatWithToolResults(ctx context.Context, req *llm.ChatRequest, calls []llm.ToolCall, results []llm.ToolResult) (*llm.ChatResponse, error) {
+	p.models = append(p.models, req.Model)
+	return p.toolCallProvider.ChatWithToolResults(ctx, req, calls, results)
+}
+
+func newRoutedAgent(t *testing.T) (*Agent, *routedProvider) {
+	ag := newTestAgent()
+	t.Cleanup(ag.toolRegistry.Close)
+	p := &routedProvider{toolCallProvider: toolCallProvider{
+		testProvider: *newTestProvider(),
+		call:         llm.ToolCall{ID: "tc_1", Name: "get_gas_price", Input: json.RawMessage(`{"chain":"nonexistent"}`)},
+	}}
+	ag.provider = p
+	return ag, p
+}
+
+func TestAgent_Routing(t *testing.T) {
+	ag, p := newRoutedAgent(t)
+	_, err := ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-model-a", "test-model-a"}, p.models)
+
+	ag.SetRouting(RoutingPolicy{"test": {TaskExecute: "test-model-b"}, "other": {TaskPlan: "x"}})
+	p.models = nil
+	_, err = ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-model-a", "test-model-b"}, p.models)
+
+	// test-model-c has no tools, so tool results stay on the planning model.
+	ag.SetRouting(RoutingPolicy{"test": {TaskExecute: "test-model-c"}})
+	p.models = nil
+	_, err = ag.ChatWithEvents(context.Background(), "gas?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-model-a", "test-model-a"}, p.models)
+}
+
+func TestAgent_Compact(t *testing.T) {
+	ag, p := newRoutedAgent(t)
+	_, err := ag.Compact(context.Background())
+	assert.Error(t, err)
+
+	_, err = ag.ChatWithEvents(context.Background(), "send 5 USDC to 0xabc on base")
+	require.NoError(t, err)
+	ag.SetRouting(RoutingPolicy{"test": {TaskSummarize: "test-model-c"}})
+	p.models = nil
+
+	summary, err := ag.Compact(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "- user sends to 0xabc on base", summary)
+	assert.Equal(t, []string{"test-model-c"}, p.models)
+	assert.Empty(t, ag.main.conversation)
+	assert.Len(t, ag.Export().Turns, 4, "the transcript keeps the full history")
+
+	_, err = ag.ChatWithEvents(context.Background(), "again")
+	require.NoError(t, err)
+	assert.Contains(t, p.prompts[len(p.prompts)-1], summary)
+
+	ag.Reset()
+	assert.Empty(t, ag.main.summary)
+}
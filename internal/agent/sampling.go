@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// ProviderSampling is one provider's generation parameters: Default for
+// every model, with per-model overrides keyed by model ID.
+type ProviderSampling struct {
+	Default llm.Sampling
+	Models  map[string]llm.Sampling
+}
+
+// SamplingPolicy maps providers to their generation parameters.
+type SamplingPolicy map[llm.ProviderID]ProviderSampling
+
+// SetSampling replaces the configured generation parameters. It applies
+// from the next request.
+func (a *Agent) SetSampling(p SamplingPolicy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sampling = p
+}
+
+// SetSamplingParam overrides one parameter for the rest of the session,
+// as /set does; "default" drops the override. The result is checked
+// against the current provider's ranges.
+func (s *Session) SetSamplingParam(name, value string) (llm.Sampling, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return llm.Sampling{}, err
+	}
+	next := s.samplingOverride
+	if err := next.Set(name, value); err != nil {
+		return llm.Sampling{}, err
+	}
+	if err := next.Validate(s.cur.provider.ID()); err != nil {
+		return llm.Sampling{}, err
+	}
+	s.samplingOverride = next
+	return s.samplingFor(s.cur.model), nil
+}
+
+// Sampling returns the parameters requests to the current model use.
+func (s *Session) Sampling() llm.Sampling {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.sync(); err != nil {
+		return llm.Sampling{}
+	}
+	return s.samplingFor(s.cur.model)
+}
+
+// samplingFor merges the provider default, the model's entry and the
+// session overrides, in that order. Callers hold mu.
+func (s *Session) samplingFor(model string) llm.Sampling {
+	p := s.cur.sampling[s.cur.provider.ID()]
+	out := p.Default
+	for id, m := range p.Models {
+		// Config keys come back lowercased.
+		if strings.EqualFold(id, model) {
+			out = out.Merge(m)
+		}
+	}
+	return out.Merge(s.samplingOverride)
+}
+
+// applySampling sets the generation parameters for req's model. Callers
+// hold mu.
+func (s *Session) applySampling(req *llm.ChatRequest) {
+	s.samplingFor(req.Model).Apply(req)
+}
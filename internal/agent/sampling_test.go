@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestAgent_Sampling(t *testing.T) {
+	provider := llm.NewMockProvider()
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	low, high := 0.3, 0.9
+	a.SetSampling(SamplingPolicy{llm.ProviderMock: {
+		Default: llm.Sampling{Temperature: &high, MaxTokens: 1000},
+		Models:  map[string]llm.Sampling{a.CurrentModel(): {Temperature: &low}},
+	}})
+	assert.Equal(t, "temperature 0.3, max_tokens 1000", a.Sampling().String(), "the model entry wins over the provider default")
+
+	s, err := a.SetSamplingParam("top_p", "0.5")
+	require.NoError(t, err)
+	assert.Equal(t, "temperature 0.3, top_p 0.5, max_tokens 1000", s.String())
+	_, err = a.SetSamplingParam("temperature", "3")
+	assert.ErrorContains(t, err, "between 0 and 2")
+
+	_, err = a.Chat(context.Background(), "hi")
+	require.NoError(t, err)
+	req := provider.Requests()[0]
+	require.NotNil(t, req.Temperature)
+	assert.Equal(t, 0.3, *req.Temperature)
+	assert.Equal(t, 0.5, *req.TopP)
+	assert.Equal(t, 1000, req.MaxTokens)
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/screen"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+// screener loads the screening lists on first use.
+func (tr *ToolRegistry) screener() *screen.Screener {
+	tr.screenOnce.Do(func() {
+		tr.screen = screen.New(tr.dataDir)
+	})
+	return tr.screen
+}
+
+// screenRecipient checks the address that ends up with the funds or the
+// allowance. A flagged address is refused unless the policy only warns.
+// Lists or an API that could not be checked are noted in the preview but,
+// like contract checks, never block: that would make clifi unusable offline.
+func (tr *ToolRegistry) screenRecipient(ctx context.Context, role, address string, policy tx.Policy) (contractCheck, error) {
+	s := tr.screener()
+	if !s.Enabled() {
+		return contractCheck{}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	res := s.Screen(ctx, address)
+
+	if len(res.Hits) > 0 {
+		reasons := make([]string, len(res.Hits))
+		for i, h := range res.Hits {
+			reasons[i] = h.String()
+		}
+		if !policy.ScreenWarnOnly {
+			return contractCheck{}, fmt.Errorf("%s %s is flagged: %s; refusing to sign", strings.ToLower(role), address, strings.Join(reasons, "; "))
+		}
+		return contractCheck{
+			Warn:  true,
+			Lines: []string{fmt.Sprintf("⚠ WARNING: %s %s is flagged: %s.", role, address, strings.Join(reasons, "; "))},
+		}, nil
+	}
+	if len(res.Problems) > 0 {
+		return contractCheck{Lines: []string{fmt.Sprintf("- %s screening: incomplete (%s)", role, strings.Join(res.Problems, "; "))}}, nil
+	}
+	return contractCheck{Lines: []string{fmt.Sprintf("- %s screening: no match (%s)", role, s.Describe())}}, nil
+}
@@ -0,0 +1,261 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+// SendRequest is a native or ERC20 transfer made outside the chat loop,
+// e.g. by `clifi send`. Token empty means the chain's native currency.
+type SendRequest struct {
+	Chain   string
+	From    string // address; empty for the default wallet
+	To      string
+	Amount  string // decimal, in ETH or token units
+	Token   string // contract address or listed symbol
+	Private *bool
+}
+
+// PreparedSend is a validated, unsigned transfer and its preview. Signing
+// it later sends exactly what was previewed.
+type PreparedSend struct {
+	Chain   string
+	From    common.Address
+	To      common.Address
+	Amount  string
+	Symbol  string
+	Token   *common.Address // nil for native sends
+	Preview string
+	// Warnings repeats the preview's contract check and screening lines
+	// when the user should explicitly acknowledge them.
+	Warnings []string
+
+	unsigned *types.Transaction
+	chainID  *big.Int
+	relay    *chain.PrivateRelay
+}
+
+// SentTx is a broadcast transfer. Receipt is empty when the transaction
+// was not waited for or did not confirm in time.
+type SentTx struct {
+	Hash        common.Hash
+	ExplorerURL string
+	Receipt     string
+	Confirmed   *TxConfirmation
+}
+
+// PrepareSend validates req against the policy and builds the transaction
+// it describes, using the same checks as the send tools.
+func (tr *ToolRegistry) PrepareSend(ctx context.Context, req SendRequest) (*PreparedSend, error) {
+	if req.Token == "" {
+		return tr.prepareNativeSend(ctx, sendNativeInput{
+			From: req.From, To: req.To, Chain: req.Chain, AmountETH: req.Amount, Private: req.Private,
+		})
+	}
+	return tr.prepareTokenSend(ctx, sendTokenInput{
+		From: req.From, To: req.To, Chain: req.Chain, Token: req.Token, AmountTokens: req.Amount, Private: req.Private,
+	})
+}
+
+// SendPrepared signs and broadcasts p, then waits for the receipt when
+// wait is set.
+func (tr *ToolRegistry) SendPrepared(ctx context.Context, p *PreparedSend, password string, wait bool) (*SentTx, error) {
+	if err := tr.checkSigner(p.Chain, p.From, password); err != nil {
+		return nil, err
+	}
+	signed, err := tr.signAndSendTx(ctx, p.Chain, p.From, password, p.unsigned, p.chainID, p.relay)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := &SentTx{Hash: signed.Hash(), ExplorerURL: tr.txURL(p.Chain, signed.Hash().Hex())}
+	sent.Receipt, sent.Confirmed = tr.maybeWaitAndPersistReceipt(ctx, p.Chain, signed.Hash(), &wait)
+	if sent.Receipt == "" {
+		sent.Receipt = privateStatusLine(ctx, p.relay, signed.Hash())
+	}
+	return sent, nil
+}
+
+func (tr *ToolRegistry) prepareNativeSend(ctx context.Context, params sendNativeInput) (*PreparedSend, error) {
+	toAddr, err := requireHexAddress("recipient address", params.To)
+	if err != nil {
+		return nil, err
+	}
+	if params.AmountETH == "" {
+		return nil, fmt.Errorf("amount_eth is required")
+	}
+
+	wei, err := parseEthToWei(params.AmountETH)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount_eth: %w", err)
+	}
+	if wei.Sign() <= 0 {
+		return nil, fmt.Errorf("amount_eth must be greater than zero")
+	}
+
+	fromAddr, cfg, err := tr.prepareTxFrom(params.Chain, params.From)
+	if err != nil {
+		return nil, err
+	}
+
+	intent := tx.Intent{
+		Chain:    params.Chain,
+		From:     fromAddr,
+		To:       toAddr,
+		ValueWei: wei,
+	}
+	policy := loadPolicy()
+	if err := tx.Validate(intent, policy); err != nil {
+		return nil, err
+	}
+	recipientCheck, err := tr.screenRecipient(ctx, "Recipient", toAddr.Hex(), policy)
+	if err != nil {
+		return nil, err
+	}
+	relay, err := privateRelayFor(params.Chain, cfg, params.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	previewCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	unsigned, fees, err := tx.BuildUnsignedTx(previewCtx, tr.chainClient, intent)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("Preview:\n- Chain: %s\n- From: %s\n- To: %s\n- Amount: %s ETH\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n%s- Estimated total: %s ETH\n",
+		params.Chain,
+		fromAddr.Hex(),
+		params.To,
+		params.AmountETH,
+		fees.GasLimit,
+		weiToGwei(fees.MaxFeePerGas),
+		weiToGwei(fees.MaxPriorityFee),
+		l1FeeLine(fees),
+		weiToEth(fees.EstimatedCostWei),
+	)
+	summary += submissionLine(relay)
+
+	p := &PreparedSend{
+		Chain:    params.Chain,
+		From:     fromAddr,
+		To:       toAddr,
+		Amount:   params.AmountETH,
+		Symbol:   "ETH",
+		Preview:  summary,
+		unsigned: unsigned,
+		chainID:  cfg.ChainID,
+		relay:    relay,
+	}
+	p.addCheck(recipientCheck)
+	return p, nil
+}
+
+func (tr *ToolRegistry) prepareTokenSend(ctx context.Context, params sendTokenInput) (*PreparedSend, error) {
+	toAddr, err := requireHexAddress("recipient address", params.To)
+	if err != nil {
+		return nil, err
+	}
+	tokenAddr, err := tr.resolveToken(params.Chain, params.Token)
+	if err != nil {
+		return nil, err
+	}
+	if params.AmountTokens == "" {
+		return nil, fmt.Errorf("amount_tokens is required")
+	}
+
+	fromAddr, cfg, err := tr.prepareTxFrom(params.Chain, params.From)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals, symbol, err := queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	amountWei, err := decimalToWei(params.AmountTokens, int(decimals))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount_tokens: %w", err)
+	}
+	if amountWei.Sign() <= 0 {
+		return nil, fmt.Errorf("amount_tokens must be greater than zero")
+	}
+
+	data, err := buildERC20TransferData(toAddr, amountWei)
+	if err != nil {
+		return nil, err
+	}
+
+	intent := tx.Intent{
+		Chain:    params.Chain,
+		From:     fromAddr,
+		To:       tokenAddr,
+		ValueWei: big.NewInt(0),
+		Data:     data,
+	}
+	policy := loadPolicy()
+	if err := tx.Validate(intent, policy); err != nil {
+		return nil, err
+	}
+	recipientCheck, err := tr.screenRecipient(ctx, "Recipient", toAddr.Hex(), policy)
+	if err != nil {
+		return nil, err
+	}
+	relay, err := privateRelayFor(params.Chain, cfg, params.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	unsigned, fees, err := tx.BuildUnsignedTx(ctx, tr.chainClient, intent)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("Preview ERC20 transfer:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- To: %s\n- Amount: %s %s\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n%s- Estimated total (gas only): %s ETH\n",
+		tokenAddr.Hex(), symbol, params.Chain, fromAddr.Hex(), params.To, params.AmountTokens, symbol,
+		fees.GasLimit,
+		weiToGwei(fees.MaxFeePerGas),
+		weiToGwei(fees.MaxPriorityFee),
+		l1FeeLine(fees),
+		weiToEth(fees.EstimatedCostWei),
+	)
+	summary += submissionLine(relay)
+
+	p := &PreparedSend{
+		Chain:    params.Chain,
+		From:     fromAddr,
+		To:       toAddr,
+		Amount:   params.AmountTokens,
+		Symbol:   symbol,
+		Token:    &tokenAddr,
+		Preview:  summary,
+		unsigned: unsigned,
+		chainID:  cfg.ChainID,
+		relay:    relay,
+	}
+	p.addCheck(tr.checkContract(ctx, params.Chain, cfg, "Token", tokenAddr).and(recipientCheck))
+	return p, nil
+}
+
+// addCheck appends a check's lines to the preview, keeping them as
+// warnings when the user should acknowledge them.
+func (p *PreparedSend) addCheck(c contractCheck) {
+	if len(c.Lines) == 0 {
+		return
+	}
+	p.Preview += strings.Join(c.Lines, "\n") + "\n"
+	if c.Warn {
+		p.Warnings = c.Lines
+	}
+}
@@ -0,0 +1,304 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/jsonschema"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// Session is one conversation with the agent: its messages, transcript,
+// summary, pending plan, chain and wallet defaults and /set overrides.
+// Sessions share the agent's provider, tools, settings and spend, so a
+// daemon can keep one per client. A session runs one turn at a time;
+// separate sessions run theirs in parallel.
+type Session struct {
+	agent *Agent
+
+	// mu serializes the session's turns and guards the fields below, so
+	// concurrent calls can't interleave messages.
+	mu           sync.Mutex
+	conversation []llm.Message
+	// transcript records the full exchange, tool calls included, for Export.
+	transcript *Conversation
+	// summary stands in for the messages Compact dropped.
+	summary string
+	// plan waits for approval; see MakePlan.
+	plan *Plan
+	// defaults are the session's chain and wallet; see SetDefaults.
+	defaults sessionDefaults
+	// samplingOverride holds the session's /set values.
+	samplingOverride llm.Sampling
+	// budgetWarned is the last budget warning given; see budgetNotice.
+	budgetWarned string
+
+	// generation and provider are the agent's as of the conversation's
+	// start; see catchUp.
+	generation uint64
+	provider   llm.ProviderID
+	// cur is the agent's settings as of the current turn; see sync.
+	cur settings
+
+	sessionID string
+	logger    *sessionLogger
+	debug     *llmDebugLog // nil unless the agent's SetDebugLLM is on
+
+	// Size of the current conversation as of the last request; see
+	// ContextUsage.
+	contextTokens    atomic.Int64
+	contextEstimated atomic.Bool
+}
+
+// settings is a copy of the agent's provider and shared settings. A turn
+// runs on the copy sync took at its start rather than holding the agent's
+// mu through its requests and tools, so a switch doesn't wait for running
+// turns, nor do other sessions' turns wait for the switch.
+type settings struct {
+	provider llm.Provider
+	// model is the provider's model at the time of the copy.
+	model      string
+	routing    RoutingPolicy
+	sampling   SamplingPolicy
+	budget     Budget
+	debugLLM   bool
+	generation uint64
+}
+
+func newSession(a *Agent) *Session {
+	s := &Session{
+		agent:        a,
+		conversation: make([]llm.Message, 0),
+		transcript:   NewConversation(),
+		generation:   a.generation,
+	}
+	if a.provider != nil {
+		s.provider = a.provider.ID()
+	}
+	return s
+}
+
+// NewSession opens a conversation of its own, alongside the Agent's main
+// one. Close it when the client is done; Agent.Close closes any left open.
+func (a *Agent) NewSession() *Session {
+	a.mu.RLock()
+	s := newSession(a)
+	a.mu.RUnlock()
+
+	a.sessionsMu.Lock()
+	defer a.sessionsMu.Unlock()
+	if a.sessions == nil {
+		a.sessions = make(map[*Session]struct{})
+	}
+	a.sessions[s] = struct{}{}
+	return s
+}
+
+// Close ends the session's log.
+func (s *Session) Close() {
+	s.mu.Lock()
+	if s.logger != nil {
+		s.logger.Close()
+		s.logger = nil
+	}
+	s.mu.Unlock()
+
+	a := s.agent
+	a.sessionsMu.Lock()
+	delete(a.sessions, s)
+	a.sessionsMu.Unlock()
+}
+
+// clear starts a new conversation with a new session log. Callers hold mu.
+func (s *Session) clear() {
+	s.conversation = make([]llm.Message, 0)
+	s.transcript = NewConversation()
+	s.summary = ""
+	s.resetContext()
+	s.rotateSession()
+}
+
+// catchUp clears a conversation held under a model or provider the agent
+// has since switched from, since prior messages may be incompatible, and
+// drops /set values when the provider changed, as ranges differ between
+// providers. Callers hold mu and the agent's mu.
+func (s *Session) catchUp() {
+	a := s.agent
+	if s.generation == a.generation {
+		return
+	}
+	if id := a.provider.ID(); id != s.provider {
+		s.samplingOverride = llm.Sampling{}
+		s.provider = id
+	}
+	s.generation = a.generation
+	s.clear()
+}
+
+// sync catches up with the agent and copies its settings into cur.
+// Callers hold mu; the agent's mu is held only while copying.
+func (s *Session) sync() error {
+	a := s.agent
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.provider == nil {
+		return fmt.Errorf("agent provider not initialized")
+	}
+	s.catchUp()
+	s.cur = settings{
+		provider:   a.provider,
+		model:      a.provider.DefaultModel(),
+		routing:    a.routing,
+		sampling:   a.sampling,
+		budget:     a.budget,
+		debugLLM:   a.debugLLM,
+		generation: a.generation,
+	}
+	return nil
+}
+
+// switched reports whether the agent switched model or provider since
+// the last sync, that is during the current turn.
+func (s *Session) switched() bool {
+	a := s.agent
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.generation != s.cur.generation
+}
+
+func (s *Session) ensureSession() {
+	if s.sessionID != "" {
+		return
+	}
+	s.rotateSession()
+}
+
+func (s *Session) rotateSession() {
+	if s.logger != nil {
+		s.logger.Close()
+		s.logger = nil
+	}
+
+	s.sessionID = time.Now().UTC().Format("20060102-150405.000000000")
+	l, err := newSessionLogger(s.agent.dataDir, s.sessionID)
+	if err == nil {
+		s.logger = l
+	}
+}
+
+func (s *Session) log(rec sessionRecord) {
+	if s.logger == nil {
+		return
+	}
+	s.logger.logRecord(rec)
+}
+
+// The Agent's conversation methods act on its main session.
+
+// Chat sends a user message and returns the agent's response.
+func (a *Agent) Chat(ctx context.Context, userMessage string) (string, error) {
+	return a.main.Chat(ctx, userMessage)
+}
+
+// ChatWithEvents sends a user message and returns structured events for UI rendering.
+func (a *Agent) ChatWithEvents(ctx context.Context, userMessage string) ([]ChatEvent, error) {
+	return a.main.ChatWithEvents(ctx, userMessage)
+}
+
+// ChatStream is ChatWithEvents that also passes each event to onEvent as
+// it happens; see Session.ChatStream.
+func (a *Agent) ChatStream(ctx context.Context, userMessage string, onEvent func(ChatEvent)) ([]ChatEvent, error) {
+	return a.main.ChatStream(ctx, userMessage, onEvent)
+}
+
+// AskJSON answers question with a JSON value valid against schema; see
+// Session.AskJSON.
+func (a *Agent) AskJSON(ctx context.Context, question string, schema *jsonschema.Schema, onEvent func(ChatEvent)) ([]byte, error) {
+	return a.main.AskJSON(ctx, question, schema, onEvent)
+}
+
+// UndoLastTurn removes the last user message and the replies to it.
+func (a *Agent) UndoLastTurn() (string, error) {
+	return a.main.UndoLastTurn()
+}
+
+// Reset clears the conversation history, once a running turn finishes.
+func (a *Agent) Reset() {
+	a.main.Reset()
+}
+
+// Export returns a copy of the conversation for sharing.
+func (a *Agent) Export() *Conversation {
+	return a.main.Export()
+}
+
+// Resume continues the stored conversation up to message id; see
+// Session.Resume.
+func (a *Agent) Resume(id int64) ([]llm.Message, error) {
+	return a.main.Resume(id)
+}
+
+// RunTool runs a tool directly, outside the conversation, for commands
+// that don't need the model. input is marshalled to JSON.
+func (a *Agent) RunTool(ctx context.Context, name string, input any) (ToolOutput, error) {
+	return a.main.RunTool(ctx, name, input)
+}
+
+// Defaults returns the session's chain and wallet defaults.
+func (a *Agent) Defaults() SessionDefaults {
+	return a.main.Defaults()
+}
+
+// SetDefaults replaces the session defaults; see Session.SetDefaults.
+func (a *Agent) SetDefaults(d SessionDefaults) (SessionDefaults, error) {
+	return a.main.SetDefaults(d)
+}
+
+// DescribeDefaults renders the session defaults for display.
+func (a *Agent) DescribeDefaults() string {
+	return a.main.DescribeDefaults()
+}
+
+// ContextUsage reports the size of the conversation as of the last
+// request.
+func (a *Agent) ContextUsage() ContextUsage {
+	return a.main.ContextUsage()
+}
+
+// Compact replaces the conversation with a summary; see Session.Compact.
+func (a *Agent) Compact(ctx context.Context) (string, error) {
+	return a.main.Compact(ctx)
+}
+
+// SetSamplingParam overrides one parameter for the rest of the session.
+func (a *Agent) SetSamplingParam(name, value string) (llm.Sampling, error) {
+	return a.main.SetSamplingParam(name, value)
+}
+
+// Sampling returns the parameters requests to the current model use.
+func (a *Agent) Sampling() llm.Sampling {
+	return a.main.Sampling()
+}
+
+// MakePlan runs a planning pass for goal; see Session.MakePlan.
+func (a *Agent) MakePlan(ctx context.Context, goal string, onEvent func(ChatEvent)) (*Plan, error) {
+	return a.main.MakePlan(ctx, goal, onEvent)
+}
+
+// PendingPlan returns the plan waiting for approval, if any.
+func (a *Agent) PendingPlan() *Plan {
+	return a.main.PendingPlan()
+}
+
+// DiscardPlan drops the pending plan.
+func (a *Agent) DiscardPlan() {
+	a.main.DiscardPlan()
+}
+
+// RunPlan runs the approved plan's steps in order; see Session.RunPlan.
+func (a *Agent) RunPlan(ctx context.Context, password string, onEvent func(ChatEvent)) error {
+	return a.main.RunPlan(ctx, password, onEvent)
+}
@@ -0,0 +1,225 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SessionDefaults are the chain and wallet that tool calls fall back to
+// for the rest of a session when they omit chain or from.
+type SessionDefaults struct {
+	Chain  string `json:"chain,omitempty"`
+	Wallet string `json:"wallet,omitempty"` // EVM address
+}
+
+// IsZero reports whether no default is set.
+func (d SessionDefaults) IsZero() bool {
+	return d.Chain == "" && d.Wallet == ""
+}
+
+// defaultChainTools take an EVM chain the session default can fill in. The
+// faucet is left out: it only takes testnets, and a default is usually a
+// mainnet.
+var defaultChainTools = []string{
+	"get_token_balance", "get_chain_info", "get_gas_price",
+	"send_native", "send_token", "approve_token",
+	"swap", "create_dca", "create_limit_order",
+	"get_receipt", "wait_receipt", "get_private_tx_status", "explain_tx",
+	"trace_tx", "query_logs", "get_account_state", "raw_call", "read_storage",
+	"audit_approvals", "sign_permit", "send_token_gasless",
+}
+
+// defaultWalletTools maps EVM tools to the argument naming their wallet.
+// Solana and Cosmos tools use other address formats and are left alone.
+var defaultWalletTools = map[string]string{
+	"send_native":        "from",
+	"send_token":         "from",
+	"approve_token":      "from",
+	"swap":               "from",
+	"create_dca":         "wallet",
+	"create_limit_order": "wallet",
+	"get_balances":       "address",
+	"get_token_balance":  "address",
+	"get_account_state":  "address",
+	"audit_approvals":    "owner",
+	"sign_permit":        "owner",
+	"send_token_gasless": "from",
+}
+
+// sessionDefaults is the agent's mutable SessionDefaults. It travels to the
+// tool registry in the context, so set_context can change it mid-turn.
+type sessionDefaults struct {
+	mu sync.Mutex
+	d  SessionDefaults
+}
+
+func (s *sessionDefaults) get() SessionDefaults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d
+}
+
+func (s *sessionDefaults) set(d SessionDefaults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.d = d
+}
+
+type sessionDefaultsKey struct{}
+
+func withSessionDefaults(ctx context.Context, s *sessionDefaults) context.Context {
+	return context.WithValue(ctx, sessionDefaultsKey{}, s)
+}
+
+func sessionDefaultsFrom(ctx context.Context) *sessionDefaults {
+	s, _ := ctx.Value(sessionDefaultsKey{}).(*sessionDefaults)
+	return s
+}
+
+// applyDefaults fills the session's chain and wallet into a call that
+// omits them. Inputs it can't read are passed through for the handler to
+// reject.
+func applyDefaults(name string, input json.RawMessage, d SessionDefaults) json.RawMessage {
+	if d.IsZero() {
+		return input
+	}
+	args := make(map[string]json.RawMessage)
+	if len(input) > 0 && string(input) != "null" {
+		if err := json.Unmarshal(input, &args); err != nil || args == nil {
+			return input
+		}
+	}
+	changed := false
+	fill := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if raw, ok := args[key]; ok && string(raw) != `""` && string(raw) != "null" {
+			return
+		}
+		args[key], _ = json.Marshal(value)
+		changed = true
+	}
+	if slices.Contains(defaultChainTools, name) {
+		fill("chain", d.Chain)
+	}
+	if key, ok := defaultWalletTools[name]; ok {
+		fill(key, d.Wallet)
+	}
+	if !changed {
+		return input
+	}
+	out, err := json.Marshal(args)
+	if err != nil {
+		return input
+	}
+	return out
+}
+
+// resolveDefaults checks d's chain and resolves its wallet, given as a
+// label, address or number from the wallet list, to an address.
+func (tr *ToolRegistry) resolveDefaults(d SessionDefaults) (SessionDefaults, error) {
+	d.Chain = strings.ToLower(strings.TrimSpace(d.Chain))
+	if d.Chain != "" {
+		if _, err := tr.chainClient.GetChainConfig(d.Chain); err != nil {
+			return d, err
+		}
+	}
+	if ref := strings.TrimSpace(d.Wallet); ref != "" {
+		km, err := tr.keystore()
+		if err != nil {
+			return d, fmt.Errorf("failed to load wallets: %w", err)
+		}
+		acc, err := km.FindAccount(ref)
+		if err != nil {
+			return d, err
+		}
+		d.Wallet = acc.Address.Hex()
+	}
+	return d, nil
+}
+
+// describeDefaults renders d for the model and the REPL, with the wallet's
+// label when it has one.
+func (tr *ToolRegistry) describeDefaults(d SessionDefaults) string {
+	if d.IsZero() {
+		return "No session defaults: tools use the chain and wallet given, or the global defaults."
+	}
+	var parts []string
+	if d.Chain != "" {
+		parts = append(parts, "chain "+d.Chain)
+	}
+	if d.Wallet != "" {
+		w := d.Wallet
+		if km, err := tr.keystore(); err == nil {
+			if label := km.Label(common.HexToAddress(d.Wallet)); label != "" {
+				w = fmt.Sprintf("%s (%s)", label, d.Wallet)
+			}
+		}
+		parts = append(parts, "wallet "+w)
+	}
+	return "Session defaults: " + strings.Join(parts, ", ") + ". Tool calls that omit chain or from use these."
+}
+
+type setContextInput struct {
+	Chain  string `json:"chain"`
+	Wallet string `json:"wallet"`
+	Clear  bool   `json:"clear"`
+}
+
+func (tr *ToolRegistry) handleSetContext(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params setContextInput
+	if err := json.Unmarshal(input, &params); err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid input: %w", err)
+	}
+	s := sessionDefaultsFrom(ctx)
+	if s == nil {
+		return ToolOutput{}, fmt.Errorf("set_context needs an agent session")
+	}
+
+	d := s.get()
+	if params.Clear {
+		d = SessionDefaults{}
+	}
+	if params.Chain != "" || params.Wallet != "" {
+		next, err := tr.resolveDefaults(SessionDefaults{Chain: params.Chain, Wallet: params.Wallet})
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		if next.Chain != "" {
+			d.Chain = next.Chain
+		}
+		if next.Wallet != "" {
+			d.Wallet = next.Wallet
+		}
+	}
+	s.set(d)
+	return ToolOutput{Text: tr.describeDefaults(d)}, nil
+}
+
+// Defaults returns the session's chain and wallet defaults.
+func (s *Session) Defaults() SessionDefaults {
+	return s.defaults.get()
+}
+
+// SetDefaults replaces the session defaults. Empty fields clear that
+// default; the wallet may be a label, address or wallet list number.
+func (s *Session) SetDefaults(d SessionDefaults) (SessionDefaults, error) {
+	d, err := s.agent.toolRegistry.resolveDefaults(d)
+	if err != nil {
+		return SessionDefaults{}, err
+	}
+	s.defaults.set(d)
+	return d, nil
+}
+
+// DescribeDefaults renders the session defaults for display.
+func (s *Session) DescribeDefaults() string {
+	return s.agent.toolRegistry.describeDefaults(s.defaults.get())
+}
@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	d := SessionDefaults{Chain: "base", Wallet: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"}
+
+	out := applyDefaults("send_native", json.RawMessage(`{"to":"0x1","amount_eth":"1"}`), d)
+	var args map[string]string
+	require.NoError(t, json.Unmarshal(out, &args))
+	assert.Equal(t, "base", args["chain"])
+	assert.Equal(t, d.Wallet, args["from"])
+
+	// Explicit arguments win.
+	out = applyDefaults("send_native", json.RawMessage(`{"chain":"optimism","from":"0x2"}`), d)
+	assert.JSONEq(t, `{"chain":"optimism","from":"0x2"}`, string(out))
+
+	// get_balances takes an address, and no single chain.
+	out = applyDefaults("get_balances", nil, d)
+	assert.JSONEq(t, `{"address":"`+d.Wallet+`"}`, string(out))
+
+	// Non-EVM tools are left alone.
+	in := json.RawMessage(`{"to":"abc"}`)
+	assert.Equal(t, in, applyDefaults("send_sol", in, d))
+	assert.Equal(t, in, applyDefaults("send_native", in, SessionDefaults{}))
+}
+
+func TestAgent_SessionDefaults(t *testing.T) {
+	dataDir := t.TempDir()
+	km, err := wallet.NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	acct, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	require.NoError(t, km.SetLabel(acct.Address, "hot"))
+
+	setContext, _ := json.Marshal(map[string]any{"chain": "Base", "wallet": "hot"})
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{{Name: "set_context", Input: setContext}}},
+	}})
+	a := NewWithProvider(provider, dataDir)
+	defer a.Close()
+
+	reply, err := a.Chat(context.Background(), "use base and my hot wallet from now on")
+	require.NoError(t, err)
+	assert.Contains(t, reply, "chain base, wallet hot ("+acct.Address.Hex()+")")
+	assert.Equal(t, SessionDefaults{Chain: "base", Wallet: acct.Address.Hex()}, a.Defaults())
+
+	_, err = a.Chat(context.Background(), "and now?")
+	require.NoError(t, err)
+	reqs := provider.Requests()
+	assert.Contains(t, reqs[len(reqs)-1].SystemPrompt, "Session defaults: chain base")
+
+	_, err = a.SetDefaults(SessionDefaults{Chain: "nochain"})
+	assert.Error(t, err)
+	_, err = a.SetDefaults(SessionDefaults{Wallet: "cold"})
+	assert.Error(t, err)
+
+	a.Reset()
+	assert.True(t, a.Defaults().IsZero())
+}
+
+func TestSetContext_NeedsSession(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+	_, err := tr.ExecuteTool(context.Background(), "set_context", json.RawMessage(`{"chain":"base"}`))
+	assert.ErrorContains(t, err, "agent session")
+}
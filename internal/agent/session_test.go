@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestSession_ConcurrentConversationsStaySeparate(t *testing.T) {
+	provider := llm.NewMockProvider(
+		llm.MockTurn{Match: "alpha", Responses: []llm.ChatResponse{{Content: "A"}}},
+		llm.MockTurn{Match: "beta", Responses: []llm.ChatResponse{{Content: "B"}}},
+	)
+	ag := NewWithProvider(provider, t.TempDir())
+	t.Cleanup(ag.Close)
+
+	const turns = 5
+	sessions := map[string]*Session{"alpha": ag.NewSession(), "beta": ag.NewSession()}
+	var wg sync.WaitGroup
+	for name, s := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range turns {
+				_, err := s.Chat(context.Background(), fmt.Sprintf("%s %d", name, i))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for name, s := range sessions {
+		msgs := s.Export().ToMessages()
+		require.Len(t, msgs, 2*turns, name)
+		for i := range turns {
+			assert.Equal(t, fmt.Sprintf("%s %d", name, i), msgs[2*i].Content)
+			assert.Equal(t, map[string]string{"alpha": "A", "beta": "B"}[name], msgs[2*i+1].Content)
+		}
+	}
+	assert.Empty(t, ag.Export().Turns, "the main session saw none of it")
+	assert.Len(t, provider.Requests(), 2*turns)
+}
+
+func TestSession_StartsOverAfterModelSwitch(t *testing.T) {
+	ag := NewWithProvider(llm.NewMockProvider(), t.TempDir())
+	t.Cleanup(ag.Close)
+	s := ag.NewSession()
+
+	_, err := s.Chat(context.Background(), "first")
+	require.NoError(t, err)
+	_, err = s.SetDefaults(SessionDefaults{Chain: "base"})
+	require.NoError(t, err)
+	require.NoError(t, ag.SetModel("mock-1"))
+
+	_, err = s.Chat(context.Background(), "second")
+	require.NoError(t, err)
+	msgs := s.Export().ToMessages()
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "second", msgs[0].Content)
+	assert.Equal(t, "base", s.Defaults().Chain, "defaults outlive a model switch")
+}
+
+func TestSession_CloseForgetsSession(t *testing.T) {
+	ag := NewWithProvider(llm.NewMockProvider(), t.TempDir())
+	t.Cleanup(ag.Close)
+
+	s := ag.NewSession()
+	require.Len(t, ag.sessions, 1)
+	s.Close()
+	assert.Empty(t, ag.sessions)
+}
+
+// slowProvider holds every turn whose message contains "slow" until
+// release is closed.
+type slowProvider struct {
+	*llm.MockProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *slowProvider) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if strings.Contains(lastUserMessageOf(*req), "slow") {
+		close(p.started)
+		<-p.release
+	}
+	return p.MockProvider.Chat(ctx, req)
+}
+
+func TestSession_SwitchDoesNotWaitForRunningTurns(t *testing.T) {
+	provider := &slowProvider{MockProvider: llm.NewMockProvider(), started: make(chan struct{}), release: make(chan struct{})}
+	ag := NewWithProvider(provider, t.TempDir())
+	t.Cleanup(ag.Close)
+	slow, fast := ag.NewSession(), ag.NewSession()
+
+	done := make(chan []ChatEvent)
+	go func() {
+		events, err := slow.ChatWithEvents(context.Background(), "slow")
+		assert.NoError(t, err)
+		done <- events
+	}()
+	<-provider.started
+
+	switched := make(chan error)
+	go func() { switched <- ag.SetModel("mock-1") }()
+	select {
+	case err := <-switched:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetModel waited for another session's turn")
+	}
+	_, err := fast.Chat(context.Background(), "fast")
+	require.NoError(t, err, "other sessions' turns don't wait either")
+
+	close(provider.release)
+	events := <-done
+	assert.Contains(t, lastNotice(events), "changed during this reply")
+}
+
+func lastNotice(events []ChatEvent) string {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == "notice" {
+			return events[i].Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,210 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/permit"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+const (
+	defaultPermitMinutes = 60
+	maxPermitMinutes     = 7 * 24 * 60
+)
+
+type signPermitInput struct {
+	Chain           string `json:"chain"`
+	Owner           string `json:"owner"`
+	Token           string `json:"token"`
+	Spender         string `json:"spender"`
+	AmountTokens    string `json:"amount_tokens"`
+	DeadlineMinutes int    `json:"deadline_minutes"`
+	// Relay has the spender, one of the keystore wallets, submit the
+	// permit and transferFrom at once.
+	Relay           bool   `json:"relay"`
+	To              string `json:"to"`
+	Password        string `json:"password"`
+	SpenderPassword string `json:"spender_password"`
+	Confirm         bool   `json:"confirm"`
+}
+
+// handleSignPermit signs an EIP-2612 permit: a gasless approval the
+// spender submits with the token's permit(). With relay, clifi submits it
+// from the spender wallet and follows with transferFrom, so the owner moves
+// tokens without holding native gas.
+func (tr *ToolRegistry) handleSignPermit(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params signPermitInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	spender, err := requireHexAddress("spender", params.Spender)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	tokenAddr, err := tr.resolveToken(params.Chain, params.Token)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if params.AmountTokens == "" {
+		return ToolOutput{}, fmt.Errorf("amount_tokens is required")
+	}
+	minutes := params.DeadlineMinutes
+	if minutes == 0 {
+		minutes = defaultPermitMinutes
+	}
+	if minutes < 0 || minutes > maxPermitMinutes {
+		return ToolOutput{}, fmt.Errorf("deadline_minutes must be between 1 and %d", maxPermitMinutes)
+	}
+	owner, cfg, err := tr.prepareTxFrom(params.Chain, params.Owner)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	recipient := spender
+	if params.To != "" {
+		if !params.Relay {
+			return ToolOutput{}, fmt.Errorf("to is only used with relay")
+		}
+		if recipient, err = requireHexAddress("to", params.To); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+	if params.Relay {
+		km, err := tr.keystore()
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		if !km.HasAccount(spender) {
+			return ToolOutput{}, fmt.Errorf("relay needs the spender %s to be a keystore wallet that can pay the gas", spender.Hex())
+		}
+	}
+
+	// Waiting on the relayed transactions outlasts the lookups' timeout.
+	relayCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
+	defer cancel()
+	decimals, symbol, err := queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	amount, err := decimalToWei(params.AmountTokens, int(decimals))
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid amount_tokens: %w", err)
+	}
+	if amount.Sign() <= 0 {
+		return ToolOutput{}, fmt.Errorf("amount_tokens must be greater than zero")
+	}
+
+	info, err := tr.chainClient.GetPermitInfo(ctx, params.Chain, tokenAddr, owner)
+	if errors.Is(err, chain.ErrPermitUnsupported) {
+		return ToolOutput{}, fmt.Errorf("%s (%s) does not support EIP-2612 permits; use approve_token", symbol, tokenAddr.Hex())
+	}
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	domain, err := permit.MatchDomain(info.Name, info.Version, cfg.ChainID, tokenAddr, info.DomainSeparator)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	deadline := time.Now().Add(time.Duration(minutes) * time.Minute)
+	p := permit.Permit{Owner: owner, Spender: spender, Value: amount, Nonce: info.Nonce, Deadline: big.NewInt(deadline.Unix())}
+
+	policy := loadPolicy()
+	screenCheck, err := tr.screenRecipient(ctx, "Spender", spender.Hex(), policy)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview EIP-2612 permit:\n- Token: %s (%s)\n- Chain: %s\n- Owner: %s\n- Spender: %s\n- Amount: %s %s\n- Deadline: %s (%d minutes)\n- Permit nonce: %s\n",
+		tokenAddr.Hex(), symbol, params.Chain, owner.Hex(), spender.Hex(), params.AmountTokens, symbol,
+		deadline.UTC().Format("2006-01-02 15:04 UTC"), minutes, info.Nonce)
+	checks := screenCheck.and(tr.tokenRiskCheck(ctx, params.Chain, cfg, "Token", tokenAddr, owner))
+	if params.Relay {
+		fmt.Fprintf(&b, "- Then: %s sends permit() and transferFrom of %s %s to %s, paying the gas\n",
+			spender.Hex(), params.AmountTokens, symbol, recipient.Hex())
+		// Both transactions call the token from the spender wallet.
+		if err := tx.Validate(tx.Intent{Chain: params.Chain, From: spender, To: tokenAddr, ValueWei: big.NewInt(0)}, policy); err != nil {
+			return ToolOutput{}, err
+		}
+		if recipient != spender {
+			recipientCheck, err := tr.screenRecipient(ctx, "Recipient", recipient.Hex(), policy)
+			if err != nil {
+				return ToolOutput{}, err
+			}
+			checks = checks.and(recipientCheck)
+		}
+	} else {
+		b.WriteString("- Gas: none; the spender submits the permit\n")
+		checks = tr.checkContract(ctx, params.Chain, cfg, "Spender", spender).and(checks)
+	}
+	b.WriteString(checks.previewText())
+	summary := b.String()
+
+	if !params.Confirm {
+		return ToolOutput{Text: summary + "\nSet confirm=true and provide password to sign."}, nil
+	}
+	if err := tr.checkSigner(params.Chain, owner, params.Password); err != nil {
+		return ToolOutput{}, err
+	}
+	td := p.TypedData(domain)
+	sig, err := tr.signTypedData(owner, params.Password, td)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "Token", Value: tokenAddr.Hex()},
+		{Key: "Spender", Value: spender.Hex()},
+		{Key: "Amount", Value: params.AmountTokens + " " + symbol},
+		{Key: "Deadline", Value: deadline.UTC().Format("2006-01-02 15:04 UTC")},
+	}
+
+	if !params.Relay {
+		message, err := json.Marshal(td)
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		text := fmt.Sprintf("%s\nSigned permit (EIP-712):\n%s\nSignature: %s\n- v: %d\n- r: %s\n- s: %s\nThe spender submits it with permit(owner, spender, value, deadline, v, r, s) before the deadline.",
+			summary, message, hexutil.Encode(sig), sig[64], hexutil.Encode(sig[:32]), hexutil.Encode(sig[32:64]))
+		items = append(items, KVItem{Key: "Signature", Value: hexutil.Encode(sig)})
+		return ToolOutput{Text: text, Blocks: []UIBlock{kvBlock("EIP-2612 permit", items...)}}, nil
+	}
+
+	spenderPassword := params.SpenderPassword
+	if spenderPassword == "" {
+		spenderPassword = params.Password
+	}
+	calldata, err := p.Calldata(sig)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	permitHash, confirmed, err := tr.sendAndWait(relayCtx, tx.Intent{Chain: params.Chain, From: spender, To: tokenAddr, ValueWei: big.NewInt(0), Data: calldata}, cfg.ChainID, spenderPassword)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("permit: %w", err)
+	}
+	text := summary + "\nPermit tx: " + permitHash.Hex()
+	if confirmed == nil || !confirmed.Success {
+		return ToolOutput{Text: text, Confirmed: confirmed}, fmt.Errorf("permit %s did not succeed; transferFrom not sent", permitHash.Hex())
+	}
+	data := buildERC20TransferFromData(owner, recipient, amount)
+	hash, confirmed, err := tr.sendAndWait(relayCtx, tx.Intent{Chain: params.Chain, From: spender, To: tokenAddr, ValueWei: big.NewInt(0), Data: data}, cfg.ChainID, spenderPassword)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("transferFrom after permit %s: %w", permitHash.Hex(), err)
+	}
+	text += "\nTransfer tx: " + hash.Hex()
+	if url := tr.txURL(params.Chain, hash.Hex()); url != "" {
+		text += "\nExplorer: " + url
+	}
+	if confirmed != nil {
+		text += fmt.Sprintf("\nReceipt status: %t", confirmed.Success)
+	}
+	items = append(items, KVItem{Key: "To", Value: recipient.Hex()}, tr.txItem(params.Chain, hash.Hex()))
+	return ToolOutput{Text: text, Confirmed: confirmed, Blocks: []UIBlock{kvBlock("Permit and transfer", items...)}}, nil
+}
@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleSignPermit_Validation(t *testing.T) {
+	tr := NewToolRegistry()
+	spender := "0x00000000000000000000000000000000000000bb"
+	token := "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	for _, tc := range []struct {
+		input, want string
+	}{
+		{`{"chain":"ethereum","token":"` + token + `","amount_tokens":"1"}`, "spender"},
+		{`{"chain":"ethereum","token":"` + token + `","spender":"` + spender + `"}`, "amount_tokens is required"},
+		{`{"chain":"ethereum","token":"` + token + `","spender":"` + spender + `","amount_tokens":"1","deadline_minutes":20000}`, "deadline_minutes"},
+	} {
+		_, err := tr.handleSignPermit(context.Background(), json.RawMessage(tc.input))
+		assert.ErrorContains(t, err, tc.want, tc.input)
+	}
+}
+
+func TestBuildERC20TransferFromData(t *testing.T) {
+	data := buildERC20TransferFromData(common.HexToAddress("0xaa"), common.HexToAddress("0xbb"), big.NewInt(5))
+	assert.Equal(t, "0x23b872dd", hexutil.Encode(data[:4]))
+	assert.Len(t, data, 4+3*32)
+	assert.Equal(t, byte(0xaa), data[4+31])
+	assert.Equal(t, byte(0xbb), data[4+63])
+	assert.Equal(t, byte(5), data[4+95])
+}
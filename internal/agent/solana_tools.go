@@ -0,0 +1,327 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/solana"
+)
+
+// solanaSignatureFee is the base fee for a single-signature transaction.
+// Priority fees are not set, so this is the whole fee.
+const solanaSignatureFee = 5000
+
+func (tr *ToolRegistry) solanaKeystore() (*solana.Keystore, error) {
+	tr.solKsOnce.Do(func() {
+		if tr.dataDir == "" {
+			tr.solKsErr = fmt.Errorf("data dir not configured")
+			return
+		}
+		tr.solKs, tr.solKsErr = solana.NewKeystore(tr.dataDir)
+	})
+	return tr.solKs, tr.solKsErr
+}
+
+// solanaAccount resolves address, defaulting to the first stored keypair.
+func (tr *ToolRegistry) solanaAccount(address string) (solana.PublicKey, error) {
+	if address != "" {
+		return solana.ParsePublicKey(address)
+	}
+	ks, err := tr.solanaKeystore()
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	if len(keys) == 0 {
+		return solana.PublicKey{}, fmt.Errorf("no solana wallets found; use 'clifi wallet solana create' or pass an address")
+	}
+	return keys[0], nil
+}
+
+func solanaCluster(cluster string) string {
+	if cluster == "" {
+		return "solana"
+	}
+	return cluster
+}
+
+type getSolanaBalanceInput struct {
+	Address string `json:"address"`
+	Cluster string `json:"cluster"`
+}
+
+func (tr *ToolRegistry) handleGetSolanaBalance(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	var params getSolanaBalanceInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	cluster := solanaCluster(params.Cluster)
+	owner, err := tr.solanaAccount(params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	lamports, err := tr.solClient.GetBalance(ctx, cluster, owner)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	formatted := solana.FormatSOL(lamports) + " SOL"
+	return ToolOutput{
+		Text: fmt.Sprintf("Balance for %s on %s: %s", owner, cluster, formatted),
+		Blocks: []UIBlock{kvBlock("Solana balance",
+			KVItem{Key: "Cluster", Value: cluster},
+			KVItem{Key: "Address", Value: owner.String()},
+			KVItem{Key: "Balance", Value: formatted},
+		)},
+	}, nil
+}
+
+type getSPLTokenBalancesInput struct {
+	Address string `json:"address"`
+	Cluster string `json:"cluster"`
+	Mint    string `json:"mint"`
+}
+
+func (tr *ToolRegistry) handleGetSPLTokenBalances(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	var params getSPLTokenBalancesInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	cluster := solanaCluster(params.Cluster)
+	owner, err := tr.solanaAccount(params.Address)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	var mint *solana.PublicKey
+	if params.Mint != "" {
+		m, err := solana.ParsePublicKey(params.Mint)
+		if err != nil {
+			return ToolOutput{}, fmt.Errorf("invalid mint: %w", err)
+		}
+		mint = &m
+	}
+
+	balances, err := tr.solClient.GetTokenBalances(ctx, cluster, owner, mint)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(balances) == 0 {
+		return ToolOutput{Text: fmt.Sprintf("No SPL token balances for %s on %s.", owner, cluster)}, nil
+	}
+
+	lines := []string{fmt.Sprintf("SPL token balances for %s on %s:", owner, cluster)}
+	table := &UITable{
+		Title:   fmt.Sprintf("SPL tokens (%s)", cluster),
+		Headers: []string{"Mint", "Balance", "Decimals"},
+		Rows:    make([][]string, 0, len(balances)),
+	}
+	for _, b := range balances {
+		lines = append(lines, fmt.Sprintf("- %s: %s (decimals %d)", b.Mint, b.UIAmount, b.Decimals))
+		table.Rows = append(table.Rows, []string{b.Mint, b.UIAmount, fmt.Sprintf("%d", b.Decimals)})
+	}
+	return ToolOutput{Text: strings.Join(lines, "\n"), Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+func (tr *ToolRegistry) handleListSolanaWallets(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ks, err := tr.solanaKeystore()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(keys) == 0 {
+		return ToolOutput{Text: "No Solana wallets found. Use 'clifi wallet solana create' to create one."}, nil
+	}
+
+	var results []string
+	table := &UITable{
+		Title:   fmt.Sprintf("Solana wallets (%d)", len(keys)),
+		Headers: []string{"#", "Address"},
+		Rows:    make([][]string, 0, len(keys)),
+	}
+	for i, k := range keys {
+		results = append(results, fmt.Sprintf("%d. %s", i+1, k))
+		table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", i+1), k.String()})
+	}
+	text := fmt.Sprintf("Found %d Solana wallet(s):\n%s", len(keys), strings.Join(results, "\n"))
+	return ToolOutput{Text: text, Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
+}
+
+type sendSOLInput struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Cluster   string `json:"cluster"`
+	AmountSOL string `json:"amount_sol"`
+	Password  string `json:"password"`
+	Confirm   bool   `json:"confirm"`
+	Wait      *bool  `json:"wait"`
+}
+
+func (tr *ToolRegistry) handleSendSOL(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	var params sendSOLInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	cluster := solanaCluster(params.Cluster)
+	cfg, err := tr.solClient.GetClusterConfig(cluster)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	to, err := solana.ParsePublicKey(params.To)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid recipient: %w", err)
+	}
+	if params.AmountSOL == "" {
+		return ToolOutput{}, fmt.Errorf("amount_sol is required")
+	}
+	lamports, err := solana.ParseSOL(params.AmountSOL)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("invalid amount_sol: %w", err)
+	}
+	if lamports == 0 {
+		return ToolOutput{}, fmt.Errorf("amount_sol must be greater than zero")
+	}
+	// The total with the fee must not wrap around.
+	if lamports > math.MaxUint64-solanaSignatureFee {
+		return ToolOutput{}, fmt.Errorf("amount_sol is too large")
+	}
+	if limit, ok := loadMaxTxLamports(); ok && lamports > limit {
+		return ToolOutput{}, fmt.Errorf("amount exceeds CLIFI_MAX_TX_SOL (%s SOL)", solana.FormatSOL(limit))
+	}
+
+	recipientCheck, err := tr.screenRecipient(ctx, "Recipient", to.String(), loadPolicy())
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	from, err := tr.solanaAccount(params.From)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	ks, err := tr.solanaKeystore()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if !ks.Has(from) {
+		return ToolOutput{}, fmt.Errorf("address %s is not in the solana keystore", from)
+	}
+
+	balance, err := tr.solClient.GetBalance(ctx, cluster, from)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to read sender balance: %w", err)
+	}
+	total := lamports + solanaSignatureFee
+	if balance < total {
+		return ToolOutput{}, fmt.Errorf("insufficient balance: have %s SOL, need %s SOL including fee", solana.FormatSOL(balance), solana.FormatSOL(total))
+	}
+
+	summary := fmt.Sprintf("Preview:\n- Cluster: %s\n- From: %s\n- To: %s\n- Amount: %s SOL\n- Fee: %s SOL\n- Estimated total: %s SOL\n",
+		cluster,
+		from,
+		to,
+		solana.FormatSOL(lamports),
+		solana.FormatSOL(solanaSignatureFee),
+		solana.FormatSOL(total),
+	)
+	summary += recipientCheck.previewText()
+
+	if !params.Confirm {
+		if params.Password == "" {
+			return ToolOutput{Text: summary + "\nSet confirm=true and provide password to sign and broadcast."}, nil
+		}
+		return ToolOutput{Text: summary + "\nSet confirm=true to sign and broadcast."}, nil
+	}
+	if params.Password == "" {
+		return ToolOutput{}, fmt.Errorf("password required to sign")
+	}
+
+	signer, err := ks.Signer(from, params.Password)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to unlock signer: %w", err)
+	}
+	defer signer.Lock()
+
+	blockhash, err := tr.solClient.GetLatestBlockhash(ctx, cluster)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	msg, err := solana.NewTransferMessage(from, to, lamports, blockhash)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	txn := &solana.Transaction{Message: msg}
+	if err := signer.SignTransaction(txn); err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	raw, err := txn.Serialize()
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	sig, err := tr.solClient.SendTransaction(ctx, cluster, raw)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("failed to send tx: %w", err)
+	}
+
+	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, sig)
+	if url := cfg.TxURL(sig); url != "" {
+		result += "\nExplorer: " + url
+	}
+
+	if params.Wait == nil || *params.Wait {
+		// Blockhashes expire after ~60s, so a transaction that has not
+		// landed by then never will.
+		waitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 90*time.Second)
+		defer cancel()
+		status, err := tr.solClient.WaitForConfirmation(waitCtx, cluster, sig, 2*time.Second)
+		if err != nil {
+			result += "\n" + err.Error()
+		} else {
+			result += fmt.Sprintf("\nConfirmed in slot %d", status.Slot)
+		}
+	}
+
+	return ToolOutput{
+		Text: result,
+		Blocks: []UIBlock{kvBlock("SOL send",
+			KVItem{Key: "Cluster", Value: cluster},
+			KVItem{Key: "From", Value: from.String()},
+			KVItem{Key: "To", Value: to.String()},
+			KVItem{Key: "Amount", Value: solana.FormatSOL(lamports) + " SOL"},
+			KVItem{Key: "Tx", Value: sig, URL: cfg.TxURL(sig)},
+		)},
+	}, nil
+}
+
+// loadMaxTxLamports reads CLIFI_MAX_TX_SOL, the Solana counterpart of
+// CLIFI_MAX_TX_ETH. ok is false when unset or invalid.
+func loadMaxTxLamports() (uint64, bool) {
+	v := os.Getenv("CLIFI_MAX_TX_SOL")
+	if v == "" {
+		return 0, false
+	}
+	lamports, err := solana.ParseSOL(v)
+	if err != nil {
+		return 0, false
+	}
+	return lamports, true
+}
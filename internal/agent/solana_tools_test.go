@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/solana"
+)
+
+func TestToolRegistry_GetSolanaBalance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"value":2500000000}}`))
+	}))
+	defer srv.Close()
+
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+	tr.solClient.AddCluster("solana-local", &solana.ClusterConfig{Name: "Local", RPCURLs: []string{srv.URL}})
+
+	input := json.RawMessage(`{"address": "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", "cluster": "solana-local"}`)
+	out, err := tr.ExecuteTool(context.Background(), "get_solana_balance", input)
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "2.5 SOL")
+}
+
+func TestToolRegistry_SolanaValidation(t *testing.T) {
+	tr := NewToolRegistryWithDataDir(t.TempDir())
+	defer tr.Close()
+
+	t.Run("balance defaults to a stored wallet", func(t *testing.T) {
+		_, err := tr.ExecuteTool(context.Background(), "get_solana_balance", json.RawMessage(`{}`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no solana wallets")
+	})
+
+	t.Run("rejects EVM address", func(t *testing.T) {
+		input := json.RawMessage(`{"address": "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"}`)
+		_, err := tr.ExecuteTool(context.Background(), "get_spl_token_balances", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid solana address")
+	})
+
+	t.Run("send requires a positive amount", func(t *testing.T) {
+		input := json.RawMessage(`{"to": "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", "amount_sol": "0"}`)
+		_, err := tr.ExecuteTool(context.Background(), "send_sol", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "greater than zero")
+	})
+
+	t.Run("send rejects an amount the fee would wrap", func(t *testing.T) {
+		input := json.RawMessage(`{"to": "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", "amount_sol": "18446744073.709551615"}`)
+		_, err := tr.ExecuteTool(context.Background(), "send_sol", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "amount_sol is too large")
+	})
+
+	t.Run("send honors CLIFI_MAX_TX_SOL", func(t *testing.T) {
+		t.Setenv("CLIFI_MAX_TX_SOL", "1")
+		input := json.RawMessage(`{"to": "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", "amount_sol": "1.5"}`)
+		_, err := tr.ExecuteTool(context.Background(), "send_sol", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CLIFI_MAX_TX_SOL")
+	})
+
+	t.Run("list reports no wallets", func(t *testing.T) {
+		out, err := tr.ExecuteTool(context.Background(), "list_solana_wallets", json.RawMessage(`{}`))
+		require.NoError(t, err)
+		assert.Contains(t, out.Text, "No Solana wallets")
+	})
+}
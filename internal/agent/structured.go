@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yolodolo42/clifi/internal/jsonschema"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// structuredRetries is how many times AskJSON asks again after an answer
+// fails the schema.
+const structuredRetries = 2
+
+type responseSchemaKey struct{}
+
+func withResponseSchema(ctx context.Context, s *jsonschema.Schema) context.Context {
+	return context.WithValue(ctx, responseSchemaKey{}, s)
+}
+
+func responseSchemaFrom(ctx context.Context) *jsonschema.Schema {
+	s, _ := ctx.Value(responseSchemaKey{}).(*jsonschema.Schema)
+	return s
+}
+
+// describeAnswerFormat is the system prompt section asking for a JSON
+// answer. Providers with native structured output enforce it as well.
+func describeAnswerFormat(s *jsonschema.Schema) string {
+	return "## Answer format\nUse tools as usual, then reply with only a JSON value valid against this JSON Schema: no prose, no code fence. Use null or an empty value for anything you could not find rather than inventing it.\n" + string(s.Raw())
+}
+
+// AskJSON answers question with a JSON value valid against schema, for
+// scripts. The schema goes to the provider as native structured output
+// where it has one; otherwise the answer is validated here and the model
+// is asked to fix it, up to structuredRetries times.
+func (s *Session) AskJSON(ctx context.Context, question string, schema *jsonschema.Schema, onEvent func(ChatEvent)) ([]byte, error) {
+	ctx = withResponseSchema(ctx, schema)
+	message := question
+	for attempt := 0; ; attempt++ {
+		events, err := s.ChatStream(ctx, message, onEvent)
+		if err != nil {
+			return nil, err
+		}
+		answer := jsonschema.Extract(lastContent(events))
+		verr := schema.Validate([]byte(answer))
+		if verr == nil {
+			return []byte(answer), nil
+		}
+		if attempt == structuredRetries {
+			return nil, fmt.Errorf("answer does not match the schema after %d attempts: %w", attempt+1, verr)
+		}
+		message = fmt.Sprintf("Your answer does not match the JSON Schema: %v. Reply again with only the corrected JSON value.", verr)
+		if toolChoiceFrom(ctx).Mode == llm.ToolChoiceForce {
+			// The forced tool already ran; fixing the answer doesn't
+			// need it again.
+			ctx = WithToolChoice(ctx, llm.ToolChoice{})
+		}
+	}
+}
+
+func lastContent(events []ChatEvent) string {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == "content" {
+			return events[i].Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/jsonschema"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestAgent_AskJSON(t *testing.T) {
+	schema, err := jsonschema.Parse([]byte(`{"type":"object","properties":{"eth":{"type":"number"}},"required":["eth"]}`))
+	require.NoError(t, err)
+
+	provider := llm.NewMockProvider(
+		llm.MockTurn{Responses: []llm.ChatResponse{{Content: "Your balance is 1.5 ETH."}}},
+		llm.MockTurn{Responses: []llm.ChatResponse{{Content: "```json\n{\"eth\": 1.5}\n```"}}},
+	)
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	answer, err := a.AskJSON(context.Background(), "my eth balance?", schema, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"eth": 1.5}`, string(answer))
+
+	reqs := provider.Requests()
+	require.Len(t, reqs, 2, "the prose answer is retried once")
+	assert.JSONEq(t, string(schema.Raw()), string(reqs[0].ResponseSchema))
+	assert.Contains(t, reqs[0].SystemPrompt, "## Answer format")
+	assert.Contains(t, lastUserMessageOf(reqs[1]), "does not match the JSON Schema")
+}
+
+func TestAgent_AskJSONGivesUp(t *testing.T) {
+	schema, err := jsonschema.Parse([]byte(`{"type":"array"}`))
+	require.NoError(t, err)
+
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{{Content: "{}"}}})
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	_, err = a.AskJSON(context.Background(), "list", schema, nil)
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Len(t, provider.Requests(), 3)
+}
+
+func lastUserMessageOf(req llm.ChatRequest) string {
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == "user" {
+			return req.Messages[i].Content
+		}
+	}
+	return ""
+}
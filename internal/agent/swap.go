@@ -0,0 +1,328 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/quote"
+	"github.com/yolodolo42/clifi/internal/tx"
+)
+
+// SwapRequest is a same-chain swap, made by the swap tool or outside the
+// chat loop (e.g. by a DCA plan). Tokens are symbols, addresses or
+// "native"; Amount is in FromToken units.
+type SwapRequest struct {
+	Chain       string
+	From        string // address; empty for the default wallet
+	FromToken   string
+	ToToken     string
+	Amount      string
+	SlippageBps *uint32
+}
+
+// PreparedSwap is a quoted, policy-checked swap and its preview.
+type PreparedSwap struct {
+	Chain      string
+	From       common.Address
+	Amount     string
+	FromSymbol string
+	ToSymbol   string
+	// ExpectedOut and MinOut are in ToToken units.
+	ExpectedOut string
+	MinOut      string
+	Route       string
+	Preview     string
+	// Warnings are preview lines the user must acknowledge, such as a
+	// high-risk token.
+	Warnings []string
+
+	swap    *quote.Swap
+	token   common.Address // FromToken; quote.NativeToken for native
+	approve bool           // the router needs an allowance first
+	chainID *big.Int
+}
+
+// SwapResult is an executed swap.
+type SwapResult struct {
+	ApprovalHash *common.Hash
+	Hash         common.Hash
+	ExplorerURL  string
+	Receipt      string
+	Confirmed    *TxConfirmation
+}
+
+// swapParams are a SwapRequest's checked and resolved inputs.
+type swapParams struct {
+	from      common.Address
+	cfg       *chain.ChainConfig
+	limits    tx.SwapLimits
+	fromToken common.Address
+	toToken   common.Address
+}
+
+func (tr *ToolRegistry) resolveSwap(req SwapRequest, policy tx.Policy) (swapParams, error) {
+	if strings.TrimSpace(req.Amount) == "" {
+		return swapParams{}, fmt.Errorf("amount is required")
+	}
+	fromAddr, cfg, err := tr.prepareTxFrom(req.Chain, req.From)
+	if err != nil {
+		return swapParams{}, err
+	}
+	if cfg.IsTestnet {
+		return swapParams{}, fmt.Errorf("swaps are only available on mainnets")
+	}
+	limits, err := swapLimits(req.SlippageBps, nil, policy)
+	if err != nil {
+		return swapParams{}, err
+	}
+	fromToken, err := tr.resolveQuoteToken(req.Chain, cfg, req.FromToken)
+	if err != nil {
+		return swapParams{}, fmt.Errorf("from_token: %w", err)
+	}
+	toToken, err := tr.resolveQuoteToken(req.Chain, cfg, req.ToToken)
+	if err != nil {
+		return swapParams{}, fmt.Errorf("to_token: %w", err)
+	}
+	if fromToken == toToken {
+		return swapParams{}, fmt.Errorf("from_token and to_token are the same")
+	}
+	return swapParams{from: fromAddr, cfg: cfg, limits: limits, fromToken: fromToken, toToken: toToken}, nil
+}
+
+// CheckSwap checks what it can of req without the network: the chain,
+// wallet, tokens and slippage. Scheduled swaps use it to fail when they are
+// defined rather than when they first run.
+func (tr *ToolRegistry) CheckSwap(req SwapRequest) error {
+	_, err := tr.resolveSwap(req, loadPolicy())
+	return err
+}
+
+// PrepareSwap quotes req and checks it against the policy: slippage
+// ceiling, and the allow/deny lists and per-tx limit for the router (and
+// the token, when an approval is needed).
+func (tr *ToolRegistry) PrepareSwap(ctx context.Context, req SwapRequest) (*PreparedSwap, error) {
+	policy := loadPolicy()
+	sp, err := tr.resolveSwap(req, policy)
+	if err != nil {
+		return nil, err
+	}
+	decimals := uint8(18)
+	if sp.fromToken != quote.NativeToken {
+		if decimals, _, err = queryTokenMeta(ctx, tr.chainClient, req.Chain, sp.fromToken); err != nil {
+			return nil, fmt.Errorf("failed to read token decimals: %w", err)
+		}
+	}
+	amount, err := decimalToWei(req.Amount, int(decimals))
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	s, err := tr.quotes.SwapTx(reqCtx, quote.Request{
+		FromChainID: sp.cfg.ChainIDInt,
+		ToChainID:   sp.cfg.ChainIDInt,
+		FromToken:   sp.fromToken,
+		ToToken:     sp.toToken,
+		FromAmount:  amount,
+		SlippageBps: sp.limits.SlippageBps,
+		FromAddress: &sp.from,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Validate(tx.Intent{Chain: req.Chain, From: sp.from, To: s.To, ValueWei: s.Value, Data: s.Data}, policy); err != nil {
+		return nil, fmt.Errorf("swap router %s: %w", s.To.Hex(), err)
+	}
+	p := &PreparedSwap{
+		Chain:       req.Chain,
+		From:        sp.from,
+		Amount:      req.Amount,
+		FromSymbol:  s.FromToken.Symbol,
+		ToSymbol:    s.ToToken.Symbol,
+		ExpectedOut: chain.FormatBalance(s.ToAmount, s.ToToken.Decimals),
+		MinOut:      chain.FormatBalance(s.ToAmountMin, s.ToToken.Decimals),
+		Route:       s.Tool,
+		swap:        s,
+		token:       sp.fromToken,
+		chainID:     sp.cfg.ChainID,
+	}
+	if sp.fromToken != quote.NativeToken {
+		allowance, err := tr.allowance(ctx, req.Chain, sp.fromToken, sp.from, s.ApprovalAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read allowance: %w", err)
+		}
+		if allowance.Cmp(amount) < 0 {
+			if err := tx.Validate(tx.Intent{Chain: req.Chain, From: sp.from, To: sp.fromToken, ValueWei: big.NewInt(0)}, policy); err != nil {
+				return nil, fmt.Errorf("approval of %s: %w", s.FromToken.Symbol, err)
+			}
+			p.approve = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview swap:\n- Chain: %s\n- From: %s\n- Sell: %s %s\n- Expected: %s %s\n- Min received: %s %s (slippage %s)\n- Route: %s\n- Router: %s\n",
+		req.Chain, sp.from.Hex(), req.Amount, p.FromSymbol, p.ExpectedOut, p.ToSymbol, p.MinOut, p.ToSymbol,
+		tx.FormatBps(sp.limits.SlippageBps), p.Route, s.To.Hex())
+	if p.approve {
+		fmt.Fprintf(&b, "- Approval: %s %s to %s is sent first\n", req.Amount, p.FromSymbol, s.ApprovalAddress.Hex())
+	}
+	p.Preview = b.String()
+	for _, side := range []struct {
+		role  string
+		token common.Address
+	}{{"Sell token", sp.fromToken}, {"Buy token", sp.toToken}} {
+		if side.token != quote.NativeToken {
+			p.addCheck(tr.tokenRiskCheck(ctx, req.Chain, sp.cfg, side.role, side.token, sp.from))
+		}
+	}
+	return p, nil
+}
+
+// addCheck appends a check's lines to the preview, keeping them as
+// warnings when the user should acknowledge them.
+func (p *PreparedSwap) addCheck(c contractCheck) {
+	if len(c.Lines) == 0 {
+		return
+	}
+	p.Preview += strings.Join(c.Lines, "\n") + "\n"
+	if c.Warn {
+		p.Warnings = append(p.Warnings, c.Lines...)
+	}
+}
+
+// SwapPrepared sends the approval, when needed, then the swap, waiting for
+// each to be mined.
+func (tr *ToolRegistry) SwapPrepared(ctx context.Context, p *PreparedSwap, password string) (*SwapResult, error) {
+	if err := tr.checkSigner(p.Chain, p.From, password); err != nil {
+		return nil, err
+	}
+	res := &SwapResult{}
+	if p.approve {
+		data, err := buildERC20ApproveData(p.swap.ApprovalAddress, p.swap.FromAmount)
+		if err != nil {
+			return nil, err
+		}
+		hash, confirmed, err := tr.sendAndWait(ctx, tx.Intent{Chain: p.Chain, From: p.From, To: p.token, ValueWei: big.NewInt(0), Data: data}, p.chainID, password)
+		if err != nil {
+			return nil, fmt.Errorf("approval: %w", err)
+		}
+		res.ApprovalHash = &hash
+		if confirmed == nil || !confirmed.Success {
+			return res, fmt.Errorf("approval %s did not succeed; swap not sent", hash.Hex())
+		}
+	}
+
+	hash, confirmed, err := tr.sendAndWait(ctx, tx.Intent{Chain: p.Chain, From: p.From, To: p.swap.To, ValueWei: p.swap.Value, Data: p.swap.Data}, p.chainID, password)
+	if err != nil {
+		return res, err
+	}
+	res.Hash = hash
+	res.ExplorerURL = tr.txURL(p.Chain, hash.Hex())
+	res.Confirmed = confirmed
+	if confirmed != nil {
+		res.Receipt = fmt.Sprintf("Receipt status: %t", confirmed.Success)
+	}
+	return res, nil
+}
+
+// sendAndWait builds, signs and sends intent on the public mempool and
+// waits for it. The gas estimate happens here, after any earlier step
+// (an approval, a permit) is mined.
+func (tr *ToolRegistry) sendAndWait(ctx context.Context, intent tx.Intent, chainID *big.Int, password string) (common.Hash, *TxConfirmation, error) {
+	buildCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	unsigned, _, err := tx.BuildUnsignedTx(buildCtx, tr.chainClient, intent)
+	cancel()
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	signed, err := tr.signAndSendTx(ctx, intent.Chain, intent.From, password, unsigned, chainID, nil)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	wait := true
+	_, confirmed := tr.maybeWaitAndPersistReceipt(ctx, intent.Chain, signed.Hash(), &wait)
+	return signed.Hash(), confirmed, nil
+}
+
+// allowance reads token.allowance(owner, spender).
+func (tr *ToolRegistry) allowance(ctx context.Context, chainName string, token, owner, spender common.Address) (*big.Int, error) {
+	data := append(common.FromHex("0xdd62ed3e"), common.LeftPadBytes(owner.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(spender.Bytes(), 32)...)
+	out, err := tr.chainClient.CallContract(ctx, chainName, ethereum.CallMsg{To: &token, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	if len(out) < 32 {
+		return nil, fmt.Errorf("unexpected allowance result")
+	}
+	return new(big.Int).SetBytes(out[:32]), nil
+}
+
+type swapInput struct {
+	Chain       string  `json:"chain"`
+	From        string  `json:"from"`
+	FromToken   string  `json:"from_token"`
+	ToToken     string  `json:"to_token"`
+	Amount      string  `json:"amount"`
+	SlippageBps *uint32 `json:"slippage_bps"`
+	Password    string  `json:"password"`
+	Confirm     bool    `json:"confirm"`
+}
+
+func (tr *ToolRegistry) handleSwap(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params swapInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	p, err := tr.PrepareSwap(ctx, SwapRequest{
+		Chain: params.Chain, From: params.From, FromToken: params.FromToken, ToToken: params.ToToken,
+		Amount: params.Amount, SlippageBps: params.SlippageBps,
+	})
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if len(p.Warnings) > 0 {
+		p.Preview += "Ask the user to explicitly acknowledge the warning above before setting confirm=true.\n"
+	}
+	if !params.Confirm {
+		return ToolOutput{Text: p.Preview + "\nSet confirm=true and provide password to broadcast."}, nil
+	}
+
+	res, err := tr.SwapPrepared(ctx, p, params.Password)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	text := fmt.Sprintf("%s\nBroadcasted tx: %s", p.Preview, res.Hash.Hex())
+	if res.ApprovalHash != nil {
+		text += "\nApproval tx: " + res.ApprovalHash.Hex()
+	}
+	if res.ExplorerURL != "" {
+		text += "\nExplorer: " + res.ExplorerURL
+	}
+	if res.Receipt != "" {
+		text += "\n" + res.Receipt
+	}
+	return ToolOutput{
+		Text:      text,
+		Confirmed: res.Confirmed,
+		Blocks: []UIBlock{kvBlock("Swap",
+			KVItem{Key: "Chain", Value: p.Chain},
+			KVItem{Key: "Sell", Value: p.Amount + " " + p.FromSymbol},
+			KVItem{Key: "Min received", Value: p.MinOut + " " + p.ToSymbol},
+			KVItem{Key: "Route", Value: p.Route},
+			tr.txItem(p.Chain, res.Hash.Hex()),
+		)},
+	}, nil
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/tokenrisk"
+)
+
+// riskProbe receives the simulated transfer in honeypot checks. It is an
+// ordinary address no token should treat specially, unlike 0x0 or 0xdead.
+var riskProbe = common.HexToAddress("0x5Fd1a2b3c4d5e6f708192a3b4c5d6e7f80910c1f")
+
+// assessToken rates token for a preview. Tokens on a token list are taken
+// as vetted; anything else is also checked for impersonation and, when
+// holder has a balance, whether it can be transferred at all. Like
+// checkContract it never fails the tool call.
+func (tr *ToolRegistry) assessToken(ctx context.Context, chainName string, cfg *chain.ChainConfig, token, holder common.Address) (tokenrisk.Verdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var f tokenrisk.Facts
+	if s := tr.screener(); s.Enabled() {
+		for _, h := range s.Screen(ctx, token.Hex()).Hits {
+			f.Flagged = append(f.Flagged, h.String())
+		}
+	}
+	resolver, err := tr.tokenResolver()
+	if err != nil {
+		return tokenrisk.Verdict{}, err
+	}
+	if m, ok := resolver.Lookup(cfg.ChainID.Int64(), token); ok {
+		f.Symbol, f.Name, f.Listed = m.Symbol, m.Name, m.Lists
+		return tokenrisk.Assess(f), nil
+	}
+
+	bal, err := tr.chainClient.GetTokenBalance(ctx, chainName, token, holder)
+	if err != nil {
+		return tokenrisk.Verdict{}, err
+	}
+	f.Symbol, f.Name = bal.Symbol, bal.Name
+	if f.Symbol != "" {
+		matches, err := resolver.Resolve(cfg.ChainID.Int64(), f.Symbol)
+		if err != nil && !errors.Is(err, chain.ErrAmbiguousToken) && !errors.Is(err, chain.ErrTokenNotListed) {
+			return tokenrisk.Verdict{}, err
+		}
+		for _, m := range matches {
+			f.Impersonates = append(f.Impersonates, m.Symbol+" "+m.Address)
+		}
+	}
+	if bal.Balance != nil && bal.Balance.Sign() > 0 {
+		f.Transfer, f.TransferError = tr.simulateTransfer(ctx, chainName, token, holder, bal.Balance)
+	}
+	return tokenrisk.Assess(f), nil
+}
+
+// simulateTransfer eth_calls transfer(riskProbe, amount) from holder. A
+// honeypot lets anyone buy but reverts (or returns false) when holders
+// try to move or sell. Network errors leave the check as not run.
+func (tr *ToolRegistry) simulateTransfer(ctx context.Context, chainName string, token, holder common.Address, amount *big.Int) (tokenrisk.Transfer, string) {
+	data, err := buildERC20TransferData(riskProbe, amount)
+	if err != nil {
+		return tokenrisk.TransferNotRun, ""
+	}
+	out, err := tr.chainClient.CallContract(ctx, chainName, ethereum.CallMsg{From: holder, To: &token, Data: data})
+	switch {
+	case err != nil && chain.IsRPCError(err):
+		return tokenrisk.TransferFailed, err.Error()
+	case err != nil:
+		return tokenrisk.TransferNotRun, ""
+	case len(out) >= 32 && new(big.Int).SetBytes(out[:32]).Sign() == 0:
+		return tokenrisk.TransferFailed, "transfer returned false"
+	}
+	// Empty output is fine: older tokens such as USDT return nothing.
+	return tokenrisk.TransferOK, ""
+}
+
+// tokenRiskCheck renders assessToken for a preview. High risk is a
+// warning the user has to acknowledge; it is not refused, since lists and
+// heuristics can be wrong and the token may be exactly what the user
+// wants.
+func (tr *ToolRegistry) tokenRiskCheck(ctx context.Context, chainName string, cfg *chain.ChainConfig, role string, token, holder common.Address) contractCheck {
+	v, err := tr.assessToken(ctx, chainName, cfg, token, holder)
+	if err != nil {
+		return contractCheck{Lines: []string{fmt.Sprintf("- %s risk: unknown (%v)", role, err)}}
+	}
+	return describeTokenRisk(role, token, v)
+}
+
+func describeTokenRisk(role string, token common.Address, v tokenrisk.Verdict) contractCheck {
+	if v.Level == tokenrisk.High {
+		return contractCheck{
+			Warn:  true,
+			Lines: []string{fmt.Sprintf("⚠ WARNING: %s %s is HIGH RISK: %s.", role, token.Hex(), strings.Join(v.Reasons, "; "))},
+		}
+	}
+	return contractCheck{Lines: []string{fmt.Sprintf("- %s risk: %s", role, v)}}
+}
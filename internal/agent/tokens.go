@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// tokenResolver builds the symbol resolver on first use: the built-in token
+// set plus the lists named in CLIFI_TOKEN_LISTS (comma-separated https URLs
+// or file paths, e.g. https://tokens.uniswap.org).
+func (tr *ToolRegistry) tokenResolver() (*chain.TokenResolver, error) {
+	tr.tokensOnce.Do(func() {
+		chains := make(map[string]*chain.ChainConfig)
+		for _, name := range tr.chainClient.ListChains() {
+			if cfg, err := tr.chainClient.GetChainConfig(name); err == nil {
+				chains[name] = cfg
+			}
+		}
+		resolver := chain.NewTokenResolver(chains)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, source := range strings.Split(os.Getenv("CLIFI_TOKEN_LISTS"), ",") {
+			source = strings.TrimSpace(source)
+			if source == "" {
+				continue
+			}
+			list, err := chain.LoadTokenList(ctx, source)
+			if err != nil {
+				// Resolving against a partial set could pick a token the
+				// missing list would have flagged as ambiguous.
+				tr.tokensErr = fmt.Errorf("CLIFI_TOKEN_LISTS: %s: %w", source, err)
+				return
+			}
+			resolver.AddList(list)
+		}
+		tr.tokens = resolver
+	})
+	return tr.tokens, tr.tokensErr
+}
+
+// resolveToken accepts a token contract address or a symbol such as "USDC".
+// Symbols resolve only when exactly one contract is listed for them on the
+// chain; anything else is an error asking for the address.
+func (tr *ToolRegistry) resolveToken(chainName, token string) (common.Address, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return common.Address{}, fmt.Errorf("token is required")
+	}
+	if strings.HasPrefix(token, "0x") || strings.HasPrefix(token, "0X") {
+		return requireHexAddress("token address", token)
+	}
+
+	cfg, err := tr.chainClient.GetChainConfig(chainName)
+	if err != nil {
+		return common.Address{}, err
+	}
+	resolver, err := tr.tokenResolver()
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	matches, err := resolver.Resolve(cfg.ChainID.Int64(), token)
+	switch {
+	case errors.Is(err, chain.ErrTokenNotListed):
+		return common.Address{}, fmt.Errorf("invalid token address: %s is neither an address nor a symbol on any token list for %s; pass the token contract address instead", token, chainName)
+	case errors.Is(err, chain.ErrAmbiguousToken):
+		candidates := make([]string, len(matches))
+		for i, m := range matches {
+			candidates[i] = fmt.Sprintf("%s (%s; %s)", m.Address, m.Name, strings.Join(m.Lists, ", "))
+		}
+		return common.Address{}, fmt.Errorf("%s is ambiguous on %s, matching %d contracts: %s; pass the token contract address instead",
+			token, chainName, len(matches), strings.Join(candidates, "; "))
+	case err != nil:
+		return common.Address{}, err
+	}
+	return common.HexToAddress(matches[0].Address), nil
+}
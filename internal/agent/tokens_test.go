@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveToken(t *testing.T) {
+	list := `{"name": "Extra", "tokens": [
+		{"chainId": 8453, "address": "0x00000000000000000000000000000000000000aa", "symbol": "USDC", "name": "Impostor", "decimals": 6},
+		{"chainId": 8453, "address": "0x00000000000000000000000000000000000000bb", "symbol": "ZZZ", "name": "Zzz", "decimals": 18}
+	]}`
+	path := filepath.Join(t.TempDir(), "list.json")
+	require.NoError(t, os.WriteFile(path, []byte(list), 0600))
+
+	t.Run("addresses pass through", func(t *testing.T) {
+		t.Setenv("CLIFI_TOKEN_LISTS", "")
+		tr := NewToolRegistryWithDataDir("")
+		defer tr.Close()
+
+		addr, err := tr.resolveToken("base", "0x00000000000000000000000000000000000000cc")
+		require.NoError(t, err)
+		assert.Equal(t, "0x00000000000000000000000000000000000000cc", addr.Hex())
+
+		_, err = tr.resolveToken("base", "0x1234")
+		assert.Error(t, err)
+	})
+
+	t.Run("built-in symbols", func(t *testing.T) {
+		t.Setenv("CLIFI_TOKEN_LISTS", "")
+		tr := NewToolRegistryWithDataDir("")
+		defer tr.Close()
+
+		addr, err := tr.resolveToken("base", "USDC")
+		require.NoError(t, err)
+		assert.Equal(t, "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", addr.Hex())
+
+		_, err = tr.resolveToken("base", "ZZZ")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a symbol on any token list")
+	})
+
+	t.Run("configured lists", func(t *testing.T) {
+		t.Setenv("CLIFI_TOKEN_LISTS", path)
+		tr := NewToolRegistryWithDataDir("")
+		defer tr.Close()
+
+		addr, err := tr.resolveToken("base", "zzz")
+		require.NoError(t, err)
+		assert.Equal(t, "0x00000000000000000000000000000000000000bb", addr.Hex())
+
+		_, err = tr.resolveToken("base", "USDC")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ambiguous")
+		assert.Contains(t, err.Error(), "Impostor")
+	})
+
+	t.Run("unloadable list fails symbol lookups", func(t *testing.T) {
+		t.Setenv("CLIFI_TOKEN_LISTS", filepath.Join(t.TempDir(), "missing.json"))
+		tr := NewToolRegistryWithDataDir("")
+		defer tr.Close()
+
+		_, err := tr.resolveToken("base", "USDC")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+type toolChoiceKey struct{}
+
+// WithToolChoice sets how the model may use tools in requests made with
+// ctx. ToolChoiceForce makes its first reply call the named tool, for
+// deterministic flows; the model answers freely once it has the result.
+// ToolChoiceNone sends no tools at all.
+func WithToolChoice(ctx context.Context, choice llm.ToolChoice) context.Context {
+	return context.WithValue(ctx, toolChoiceKey{}, choice)
+}
+
+func toolChoiceFrom(ctx context.Context) llm.ToolChoice {
+	c, _ := ctx.Value(toolChoiceKey{}).(llm.ToolChoice)
+	return c
+}
+
+// checkToolChoice rejects a choice the provider would: an unknown mode or
+// a forced tool that isn't offered.
+func checkToolChoice(choice llm.ToolChoice, tools []llm.Tool) error {
+	switch choice.Mode {
+	case "", llm.ToolChoiceAuto, llm.ToolChoiceNone:
+		return nil
+	case llm.ToolChoiceForce:
+		if choice.Name == "" {
+			return fmt.Errorf("forcing a tool needs its name")
+		}
+		for _, t := range tools {
+			if t.Name == choice.Name {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown tool %q", choice.Name)
+	}
+	return fmt.Errorf("unknown tool choice %q", choice.Mode)
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestAgent_ForcedTool(t *testing.T) {
+	provider := llm.NewMockProvider(llm.MockTurn{Responses: []llm.ChatResponse{
+		{ToolCalls: []llm.ToolCall{{Name: "list_chains", Input: json.RawMessage(`{}`)}}},
+		{Content: "done"},
+	}})
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	ctx := WithToolChoice(context.Background(), llm.ToolChoice{Mode: llm.ToolChoiceForce, Name: "list_chains"})
+	reply, err := a.ChatStream(ctx, "which chains?", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "done", lastContent(reply))
+
+	reqs := provider.Requests()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, llm.ToolChoice{Mode: llm.ToolChoiceForce, Name: "list_chains"}, reqs[0].ToolChoice)
+	assert.Equal(t, llm.ToolChoice{}, reqs[1].ToolChoice, "the tool result is answered freely")
+}
+
+func TestAgent_ToolChoiceNone(t *testing.T) {
+	provider := llm.NewMockProvider()
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	ctx := WithToolChoice(context.Background(), llm.ToolChoice{Mode: llm.ToolChoiceNone})
+	_, err := a.ChatStream(ctx, "hi", nil)
+	require.NoError(t, err)
+	assert.Empty(t, provider.Requests()[0].Tools)
+}
+
+func TestAgent_ForcedUnknownTool(t *testing.T) {
+	provider := llm.NewMockProvider()
+	a := NewWithProvider(provider, t.TempDir())
+	defer a.Close()
+
+	ctx := WithToolChoice(context.Background(), llm.ToolChoice{Mode: llm.ToolChoiceForce, Name: "nope"})
+	_, err := a.ChatStream(ctx, "hi", nil)
+	assert.ErrorContains(t, err, `unknown tool "nope"`)
+	assert.Empty(t, provider.Requests())
+	assert.Empty(t, a.main.conversation, "a rejected request leaves no message behind")
+}
@@ -6,4 +6,14 @@ package agent
 type ToolOutput struct {
 	Text   string    `json:"text"`
 	Blocks []UIBlock `json:"blocks,omitempty"`
+	// Confirmed is set when the tool waited for a transaction and it was
+	// mined, so the REPL can tell a user who has looked away.
+	Confirmed *TxConfirmation `json:"confirmed,omitempty"`
+}
+
+// TxConfirmation is a transaction a tool saw mined.
+type TxConfirmation struct {
+	Chain   string `json:"chain"`
+	TxHash  string `json:"tx_hash"`
+	Success bool   `json:"success"`
 }
@@ -1,32 +1,47 @@
 package agent
 
 import (
-	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/cosmos"
 	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/quote"
+	"github.com/yolodolo42/clifi/internal/relay"
+	"github.com/yolodolo42/clifi/internal/screen"
+	"github.com/yolodolo42/clifi/internal/solana"
+	"github.com/yolodolo42/clifi/internal/tokenrisk"
 	"github.com/yolodolo42/clifi/internal/tx"
 	"github.com/yolodolo42/clifi/internal/wallet"
 )
 
 // ToolRegistry manages available tools and their handlers
 type ToolRegistry struct {
-	tools       []llm.Tool
-	handlers    map[string]toolHandler
+	mu           sync.RWMutex
+	tools        []llm.Tool
+	handlers     map[string]ToolHandler
+	capabilities map[string]ToolCapability
+
 	chainClient *chain.Client
-	dataDir     string
+	// txQueue assigns nonces to EVM sends, one account at a time.
+	txQueue  *tx.Queue
+	verifier *chain.Verifier
+	quotes   *quote.Client
+	dataDir  string
+	pager    *outputPager
 
 	kmOnce sync.Once
 	km     *wallet.KeystoreManager
@@ -35,6 +50,27 @@ type ToolRegistry struct {
 	receiptsOnce sync.Once
 	receipts     *ReceiptStore
 	receiptsErr  error
+
+	tokensOnce sync.Once
+	tokens     *chain.TokenResolver
+	tokensErr  error
+
+	solClient *solana.Client
+	solKsOnce sync.Once
+	solKs     *solana.Keystore
+	solKsErr  error
+
+	cosmosClient *cosmos.Client
+	cosmosKsOnce sync.Once
+	cosmosKs     *cosmos.Keystore
+	cosmosKsErr  error
+
+	screenOnce sync.Once
+	screen     *screen.Screener
+
+	relayOnce sync.Once
+	relay     *relay.Client
+	relayErr  error
 }
 
 // NewToolRegistry creates a new tool registry with default crypto tools
@@ -50,22 +86,77 @@ func NewToolRegistry() *ToolRegistry {
 // When dataDir is empty, wallet/receipt persistence is disabled and tools fall back to best-effort behavior.
 func NewToolRegistryWithDataDir(dataDir string) *ToolRegistry {
 	tr := &ToolRegistry{
-		tools:       llm.CryptoTools(),
+		tools:       withPageParam(llm.CryptoTools()),
 		chainClient: chain.NewClient(),
+		verifier:    chain.NewVerifier(),
+		quotes:      quote.NewClient(),
 		dataDir:     dataDir,
-	}
-
-	tr.handlers = map[string]toolHandler{
-		"get_balances":      tr.handleGetBalances,
-		"get_token_balance": tr.handleGetTokenBalance,
-		"list_wallets":      tr.handleListWallets,
-		"get_chain_info":    tr.handleGetChainInfo,
-		"list_chains":       tr.handleListChains,
-		"send_native":       tr.handleSendNative,
-		"send_token":        tr.handleSendToken,
-		"approve_token":     tr.handleApproveToken,
-		"get_receipt":       tr.handleGetReceipt,
-		"wait_receipt":      tr.handleWaitReceipt,
+		pager:       newOutputPager(),
+		solClient:   solana.NewClient(),
+
+		cosmosClient: cosmos.NewClient(),
+	}
+	// A broken chains.yaml leaves the defaults in place rather than
+	// failing every tool.
+	if settings, err := chain.LoadSettings(dataDir); err == nil {
+		tr.chainClient.ApplySettings(settings)
+	}
+	if ttl, ok := loadBalanceCacheTTL(); ok {
+		tr.chainClient.SetBalanceCacheTTL(ttl)
+	}
+	tr.chainClient.SetTokenMetadataStore(lazyTokenStore{tr})
+	tr.txQueue = &tx.Queue{Nonces: tr.chainClient}
+
+	tr.handlers = map[string]ToolHandler{
+		"get_balances":          tr.handleGetBalances,
+		"get_token_balance":     tr.handleGetTokenBalance,
+		"list_wallets":          tr.handleListWallets,
+		"get_chain_info":        tr.handleGetChainInfo,
+		"list_chains":           tr.handleListChains,
+		"get_gas_price":         tr.handleGetGasPrice,
+		"get_quote":             tr.handleGetQuote,
+		"send_native":           tr.handleSendNative,
+		"send_token":            tr.handleSendToken,
+		"approve_token":         tr.handleApproveToken,
+		"swap":                  tr.handleSwap,
+		"get_receipt":           tr.handleGetReceipt,
+		"explain_tx":            tr.handleExplainTx,
+		"trace_tx":              tr.handleTraceTx,
+		"decode_calldata":       tr.handleDecodeCalldata,
+		"query_logs":            tr.handleQueryLogs,
+		"get_account_state":     tr.handleGetAccountState,
+		"read_storage":          tr.handleReadStorage,
+		"audit_approvals":       tr.handleAuditApprovals,
+		"sign_permit":           tr.handleSignPermit,
+		"send_token_gasless":    tr.handleSendTokenGasless,
+		"wait_receipt":          tr.handleWaitReceipt,
+		"get_private_tx_status": tr.handleGetPrivateTxStatus,
+		"request_faucet":        tr.handleRequestFaucet,
+		"set_context":           tr.handleSetContext,
+		"create_watch":          tr.handleCreateWatch,
+		"remember":              tr.handleRemember,
+		"recall":                tr.handleRecall,
+		"search_notes":          tr.handleSearchNotes,
+		"create_dca":            tr.handleCreateDCA,
+		"create_limit_order":    tr.handleCreateLimitOrder,
+		"get_portfolio_diff":    tr.handlePortfolioDiff,
+		"get_pnl":               tr.handleGetPnL,
+
+		"get_solana_balance":     tr.handleGetSolanaBalance,
+		"get_spl_token_balances": tr.handleGetSPLTokenBalances,
+		"list_solana_wallets":    tr.handleListSolanaWallets,
+		"send_sol":               tr.handleSendSOL,
+
+		"get_cosmos_balances": tr.handleGetCosmosBalances,
+		"list_cosmos_wallets": tr.handleListCosmosWallets,
+		"send_cosmos":         tr.handleSendCosmos,
+	}
+	tr.capabilities = make(map[string]ToolCapability, len(tr.handlers))
+	for name := range tr.handlers {
+		tr.capabilities[name] = ToolReadOnly
+		if slices.Contains(signingTools, name) {
+			tr.capabilities[name] = ToolSigning
+		}
 	}
 
 	return tr
@@ -73,20 +164,57 @@ func NewToolRegistryWithDataDir(dataDir string) *ToolRegistry {
 
 // GetTools returns all registered tools
 func (tr *ToolRegistry) GetTools() []llm.Tool {
-	return tr.tools
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+	return slices.Clone(tr.tools)
 }
 
-type toolHandler func(ctx context.Context, input json.RawMessage) (ToolOutput, error)
-
 // ExecuteTool executes a tool by name with the given input.
 // The returned ToolOutput.Text is what should be passed back to the LLM as the tool result.
+// Text longer than the output limit is split into pages; the page argument
+// picks one, served from the first run when it is still cached.
 func (tr *ToolRegistry) ExecuteTool(ctx context.Context, name string, input json.RawMessage) (ToolOutput, error) {
-	handler, ok := tr.handlers[name]
+	handler, ok := tr.handler(name)
 	if !ok {
 		return ToolOutput{}, fmt.Errorf("unknown tool: %s", name)
 	}
 
-	return handler(ctx, input)
+	if s := sessionDefaultsFrom(ctx); s != nil {
+		input = applyDefaults(name, input, s.get())
+	}
+	page, input, key, err := splitPage(name, input)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if page > 1 {
+		text, ok, err := tr.pager.cached(key, page)
+		if ok {
+			return ToolOutput{Text: text}, err
+		}
+		// Re-running a tool that can broadcast to fetch a page could send
+		// a second transaction.
+		if capability, _ := tr.ToolCapability(name); capability == ToolSigning {
+			return ToolOutput{}, fmt.Errorf("page %d of this %s result is no longer available; call it again without page", page, name)
+		}
+	}
+
+	// Inputs are left out of the log; the session log already records them.
+	start := time.Now()
+	out, err := handler(ctx, input)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		slog.Warn("tool failed", "tool", name, "duration", elapsed, "err", err)
+		return out, err
+	}
+	slog.Debug("tool ran", "tool", name, "duration", elapsed)
+
+	if out.Text, err = tr.pager.paginate(key, out.Text, page); err != nil {
+		return ToolOutput{}, err
+	}
+	if page > 1 {
+		out.Blocks = nil
+	}
+	return out, nil
 }
 
 // Close cleans up resources
@@ -124,6 +252,26 @@ func (tr *ToolRegistry) receiptStore() (*ReceiptStore, error) {
 	return tr.receipts, tr.receiptsErr
 }
 
+// lazyTokenStore persists token metadata in the receipt DB, opening it only
+// once a token is actually looked up.
+type lazyTokenStore struct{ tr *ToolRegistry }
+
+func (s lazyTokenStore) GetTokenMetadata(chainName string, token common.Address) (chain.TokenMetadata, bool) {
+	rs, err := s.tr.receiptStore()
+	if err != nil {
+		return chain.TokenMetadata{}, false
+	}
+	return rs.GetTokenMetadata(chainName, token)
+}
+
+func (s lazyTokenStore) PutTokenMetadata(chainName string, meta chain.TokenMetadata) error {
+	rs, err := s.tr.receiptStore()
+	if err != nil {
+		return err
+	}
+	return rs.PutTokenMetadata(chainName, meta)
+}
+
 func parseToolInput[T any](input json.RawMessage, out *T) error {
 	if err := json.Unmarshal(input, out); err != nil {
 		return fmt.Errorf("invalid input: %w", err)
@@ -138,6 +286,21 @@ func requireHexAddress(label, v string) (common.Address, error) {
 	return common.HexToAddress(v), nil
 }
 
+// txURL returns the explorer link for a transaction, or "" if the chain is
+// unknown or has no explorer.
+func (tr *ToolRegistry) txURL(chainName, txHash string) string {
+	cfg, err := tr.chainClient.GetChainConfig(chainName)
+	if err != nil {
+		return ""
+	}
+	return cfg.TxURL(txHash)
+}
+
+// txItem is the "Tx" row of a KV block, linked to the chain's explorer.
+func (tr *ToolRegistry) txItem(chainName, txHash string) KVItem {
+	return KVItem{Key: "Tx", Value: txHash, URL: tr.txURL(chainName, txHash)}
+}
+
 func kvBlock(title string, items ...KVItem) UIBlock {
 	return UIBlock{
 		Kind: UIBlockKV,
@@ -149,8 +312,9 @@ func kvBlock(title string, items ...KVItem) UIBlock {
 }
 
 type getBalancesInput struct {
-	Address string   `json:"address"`
-	Chains  []string `json:"chains"`
+	Address string              `json:"address"`
+	Chains  []string            `json:"chains"`
+	Tokens  map[string][]string `json:"tokens"`
 }
 
 func (tr *ToolRegistry) handleGetBalances(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
@@ -177,19 +341,40 @@ func (tr *ToolRegistry) handleGetBalances(ctx context.Context, input json.RawMes
 		}
 	}
 
+	tokens := make(map[string][]common.Address, len(params.Tokens))
+	for chainName, addrs := range params.Tokens {
+		if !slices.Contains(params.Chains, chainName) {
+			return ToolOutput{}, fmt.Errorf("tokens given for chain %s which is not in chains", chainName)
+		}
+		for _, a := range addrs {
+			tokenAddr, err := tr.resolveToken(chainName, a)
+			if err != nil {
+				return ToolOutput{}, err
+			}
+			tokens[chainName] = append(tokens[chainName], tokenAddr)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
-	var results []string
+	portfolio, err := tr.chainClient.GetPortfolio(ctx, address, params.Chains, tokens)
+	if err != nil {
+		return ToolOutput{}, err
+	}
 
+	var results []string
 	for _, chainName := range params.Chains {
-		balance, err := tr.chainClient.GetNativeBalance(ctx, chainName, address)
-		if err != nil {
-			results = append(results, fmt.Sprintf("%s: error - %v", chainName, err))
+		balance, ok := portfolio.NativeBalances[chainName]
+		if !ok {
+			results = append(results, fmt.Sprintf("%s: error - %s", chainName, portfolio.Errors[chainName]))
 			continue
 		}
 
 		formatted := chain.FormatBalance(balance.Balance, balance.Decimals)
 		results = append(results, fmt.Sprintf("%s: %s %s", chainName, formatted, balance.Symbol))
+		for _, tb := range portfolio.TokenBalances[chainName] {
+			results = append(results, fmt.Sprintf("%s: %s %s", chainName, chain.FormatBalance(tb.Balance, tb.Decimals), tb.Symbol))
+		}
 	}
 
 	text := fmt.Sprintf("Balances for %s:\n%s", params.Address, strings.Join(results, "\n"))
@@ -231,7 +416,7 @@ func (tr *ToolRegistry) handleGetTokenBalance(ctx context.Context, input json.Ra
 	if err != nil {
 		return ToolOutput{}, err
 	}
-	tokenAddr, err := requireHexAddress("token address", params.Token)
+	tokenAddr, err := tr.resolveToken(params.Chain, params.Token)
 	if err != nil {
 		return ToolOutput{}, err
 	}
@@ -245,6 +430,13 @@ func (tr *ToolRegistry) handleGetTokenBalance(ctx context.Context, input json.Ra
 
 	formatted := chain.FormatBalance(balance.Balance, balance.Decimals)
 	text := fmt.Sprintf("Token balance on %s:\n%s %s (%s)", params.Chain, formatted, balance.Symbol, balance.Name)
+	// Unsolicited tokens are how airdrop phishing starts, so anything not
+	// on a token list gets a verdict.
+	if cfg, err := tr.chainClient.GetChainConfig(params.Chain); err == nil {
+		if v, err := tr.assessToken(ctx, params.Chain, cfg, tokenAddr, walletAddr); err == nil && v.Level != tokenrisk.Low {
+			text += "\n" + strings.TrimSuffix(describeTokenRisk("Token", tokenAddr, v).previewText(), "\n")
+		}
+	}
 	block := UIBlock{
 		Kind: UIBlockKV,
 		KV: &UIKV{
@@ -252,7 +444,7 @@ func (tr *ToolRegistry) handleGetTokenBalance(ctx context.Context, input json.Ra
 			Items: []KVItem{
 				{Key: "Chain", Value: params.Chain},
 				{Key: "Wallet", Value: params.Address},
-				{Key: "Token", Value: params.Token},
+				{Key: "Token", Value: tokenAddr.Hex()},
 				{Key: "Balance", Value: formatted + " " + balance.Symbol},
 				{Key: "Name", Value: balance.Name},
 			},
@@ -272,20 +464,32 @@ func (tr *ToolRegistry) handleListWallets(ctx context.Context, input json.RawMes
 		return ToolOutput{Text: "No wallets found. Use 'clifi wallet create' to create one."}, nil
 	}
 
+	def, _ := km.DefaultAccount()
 	var results []string
-	for i, acc := range accounts {
-		results = append(results, fmt.Sprintf("%d. %s", i+1, acc.Address.Hex()))
-	}
-
-	text := fmt.Sprintf("Found %d wallet(s):\n%s", len(accounts), strings.Join(results, "\n"))
 	table := &UITable{
 		Title:   fmt.Sprintf("Wallets (%d)", len(accounts)),
-		Headers: []string{"#", "Address"},
+		Headers: []string{"#", "Label", "Address", "Default"},
 		Rows:    make([][]string, 0, len(accounts)),
 	}
 	for i, acc := range accounts {
-		table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", i+1), acc.Address.Hex()})
+		label := km.Label(acc.Address)
+		isDefault := ""
+		line := fmt.Sprintf("%d. %s", i+1, acc.Address.Hex())
+		if label != "" {
+			line += " (" + label + ")"
+		}
+		if acc.Address == def.Address {
+			isDefault = "yes"
+			line += " [default]"
+		}
+		if provider := km.KMSProvider(acc.Address); provider != "" {
+			line += " [" + provider + "]"
+		}
+		results = append(results, line)
+		table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", i+1), label, acc.Address.Hex(), isDefault})
 	}
+
+	text := fmt.Sprintf("Found %d wallet(s):\n%s", len(accounts), strings.Join(results, "\n"))
 	return ToolOutput{Text: text, Blocks: []UIBlock{{Kind: UIBlockTable, Table: table}}}, nil
 }
 
@@ -335,6 +539,49 @@ Testnet: %v`,
 	return ToolOutput{Text: text, Blocks: []UIBlock{block}}, nil
 }
 
+type getGasPriceInput struct {
+	Chain string `json:"chain"`
+}
+
+func (tr *ToolRegistry) handleGetGasPrice(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params getGasPriceInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		params.Chain = "ethereum"
+	}
+
+	info, err := tr.chainClient.GetGasInfo(ctx, params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	items := []KVItem{
+		{Key: "Chain", Value: params.Chain},
+		{Key: "Block", Value: fmt.Sprintf("%d", info.BlockNumber)},
+	}
+	if info.BaseFee != nil {
+		items = append(items, KVItem{Key: "Base fee", Value: formatFeeRate(info.BaseFee)})
+	}
+	if info.TipCap != nil {
+		items = append(items, KVItem{Key: "Priority fee", Value: formatFeeRate(info.TipCap)})
+	}
+	items = append(items, KVItem{Key: "Gas price", Value: formatFeeRate(info.GasPrice)})
+	if info.BlobBaseFee != nil {
+		items = append(items,
+			KVItem{Key: "Blob base fee", Value: formatFeeRate(info.BlobBaseFee)},
+			KVItem{Key: "Cost per blob", Value: formatFeeRate(info.BlobCost(1))},
+		)
+	}
+
+	var text strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&text, "%s: %s\n", item.Key, item.Value)
+	}
+	return ToolOutput{Text: strings.TrimRight(text.String(), "\n"), Blocks: []UIBlock{kvBlock("Gas price", items...)}}, nil
+}
+
 func (tr *ToolRegistry) handleListChains(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
 	chains := tr.chainClient.ListChains()
 
@@ -385,6 +632,7 @@ type sendNativeInput struct {
 	Password  string `json:"password"`
 	Confirm   bool   `json:"confirm"`
 	Wait      *bool  `json:"wait"`
+	Private   *bool  `json:"private"`
 }
 
 type sendTokenInput struct {
@@ -396,6 +644,7 @@ type sendTokenInput struct {
 	Password     string `json:"password"`
 	Confirm      bool   `json:"confirm"`
 	Wait         *bool  `json:"wait"`
+	Private      *bool  `json:"private"`
 }
 
 type approveTokenInput struct {
@@ -407,6 +656,10 @@ type approveTokenInput struct {
 	Password     string `json:"password"`
 	Confirm      bool   `json:"confirm"`
 	Wait         *bool  `json:"wait"`
+	Private      *bool  `json:"private"`
+	// Permit2 grants the allowance as an expiring Permit2 permit.
+	Permit2        bool `json:"permit2"`
+	ExpirationDays int  `json:"expiration_days"`
 }
 
 func (tr *ToolRegistry) prepareTxFrom(chainName, from string) (common.Address, *chain.ChainConfig, error) {
@@ -418,12 +671,12 @@ func (tr *ToolRegistry) prepareTxFrom(chainName, from string) (common.Address, *
 	if err != nil {
 		return common.Address{}, nil, err
 	}
-	accounts := km.ListAccounts()
-	if len(accounts) == 0 {
-		return common.Address{}, nil, fmt.Errorf("no wallets found in keystore")
+	def, err := km.DefaultAccount()
+	if err != nil {
+		return common.Address{}, nil, err
 	}
 
-	fromAddr := accounts[0].Address
+	fromAddr := def.Address
 	if from != "" {
 		a, err := requireHexAddress("from address", from)
 		if err != nil {
@@ -447,56 +700,15 @@ func (tr *ToolRegistry) handleSendNative(ctx context.Context, input json.RawMess
 	if err := parseToolInput(input, &params); err != nil {
 		return ToolOutput{}, err
 	}
-	toAddr, err := requireHexAddress("recipient address", params.To)
+	prepared, err := tr.prepareNativeSend(ctx, params)
 	if err != nil {
 		return ToolOutput{}, err
 	}
-	if params.AmountETH == "" {
-		return ToolOutput{}, fmt.Errorf("amount_eth is required")
+	summary := prepared.Preview
+	if len(prepared.Warnings) > 0 {
+		summary += "Ask the user to explicitly acknowledge the warning above before setting confirm=true.\n"
 	}
 
-	wei, err := parseEthToWei(params.AmountETH)
-	if err != nil {
-		return ToolOutput{}, fmt.Errorf("invalid amount_eth: %w", err)
-	}
-	if wei.Sign() <= 0 {
-		return ToolOutput{}, fmt.Errorf("amount_eth must be greater than zero")
-	}
-
-	fromAddr, cfg, err := tr.prepareTxFrom(params.Chain, params.From)
-	if err != nil {
-		return ToolOutput{}, err
-	}
-
-	intent := tx.Intent{
-		Chain:    params.Chain,
-		From:     fromAddr,
-		To:       toAddr,
-		ValueWei: wei,
-	}
-	if err := tx.Validate(intent, loadPolicy()); err != nil {
-		return ToolOutput{}, err
-	}
-
-	previewCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
-
-	unsigned, fees, err := tx.BuildUnsignedTx(previewCtx, tr.chainClient, intent)
-	if err != nil {
-		return ToolOutput{}, err
-	}
-
-	summary := fmt.Sprintf("Preview:\n- Chain: %s\n- From: %s\n- To: %s\n- Amount: %s ETH\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n- Estimated total: %s ETH\n",
-		params.Chain,
-		fromAddr.Hex(),
-		params.To,
-		params.AmountETH,
-		fees.GasLimit,
-		weiToGwei(fees.MaxFeePerGas),
-		weiToGwei(fees.MaxPriorityFee),
-		weiToEth(fees.EstimatedCostWei),
-	)
-
 	if !params.Confirm {
 		if params.Password == "" {
 			return ToolOutput{Text: summary + "\nSet confirm=true and provide password to sign and broadcast."}, nil
@@ -504,29 +716,20 @@ func (tr *ToolRegistry) handleSendNative(ctx context.Context, input json.RawMess
 		return ToolOutput{Text: summary + "\nSet confirm=true to sign and broadcast."}, nil
 	}
 
-	if params.Password == "" {
-		return ToolOutput{}, fmt.Errorf("password required to sign")
-	}
-
-	signed, err := tr.signAndSendTx(ctx, params.Chain, fromAddr, params.Password, unsigned, cfg.ChainID)
+	sent, err := tr.SendPrepared(ctx, prepared, params.Password, params.Wait == nil || *params.Wait)
 	if err != nil {
 		return ToolOutput{}, err
 	}
 
-	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, signed.Hash().Hex())
-
-	if line, _ := tr.maybeWaitAndPersistReceipt(ctx, params.Chain, signed.Hash(), params.Wait); line != "" {
-		result += "\n" + line
-	}
-
 	return ToolOutput{
-		Text: result,
+		Text:      sentText(summary, sent),
+		Confirmed: sent.Confirmed,
 		Blocks: []UIBlock{kvBlock("Native send",
 			KVItem{Key: "Chain", Value: params.Chain},
-			KVItem{Key: "From", Value: fromAddr.Hex()},
+			KVItem{Key: "From", Value: prepared.From.Hex()},
 			KVItem{Key: "To", Value: params.To},
 			KVItem{Key: "Amount", Value: params.AmountETH + " ETH"},
-			KVItem{Key: "Tx", Value: signed.Hash().Hex()},
+			tr.txItem(params.Chain, sent.Hash.Hex()),
 		)},
 	}, nil
 }
@@ -539,93 +742,49 @@ func (tr *ToolRegistry) handleSendToken(ctx context.Context, input json.RawMessa
 	if err := parseToolInput(input, &params); err != nil {
 		return ToolOutput{}, err
 	}
-	toAddr, err := requireHexAddress("recipient address", params.To)
+	prepared, err := tr.prepareTokenSend(ctx, params)
 	if err != nil {
 		return ToolOutput{}, err
 	}
-	tokenAddr, err := requireHexAddress("token address", params.Token)
-	if err != nil {
-		return ToolOutput{}, err
-	}
-	if params.AmountTokens == "" {
-		return ToolOutput{}, fmt.Errorf("amount_tokens is required")
-	}
-
-	fromAddr, cfg, err := tr.prepareTxFrom(params.Chain, params.From)
-	if err != nil {
-		return ToolOutput{}, err
+	summary := prepared.Preview
+	if len(prepared.Warnings) > 0 {
+		summary += "Ask the user to explicitly acknowledge the warning above before setting confirm=true.\n"
 	}
 
-	decimals, symbol := uint8(18), "TOKEN"
-	decimals, symbol = queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr, decimals, symbol)
-
-	amountWei, err := decimalToWei(params.AmountTokens, int(decimals))
-	if err != nil {
-		return ToolOutput{}, fmt.Errorf("invalid amount_tokens: %w", err)
-	}
-	if amountWei.Sign() <= 0 {
-		return ToolOutput{}, fmt.Errorf("amount_tokens must be greater than zero")
-	}
-
-	data, err := buildERC20TransferData(toAddr, amountWei)
-	if err != nil {
-		return ToolOutput{}, err
-	}
-
-	intent := tx.Intent{
-		Chain:    params.Chain,
-		From:     fromAddr,
-		To:       tokenAddr,
-		ValueWei: big.NewInt(0),
-		Data:     data,
-	}
-	if err := tx.Validate(intent, loadPolicy()); err != nil {
-		return ToolOutput{}, err
-	}
-
-	unsigned, fees, err := tx.BuildUnsignedTx(ctx, tr.chainClient, intent)
-	if err != nil {
-		return ToolOutput{}, err
-	}
-
-	summary := fmt.Sprintf("Preview ERC20 transfer:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- To: %s\n- Amount: %s %s\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n- Estimated total (gas only): %s ETH\n",
-		params.Token, symbol, params.Chain, fromAddr.Hex(), params.To, params.AmountTokens, symbol,
-		fees.GasLimit,
-		weiToGwei(fees.MaxFeePerGas),
-		weiToGwei(fees.MaxPriorityFee),
-		weiToEth(fees.EstimatedCostWei),
-	)
-
 	if !params.Confirm {
 		return ToolOutput{Text: summary + "\nSet confirm=true and provide password to broadcast."}, nil
 	}
-	if params.Password == "" {
-		return ToolOutput{}, fmt.Errorf("password required to sign")
-	}
 
-	signed, err := tr.signAndSendTx(ctx, params.Chain, fromAddr, params.Password, unsigned, cfg.ChainID)
+	sent, err := tr.SendPrepared(ctx, prepared, params.Password, params.Wait == nil || *params.Wait)
 	if err != nil {
 		return ToolOutput{}, err
 	}
-
-	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, signed.Hash().Hex())
-
-	if line, _ := tr.maybeWaitAndPersistReceipt(ctx, params.Chain, signed.Hash(), params.Wait); line != "" {
-		result += "\n" + line
-	}
 	return ToolOutput{
-		Text: result,
+		Text:      sentText(summary, sent),
+		Confirmed: sent.Confirmed,
 		Blocks: []UIBlock{kvBlock("ERC20 send",
 			KVItem{Key: "Chain", Value: params.Chain},
-			KVItem{Key: "From", Value: fromAddr.Hex()},
+			KVItem{Key: "From", Value: prepared.From.Hex()},
 			KVItem{Key: "To", Value: params.To},
-			KVItem{Key: "Token", Value: params.Token},
-			KVItem{Key: "Amount", Value: params.AmountTokens + " " + symbol},
-			KVItem{Key: "Tx", Value: signed.Hash().Hex()},
+			KVItem{Key: "Token", Value: prepared.Token.Hex()},
+			KVItem{Key: "Amount", Value: params.AmountTokens + " " + prepared.Symbol},
+			tr.txItem(params.Chain, sent.Hash.Hex()),
 		)},
 	}, nil
 }
 
+// sentText appends the broadcast result to a send preview.
+func sentText(summary string, sent *SentTx) string {
+	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, sent.Hash.Hex())
+	if sent.ExplorerURL != "" {
+		result += "\nExplorer: " + sent.ExplorerURL
+	}
+	if sent.Receipt != "" {
+		result += "\n" + sent.Receipt
+	}
+	return result
+}
+
 func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
 	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
 	defer cancel()
@@ -638,7 +797,7 @@ func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMe
 	if err != nil {
 		return ToolOutput{}, err
 	}
-	tokenAddr, err := requireHexAddress("token address", params.Token)
+	tokenAddr, err := tr.resolveToken(params.Chain, params.Token)
 	if err != nil {
 		return ToolOutput{}, err
 	}
@@ -650,8 +809,10 @@ func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMe
 	if err != nil {
 		return ToolOutput{}, err
 	}
-	decimals, symbol := uint8(18), "TOKEN"
-	decimals, symbol = queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr, decimals, symbol)
+	decimals, symbol, err := queryTokenMeta(ctx, tr.chainClient, params.Chain, tokenAddr)
+	if err != nil {
+		return ToolOutput{}, err
+	}
 
 	amountWei, err := decimalToWei(params.AmountTokens, int(decimals))
 	if err != nil {
@@ -660,6 +821,9 @@ func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMe
 	if amountWei.Sign() <= 0 {
 		return ToolOutput{}, fmt.Errorf("amount_tokens must be greater than zero")
 	}
+	if params.Permit2 {
+		return tr.approveViaPermit2(ctx, params, fromAddr, cfg, tokenAddr, spenderAddr, amountWei, symbol)
+	}
 
 	data, err := buildERC20ApproveData(spenderAddr, amountWei)
 	if err != nil {
@@ -673,7 +837,16 @@ func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMe
 		ValueWei: big.NewInt(0),
 		Data:     data,
 	}
-	if err := tx.Validate(intent, loadPolicy()); err != nil {
+	policy := loadPolicy()
+	if err := tx.Validate(intent, policy); err != nil {
+		return ToolOutput{}, err
+	}
+	screenCheck, err := tr.screenRecipient(ctx, "Spender", spenderAddr.Hex(), policy)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	relay, err := privateRelayFor(params.Chain, cfg, params.Private)
+	if err != nil {
 		return ToolOutput{}, err
 	}
 
@@ -682,40 +855,52 @@ func (tr *ToolRegistry) handleApproveToken(ctx context.Context, input json.RawMe
 		return ToolOutput{}, err
 	}
 
-	summary := fmt.Sprintf("Preview ERC20 approval:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- Spender: %s\n- Allowance: %s %s\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n- Estimated total (gas only): %s ETH\n",
-		params.Token, symbol, params.Chain, fromAddr.Hex(), params.Spender, params.AmountTokens, symbol,
+	summary := fmt.Sprintf("Preview ERC20 approval:\n- Token: %s (%s)\n- Chain: %s\n- From: %s\n- Spender: %s\n- Allowance: %s %s\n- Gas limit: %d\n- Max fee: %s gwei\n- Max priority fee: %s gwei\n%s- Estimated total (gas only): %s ETH\n",
+		tokenAddr.Hex(), symbol, params.Chain, fromAddr.Hex(), params.Spender, params.AmountTokens, symbol,
 		fees.GasLimit,
 		weiToGwei(fees.MaxFeePerGas),
 		weiToGwei(fees.MaxPriorityFee),
+		l1FeeLine(fees),
 		weiToEth(fees.EstimatedCostWei),
 	)
+	summary += submissionLine(relay)
+	spenderCheck := tr.checkContract(ctx, params.Chain, cfg, "Spender", spenderAddr)
+	tokenCheck := tr.tokenRiskCheck(ctx, params.Chain, cfg, "Token", tokenAddr, fromAddr)
+	summary += spenderCheck.and(screenCheck).and(tokenCheck).previewText()
 
 	if !params.Confirm {
 		return ToolOutput{Text: summary + "\nSet confirm=true and provide password to broadcast."}, nil
 	}
-	if params.Password == "" {
-		return ToolOutput{}, fmt.Errorf("password required to sign")
+	if err := tr.checkSigner(params.Chain, fromAddr, params.Password); err != nil {
+		return ToolOutput{}, err
 	}
 
-	signed, err := tr.signAndSendTx(ctx, params.Chain, fromAddr, params.Password, unsigned, cfg.ChainID)
+	signed, err := tr.signAndSendTx(ctx, params.Chain, fromAddr, params.Password, unsigned, cfg.ChainID, relay)
 	if err != nil {
 		return ToolOutput{}, err
 	}
 
 	result := fmt.Sprintf("%s\n\nBroadcasted tx: %s", summary, signed.Hash().Hex())
+	if url := tr.txURL(params.Chain, signed.Hash().Hex()); url != "" {
+		result += "\nExplorer: " + url
+	}
 
-	if line, _ := tr.maybeWaitAndPersistReceipt(ctx, params.Chain, signed.Hash(), params.Wait); line != "" {
+	line, confirmed := tr.maybeWaitAndPersistReceipt(ctx, params.Chain, signed.Hash(), params.Wait)
+	if line != "" {
+		result += "\n" + line
+	} else if line = privateStatusLine(ctx, relay, signed.Hash()); line != "" {
 		result += "\n" + line
 	}
 	return ToolOutput{
-		Text: result,
+		Text:      result,
+		Confirmed: confirmed,
 		Blocks: []UIBlock{kvBlock("ERC20 approval",
 			KVItem{Key: "Chain", Value: params.Chain},
 			KVItem{Key: "From", Value: fromAddr.Hex()},
 			KVItem{Key: "Spender", Value: params.Spender},
-			KVItem{Key: "Token", Value: params.Token},
+			KVItem{Key: "Token", Value: tokenAddr.Hex()},
 			KVItem{Key: "Allowance", Value: params.AmountTokens + " " + symbol},
-			KVItem{Key: "Tx", Value: signed.Hash().Hex()},
+			tr.txItem(params.Chain, signed.Hash().Hex()),
 		)},
 	}, nil
 }
@@ -753,9 +938,12 @@ func (tr *ToolRegistry) handleGetReceipt(ctx context.Context, input json.RawMess
 			text := fmt.Sprintf("Receipt (cached):\n- Chain: %s\n- Tx: %s\n- Status: %d\n- Gas used: %d\n",
 				stored.Chain, stored.TxHash, stored.Status, stored.GasUsed,
 			)
+			if url := tr.txURL(stored.Chain, stored.TxHash); url != "" {
+				text += "- Explorer: " + url + "\n"
+			}
 			block := UIBlock{Kind: UIBlockKV, KV: &UIKV{Title: "Receipt (cached)", Items: []KVItem{
 				{Key: "Chain", Value: stored.Chain},
-				{Key: "Tx", Value: stored.TxHash},
+				tr.txItem(stored.Chain, stored.TxHash),
 				{Key: "Status", Value: fmt.Sprintf("%d", stored.Status)},
 				{Key: "Gas used", Value: fmt.Sprintf("%d", stored.GasUsed)},
 			}}}
@@ -768,16 +956,17 @@ func (tr *ToolRegistry) handleGetReceipt(ctx context.Context, input json.RawMess
 		return ToolOutput{}, fmt.Errorf("receipt not found (tx may be pending): %w", err)
 	}
 
-	if rs, err := tr.receiptStore(); err == nil {
-		_ = rs.Upsert(params.Chain, receipt)
-	}
+	tr.persistReceipt(ctx, params.Chain, receipt)
 
 	text := fmt.Sprintf("Receipt:\n- Chain: %s\n- Tx: %s\n- Status: %d\n- Gas used: %d\n",
 		params.Chain, params.TxHash, receipt.Status, receipt.GasUsed,
 	)
+	if url := tr.txURL(params.Chain, params.TxHash); url != "" {
+		text += "- Explorer: " + url + "\n"
+	}
 	block := UIBlock{Kind: UIBlockKV, KV: &UIKV{Title: "Receipt", Items: []KVItem{
 		{Key: "Chain", Value: params.Chain},
-		{Key: "Tx", Value: params.TxHash},
+		tr.txItem(params.Chain, params.TxHash),
 		{Key: "Status", Value: fmt.Sprintf("%d", receipt.Status)},
 		{Key: "Gas used", Value: fmt.Sprintf("%d", receipt.GasUsed)},
 	}}}
@@ -827,20 +1016,21 @@ func (tr *ToolRegistry) handleWaitReceipt(ctx context.Context, input json.RawMes
 	if err != nil {
 		return ToolOutput{}, fmt.Errorf("wait mined: %w", err)
 	}
-	if rs, err := tr.receiptStore(); err == nil {
-		_ = rs.Upsert(params.Chain, receipt)
-	}
+	tr.persistReceipt(ctx, params.Chain, receipt)
 
 	text := fmt.Sprintf("Receipt:\n- Chain: %s\n- Tx: %s\n- Status: %d\n- Gas used: %d\n",
 		params.Chain, params.TxHash, receipt.Status, receipt.GasUsed,
 	)
+	if url := tr.txURL(params.Chain, params.TxHash); url != "" {
+		text += "- Explorer: " + url + "\n"
+	}
 	block := UIBlock{Kind: UIBlockKV, KV: &UIKV{Title: "Receipt", Items: []KVItem{
 		{Key: "Chain", Value: params.Chain},
-		{Key: "Tx", Value: params.TxHash},
+		tr.txItem(params.Chain, params.TxHash),
 		{Key: "Status", Value: fmt.Sprintf("%d", receipt.Status)},
 		{Key: "Gas used", Value: fmt.Sprintf("%d", receipt.GasUsed)},
 	}}}
-	return ToolOutput{Text: text, Blocks: []UIBlock{block}}, nil
+	return ToolOutput{Text: text, Blocks: []UIBlock{block}, Confirmed: confirmationOf(params.Chain, receipt)}, nil
 }
 
 func parseTxHash(v string) (common.Hash, error) {
@@ -879,6 +1069,27 @@ func decimalToWei(amount string, decimals int) (*big.Int, error) {
 	return weiRat.Num(), nil
 }
 
+// l1FeeLine renders the rollup L1 data fee for a preview, or "" when the
+// chain has none.
+func l1FeeLine(fees tx.SuggestedFees) string {
+	if fees.L1FeeWei == nil {
+		return ""
+	}
+	if fees.L1FeeIncluded {
+		return fmt.Sprintf("- L1 data fee: %s ETH (included in gas limit)\n", weiToEth(fees.L1FeeWei))
+	}
+	return fmt.Sprintf("- L1 data fee: %s ETH\n", weiToEth(fees.L1FeeWei))
+}
+
+// formatFeeRate renders a wei amount in gwei, falling back to wei for values
+// too small to show in gwei (blob base fees often sit at 1 wei).
+func formatFeeRate(v *big.Int) string {
+	if v != nil && v.Cmp(big.NewInt(10_000_000)) < 0 {
+		return v.String() + " wei"
+	}
+	return weiToGwei(v) + " gwei"
+}
+
 func weiToGwei(v *big.Int) string {
 	if v == nil {
 		return "0"
@@ -895,30 +1106,18 @@ func weiToEth(v *big.Int) string {
 	return r.FloatString(6)
 }
 
-// Query token decimals/symbol via eth_call; return defaults on failure.
-func queryTokenMeta(ctx context.Context, cc *chain.Client, chainName string, token common.Address, defaultDecimals uint8, defaultSymbol string) (uint8, string) {
-	decimals := defaultDecimals
-	symbol := defaultSymbol
-
-	// decimals()
-	decimalsData := common.FromHex("0x313ce567")
-	if out, err := cc.CallContract(ctx, chainName, ethereum.CallMsg{To: &token, Data: decimalsData}); err == nil && len(out) >= 32 {
-		decimals = uint8(out[len(out)-1])
+// Query token decimals/symbol in one batched request. A token that doesn't
+// implement symbol() gets a generic label; RPC failures are returned because
+// the decimals drive how transfer amounts are scaled.
+func queryTokenMeta(ctx context.Context, cc *chain.Client, chainName string, token common.Address) (uint8, string, error) {
+	symbol, decimals, err := cc.GetTokenSymbolDecimals(ctx, chainName, token)
+	if err != nil {
+		return 0, "", err
 	}
-	// symbol()
-	symbolData := common.FromHex("0x95d89b41")
-	if out, err := cc.CallContract(ctx, chainName, ethereum.CallMsg{To: &token, Data: symbolData}); err == nil && len(out) >= 64 {
-		// Trim right zeros
-		out = bytes.TrimRight(out, "\x00")
-		if len(out) > 32 {
-			out = out[len(out)-32:]
-		}
-		s := string(bytes.Trim(out, "\x00"))
-		if s != "" {
-			symbol = s
-		}
+	if symbol == "" {
+		symbol = "TOKEN"
 	}
-	return decimals, symbol
+	return decimals, symbol, nil
 }
 
 // ERC20 transfer(address,uint256)
@@ -932,6 +1131,15 @@ func buildERC20TransferData(to common.Address, amount *big.Int) ([]byte, error)
 	return data, nil
 }
 
+// ERC20 transferFrom(address,address,uint256)
+func buildERC20TransferFromData(from, to common.Address, amount *big.Int) []byte {
+	data := make([]byte, 0, 4+3*32)
+	data = append(data, common.FromHex("0x23b872dd")...)
+	data = append(data, common.LeftPadBytes(from.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
+
 // ERC20 approve(address,uint256)
 func buildERC20ApproveData(spender common.Address, amount *big.Int) ([]byte, error) {
 	method := common.FromHex("0x095ea7b3")
@@ -943,6 +1151,33 @@ func buildERC20ApproveData(spender common.Address, amount *big.Int) ([]byte, err
 	return data, nil
 }
 
+// loadBalanceCacheTTL reads CLIFI_BALANCE_CACHE_TTL (a Go duration such as
+// "30s"; "0" disables balance caching). ok is false when unset or invalid.
+func loadBalanceCacheTTL() (time.Duration, bool) {
+	v := os.Getenv("CLIFI_BALANCE_CACHE_TTL")
+	if v == "" {
+		return 0, false
+	}
+	if v == "0" {
+		return 0, true
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		return 0, false
+	}
+	return ttl, true
+}
+
+// ConnectedChains returns the EVM chains with an open RPC connection.
+func (tr *ToolRegistry) ConnectedChains() []string {
+	return tr.chainClient.ConnectedChains()
+}
+
+// ThrottleStats reports RPC rate limiter waits per host.
+func (tr *ToolRegistry) ThrottleStats() map[string]chain.ThrottleStats {
+	return tr.chainClient.ThrottleStats()
+}
+
 func loadPolicy() tx.Policy {
 	p := tx.Policy{}
 	if maxStr := os.Getenv("CLIFI_MAX_TX_ETH"); maxStr != "" {
@@ -955,6 +1190,8 @@ func loadPolicy() tx.Policy {
 			part = strings.TrimSpace(part)
 			if common.IsHexAddress(part) {
 				p.AllowTo = append(p.AllowTo, common.HexToAddress(part))
+			} else if isBech32(part) {
+				p.AllowToBech32 = append(p.AllowToBech32, part)
 			}
 		}
 	}
@@ -963,8 +1200,69 @@ func loadPolicy() tx.Policy {
 			part = strings.TrimSpace(part)
 			if common.IsHexAddress(part) {
 				p.DenyTo = append(p.DenyTo, common.HexToAddress(part))
+			} else if isBech32(part) {
+				p.DenyToBech32 = append(p.DenyToBech32, part)
+			}
+		}
+	}
+	// CLIFI_MAX_TX_DENOM caps Cosmos sends per base denom, e.g.
+	// "uatom=5000000,uosmo=20000000".
+	if limits := os.Getenv("CLIFI_MAX_TX_DENOM"); limits != "" {
+		for _, part := range strings.Split(limits, ",") {
+			denom, amount, ok := strings.Cut(strings.TrimSpace(part), "=")
+			limit, valid := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+			if !ok || !valid || limit.Sign() < 0 {
+				continue
+			}
+			if p.MaxPerTxDenom == nil {
+				p.MaxPerTxDenom = make(map[string]*big.Int)
 			}
+			p.MaxPerTxDenom[strings.TrimSpace(denom)] = limit
 		}
 	}
+	if v := os.Getenv("CLIFI_MAX_SLIPPAGE_BPS"); v != "" {
+		if bps, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32); err == nil && bps < 10_000 {
+			p.MaxSlippageBps = uint32(bps)
+		}
+	}
+	// CLIFI_SCREEN_ACTION is block (the default) or warn; anything else
+	// keeps blocking.
+	p.ScreenWarnOnly = strings.EqualFold(strings.TrimSpace(os.Getenv("CLIFI_SCREEN_ACTION")), "warn")
 	return p
 }
+
+// swapLimits merges per-call swap settings with the configured defaults
+// (CLIFI_SLIPPAGE_BPS, CLIFI_SWAP_DEADLINE as a Go duration such as "10m")
+// and checks them against the policy's slippage ceiling.
+func swapLimits(slippageBps *uint32, deadlineSec *int64, policy tx.Policy) (tx.SwapLimits, error) {
+	limits := tx.SwapLimits{SlippageBps: tx.DefaultSlippageBps, Deadline: tx.DefaultSwapDeadline}
+	if v := os.Getenv("CLIFI_SLIPPAGE_BPS"); v != "" {
+		bps, err := strconv.ParseUint(strings.TrimSpace(v), 10, 32)
+		if err != nil {
+			return tx.SwapLimits{}, fmt.Errorf("invalid CLIFI_SLIPPAGE_BPS: %w", err)
+		}
+		limits.SlippageBps = uint32(bps)
+	}
+	if v := os.Getenv("CLIFI_SWAP_DEADLINE"); v != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return tx.SwapLimits{}, fmt.Errorf("invalid CLIFI_SWAP_DEADLINE: %w", err)
+		}
+		limits.Deadline = d
+	}
+	if slippageBps != nil {
+		limits.SlippageBps = *slippageBps
+	}
+	if deadlineSec != nil {
+		limits.Deadline = time.Duration(*deadlineSec) * time.Second
+	}
+	if err := tx.ValidateSwapLimits(limits, policy); err != nil {
+		return tx.SwapLimits{}, err
+	}
+	return limits, nil
+}
+
+func isBech32(s string) bool {
+	_, _, err := cosmos.DecodeBech32(s)
+	return err == nil
+}
@@ -1,11 +1,18 @@
 package agent
 
 import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/cosmos"
 	"github.com/yolodolo42/clifi/internal/tx"
 )
 
@@ -22,6 +29,50 @@ func TestLoadPolicy_ParsesEnv(t *testing.T) {
 	assert.Equal(t, common.HexToAddress("0x3333333333333333333333333333333333333333"), p.DenyTo[0])
 }
 
+func TestLoadPolicy_ParsesCosmosEnv(t *testing.T) {
+	allowed, err := cosmos.EncodeBech32("cosmos", make([]byte, 20))
+	require.NoError(t, err)
+	t.Setenv("CLIFI_ALLOW_TO", "0x1111111111111111111111111111111111111111, "+allowed+", not-an-address")
+	t.Setenv("CLIFI_DENY_TO", "")
+	t.Setenv("CLIFI_MAX_TX_DENOM", "uatom=5000000, uosmo=bad")
+
+	p := loadPolicy()
+
+	assert.Len(t, p.AllowTo, 1)
+	assert.Equal(t, []string{allowed}, p.AllowToBech32)
+	require.Contains(t, p.MaxPerTxDenom, "uatom")
+	assert.Equal(t, "5000000", p.MaxPerTxDenom["uatom"].String())
+	assert.NotContains(t, p.MaxPerTxDenom, "uosmo")
+}
+
+func TestLoadBalanceCacheTTL(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv("CLIFI_BALANCE_CACHE_TTL", "")
+		_, ok := loadBalanceCacheTTL()
+		assert.False(t, ok)
+	})
+
+	t.Run("duration", func(t *testing.T) {
+		t.Setenv("CLIFI_BALANCE_CACHE_TTL", "45s")
+		ttl, ok := loadBalanceCacheTTL()
+		assert.True(t, ok)
+		assert.Equal(t, 45*time.Second, ttl)
+	})
+
+	t.Run("zero disables", func(t *testing.T) {
+		t.Setenv("CLIFI_BALANCE_CACHE_TTL", "0")
+		ttl, ok := loadBalanceCacheTTL()
+		assert.True(t, ok)
+		assert.Zero(t, ttl)
+	})
+
+	t.Run("invalid is ignored", func(t *testing.T) {
+		t.Setenv("CLIFI_BALANCE_CACHE_TTL", "soon")
+		_, ok := loadBalanceCacheTTL()
+		assert.False(t, ok)
+	})
+}
+
 func TestValidatePolicy(t *testing.T) {
 	intent := tx.Intent{
 		Chain:    "ethereum",
@@ -40,6 +91,21 @@ func TestValidatePolicy(t *testing.T) {
 	assert.Error(t, tx.Validate(intent, p))
 }
 
+func TestValidateCosmosPolicy(t *testing.T) {
+	to, err := cosmos.EncodeBech32("cosmos", make([]byte, 20))
+	require.NoError(t, err)
+	intent := tx.CosmosIntent{Chain: "cosmoshub", To: to, Denom: "uatom", Amount: big.NewInt(10)}
+
+	assert.NoError(t, tx.ValidateCosmos(intent, tx.Policy{}))
+	assert.Error(t, tx.ValidateCosmos(intent, tx.Policy{DenyToBech32: []string{to}}))
+	assert.NoError(t, tx.ValidateCosmos(intent, tx.Policy{AllowToBech32: []string{to}}))
+	assert.Error(t, tx.ValidateCosmos(intent, tx.Policy{MaxPerTxDenom: map[string]*big.Int{"uatom": big.NewInt(9)}}))
+
+	// An EVM-only allowlist must not leave Cosmos sends unrestricted.
+	evmOnly := tx.Policy{AllowTo: []common.Address{common.HexToAddress("0x1111111111111111111111111111111111111111")}}
+	assert.Error(t, tx.ValidateCosmos(intent, evmOnly))
+}
+
 func TestDecimalToWei(t *testing.T) {
 	v, err := decimalToWei("1.5", 6)
 	require.NoError(t, err)
@@ -48,3 +114,161 @@ func TestDecimalToWei(t *testing.T) {
 	_, err = decimalToWei("notnum", 18)
 	assert.Error(t, err)
 }
+
+func TestPrivateRelayFor(t *testing.T) {
+	chains := chain.DefaultChains()
+	yes, no := true, false
+
+	t.Run("public by default", func(t *testing.T) {
+		t.Setenv("CLIFI_PRIVATE_TX", "")
+		relay, err := privateRelayFor("ethereum", chains["ethereum"], nil)
+		require.NoError(t, err)
+		assert.Nil(t, relay)
+	})
+
+	t.Run("explicit private uses flashbots", func(t *testing.T) {
+		t.Setenv("CLIFI_PRIVATE_TX", "")
+		relay, err := privateRelayFor("ethereum", chains["ethereum"], &yes)
+		require.NoError(t, err)
+		require.NotNil(t, relay)
+		assert.Equal(t, "Flashbots Protect", relay.Name)
+
+		relay, err = privateRelayFor("sepolia", chains["sepolia"], &yes)
+		require.NoError(t, err)
+		assert.Equal(t, "Flashbots Protect (Sepolia)", relay.Name)
+
+		_, err = privateRelayFor("base", chains["base"], &yes)
+		assert.Error(t, err)
+	})
+
+	t.Run("configured relay applies only where supported", func(t *testing.T) {
+		t.Setenv("CLIFI_PRIVATE_TX", "mevblocker")
+		relay, err := privateRelayFor("ethereum", chains["ethereum"], nil)
+		require.NoError(t, err)
+		assert.Equal(t, "MEV Blocker", relay.Name)
+
+		relay, err = privateRelayFor("base", chains["base"], nil)
+		require.NoError(t, err)
+		assert.Nil(t, relay)
+
+		relay, err = privateRelayFor("ethereum", chains["ethereum"], &no)
+		require.NoError(t, err)
+		assert.Nil(t, relay)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Setenv("CLIFI_PRIVATE_TX", "bogus")
+		_, err := privateRelayFor("ethereum", chains["ethereum"], nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestSwapLimits(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+		t.Setenv("CLIFI_SWAP_DEADLINE", "")
+		limits, err := swapLimits(nil, nil, tx.Policy{})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(tx.DefaultSlippageBps), limits.SlippageBps)
+		assert.Equal(t, tx.DefaultSwapDeadline, limits.Deadline)
+	})
+
+	t.Run("config then per-call overrides", func(t *testing.T) {
+		t.Setenv("CLIFI_SLIPPAGE_BPS", "100")
+		t.Setenv("CLIFI_SWAP_DEADLINE", "5m")
+		limits, err := swapLimits(nil, nil, tx.Policy{})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(100), limits.SlippageBps)
+		assert.Equal(t, 5*time.Minute, limits.Deadline)
+
+		bps, deadline := uint32(25), int64(60)
+		limits, err = swapLimits(&bps, &deadline, tx.Policy{})
+		require.NoError(t, err)
+		assert.Equal(t, uint32(25), limits.SlippageBps)
+		assert.Equal(t, time.Minute, limits.Deadline)
+	})
+
+	t.Run("policy ceiling", func(t *testing.T) {
+		t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+		t.Setenv("CLIFI_SWAP_DEADLINE", "")
+		bps := uint32(500)
+		_, err := swapLimits(&bps, nil, tx.Policy{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds policy maximum 3.00%")
+
+		t.Setenv("CLIFI_MAX_SLIPPAGE_BPS", "1000")
+		_, err = swapLimits(&bps, nil, loadPolicy())
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Setenv("CLIFI_SLIPPAGE_BPS", "lots")
+		_, err := swapLimits(nil, nil, tx.Policy{})
+		assert.Error(t, err)
+	})
+}
+
+func TestMinReceived(t *testing.T) {
+	assert.Equal(t, "995000", tx.MinReceived(big.NewInt(1_000_000), 50).String())
+	assert.Equal(t, "9", tx.MinReceived(big.NewInt(10), 50).String(), "rounds down")
+	assert.Equal(t, "0", tx.MinReceived(big.NewInt(10), 20_000).String())
+	assert.Equal(t, "0.50%", tx.FormatBps(50))
+
+	limits := tx.SwapLimits{Deadline: time.Minute}
+	assert.Equal(t, int64(1060), limits.DeadlineAt(time.Unix(1000, 0)).Int64())
+}
+
+func TestScreenRecipient(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "local.txt")
+	require.NoError(t, os.WriteFile(list, []byte("0x00000000000000000000000000000000000000aa drainer Inferno\n"), 0600))
+	t.Setenv("CLIFI_SCREEN_LISTS", list)
+	t.Setenv("CLIFI_SCREEN_API_KEY", "")
+	tr := NewToolRegistryWithDataDir("")
+	defer tr.Close()
+	ctx := context.Background()
+	flagged := "0x00000000000000000000000000000000000000AA"
+
+	_, err := tr.screenRecipient(ctx, "Recipient", flagged, tx.Policy{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "drainer (Inferno) per local.txt")
+
+	check, err := tr.screenRecipient(ctx, "Spender", flagged, tx.Policy{ScreenWarnOnly: true})
+	require.NoError(t, err)
+	assert.True(t, check.Warn)
+	assert.Contains(t, check.previewText(), "WARNING: Spender "+flagged+" is flagged")
+
+	check, err = tr.screenRecipient(ctx, "Recipient", "0x00000000000000000000000000000000000000bb", tx.Policy{})
+	require.NoError(t, err)
+	assert.False(t, check.Warn)
+	assert.Equal(t, []string{"- Recipient screening: no match (local.txt)"}, check.Lines)
+
+	t.Setenv("CLIFI_SCREEN_ACTION", "warn")
+	assert.True(t, loadPolicy().ScreenWarnOnly)
+	assert.Contains(t, loadPolicy().Summary(), "flagged recipients warn only")
+}
+
+func TestTokenRiskCheck_Listed(t *testing.T) {
+	usdc := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	weth := common.HexToAddress("0x4200000000000000000000000000000000000006")
+	list := filepath.Join(t.TempDir(), "scams.txt")
+	require.NoError(t, os.WriteFile(list, []byte(weth.Hex()+" scam test entry\n"), 0600))
+	t.Setenv("CLIFI_SCREEN_LISTS", list)
+	t.Setenv("CLIFI_SCREEN_API_KEY", "")
+	t.Setenv("CLIFI_TOKEN_LISTS", "")
+	tr := NewToolRegistryWithDataDir("")
+	defer tr.Close()
+	cfg, err := tr.chainClient.GetChainConfig("base")
+	require.NoError(t, err)
+	ctx := context.Background()
+	holder := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	// Listed tokens are rated without any RPC.
+	check := tr.tokenRiskCheck(ctx, "base", cfg, "Token", usdc, holder)
+	assert.False(t, check.Warn)
+	assert.Equal(t, []string{"- Token risk: low risk: listed on clifi default"}, check.Lines)
+
+	// Being listed does not outweigh a scam list.
+	check = tr.tokenRiskCheck(ctx, "base", cfg, "Buy token", weth, holder)
+	assert.True(t, check.Warn)
+	assert.Contains(t, check.previewText(), "WARNING: Buy token "+weth.Hex()+" is HIGH RISK: on a scam list: scam (test entry) per scams.txt")
+}
@@ -3,10 +3,14 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/tx"
+	"github.com/yolodolo42/clifi/internal/wallet"
 )
 
 func TestNewToolRegistry(t *testing.T) {
@@ -94,6 +98,26 @@ func TestToolRegistry_ExecuteTool(t *testing.T) {
 		}
 	})
 
+	t.Run("get_balances rejects tokens for unlisted chain", func(t *testing.T) {
+		tr := NewToolRegistry()
+		defer tr.Close()
+
+		input := json.RawMessage(`{"address": "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "chains": ["base"], "tokens": {"ethereum": ["0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"]}}`)
+		_, err := tr.ExecuteTool(context.Background(), "get_balances", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not in chains")
+	})
+
+	t.Run("get_balances validates token addresses", func(t *testing.T) {
+		tr := NewToolRegistry()
+		defer tr.Close()
+
+		input := json.RawMessage(`{"address": "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "chains": ["base"], "tokens": {"base": ["nope"]}}`)
+		_, err := tr.ExecuteTool(context.Background(), "get_balances", input)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid token address")
+	})
+
 	t.Run("get_token_balance validates wallet address", func(t *testing.T) {
 		tr := NewToolRegistry()
 		defer tr.Close()
@@ -215,3 +239,113 @@ func TestGetChainInfoInput(t *testing.T) {
 		assert.Equal(t, "polygon", params.Chain)
 	})
 }
+
+func TestToolRegistry_TxItemLinksExplorer(t *testing.T) {
+	tr := NewToolRegistry()
+	defer tr.Close()
+
+	hash := "0x" + strings.Repeat("11", 32)
+
+	item := tr.txItem("ethereum", hash)
+	assert.Equal(t, "Tx", item.Key)
+	assert.Equal(t, hash, item.Value)
+	assert.Equal(t, "https://etherscan.io/tx/"+hash, item.URL)
+
+	unknown := tr.txItem("nonexistent", hash)
+	assert.Empty(t, unknown.URL)
+}
+
+func TestToolRegistry_RequestFaucetRejectsMainnet(t *testing.T) {
+	dir := t.TempDir()
+	km, err := wallet.NewKeystoreManager(dir)
+	require.NoError(t, err)
+	_, err = km.CreateAccount("password123")
+	require.NoError(t, err)
+
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+
+	_, err = tr.ExecuteTool(context.Background(), "request_faucet", json.RawMessage(`{"chain": "ethereum"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only available on testnets")
+}
+
+func TestFeeFormatting(t *testing.T) {
+	t.Run("fee rates below 0.01 gwei are shown in wei", func(t *testing.T) {
+		assert.Equal(t, "1 wei", formatFeeRate(big.NewInt(1)))
+		assert.Equal(t, "1.50 gwei", formatFeeRate(big.NewInt(1_500_000_000)))
+	})
+
+	t.Run("L1 data fee line", func(t *testing.T) {
+		assert.Empty(t, l1FeeLine(tx.SuggestedFees{}))
+		fee := big.NewInt(2_000_000_000_000)
+		assert.Equal(t, "- L1 data fee: 0.000002 ETH\n", l1FeeLine(tx.SuggestedFees{L1FeeWei: fee}))
+		assert.Contains(t, l1FeeLine(tx.SuggestedFees{L1FeeWei: fee, L1FeeIncluded: true}), "included in gas limit")
+	})
+
+	t.Run("get_gas_price rejects unknown chain", func(t *testing.T) {
+		tr := NewToolRegistry()
+		defer tr.Close()
+
+		_, err := tr.ExecuteTool(context.Background(), "get_gas_price", json.RawMessage(`{"chain":"nonexistent"}`))
+		require.Error(t, err)
+	})
+}
+
+func TestGetQuoteValidation(t *testing.T) {
+	t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+	t.Setenv("CLIFI_MAX_SLIPPAGE_BPS", "")
+	tr := NewToolRegistryWithDataDir("")
+	defer tr.Close()
+
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"testnet":        {`{"from_chain":"sepolia","from_token":"ETH","to_token":"0x00000000000000000000000000000000000000aa","amount":"1"}`, "mainnets"},
+		"slippage":       {`{"from_chain":"base","from_token":"ETH","to_token":"USDC","amount":"1","slippage_bps":1000}`, "exceeds policy maximum"},
+		"same token":     {`{"from_chain":"base","from_token":"native","to_token":"eth","amount":"1"}`, "are the same"},
+		"unknown symbol": {`{"from_chain":"base","from_token":"ETH","to_token":"ZZZ","amount":"1"}`, "to_token"},
+		"missing amount": {`{"from_chain":"base","from_token":"ETH","to_token":"USDC"}`, "amount is required"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tr.ExecuteTool(context.Background(), "get_quote", json.RawMessage(tc.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestSwapValidation(t *testing.T) {
+	t.Setenv("CLIFI_SLIPPAGE_BPS", "")
+	t.Setenv("CLIFI_MAX_SLIPPAGE_BPS", "")
+	dir := t.TempDir()
+	km, err := wallet.NewKeystoreManager(dir)
+	require.NoError(t, err)
+	_, err = km.CreateAccount("password123")
+	require.NoError(t, err)
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"testnet":        {`{"chain":"sepolia","from_token":"ETH","to_token":"0x00000000000000000000000000000000000000aa","amount":"1"}`, "mainnets"},
+		"slippage":       {`{"chain":"base","from_token":"ETH","to_token":"USDC","amount":"1","slippage_bps":1000}`, "exceeds policy maximum"},
+		"same token":     {`{"chain":"base","from_token":"native","to_token":"eth","amount":"1"}`, "are the same"},
+		"unknown symbol": {`{"chain":"base","from_token":"ETH","to_token":"ZZZ","amount":"1"}`, "to_token"},
+		"missing amount": {`{"chain":"base","from_token":"ETH","to_token":"USDC"}`, "amount is required"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tr.ExecuteTool(context.Background(), "swap", json.RawMessage(tc.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+	capability, ok := tr.ToolCapability("swap")
+	require.True(t, ok)
+	assert.Equal(t, ToolSigning, capability)
+}
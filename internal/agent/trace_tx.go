@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+// maxTraceFrames caps the calls listed; the value flows still cover the
+// whole trace.
+const maxTraceFrames = 150
+
+type traceTxInput struct {
+	Chain  string `json:"chain"`
+	TxHash string `json:"tx_hash"`
+}
+
+// valueFlow is native currency moved by one internal call.
+type valueFlow struct {
+	From, To common.Address
+	Amount   *big.Int
+}
+
+// handleTraceTx shows a transaction's internal calls and the native value
+// they moved. Tracing needs a node that exposes debug or trace APIs, so
+// without one it says so instead of failing.
+func (tr *ToolRegistry) handleTraceTx(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+
+	var params traceTxInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		return ToolOutput{}, fmt.Errorf("chain is required")
+	}
+	txHash, err := parseTxHash(params.TxHash)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	cfg, err := tr.chainClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("unknown chain: %s", params.Chain)
+	}
+
+	root, err := tr.chainClient.TraceTransaction(ctx, params.Chain, txHash)
+	if errors.Is(err, chain.ErrTracingUnsupported) {
+		return ToolOutput{Text: fmt.Sprintf(
+			"Internal calls are not available: the %s RPC endpoint does not support tracing (debug_traceTransaction or trace_transaction). "+
+				"Use explain_tx for the decoded top-level call and its events; an RPC URL with tracing in chains.yaml enables traces.\n", params.Chain)}, nil
+	}
+	if err != nil {
+		return ToolOutput{}, fmt.Errorf("trace %s: %w", txHash.Hex(), err)
+	}
+
+	var contracts []common.Address
+	total := 0
+	walkFrames(root, 0, func(f *chain.CallFrame, _ int) bool {
+		total++
+		if len(f.Input) >= 4 && f.Type != "CREATE" && f.Type != "CREATE2" {
+			contracts = append(contracts, f.To)
+		}
+		return true
+	})
+	dec := decode.New()
+	notes := tr.loadABIs(ctx, params.Chain, cfg, dec, contracts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Call trace of %s on %s:\n", txHash.Hex(), params.Chain)
+	table := &UITable{Title: "Call trace", Headers: []string{"Call", "To", "Function", "Value", "Result"}}
+	shown := 0
+	walkFrames(root, 0, func(f *chain.CallFrame, depth int) bool {
+		if shown == maxTraceFrames {
+			return false
+		}
+		shown++
+		fn, value, result := describeFrame(dec, f, cfg.NativeCurrency)
+		line := fmt.Sprintf("%s%s %s %s", strings.Repeat("  ", depth), f.Type, f.To.Hex(), fn)
+		if value != "" {
+			line += " value " + value
+		}
+		if result != "ok" {
+			line += " [" + result + "]"
+		}
+		b.WriteString(line + "\n")
+		table.Rows = append(table.Rows, []string{strings.Repeat("  ", depth) + f.Type, f.To.Hex(), fn, value, result})
+		return true
+	})
+	if total > shown {
+		fmt.Fprintf(&b, "(%d more calls not shown)\n", total-shown)
+	}
+	blocks := []UIBlock{{Kind: UIBlockTable, Table: table}}
+
+	flows := valueFlows(root)
+	if len(flows) > 0 {
+		fmt.Fprintf(&b, "%s moved by the transaction and its internal calls:\n", cfg.NativeCurrency)
+		ft := &UITable{Title: cfg.NativeCurrency + " flows", Headers: []string{"From", "To", "Amount"}}
+		for _, fl := range flows {
+			amount := weiToEth(fl.Amount) + " " + cfg.NativeCurrency
+			fmt.Fprintf(&b, "- %s -> %s: %s\n", fl.From.Hex(), fl.To.Hex(), amount)
+			ft.Rows = append(ft.Rows, []string{fl.From.Hex(), fl.To.Hex(), amount})
+		}
+		blocks = append(blocks, UIBlock{Kind: UIBlockTable, Table: ft})
+	}
+	for _, n := range notes {
+		b.WriteString("Note: " + n + "\n")
+	}
+	return ToolOutput{Text: b.String(), Blocks: blocks}, nil
+}
+
+// walkFrames visits f and its calls depth first until visit returns false.
+func walkFrames(f *chain.CallFrame, depth int, visit func(*chain.CallFrame, int) bool) bool {
+	if !visit(f, depth) {
+		return false
+	}
+	for i := range f.Calls {
+		if !walkFrames(&f.Calls[i], depth+1, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeFrame renders a call's function, the value it carried and how
+// it ended.
+func describeFrame(dec *decode.Decoder, f *chain.CallFrame, symbol string) (fn, value, result string) {
+	switch {
+	case f.Type == "CREATE" || f.Type == "CREATE2":
+		fn = fmt.Sprintf("deploy (%d bytes of init code)", len(f.Input))
+	case f.Type == "SELFDESTRUCT":
+		fn = "selfdestruct"
+	case len(f.Input) == 0:
+		fn = "(no calldata)"
+	default:
+		if c, err := dec.Call(f.Input); err == nil {
+			fn = c.String()
+		} else if len(f.Input) >= 4 {
+			fn = "unknown " + hexutil.Encode(f.Input[:4])
+		} else {
+			fn = "(" + hexutil.Encode(f.Input) + ")"
+		}
+	}
+	if f.Value != nil && f.Value.ToInt().Sign() > 0 && f.Type != "DELEGATECALL" {
+		value = weiToEth(f.Value.ToInt()) + " " + symbol
+	}
+	result = "ok"
+	if f.Error != "" {
+		result = "reverted: " + f.Error
+		if f.RevertReason != "" {
+			result += " (" + f.RevertReason + ")"
+		}
+	}
+	return fn, value, result
+}
+
+// valueFlows lists the native value each call moved. A reverted call moved
+// nothing, and neither did anything it called; a delegatecall's value is
+// only its caller's, already counted.
+func valueFlows(root *chain.CallFrame) []valueFlow {
+	var flows []valueFlow
+	var walk func(f *chain.CallFrame)
+	walk = func(f *chain.CallFrame) {
+		if f.Error != "" {
+			return
+		}
+		if f.Value != nil && f.Value.ToInt().Sign() > 0 && f.Type != "DELEGATECALL" {
+			flows = append(flows, valueFlow{From: f.From, To: f.To, Amount: f.Value.ToInt()})
+		}
+		for i := range f.Calls {
+			walk(&f.Calls[i])
+		}
+	}
+	walk(root)
+	return flows
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/decode"
+)
+
+func TestValueFlows(t *testing.T) {
+	eth := func(n int64) *hexutil.Big { return (*hexutil.Big)(new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))) }
+	a, b, c := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+	root := &chain.CallFrame{Type: "CALL", From: a, To: b, Value: eth(2), Calls: []chain.CallFrame{
+		{Type: "DELEGATECALL", From: b, To: c, Value: eth(2), Calls: []chain.CallFrame{
+			{Type: "CALL", From: b, To: a, Value: eth(1)},
+		}},
+		// Reverted: neither it nor its calls moved anything.
+		{Type: "CALL", From: b, To: c, Value: eth(1), Error: "execution reverted", Calls: []chain.CallFrame{
+			{Type: "CALL", From: c, To: a, Value: eth(1)},
+		}},
+	}}
+
+	flows := valueFlows(root)
+	require.Len(t, flows, 2)
+	assert.Equal(t, valueFlow{From: a, To: b, Amount: eth(2).ToInt()}, flows[0])
+	assert.Equal(t, valueFlow{From: b, To: a, Amount: eth(1).ToInt()}, flows[1])
+}
+
+func TestDescribeFrame(t *testing.T) {
+	dec := decode.New()
+	input := append(common.FromHex("0xa9059cbb"), append(common.LeftPadBytes(common.HexToAddress("0xb").Bytes(), 32), common.LeftPadBytes([]byte{5}, 32)...)...)
+
+	fn, value, result := describeFrame(dec, &chain.CallFrame{Type: "CALL", Input: input}, "ETH")
+	assert.Equal(t, "transfer(to=0x000000000000000000000000000000000000000b, amount=5)", fn)
+	assert.Empty(t, value)
+	assert.Equal(t, "ok", result)
+
+	fn, _, result = describeFrame(dec, &chain.CallFrame{Type: "CALL", Input: common.FromHex("0xdeadbeef"), Error: "execution reverted", RevertReason: "paused"}, "ETH")
+	assert.Equal(t, "unknown 0xdeadbeef", fn)
+	assert.Equal(t, "reverted: execution reverted (paused)", result)
+}
@@ -2,40 +2,122 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/wallet"
 )
 
-func (tr *ToolRegistry) signAndSendTx(ctx context.Context, chainName string, fromAddr common.Address, password string, unsigned *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+// signAndSendTx signs unsigned and broadcasts it, through relay when non-nil
+// and the chain's public RPC otherwise.
+func (tr *ToolRegistry) signAndSendTx(ctx context.Context, chainName string, fromAddr common.Address, password string, unsigned *types.Transaction, chainID *big.Int, relay *chain.PrivateRelay) (*types.Transaction, error) {
 	km, err := tr.keystore()
 	if err != nil {
 		return nil, err
 	}
+	if km.IsEnvSigner(fromAddr) {
+		if err := tr.checkSigner(chainName, fromAddr, password); err != nil {
+			return nil, err
+		}
+	}
 
 	signer, err := km.GetSigner(fromAddr, password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unlock signer: %w", err)
 	}
 
-	signed, err := signer.SignTransaction(unsigned, chainID)
+	// The queue replaces the nonce picked when unsigned was built, which
+	// an earlier send in the same turn may have taken since.
+	signed, err := tr.txQueue.Submit(ctx, chainName, fromAddr, unsigned, relay, func(ctx context.Context, unsigned *types.Transaction) (*types.Transaction, error) {
+		signed, err := signer.SignTransaction(unsigned, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign tx: %w", err)
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		defer cancel()
+		if relay != nil {
+			err = tr.chainClient.SendPrivateTransaction(sendCtx, chainName, relay, signed)
+		} else {
+			err = tr.chainClient.SendTransaction(sendCtx, chainName, signed)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to send tx: %w", err)
+		}
+		return signed, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign tx: %w", err)
+		return nil, err
 	}
+	tr.recordSent(chainName, fromAddr, signed, relay)
 
-	sendCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-	defer cancel()
-	if err := tr.chainClient.SendTransaction(sendCtx, chainName, signed); err != nil {
-		return nil, fmt.Errorf("failed to send tx: %w", err)
+	// Both sides' native balances are now stale (value and/or gas spent).
+	tr.chainClient.InvalidateBalance(chainName, fromAddr)
+	if to := signed.To(); to != nil {
+		tr.chainClient.InvalidateBalance(chainName, *to)
 	}
 
 	return signed, nil
 }
 
-func (tr *ToolRegistry) maybeWaitAndPersistReceipt(ctx context.Context, chainName string, txHash common.Hash, wait *bool) (string, error) {
+// checkSigner checks that from can sign on chainName: keystore wallets
+// need a password, the environment signer and the KMS keys none, but the
+// environment signer is held to testnets unless
+// wallet.EnvSignerAllowMainnet is set.
+func (tr *ToolRegistry) checkSigner(chainName string, from common.Address, password string) error {
+	km, err := tr.keystore()
+	if err != nil || km.NeedsPassword(from) {
+		if password == "" {
+			return fmt.Errorf("password required to sign")
+		}
+		return nil
+	}
+	if !km.IsEnvSigner(from) || wallet.EnvSignerMainnetAllowed() {
+		return nil
+	}
+	cfg, err := tr.chainClient.GetChainConfig(chainName)
+	if err != nil {
+		return err
+	}
+	if !cfg.IsTestnet {
+		return wallet.ErrEnvSignerMainnet
+	}
+	return nil
+}
+
+// describeSigners tells the model which wallets sign without a password,
+// or returns "" when all need one.
+func (tr *ToolRegistry) describeSigners() string {
+	km, err := tr.keystore()
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	if addr, ok := km.EnvSigner(); ok {
+		lines = append(lines, fmt.Sprintf("- %s signs with a key given to this process (%s), for testnet automation. It refuses mainnets unless %s=1.",
+			addr.Hex(), wallet.EnvSignerKey, wallet.EnvSignerAllowMainnet))
+	}
+	for _, acc := range km.ListAccounts() {
+		if provider := km.KMSProvider(acc.Address); provider != "" {
+			lines = append(lines, fmt.Sprintf("- %s signs with a %s key (%s).", acc.Address.Hex(), provider, acc.URL.Path))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "## Wallets without a password\nLeave password empty when confirming transactions from these:\n" + strings.Join(lines, "\n")
+}
+
+// maybeWaitAndPersistReceipt waits for txHash unless wait is false, and
+// reports the receipt line and confirmation once it is mined.
+func (tr *ToolRegistry) maybeWaitAndPersistReceipt(ctx context.Context, chainName string, txHash common.Hash, wait *bool) (string, *TxConfirmation) {
 	shouldWait := true
 	if wait != nil {
 		shouldWait = *wait
@@ -52,9 +134,151 @@ func (tr *ToolRegistry) maybeWaitAndPersistReceipt(ctx context.Context, chainNam
 		return "", nil
 	}
 
-	if rs, err := tr.receiptStore(); err == nil {
-		_ = rs.Upsert(chainName, receipt)
+	tr.persistReceipt(ctx, chainName, receipt)
+
+	return fmt.Sprintf("Receipt status: %d, gas used: %d", receipt.Status, receipt.GasUsed), confirmationOf(chainName, receipt)
+}
+
+func confirmationOf(chainName string, receipt *types.Receipt) *TxConfirmation {
+	return &TxConfirmation{
+		Chain:   chainName,
+		TxHash:  receipt.TxHash.Hex(),
+		Success: receipt.Status == types.ReceiptStatusSuccessful,
+	}
+}
+
+// privateRelayFor picks the relay a send should go through. CLIFI_PRIVATE_TX
+// names the default relay (flashbots, mevblocker or an https URL) and applies
+// to every chain it supports; other chains fall back to the public mempool.
+// private=true forces private submission (Flashbots Protect unless configured
+// otherwise) and fails on unsupported chains; private=false forces public.
+func privateRelayFor(chainName string, cfg *chain.ChainConfig, private *bool) (*chain.PrivateRelay, error) {
+	if private != nil && !*private {
+		return nil, nil
+	}
+
+	var relay *chain.PrivateRelay
+	if v := os.Getenv("CLIFI_PRIVATE_TX"); v != "" {
+		r, err := chain.LookupPrivateRelay(v)
+		if err != nil {
+			return nil, fmt.Errorf("CLIFI_PRIVATE_TX: %w", err)
+		}
+		relay = r
+	}
+	explicit := private != nil && *private
+	if relay == nil {
+		if !explicit {
+			return nil, nil
+		}
+		relay = defaultPrivateRelay(cfg)
+		if relay == nil {
+			return nil, fmt.Errorf("no private relay available for %s; set CLIFI_PRIVATE_TX to a relay URL", chainName)
+		}
+	}
+
+	if !relay.Supports(cfg.ChainID) {
+		if explicit {
+			return nil, fmt.Errorf("%s does not support %s", relay.Name, chainName)
+		}
+		return nil, nil
+	}
+	return relay, nil
+}
+
+// defaultPrivateRelay returns the built-in Flashbots relay for cfg's chain.
+func defaultPrivateRelay(cfg *chain.ChainConfig) *chain.PrivateRelay {
+	for _, name := range []string{"flashbots", "flashbots-sepolia"} {
+		if r := chain.PrivateRelays()[name]; r.Supports(cfg.ChainID) {
+			return r
+		}
+	}
+	return nil
+}
+
+// submissionLine notes private submission in a preview.
+func submissionLine(relay *chain.PrivateRelay) string {
+	if relay == nil {
+		return ""
 	}
+	return fmt.Sprintf("- Submission: private via %s (not visible in the public mempool)\n", relay.Name)
+}
 
-	return fmt.Sprintf("Receipt status: %d, gas used: %d", receipt.Status, receipt.GasUsed), nil
+// privateStatusLine reports the relay's status for a private transaction
+// whose receipt is not available yet.
+func privateStatusLine(ctx context.Context, relay *chain.PrivateRelay, txHash common.Hash) string {
+	if relay == nil {
+		return ""
+	}
+	statusCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	status, err := relay.TxStatus(statusCtx, txHash)
+	if errors.Is(err, chain.ErrNoPrivateStatus) {
+		return fmt.Sprintf("Submitted privately via %s; it will not appear on explorers until included.", relay.Name)
+	}
+	if err != nil {
+		return fmt.Sprintf("Private status unavailable: %v", err)
+	}
+	return fmt.Sprintf("Private status (%s): %s", relay.Name, status.Status)
+}
+
+type getPrivateTxStatusInput struct {
+	Chain  string `json:"chain"`
+	TxHash string `json:"tx_hash"`
+}
+
+func (tr *ToolRegistry) handleGetPrivateTxStatus(ctx context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params getPrivateTxStatusInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if params.Chain == "" {
+		params.Chain = "ethereum"
+	}
+	txHash, err := parseTxHash(params.TxHash)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	cfg, err := tr.chainClient.GetChainConfig(params.Chain)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	private := true
+	relay, err := privateRelayFor(params.Chain, cfg, &private)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	status, err := relay.TxStatus(ctx, txHash)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+
+	items := []KVItem{
+		{Key: "Relay", Value: relay.Name},
+		tr.txItem(params.Chain, txHash.Hex()),
+		{Key: "Status", Value: status.Status},
+	}
+	if status.MaxBlockNumber > 0 {
+		items = append(items, KVItem{Key: "Max block", Value: fmt.Sprintf("%d", status.MaxBlockNumber)})
+	}
+	if status.SimError != "" {
+		items = append(items, KVItem{Key: "Simulation error", Value: status.SimError})
+	}
+
+	text := fmt.Sprintf("Private tx status (%s):\n- Tx: %s\n- Status: %s\n", relay.Name, txHash.Hex(), status.Status)
+	switch status.Status {
+	case chain.PrivateTxPending:
+		text += "The relay is still trying to include it; it is not in the public mempool.\n"
+	case chain.PrivateTxFailed, chain.PrivateTxCancelled:
+		text += "It will not be included. The nonce is free to reuse.\n"
+	case chain.PrivateTxIncluded:
+		text += "Included on-chain; use get_receipt for details.\n"
+	}
+	if status.SimError != "" {
+		text += "- Simulation error: " + status.SimError + "\n"
+	}
+	return ToolOutput{Text: text, Blocks: []UIBlock{kvBlock("Private tx", items...)}}, nil
 }
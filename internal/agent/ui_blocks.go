@@ -27,4 +27,6 @@ type UIKV struct {
 type KVItem struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
+	// URL, when set, links Value to an external page (e.g. a block explorer).
+	URL string `json:"url,omitempty"`
 }
@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+type createWatchInput struct {
+	Condition string `json:"condition"`
+	Once      bool   `json:"once"`
+}
+
+func (tr *ToolRegistry) handleCreateWatch(_ context.Context, input json.RawMessage) (ToolOutput, error) {
+	var params createWatchInput
+	if err := parseToolInput(input, &params); err != nil {
+		return ToolOutput{}, err
+	}
+	if tr.dataDir == "" {
+		return ToolOutput{}, fmt.Errorf("watches need a data directory")
+	}
+
+	cond, err := watch.ParseCondition(params.Condition)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	if _, err := tr.chainClient.GetChainConfig(cond.Chain); err != nil {
+		return ToolOutput{}, err
+	}
+	if cond.Kind == watch.KindBalance {
+		km, err := tr.keystore()
+		if err != nil {
+			return ToolOutput{}, err
+		}
+		if err := cond.ResolveWallet(km); err != nil {
+			return ToolOutput{}, err
+		}
+	}
+
+	w, err := watch.NewStore(tr.dataDir).Add(cond, params.Once)
+	if err != nil {
+		return ToolOutput{}, err
+	}
+	return ToolOutput{Text: fmt.Sprintf(
+		"Created watch #%d: %s. Alerts are delivered while `clifi serve` is running; `clifi watch list` shows all watches.",
+		w.ID, w.Condition)}, nil
+}
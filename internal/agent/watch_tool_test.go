@@ -0,0 +1,33 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+func TestCreateWatch(t *testing.T) {
+	dir := t.TempDir()
+	tr := NewToolRegistryWithDataDir(dir)
+	defer tr.Close()
+	ctx := context.Background()
+
+	out, err := tr.ExecuteTool(ctx, "create_watch", json.RawMessage(`{"condition":"ETH > 4000","once":true}`))
+	require.NoError(t, err)
+	assert.Contains(t, out.Text, "Created watch #1: ETH > 4000")
+	assert.Contains(t, out.Text, "clifi serve")
+
+	watches, err := watch.NewStore(dir).List()
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	assert.True(t, watches[0].Once)
+
+	_, err = tr.ExecuteTool(ctx, "create_watch", json.RawMessage(`{"condition":"ETH@nochain > 1"}`))
+	assert.Error(t, err)
+	_, err = tr.ExecuteTool(ctx, "create_watch", json.RawMessage(`{"condition":"ETH = 1"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+// Package audit keeps an append-only record of the transactions clifi
+// makes on its own, such as scheduled buys run by `clifi serve`.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File is the audit trail, in the data dir: one JSON object per line.
+const File = "audit.jsonl"
+
+// Status is how an action ended.
+type Status string
+
+const (
+	StatusOK     Status = "ok"
+	StatusFailed Status = "failed"
+)
+
+// Entry is one action.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"` // what acted, e.g. "dca #3"
+	Action  string    `json:"action"` // e.g. "swap"
+	Chain   string    `json:"chain,omitempty"`
+	From    string    `json:"from,omitempty"`
+	Summary string    `json:"summary"`
+	TxHash  string    `json:"tx_hash,omitempty"`
+	Status  Status    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Log appends entries to dataDir/audit.jsonl. Entries are never rewritten,
+// so the file can be shipped or tailed as it grows.
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// New returns the log in dataDir.
+func New(dataDir string) *Log {
+	return &Log{path: filepath.Join(dataDir, File)}
+}
+
+// Record appends e, stamping the time when it is unset.
+func (l *Log) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", File, err)
+	}
+	// One write per entry keeps lines whole when serve and the CLI append
+	// at the same time.
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	return f.Close()
+}
+
+// Entries returns the recorded entries, oldest first.
+func (l *Log) Entries() ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", File, err)
+	}
+	defer f.Close()
+
+	var out []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", File, n, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/browser"
 	"github.com/spf13/viper"
 	"github.com/yolodolo42/clifi/internal/llm"
 )
@@ -98,6 +99,8 @@ func (m *Manager) SetOAuthToken(providerID llm.ProviderID, token *OAuthCredentia
 
 // ConnectWithOAuth initiates the OAuth flow for a provider.
 // Opens browser for user authentication and stores the resulting tokens.
+// Providers with a device flow print the code to enter instead of waiting
+// for a redirect.
 func (m *Manager) ConnectWithOAuth(ctx context.Context, providerID llm.ProviderID) error {
 	config := GetOAuthConfig(providerID)
 	if config == nil {
@@ -108,12 +111,53 @@ func (m *Manager) ConnectWithOAuth(ctx context.Context, providerID llm.ProviderI
 		return fmt.Errorf("OAuth not configured for provider %s (no client ID)", providerID)
 	}
 
+	if config.DeviceCodeURL != "" {
+		code, err := StartDeviceFlow(ctx, *config)
+		if err != nil {
+			return fmt.Errorf("OAuth flow failed: %w", err)
+		}
+		fmt.Printf("Enter code %s at %s to sign in to %s.\n", code.UserCode, code.VerificationURI, config.ProviderName)
+		if err := browser.OpenURL(code.VerificationURI); err != nil {
+			fmt.Printf("Could not open browser automatically. Please visit the URL above.\n")
+		}
+		fmt.Println("Waiting for approval...")
+		return m.FinishDeviceFlow(ctx, providerID, code)
+	}
+
 	// Start OAuth flow
 	result, err := StartOAuthFlow(ctx, *config)
 	if err != nil {
 		return fmt.Errorf("OAuth flow failed: %w", err)
 	}
+	return m.storeOAuthResult(providerID, result)
+}
+
+// StartDeviceFlow requests a device code for a provider whose OAuth
+// config has a DeviceCodeURL. Show the user code to the user, then call
+// FinishDeviceFlow.
+func (m *Manager) StartDeviceFlow(ctx context.Context, providerID llm.ProviderID) (*DeviceCode, error) {
+	config := GetOAuthConfig(providerID)
+	if config == nil || config.DeviceCodeURL == "" {
+		return nil, fmt.Errorf("provider %s does not support device login", providerID)
+	}
+	return StartDeviceFlow(ctx, *config)
+}
+
+// FinishDeviceFlow waits for the user to approve code and stores the
+// resulting tokens.
+func (m *Manager) FinishDeviceFlow(ctx context.Context, providerID llm.ProviderID, code *DeviceCode) error {
+	config := GetOAuthConfig(providerID)
+	if config == nil || config.DeviceCodeURL == "" {
+		return fmt.Errorf("provider %s does not support device login", providerID)
+	}
+	result, err := PollDeviceToken(ctx, *config, code)
+	if err != nil {
+		return fmt.Errorf("OAuth flow failed: %w", err)
+	}
+	return m.storeOAuthResult(providerID, result)
+}
 
+func (m *Manager) storeOAuthResult(providerID llm.ProviderID, result *OAuthResult) error {
 	// Calculate expiry time
 	expiresAt := ""
 	if result.ExpiresIn > 0 {
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// devicePollUnit is the unit of the device flow's polling interval;
+// tests shorten it.
+var devicePollUnit = time.Second
+
+// DeviceCode is a pending device authorization: the user enters UserCode
+// at VerificationURI while the CLI polls for the token.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// StartDeviceFlow requests a device code (RFC 8628), for providers whose
+// OAuth app has no local redirect, such as GitHub's Copilot app.
+func StartDeviceFlow(ctx context.Context, config OAuthConfig) (*DeviceCode, error) {
+	data := url.Values{}
+	data.Set("client_id", config.ClientID)
+	if len(config.Scopes) > 0 {
+		data.Set("scope", strings.Join(config.Scopes, " "))
+	}
+
+	resp, err := postForm(ctx, config.DeviceCodeURL, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request device code: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device code endpoint returned status %d", resp.StatusCode)
+	}
+	var code DeviceCode
+	if err := decodeJSON(resp.Body, &code); err != nil {
+		return nil, fmt.Errorf("failed to parse device code response: %w", err)
+	}
+	if code.DeviceCode == "" || code.UserCode == "" {
+		return nil, fmt.Errorf("no device code in response")
+	}
+	return &code, nil
+}
+
+// PollDeviceToken waits for the user to approve code and returns the
+// tokens, polling at the interval the server asks for.
+func PollDeviceToken(ctx context.Context, config OAuthConfig, code *DeviceCode) (*OAuthResult, error) {
+	interval := time.Duration(max(code.Interval, 1)) * devicePollUnit
+	if code.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*devicePollUnit)
+		defer cancel()
+	}
+
+	data := url.Values{}
+	data.Set("client_id", config.ClientID)
+	data.Set("device_code", code.DeviceCode)
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("device code expired before it was approved")
+		case <-time.After(interval):
+		}
+
+		resp, err := postForm(ctx, config.TokenURL, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll for token: %w", err)
+		}
+		var result struct {
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+			ExpiresIn    int    `json:"expires_in"`
+			TokenType    string `json:"token_type"`
+			Error        string `json:"error"`
+			ErrorDesc    string `json:"error_description"`
+			Interval     int    `json:"interval"`
+		}
+		err = decodeJSON(resp.Body, &result)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token response: %w", err)
+		}
+
+		switch result.Error {
+		case "":
+			if result.AccessToken == "" {
+				return nil, fmt.Errorf("no access token in response")
+			}
+			return &OAuthResult{
+				AccessToken:  result.AccessToken,
+				RefreshToken: result.RefreshToken,
+				ExpiresIn:    result.ExpiresIn,
+				TokenType:    result.TokenType,
+			}, nil
+		case "authorization_pending":
+		case "slow_down":
+			if result.Interval > 0 {
+				interval = time.Duration(result.Interval) * devicePollUnit
+			} else {
+				interval += 5 * devicePollUnit
+			}
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired before it was approved")
+		case "access_denied":
+			return nil, fmt.Errorf("authorization was denied")
+		default:
+			return nil, fmt.Errorf("token error: %s - %s", result.Error, result.ErrorDesc)
+		}
+	}
+}
+
+// postForm posts data and asks for JSON, which GitHub otherwise answers
+// form-encoded.
+func postForm(ctx context.Context, endpoint string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func TestDeviceFlow(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = time.Second })
+
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device/code", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client", r.FormValue("client_id"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"device_code":"dev","user_code":"ABCD-1234","verification_uri":"https://github.com/login/device","expires_in":900,"interval":5}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "dev", r.FormValue("device_code"))
+		switch polls.Add(1) {
+		case 1:
+			_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+		case 2:
+			_, _ = w.Write([]byte(`{"error":"slow_down","interval":10}`))
+		default:
+			_, _ = w.Write([]byte(`{"access_token":"gho_token","token_type":"bearer"}`))
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	config := OAuthConfig{ClientID: "client", TokenURL: srv.URL + "/token", DeviceCodeURL: srv.URL + "/device/code"}
+	code, err := StartDeviceFlow(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, "ABCD-1234", code.UserCode)
+
+	result, err := PollDeviceToken(context.Background(), config, code)
+	require.NoError(t, err)
+	assert.Equal(t, "gho_token", result.AccessToken)
+	assert.Equal(t, int32(3), polls.Load())
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = time.Second })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"access_denied"}`))
+	}))
+	defer srv.Close()
+
+	_, err := PollDeviceToken(context.Background(), OAuthConfig{TokenURL: srv.URL}, &DeviceCode{DeviceCode: "dev", Interval: 1})
+	assert.ErrorContains(t, err, "denied")
+}
+
+func TestManager_DeviceFlowNeedsSupport(t *testing.T) {
+	manager, err := NewManager(testutil.TempDir(t))
+	require.NoError(t, err)
+
+	_, err = manager.StartDeviceFlow(context.Background(), llm.ProviderAnthropic)
+	assert.ErrorContains(t, err, "does not support device login")
+	assert.NotEmpty(t, GetOAuthConfig(llm.ProviderCopilot).DeviceCodeURL)
+}
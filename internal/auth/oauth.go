@@ -39,6 +39,9 @@ type OAuthConfig struct {
 	ClientSecret string // Optional, some flows don't need it
 	Scopes       []string
 	RedirectURI  string // Will be set automatically if empty
+	// DeviceCodeURL selects the device flow instead of the browser
+	// redirect when set.
+	DeviceCodeURL string
 }
 
 // OAuthResult contains the result of a successful OAuth flow
@@ -88,13 +88,14 @@ var providerAuthConfigs = map[llm.ProviderID]ProviderAuthInfo{
 			{
 				Type:        "oauth",
 				Label:       "GitHub Login",
-				Description: "Sign in with GitHub (opens browser)",
+				Description: "Sign in with GitHub (enter a code in the browser)",
 			},
 		},
 		OAuthConfig: &OAuthConfig{
-			ProviderName: "GitHub Copilot",
-			AuthURL:      "https://github.com/login/oauth/authorize",
-			TokenURL:     "https://github.com/login/oauth/access_token",
+			ProviderName:  "GitHub Copilot",
+			AuthURL:       "https://github.com/login/oauth/authorize",
+			TokenURL:      "https://github.com/login/oauth/access_token",
+			DeviceCodeURL: "https://github.com/login/device/code",
 			// GitHub's public OAuth app for Copilot CLI
 			ClientID: "Iv1.b507a08c87ecfe98",
 			Scopes:   []string{"read:user"},
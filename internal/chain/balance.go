@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // Common ERC20 ABI function selectors
@@ -44,6 +46,7 @@ type Portfolio struct {
 	Address        string                     `json:"address"`
 	NativeBalances map[string]*NativeBalance  `json:"native_balances"`
 	TokenBalances  map[string][]*TokenBalance `json:"token_balances"`
+	Errors         map[string]string          `json:"errors,omitempty"`
 }
 
 // GetNativeBalance returns the native token balance for an address
@@ -66,83 +69,208 @@ func (c *Client) GetNativeBalance(ctx context.Context, chainName string, address
 	}, nil
 }
 
+// TokenMetadata holds the descriptive fields of an ERC20 token
+type TokenMetadata struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+}
+
 // GetTokenBalance returns the balance of an ERC20 token
 func (c *Client) GetTokenBalance(ctx context.Context, chainName string, tokenAddress, holderAddress common.Address) (*TokenBalance, error) {
-	// Build balanceOf call data
-	callData := make([]byte, 36)
-	copy(callData[:4], balanceOfSelector)
-	copy(callData[4:], common.LeftPadBytes(holderAddress.Bytes(), 32))
-
-	msg := ethereum.CallMsg{
-		To:   &tokenAddress,
-		Data: callData,
+	balances, err := c.GetTokenBalances(ctx, chainName, holderAddress, []common.Address{tokenAddress})
+	if err != nil {
+		return nil, err
 	}
+	return balances[0], nil
+}
 
-	result, err := c.CallContract(ctx, chainName, msg)
-	if err != nil {
+// GetTokenBalances returns ERC20 balances and metadata for several tokens held
+// by one address. All balanceOf/symbol/name/decimals calls share one batch;
+// metadata already in the cache is not re-fetched.
+func (c *Client) GetTokenBalances(ctx context.Context, chainName string, holderAddress common.Address, tokens []common.Address) ([]*TokenBalance, error) {
+	elems, decode := c.tokenBalanceCalls(chainName, holderAddress, tokens)
+	if err := c.BatchCall(ctx, chainName, elems); err != nil {
 		return nil, fmt.Errorf("failed to get token balance: %w", err)
 	}
+	return decode(elems)
+}
 
-	balance := new(big.Int).SetBytes(result)
+// tokenBalanceCalls builds the batch elements for holder's balance and the
+// metadata of each token, plus a decoder for the completed elements. Splitting
+// build and decode lets callers append extra calls to the same batch. Tokens
+// with cached metadata only need their balanceOf call.
+func (c *Client) tokenBalanceCalls(chainName string, holderAddress common.Address, tokens []common.Address) ([]rpc.BatchElem, func([]rpc.BatchElem) ([]*TokenBalance, error)) {
+	balanceData := make([]byte, 36)
+	copy(balanceData[:4], balanceOfSelector)
+	copy(balanceData[4:], common.LeftPadBytes(holderAddress.Bytes(), 32))
+
+	// Layout per token: [balanceOf] or [balanceOf, symbol, name, decimals];
+	// offsets[i] is the index of token i's balanceOf call.
+	offsets := make([]int, len(tokens))
+	cached := make([]*TokenMetadata, len(tokens))
+	var elems []rpc.BatchElem
+	for i, token := range tokens {
+		offsets[i] = len(elems)
+		elems = append(elems, newEthCall(token, balanceData, new(hexutil.Bytes)))
+		if meta, ok := c.cache.tokenMetadata(chainName, token); ok {
+			cached[i] = &meta
+			continue
+		}
+		elems = append(elems, metadataCalls(token)...)
+	}
 
-	// Get token metadata
-	symbol, _ := c.getTokenSymbol(ctx, chainName, tokenAddress)
-	name, _ := c.getTokenName(ctx, chainName, tokenAddress)
-	decimals, _ := c.getTokenDecimals(ctx, chainName, tokenAddress)
+	decode := func(elems []rpc.BatchElem) ([]*TokenBalance, error) {
+		balances := make([]*TokenBalance, len(tokens))
+		for i, token := range tokens {
+			base := offsets[i]
+			if err := elems[base].Error; err != nil {
+				return nil, fmt.Errorf("failed to get token balance for %s: %w", token.Hex(), err)
+			}
+			balanceOut, _ := callResult(elems[base])
+
+			meta := cached[i]
+			if meta == nil {
+				m := c.decodeAndCacheMetadata(chainName, token, elems[base+1:base+1+metadataCallsPerToken])
+				meta = &m
+			}
+			balances[i] = &TokenBalance{
+				TokenAddress: token.Hex(),
+				Symbol:       meta.Symbol,
+				Name:         meta.Name,
+				Balance:      new(big.Int).SetBytes(balanceOut),
+				Decimals:     meta.Decimals,
+			}
+		}
+		return balances, nil
+	}
+	return elems, decode
+}
 
-	return &TokenBalance{
-		TokenAddress: tokenAddress.Hex(),
-		Symbol:       symbol,
-		Name:         name,
-		Balance:      balance,
-		Decimals:     decimals,
-	}, nil
+// metadataCallsPerToken is the number of calls metadataCalls emits.
+const metadataCallsPerToken = 3
+
+// metadataCalls builds [symbol, name, decimals] calls for a token.
+func metadataCalls(token common.Address) []rpc.BatchElem {
+	return []rpc.BatchElem{
+		newEthCall(token, symbolSelector, new(hexutil.Bytes)),
+		newEthCall(token, nameSelector, new(hexutil.Bytes)),
+		newEthCall(token, decimalsSelector, new(hexutil.Bytes)),
+	}
 }
 
-func (c *Client) getTokenSymbol(ctx context.Context, chainName string, tokenAddress common.Address) (string, error) {
-	msg := ethereum.CallMsg{
-		To:   &tokenAddress,
-		Data: symbolSelector,
+// GetTokenMetadata returns symbol, name and decimals for several tokens.
+// Cached tokens are served from memory; the rest share a single batch.
+func (c *Client) GetTokenMetadata(ctx context.Context, chainName string, tokens []common.Address) ([]*TokenMetadata, error) {
+	metas := make([]*TokenMetadata, len(tokens))
+	var (
+		elems   []rpc.BatchElem
+		pending []int
+	)
+	for i, token := range tokens {
+		if meta, ok := c.cache.tokenMetadata(chainName, token); ok {
+			metas[i] = &meta
+			continue
+		}
+		pending = append(pending, i)
+		elems = append(elems, metadataCalls(token)...)
 	}
 
-	result, err := c.CallContract(ctx, chainName, msg)
-	if err != nil {
-		return "", err
+	if err := c.BatchCall(ctx, chainName, elems); err != nil {
+		return nil, fmt.Errorf("failed to get token metadata: %w", err)
 	}
 
-	return decodeString(result), nil
+	for n, i := range pending {
+		base := n * metadataCallsPerToken
+		meta := c.decodeAndCacheMetadata(chainName, tokens[i], elems[base:base+metadataCallsPerToken])
+		metas[i] = &meta
+	}
+	return metas, nil
 }
 
-func (c *Client) getTokenName(ctx context.Context, chainName string, tokenAddress common.Address) (string, error) {
-	msg := ethereum.CallMsg{
-		To:   &tokenAddress,
-		Data: nameSelector,
+// GetTokenSymbolDecimals returns the symbol and decimals of a token. name()
+// is fetched in the same batch so the result can be cached (and persisted,
+// see SetTokenMetadataStore) like any other metadata lookup; repeat sends of
+// the same token then need no eth_call at all.
+//
+// A token whose decimals() call reverts is assumed to use 18 decimals; an
+// unreachable RPC is an error, since guessing decimals would silently scale
+// transfer amounts by orders of magnitude.
+func (c *Client) GetTokenSymbolDecimals(ctx context.Context, chainName string, token common.Address) (string, uint8, error) {
+	if meta, ok := c.cache.tokenMetadata(chainName, token); ok {
+		return meta.Symbol, meta.Decimals, nil
 	}
 
-	result, err := c.CallContract(ctx, chainName, msg)
-	if err != nil {
-		return "", err
+	elems := metadataCalls(token)
+	if err := c.BatchCall(ctx, chainName, elems); err != nil {
+		return "", 0, fmt.Errorf("failed to get token metadata: %w", err)
 	}
-
-	return decodeString(result), nil
+	meta := c.decodeAndCacheMetadata(chainName, token, elems)
+	return meta.Symbol, meta.Decimals, nil
 }
 
-func (c *Client) getTokenDecimals(ctx context.Context, chainName string, tokenAddress common.Address) (uint8, error) {
-	msg := ethereum.CallMsg{
-		To:   &tokenAddress,
-		Data: decimalsSelector,
+// newEthCall builds an eth_call batch element against the latest block.
+func newEthCall(to common.Address, data []byte, result *hexutil.Bytes) rpc.BatchElem {
+	return rpc.BatchElem{
+		Method: "eth_call",
+		Args: []interface{}{
+			map[string]interface{}{"to": to, "data": hexutil.Bytes(data)},
+			"latest",
+		},
+		Result: result,
 	}
+}
 
-	result, err := c.CallContract(ctx, chainName, msg)
-	if err != nil {
-		return 18, err // Default to 18
+// decodeTokenMetadata interprets [symbol, name, decimals] call results.
+// Metadata is best-effort: many tokens implement these non-standardly, so
+// failed calls leave fields empty and decimals falls back to 18.
+// verified reports whether both the symbol and the decimals were decoded
+// rather than defaulted. An address without code answers every call with
+// empty data, so verified metadata comes from a contract.
+func decodeTokenMetadata(token common.Address, elems []rpc.BatchElem) (meta TokenMetadata, verified bool) {
+	meta = TokenMetadata{Address: token.Hex(), Decimals: 18}
+	var hasSymbol, hasDecimals bool
+	if out, ok := callResult(elems[0]); ok {
+		meta.Symbol = decodeString(out)
+		hasSymbol = meta.Symbol != ""
+	}
+	if out, ok := callResult(elems[1]); ok {
+		meta.Name = decodeString(out)
+	}
+	if out, ok := callResult(elems[2]); ok && len(out) > 0 {
+		meta.Decimals = uint8(new(big.Int).SetBytes(out).Uint64())
+		hasDecimals = true
 	}
+	return meta, hasSymbol && hasDecimals
+}
 
-	if len(result) == 0 {
-		return 18, nil
+// decodeAndCacheMetadata decodes [symbol, name, decimals] results and caches
+// them when every call succeeded. Only verified metadata is persisted;
+// defaulted values, e.g. for an address that isn't a token contract, are
+// kept in memory so a later run looks again. Calls that failed (e.g. rate
+// limiting) are not cached at all so a later request can fill in the real
+// values.
+func (c *Client) decodeAndCacheMetadata(chainName string, token common.Address, elems []rpc.BatchElem) TokenMetadata {
+	meta, verified := decodeTokenMetadata(token, elems)
+	for _, elem := range elems {
+		if elem.Error != nil {
+			return meta
+		}
 	}
+	c.cache.putTokenMetadata(chainName, meta, verified)
+	return meta
+}
 
-	return uint8(new(big.Int).SetBytes(result).Uint64()), nil
+func callResult(elem rpc.BatchElem) ([]byte, bool) {
+	if elem.Error != nil {
+		return nil, false
+	}
+	out, ok := elem.Result.(*hexutil.Bytes)
+	if !ok || out == nil {
+		return nil, false
+	}
+	return *out, true
 }
 
 // decodeString decodes an ABI-encoded string
@@ -161,26 +289,92 @@ func decodeString(data []byte) string {
 	return strings.TrimRight(string(data[64:64+length]), "\x00")
 }
 
-// GetPortfolio returns a portfolio summary for an address across multiple chains
-func (c *Client) GetPortfolio(ctx context.Context, address common.Address, chains []string) (*Portfolio, error) {
+// GetPortfolio returns a portfolio summary for an address across multiple chains.
+// Each chain has its own RPC endpoint, so chains are queried concurrently; within
+// a chain, the native balance and all token balances in tokens[chain] go out as
+// one batch. Per-chain failures are recorded in Errors instead of aborting.
+func (c *Client) GetPortfolio(ctx context.Context, address common.Address, chains []string, tokens map[string][]common.Address) (*Portfolio, error) {
 	portfolio := &Portfolio{
 		Address:        address.Hex(),
 		NativeBalances: make(map[string]*NativeBalance),
 		TokenBalances:  make(map[string][]*TokenBalance),
+		Errors:         make(map[string]string),
 	}
 
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
 	for _, chainName := range chains {
-		balance, err := c.GetNativeBalance(ctx, chainName, address)
-		if err != nil {
-			// Log error but continue with other chains
-			continue
-		}
-		portfolio.NativeBalances[chainName] = balance
+		wg.Add(1)
+		go func(chainName string) {
+			defer wg.Done()
+			native, tokenBalances, err := c.getChainBalances(ctx, chainName, address, tokens[chainName])
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				portfolio.Errors[chainName] = err.Error()
+				return
+			}
+			portfolio.NativeBalances[chainName] = native
+			if len(tokenBalances) > 0 {
+				portfolio.TokenBalances[chainName] = tokenBalances
+			}
+		}(chainName)
 	}
+	wg.Wait()
 
 	return portfolio, nil
 }
 
+// getChainBalances fetches the native balance and token balances for one chain.
+// When tokens are requested, the eth_getBalance call rides along in the same
+// batch as the token calls. A cached native balance skips eth_getBalance.
+func (c *Client) getChainBalances(ctx context.Context, chainName string, address common.Address, tokens []common.Address) (*NativeBalance, []*TokenBalance, error) {
+	config, err := c.GetChainConfig(chainName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	elems, decode := c.tokenBalanceCalls(chainName, address, tokens)
+	tokenCalls := len(elems)
+
+	balance, cached := c.cache.balance(chainName, address)
+	var nativeOut hexutil.Big
+	if !cached {
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{address, "latest"},
+			Result: &nativeOut,
+		})
+	}
+
+	if err := c.BatchCall(ctx, chainName, elems); err != nil {
+		return nil, nil, err
+	}
+	if !cached {
+		if err := elems[tokenCalls].Error; err != nil {
+			return nil, nil, err
+		}
+		balance = nativeOut.ToInt()
+		c.cache.putBalance(chainName, address, balance)
+	}
+
+	tokenBalances, err := decode(elems[:tokenCalls])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	native := &NativeBalance{
+		Chain:    chainName,
+		Symbol:   config.NativeCurrency,
+		Balance:  balance,
+		Decimals: 18,
+	}
+	return native, tokenBalances, nil
+}
+
 // FormatBalance formats a balance with decimals as a human-readable string
 func FormatBalance(balance *big.Int, decimals uint8) string {
 	if balance == nil {
@@ -1,10 +1,17 @@
 package chain
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatBalance(t *testing.T) {
@@ -198,3 +205,227 @@ func TestPortfolio_Structure(t *testing.T) {
 		assert.Len(t, p.NativeBalances, 1)
 	})
 }
+
+func abiString(s string) string {
+	data := make([]byte, 96)
+	data[31] = 32
+	data[63] = byte(len(s))
+	copy(data[64:], s)
+	return hexutil.Encode(data)
+}
+
+func abiUint(v int64) string {
+	return hexutil.Encode(common.LeftPadBytes(big.NewInt(v).Bytes(), 32))
+}
+
+func TestGetTokenBalances(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	weird := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		var call struct {
+			To   common.Address `json:"to"`
+			Data hexutil.Bytes  `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		selector := hexutil.Encode(call.Data[:4])
+
+		if call.To == weird && selector != "0x70a08231" {
+			return nil, fmt.Errorf("execution reverted")
+		}
+		switch selector {
+		case "0x70a08231":
+			return abiUint(2_500_000), nil
+		case "0x95d89b41":
+			return abiString("USDC"), nil
+		case "0x06fdde03":
+			return abiString("USD Coin"), nil
+		case "0x313ce567":
+			return abiUint(6), nil
+		}
+		return nil, fmt.Errorf("unexpected selector %s", selector)
+	})
+	c := newTestClient(t, f)
+
+	t.Run("all calls share one request", func(t *testing.T) {
+		f.requests.Store(0)
+		balances, err := c.GetTokenBalances(context.Background(), "ethereum", common.HexToAddress("0x1"), []common.Address{usdc, weird})
+		require.NoError(t, err)
+		require.Len(t, balances, 2)
+		assert.Equal(t, int32(1), f.requests.Load())
+
+		assert.Equal(t, "USDC", balances[0].Symbol)
+		assert.Equal(t, "USD Coin", balances[0].Name)
+		assert.Equal(t, uint8(6), balances[0].Decimals)
+		assert.Equal(t, "2.500000", FormatBalance(balances[0].Balance, balances[0].Decimals))
+
+		// Metadata failures are tolerated; decimals falls back to 18
+		assert.Equal(t, "", balances[1].Symbol)
+		assert.Equal(t, uint8(18), balances[1].Decimals)
+	})
+
+	t.Run("single token balance", func(t *testing.T) {
+		balance, err := c.GetTokenBalance(context.Background(), "ethereum", usdc, common.HexToAddress("0x1"))
+		require.NoError(t, err)
+		assert.Equal(t, usdc.Hex(), balance.TokenAddress)
+		assert.Equal(t, int64(2_500_000), balance.Balance.Int64())
+	})
+
+	t.Run("token metadata", func(t *testing.T) {
+		f.requests.Store(0)
+		metas, err := c.GetTokenMetadata(context.Background(), "ethereum", []common.Address{usdc, weird})
+		require.NoError(t, err)
+		require.Len(t, metas, 2)
+		assert.Equal(t, int32(1), f.requests.Load())
+		assert.Equal(t, "USDC", metas[0].Symbol)
+		assert.Equal(t, uint8(6), metas[0].Decimals)
+		assert.Equal(t, uint8(18), metas[1].Decimals)
+	})
+}
+
+func TestGetTokenBalances_BalanceErrorFails(t *testing.T) {
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		return nil, fmt.Errorf("execution reverted")
+	})
+	c := newTestClient(t, f)
+
+	_, err := c.GetTokenBalances(context.Background(), "ethereum", common.HexToAddress("0x1"), []common.Address{common.HexToAddress("0x2")})
+	assert.Error(t, err)
+}
+
+func TestGetPortfolio_RecordsChainErrors(t *testing.T) {
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		return "0x2a", nil
+	})
+	c := newTestClient(t, f)
+
+	portfolio, err := c.GetPortfolio(context.Background(), common.HexToAddress("0x1"), []string{"ethereum", "nonexistent"}, nil)
+	require.NoError(t, err)
+	require.Contains(t, portfolio.NativeBalances, "ethereum")
+	assert.Equal(t, int64(42), portfolio.NativeBalances["ethereum"].Balance.Int64())
+	assert.Contains(t, portfolio.Errors["nonexistent"], "unknown chain")
+}
+
+func TestGetTokenSymbolDecimals(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	handle := func(method string, params []json.RawMessage) (interface{}, error) {
+		var call struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		switch hexutil.Encode(call.Data[:4]) {
+		case "0x95d89b41":
+			return abiString("USDC"), nil
+		case "0x313ce567":
+			return abiUint(6), nil
+		}
+		return nil, fmt.Errorf("unexpected call")
+	}
+
+	t.Run("batches metadata calls", func(t *testing.T) {
+		f := newFakeRPC(t, handle)
+		c := newTestClient(t, f)
+
+		symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", token)
+		require.NoError(t, err)
+		assert.Equal(t, "USDC", symbol)
+		assert.Equal(t, uint8(6), decimals)
+		assert.Equal(t, int32(1), f.requests.Load())
+	})
+
+	t.Run("RPC rejecting batches still yields real decimals", func(t *testing.T) {
+		f := newFakeRPC(t, handle)
+		f.rejectBatch = true
+		c := newTestClient(t, f)
+
+		symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", token)
+		require.NoError(t, err)
+		assert.Equal(t, "USDC", symbol)
+		assert.Equal(t, uint8(6), decimals)
+	})
+
+	t.Run("reverting decimals falls back to 18", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return nil, fmt.Errorf("execution reverted")
+		})
+		c := newTestClient(t, f)
+
+		symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", token)
+		require.NoError(t, err)
+		assert.Equal(t, "", symbol)
+		assert.Equal(t, uint8(18), decimals)
+	})
+
+	t.Run("unreachable RPC is an error", func(t *testing.T) {
+		f := newFakeRPC(t, handle)
+		c := newTestClient(t, f)
+		f.server.Close()
+
+		_, _, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", token)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetPortfolio_IncludesTokensInChainBatch(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		if method == "eth_getBalance" {
+			return "0xde0b6b3a7640000", nil // 1 ETH
+		}
+		var call struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		switch hexutil.Encode(call.Data[:4]) {
+		case "0x70a08231":
+			return abiUint(1_000_000), nil
+		case "0x95d89b41":
+			return abiString("USDC"), nil
+		case "0x06fdde03":
+			return abiString("USD Coin"), nil
+		}
+		return abiUint(6), nil
+	})
+	c := newTestClient(t, f)
+
+	portfolio, err := c.GetPortfolio(context.Background(), common.HexToAddress("0x1"), []string{"ethereum"}, map[string][]common.Address{
+		"ethereum": {usdc},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), f.requests.Load())
+	assert.Equal(t, "1.000000", FormatBalance(portfolio.NativeBalances["ethereum"].Balance, 18))
+	require.Len(t, portfolio.TokenBalances["ethereum"], 1)
+	assert.Equal(t, "USDC", portfolio.TokenBalances["ethereum"][0].Symbol)
+	assert.Equal(t, "1.000000", FormatBalance(portfolio.TokenBalances["ethereum"][0].Balance, 6))
+}
+
+func TestGetPortfolio_ConnectsChainsConcurrently(t *testing.T) {
+	const delay = 300 * time.Millisecond
+
+	// Each fake chain is slow to answer eth_chainId, as a cold public RPC would be.
+	slowChain := func(chainID int64) *fakeRPC {
+		return newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			if method == "eth_chainId" {
+				time.Sleep(delay)
+				return hexutil.EncodeBig(big.NewInt(chainID)), nil
+			}
+			return "0x1", nil
+		})
+	}
+	a, b := slowChain(1001), slowChain(1002)
+
+	c := NewClient()
+	t.Cleanup(c.Close)
+	c.AddChain("slow-a", &ChainConfig{Name: "slow-a", ChainID: big.NewInt(1001), RPCURLs: []string{a.server.URL}, NativeCurrency: "A"})
+	c.AddChain("slow-b", &ChainConfig{Name: "slow-b", ChainID: big.NewInt(1002), RPCURLs: []string{b.server.URL}, NativeCurrency: "B"})
+
+	start := time.Now()
+	portfolio, err := c.GetPortfolio(context.Background(), common.HexToAddress("0x1"), []string{"slow-a", "slow-b"}, nil)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Empty(t, portfolio.Errors)
+	assert.Len(t, portfolio.NativeBalances, 2)
+	// Serialized connection setup would take at least 2*delay.
+	assert.Less(t, elapsed, 2*delay-50*time.Millisecond)
+}
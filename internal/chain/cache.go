@@ -0,0 +1,153 @@
+package chain
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultBalanceCacheTTL is how long a native balance is served from memory
+// before the RPC is queried again. Short enough that a user checking after a
+// transfer sees fresh data, long enough to absorb repeated questions within
+// one conversation.
+const DefaultBalanceCacheTTL = 15 * time.Second
+
+type balanceKey struct {
+	chain   string
+	address common.Address
+}
+
+type cachedBalance struct {
+	balance *big.Int
+	expires time.Time
+}
+
+type tokenKey struct {
+	chain string
+	token common.Address
+}
+
+// TokenMetadataStore persists token metadata across runs. Implementations
+// must be safe for concurrent use.
+type TokenMetadataStore interface {
+	GetTokenMetadata(chainName string, token common.Address) (TokenMetadata, bool)
+	PutTokenMetadata(chainName string, meta TokenMetadata) error
+}
+
+// cache holds recently fetched chain data. Native balances expire after ttl;
+// token metadata (symbol/name/decimals) is immutable for all practical
+// purposes, so it is kept for the lifetime of the client and written through
+// to store when one is set.
+type cache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	now      func() time.Time
+	balances map[balanceKey]cachedBalance
+	tokens   map[tokenKey]TokenMetadata
+	store    TokenMetadataStore
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{
+		ttl:      ttl,
+		now:      time.Now,
+		balances: make(map[balanceKey]cachedBalance),
+		tokens:   make(map[tokenKey]TokenMetadata),
+	}
+}
+
+func (c *cache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	if ttl <= 0 {
+		c.balances = make(map[balanceKey]cachedBalance)
+	}
+}
+
+// balance returns a copy of the cached balance so callers cannot mutate the
+// cached value through the returned pointer.
+func (c *cache) balance(chainName string, address common.Address) (*big.Int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.balances[balanceKey{chainName, address}]
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return new(big.Int).Set(entry.balance), true
+}
+
+func (c *cache) putBalance(chainName string, address common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 || balance == nil {
+		return
+	}
+	c.balances[balanceKey{chainName, address}] = cachedBalance{
+		balance: new(big.Int).Set(balance),
+		expires: c.now().Add(c.ttl),
+	}
+}
+
+func (c *cache) dropBalance(chainName string, address common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.balances, balanceKey{chainName, address})
+}
+
+func (c *cache) tokenMetadata(chainName string, token common.Address) (TokenMetadata, bool) {
+	c.mu.Lock()
+	meta, ok := c.tokens[tokenKey{chainName, token}]
+	store := c.store
+	c.mu.Unlock()
+	if ok || store == nil {
+		return meta, ok
+	}
+
+	// Rows with no symbol were defaulted, before only verified metadata
+	// was persisted; look those up again.
+	meta, ok = store.GetTokenMetadata(chainName, token)
+	ok = ok && meta.Symbol != ""
+	if ok {
+		c.mu.Lock()
+		c.tokens[tokenKey{chainName, token}] = meta
+		c.mu.Unlock()
+	}
+	return meta, ok
+}
+
+// putTokenMetadata caches meta in memory and, with persist, in the store.
+// Persistence is best-effort: a failed write only costs a future eth_call.
+func (c *cache) putTokenMetadata(chainName string, meta TokenMetadata, persist bool) {
+	c.mu.Lock()
+	c.tokens[tokenKey{chainName, common.HexToAddress(meta.Address)}] = meta
+	store := c.store
+	c.mu.Unlock()
+	if store != nil && persist {
+		_ = store.PutTokenMetadata(chainName, meta)
+	}
+}
+
+// SetBalanceCacheTTL changes how long native balances are cached. A TTL of
+// zero or less disables balance caching; token metadata is always cached.
+func (c *Client) SetBalanceCacheTTL(ttl time.Duration) {
+	c.cache.setTTL(ttl)
+}
+
+// InvalidateBalance drops any cached native balance for address on a chain,
+// e.g. after sending a transaction from it.
+func (c *Client) InvalidateBalance(chainName string, address common.Address) {
+	c.cache.dropBalance(chainName, address)
+}
+
+// SetTokenMetadataStore makes token metadata lookups fall back to, and write
+// through to, a persistent store.
+func (c *Client) SetTokenMetadataStore(store TokenMetadataStore) {
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	c.cache.store = store
+}
@@ -0,0 +1,232 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_BalanceTTL(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newCache(10 * time.Second)
+	c.now = func() time.Time { return now }
+	addr := common.HexToAddress("0x1")
+
+	c.putBalance("ethereum", addr, big.NewInt(5))
+
+	t.Run("hit before expiry", func(t *testing.T) {
+		bal, ok := c.balance("ethereum", addr)
+		require.True(t, ok)
+		assert.Equal(t, int64(5), bal.Int64())
+	})
+
+	t.Run("returned value is a copy", func(t *testing.T) {
+		bal, _ := c.balance("ethereum", addr)
+		bal.SetInt64(99)
+		again, _ := c.balance("ethereum", addr)
+		assert.Equal(t, int64(5), again.Int64())
+	})
+
+	t.Run("keyed by chain", func(t *testing.T) {
+		_, ok := c.balance("base", addr)
+		assert.False(t, ok)
+	})
+
+	t.Run("miss after expiry", func(t *testing.T) {
+		now = now.Add(10 * time.Second)
+		_, ok := c.balance("ethereum", addr)
+		assert.False(t, ok)
+	})
+}
+
+func TestCache_DisabledTTL(t *testing.T) {
+	c := newCache(time.Minute)
+	addr := common.HexToAddress("0x1")
+	c.putBalance("ethereum", addr, big.NewInt(1))
+
+	c.setTTL(0)
+	_, ok := c.balance("ethereum", addr)
+	assert.False(t, ok, "disabling clears existing entries")
+
+	c.putBalance("ethereum", addr, big.NewInt(1))
+	_, ok = c.balance("ethereum", addr)
+	assert.False(t, ok, "nothing is stored while disabled")
+}
+
+func TestClient_GetBalanceUsesCache(t *testing.T) {
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		return "0x2a", nil
+	})
+	c := newTestClient(t, f)
+	addr := common.HexToAddress("0x1")
+
+	for i := 0; i < 3; i++ {
+		bal, err := c.GetBalance(context.Background(), "ethereum", addr)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), bal.Int64())
+	}
+	assert.Equal(t, int32(1), f.requests.Load())
+
+	c.InvalidateBalance("ethereum", addr)
+	_, err := c.GetBalance(context.Background(), "ethereum", addr)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), f.requests.Load())
+
+	c.SetBalanceCacheTTL(0)
+	_, err = c.GetBalance(context.Background(), "ethereum", addr)
+	require.NoError(t, err)
+	_, err = c.GetBalance(context.Background(), "ethereum", addr)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), f.requests.Load())
+}
+
+func TestClient_TokenMetadataCached(t *testing.T) {
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	var metadataCalls int
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		var call struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		switch hexutil.Encode(call.Data[:4]) {
+		case "0x70a08231":
+			return abiUint(7), nil
+		case "0x95d89b41":
+			metadataCalls++
+			return abiString("USDC"), nil
+		case "0x06fdde03":
+			metadataCalls++
+			return abiString("USD Coin"), nil
+		case "0x313ce567":
+			metadataCalls++
+			return abiUint(6), nil
+		}
+		return nil, fmt.Errorf("unexpected call")
+	})
+	c := newTestClient(t, f)
+	holder := common.HexToAddress("0x1")
+
+	_, err := c.GetTokenBalances(context.Background(), "ethereum", holder, []common.Address{token})
+	require.NoError(t, err)
+	assert.Equal(t, 3, metadataCalls)
+
+	balances, err := c.GetTokenBalances(context.Background(), "ethereum", holder, []common.Address{token})
+	require.NoError(t, err)
+	assert.Equal(t, 3, metadataCalls, "metadata served from cache")
+	assert.Equal(t, "USDC", balances[0].Symbol)
+	assert.Equal(t, uint8(6), balances[0].Decimals)
+	assert.Equal(t, int64(7), balances[0].Balance.Int64())
+
+	symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", token)
+	require.NoError(t, err)
+	assert.Equal(t, "USDC", symbol)
+	assert.Equal(t, uint8(6), decimals)
+	assert.Equal(t, 3, metadataCalls)
+}
+
+type mapTokenStore struct {
+	mu    sync.Mutex
+	metas map[string]TokenMetadata
+}
+
+func (s *mapTokenStore) GetTokenMetadata(chainName string, token common.Address) (TokenMetadata, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta, ok := s.metas[chainName+":"+token.Hex()]
+	return meta, ok
+}
+
+func (s *mapTokenStore) PutTokenMetadata(chainName string, meta TokenMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metas[chainName+":"+meta.Address] = meta
+	return nil
+}
+
+func TestClient_TokenMetadataStore(t *testing.T) {
+	seeded := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	store := &mapTokenStore{metas: map[string]TokenMetadata{
+		"ethereum:" + seeded.Hex(): {Address: seeded.Hex(), Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+	}}
+
+	var calls int
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		calls++
+		var call struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		switch hexutil.Encode(call.Data[:4]) {
+		case "0x95d89b41":
+			return abiString("AA"), nil
+		case "0x06fdde03":
+			return abiString("Token AA"), nil
+		case "0x313ce567":
+			return abiUint(9), nil
+		}
+		return nil, fmt.Errorf("unexpected call")
+	})
+	c := newTestClient(t, f)
+	c.SetTokenMetadataStore(store)
+
+	symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", seeded)
+	require.NoError(t, err)
+	assert.Equal(t, "USDC", symbol)
+	assert.Equal(t, uint8(6), decimals)
+	assert.Equal(t, 0, calls, "stored metadata needs no eth_call")
+
+	symbol, decimals, err = c.GetTokenSymbolDecimals(context.Background(), "ethereum", other)
+	require.NoError(t, err)
+	assert.Equal(t, "AA", symbol)
+	assert.Equal(t, uint8(9), decimals)
+
+	persisted, ok := store.GetTokenMetadata("ethereum", other)
+	require.True(t, ok, "fetched metadata is written through")
+	assert.Equal(t, "Token AA", persisted.Name)
+}
+
+func TestKnownTokens(t *testing.T) {
+	chains := DefaultChains()
+	for chainName, tokens := range KnownTokens() {
+		require.Contains(t, chains, chainName)
+		seen := map[string]bool{}
+		for _, meta := range tokens {
+			assert.Equal(t, common.HexToAddress(meta.Address).Hex(), meta.Address, "%s %s not checksummed", chainName, meta.Symbol)
+			assert.False(t, seen[meta.Symbol], "%s lists %s twice", chainName, meta.Symbol)
+			seen[meta.Symbol] = true
+		}
+	}
+}
+
+func TestClient_TokenMetadataNotPersistedForNonContract(t *testing.T) {
+	eoa := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	store := &mapTokenStore{metas: map[string]TokenMetadata{}}
+	var calls int
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		calls++
+		return "0x", nil // no code: every call returns empty data
+	})
+	c := newTestClient(t, f)
+	c.SetTokenMetadataStore(store)
+
+	symbol, decimals, err := c.GetTokenSymbolDecimals(context.Background(), "ethereum", eoa)
+	require.NoError(t, err)
+	assert.Equal(t, "", symbol)
+	assert.Equal(t, uint8(18), decimals, "defaulted")
+	_, ok := store.GetTokenMetadata("ethereum", eoa)
+	assert.False(t, ok, "defaulted metadata is not persisted")
+
+	_, _, err = c.GetTokenSymbolDecimals(context.Background(), "ethereum", eoa)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "kept in memory for this process")
+}
@@ -2,8 +2,13 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,20 +16,33 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// maxBatchSize caps the number of calls sent in a single JSON-RPC batch.
+// Public RPC providers enforce differing, mostly undocumented batch limits;
+// 50 is a conservative chunk size that keeps well clear of them while still
+// covering a full token list for one chain in a single round trip.
+const maxBatchSize = 50
+
 // Client manages connections to multiple EVM chains
 type Client struct {
 	chains  map[string]*ChainConfig
 	clients map[string]*ethclient.Client
 	mu      sync.RWMutex
+	cache   *cache
+	limiter *rateLimiter
 }
 
 // NewClient creates a new multi-chain client
 func NewClient() *Client {
+	chains := DefaultChains()
+	chains[LocalChain] = LocalChainConfig()
 	return &Client{
-		chains:  DefaultChains(),
+		chains:  chains,
 		clients: make(map[string]*ethclient.Client),
+		cache:   newCache(DefaultBalanceCacheTTL),
+		limiter: newRateLimiter(),
 	}
 }
 
@@ -47,53 +65,99 @@ func (c *Client) GetChainConfig(chainName string) (*ChainConfig, error) {
 	return config, nil
 }
 
-// ListChains returns all configured chains
+// ListChains returns all configured chains except the local preset
 func (c *Client) ListChains() []string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	chains := make([]string, 0, len(c.chains))
 	for name := range c.chains {
+		if name == LocalChain {
+			continue
+		}
+		chains = append(chains, name)
+	}
+	return chains
+}
+
+// ConnectedChains returns the chains with an open RPC connection, sorted.
+func (c *Client) ConnectedChains() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	chains := make([]string, 0, len(c.clients))
+	for name := range c.clients {
 		chains = append(chains, name)
 	}
+	sort.Strings(chains)
 	return chains
 }
 
 // getClient returns an ethclient for the given chain, creating one if needed.
-// Acquires write lock upfront to prevent duplicate connection creation under
-// contention. The simpler locking model is preferred over double-checked locking
-// since connection creation is not a hot path.
+// Dialing and chain ID verification can take several seconds per RPC URL, so
+// they run outside the lock; otherwise concurrent callers for different chains
+// would connect one after another. If two callers race to connect the same
+// chain, the first stored connection wins and the other is closed.
 func (c *Client) getClient(chainName string) (*ethclient.Client, *ChainConfig, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
+	c.mu.RLock()
 	config, configExists := c.chains[chainName]
+	client, clientExists := c.clients[chainName]
+	c.mu.RUnlock()
+
 	if !configExists {
 		return nil, nil, fmt.Errorf("unknown chain: %s", chainName)
 	}
-
-	// Return cached client if available
-	if client, exists := c.clients[chainName]; exists {
+	if clientExists {
 		return client, config, nil
 	}
 
+	client, err := c.dialChain(config)
+	if err != nil {
+		if chainName == LocalChain {
+			return nil, nil, fmt.Errorf("failed to connect to local node at %s: %w (start anvil, or set local.rpc_url and local.chain_id in %s)", config.RPCURLs[0], err, SettingsFile)
+		}
+		return nil, nil, fmt.Errorf("failed to connect to %s: %w", chainName, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.clients[chainName]; ok {
+		client.Close()
+		return existing, config, nil
+	}
+	c.clients[chainName] = client
+	return client, config, nil
+}
+
+// dialChain connects to the first RPC URL of config that answers with the
+// expected chain ID. HTTP requests through the connection are rate limited
+// per URL; websocket URLs are not.
+func (c *Client) dialChain(config *ChainConfig) (*ethclient.Client, error) {
 	var lastErr error
 	for _, rpcURL := range config.RPCURLs {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		client, err := ethclient.DialContext(ctx, rpcURL)
+		rc, err := dialRPC(ctx, rpcURL, func(base http.RoundTripper) http.RoundTripper {
+			return c.limiter.transport(base, rpcURL, config.RPS)
+		})
 		cancel()
 
 		if err != nil {
+			err = redactURL(err)
+			slog.Warn("rpc dial failed", "chain_id", config.ChainIDInt, "rpc", rpcHost(rpcURL), "err", err)
 			lastErr = err
 			continue
 		}
 
+		client := ethclient.NewClient(rc)
+
 		// Verify chain ID
 		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 		chainID, err := client.ChainID(ctx)
 		cancel()
 
 		if err != nil {
+			err = redactURL(err)
+			slog.Warn("rpc chain id check failed", "chain_id", config.ChainIDInt, "rpc", rpcHost(rpcURL), "err", err)
 			client.Close()
 			lastErr = err
 			continue
@@ -102,24 +166,46 @@ func (c *Client) getClient(chainName string) (*ethclient.Client, *ChainConfig, e
 		if chainID.Cmp(config.ChainID) != 0 {
 			client.Close()
 			lastErr = fmt.Errorf("chain ID mismatch: expected %s, got %s", config.ChainID.String(), chainID.String())
+			slog.Warn("rpc on wrong chain", "chain_id", config.ChainIDInt, "rpc", rpcHost(rpcURL), "got", chainID)
 			continue
 		}
 
-		c.clients[chainName] = client
-		return client, config, nil
+		slog.Debug("rpc connected", "chain_id", config.ChainIDInt, "rpc", rpcHost(rpcURL))
+		return client, nil
 	}
 
-	return nil, nil, fmt.Errorf("failed to connect to %s: %w", chainName, lastErr)
+	return nil, lastErr
+}
+
+// rpcHost trims an RPC URL to its host for logging; paths and queries often
+// carry provider API keys. Errors from the RPC transport carry the whole
+// URL, so they are logged through redactURL.
+func rpcHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "(invalid url)"
+	}
+	return u.Scheme + "://" + u.Host
 }
 
-// GetBalance returns the native token balance for an address on a chain
+// GetBalance returns the native token balance for an address on a chain.
+// Results are cached for the balance cache TTL (see SetBalanceCacheTTL).
 func (c *Client) GetBalance(ctx context.Context, chainName string, address common.Address) (*big.Int, error) {
+	if balance, ok := c.cache.balance(chainName, address); ok {
+		return balance, nil
+	}
+
 	client, _, err := c.getClient(chainName)
 	if err != nil {
 		return nil, err
 	}
 
-	return client.BalanceAt(ctx, address, nil)
+	balance, err := client.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.putBalance(chainName, address, balance)
+	return balance, nil
 }
 
 // GetNonce returns the current nonce for an address
@@ -132,6 +218,17 @@ func (c *Client) GetNonce(ctx context.Context, chainName string, address common.
 	return client.PendingNonceAt(ctx, address)
 }
 
+// GetLatestNonce returns the nonce as of the latest block: the number of
+// the address's transactions mined so far, not counting the mempool.
+func (c *Client) GetLatestNonce(ctx context.Context, chainName string, address common.Address) (uint64, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.NonceAt(ctx, address, nil)
+}
+
 // EstimateGas estimates gas for a transaction
 func (c *Client) EstimateGas(ctx context.Context, chainName string, msg ethereum.CallMsg) (uint64, error) {
 	client, _, err := c.getClient(chainName)
@@ -169,7 +266,12 @@ func (c *Client) SendTransaction(ctx context.Context, chainName string, tx *type
 		return err
 	}
 
-	return client.SendTransaction(ctx, tx)
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		slog.Warn("send transaction failed", "chain", chainName, "tx", tx.Hash().Hex(), "err", redactURL(err))
+		return err
+	}
+	slog.Info("transaction sent", "chain", chainName, "tx", tx.Hash().Hex(), "nonce", tx.Nonce())
+	return nil
 }
 
 // WaitMined waits for a transaction to be mined
@@ -206,6 +308,69 @@ func (c *Client) GetTransactionReceipt(ctx context.Context, chainName string, tx
 	return client.TransactionReceipt(ctx, txHash)
 }
 
+// TransactionByHash looks up a transaction, reporting whether it is still
+// pending. A hash the node has never seen returns ethereum.NotFound.
+func (c *Client) TransactionByHash(ctx context.Context, chainName string, txHash common.Hash) (*types.Transaction, bool, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return client.TransactionByHash(ctx, txHash)
+}
+
+// BlockNumber returns the latest block number.
+func (c *Client) BlockNumber(ctx context.Context, chainName string) (uint64, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.BlockNumber(ctx)
+}
+
+// HeaderByNumber returns the header of a block, for its timestamp and base
+// fee.
+func (c *Client) HeaderByNumber(ctx context.Context, chainName string, number *big.Int) (*types.Header, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.HeaderByNumber(ctx, number)
+}
+
+// GetCode returns the deployed bytecode at address; empty for EOAs.
+func (c *Client) GetCode(ctx context.Context, chainName string, address common.Address) ([]byte, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CodeAt(ctx, address, nil)
+}
+
+// FilterLogs runs eth_getLogs.
+func (c *Client) FilterLogs(ctx context.Context, chainName string, q ethereum.FilterQuery) ([]types.Log, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.FilterLogs(ctx, q)
+}
+
+// StorageAt reads one storage slot of address after block, or at the
+// latest block when block is nil.
+func (c *Client) StorageAt(ctx context.Context, chainName string, address common.Address, slot common.Hash, block *big.Int) ([]byte, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.StorageAt(ctx, address, slot, block)
+}
+
 // CallContract executes a contract call (read-only)
 func (c *Client) CallContract(ctx context.Context, chainName string, msg ethereum.CallMsg) ([]byte, error) {
 	client, _, err := c.getClient(chainName)
@@ -216,6 +381,60 @@ func (c *Client) CallContract(ctx context.Context, chainName string, msg ethereu
 	return client.CallContract(ctx, msg, nil)
 }
 
+// CallContractAt executes a read-only call against the state after block,
+// or the latest block when block is nil.
+func (c *Client) CallContractAt(ctx context.Context, chainName string, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CallContract(ctx, msg, block)
+}
+
+// BatchCall sends multiple JSON-RPC calls to a chain in as few HTTP round
+// trips as possible. Some public RPCs reject batch requests outright, so a
+// failed batch is retried as individual calls.
+//
+// Post-condition: on success, each element's Error holds only JSON-RPC errors
+// returned by the node (e.g. execution reverted); transport failures are
+// returned as the error and never recorded per element.
+func (c *Client) BatchCall(ctx context.Context, chainName string, elems []rpc.BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return err
+	}
+	rc := client.Client()
+
+	for start := 0; start < len(elems); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(elems))
+		chunk := elems[start:end]
+		err := rc.BatchCallContext(ctx, chunk)
+		if err == nil {
+			continue
+		}
+		slog.Debug("batch call failed, retrying calls one by one", "chain", chainName, "calls", len(chunk), "err", redactURL(err))
+		for i := range chunk {
+			chunk[i].Error = rc.CallContext(ctx, chunk[i].Result, chunk[i].Method, chunk[i].Args...)
+			if chunk[i].Error != nil && !IsRPCError(chunk[i].Error) {
+				return fmt.Errorf("call %s on %s: %w", chunk[i].Method, chainName, chunk[i].Error)
+			}
+		}
+	}
+	return nil
+}
+
+// IsRPCError reports whether err is an error response from the node (such as
+// a reverted eth_call) rather than a transport or connection failure.
+func IsRPCError(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr)
+}
+
 // Close closes all client connections
 func (c *Client) Close() {
 	c.mu.Lock()
@@ -0,0 +1,200 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// fakeRPC is a minimal JSON-RPC server that answers single and batched
+// requests via handle and counts HTTP round trips.
+type fakeRPC struct {
+	server      *httptest.Server
+	requests    atomic.Int32
+	rejectBatch bool
+	handle      func(method string, params []json.RawMessage) (interface{}, error)
+}
+
+func newFakeRPC(t *testing.T, handle func(method string, params []json.RawMessage) (interface{}, error)) *fakeRPC {
+	t.Helper()
+	f := &fakeRPC{handle: handle}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(body) > 0 && body[0] == '[' {
+			if f.rejectBatch {
+				http.Error(w, "batch requests are not supported", http.StatusBadRequest)
+				return
+			}
+			var reqs []rpcRequest
+			_ = json.Unmarshal(body, &reqs)
+			resps := make([]rpcResponse, len(reqs))
+			for i, req := range reqs {
+				resps[i] = f.respond(req)
+			}
+			_ = json.NewEncoder(w).Encode(resps)
+			return
+		}
+
+		var req rpcRequest
+		_ = json.Unmarshal(body, &req)
+		_ = json.NewEncoder(w).Encode(f.respond(req))
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeRPC) respond(req rpcRequest) rpcResponse {
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	result, err := f.handle(req.Method, req.Params)
+	if err != nil {
+		resp.Error = &rpcError{Code: -32000, Message: err.Error()}
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+// newTestClient returns a Client whose "ethereum" connection points at the fake server.
+func newTestClient(t *testing.T, f *fakeRPC) *Client {
+	t.Helper()
+	rc, err := rpc.DialHTTP(f.server.URL)
+	require.NoError(t, err)
+
+	c := NewClient()
+	c.clients["ethereum"] = ethclient.NewClient(rc)
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestBatchCall(t *testing.T) {
+	t.Run("splits large batches into chunks", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return "0x1", nil
+		})
+		c := newTestClient(t, f)
+
+		elems := make([]rpc.BatchElem, maxBatchSize*2+1)
+		results := make([]hexutil.Big, len(elems))
+		for i := range elems {
+			elems[i] = rpc.BatchElem{Method: "eth_blockNumber", Result: &results[i]}
+		}
+
+		require.NoError(t, c.BatchCall(context.Background(), "ethereum", elems))
+		assert.Equal(t, int32(3), f.requests.Load())
+		for i := range elems {
+			assert.NoError(t, elems[i].Error)
+			assert.Equal(t, int64(1), results[i].ToInt().Int64())
+		}
+	})
+
+	t.Run("empty batch makes no request", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return nil, nil
+		})
+		c := newTestClient(t, f)
+
+		require.NoError(t, c.BatchCall(context.Background(), "ethereum", nil))
+		assert.Equal(t, int32(0), f.requests.Load())
+	})
+
+	t.Run("falls back to single calls when batches are rejected", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			if method == "eth_call" {
+				return nil, fmt.Errorf("execution reverted")
+			}
+			return "0x7", nil
+		})
+		f.rejectBatch = true
+		c := newTestClient(t, f)
+
+		var block hexutil.Big
+		var out hexutil.Bytes
+		elems := []rpc.BatchElem{
+			{Method: "eth_blockNumber", Result: &block},
+			{Method: "eth_call", Args: []interface{}{map[string]interface{}{"to": common.HexToAddress("0x1")}, "latest"}, Result: &out},
+		}
+
+		require.NoError(t, c.BatchCall(context.Background(), "ethereum", elems))
+		assert.Equal(t, int32(3), f.requests.Load()) // rejected batch + 2 single calls
+		assert.NoError(t, elems[0].Error)
+		assert.Equal(t, int64(7), block.ToInt().Int64())
+		assert.True(t, IsRPCError(elems[1].Error))
+	})
+
+	t.Run("transport failure is returned, not recorded per element", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return "0x1", nil
+		})
+		c := newTestClient(t, f)
+		f.server.Close()
+
+		var block hexutil.Big
+		elems := []rpc.BatchElem{{Method: "eth_blockNumber", Result: &block}}
+		err := c.BatchCall(context.Background(), "ethereum", elems)
+		assert.Error(t, err)
+		assert.False(t, IsRPCError(err))
+	})
+
+	t.Run("unknown chain returns error", func(t *testing.T) {
+		c := NewClient()
+		err := c.BatchCall(context.Background(), "nonexistent", []rpc.BatchElem{{Method: "eth_blockNumber"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_DialErrorHidesRPCKey(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	c := NewClient()
+	t.Cleanup(c.Close)
+	cfg := *DefaultChains()["ethereum"]
+	cfg.RPCURLs = []string{srv.URL + "/v2/secret-key"}
+	c.AddChain("ethereum", &cfg)
+
+	_, err := c.BlockNumber(context.Background(), "ethereum")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-key")
+	assert.Contains(t, logs.String(), "rpc chain id check failed")
+	assert.NotContains(t, logs.String(), "secret-key")
+}
@@ -1,6 +1,9 @@
 package chain
 
-import "math/big"
+import (
+	"math/big"
+	"strings"
+)
 
 // ChainConfig holds configuration for an EVM chain.
 // Invariant: ChainID and ChainIDInt must always represent the same value.
@@ -14,6 +17,51 @@ type ChainConfig struct {
 	ExplorerURL    string   `yaml:"explorer_url"`
 	NativeCurrency string   `yaml:"native_currency"`
 	IsTestnet      bool     `yaml:"is_testnet"`
+	// L2Kind selects how the L1 data fee is estimated: L2OPStack, L2Arbitrum,
+	// or "" for chains without one.
+	L2Kind string `yaml:"l2_kind,omitempty"`
+	// RPS caps requests per second to each RPC URL: 0 means DefaultRPS and
+	// a negative value means no limit.
+	RPS float64 `yaml:"rps,omitempty"`
+}
+
+// TxURL returns the block explorer page for a transaction hash, or "" when the
+// chain has no explorer configured.
+func (c *ChainConfig) TxURL(txHash string) string {
+	return c.explorerPath("tx", txHash)
+}
+
+// AddressURL returns the block explorer page for an address or contract, or ""
+// when the chain has no explorer configured.
+func (c *ChainConfig) AddressURL(address string) string {
+	return c.explorerPath("address", address)
+}
+
+func (c *ChainConfig) explorerPath(kind, id string) string {
+	if c == nil || c.ExplorerURL == "" || id == "" {
+		return ""
+	}
+	return strings.TrimRight(c.ExplorerURL, "/") + "/" + kind + "/" + id
+}
+
+// LocalChain names the preset for a development node such as Anvil or
+// Hardhat. It resolves by name but is left out of ListChains, since such a
+// node usually isn't running.
+const LocalChain = "local"
+
+// LocalChainConfig returns the local preset: a node on localhost:8545 with
+// the chain ID Anvil and Hardhat use by default. The node is the user's
+// own, so it isn't rate limited.
+func LocalChainConfig() *ChainConfig {
+	return &ChainConfig{
+		Name:           "Local node",
+		ChainID:        big.NewInt(31337),
+		ChainIDInt:     31337,
+		RPCURLs:        []string{"http://127.0.0.1:8545"},
+		NativeCurrency: "ETH",
+		IsTestnet:      true,
+		RPS:            -1,
+	}
 }
 
 // DefaultChains returns the default chain configurations
@@ -36,6 +84,7 @@ func DefaultChains() map[string]*ChainConfig {
 			ExplorerURL:    "https://basescan.org",
 			NativeCurrency: "ETH",
 			IsTestnet:      false,
+			L2Kind:         L2OPStack,
 		},
 		"arbitrum": {
 			Name:           "Arbitrum One",
@@ -45,6 +94,7 @@ func DefaultChains() map[string]*ChainConfig {
 			ExplorerURL:    "https://arbiscan.io",
 			NativeCurrency: "ETH",
 			IsTestnet:      false,
+			L2Kind:         L2Arbitrum,
 		},
 		"optimism": {
 			Name:           "Optimism",
@@ -54,6 +104,7 @@ func DefaultChains() map[string]*ChainConfig {
 			ExplorerURL:    "https://optimistic.etherscan.io",
 			NativeCurrency: "ETH",
 			IsTestnet:      false,
+			L2Kind:         L2OPStack,
 		},
 		"polygon": {
 			Name:           "Polygon",
@@ -81,6 +132,7 @@ func DefaultChains() map[string]*ChainConfig {
 			ExplorerURL:    "https://sepolia.basescan.org",
 			NativeCurrency: "ETH",
 			IsTestnet:      true,
+			L2Kind:         L2OPStack,
 		},
 	}
 }
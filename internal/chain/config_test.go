@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,7 @@ func TestDefaultChains(t *testing.T) {
 		assert.Equal(t, "https://etherscan.io", eth.ExplorerURL)
 		assert.Equal(t, "ETH", eth.NativeCurrency)
 		assert.False(t, eth.IsTestnet)
+		assert.Empty(t, eth.L2Kind)
 	})
 
 	t.Run("base config is correct", func(t *testing.T) {
@@ -49,6 +51,7 @@ func TestDefaultChains(t *testing.T) {
 		assert.Equal(t, int64(8453), base.ChainID.Int64())
 		assert.Equal(t, "ETH", base.NativeCurrency)
 		assert.False(t, base.IsTestnet)
+		assert.Equal(t, L2OPStack, base.L2Kind)
 	})
 
 	t.Run("arbitrum config is correct", func(t *testing.T) {
@@ -57,6 +60,7 @@ func TestDefaultChains(t *testing.T) {
 
 		assert.Equal(t, "Arbitrum One", arb.Name)
 		assert.Equal(t, int64(42161), arb.ChainID.Int64())
+		assert.Equal(t, L2Arbitrum, arb.L2Kind)
 		assert.Equal(t, "ETH", arb.NativeCurrency)
 		assert.False(t, arb.IsTestnet)
 	})
@@ -120,3 +124,24 @@ func TestDefaultChains(t *testing.T) {
 		}
 	})
 }
+
+func TestChainConfig_ExplorerLinks(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+
+	t.Run("tx and address paths", func(t *testing.T) {
+		cfg := DefaultChains()["base"]
+		assert.Equal(t, "https://basescan.org/tx/"+hash, cfg.TxURL(hash))
+		assert.Equal(t, "https://basescan.org/address/0x1234", cfg.AddressURL("0x1234"))
+	})
+
+	t.Run("trailing slash is normalized", func(t *testing.T) {
+		cfg := &ChainConfig{ExplorerURL: "https://example.org/"}
+		assert.Equal(t, "https://example.org/tx/"+hash, cfg.TxURL(hash))
+	})
+
+	t.Run("no explorer yields empty link", func(t *testing.T) {
+		cfg := &ChainConfig{}
+		assert.Empty(t, cfg.TxURL(hash))
+		assert.Empty(t, cfg.AddressURL("0x1234"))
+	})
+}
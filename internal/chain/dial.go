@@ -0,0 +1,41 @@
+package chain
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/yolodolo42/clifi/internal/netcfg"
+)
+
+// dialRPC connects to an RPC endpoint over HTTP or websocket with the rpc
+// proxy and CA settings. wrap, if set, wraps the HTTP transport.
+func dialRPC(ctx context.Context, rawURL string, wrap func(http.RoundTripper) http.RoundTripper) (*rpc.Client, error) {
+	t, err := netcfg.Transport(netcfg.ScopeRPC)
+	if err != nil {
+		return nil, err
+	}
+	var rt http.RoundTripper = t
+	if wrap != nil {
+		rt = wrap(rt)
+	}
+	return rpc.DialOptions(ctx, rawURL,
+		rpc.WithHTTPClient(&http.Client{Transport: rt}),
+		rpc.WithWebsocketDialer(websocket.Dialer{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			Proxy:           t.Proxy,
+			TLSClientConfig: t.TLSClientConfig,
+		}))
+}
+
+// dialEth is dialRPC for an ethclient.
+func dialEth(ctx context.Context, rawURL string) (*ethclient.Client, error) {
+	rc, err := dialRPC(ctx, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rc), nil
+}
@@ -0,0 +1,94 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlobGasPerBlob is the blob gas consumed by one EIP-4844 blob (128 KiB).
+const BlobGasPerBlob = params.BlobTxBlobGasPerBlob
+
+// GasInfo is a snapshot of a chain's current fee market. BaseFee and
+// BlobBaseFee are nil on chains without EIP-1559 or EIP-4844 respectively.
+type GasInfo struct {
+	BlockNumber uint64
+	BaseFee     *big.Int
+	TipCap      *big.Int
+	GasPrice    *big.Int
+	BlobBaseFee *big.Int
+}
+
+// BlobCost returns the blob fee in wei for n blobs at the current blob base
+// fee, or nil when the chain has no blob market.
+func (g *GasInfo) BlobCost(n int) *big.Int {
+	if g.BlobBaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Mul(g.BlobBaseFee, big.NewInt(int64(n)*BlobGasPerBlob))
+}
+
+// GetGasInfo fetches the latest base fee, priority fee, gas price and blob
+// base fee in one batch. Nodes that predate eth_blobBaseFee still report the
+// blob base fee when the latest header carries excessBlobGas. Rollups have no
+// blob market of their own (they pay L1 blob fees through the L1 data fee),
+// so BlobBaseFee is left nil for them.
+func (c *Client) GetGasInfo(ctx context.Context, chainName string) (*GasInfo, error) {
+	cfg, err := c.GetChainConfig(chainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		header struct {
+			Number        hexutil.Uint64  `json:"number"`
+			BaseFee       *hexutil.Big    `json:"baseFeePerGas"`
+			ExcessBlobGas *hexutil.Uint64 `json:"excessBlobGas"`
+		}
+		gasPrice, tipCap, blobBaseFee hexutil.Big
+	)
+	elems := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{"latest", false}, Result: &header},
+		{Method: "eth_gasPrice", Result: &gasPrice},
+		{Method: "eth_maxPriorityFeePerGas", Result: &tipCap},
+	}
+	if cfg.L2Kind == "" {
+		elems = append(elems, rpc.BatchElem{Method: "eth_blobBaseFee", Result: &blobBaseFee})
+	}
+	if err := c.BatchCall(ctx, chainName, elems); err != nil {
+		return nil, err
+	}
+	if elems[0].Error != nil {
+		return nil, fmt.Errorf("get latest block: %w", elems[0].Error)
+	}
+	if elems[1].Error != nil {
+		return nil, fmt.Errorf("get gas price: %w", elems[1].Error)
+	}
+
+	info := &GasInfo{
+		BlockNumber: uint64(header.Number),
+		GasPrice:    gasPrice.ToInt(),
+	}
+	if header.BaseFee != nil {
+		info.BaseFee = header.BaseFee.ToInt()
+	}
+	// Not every chain implements eth_maxPriorityFeePerGas; legacy-priced
+	// chains just have no tip.
+	if elems[2].Error == nil {
+		info.TipCap = tipCap.ToInt()
+	}
+	if cfg.L2Kind == "" {
+		switch {
+		case elems[3].Error == nil:
+			info.BlobBaseFee = blobBaseFee.ToInt()
+		case header.ExcessBlobGas != nil:
+			info.BlobBaseFee = eip4844.CalcBlobFee(uint64(*header.ExcessBlobGas))
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,92 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGasInfo(t *testing.T) {
+	t.Run("reads blob base fee", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			switch method {
+			case "eth_getBlockByNumber":
+				return map[string]string{"number": "0x10", "baseFeePerGas": "0x3b9aca00"}, nil
+			case "eth_gasPrice":
+				return "0x77359400", nil
+			case "eth_maxPriorityFeePerGas":
+				return "0x5f5e100", nil
+			case "eth_blobBaseFee":
+				return "0x2", nil
+			}
+			return nil, fmt.Errorf("unexpected method %s", method)
+		})
+		c := newTestClient(t, f)
+
+		info, err := c.GetGasInfo(context.Background(), "ethereum")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), f.requests.Load())
+		assert.Equal(t, uint64(16), info.BlockNumber)
+		assert.Equal(t, int64(1_000_000_000), info.BaseFee.Int64())
+		assert.Equal(t, int64(2_000_000_000), info.GasPrice.Int64())
+		assert.Equal(t, int64(100_000_000), info.TipCap.Int64())
+		assert.Equal(t, int64(2), info.BlobBaseFee.Int64())
+		assert.Equal(t, int64(2*3*BlobGasPerBlob), info.BlobCost(3).Int64())
+	})
+
+	t.Run("derives blob base fee from header when method is missing", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			switch method {
+			case "eth_getBlockByNumber":
+				return map[string]string{"number": "0x10", "baseFeePerGas": "0x1", "excessBlobGas": "0x0"}, nil
+			case "eth_gasPrice", "eth_maxPriorityFeePerGas":
+				return "0x1", nil
+			}
+			return nil, fmt.Errorf("the method %s does not exist", method)
+		})
+		c := newTestClient(t, f)
+
+		info, err := c.GetGasInfo(context.Background(), "ethereum")
+		require.NoError(t, err)
+		// Zero excess blob gas prices blobs at the 1 wei minimum.
+		assert.Equal(t, int64(1), info.BlobBaseFee.Int64())
+	})
+
+	t.Run("no blob market", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			switch method {
+			case "eth_getBlockByNumber":
+				return map[string]string{"number": "0x10"}, nil
+			case "eth_gasPrice":
+				return "0x1", nil
+			}
+			return nil, fmt.Errorf("the method %s does not exist", method)
+		})
+		c := newTestClient(t, f)
+
+		info, err := c.GetGasInfo(context.Background(), "ethereum")
+		require.NoError(t, err)
+		assert.Nil(t, info.BaseFee)
+		assert.Nil(t, info.TipCap)
+		assert.Nil(t, info.BlobBaseFee)
+		assert.Nil(t, info.BlobCost(1))
+	})
+
+	t.Run("gas price error fails", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			if method == "eth_getBlockByNumber" {
+				return map[string]string{"number": "0x10"}, nil
+			}
+			return nil, fmt.Errorf("boom")
+		})
+		c := newTestClient(t, f)
+
+		_, err := c.GetGasInfo(context.Background(), "ethereum")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "get gas price")
+	})
+}
@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// L2 stacks whose fees include an L1 data component.
+const (
+	L2OPStack  = "op-stack"
+	L2Arbitrum = "arbitrum"
+)
+
+var (
+	// opGasPriceOracle is the OP-stack GasPriceOracle predeploy.
+	opGasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+	// arbNodeInterface is Arbitrum's virtual NodeInterface contract, only
+	// reachable through eth_call.
+	arbNodeInterface = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+	// getL1Fee(bytes)
+	getL1FeeSelector = common.Hex2Bytes("49948e0e")
+	// gasEstimateL1Component(address,bool,bytes)
+	gasEstimateL1ComponentSelector = common.Hex2Bytes("77d488a2")
+)
+
+// OPStackL1Fee returns the L1 data fee in wei that an OP-stack chain charges
+// on top of L2 execution gas for a transaction. unsignedTx is the unsigned
+// RLP-encoded transaction; the oracle accounts for the signature itself.
+func (c *Client) OPStackL1Fee(ctx context.Context, chainName string, unsignedTx []byte) (*big.Int, error) {
+	data := append(append([]byte{}, getL1FeeSelector...), encodeBytesArg(unsignedTx, 1)...)
+	out, err := c.CallContract(ctx, chainName, ethereum.CallMsg{To: &opGasPriceOracle, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("GasPriceOracle.getL1Fee: %w", err)
+	}
+	if len(out) < 32 {
+		return nil, fmt.Errorf("GasPriceOracle.getL1Fee: short result (%d bytes)", len(out))
+	}
+	return new(big.Int).SetBytes(out[:32]), nil
+}
+
+// ArbitrumL1Gas returns the portion of a transaction's gas that pays for
+// posting its data to L1, and the L2 base fee it is priced at. Unlike the
+// OP stack, Arbitrum folds this into the gas limit eth_estimateGas returns.
+func (c *Client) ArbitrumL1Gas(ctx context.Context, chainName string, to common.Address, calldata []byte) (uint64, *big.Int, error) {
+	data := append([]byte{}, gasEstimateL1ComponentSelector...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	data = append(data, make([]byte, 32)...) // contractCreation = false
+	data = append(data, encodeBytesArg(calldata, 3)...)
+
+	out, err := c.CallContract(ctx, chainName, ethereum.CallMsg{To: &arbNodeInterface, Data: data})
+	if err != nil {
+		return 0, nil, fmt.Errorf("NodeInterface.gasEstimateL1Component: %w", err)
+	}
+	// Returns (uint64 gasEstimateForL1, uint256 baseFee, uint256 l1BaseFeeEstimate).
+	if len(out) < 64 {
+		return 0, nil, fmt.Errorf("NodeInterface.gasEstimateL1Component: short result (%d bytes)", len(out))
+	}
+	gas := new(big.Int).SetBytes(out[:32])
+	if !gas.IsUint64() {
+		return 0, nil, fmt.Errorf("NodeInterface.gasEstimateL1Component: gas out of range")
+	}
+	return gas.Uint64(), new(big.Int).SetBytes(out[32:64]), nil
+}
+
+// encodeBytesArg ABI-encodes a bytes argument that is the last of numArgs
+// arguments: its offset word followed by the length-prefixed, zero-padded
+// tail.
+func encodeBytesArg(b []byte, numArgs int) []byte {
+	offset := big.NewInt(int64(numArgs * 32))
+	out := common.LeftPadBytes(offset.Bytes(), 32)
+	out = append(out, common.LeftPadBytes(big.NewInt(int64(len(b))).Bytes(), 32)...)
+	out = append(out, b...)
+	if pad := len(b) % 32; pad != 0 {
+		out = append(out, make([]byte, 32-pad)...)
+	}
+	return out
+}
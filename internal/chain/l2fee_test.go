@@ -0,0 +1,91 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ethCallArgs struct {
+	To    common.Address `json:"to"`
+	Input hexutil.Bytes  `json:"input"`
+}
+
+func TestOPStackL1Fee(t *testing.T) {
+	var got ethCallArgs
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		_ = json.Unmarshal(params[0], &got)
+		return abiUint(42_000_000_000), nil
+	})
+	c := newTestClient(t, f)
+
+	raw := []byte{0x02, 0xaa, 0xbb}
+	fee, err := c.OPStackL1Fee(context.Background(), "ethereum", raw)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42_000_000_000), fee.Int64())
+
+	assert.Equal(t, opGasPriceOracle, got.To)
+	want := "0x49948e0e" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"02aabb0000000000000000000000000000000000000000000000000000000000"
+	assert.Equal(t, want, hexutil.Encode(got.Input))
+}
+
+func TestArbitrumL1Gas(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	var got ethCallArgs
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		_ = json.Unmarshal(params[0], &got)
+		out := common.LeftPadBytes(big.NewInt(1_500).Bytes(), 32)
+		out = append(out, common.LeftPadBytes(big.NewInt(10_000_000).Bytes(), 32)...)
+		out = append(out, common.LeftPadBytes(big.NewInt(30_000_000_000).Bytes(), 32)...)
+		return hexutil.Encode(out), nil
+	})
+	c := newTestClient(t, f)
+
+	gas, baseFee, err := c.ArbitrumL1Gas(context.Background(), "ethereum", to, []byte{0xde, 0xad})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_500), gas)
+	assert.Equal(t, int64(10_000_000), baseFee.Int64())
+
+	assert.Equal(t, arbNodeInterface, got.To)
+	want := "0x77d488a2" +
+		"00000000000000000000000000000000000000000000000000000000000000aa" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000060" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"dead000000000000000000000000000000000000000000000000000000000000"
+	assert.Equal(t, want, hexutil.Encode(got.Input))
+}
+
+func TestL1Fee_Errors(t *testing.T) {
+	t.Run("call error is wrapped", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return nil, fmt.Errorf("execution reverted")
+		})
+		c := newTestClient(t, f)
+
+		_, err := c.OPStackL1Fee(context.Background(), "ethereum", []byte{0x02})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "getL1Fee")
+	})
+
+	t.Run("short result", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return abiUint(1), nil
+		})
+		c := newTestClient(t, f)
+
+		_, _, err := c.ArbitrumL1Gas(context.Background(), "ethereum", common.Address{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "short result")
+	})
+}
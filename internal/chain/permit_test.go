@@ -0,0 +1,55 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPermitInfo(t *testing.T) {
+	permitToken := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	separator := "0x06c37168a7db5138defc7866392bb87a741f9b3d104deb5094588ce041cae335"
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		var call struct {
+			To   common.Address `json:"to"`
+			Data hexutil.Bytes  `json:"data"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		selector := hexutil.Encode(call.Data[:4])
+		if call.To != permitToken && selector != "0x06fdde03" {
+			return nil, fmt.Errorf("execution reverted")
+		}
+		switch selector {
+		case "0x06fdde03":
+			return abiString("USD Coin"), nil
+		case "0x54fd4d50":
+			return abiString("2"), nil
+		case "0x3644e515":
+			return separator, nil
+		case "0x7ecebe00":
+			return abiUint(4), nil
+		case "0xe94a0102":
+			return abiUint(0), nil
+		}
+		return nil, fmt.Errorf("unexpected selector %s", selector)
+	})
+	c := newTestClient(t, f)
+	owner := common.HexToAddress("0x1")
+
+	info, err := c.GetPermitInfo(context.Background(), "ethereum", permitToken, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "USD Coin", info.Name)
+	assert.Equal(t, "2", info.Version)
+	assert.Equal(t, separator, info.DomainSeparator.Hex())
+	assert.Equal(t, int64(4), info.Nonce.Int64())
+	assert.True(t, info.TransferAuthorization)
+
+	_, err = c.GetPermitInfo(context.Background(), "ethereum", common.HexToAddress("0xbb"), owner)
+	assert.ErrorIs(t, err, ErrPermitUnsupported)
+}
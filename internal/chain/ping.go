@@ -0,0 +1,63 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// RPCPing is the result of probing one RPC endpoint.
+type RPCPing struct {
+	URL         string
+	BlockNumber uint64
+	// Latency is the eth_blockNumber round trip, excluding the dial.
+	Latency time.Duration
+	Err     error
+}
+
+// PingRPC checks that rawURL serves chain want and reports its head block
+// and latency.
+func PingRPC(ctx context.Context, rawURL string, want *big.Int) RPCPing {
+	p := RPCPing{URL: rawURL}
+	client, err := dialEth(ctx, rawURL)
+	if err != nil {
+		p.Err = fmt.Errorf("connect: %w", err)
+		return p
+	}
+	defer client.Close()
+
+	got, err := client.ChainID(ctx)
+	if err != nil {
+		p.Err = fmt.Errorf("eth_chainId: %w", err)
+		return p
+	}
+	if got.Cmp(want) != 0 {
+		p.Err = fmt.Errorf("RPC is for chain %s, expected %s", got, want)
+		return p
+	}
+
+	start := time.Now()
+	p.BlockNumber, p.Err = client.BlockNumber(ctx)
+	p.Latency = time.Since(start)
+	return p
+}
+
+// PingChain probes every RPC URL of cfg concurrently, each with its own
+// timeout, and returns the results in cfg.RPCURLs order.
+func PingChain(ctx context.Context, cfg *ChainConfig, timeout time.Duration) []RPCPing {
+	pings := make([]RPCPing, len(cfg.RPCURLs))
+	var wg sync.WaitGroup
+	for i, url := range cfg.RPCURLs {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			pings[i] = PingRPC(pingCtx, url, cfg.ChainID)
+		}(i, url)
+	}
+	wg.Wait()
+	return pings
+}
@@ -0,0 +1,39 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingChain(t *testing.T) {
+	good := newFakeRPC(t, func(method string, _ []json.RawMessage) (interface{}, error) {
+		switch method {
+		case "eth_chainId":
+			return "0x2105", nil
+		case "eth_blockNumber":
+			return "0x10", nil
+		}
+		return nil, fmt.Errorf("unexpected %s", method)
+	})
+	wrong := newFakeRPC(t, func(method string, _ []json.RawMessage) (interface{}, error) {
+		return "0x1", nil
+	})
+
+	cfg := &ChainConfig{ChainID: big.NewInt(8453), RPCURLs: []string{good.server.URL, wrong.server.URL}}
+	pings := PingChain(context.Background(), cfg, 5*time.Second)
+	require.Len(t, pings, 2)
+
+	assert.NoError(t, pings[0].Err)
+	assert.Equal(t, good.server.URL, pings[0].URL)
+	assert.Equal(t, uint64(16), pings[0].BlockNumber)
+	assert.Positive(t, pings[0].Latency)
+
+	assert.ErrorContains(t, pings[1].Err, "chain 1, expected 8453")
+}
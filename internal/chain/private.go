@@ -0,0 +1,148 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoPrivateStatus is returned by TxStatus for relays without a status API.
+var ErrNoPrivateStatus = errors.New("relay does not report private transaction status")
+
+// Private transaction statuses reported by Flashbots Protect.
+const (
+	PrivateTxPending   = "PENDING"
+	PrivateTxIncluded  = "INCLUDED"
+	PrivateTxFailed    = "FAILED"
+	PrivateTxCancelled = "CANCELLED"
+	PrivateTxUnknown   = "UNKNOWN"
+)
+
+// PrivateRelay is an RPC endpoint that forwards transactions straight to
+// block builders instead of the public mempool, so they cannot be sandwiched.
+type PrivateRelay struct {
+	Name      string
+	RPCURL    string
+	StatusURL string  // base URL of the per-tx status API; "" if none
+	ChainIDs  []int64 // chains the relay accepts; empty means any
+}
+
+// PrivateRelays returns the built-in relays, keyed by the name accepted in
+// CLIFI_PRIVATE_TX.
+func PrivateRelays() map[string]*PrivateRelay {
+	return map[string]*PrivateRelay{
+		"flashbots": {
+			Name:      "Flashbots Protect",
+			RPCURL:    "https://rpc.flashbots.net/fast",
+			StatusURL: "https://protect.flashbots.net/tx",
+			ChainIDs:  []int64{1},
+		},
+		"flashbots-sepolia": {
+			Name:      "Flashbots Protect (Sepolia)",
+			RPCURL:    "https://rpc-sepolia.flashbots.net",
+			StatusURL: "https://protect-sepolia.flashbots.net/tx",
+			ChainIDs:  []int64{11155111},
+		},
+		"mevblocker": {
+			Name:     "MEV Blocker",
+			RPCURL:   "https://rpc.mevblocker.io",
+			ChainIDs: []int64{1},
+		},
+	}
+}
+
+// LookupPrivateRelay resolves a built-in relay name or a custom https RPC URL.
+func LookupPrivateRelay(nameOrURL string) (*PrivateRelay, error) {
+	nameOrURL = strings.TrimSpace(nameOrURL)
+	if relay, ok := PrivateRelays()[strings.ToLower(nameOrURL)]; ok {
+		return relay, nil
+	}
+	if strings.HasPrefix(nameOrURL, "https://") {
+		return &PrivateRelay{Name: nameOrURL, RPCURL: nameOrURL}, nil
+	}
+	return nil, fmt.Errorf("unknown private relay %q (use flashbots, flashbots-sepolia, mevblocker or an https URL)", nameOrURL)
+}
+
+// Supports reports whether the relay accepts transactions for chainID.
+func (r *PrivateRelay) Supports(chainID *big.Int) bool {
+	if len(r.ChainIDs) == 0 {
+		return true
+	}
+	return chainID != nil && chainID.IsInt64() && slices.Contains(r.ChainIDs, chainID.Int64())
+}
+
+// SendPrivateTransaction submits a signed transaction through relay instead
+// of the chain's public RPC.
+func (c *Client) SendPrivateTransaction(ctx context.Context, chainName string, relay *PrivateRelay, tx *types.Transaction) error {
+	cfg, err := c.GetChainConfig(chainName)
+	if err != nil {
+		return err
+	}
+	if !relay.Supports(cfg.ChainID) {
+		return fmt.Errorf("%s does not support %s", relay.Name, chainName)
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	rc, err := dialRPC(ctx, relay.RPCURL, nil)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", relay.Name, err)
+	}
+	defer rc.Close()
+
+	var hash common.Hash
+	if err := rc.CallContext(ctx, &hash, "eth_sendRawTransaction", hexutil.Encode(raw)); err != nil {
+		return fmt.Errorf("%s: %w", relay.Name, err)
+	}
+	return nil
+}
+
+// PrivateTxStatus is a relay's view of a privately submitted transaction.
+type PrivateTxStatus struct {
+	Status         string `json:"status"`
+	Hash           string `json:"hash"`
+	MaxBlockNumber uint64 `json:"maxBlockNumber"`
+	SimError       string `json:"simError,omitempty"`
+}
+
+var privateStatusClient = &http.Client{Timeout: 10 * time.Second}
+
+// TxStatus asks the relay what happened to a transaction it received. A
+// private transaction never shows up in the public mempool, so until it is
+// included this is the only way to tell pending from dropped.
+func (r *PrivateRelay) TxStatus(ctx context.Context, txHash common.Hash) (*PrivateTxStatus, error) {
+	if r.StatusURL == "" {
+		return nil, ErrNoPrivateStatus
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(r.StatusURL, "/")+"/"+txHash.Hex(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := privateStatusClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s status: %w", r.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s status: HTTP %d", r.Name, resp.StatusCode)
+	}
+	var status PrivateTxStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("%s status: %w", r.Name, err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,84 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupPrivateRelay(t *testing.T) {
+	t.Run("built-in names", func(t *testing.T) {
+		r, err := LookupPrivateRelay("Flashbots")
+		require.NoError(t, err)
+		assert.Equal(t, "Flashbots Protect", r.Name)
+		assert.True(t, r.Supports(big.NewInt(1)))
+		assert.False(t, r.Supports(big.NewInt(8453)))
+	})
+
+	t.Run("custom URL supports any chain", func(t *testing.T) {
+		r, err := LookupPrivateRelay("https://relay.example")
+		require.NoError(t, err)
+		assert.Equal(t, "https://relay.example", r.RPCURL)
+		assert.Empty(t, r.StatusURL)
+		assert.True(t, r.Supports(big.NewInt(8453)))
+	})
+
+	t.Run("rejects unknown names and plain http", func(t *testing.T) {
+		_, err := LookupPrivateRelay("nope")
+		assert.Error(t, err)
+		_, err = LookupPrivateRelay("http://relay.example")
+		assert.Error(t, err)
+	})
+}
+
+func TestSendPrivateTransaction(t *testing.T) {
+	var sent string
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		if method == "eth_sendRawTransaction" {
+			_ = json.Unmarshal(params[0], &sent)
+		}
+		return common.Hash{}.Hex(), nil
+	})
+	c := NewClient()
+	defer c.Close()
+
+	signed := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), To: &common.Address{}})
+	relay := &PrivateRelay{Name: "test", RPCURL: f.server.URL, ChainIDs: []int64{1}}
+
+	require.NoError(t, c.SendPrivateTransaction(context.Background(), "ethereum", relay, signed))
+	raw, _ := signed.MarshalBinary()
+	assert.Equal(t, common.Bytes2Hex(raw), sent[2:])
+
+	err := c.SendPrivateTransaction(context.Background(), "base", relay, signed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support base")
+}
+
+func TestPrivateRelay_TxStatus(t *testing.T) {
+	hash := common.HexToHash("0xabc")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tx/"+hash.Hex() {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"PENDING","hash":"` + hash.Hex() + `","maxBlockNumber":123}`))
+	}))
+	defer srv.Close()
+
+	relay := &PrivateRelay{Name: "test", StatusURL: srv.URL + "/tx/"}
+	status, err := relay.TxStatus(context.Background(), hash)
+	require.NoError(t, err)
+	assert.Equal(t, PrivateTxPending, status.Status)
+	assert.Equal(t, uint64(123), status.MaxBlockNumber)
+
+	_, err = (&PrivateRelay{Name: "none"}).TxStatus(context.Background(), hash)
+	assert.ErrorIs(t, err, ErrNoPrivateStatus)
+}
@@ -0,0 +1,148 @@
+package chain
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRPS is the request rate allowed per RPC endpoint when chains.yaml
+// sets none. Public endpoints start answering 429 somewhere above this.
+const DefaultRPS = 10
+
+// throttleLogAfter is how long a request has to queue before the wait is
+// logged at info level rather than debug.
+const throttleLogAfter = time.Second
+
+// ThrottleStats counts how often requests to one endpoint had to wait for
+// the rate limiter.
+type ThrottleStats struct {
+	Requests  int64
+	Throttled int64
+	Waited    time.Duration
+}
+
+// rateLimiter holds a token bucket per RPC endpoint. Requests queue for a
+// token instead of failing, so a burst of lookups is spread out rather than
+// tripping the provider's own limit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	stats    map[string]*ThrottleStats
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		limiters: make(map[string]*rate.Limiter),
+		stats:    make(map[string]*ThrottleStats),
+	}
+}
+
+// limitFor converts a configured RPS to a rate.Limit: zero means
+// DefaultRPS and a negative value disables limiting.
+func limitFor(rps float64) rate.Limit {
+	switch {
+	case rps < 0:
+		return rate.Inf
+	case rps == 0:
+		return DefaultRPS
+	}
+	return rate.Limit(rps)
+}
+
+// limiter returns the bucket for endpoint, updating its rate when the
+// configuration changed. The burst is one second's worth of requests.
+func (r *rateLimiter) limiter(endpoint string, rps float64) *rate.Limiter {
+	limit := limitFor(rps)
+	burst := 1
+	if limit != rate.Inf {
+		burst = max(1, int(math.Ceil(float64(limit))))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.limiters[endpoint]
+	if !ok {
+		l = rate.NewLimiter(limit, burst)
+		r.limiters[endpoint] = l
+		return l
+	}
+	if l.Limit() != limit {
+		l.SetLimit(limit)
+		l.SetBurst(burst)
+	}
+	return l
+}
+
+// wait blocks until endpoint may be called or ctx ends.
+func (r *rateLimiter) wait(ctx context.Context, endpoint string, rps float64) error {
+	l := r.limiter(endpoint, rps)
+	start := time.Now()
+	err := l.Wait(ctx)
+	waited := time.Since(start)
+
+	host := rpcHost(endpoint)
+	r.mu.Lock()
+	s, ok := r.stats[host]
+	if !ok {
+		s = &ThrottleStats{}
+		r.stats[host] = s
+	}
+	s.Requests++
+	// Wait returns at once when a token is free; anything measurable
+	// means the request was queued.
+	if waited >= time.Millisecond {
+		s.Throttled++
+		s.Waited += waited
+	}
+	r.mu.Unlock()
+
+	if waited >= throttleLogAfter {
+		slog.Info("rpc request throttled", "rpc", host, "waited", waited.Round(time.Millisecond))
+	} else if waited >= time.Millisecond {
+		slog.Debug("rpc request throttled", "rpc", host, "waited", waited.Round(time.Millisecond))
+	}
+	return err
+}
+
+func (r *rateLimiter) snapshot() map[string]ThrottleStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]ThrottleStats, len(r.stats))
+	for host, s := range r.stats {
+		out[host] = *s
+	}
+	return out
+}
+
+// transport wraps base so requests to endpoint take a token first. A
+// JSON-RPC batch is one HTTP request and takes one token.
+func (r *rateLimiter) transport(base http.RoundTripper, endpoint string, rps float64) http.RoundTripper {
+	return &limitedTransport{
+		base: base,
+		wait: func(ctx context.Context) error { return r.wait(ctx, endpoint, rps) },
+	}
+}
+
+type limitedTransport struct {
+	base http.RoundTripper
+	wait func(context.Context) error
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ThrottleStats reports, per RPC host, how many requests were sent and how
+// long they queued for the rate limiter. Hosts never called are absent.
+func (c *Client) ThrottleStats() map[string]ThrottleStats {
+	return c.limiter.snapshot()
+}
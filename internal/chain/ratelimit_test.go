@@ -0,0 +1,73 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_QueuesPastBurst(t *testing.T) {
+	r := newRateLimiter()
+	const endpoint = "https://rpc.example/v2/secret-key"
+
+	// 20 rps allows a burst of 20; the 21st request queues ~50ms.
+	start := time.Now()
+	for range 21 {
+		require.NoError(t, r.wait(context.Background(), endpoint, 20))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	stats := r.snapshot()
+	require.Contains(t, stats, "https://rpc.example", "stats are keyed by host, without the key in the path")
+	s := stats["https://rpc.example"]
+	assert.Equal(t, int64(21), s.Requests)
+	assert.Equal(t, int64(1), s.Throttled)
+	assert.Greater(t, s.Waited, time.Duration(0))
+}
+
+func TestRateLimiter_NegativeDisables(t *testing.T) {
+	r := newRateLimiter()
+	for range 100 {
+		require.NoError(t, r.wait(context.Background(), "https://rpc.example", -1))
+	}
+	assert.Zero(t, r.snapshot()["https://rpc.example"].Throttled)
+}
+
+func TestRateLimiter_FailsWhenQueueOutlastsContext(t *testing.T) {
+	r := newRateLimiter()
+	require.NoError(t, r.wait(context.Background(), "https://rpc.example", 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, r.wait(ctx, "https://rpc.example", 1))
+}
+
+func TestClient_DialUsesRateLimiter(t *testing.T) {
+	f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		switch method {
+		case "eth_chainId":
+			return "0x1", nil
+		case "eth_blockNumber":
+			return "0x10", nil
+		}
+		return nil, nil
+	})
+
+	c := NewClient()
+	t.Cleanup(c.Close)
+	cfg := *DefaultChains()["ethereum"]
+	cfg.RPCURLs = []string{f.server.URL}
+	cfg.RPS = 1
+	c.AddChain("ethereum", &cfg)
+
+	_, err := c.BlockNumber(context.Background(), "ethereum")
+	require.NoError(t, err)
+
+	stats := c.ThrottleStats()[rpcHost(f.server.URL)]
+	assert.Equal(t, int64(2), stats.Requests, "eth_chainId and eth_blockNumber")
+	assert.Equal(t, int64(1), stats.Throttled, "1 rps leaves no token for the second request")
+}
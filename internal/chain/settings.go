@@ -0,0 +1,175 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsFile is the chain config file in the data directory.
+const SettingsFile = "chains.yaml"
+
+// Settings is the user's chain configuration, written by the setup wizard.
+type Settings struct {
+	// Enabled limits clifi to these chains; empty means all of them.
+	Enabled []string `yaml:"enabled,omitempty"`
+	// RPCURLs replaces a chain's default endpoints, e.g. with a private
+	// RPC that is faster and doesn't rate limit.
+	RPCURLs map[string][]string `yaml:"rpc_urls,omitempty"`
+	// RPS caps requests per second to each RPC endpoint (0 means
+	// DefaultRPS, negative means no limit). ChainRPS overrides it per
+	// chain, e.g. for a paid RPC with a higher allowance.
+	RPS      float64            `yaml:"rpc_rps,omitempty"`
+	ChainRPS map[string]float64 `yaml:"chain_rps,omitempty"`
+	// Local points the local preset at another node, e.g. an Anvil fork
+	// that kept the forked network's chain ID.
+	Local *LocalSettings `yaml:"local,omitempty"`
+}
+
+// LocalSettings overrides the local preset's endpoint and chain ID.
+type LocalSettings struct {
+	RPCURL  string `yaml:"rpc_url,omitempty"`
+	ChainID int64  `yaml:"chain_id,omitempty"`
+}
+
+// LocalConfig returns the local preset with any overrides applied.
+func (s *Settings) LocalConfig() *ChainConfig {
+	cfg := LocalChainConfig()
+	if s.Local == nil {
+		return cfg
+	}
+	if s.Local.RPCURL != "" {
+		cfg.RPCURLs = []string{s.Local.RPCURL}
+	}
+	if s.Local.ChainID != 0 {
+		cfg.ChainID = big.NewInt(s.Local.ChainID)
+		cfg.ChainIDInt = s.Local.ChainID
+	}
+	return cfg
+}
+
+// LoadSettings reads dataDir/chains.yaml. A missing file yields empty
+// settings, which leave the defaults unchanged.
+func LoadSettings(dataDir string) (*Settings, error) {
+	s := &Settings{}
+	if dataDir == "" {
+		return s, nil
+	}
+	path := filepath.Join(dataDir, SettingsFile)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the settings to dataDir/chains.yaml. RPC URLs can embed API
+// keys, so the file is private to the user.
+func (s *Settings) Save(dataDir string) error {
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dataDir, SettingsFile), b, 0600)
+}
+
+// Apply returns chains with the settings applied: chains not enabled are
+// dropped and RPC and rate limit overrides replace the defaults. Unknown
+// chain names are ignored so a stale file can't break startup.
+func (s *Settings) Apply(chains map[string]*ChainConfig) map[string]*ChainConfig {
+	out := make(map[string]*ChainConfig, len(chains))
+	for name, cfg := range chains {
+		if len(s.Enabled) > 0 && !slices.Contains(s.Enabled, name) {
+			continue
+		}
+		urls := s.RPCURLs[name]
+		rps, hasRPS := s.ChainRPS[name]
+		if !hasRPS {
+			rps = s.RPS
+		}
+		if len(urls) > 0 || rps != 0 {
+			c := *cfg
+			if len(urls) > 0 {
+				c.RPCURLs = slices.Clone(urls)
+			}
+			if rps != 0 {
+				c.RPS = rps
+			}
+			cfg = &c
+		}
+		out[name] = cfg
+	}
+	return out
+}
+
+// NewConfiguredClient returns a client for the chains in
+// dataDir/chains.yaml.
+func NewConfiguredClient(dataDir string) (*Client, error) {
+	c := NewClient()
+	s, err := LoadSettings(dataDir)
+	if err != nil {
+		return c, err
+	}
+	c.ApplySettings(s)
+	return c, nil
+}
+
+// ApplySettings replaces the client's chains with the defaults as
+// modified by s. Open connections are dropped so the next call dials the
+// new endpoints.
+func (c *Client) ApplySettings(s *Settings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chains = s.Apply(DefaultChains())
+	c.chains[LocalChain] = s.LocalConfig()
+	for name, client := range c.clients {
+		client.Close()
+		delete(c.clients, name)
+	}
+}
+
+// ValidateRPCURL checks that rawURL is an http(s) or ws(s) endpoint that
+// answers eth_chainId with want, so a key for the wrong network is caught
+// before it is saved.
+func ValidateRPCURL(ctx context.Context, rawURL string, want *big.Int) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+	default:
+		return fmt.Errorf("unsupported scheme %q (use https or wss)", u.Scheme)
+	}
+
+	client, err := dialEth(ctx, rawURL)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	got, err := client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("eth_chainId: %w", err)
+	}
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("RPC is for chain %s, expected %s", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func TestSettings_SaveLoadApply(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	empty, err := LoadSettings(dir)
+	require.NoError(t, err)
+	assert.Len(t, empty.Apply(DefaultChains()), len(DefaultChains()), "missing file keeps every chain")
+
+	s := &Settings{
+		Enabled: []string{"ethereum", "base", "gone"},
+		RPCURLs: map[string][]string{"base": {"https://base.private.example"}},
+	}
+	require.NoError(t, s.Save(dir))
+
+	loaded, err := LoadSettings(dir)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+
+	chains := loaded.Apply(DefaultChains())
+	assert.Len(t, chains, 2)
+	assert.Equal(t, []string{"https://base.private.example"}, chains["base"].RPCURLs)
+	assert.Equal(t, DefaultChains()["ethereum"].RPCURLs, chains["ethereum"].RPCURLs)
+
+	c, err := NewConfiguredClient(dir)
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.GetChainConfig("polygon")
+	assert.Error(t, err, "disabled chains are unknown")
+}
+
+func TestSettings_ApplyRPS(t *testing.T) {
+	s := &Settings{RPS: 5, ChainRPS: map[string]float64{"base": 40, "arbitrum": -1}}
+	chains := s.Apply(DefaultChains())
+	assert.Equal(t, 5.0, chains["ethereum"].RPS)
+	assert.Equal(t, 40.0, chains["base"].RPS)
+	assert.Equal(t, -1.0, chains["arbitrum"].RPS)
+	assert.Zero(t, DefaultChains()["ethereum"].RPS, "defaults are not modified")
+}
+
+func TestValidateRPCURL(t *testing.T) {
+	f := newFakeRPC(t, func(method string, _ []json.RawMessage) (interface{}, error) {
+		return "0x2105", nil // 8453
+	})
+	ctx := context.Background()
+
+	assert.NoError(t, ValidateRPCURL(ctx, f.server.URL, big.NewInt(8453)))
+
+	err := ValidateRPCURL(ctx, f.server.URL, big.NewInt(1))
+	assert.ErrorContains(t, err, "chain 8453, expected 1")
+
+	assert.ErrorContains(t, ValidateRPCURL(ctx, "ftp://rpc.example", big.NewInt(1)), "unsupported scheme")
+	assert.ErrorContains(t, ValidateRPCURL(ctx, "not a url", big.NewInt(1)), "invalid URL")
+}
+
+func TestSettings_LocalConfig(t *testing.T) {
+	def := (&Settings{}).LocalConfig()
+	assert.Equal(t, []string{"http://127.0.0.1:8545"}, def.RPCURLs)
+	assert.Equal(t, int64(31337), def.ChainID.Int64())
+
+	s := &Settings{Enabled: []string{"base"}, Local: &LocalSettings{RPCURL: "http://10.0.0.5:8545", ChainID: 1}}
+	fork := s.LocalConfig()
+	assert.Equal(t, []string{"http://10.0.0.5:8545"}, fork.RPCURLs)
+	assert.Equal(t, int64(1), fork.ChainID.Int64())
+	assert.Equal(t, int64(1), fork.ChainIDInt)
+
+	c := NewClient()
+	defer c.Close()
+	c.ApplySettings(s)
+	cfg, err := c.GetChainConfig(LocalChain)
+	require.NoError(t, err, "the local preset resolves even when not enabled")
+	assert.Equal(t, fork.RPCURLs, cfg.RPCURLs)
+	assert.NotContains(t, c.ListChains(), LocalChain)
+}
@@ -0,0 +1,35 @@
+package chain
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP-1967 proxy slots: keccak256 of the label minus one, so no Solidity
+// variable layout can collide with them.
+var (
+	EIP1967ImplementationSlot = eip1967Slot("eip1967.proxy.implementation")
+	EIP1967AdminSlot          = eip1967Slot("eip1967.proxy.admin")
+	EIP1967BeaconSlot         = eip1967Slot("eip1967.proxy.beacon")
+)
+
+func eip1967Slot(label string) common.Hash {
+	h := crypto.Keccak256Hash([]byte(label)).Big()
+	return common.BigToHash(h.Sub(h, big.NewInt(1)))
+}
+
+// MappingSlot is where Solidity keeps mapping[key] for a mapping declared
+// at slot: keccak256(key . slot), with key already padded to 32 bytes.
+// Nested mappings apply it once per key, outermost first.
+func MappingSlot(key common.Hash, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// ArraySlot is where Solidity keeps element index of a dynamic array
+// declared at slot, for elements one slot wide.
+func ArraySlot(slot common.Hash, index uint64) common.Hash {
+	base := crypto.Keccak256Hash(slot.Bytes()).Big()
+	return common.BigToHash(base.Add(base, new(big.Int).SetUint64(index)))
+}
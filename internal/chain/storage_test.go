@@ -0,0 +1,21 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIP1967Slots(t *testing.T) {
+	assert.Equal(t, "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc", EIP1967ImplementationSlot.Hex())
+	assert.Equal(t, "0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103", EIP1967AdminSlot.Hex())
+	assert.Equal(t, "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50", EIP1967BeaconSlot.Hex())
+}
+
+func TestMappingAndArraySlots(t *testing.T) {
+	// keccak256 of 64 and 32 zero bytes.
+	assert.Equal(t, "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5", MappingSlot(common.Hash{}, common.Hash{}).Hex())
+	assert.Equal(t, "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563", ArraySlot(common.Hash{}, 0).Hex())
+	assert.Equal(t, "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e565", ArraySlot(common.Hash{}, 2).Hex())
+}
@@ -0,0 +1,216 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrTokenNotListed = errors.New("token not on any token list")
+	ErrAmbiguousToken = errors.New("token symbol is ambiguous")
+)
+
+// maxTokenListSize bounds downloaded token lists; the largest public lists
+// are a few MB.
+const maxTokenListSize = 20 << 20
+
+// builtinTokenList names the list built from KnownTokens.
+const builtinTokenList = "clifi default"
+
+// TokenList is a Uniswap-style token list
+// (https://github.com/Uniswap/token-lists).
+type TokenList struct {
+	Name   string           `json:"name"`
+	Tokens []TokenListEntry `json:"tokens"`
+}
+
+// TokenListEntry is one token of a TokenList.
+type TokenListEntry struct {
+	ChainID  int64  `json:"chainId"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// ParseTokenList decodes a token list, dropping entries without a valid
+// address or symbol.
+func ParseTokenList(data []byte) (*TokenList, error) {
+	var list TokenList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid token list: %w", err)
+	}
+	valid := list.Tokens[:0]
+	for _, t := range list.Tokens {
+		if common.IsHexAddress(t.Address) && t.Symbol != "" {
+			valid = append(valid, t)
+		}
+	}
+	list.Tokens = valid
+	return &list, nil
+}
+
+var tokenListClient = &http.Client{Timeout: 15 * time.Second}
+
+// LoadTokenList reads a token list from an https URL or a local file.
+func LoadTokenList(ctx context.Context, source string) (*TokenList, error) {
+	var data []byte
+	if strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := tokenListClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch token list: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch token list: HTTP %d", resp.StatusCode)
+		}
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxTokenListSize))
+		if err != nil {
+			return nil, fmt.Errorf("fetch token list: %w", err)
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("read token list: %w", err)
+		}
+	}
+
+	list, err := ParseTokenList(data)
+	if err != nil {
+		return nil, err
+	}
+	if list.Name == "" {
+		list.Name = source
+	}
+	return list, nil
+}
+
+// TokenMatch is a token a symbol resolved to, with the lists naming it.
+type TokenMatch struct {
+	TokenMetadata
+	Lists []string
+}
+
+// TokenResolver maps token symbols to contract addresses per chain using the
+// built-in KnownTokens plus any added token lists. Symbols are never guessed:
+// a symbol that is not listed, or that different lists assign to different
+// contracts, is an error.
+type TokenResolver struct {
+	mu sync.RWMutex
+	// byChain[chainID][upper(symbol)][address] -> match
+	byChain map[int64]map[string]map[common.Address]*TokenMatch
+}
+
+// NewTokenResolver creates a resolver seeded with KnownTokens. chains maps
+// the chain names KnownTokens uses to their chain IDs.
+func NewTokenResolver(chains map[string]*ChainConfig) *TokenResolver {
+	r := &TokenResolver{byChain: make(map[int64]map[string]map[common.Address]*TokenMatch)}
+	builtin := &TokenList{Name: builtinTokenList}
+	for chainName, tokens := range KnownTokens() {
+		cfg, ok := chains[chainName]
+		if !ok || cfg.ChainID == nil {
+			continue
+		}
+		for _, t := range tokens {
+			builtin.Tokens = append(builtin.Tokens, TokenListEntry{
+				ChainID:  cfg.ChainID.Int64(),
+				Address:  t.Address,
+				Symbol:   t.Symbol,
+				Name:     t.Name,
+				Decimals: t.Decimals,
+			})
+		}
+	}
+	r.AddList(builtin)
+	return r
+}
+
+// AddList indexes every token of list.
+func (r *TokenResolver) AddList(list *TokenList) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range list.Tokens {
+		symbols, ok := r.byChain[t.ChainID]
+		if !ok {
+			symbols = make(map[string]map[common.Address]*TokenMatch)
+			r.byChain[t.ChainID] = symbols
+		}
+		key := strings.ToUpper(t.Symbol)
+		addrs, ok := symbols[key]
+		if !ok {
+			addrs = make(map[common.Address]*TokenMatch)
+			symbols[key] = addrs
+		}
+		addr := common.HexToAddress(t.Address)
+		m, ok := addrs[addr]
+		if !ok {
+			m = &TokenMatch{TokenMetadata: TokenMetadata{
+				Address:  addr.Hex(),
+				Symbol:   t.Symbol,
+				Name:     t.Name,
+				Decimals: t.Decimals,
+			}}
+			addrs[addr] = m
+		}
+		m.Lists = append(m.Lists, list.Name)
+	}
+}
+
+// Resolve looks up symbol (case-insensitively) on chainID. It returns the
+// single listed contract, ErrTokenNotListed, or ErrAmbiguousToken together
+// with every candidate so the caller can show them.
+func (r *TokenResolver) Resolve(chainID int64, symbol string) ([]TokenMatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	addrs := r.byChain[chainID][strings.ToUpper(strings.TrimSpace(symbol))]
+	if len(addrs) == 0 {
+		return nil, ErrTokenNotListed
+	}
+	matches := make([]TokenMatch, 0, len(addrs))
+	for _, m := range addrs {
+		matches = append(matches, *m)
+	}
+	// Most widely listed first, so the likely intended token leads.
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i].Lists) != len(matches[j].Lists) {
+			return len(matches[i].Lists) > len(matches[j].Lists)
+		}
+		return matches[i].Address < matches[j].Address
+	})
+	if len(matches) > 1 {
+		return matches, ErrAmbiguousToken
+	}
+	return matches, nil
+}
+
+// Lookup finds the listed token at address on chainID, whatever its
+// symbol.
+func (r *TokenResolver) Lookup(chainID int64, address common.Address) (TokenMatch, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, addrs := range r.byChain[chainID] {
+		if m, ok := addrs[address]; ok {
+			return *m, true
+		}
+	}
+	return TokenMatch{}, false
+}
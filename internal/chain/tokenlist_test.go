@@ -0,0 +1,113 @@
+package chain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenList = `{
+	"name": "Test List",
+	"tokens": [
+		{"chainId": 8453, "address": "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", "symbol": "USDC", "name": "USD Coin", "decimals": 6},
+		{"chainId": 8453, "address": "0xd9aAEc86B65D86f6A7B5B1b0c42FFA531710b6CA", "symbol": "USDbC", "name": "USD Base Coin", "decimals": 6},
+		{"chainId": 8453, "address": "0x00000000000000000000000000000000000000aa", "symbol": "DAI", "name": "Fake Dai", "decimals": 18},
+		{"chainId": 8453, "address": "not-an-address", "symbol": "BAD", "decimals": 18}
+	]
+}`
+
+func TestParseTokenList(t *testing.T) {
+	list, err := ParseTokenList([]byte(testTokenList))
+	require.NoError(t, err)
+	assert.Equal(t, "Test List", list.Name)
+	assert.Len(t, list.Tokens, 3, "invalid entries are dropped")
+
+	_, err = ParseTokenList([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestLoadTokenList(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "list.json")
+		require.NoError(t, os.WriteFile(path, []byte(testTokenList), 0600))
+
+		list, err := LoadTokenList(context.Background(), path)
+		require.NoError(t, err)
+		assert.Len(t, list.Tokens, 3)
+	})
+
+	t.Run("https", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"tokens": []}`))
+		}))
+		defer srv.Close()
+		orig := tokenListClient
+		tokenListClient = srv.Client()
+		defer func() { tokenListClient = orig }()
+
+		list, err := LoadTokenList(context.Background(), srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL, list.Name, "unnamed lists are named after their source")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadTokenList(context.Background(), filepath.Join(t.TempDir(), "nope.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenResolver(t *testing.T) {
+	r := NewTokenResolver(DefaultChains())
+
+	t.Run("resolves built-in tokens case-insensitively", func(t *testing.T) {
+		matches, err := r.Resolve(8453, "usdc")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", matches[0].Address)
+		assert.Equal(t, uint8(6), matches[0].Decimals)
+	})
+
+	t.Run("unlisted symbols are not guessed", func(t *testing.T) {
+		_, err := r.Resolve(8453, "PEPE")
+		assert.ErrorIs(t, err, ErrTokenNotListed)
+		_, err = r.Resolve(999999, "USDC")
+		assert.ErrorIs(t, err, ErrTokenNotListed)
+	})
+
+	list, err := ParseTokenList([]byte(testTokenList))
+	require.NoError(t, err)
+	r.AddList(list)
+
+	t.Run("lists agreeing on a contract stay unambiguous", func(t *testing.T) {
+		matches, err := r.Resolve(8453, "USDC")
+		require.NoError(t, err)
+		assert.Equal(t, []string{builtinTokenList, "Test List"}, matches[0].Lists)
+	})
+
+	t.Run("conflicting contracts are ambiguous", func(t *testing.T) {
+		matches, err := r.Resolve(8453, "DAI")
+		assert.ErrorIs(t, err, ErrAmbiguousToken)
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("list-only tokens resolve", func(t *testing.T) {
+		matches, err := r.Resolve(8453, "USDbC")
+		require.NoError(t, err)
+		assert.Equal(t, "USD Base Coin", matches[0].Name)
+	})
+
+	t.Run("lookup by address", func(t *testing.T) {
+		m, ok := r.Lookup(8453, common.HexToAddress("0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"))
+		require.True(t, ok)
+		assert.Equal(t, "USDC", m.Symbol)
+		_, ok = r.Lookup(8453, common.HexToAddress("0x1"))
+		assert.False(t, ok)
+	})
+}
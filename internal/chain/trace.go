@@ -0,0 +1,136 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrTracingUnsupported means the chain's RPC endpoint exposes neither
+// debug_traceTransaction nor trace_transaction; most free public RPCs
+// don't.
+var ErrTracingUnsupported = errors.New("RPC endpoint does not support transaction tracing")
+
+// CallFrame is one call of a transaction trace, in the shape of geth's
+// callTracer: the top-level call with its internal calls nested below.
+type CallFrame struct {
+	Type         string         `json:"type"` // CALL, DELEGATECALL, STATICCALL, CREATE, SELFDESTRUCT...
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Value        *hexutil.Big   `json:"value,omitempty"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []CallFrame    `json:"calls,omitempty"`
+}
+
+// TraceTransaction returns txHash's call tree, from debug_traceTransaction
+// (geth and most clients) or else trace_transaction (Erigon, Nethermind,
+// Parity-style). When the node rejects both it returns
+// ErrTracingUnsupported; transport failures are returned as they are.
+func (c *Client) TraceTransaction(ctx context.Context, chainName string, txHash common.Hash) (*CallFrame, error) {
+	client, _, err := c.getClient(chainName)
+	if err != nil {
+		return nil, err
+	}
+	rc := client.Client()
+
+	var frame CallFrame
+	debugErr := rc.CallContext(ctx, &frame, "debug_traceTransaction", txHash, map[string]string{"tracer": "callTracer"})
+	if debugErr == nil && frame.Type != "" {
+		return &frame, nil
+	}
+	if debugErr != nil && !IsRPCError(debugErr) {
+		return nil, debugErr
+	}
+
+	var traces []parityTrace
+	parityErr := rc.CallContext(ctx, &traces, "trace_transaction", txHash)
+	if parityErr == nil && len(traces) > 0 {
+		return buildCallTree(traces)
+	}
+	if parityErr != nil && !IsRPCError(parityErr) {
+		return nil, parityErr
+	}
+	if debugErr == nil && parityErr == nil {
+		return nil, fmt.Errorf("no trace for %s; the node may not have the transaction", txHash.Hex())
+	}
+	return nil, fmt.Errorf("%w (debug_traceTransaction: %v; trace_transaction: %v)", ErrTracingUnsupported, debugErr, parityErr)
+}
+
+// parityTrace is one flattened call from trace_transaction. Its position
+// in the tree is traceAddress: the child index at each level below the
+// top-level call.
+type parityTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType      string         `json:"callType"`
+		From          common.Address `json:"from"`
+		To            common.Address `json:"to"`
+		Value         *hexutil.Big   `json:"value"`
+		Gas           hexutil.Uint64 `json:"gas"`
+		Input         hexutil.Bytes  `json:"input"`
+		Init          hexutil.Bytes  `json:"init"`
+		Address       common.Address `json:"address"`
+		RefundAddress common.Address `json:"refundAddress"`
+		Balance       *hexutil.Big   `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		GasUsed hexutil.Uint64 `json:"gasUsed"`
+		Output  hexutil.Bytes  `json:"output"`
+		Address common.Address `json:"address"`
+	} `json:"result"`
+	Error        string `json:"error"`
+	TraceAddress []int  `json:"traceAddress"`
+}
+
+func (t parityTrace) frame() CallFrame {
+	a := t.Action
+	f := CallFrame{Type: strings.ToUpper(t.Type), From: a.From, To: a.To, Value: a.Value, Gas: a.Gas, Input: a.Input, Error: t.Error}
+	switch t.Type {
+	case "call":
+		f.Type = strings.ToUpper(a.CallType)
+	case "create":
+		f.Input = a.Init
+	case "suicide":
+		f.Type = "SELFDESTRUCT"
+		f.From, f.To, f.Value = a.Address, a.RefundAddress, a.Balance
+	}
+	if t.Result != nil {
+		f.GasUsed, f.Output = t.Result.GasUsed, t.Result.Output
+		if t.Type == "create" {
+			f.To = t.Result.Address
+		}
+	}
+	return f
+}
+
+// buildCallTree nests trace_transaction's depth-first list of calls.
+func buildCallTree(traces []parityTrace) (*CallFrame, error) {
+	if len(traces[0].TraceAddress) != 0 {
+		return nil, fmt.Errorf("trace_transaction: first trace is not the top-level call")
+	}
+	root := traces[0].frame()
+	for _, t := range traces[1:] {
+		path := t.TraceAddress
+		if len(path) == 0 {
+			return nil, fmt.Errorf("trace_transaction: more than one top-level call")
+		}
+		parent := &root
+		for _, i := range path[:len(path)-1] {
+			if i < 0 || i >= len(parent.Calls) {
+				return nil, fmt.Errorf("trace_transaction: trace address %v out of order", path)
+			}
+			parent = &parent.Calls[i]
+		}
+		parent.Calls = append(parent.Calls, t.frame())
+	}
+	return &root, nil
+}
@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceTransaction(t *testing.T) {
+	hash := common.HexToHash("0x01")
+
+	t.Run("debug_traceTransaction", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			require.Equal(t, "debug_traceTransaction", method)
+			return json.RawMessage(`{"type":"CALL","from":"0x00000000000000000000000000000000000000aa","to":"0x00000000000000000000000000000000000000bb","value":"0x0","gas":"0x5208","gasUsed":"0x5208","input":"0x",
+				"calls":[{"type":"DELEGATECALL","from":"0x00000000000000000000000000000000000000bb","to":"0x00000000000000000000000000000000000000cc","gas":"0x1","gasUsed":"0x1","input":"0x12345678","error":"execution reverted"}]}`), nil
+		})
+		frame, err := newTestClient(t, f).TraceTransaction(context.Background(), "ethereum", hash)
+		require.NoError(t, err)
+		assert.Equal(t, "CALL", frame.Type)
+		require.Len(t, frame.Calls, 1)
+		assert.Equal(t, "DELEGATECALL", frame.Calls[0].Type)
+		assert.Equal(t, "execution reverted", frame.Calls[0].Error)
+	})
+
+	t.Run("falls back to trace_transaction", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			if method == "debug_traceTransaction" {
+				return nil, fmt.Errorf("the method debug_traceTransaction does not exist/is not available")
+			}
+			return json.RawMessage(`[
+				{"type":"call","action":{"callType":"call","from":"0x00000000000000000000000000000000000000aa","to":"0x00000000000000000000000000000000000000bb","value":"0x0","gas":"0x10","input":"0x"},"result":{"gasUsed":"0x8","output":"0x"},"subtraces":2,"traceAddress":[]},
+				{"type":"call","action":{"callType":"staticcall","from":"0x00000000000000000000000000000000000000bb","to":"0x00000000000000000000000000000000000000cc","value":"0x0","gas":"0x4","input":"0x"},"result":{"gasUsed":"0x1","output":"0x"},"subtraces":1,"traceAddress":[0]},
+				{"type":"call","action":{"callType":"call","from":"0x00000000000000000000000000000000000000cc","to":"0x00000000000000000000000000000000000000dd","value":"0x5","gas":"0x2","input":"0x"},"result":{"gasUsed":"0x1","output":"0x"},"subtraces":0,"traceAddress":[0,0]},
+				{"type":"create","action":{"from":"0x00000000000000000000000000000000000000bb","value":"0x0","gas":"0x4","init":"0x6080"},"result":{"gasUsed":"0x1","address":"0x00000000000000000000000000000000000000ee"},"subtraces":0,"traceAddress":[1]}
+			]`), nil
+		})
+		frame, err := newTestClient(t, f).TraceTransaction(context.Background(), "ethereum", hash)
+		require.NoError(t, err)
+		require.Len(t, frame.Calls, 2)
+		assert.Equal(t, "STATICCALL", frame.Calls[0].Type)
+		require.Len(t, frame.Calls[0].Calls, 1)
+		assert.Equal(t, int64(5), frame.Calls[0].Calls[0].Value.ToInt().Int64())
+		assert.Equal(t, "CREATE", frame.Calls[1].Type)
+		assert.Equal(t, common.HexToAddress("0xee"), frame.Calls[1].To)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		f := newFakeRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			return nil, fmt.Errorf("method %s not found", method)
+		})
+		_, err := newTestClient(t, f).TraceTransaction(context.Background(), "ethereum", hash)
+		assert.ErrorIs(t, err, ErrTracingUnsupported)
+	})
+}
@@ -0,0 +1,293 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultSourcifyURL  = "https://sourcify.dev/server"
+	defaultEtherscanURL = "https://api.etherscan.io/v2/api"
+)
+
+// ContractVerification describes whether a contract's source is published.
+type ContractVerification struct {
+	Address  string `json:"address"`
+	Verified bool   `json:"verified"`
+	Name     string `json:"name,omitempty"`
+	Compiler string `json:"compiler,omitempty"`
+	// Source names the service that answered ("etherscan" or "sourcify").
+	Source string `json:"source"`
+}
+
+// Verifier checks contract source verification through explorer APIs.
+// Etherscan's multichain API is used when ETHERSCAN_API_KEY is set; otherwise
+// Sourcify, which needs no key. Results are cached since verification status
+// only ever changes from unverified to verified.
+type Verifier struct {
+	httpClient   *http.Client
+	etherscanKey string
+	etherscanURL string
+	sourcifyURL  string
+
+	mu       sync.Mutex
+	verified map[contractKey]*ContractVerification
+	abis     map[contractKey]string
+}
+
+// ErrNoABI means the contract has no published ABI.
+var ErrNoABI = errors.New("no published ABI")
+
+type contractKey struct {
+	chainID int64
+	address common.Address
+}
+
+// NewVerifier creates a verifier using the public explorer endpoints.
+func NewVerifier() *Verifier {
+	return &Verifier{
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		etherscanKey: os.Getenv("ETHERSCAN_API_KEY"),
+		etherscanURL: defaultEtherscanURL,
+		sourcifyURL:  defaultSourcifyURL,
+		verified:     make(map[contractKey]*ContractVerification),
+		abis:         make(map[contractKey]string),
+	}
+}
+
+// Check returns the verification status of address on the given chain.
+func (v *Verifier) Check(ctx context.Context, chainID int64, address common.Address) (*ContractVerification, error) {
+	key := contractKey{chainID: chainID, address: address}
+	v.mu.Lock()
+	cached, ok := v.verified[key]
+	v.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var (
+		res *ContractVerification
+		err error
+	)
+	if v.etherscanKey != "" {
+		res, err = v.checkEtherscan(ctx, chainID, address)
+	} else {
+		res, err = v.checkSourcify(ctx, chainID, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only positive results are cached: an unverified contract may be verified later.
+	if res.Verified {
+		v.mu.Lock()
+		v.verified[key] = res
+		v.mu.Unlock()
+	}
+	return res, nil
+}
+
+func (v *Verifier) checkSourcify(ctx context.Context, chainID int64, address common.Address) (*ContractVerification, error) {
+	endpoint := fmt.Sprintf("%s/v2/contract/%d/%s?fields=compilation", v.sourcifyURL, chainID, address.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sourcify: %w", err)
+	}
+	defer resp.Body.Close()
+
+	res := &ContractVerification{Address: address.Hex(), Source: "sourcify"}
+	if resp.StatusCode == http.StatusNotFound {
+		return res, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sourcify: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Match       string `json:"match"`
+		Compilation struct {
+			Name            string `json:"name"`
+			CompilerVersion string `json:"compilerVersion"`
+		} `json:"compilation"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("sourcify: decode response: %w", err)
+	}
+	res.Verified = body.Match != ""
+	res.Name = body.Compilation.Name
+	res.Compiler = body.Compilation.CompilerVersion
+	return res, nil
+}
+
+// redactURL drops the request URL from an HTTP client error, keeping the
+// method and cause: Etherscan's carries the API key in its query.
+func redactURL(err error) error {
+	var ue *url.Error
+	if !errors.As(err, &ue) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", ue.Op, ue.Err)
+}
+
+func (v *Verifier) checkEtherscan(ctx context.Context, chainID int64, address common.Address) (*ContractVerification, error) {
+	q := url.Values{}
+	q.Set("chainid", strconv.FormatInt(chainID, 10))
+	q.Set("module", "contract")
+	q.Set("action", "getsourcecode")
+	q.Set("address", address.Hex())
+	q.Set("apikey", v.etherscanKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.etherscanURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("etherscan: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("etherscan: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("etherscan: decode response: %w", err)
+	}
+	// On errors Etherscan returns result as a string explaining the problem.
+	var results []struct {
+		SourceCode      string `json:"SourceCode"`
+		ContractName    string `json:"ContractName"`
+		CompilerVersion string `json:"CompilerVersion"`
+	}
+	if body.Status != "1" || json.Unmarshal(body.Result, &results) != nil {
+		var msg string
+		_ = json.Unmarshal(body.Result, &msg)
+		return nil, fmt.Errorf("etherscan: %s %s", body.Message, msg)
+	}
+
+	res := &ContractVerification{Address: address.Hex(), Source: "etherscan"}
+	if len(results) > 0 && results[0].SourceCode != "" {
+		res.Verified = true
+		res.Name = results[0].ContractName
+		res.Compiler = results[0].CompilerVersion
+	}
+	return res, nil
+}
+
+// ABI returns the JSON ABI of a verified contract, from the same service
+// Check uses, or ErrNoABI when the contract is not verified.
+func (v *Verifier) ABI(ctx context.Context, chainID int64, address common.Address) (string, error) {
+	key := contractKey{chainID: chainID, address: address}
+	v.mu.Lock()
+	cached, ok := v.abis[key]
+	v.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var (
+		abiJSON string
+		err     error
+	)
+	if v.etherscanKey != "" {
+		abiJSON, err = v.etherscanABI(ctx, chainID, address)
+	} else {
+		abiJSON, err = v.sourcifyABI(ctx, chainID, address)
+	}
+	if err != nil {
+		return "", err
+	}
+	v.mu.Lock()
+	v.abis[key] = abiJSON
+	v.mu.Unlock()
+	return abiJSON, nil
+}
+
+func (v *Verifier) sourcifyABI(ctx context.Context, chainID int64, address common.Address) (string, error) {
+	endpoint := fmt.Sprintf("%s/v2/contract/%d/%s?fields=abi", v.sourcifyURL, chainID, address.Hex())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("sourcify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNoABI
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("sourcify: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("sourcify: decode response: %w", err)
+	}
+	if len(body.ABI) == 0 || string(body.ABI) == "null" {
+		return "", ErrNoABI
+	}
+	return string(body.ABI), nil
+}
+
+func (v *Verifier) etherscanABI(ctx context.Context, chainID int64, address common.Address) (string, error) {
+	q := url.Values{}
+	q.Set("chainid", strconv.FormatInt(chainID, 10))
+	q.Set("module", "contract")
+	q.Set("action", "getabi")
+	q.Set("address", address.Hex())
+	q.Set("apikey", v.etherscanKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.etherscanURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("etherscan: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("etherscan: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Result  string `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("etherscan: decode response: %w", err)
+	}
+	if body.Status != "1" {
+		// The ABI of an unverified contract is an error like any other.
+		if strings.Contains(body.Result, "not verified") {
+			return "", ErrNoABI
+		}
+		return "", fmt.Errorf("etherscan: %s %s", body.Message, body.Result)
+	}
+	return body.Result, nil
+}
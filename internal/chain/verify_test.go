@@ -0,0 +1,175 @@
+package chain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifier(t *testing.T, handler http.HandlerFunc) (*Verifier, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	v := NewVerifier()
+	v.etherscanKey = ""
+	v.sourcifyURL = srv.URL
+	v.etherscanURL = srv.URL
+	return v, &hits
+}
+
+func TestVerifier_Sourcify(t *testing.T) {
+	verifiedAddr := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+	v, hits := newTestVerifier(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/v2/contract/1/") {
+			http.Error(w, "bad path", http.StatusBadRequest)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, verifiedAddr.Hex()) {
+			_, _ = w.Write([]byte(`{"match":"exact_match","compilation":{"name":"Permit2","compilerVersion":"0.8.17+commit.8df45f5f"}}`))
+			return
+		}
+		http.Error(w, `{"customCode":"not_found"}`, http.StatusNotFound)
+	})
+
+	t.Run("verified contract", func(t *testing.T) {
+		res, err := v.Check(context.Background(), 1, verifiedAddr)
+		require.NoError(t, err)
+		assert.True(t, res.Verified)
+		assert.Equal(t, "Permit2", res.Name)
+		assert.Equal(t, "0.8.17+commit.8df45f5f", res.Compiler)
+		assert.Equal(t, "sourcify", res.Source)
+	})
+
+	t.Run("verified result is cached", func(t *testing.T) {
+		before := hits.Load()
+		_, err := v.Check(context.Background(), 1, verifiedAddr)
+		require.NoError(t, err)
+		assert.Equal(t, before, hits.Load())
+	})
+
+	t.Run("unverified contract", func(t *testing.T) {
+		res, err := v.Check(context.Background(), 1, common.HexToAddress("0x1"))
+		require.NoError(t, err)
+		assert.False(t, res.Verified)
+	})
+}
+
+func TestVerifier_SourcifyServerError(t *testing.T) {
+	v, _ := newTestVerifier(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	_, err := v.Check(context.Background(), 1, common.HexToAddress("0x1"))
+	assert.Error(t, err)
+}
+
+func TestVerifier_Etherscan(t *testing.T) {
+	v, _ := newTestVerifier(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("apikey") != "test-key" || q.Get("chainid") != "8453" || q.Get("action") != "getsourcecode" {
+			_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Invalid API Key"}`))
+			return
+		}
+		if q.Get("address") == common.HexToAddress("0x2").Hex() {
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[{"SourceCode":"","ContractName":"","CompilerVersion":""}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":[{"SourceCode":"contract Router {}","ContractName":"Router","CompilerVersion":"v0.8.20"}]}`))
+	})
+	v.etherscanKey = "test-key"
+
+	t.Run("verified", func(t *testing.T) {
+		res, err := v.Check(context.Background(), 8453, common.HexToAddress("0x1"))
+		require.NoError(t, err)
+		assert.True(t, res.Verified)
+		assert.Equal(t, "Router", res.Name)
+		assert.Equal(t, "etherscan", res.Source)
+	})
+
+	t.Run("unverified", func(t *testing.T) {
+		res, err := v.Check(context.Background(), 8453, common.HexToAddress("0x2"))
+		require.NoError(t, err)
+		assert.False(t, res.Verified)
+	})
+
+	t.Run("api error surfaces message", func(t *testing.T) {
+		_, err := v.Check(context.Background(), 1, common.HexToAddress("0x3"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid API Key")
+	})
+}
+
+func TestVerifier_EtherscanErrorHidesKey(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	v := NewVerifier()
+	v.etherscanURL = srv.URL
+	v.etherscanKey = "secret-key"
+
+	_, err := v.Check(context.Background(), 1, common.HexToAddress("0x1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "etherscan: Get")
+	assert.NotContains(t, err.Error(), "secret-key")
+
+	_, err = v.ABI(context.Background(), 1, common.HexToAddress("0x1"))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-key")
+}
+
+func TestVerifier_ABI(t *testing.T) {
+	const abiJSON = `[{"type":"function","name":"ping","inputs":[],"outputs":[]}]`
+	verifiedAddr := common.HexToAddress("0x1")
+
+	v, hits := newTestVerifier(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields") != "abi" {
+			http.Error(w, "bad fields", http.StatusBadRequest)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, verifiedAddr.Hex()) {
+			_, _ = w.Write([]byte(`{"abi":` + abiJSON + `}`))
+			return
+		}
+		http.Error(w, `{"customCode":"not_found"}`, http.StatusNotFound)
+	})
+
+	got, err := v.ABI(context.Background(), 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.JSONEq(t, abiJSON, got)
+	_, err = v.ABI(context.Background(), 1, verifiedAddr)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load())
+
+	_, err = v.ABI(context.Background(), 1, common.HexToAddress("0x2"))
+	assert.ErrorIs(t, err, ErrNoABI)
+
+	es, _ := newTestVerifier(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "getabi" {
+			http.Error(w, "bad action", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("address") == verifiedAddr.Hex() {
+			_, _ = w.Write([]byte(`{"status":"1","message":"OK","result":"` + strings.ReplaceAll(abiJSON, `"`, `\"`) + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`))
+	})
+	es.etherscanKey = "test-key"
+	got, err = es.ABI(context.Background(), 8453, verifiedAddr)
+	require.NoError(t, err)
+	assert.JSONEq(t, abiJSON, got)
+	_, err = es.ABI(context.Background(), 8453, common.HexToAddress("0x2"))
+	assert.ErrorIs(t, err, ErrNoABI)
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/jsonschema"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// askTimeout covers the whole question, retries for a schema included.
+const askTimeout = 3 * time.Minute
+
+var askCmd = &cobra.Command{
+	Use:   "ask [question]",
+	Short: "Ask one question and print the answer",
+	Long: `Ask the agent one question without the REPL and print its answer to
+stdout; tool calls are reported on stderr. Without arguments the question
+is read from stdin.
+
+With --schema the answer is a JSON value valid against the JSON Schema in
+the file, for scripts. OpenAI and Gemini (when no tools are needed) are
+held to it natively; otherwise the answer is validated and the model asked
+to fix it, and clifi exits with an error if it still doesn't match.
+
+--tool makes the model call the named tool first, so a scripted question
+always reads the same data; --no-tools answers from the model alone.`,
+	Example: `  clifi ask "what is my ETH balance on base?"
+  clifi ask --schema balances.json "list my balances on base" | jq .
+  clifi ask --tool get_balances "what do I hold on base?"
+  echo "gas price on arbitrum?" | clifi ask`,
+	RunE: runAsk,
+}
+
+func init() {
+	rootCmd.AddCommand(askCmd)
+
+	askCmd.Flags().String("schema", "", "JSON Schema file the answer must validate against")
+	askCmd.Flags().String("tool", "", "Tool the model must call first, e.g. get_balances")
+	askCmd.Flags().Bool("no-tools", false, "Answer without calling any tools")
+	askCmd.MarkFlagsMutuallyExclusive("tool", "no-tools")
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	question := strings.Join(args, " ")
+	if question == "" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("read question: %w", err)
+		}
+		question = string(data)
+	}
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return errors.New("no question: pass it as arguments or on stdin")
+	}
+
+	var schema *jsonschema.Schema
+	if path, _ := cmd.Flags().GetString("schema"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read schema: %w", err)
+		}
+		if schema, err = jsonschema.Parse(data); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	cmd.SilenceUsage = true
+
+	ag, err := agent.New("")
+	if err != nil {
+		return fmt.Errorf("failed to create agent: %w", err)
+	}
+	defer ag.Close()
+	if err := configureAgent(ag); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), askTimeout)
+	defer cancel()
+	if name, _ := cmd.Flags().GetString("tool"); name != "" {
+		ctx = agent.WithToolChoice(ctx, llm.ToolChoice{Mode: llm.ToolChoiceForce, Name: name})
+	} else if off, _ := cmd.Flags().GetBool("no-tools"); off {
+		ctx = agent.WithToolChoice(ctx, llm.ToolChoice{Mode: llm.ToolChoiceNone})
+	}
+	progress := func(e agent.ChatEvent) {
+		switch e.Type {
+		case "tool_call":
+			fmt.Fprintf(cmd.ErrOrStderr(), "→ %s\n", e.Tool)
+		case "tool_result":
+			if e.IsError {
+				fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s: %s\n", e.Tool, e.Content)
+			}
+		case "notice":
+			fmt.Fprintln(cmd.ErrOrStderr(), e.Content)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if schema == nil {
+		events, err := ag.ChatStream(ctx, question, progress)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if e.Type == "content" {
+				fmt.Fprintln(out, e.Content)
+			}
+		}
+		return nil
+	}
+
+	answer, err := ag.AskJSON(ctx, question, schema, progress)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, answer); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, buf.String())
+	return nil
+}
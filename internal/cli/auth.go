@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -29,7 +28,7 @@ Supported providers:
   anthropic  - Anthropic Claude (requires API key)
   openai     - OpenAI GPT (requires API key)
   venice     - Venice AI (requires API key)
-  copilot    - GitHub Copilot (requires OAuth)
+  copilot    - GitHub Copilot (GitHub login: enter a code in the browser)
   gemini     - Google Gemini (requires API key)`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAuthConnect,
@@ -75,12 +74,7 @@ func init() {
 }
 
 func getAuthManager() (*auth.Manager, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
-	}
-	dataDir := filepath.Join(home, ".clifi")
-	return auth.NewManager(dataDir)
+	return auth.NewManager(getDataDir())
 }
 
 func runAuthConnect(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+var balanceCmd = &cobra.Command{
+	Use:   "balance [address|wallet]",
+	Short: "Show balances without the agent",
+	Long: `Show native and token balances for an address or wallet, straight from
+the chain RPCs. Without an argument the default wallet is used, and without
+--chains every enabled mainnet is queried.
+
+Tokens are symbols from the built-in token list (USDC, WETH, ...), which are
+looked up on each chain, or contract addresses, optionally as chain:0x...`,
+	Example: `  clifi balance
+  clifi balance trading --chains base,arbitrum --tokens usdc,weth
+  clifi balance 0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBalance,
+}
+
+func init() {
+	rootCmd.AddCommand(balanceCmd)
+
+	balanceCmd.Flags().StringSlice("chains", nil, "Chains to query (default: enabled mainnets)")
+	balanceCmd.Flags().StringSlice("tokens", nil, "Tokens to include: symbol, 0x address or chain:0x address")
+	balanceCmd.Flags().Bool("json", false, "Print JSON instead of a table")
+}
+
+// balanceReport is the --json output. Amounts are decimal strings so no
+// precision is lost to float parsing.
+type balanceReport struct {
+	Address string         `json:"address"`
+	Chains  []chainBalance `json:"chains"`
+}
+
+type chainBalance struct {
+	Chain  string         `json:"chain"`
+	Native *assetBalance  `json:"native,omitempty"`
+	Tokens []assetBalance `json:"tokens,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type assetBalance struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address,omitempty"`
+	Balance  string `json:"balance"`
+	Raw      string `json:"raw"`
+	Decimals uint8  `json:"decimals"`
+}
+
+func runBalance(cmd *cobra.Command, args []string) error {
+	chainsFlag, _ := cmd.Flags().GetStringSlice("chains")
+	tokensFlag, _ := cmd.Flags().GetStringSlice("tokens")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	var ref string
+	if len(args) > 0 {
+		ref = args[0]
+	}
+	address, err := resolveAddress(ref)
+	if err != nil {
+		return err
+	}
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	chains := chainsFlag
+	if len(chains) == 0 {
+		chains = enabledMainnets(client)
+	}
+	for i, name := range chains {
+		chains[i] = strings.ToLower(strings.TrimSpace(name))
+		if _, err := client.GetChainConfig(chains[i]); err != nil {
+			return err
+		}
+	}
+	tokens, err := balanceTokens(chains, tokensFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	portfolio, err := client.GetPortfolio(ctx, address, chains, tokens)
+	if err != nil {
+		return err
+	}
+
+	report := newBalanceReport(portfolio, chains)
+	if asJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		printBalanceTable(cmd.OutOrStdout(), report)
+	}
+	if err != nil {
+		return err
+	}
+	if len(portfolio.Errors) == len(chains) {
+		cmd.SilenceUsage = true
+		return errors.New("no chain could be queried")
+	}
+	return nil
+}
+
+// resolveAddress turns an address, wallet label or index into an address,
+// falling back to the default wallet when ref is empty.
+func resolveAddress(ref string) (common.Address, error) {
+	if common.IsHexAddress(ref) {
+		return common.HexToAddress(ref), nil
+	}
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to load wallets: %w", err)
+	}
+	if ref == "" {
+		acc, err := km.DefaultAccount()
+		if err != nil {
+			return common.Address{}, errors.New("no address given and no wallets found; pass an address or create a wallet first")
+		}
+		return acc.Address, nil
+	}
+	acc, err := km.FindAccount(ref)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%q is not an address or wallet: %w", ref, err)
+	}
+	return acc.Address, nil
+}
+
+// enabledMainnets lists the client's non-testnet chains by chain ID.
+func enabledMainnets(client *chain.Client) []string {
+	var names []string
+	for _, name := range sortedChains(client) {
+		if cfg, err := client.GetChainConfig(name); err == nil && !cfg.IsTestnet {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// balanceTokens resolves --tokens entries for each chain. A symbol is
+// looked up on every chain and skipped where it isn't listed, so
+// "--tokens usdc" works across chains; it is an error only when no chain
+// lists it. A bare address is queried on every chain.
+func balanceTokens(chains []string, specs []string) (map[string][]common.Address, error) {
+	tokens := make(map[string][]common.Address)
+	defaults := chain.DefaultChains()
+	resolver := chain.NewTokenResolver(defaults)
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if chainName, addr, ok := strings.Cut(spec, ":"); ok {
+			if !common.IsHexAddress(addr) {
+				return nil, fmt.Errorf("invalid token %q: expected chain:0x<address>", spec)
+			}
+			chainName = strings.ToLower(chainName)
+			tokens[chainName] = append(tokens[chainName], common.HexToAddress(addr))
+			continue
+		}
+		if common.IsHexAddress(spec) {
+			for _, c := range chains {
+				tokens[c] = append(tokens[c], common.HexToAddress(spec))
+			}
+			continue
+		}
+
+		found := false
+		for _, c := range chains {
+			cfg, ok := defaults[c]
+			if !ok {
+				continue
+			}
+			matches, err := resolver.Resolve(cfg.ChainIDInt, spec)
+			if errors.Is(err, chain.ErrTokenNotListed) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s on %s: %w; pass the contract address instead", spec, c, err)
+			}
+			tokens[c] = append(tokens[c], common.HexToAddress(matches[0].Address))
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("token %q is not listed on %s; pass its contract address", spec, strings.Join(chains, ", "))
+		}
+	}
+	return tokens, nil
+}
+
+func newBalanceReport(p *chain.Portfolio, chains []string) balanceReport {
+	report := balanceReport{Address: p.Address}
+	for _, name := range chains {
+		cb := chainBalance{Chain: name}
+		if native, ok := p.NativeBalances[name]; ok {
+			cb.Native = newAssetBalance(native.Symbol, "", native.Balance, native.Decimals)
+		} else {
+			cb.Error = p.Errors[name]
+		}
+		for _, tb := range p.TokenBalances[name] {
+			cb.Tokens = append(cb.Tokens, *newAssetBalance(tb.Symbol, tb.TokenAddress, tb.Balance, tb.Decimals))
+		}
+		report.Chains = append(report.Chains, cb)
+	}
+	return report
+}
+
+func newAssetBalance(symbol, address string, amount *big.Int, decimals uint8) *assetBalance {
+	raw := "0"
+	if amount != nil {
+		raw = amount.String()
+	}
+	return &assetBalance{
+		Symbol:   symbol,
+		Address:  address,
+		Balance:  chain.FormatBalance(amount, decimals),
+		Raw:      raw,
+		Decimals: decimals,
+	}
+}
+
+func printBalanceTable(w io.Writer, r balanceReport) {
+	fmt.Fprintf(w, "Balances for %s\n\n", r.Address)
+	fmt.Fprintf(w, "%-14s %-8s %24s\n", "CHAIN", "ASSET", "BALANCE")
+	for _, cb := range r.Chains {
+		if cb.Native == nil {
+			fmt.Fprintf(w, "%-14s ⚠ %s\n", cb.Chain, cb.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%-14s %-8s %24s\n", cb.Chain, cb.Native.Symbol, cb.Native.Balance)
+		for _, t := range cb.Tokens {
+			fmt.Fprintf(w, "%-14s %-8s %24s\n", "", t.Symbol, t.Balance)
+		}
+	}
+}
+
+// toolRunMsg carries the result of a tool run directly from a command.
+type toolRunMsg struct {
+	tool    string
+	out     agent.ToolOutput
+	err     error
+	elapsed time.Duration
+}
+
+// handleBalanceCommand shows balances without an LLM round trip.
+//
+//	/balance                        default wallet, default chains
+//	/balance <address|wallet>       another address or labelled wallet
+//	/balance [who] base arbitrum    only these chains
+func (m model) handleBalanceCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+
+	var km *wallet.KeystoreManager
+	if k, err := wallet.NewKeystoreManager(getDataDir()); err == nil {
+		km = k
+	}
+
+	var address string
+	var chains []string
+	for _, f := range strings.Fields(arg) {
+		if address == "" && common.IsHexAddress(f) {
+			address = common.HexToAddress(f).Hex()
+			continue
+		}
+		if address == "" && km != nil {
+			if acc, err := km.FindAccount(f); err == nil {
+				address = acc.Address.Hex()
+				continue
+			}
+		}
+		chains = append(chains, strings.ToLower(f))
+	}
+	if address == "" {
+		if km == nil {
+			m.addError("No address given and the keystore could not be loaded.")
+			m.updateViewport()
+			return m, nil
+		}
+		acc, err := km.DefaultAccount()
+		if err != nil {
+			m.addError("No address given and no wallets found. Use /balance <address> or /wallet create.")
+			m.updateViewport()
+			return m, nil
+		}
+		address = acc.Address.Hex()
+	}
+
+	input := map[string]any{"address": address}
+	if len(chains) > 0 {
+		input["chains"] = chains
+	}
+	args, _ := json.Marshal(input)
+	m.running = append(m.running, runningTool{name: "get_balances", args: string(args), started: time.Now()})
+	m.loading = true
+	m.updateViewport()
+	return m, m.runTool("get_balances", input)
+}
+
+// runTool runs a tool in the background and reports a toolRunMsg.
+func (m model) runTool(name string, input any) tea.Cmd {
+	ag := m.agent
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		start := time.Now()
+		out, err := ag.RunTool(ctx, name, input)
+		return toolRunMsg{tool: name, out: out, err: err, elapsed: time.Since(start)}
+	}
+}
+
+// handleToolRun shows the result of a direct tool run.
+func (m *model) handleToolRun(msg toolRunMsg) {
+	m.loading = false
+	call := m.finishTool(msg.tool)
+	m.addToolCall(call.name, call.args, msg.elapsed)
+	if msg.err != nil {
+		m.addErrorf("%s: %v", msg.tool, msg.err)
+		return
+	}
+	m.addToolResult(msg.tool, msg.out.Text, msg.out.Blocks)
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+func TestBalanceTokens(t *testing.T) {
+	tokens, err := balanceTokens([]string{"ethereum", "base", "sepolia"}, []string{"usdc", "Base:0x4200000000000000000000000000000000000006"})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")}, tokens["ethereum"])
+	assert.Equal(t, []common.Address{
+		common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+		common.HexToAddress("0x4200000000000000000000000000000000000006"),
+	}, tokens["base"])
+
+	_, err = balanceTokens([]string{"base"}, []string{"ARB"})
+	assert.ErrorContains(t, err, "not listed on base")
+
+	_, err = balanceTokens([]string{"base"}, []string{"base:nope"})
+	assert.Error(t, err)
+}
+
+func TestNewBalanceReport(t *testing.T) {
+	p := &chain.Portfolio{
+		Address: "0xabc",
+		NativeBalances: map[string]*chain.NativeBalance{
+			"base": {Chain: "base", Symbol: "ETH", Balance: big.NewInt(1500000000000000000), Decimals: 18},
+		},
+		TokenBalances: map[string][]*chain.TokenBalance{
+			"base": {{TokenAddress: "0xusdc", Symbol: "USDC", Balance: big.NewInt(2500000), Decimals: 6}},
+		},
+		Errors: map[string]string{"ethereum": "rpc down"},
+	}
+	r := newBalanceReport(p, []string{"ethereum", "base"})
+	require.Len(t, r.Chains, 2)
+	assert.Equal(t, "rpc down", r.Chains[0].Error)
+	assert.Nil(t, r.Chains[0].Native)
+	assert.Equal(t, "1.500000", r.Chains[1].Native.Balance)
+	assert.Equal(t, "1500000000000000000", r.Chains[1].Native.Raw)
+	assert.Equal(t, "2.500000", r.Chains[1].Tokens[0].Balance)
+}
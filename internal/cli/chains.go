@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+var chainsCmd = &cobra.Command{
+	Use:   "chains",
+	Short: "Show configured chains and check their RPCs",
+}
+
+var chainsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List enabled chains",
+	Long: `List the chains clifi uses, after ~/.clifi/chains.yaml is applied.
+Custom RPC URLs from the setup wizard are marked.`,
+	Args: cobra.NoArgs,
+	RunE: runChainsList,
+}
+
+var chainsPingCmd = &cobra.Command{
+	Use:   "ping [chain...]",
+	Short: "Check every RPC of the enabled chains",
+	Long: `Contact every RPC URL of each chain (or only the named chains) and show
+its head block and latency. Exits non-zero when a chain has no working RPC.`,
+	RunE: runChainsPing,
+}
+
+func init() {
+	rootCmd.AddCommand(chainsCmd)
+	chainsCmd.AddCommand(chainsListCmd)
+	chainsCmd.AddCommand(chainsPingCmd)
+
+	chainsPingCmd.Flags().Duration("timeout", 8*time.Second, "Timeout per RPC")
+}
+
+// sortedChains returns the client's chain names, mainnets first, then by
+// chain ID.
+func sortedChains(client *chain.Client) []string {
+	names := client.ListChains()
+	sort.Slice(names, func(i, j int) bool {
+		a, _ := client.GetChainConfig(names[i])
+		b, _ := client.GetChainConfig(names[j])
+		if a.IsTestnet != b.IsTestnet {
+			return !a.IsTestnet
+		}
+		return a.ChainIDInt < b.ChainIDInt
+	})
+	return names
+}
+
+func runChainsList(cmd *cobra.Command, args []string) error {
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	settings, err := chain.LoadSettings(getDataDir())
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%-14s %-9s %-6s %-8s %s\n", "CHAIN", "ID", "TOKEN", "NETWORK", "RPC")
+	for _, name := range sortedChains(client) {
+		cfg, _ := client.GetChainConfig(name)
+		network := "mainnet"
+		if cfg.IsTestnet {
+			network = "testnet"
+		}
+		rpc := strings.Join(cfg.RPCURLs, ", ")
+		if len(settings.RPCURLs[name]) > 0 {
+			rpc += " (custom)"
+		}
+		fmt.Fprintf(out, "%-14s %-9d %-6s %-8s %s\n", name, cfg.ChainIDInt, cfg.NativeCurrency, network, rpc)
+	}
+	return nil
+}
+
+func runChainsPing(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	names := sortedChains(client)
+	if len(args) > 0 {
+		// Named chains may include the local preset, which isn't listed.
+		for _, name := range args {
+			if _, err := client.GetChainConfig(name); err != nil {
+				return err
+			}
+		}
+		names = args
+	}
+	cmd.SilenceUsage = true
+
+	results := make([][]chain.RPCPing, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		cfg, _ := client.GetChainConfig(name)
+		wg.Add(1)
+		go func(i int, cfg *chain.ChainConfig) {
+			defer wg.Done()
+			results[i] = chain.PingChain(cmd.Context(), cfg, timeout)
+		}(i, cfg)
+	}
+	wg.Wait()
+
+	var unreachable []string
+	for i, name := range names {
+		cfg, _ := client.GetChainConfig(name)
+		if !printChainPings(cmd.OutOrStdout(), name, cfg, results[i]) {
+			unreachable = append(unreachable, name)
+		}
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("unreachable: %s (set a working RPC with clifi setup)", strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+// printChainPings prints one chain's RPC results and reports whether any
+// RPC worked.
+func printChainPings(w io.Writer, name string, cfg *chain.ChainConfig, pings []chain.RPCPing) bool {
+	ok := false
+	for _, p := range pings {
+		if p.Err == nil {
+			ok = true
+		}
+	}
+	header := fmt.Sprintf("%s (%d)", name, cfg.ChainIDInt)
+	if len(pings) == 0 {
+		header += "  ⚠ no RPC URLs"
+	} else if !ok {
+		header += "  ⚠ unreachable"
+	}
+	fmt.Fprintln(w, header)
+	for _, p := range pings {
+		if p.Err != nil {
+			fmt.Fprintf(w, "  ✗ %s  %v\n", p.URL, p.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  ✓ %s  block %d  %s\n", p.URL, p.BlockNumber, p.Latency.Round(time.Millisecond))
+	}
+	return ok
+}
@@ -0,0 +1,562 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/faucet"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/logging"
+	"github.com/yolodolo42/clifi/internal/netcfg"
+	"github.com/yolodolo42/clifi/internal/ui"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change settings in config.yaml",
+	Long: `Read and change settings in ~/.clifi/config.yaml (or the file given
+with --config). Values are checked before they are written, so a typo is
+reported here rather than at the next start.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Example: `  clifi config set theme light
+  clifi config set keys.submit ctrl+s
+  clifi config set llm.providers.openai.api_key '${OPENAI_API_KEY}'`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List settings",
+	RunE:  runConfigList,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.yaml in $EDITOR",
+	Long: `Open the config file in $VISUAL or $EDITOR (vi if neither is set) and
+check it once the editor exits.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+
+	configListCmd.Flags().Bool("all", false, "Include settings that are not set")
+}
+
+// configKey is a setting clifi reads from config.yaml.
+type configKey struct {
+	name string
+	desc string
+	// check validates the value stored under name in v.
+	check func(v *viper.Viper, name string) error
+	// listValue marks settings that hold a list, which only `config edit`
+	// can change.
+	listValue bool
+}
+
+// configKeys returns every known setting, expanded so that pattern keys
+// such as theme_colors.<slot> appear once per slot.
+func configKeys() []configKey {
+	keys := []configKey{
+		{name: "chain", desc: "Default chain", check: checkChain},
+		{name: "log_level", desc: "Log level: debug, info, warn or error", check: checkLogLevel},
+		{name: "log_file", desc: "Log file, or - for stderr (default ~/.clifi/clifi.log)"},
+		{name: "watch.interval", desc: "How often clifi serve checks watches, e.g. 1m", check: checkDuration},
+		{name: "watch.desktop", desc: "Desktop notifications for watches (true/false)", check: checkBool},
+		{name: "watch.webhook", desc: "URL that watch alerts are POSTed to as JSON", check: checkURL},
+		{name: "portfolio.snapshot_interval", desc: "How often clifi serve snapshots the default wallet, e.g. 24h (0 turns it off)", check: checkDurationOrOff},
+		{name: "orders.auto_approve", desc: "Let clifi serve fill triggered limit orders without confirmation, within the policy limits (true/false)", check: checkBool},
+		{name: "tools.raw_call", desc: "Offer the agent raw_call, an eth_call with arbitrary calldata (true/false)", check: checkBool},
+		{name: "network.proxy", desc: "Proxy for all requests, e.g. http://proxy.corp:3128, or direct (default HTTP(S)_PROXY)", check: checkProxy},
+		{name: "network.no_proxy", desc: "Hosts that bypass network.proxy, comma-separated (default NO_PROXY)"},
+		{name: "network.ca_bundle", desc: "PEM file of extra CA certificates to trust, e.g. a TLS-inspecting proxy's", check: checkCABundle},
+		{name: "rpc.proxy", desc: "Proxy for chain RPC requests, overriding network.proxy", check: checkProxy},
+		{name: "rpc.ca_bundle", desc: "CA bundle for chain RPC requests, overriding network.ca_bundle", check: checkCABundle},
+		{name: "kms.proxy", desc: "Proxy for AWS and Google Cloud KMS signer requests, overriding network.proxy", check: checkProxy},
+		{name: "kms.ca_bundle", desc: "CA bundle for KMS signer requests, overriding network.ca_bundle", check: checkCABundle},
+		{name: "llm.budget.monthly_usd", desc: "Monthly cap on estimated spend on paid models, in USD (0 turns it off)", check: checkBudget},
+		{name: "llm.budget.warn_percent", desc: fmt.Sprintf("Share of the budget at which clifi warns (default %d)", agent.DefaultWarnPercent), check: checkPercent},
+		{name: "db.retention.months", desc: "Months of receipts, chat and LLM usage history to keep (0 keeps all); see clifi db prune", check: checkCount},
+		{name: "db.retention.rows", desc: "Newest rows of each history table to keep (0 keeps all); see clifi db prune", check: checkCount},
+		{name: "wallet.scrypt", desc: "Key derivation cost for new wallets: standard, light (test wallets only) or N,P", check: checkScrypt},
+		{name: "wallet.keystore_dir", desc: "Existing geth-style keystore directory whose wallets clifi uses in place", check: checkKeystoreDir},
+		{name: "wallet.keystore_mode", desc: "wallet.keystore_dir access: read-only (default) or read-write, which also creates new wallets there", check: checkKeystoreMode},
+		{name: "wallet.kms.key_arn", desc: "ARN of an AWS KMS secp256k1 key to sign with; it is listed as a wallet"},
+		{name: "wallet.kms.region", desc: "AWS region of wallet.kms.key_arn (default the ARN's, then AWS_REGION)"},
+		{name: "wallet.kms.endpoint", desc: "KMS endpoint URL, e.g. a VPC endpoint (default https://kms.<region>.amazonaws.com)", check: checkURL},
+		{name: "wallet.gcp_kms.key_version", desc: "Google Cloud KMS secp256k1 key version (projects/.../cryptoKeyVersions/<v>) to sign with; it is listed as a wallet", check: checkGCPKeyVersion},
+		{name: "wallet.gcp_kms.endpoint", desc: "Cloud KMS endpoint URL (default https://cloudkms.googleapis.com)", check: checkURL},
+		{name: "theme", desc: "Color theme (" + strings.Join(append(ui.ThemeNames(), ui.CustomTheme), ", ") + ")", check: checkTheme},
+	}
+	for _, slot := range []string{"primary", "success", "warning", "error", "dim", "accent", "highlight", "text", "border", "background"} {
+		desc := "Theme color: ANSI 0-255 or #hex"
+		if slot == "background" {
+			desc = "Terminal background: auto, dark or light"
+		}
+		keys = append(keys, configKey{name: "theme_colors." + slot, desc: desc, check: checkThemeColor})
+	}
+	for _, help := range ui.DefaultKeyMap().Help() {
+		keys = append(keys, configKey{name: "keys." + help.Action, desc: "Key binding: " + help.Desc, check: checkKeyBinding})
+	}
+	for _, id := range llm.AllProviderIDs() {
+		keys = append(keys, configKey{
+			name: fmt.Sprintf("llm.providers.%s.api_key", id),
+			desc: "API key for " + string(id) + "; ${VAR} reads an environment variable",
+		})
+		keys = append(keys,
+			configKey{name: fmt.Sprintf("llm.providers.%s.proxy", id), desc: "Proxy for " + string(id) + ", overriding network.proxy", check: checkProxy},
+			configKey{name: fmt.Sprintf("llm.providers.%s.ca_bundle", id), desc: "CA bundle for " + string(id) + ", overriding network.ca_bundle", check: checkCABundle},
+		)
+		for _, param := range llm.SamplingParams {
+			if param == "thinking_budget" && id != llm.ProviderGemini {
+				continue
+			}
+			keys = append(keys, configKey{
+				name:  fmt.Sprintf("llm.sampling.%s.%s", id, param),
+				desc:  fmt.Sprintf("Default %s for %s models (per model under llm.sampling.%s.models)", param, id, id),
+				check: checkSampling(id, param),
+			})
+		}
+		for _, task := range agent.Tasks {
+			keys = append(keys, configKey{
+				name: fmt.Sprintf("llm.routing.%s.%s", id, task),
+				desc: fmt.Sprintf("Model for %s requests to %s (default the model picked with /model)", task, id),
+			})
+		}
+	}
+	chains := chain.DefaultChains()
+	names := make([]string, 0, len(chains))
+	for name, cfg := range chains {
+		if cfg.IsTestnet {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		keys = append(keys, configKey{name: "faucets." + name, desc: "Faucets for " + name, check: checkFaucets, listValue: true})
+	}
+	return keys
+}
+
+// findConfigKey looks up a setting, or explains that the key is unknown
+// and suggests the closest match.
+func findConfigKey(name string) (configKey, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	keys := configKeys()
+	for _, k := range keys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+
+	var prefixed []string
+	best, bestDist := "", 4
+	for _, k := range keys {
+		if strings.HasPrefix(k.name, name+".") {
+			prefixed = append(prefixed, k.name)
+		}
+		if d := editDistance(name, k.name); d < bestDist {
+			best, bestDist = k.name, d
+		}
+	}
+	msg := fmt.Sprintf("unknown config key %q", name)
+	switch {
+	case len(prefixed) > 0:
+		msg += "; set one of: " + strings.Join(prefixed, ", ")
+	case best != "":
+		msg += fmt.Sprintf("; did you mean %q?", best)
+	}
+	return configKey{}, errors.New(msg + " (see clifi config list --all)")
+}
+
+func checkChain(v *viper.Viper, name string) error {
+	value := v.GetString(name)
+	if _, ok := chain.DefaultChains()[value]; ok || value == chain.LocalChain {
+		return nil
+	}
+	names := []string{chain.LocalChain}
+	for n := range chain.DefaultChains() {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown chain %q (available: %s)", value, strings.Join(names, ", "))
+}
+
+func checkLogLevel(v *viper.Viper, name string) error {
+	_, err := logging.ParseLevel(v.GetString(name))
+	return err
+}
+
+func checkDuration(v *viper.Viper, name string) error {
+	d, err := time.ParseDuration(v.GetString(name))
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%s: expected a positive duration such as 30s or 5m", name)
+	}
+	return nil
+}
+
+// checkDurationOrOff is checkDuration that also accepts 0 for "disabled".
+func checkDurationOrOff(v *viper.Viper, name string) error {
+	if d, err := time.ParseDuration(v.GetString(name)); err == nil && d == 0 {
+		return nil
+	}
+	return checkDuration(v, name)
+}
+
+func checkBool(v *viper.Viper, name string) error {
+	if _, err := strconv.ParseBool(v.GetString(name)); err != nil {
+		return fmt.Errorf("%s: expected true or false", name)
+	}
+	return nil
+}
+
+func checkURL(v *viper.Viper, name string) error {
+	u, err := url.Parse(v.GetString(name))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s: expected an http(s) URL", name)
+	}
+	return nil
+}
+
+// checkSampling validates a generation parameter against provider id's
+// range.
+func checkSampling(id llm.ProviderID, param string) func(*viper.Viper, string) error {
+	return func(v *viper.Viper, name string) error {
+		var s llm.Sampling
+		if err := s.Set(param, v.GetString(name)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := s.Validate(id); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+}
+
+func checkBudget(v *viper.Viper, name string) error {
+	f, err := strconv.ParseFloat(v.GetString(name), 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("%s: expected an amount in USD, e.g. 20", name)
+	}
+	return nil
+}
+
+func checkPercent(v *viper.Viper, name string) error {
+	f, err := strconv.ParseFloat(v.GetString(name), 64)
+	if err != nil || f <= 0 || f > 100 {
+		return fmt.Errorf("%s: expected a percentage between 1 and 100", name)
+	}
+	return nil
+}
+
+func checkScrypt(v *viper.Viper, name string) error {
+	if _, err := wallet.ParseScrypt(v.GetString(name)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkKeystoreDir(v *viper.Viper, name string) error {
+	if dir := v.GetString(name); dir != "" {
+		if _, err := wallet.ResolveKeystoreDir(dir); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func checkKeystoreMode(v *viper.Viper, name string) error {
+	if _, err := wallet.ParseKeystoreMode(v.GetString(name)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkGCPKeyVersion(v *viper.Viper, name string) error {
+	if key := v.GetString(name); key != "" {
+		if _, err := wallet.ParseGCPKeyVersion(key); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// checkCount accepts a whole number, 0 included.
+func checkCount(v *viper.Viper, name string) error {
+	n, err := strconv.Atoi(v.GetString(name))
+	if err != nil || n < 0 {
+		return fmt.Errorf("%s: expected a whole number, e.g. 12 (0 turns it off)", name)
+	}
+	return nil
+}
+
+func checkProxy(v *viper.Viper, name string) error {
+	if err := netcfg.CheckProxyURL(v.GetString(name)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkCABundle(v *viper.Viper, name string) error {
+	if _, err := netcfg.LoadCABundle(v.GetString(name)); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkTheme(v *viper.Viper, name string) error {
+	_, err := ui.LoadTheme(v.GetString(name), nil)
+	return err
+}
+
+func checkThemeColor(v *viper.Viper, name string) error {
+	slot := strings.TrimPrefix(name, "theme_colors.")
+	_, err := ui.LoadTheme("", map[string]string{slot: v.GetString(name)})
+	return err
+}
+
+// checkKeyBinding checks the whole keys section, since a new binding can
+// clash with another action's. Keys are read one by one because
+// GetStringMapString returns only the values passed to Set once any are.
+func checkKeyBinding(v *viper.Viper, _ string) error {
+	overrides := make(map[string]string)
+	for _, name := range ui.KeyActionNames() {
+		if v.IsSet("keys." + name) {
+			overrides[name] = v.GetString("keys." + name)
+		}
+	}
+	_, err := ui.LoadKeyMap(overrides)
+	return err
+}
+
+func checkFaucets(v *viper.Viper, name string) error {
+	var faucets []faucet.Faucet
+	if err := v.UnmarshalKey(name, &faucets); err != nil {
+		return fmt.Errorf("%s: expected a list of {name, url}: %w", name, err)
+	}
+	for i, f := range faucets {
+		if f.URL == "" {
+			return fmt.Errorf("%s[%d]: url is required", name, i)
+		}
+	}
+	return nil
+}
+
+// configPath is the file config commands read and write: --config, the
+// file viper loaded, or ~/.clifi/config.yaml.
+func configPath() (string, error) {
+	if path := viper.ConfigFileUsed(); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".clifi", "config.yaml"), nil
+}
+
+// readConfigFile loads the config file alone, without env or flag values.
+// A missing file yields an empty config.
+func readConfigFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if _, err := os.Stat(path); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	return v, nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	key, err := findConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	if !viper.IsSet(key.name) {
+		return fmt.Errorf("%s is not set", key.name)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), viper.Get(key.name))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	key, err := findConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	if key.listValue {
+		return fmt.Errorf("%s is a list; change it with clifi config edit", key.name)
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	v, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	v.Set(key.name, args[1])
+	if key.check != nil {
+		if err := key.check(v, key.name); err != nil {
+			return err
+		}
+	}
+
+	if err := saveConfigValue(key.name, args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s = %s\n", key.name, args[1])
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	out := cmd.OutOrStdout()
+
+	keys := configKeys()
+	width := 0
+	for _, k := range keys {
+		if (all || viper.IsSet(k.name)) && len(k.name) > width {
+			width = len(k.name)
+		}
+	}
+	for _, k := range keys {
+		if viper.IsSet(k.name) {
+			fmt.Fprintf(out, "%-*s  %v\n", width, k.name, viper.Get(k.name))
+		} else if all {
+			fmt.Fprintf(out, "%-*s  %s\n", width, k.name, "# "+k.desc)
+		}
+	}
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(path, []byte("# clifi configuration; see clifi config list --all\n"), 0600); err != nil {
+			return err
+		}
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// EDITOR often carries flags, e.g. "code --wait".
+	fields := strings.Fields(editor)
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("run %s: %w", editor, err)
+	}
+
+	v, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if err := validateConfig(v, cmd.ErrOrStderr()); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+	return nil
+}
+
+// validateConfig checks every known setting in v. Unknown keys are only
+// warned about, since they may be left over from other versions.
+func validateConfig(v *viper.Viper, warn io.Writer) error {
+	keys := configKeys()
+	var problems []string
+	checked := make(map[string]bool)
+	for _, name := range v.AllKeys() {
+		i := slices.IndexFunc(keys, func(k configKey) bool {
+			return name == k.name || strings.HasPrefix(name, k.name+".")
+		})
+		if i < 0 {
+			if _, err := findConfigKey(name); err != nil {
+				fmt.Fprintf(warn, "warning: %v\n", err)
+			}
+			continue
+		}
+		k := keys[i]
+		if k.check == nil || checked[k.name] {
+			continue
+		}
+		checked[k.name] = true
+		if err := k.check(v, k.name); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// editDistance is the Levenshtein distance between a and b, used to
+// suggest the key a typo was meant to be.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConfigKey(t *testing.T) {
+	k, err := findConfigKey("Theme")
+	require.NoError(t, err)
+	assert.Equal(t, "theme", k.name)
+
+	_, err = findConfigKey("them")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `did you mean "theme"?`)
+
+	_, err = findConfigKey("theme_colors")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "theme_colors.accent")
+
+	_, err = findConfigKey("nonsense.setting.here")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestConfigSetValidatesAndWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("keys:\n  newline: ctrl+j\n"), 0600))
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	require.NoError(t, viper.ReadInConfig())
+
+	var out bytes.Buffer
+	configSetCmd.SetOut(&out)
+
+	require.NoError(t, runConfigSet(configSetCmd, []string{"theme", "light"}))
+	assert.Equal(t, "light", viper.GetString("theme"))
+
+	err := runConfigSet(configSetCmd, []string{"theme", "neon"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown theme")
+
+	err = runConfigSet(configSetCmd, []string{"keys.submit", "ctrl+j"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bound to both")
+
+	err = runConfigSet(configSetCmd, []string{"faucets.sepolia", "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "config edit")
+
+	v, err := readConfigFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "light", v.GetString("theme"))
+	assert.Equal(t, "ctrl+j", v.GetString("keys.newline"))
+	assert.False(t, v.IsSet("keys.submit"))
+}
+
+func TestValidateConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+chain: base
+themee: dark
+theme_colors:
+  accent: purple
+faucets:
+  sepolia:
+    - name: no-url
+`), 0600))
+	v, err := readConfigFile(path)
+	require.NoError(t, err)
+
+	var warn bytes.Buffer
+	err = validateConfig(v, &warn)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "theme_colors.accent")
+	assert.Contains(t, err.Error(), "faucets.sepolia[0]: url is required")
+	assert.NotContains(t, err.Error(), "chain")
+	assert.Contains(t, warn.String(), `did you mean "theme"?`)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+// handleContextCommand shows or sets the session's default chain and
+// wallet, which tool calls fall back to when they omit them.
+//
+//	/context                 show the defaults
+//	/context chain <name>    set the chain
+//	/context wallet <ref>    set the wallet (label, address or n)
+//	/context clear           drop both
+func (m model) handleContextCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+	sub, rest, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	rest = strings.TrimSpace(rest)
+
+	d := m.agent.Defaults()
+	switch strings.ToLower(sub) {
+	case "", "show":
+		m.addSystem(m.agent.DescribeDefaults())
+		m.updateViewport()
+		return m, nil
+	case "chain":
+		d.Chain = rest
+	case "wallet":
+		d.Wallet = rest
+	case "clear", "reset":
+		d = agent.SessionDefaults{}
+	default:
+		m.addError("Usage: /context [chain <name>|wallet <ref>|clear]")
+		m.updateViewport()
+		return m, nil
+	}
+
+	if _, err := m.agent.SetDefaults(d); err != nil {
+		m.addErrorf("Failed to set context: %v", err)
+	} else {
+		m.addSystem(m.agent.DescribeDefaults())
+	}
+	m.updateViewport()
+	return m, nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// maxCopyTargets bounds the /copy list.
+const maxCopyTargets = 10
+
+// handleCopyCommand copies the last assistant message, or an address/hash
+// from recent output, to the clipboard.
+//
+//	/copy            last assistant message
+//	/copy hash       most recent transaction hash
+//	/copy address    most recent address
+//	/copy list       numbered recent addresses and hashes
+//	/copy <n>        entry n from /copy list
+func (m model) handleCopyCommand(arg string) (tea.Model, tea.Cmd) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	targets := m.copyTargets()
+
+	var text, label string
+	switch arg {
+	case "":
+		for i := len(m.messages) - 1; i >= 0; i-- {
+			if m.messages[i].kind == "assistant" {
+				text, label = m.messages[i].content, "last response"
+				break
+			}
+		}
+		if text == "" {
+			m.addError("Nothing to copy yet.")
+			m.updateViewport()
+			return m, nil
+		}
+
+	case "list":
+		if len(targets) == 0 {
+			m.addSystem("No addresses or hashes in the conversation yet.")
+			m.updateViewport()
+			return m, nil
+		}
+		var b strings.Builder
+		b.WriteString("Copy targets (/copy <n>):\n")
+		for i, t := range targets {
+			fmt.Fprintf(&b, "  %2d  %-8s %s\n", i+1, t.Kind, t.Value)
+		}
+		m.addSystem(strings.TrimRight(b.String(), "\n"))
+		m.updateViewport()
+		return m, nil
+
+	case "hash", "tx", "address", "addr":
+		kind := ui.CopyHash
+		if strings.HasPrefix(arg, "addr") {
+			kind = ui.CopyAddress
+		}
+		for _, t := range targets {
+			if t.Kind == kind {
+				text, label = t.Value, kind
+				break
+			}
+		}
+		if text == "" {
+			m.addErrorf("No %s in the conversation yet.", kind)
+			m.updateViewport()
+			return m, nil
+		}
+
+	default:
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(targets) {
+			m.addError("Usage: /copy [hash|address|list|<n>]")
+			m.updateViewport()
+			return m, nil
+		}
+		text, label = targets[n-1].Value, targets[n-1].Kind
+	}
+
+	if err := ui.CopyToClipboard(text); err != nil {
+		m.addErrorf("Copy failed: %v", err)
+	} else if label == "last response" {
+		m.addSystem("Copied last response to clipboard.")
+	} else {
+		m.addSystem(fmt.Sprintf("Copied %s %s to clipboard.", label, text))
+	}
+	m.updateViewport()
+	return m, nil
+}
+
+// copyTargets lists addresses and hashes from tool results and responses,
+// newest message first.
+func (m model) copyTargets() []ui.CopyTarget {
+	var targets []ui.CopyTarget
+	seen := make(map[string]bool)
+	for i := len(m.messages) - 1; i >= 0 && len(targets) < maxCopyTargets; i-- {
+		msg := m.messages[i]
+		if msg.kind != "tool_result" && msg.kind != "assistant" {
+			continue
+		}
+		for _, t := range ui.FindCopyTargets(msg.content) {
+			key := strings.ToLower(t.Value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, t)
+			if len(targets) == maxCopyTargets {
+				break
+			}
+		}
+	}
+	return targets
+}
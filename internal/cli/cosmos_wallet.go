@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/cosmos"
+)
+
+var walletCosmosCmd = &cobra.Command{
+	Use:   "cosmos",
+	Short: "Manage Cosmos SDK keys",
+	Long:  `Create, import, and list secp256k1 keys for Cosmos SDK chains. One key has an address on every supported chain.`,
+}
+
+var walletCosmosCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new Cosmos key",
+	RunE:  runWalletCosmosCreate,
+}
+
+var walletCosmosImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a Cosmos key from a hex private key",
+	RunE:  runWalletCosmosImport,
+}
+
+var walletCosmosListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Cosmos keys and their addresses",
+	RunE:  runWalletCosmosList,
+}
+
+func init() {
+	walletCmd.AddCommand(walletCosmosCmd)
+	walletCosmosCmd.AddCommand(walletCosmosCreateCmd)
+	walletCosmosCmd.AddCommand(walletCosmosImportCmd)
+	walletCosmosCmd.AddCommand(walletCosmosListCmd)
+
+	walletCosmosImportCmd.Flags().String("key", "", "Private key to import (hex, as exported by Keplr)")
+}
+
+// printCosmosAddresses prints a key's address on each default chain.
+func printCosmosAddresses(key cosmos.Key, indent string) {
+	chains := cosmos.DefaultChains()
+	for _, name := range slices.Sorted(maps.Keys(chains)) {
+		fmt.Printf("%s%-10s %s\n", indent, name+":", chains[name].Address(key.Address))
+	}
+}
+
+func runWalletCosmosCreate(cmd *cobra.Command, args []string) error {
+	ks, err := cosmos.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize cosmos keystore: %w", err)
+	}
+
+	password, err := readNewPassword("Enter password for new Cosmos wallet: ")
+	if err != nil {
+		return err
+	}
+
+	key, err := ks.Create(password)
+	if err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+
+	fmt.Println("\nCosmos wallet created successfully!")
+	printCosmosAddresses(key, "")
+	printWeakScrypt()
+	fmt.Println("\nIMPORTANT: Back up your keystore file and remember your password!")
+	return nil
+}
+
+func runWalletCosmosImport(cmd *cobra.Command, args []string) error {
+	privateKey, _ := cmd.Flags().GetString("key")
+	if privateKey == "" {
+		fmt.Print("Enter private key (hex): ")
+		var input string
+		_, _ = fmt.Scanln(&input)
+		privateKey = strings.TrimSpace(input)
+	}
+	if privateKey == "" {
+		return fmt.Errorf("private key is required")
+	}
+
+	ks, err := cosmos.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize cosmos keystore: %w", err)
+	}
+
+	password, err := readNewPassword("Enter password to encrypt wallet: ")
+	if err != nil {
+		return err
+	}
+
+	key, err := ks.Import(privateKey, password)
+	if err != nil {
+		return fmt.Errorf("failed to import key: %w", err)
+	}
+
+	fmt.Println("\nCosmos wallet imported successfully!")
+	printCosmosAddresses(key, "")
+	printWeakScrypt()
+	return nil
+}
+
+func runWalletCosmosList(cmd *cobra.Command, args []string) error {
+	ks, err := cosmos.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize cosmos keystore: %w", err)
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No Cosmos wallets found.")
+		fmt.Println("Use 'clifi wallet cosmos create' to create one.")
+		return nil
+	}
+
+	fmt.Printf("Found %d Cosmos wallet(s):\n\n", len(keys))
+	for i, k := range keys {
+		fmt.Printf("%d.\n", i+1)
+		printCosmosAddresses(k, "   ")
+	}
+	return nil
+}
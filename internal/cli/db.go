@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage clifi's local database",
+}
+
+var dbPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old history from receipts.db and shrink the file",
+	Long: `Delete receipts, sent transactions, chat messages and LLM usage older than
+--months, or beyond the newest --rows of each, then VACUUM the DB so the
+file shrinks. Both default to db.retention.months and db.retention.rows;
+when both are set, both apply. The P&L ledger, token metadata and
+transactions still pending are always kept.
+
+clifi serve prunes to the configured retention once a day and vacuums
+when a quarter of the file is free space.`,
+	Example: `  clifi db prune --months 12 --dry-run
+  clifi db prune --rows 10000`,
+	Args: cobra.NoArgs,
+	RunE: runDBPrune,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Bring receipts.db up to this version's schema",
+	Long: `Apply the schema migrations receipts.db is missing. clifi also does this
+whenever it opens the DB; this command does it on demand and shows what
+changed. The DB is first copied to receipts.db.v<N>.bak, N being its
+schema version before.
+
+--dry-run lists the pending migrations and changes nothing.`,
+	Example: `  clifi db migrate --dry-run
+  clifi db migrate`,
+	Args: cobra.NoArgs,
+	RunE: runDBMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbPruneCmd)
+
+	dbMigrateCmd.Flags().Bool("dry-run", false, "Show pending migrations without applying them")
+	dbPruneCmd.Flags().Int("months", 0, "Keep the last N months (default db.retention.months)")
+	dbPruneCmd.Flags().Int("rows", 0, "Keep the newest N rows of each table (default db.retention.rows)")
+	dbPruneCmd.Flags().Bool("dry-run", false, "Count what would be deleted without deleting it")
+}
+
+// retention returns the configured history limits.
+func retention() agent.Retention {
+	return agent.Retention{Months: viper.GetInt("db.retention.months"), Rows: viper.GetInt("db.retention.rows")}
+}
+
+func runDBMigrate(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	cmd.SilenceUsage = true
+
+	plan, err := agent.PlanMigrations(getDataDir())
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(plan.Pending) == 0 {
+		fmt.Fprintf(out, "%s is up to date (schema version %d).\n", plan.Path, plan.Current)
+		return nil
+	}
+	printMigrationPlan(out, plan)
+	if dryRun {
+		fmt.Fprintln(out, "Dry run: nothing changed. Run clifi db migrate to apply.")
+		return nil
+	}
+
+	backup, err := agent.MigrateReceiptDB(getDataDir())
+	if backup != "" {
+		fmt.Fprintf(out, "Backed up to %s\n", backup)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s is at schema version %d.\n", plan.Path, agent.SchemaVersion())
+	return nil
+}
+
+func printMigrationPlan(w io.Writer, plan agent.MigrationPlan) {
+	if plan.Exists {
+		fmt.Fprintf(w, "%s: schema version %d, %d pending:\n", plan.Path, plan.Current, len(plan.Pending))
+	} else {
+		fmt.Fprintf(w, "%s: not created yet, %d to apply:\n", plan.Path, len(plan.Pending))
+	}
+	for _, m := range plan.Pending {
+		fmt.Fprintf(w, "  %04d %s\n", m.Version, m.Name)
+	}
+}
+
+func runDBPrune(cmd *cobra.Command, args []string) error {
+	r := retention()
+	if cmd.Flags().Changed("months") {
+		r.Months, _ = cmd.Flags().GetInt("months")
+	}
+	if cmd.Flags().Changed("rows") {
+		r.Rows, _ = cmd.Flags().GetInt("rows")
+	}
+	if r.Months < 0 || r.Rows < 0 {
+		return fmt.Errorf("--months and --rows can't be negative")
+	}
+	if r.IsZero() {
+		return fmt.Errorf("nothing to prune to: pass --months or --rows, or set db.retention.months or db.retention.rows")
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	cmd.SilenceUsage = true
+
+	rs, err := agent.OpenReceiptStore(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+	before, _, err := rs.Size()
+	if err != nil {
+		return err
+	}
+	pruned, err := rs.Prune(r, time.Now(), dryRun)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	var total int64
+	for _, p := range pruned {
+		total += p.Rows
+	}
+	if total == 0 {
+		fmt.Fprintln(out, "Nothing older than the retention to delete.")
+	} else {
+		fmt.Fprintf(out, "%s %s rows:\n", verb, groupDigits(int(total)))
+		for _, p := range pruned {
+			if p.Rows > 0 {
+				fmt.Fprintf(out, "  %-14s %10s\n", p.Table, groupDigits(int(p.Rows)))
+			}
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	if _, err := rs.Vacuum(0); err != nil {
+		return err
+	}
+	after, _, err := rs.Size()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s: %s, was %s.\n", agent.ReceiptDBFile, formatSize(after), formatSize(before))
+	return nil
+}
+
+// formatSize renders a byte count, e.g. 12.4 MB.
+func formatSize(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGT"[exp])
+}
+
+// dbMaintenanceInterval is how often clifi serve prunes and vacuums the DB.
+const dbMaintenanceInterval = 24 * time.Hour
+
+// runDBMaintenance prunes the receipt DB to the configured retention and
+// vacuums it when fragmented, now and every dbMaintenanceInterval until
+// ctx is done.
+func runDBMaintenance(ctx context.Context) {
+	for {
+		if err := maintainDB(retention()); err != nil {
+			slog.Warn("receipts db maintenance failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dbMaintenanceInterval):
+		}
+	}
+}
+
+func maintainDB(r agent.Retention) error {
+	rs, err := agent.OpenReceiptStore(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+	if !r.IsZero() {
+		if _, err := rs.Prune(r, time.Now(), false); err != nil {
+			return err
+		}
+	}
+	_, err = rs.Vacuum(agent.AutoVacuumFreeRatio)
+	return err
+}
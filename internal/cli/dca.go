@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/dca"
+)
+
+var dcaCmd = &cobra.Command{
+	Use:   "dca",
+	Short: "Recurring buys",
+	Long: `Manage recurring swaps that 'clifi serve' runs on schedule, such as
+"swap 50 USDC to ETH weekly". Each run quotes the swap fresh and goes
+through the same policy checks as a swap made in chat (slippage ceiling,
+per-tx limit, allow/deny lists); every run is appended to audit.jsonl in
+the data dir.
+
+Schedules: hourly, daily, weekly, monthly, "every 3 days", "every 12h".
+A new plan first runs at serve's next check; runs missed while serve was
+stopped are skipped, not made up.`,
+}
+
+var dcaAddCmd = &cobra.Command{
+	Use:   "add <plan>",
+	Short: "Add a recurring swap",
+	Example: `  clifi dca add "swap 50 USDC to ETH weekly" --chain base
+  clifi dca add "swap 0.05 ETH to USDC on arbitrum every 3 days" --wallet trading`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDCAAdd,
+}
+
+var dcaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recurring swaps and their last run",
+	RunE:  runDCAList,
+}
+
+var dcaRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a recurring swap",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDCARm,
+}
+
+func init() {
+	rootCmd.AddCommand(dcaCmd)
+	dcaCmd.AddCommand(dcaAddCmd)
+	dcaCmd.AddCommand(dcaListCmd)
+	dcaCmd.AddCommand(dcaRmCmd)
+
+	dcaAddCmd.Flags().String("chain", "", "Chain to swap on, unless the plan says \"on <chain>\"")
+	dcaAddCmd.Flags().String("wallet", "", "Wallet label or address (default wallet when omitted)")
+	dcaAddCmd.Flags().Uint32("slippage-bps", 0, "Slippage tolerance in basis points (default from the policy)")
+}
+
+func runDCAAdd(cmd *cobra.Command, args []string) error {
+	chainFlag, _ := cmd.Flags().GetString("chain")
+	walletRef, _ := cmd.Flags().GetString("wallet")
+	// Unquoted plans arrive as several args.
+	plan, err := dca.ParsePlan(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	chainFlag = strings.ToLower(chainFlag)
+	switch {
+	case plan.Chain == "":
+		plan.Chain = chainFlag
+	case chainFlag != "" && chainFlag != plan.Chain:
+		return fmt.Errorf("plan says %s but --chain is %s", plan.Chain, chainFlag)
+	}
+	if plan.Chain == "" {
+		return errors.New("which chain? pass --chain or say \"on <chain>\" in the plan")
+	}
+	if cmd.Flags().Changed("slippage-bps") {
+		bps, _ := cmd.Flags().GetUint32("slippage-bps")
+		plan.SlippageBps = &bps
+	}
+	address, err := resolveAddress(walletRef)
+	if err != nil {
+		return err
+	}
+	plan.Wallet = address.Hex()
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+	if err := tr.CheckSwap(planSwap(plan)); err != nil {
+		return err
+	}
+
+	plan, err = dca.NewStore(getDataDir()).Add(plan)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added DCA #%d: %s from %s\n", plan.ID, plan, plan.Wallet)
+	fmt.Println("Run 'clifi serve' to execute it on schedule.")
+	return nil
+}
+
+func runDCAList(cmd *cobra.Command, args []string) error {
+	plans, err := dca.NewStore(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		fmt.Println("No recurring swaps. Add one with: clifi dca add \"swap 50 USDC to ETH weekly\" --chain base")
+		return nil
+	}
+	for _, p := range plans {
+		line := fmt.Sprintf("#%-3d %s  from %s  runs %d  next %s", p.ID, p, shortAddress(p.Wallet), p.Runs, p.NextRun.Local().Format(time.DateTime))
+		if !p.RetryAt.IsZero() {
+			line += fmt.Sprintf(" (retry at %s)", p.RetryAt.Local().Format(time.DateTime))
+		}
+		if p.LastError != "" {
+			line += "  last error: " + p.LastError
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runDCARm(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid plan ID: %s", args[0])
+	}
+	cmd.SilenceUsage = true
+	ok, err := dca.NewStore(getDataDir()).Remove(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no DCA #%d", id)
+	}
+	fmt.Printf("Removed DCA #%d\n", id)
+	return nil
+}
+
+func planSwap(p dca.Plan) agent.SwapRequest {
+	return agent.SwapRequest{
+		Chain:       p.Chain,
+		From:        p.Wallet,
+		FromToken:   p.FromToken,
+		ToToken:     p.ToToken,
+		Amount:      p.Amount,
+		SlippageBps: p.SlippageBps,
+	}
+}
+
+func shortAddress(a string) string {
+	if len(a) <= 10 {
+		return a
+	}
+	return a[:6] + "…" + a[len(a)-4:]
+}
+
+// dcaExecutor runs plans through the agent's swap path, so they are held
+// to the same policy as a swap made in chat.
+type dcaExecutor struct {
+	tr       *agent.ToolRegistry
+	password string
+}
+
+func (e dcaExecutor) Execute(ctx context.Context, p dca.Plan) (dca.Execution, error) {
+	hash, summary, err := executeSwap(ctx, e.tr, e.password, planSwap(p))
+	return dca.Execution{TxHash: hash, Summary: summary}, err
+}
+
+// executeSwap quotes, checks and sends req, waiting for it to be mined.
+func executeSwap(ctx context.Context, tr *agent.ToolRegistry, password string, req agent.SwapRequest) (hash, summary string, err error) {
+	prepared, err := tr.PrepareSwap(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	// Nobody is there to acknowledge a warning.
+	if len(prepared.Warnings) > 0 {
+		return "", "", fmt.Errorf("refusing unattended swap: %s", strings.Join(prepared.Warnings, " "))
+	}
+	return sendSwap(ctx, tr, password, prepared)
+}
+
+func sendSwap(ctx context.Context, tr *agent.ToolRegistry, password string, prepared *agent.PreparedSwap) (hash, summary string, err error) {
+	res, err := tr.SwapPrepared(ctx, prepared, password)
+	if err != nil {
+		return "", "", err
+	}
+	if res.Confirmed != nil && !res.Confirmed.Success {
+		return "", "", fmt.Errorf("swap %s reverted", res.Hash.Hex())
+	}
+	summary = fmt.Sprintf("Swapped %s %s for ~%s %s (min %s) on %s via %s",
+		prepared.Amount, prepared.FromSymbol, prepared.ExpectedOut, prepared.ToSymbol, prepared.MinOut, prepared.Chain, prepared.Route)
+	if res.Confirmed == nil {
+		summary += "; not mined yet"
+	}
+	return res.Hash.Hex(), summary, nil
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode <calldata>",
+	Short: "Decode transaction calldata",
+	Long: `Decode raw calldata into the function it calls and its arguments. No
+RPC connection is needed: the selector is matched against built-in
+signatures (token standards, WETH, Uniswap routers), then a contract ABI
+given with --abi, then 4byte.directory. When several registered
+signatures fit, the oldest is used and the others are listed.
+
+Pass --signature to decode with a known function instead, and --offline
+to stay off the network.`,
+	Example: `  clifi decode 0xa9059cbb000000000000000000000000...
+  clifi decode "$(cat calldata.txt)" --abi router.json
+  clifi decode 0x12345678... --signature "swap(address,uint256,bytes)" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDecode,
+}
+
+func init() {
+	rootCmd.AddCommand(decodeCmd)
+
+	decodeCmd.Flags().String("abi", "", "Contract JSON ABI file")
+	decodeCmd.Flags().String("signature", "", "Function signature to decode with, e.g. transfer(address,uint256)")
+	decodeCmd.Flags().Bool("offline", false, "Don't look selectors up on 4byte.directory")
+	decodeCmd.Flags().Bool("json", false, "Output JSON")
+}
+
+func runDecode(cmd *cobra.Command, args []string) error {
+	req := agent.DecodeRequest{Data: args[0]}
+	req.Signature, _ = cmd.Flags().GetString("signature")
+	req.Offline, _ = cmd.Flags().GetBool("offline")
+	if path, _ := cmd.Flags().GetString("abi"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read ABI: %w", err)
+		}
+		req.ABI = string(data)
+	}
+	cmd.SilenceUsage = true
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	r, err := agent.DecodeCalldata(ctx, req)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	fmt.Fprint(out, r.Text())
+	return nil
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/demo"
+)
+
+// RunDemo starts the REPL with the scripted provider and simulated chains.
+// Everything lives in a temporary data dir that is removed on exit.
+func RunDemo() error {
+	env, err := demo.Start()
+	if err != nil {
+		return fmt.Errorf("failed to start demo: %w", err)
+	}
+	defer env.Close()
+
+	dataDirOverride = env.DataDir
+	defer func() { dataDirOverride = "" }()
+
+	ag := agent.NewWithProvider(demo.Provider(env.Address), env.DataDir)
+	defer ag.Close()
+	return runREPL(ag)
+}
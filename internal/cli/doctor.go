@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/doctor"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the clifi installation",
+	Long: `Check that clifi is set up correctly and say how to fix what isn't:
+
+  - data directory permissions
+  - provider credentials (a one-token request to each provider)
+  - RPC reachability and chain IDs for every enabled chain
+  - keystore files and the default wallet
+  - receipts database integrity
+
+Exits non-zero when a check fails. Use --offline to skip the network checks.`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().Bool("offline", false, "Skip provider pings and RPC calls")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	offline, _ := cmd.Flags().GetBool("offline")
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+
+	checks := doctor.Run(ctx, doctor.Options{DataDir: getDataDir(), Offline: offline})
+	printChecks(cmd.OutOrStdout(), checks)
+
+	if doctor.Failed(checks) {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("some checks failed")
+	}
+	return nil
+}
+
+// printChecks lists results under their group, with the fix for anything
+// that isn't OK.
+func printChecks(w io.Writer, checks []doctor.Check) {
+	group := ""
+	var warned int
+	for _, c := range checks {
+		if c.Group != group {
+			if group != "" {
+				fmt.Fprintln(w)
+			}
+			group = c.Group
+			fmt.Fprintln(w, group)
+		}
+		line := fmt.Sprintf("  %s %s", statusMark(c.Status), c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		fmt.Fprintln(w, line)
+		if c.Fix != "" {
+			fmt.Fprintf(w, "      fix: %s\n", c.Fix)
+		}
+		if c.Status == doctor.StatusWarn {
+			warned++
+		}
+	}
+	fmt.Fprintln(w)
+	switch {
+	case doctor.Failed(checks):
+		fmt.Fprintln(w, "Problems found; see the fixes above.")
+	case warned > 0:
+		fmt.Fprintf(w, "No failures, %d warning(s).\n", warned)
+	default:
+		fmt.Fprintln(w, "Everything looks good.")
+	}
+}
+
+func statusMark(s doctor.Status) string {
+	switch s {
+	case doctor.StatusOK:
+		return "✓"
+	case doctor.StatusWarn:
+		return "!"
+	case doctor.StatusFail:
+		return "✗"
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleExportCommand writes the conversation to a file.
+//
+//	/export              Markdown to ./clifi-<id>.md
+//	/export json         JSON to ./clifi-<id>.json
+//	/export <path>       format from the extension (.json, else Markdown)
+func (m model) handleExportCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("No conversation to export.")
+		m.updateViewport()
+		return m, nil
+	}
+	conv := m.agent.Export()
+	if len(conv.Turns) == 0 {
+		m.addError("No conversation to export yet.")
+		m.updateViewport()
+		return m, nil
+	}
+
+	path := arg
+	switch strings.ToLower(arg) {
+	case "", "md", "markdown":
+		path = "clifi-" + conv.ID + ".md"
+	case "json":
+		path = "clifi-" + conv.ID + ".json"
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		if data, err = conv.ToJSON(); err != nil {
+			m.addErrorf("Export failed: %v", err)
+			m.updateViewport()
+			return m, nil
+		}
+	} else {
+		data = []byte(conv.ToMarkdown())
+	}
+
+	// O_EXCL: never overwrite an existing file with a transcript.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		m.addErrorf("Export failed: %v", err)
+		m.updateViewport()
+		return m, nil
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		m.addErrorf("Export failed: %v", err)
+	} else {
+		m.addSystem(fmt.Sprintf("Exported %d turns to %s", len(conv.Turns), path))
+	}
+	m.updateViewport()
+	return m, nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/faucet"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+var faucetCmd = &cobra.Command{
+	Use:   "faucet",
+	Short: "Request testnet funds",
+	Long: `Request testnet funds from the faucets configured under faucets.<chain>
+in config.yaml, then wait until the balance arrives.`,
+	RunE: runFaucet,
+}
+
+func init() {
+	rootCmd.AddCommand(faucetCmd)
+
+	faucetCmd.Flags().String("chain", "sepolia", "Testnet chain to fund (sepolia, base-sepolia)")
+	faucetCmd.Flags().String("address", "", "Keystore address to fund (uses first wallet if not specified)")
+	faucetCmd.Flags().Duration("timeout", 2*time.Minute, "How long to wait for funds to arrive")
+	faucetCmd.Flags().Bool("no-wait", false, "Return after the faucet accepts the request")
+}
+
+func runFaucet(cmd *cobra.Command, args []string) error {
+	chainName, _ := cmd.Flags().GetString("chain")
+	addressFlag, _ := cmd.Flags().GetString("address")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	cfg, err := client.GetChainConfig(chainName)
+	if err != nil {
+		return err
+	}
+	if !cfg.IsTestnet {
+		return fmt.Errorf("faucets are only available on testnets; %s is a mainnet", chainName)
+	}
+
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to load wallets: %w", err)
+	}
+	var address common.Address
+	if addressFlag != "" {
+		if !common.IsHexAddress(addressFlag) {
+			return fmt.Errorf("invalid address: %s", addressFlag)
+		}
+		address = common.HexToAddress(addressFlag)
+		if !km.HasAccount(address) {
+			return fmt.Errorf("address %s is not in the keystore", address.Hex())
+		}
+	} else {
+		def, err := km.DefaultAccount()
+		if err != nil {
+			return fmt.Errorf("no wallets found. Create one with 'clifi wallet create'")
+		}
+		address = def.Address
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+30*time.Second)
+	defer cancel()
+
+	baseline, err := client.GetBalance(ctx, chainName, address)
+	if err != nil {
+		return fmt.Errorf("failed to read starting balance: %w", err)
+	}
+
+	fmt.Printf("Requesting %s funds for %s...\n", chainName, address.Hex())
+	res, err := faucet.Request(ctx, &http.Client{Timeout: 30 * time.Second}, faucet.Configured(chainName), chainName, cfg.ChainIDInt, address)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Accepted by %s\n", res.Faucet)
+	if res.TxHash != "" {
+		fmt.Printf("  Funding tx: %s\n", res.TxHash)
+		if url := cfg.TxURL(res.TxHash); url != "" {
+			fmt.Printf("  %s\n", url)
+		}
+	}
+	if noWait {
+		return nil
+	}
+
+	fmt.Println("Waiting for funds to arrive...")
+	waitCtx, waitCancel := context.WithTimeout(ctx, timeout)
+	defer waitCancel()
+	bal, err := faucet.WaitForFunds(waitCtx, client, chainName, address, baseline, 3*time.Second)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ New balance: %s %s\n", chain.FormatBalance(bal, 18), cfg.NativeCurrency)
+	return nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/gasreport"
+)
+
+var gasCmd = &cobra.Command{
+	Use:   "gas",
+	Short: "Gas spending analytics",
+}
+
+var gasReportCmd = &cobra.Command{
+	Use:   "report [address|wallet]",
+	Short: "Summarize gas spent per chain and month",
+	Long: `Summarize the gas your wallets (or one of them) spent per chain and
+month, from the receipts clifi stored for transactions it sent or looked
+up. For each month it shows the average price paid next to the average
+base fee of the blocks the transactions landed in, and it lists the
+transactions that paid well over the base fee, so you can tell whether a
+lower priority fee would do.
+
+The first report fetches each receipt's sender and block once and caches
+them; USD values are those recorded with the transaction.`,
+	Example: `  clifi gas report
+  clifi gas report trading --threshold 50`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGasReport,
+}
+
+func init() {
+	rootCmd.AddCommand(gasCmd)
+	gasCmd.AddCommand(gasReportCmd)
+
+	gasReportCmd.Flags().Float64("threshold", gasreport.DefaultThreshold*100, "Flag transactions that paid more than this percentage over the base fee")
+}
+
+func runGasReport(cmd *cobra.Command, args []string) error {
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	if threshold < 0 {
+		return errors.New("--threshold must not be negative")
+	}
+	var senders []common.Address
+	if len(args) > 0 {
+		address, err := resolveAddress(args[0])
+		if err != nil {
+			return err
+		}
+		senders = []common.Address{address}
+	}
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Minute)
+	defer cancel()
+	report, err := tr.GasReport(ctx, senders, threshold/100)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), report.Text())
+	return nil
+}
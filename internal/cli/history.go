@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [address|wallet]",
+	Short: "Show incoming and outgoing transfers",
+	Long: `Show a wallet's ERC-20 transfers, in and out, together with the
+transactions clifi sent from it, newest first. Without an explorer API the
+token transfers come from Transfer logs, so only the last --blocks blocks
+of each chain are scanned; incoming native transfers leave no logs and are
+not listed.
+
+Without an argument the default wallet is used, and without --chains every
+enabled mainnet is queried.`,
+	Example: `  clifi history
+  clifi history trading --chains base --blocks 50000
+  clifi history 0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().StringSlice("chains", nil, "Chains to query (default: enabled mainnets)")
+	historyCmd.Flags().Uint64("blocks", agent.DefaultHistoryBlocks, "Blocks to scan for token transfers on each chain, back from the head")
+	historyCmd.Flags().Int("limit", 50, "Show at most this many transfers")
+	historyCmd.Flags().Bool("json", false, "Print JSON instead of a table")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	chains, _ := cmd.Flags().GetStringSlice("chains")
+	blocks, _ := cmd.Flags().GetUint64("blocks")
+	limit, _ := cmd.Flags().GetInt("limit")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	if blocks == 0 {
+		return errors.New("--blocks must be positive")
+	}
+	if limit <= 0 {
+		return errors.New("--limit must be positive")
+	}
+
+	var ref string
+	if len(args) > 0 {
+		ref = args[0]
+	}
+	address, err := resolveAddress(ref)
+	if err != nil {
+		return err
+	}
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		chains = enabledMainnets(client)
+	}
+	for i, name := range chains {
+		chains[i] = strings.ToLower(strings.TrimSpace(name))
+		if _, err := client.GetChainConfig(chains[i]); err != nil {
+			client.Close()
+			return err
+		}
+	}
+	client.Close()
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 3*time.Minute)
+	defer cancel()
+	report, err := tr.History(ctx, agent.HistoryRequest{Address: address, Chains: chains, Blocks: blocks, Limit: limit})
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	fmt.Fprint(cmd.OutOrStdout(), report.Text())
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// maxHistory bounds the input history kept for the session.
+const maxHistory = 100
+
+// handleKeysCommand opens the key bindings overlay.
+func (m model) handleKeysCommand() (tea.Model, tea.Cmd) {
+	m.mode = modeKeysHelp
+	m.prompt.Blur()
+	return m, nil
+}
+
+// updateKeysHelp closes the overlay on any key.
+func (m model) updateKeysHelp(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.mode = modeChat
+		return m, m.prompt.Focus()
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// keysHelpView renders the current bindings and how to change them.
+func (m model) keysHelpView() string {
+	var b strings.Builder
+	b.WriteString(ui.TitleStyle.Render("Key bindings"))
+	b.WriteString("\n\n")
+	for _, h := range m.keys.Help() {
+		b.WriteString("  ")
+		b.WriteString(ui.SelectorActive.Render(fmt.Sprintf("%-20s", h.Keys)))
+		b.WriteString(ui.SelectorItemStyle.Render(fmt.Sprintf("%-44s", h.Desc)))
+		b.WriteString(ui.SelectorDim.Render("keys." + h.Action))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(ui.SelectorDim.Render("  Enter sends single-line input; Tab completes commands."))
+	b.WriteString("\n")
+	b.WriteString(ui.SelectorDim.Render("  Remap in ~/.clifi/config.yaml, e.g. keys: {submit: \"ctrl+s\"} (comma-separate several keys)."))
+	b.WriteString("\n\n")
+	b.WriteString(ui.HelpStyle.Render("Press any key to close"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pushHistory records submitted input, skipping immediate repeats.
+func (m *model) pushHistory(input string) {
+	if n := len(m.history); n == 0 || m.history[n-1] != input {
+		m.history = append(m.history, input)
+		if len(m.history) > maxHistory {
+			m.history = m.history[len(m.history)-maxHistory:]
+		}
+	}
+	m.historyIdx = len(m.history)
+	m.historyDraft = ""
+}
+
+// historyPrev replaces the input with the previous history entry, saving
+// the unsent draft on the first step back.
+func (m *model) historyPrev() {
+	if m.historyIdx <= 0 {
+		return
+	}
+	if m.historyIdx == len(m.history) {
+		m.historyDraft = m.prompt.Value()
+	}
+	m.historyIdx--
+	m.prompt.SetValue(m.history[m.historyIdx])
+}
+
+// historyNext moves forward through history, ending at the saved draft.
+func (m *model) historyNext() {
+	if m.historyIdx >= len(m.history) {
+		return
+	}
+	m.historyIdx++
+	if m.historyIdx == len(m.history) {
+		m.prompt.SetValue(m.historyDraft)
+		return
+	}
+	m.prompt.SetValue(m.history[m.historyIdx])
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/memory"
+)
+
+var memoryCmd = &cobra.Command{
+	Use:   "memory",
+	Short: "Notes the agent keeps about you",
+	Long: `Manage the agent's memory: short notes such as addresses you mention or
+preferences like "always use fast gas", saved with the remember tool and
+included in every conversation. Memory is stored unencrypted in the data
+directory, so each data directory keeps its own.`,
+}
+
+var memoryListCmd = &cobra.Command{
+	Use:   "list [query]",
+	Short: "List remembered notes, optionally matching a query",
+	RunE:  runMemoryList,
+}
+
+var memorySetCmd = &cobra.Command{
+	Use:     "set <key> <value>",
+	Short:   "Remember a note",
+	Example: `  clifi memory set gas-preference "always use fast gas"`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    runMemorySet,
+}
+
+var memoryRmCmd = &cobra.Command{
+	Use:   "rm <key>",
+	Short: "Forget a note",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMemoryRm,
+}
+
+var memoryClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Forget every note",
+	RunE:  runMemoryClear,
+}
+
+func init() {
+	rootCmd.AddCommand(memoryCmd)
+	memoryCmd.AddCommand(memoryListCmd)
+	memoryCmd.AddCommand(memorySetCmd)
+	memoryCmd.AddCommand(memoryRmCmd)
+	memoryCmd.AddCommand(memoryClearCmd)
+}
+
+func runMemoryList(cmd *cobra.Command, args []string) error {
+	entries, err := memory.NewStore(getDataDir()).Search(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if len(args) > 0 {
+			fmt.Println("No matching notes.")
+			return nil
+		}
+		fmt.Println("Nothing remembered. Ask the agent to remember something, or: clifi memory set <key> <value>")
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Printf("%-20s %s  (%s)\n", e.Key, e.Value, e.UpdatedAt.Local().Format(time.DateOnly))
+	}
+	return nil
+}
+
+func runMemorySet(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	// Unquoted values arrive as several args.
+	e, err := memory.NewStore(getDataDir()).Set(args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Remembered %s: %s\n", e.Key, e.Value)
+	return nil
+}
+
+func runMemoryRm(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	key := memory.NormalizeKey(args[0])
+	ok, err := memory.NewStore(getDataDir()).Remove(key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("nothing remembered under %q", key)
+	}
+	fmt.Printf("Forgot %s\n", key)
+	return nil
+}
+
+func runMemoryClear(cmd *cobra.Command, args []string) error {
+	n, err := memory.NewStore(getDataDir()).Clear()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Forgot %d note(s)\n", n)
+	return nil
+}
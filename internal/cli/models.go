@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models [search]",
+	Short: "List models from connected providers",
+	Long: `List the models of every connected provider, or of one with --provider.
+Lists are fetched live where the provider has a models endpoint, so they
+include new models; OpenRouter's has current prices too. Costs are USD
+per million tokens; models clifi has no details for are marked unlisted
+in --json.
+
+Search words narrow the list to models whose ID or name contains all of
+them, ignoring case.`,
+	Example: `  clifi models --provider openrouter claude
+  clifi models --tools qwen coder`,
+	RunE: runModels,
+}
+
+func init() {
+	rootCmd.AddCommand(modelsCmd)
+
+	modelsCmd.Flags().String("provider", "", "Only this provider")
+	modelsCmd.Flags().Bool("tools", false, "Only models that support tool calling")
+	modelsCmd.Flags().Bool("json", false, "Print JSON instead of a table")
+}
+
+// providerModel is one row of the models listing.
+type providerModel struct {
+	Provider llm.ProviderID `json:"provider"`
+	llm.Model
+}
+
+func runModels(cmd *cobra.Command, args []string) error {
+	providerFlag, _ := cmd.Flags().GetString("provider")
+	toolsOnly, _ := cmd.Flags().GetBool("tools")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	query := strings.Join(args, " ")
+
+	authManager, err := auth.NewManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to create auth manager: %w", err)
+	}
+
+	providers := authManager.ListConnected()
+	if providerFlag != "" {
+		id := llm.ProviderID(providerFlag)
+		if !slices.Contains(llm.AllProviderIDs(), id) {
+			return fmt.Errorf("unknown provider %q", providerFlag)
+		}
+		providers = []llm.ProviderID{id}
+	}
+	if len(providers) == 0 {
+		return errors.New("no providers connected; run clifi setup or clifi auth connect")
+	}
+	cmd.SilenceUsage = true
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 20*time.Second)
+	defer cancel()
+
+	var rows []providerModel
+	for _, id := range providers {
+		models, err := providerModels(ctx, authManager, id)
+		if err != nil {
+			if providerFlag != "" {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", id, err)
+			continue
+		}
+		for _, m := range llm.SearchModels(models, query) {
+			if toolsOnly && !m.SupportsTools {
+				continue
+			}
+			rows = append(rows, providerModel{Provider: id, Model: m})
+		}
+	}
+
+	if len(rows) == 0 && query != "" {
+		return fmt.Errorf("no models match %q", query)
+	}
+	if asJSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	printModels(cmd.OutOrStdout(), rows)
+	return nil
+}
+
+// providerModels lists a provider's models. OpenRouter's live list is
+// preferred; its static list is the fallback when the API is unreachable.
+func providerModels(ctx context.Context, authManager *auth.Manager, id llm.ProviderID) ([]llm.Model, error) {
+	if id == llm.ProviderOpenRouter {
+		key, _ := authManager.GetAPIKey(id)
+		models, err := llm.FetchOpenRouterModels(ctx, key)
+		if err == nil {
+			return models, nil
+		}
+		fmt.Fprintf(os.Stderr, "Warning: could not fetch OpenRouter models (%v); showing the built-in list\n", err)
+	}
+	provider, err := agent.CreateProvider(authManager, id)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Models(), nil
+}
+
+func printModels(w io.Writer, rows []providerModel) {
+	fmt.Fprintf(w, "%-11s %-45s %8s %-5s %10s %10s\n", "PROVIDER", "MODEL", "CONTEXT", "TOOLS", "$/1M IN", "$/1M OUT")
+	for _, r := range rows {
+		tools := "no"
+		if r.SupportsTools {
+			tools = "yes"
+		}
+		ctxWindow := "-"
+		if r.ContextWindow > 0 {
+			ctxWindow = fmt.Sprintf("%dk", r.ContextWindow/1000)
+		}
+		fmt.Fprintf(w, "%-11s %-45s %8s %-5s %10s %10s\n", r.Provider, r.ID, ctxWindow, tools, formatCost(r.InputCost), formatCost(r.OutputCost))
+	}
+}
+
+// formatCost prints a per-million price, or "-" when unknown or free.
+func formatCost(c float64) string {
+	if c <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", c)
+}
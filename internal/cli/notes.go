@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/notes"
+)
+
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Protocol notes and contract addresses for the agent to search",
+	Long: `Manage a local knowledge base of documents you curate: protocol notes,
+lists of contract addresses. The agent searches them with the search_notes
+tool before stating an address, instead of relying on what the model
+recalls. Documents are copied into the data directory; import again after
+editing the original.
+
+Supported files: ` + strings.Join(notes.Extensions, ", "),
+}
+
+var notesImportCmd = &cobra.Command{
+	Use:   "import <file or directory>...",
+	Short: "Import documents",
+	Example: `  clifi notes import ~/defi/aave.md
+  clifi notes import ~/defi/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNotesImport,
+}
+
+var notesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List imported documents",
+	RunE:  runNotesList,
+}
+
+var notesSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search imported documents as the agent does",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runNotesSearch,
+}
+
+var notesRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove an imported document",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNotesRm,
+}
+
+func init() {
+	rootCmd.AddCommand(notesCmd)
+	notesCmd.AddCommand(notesImportCmd)
+	notesCmd.AddCommand(notesListCmd)
+	notesCmd.AddCommand(notesSearchCmd)
+	notesCmd.AddCommand(notesRmCmd)
+
+	notesSearchCmd.Flags().IntP("limit", "n", 5, "Most passages to show")
+}
+
+func runNotesImport(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	lib := notes.Open(getDataDir())
+	for _, path := range args {
+		names, err := lib.Import(path)
+		for _, name := range names {
+			fmt.Printf("Imported %s\n", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runNotesList(cmd *cobra.Command, args []string) error {
+	docs, err := notes.Open(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		fmt.Println("No notes. Import some with: clifi notes import <file or directory>")
+		return nil
+	}
+	for _, d := range docs {
+		fmt.Printf("%-30s %7.1f KB  %s\n", d.Name, float64(d.Size)/1024, d.ModTime.Local().Format(time.DateOnly))
+	}
+	return nil
+}
+
+func runNotesSearch(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	cmd.SilenceUsage = true
+	results, err := notes.Open(getDataDir()).Search(strings.Join(args, " "), limit)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("No matches.")
+		return nil
+	}
+	for i, r := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		source := r.Doc
+		if r.Section != "" {
+			source += " > " + r.Section
+		}
+		fmt.Printf("%s (score %.2f)\n%s\n", source, r.Score, r.Text)
+	}
+	return nil
+}
+
+func runNotesRm(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	ok, err := notes.Open(getDataDir()).Remove(args[0])
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no document named %s", args[0])
+	}
+	fmt.Printf("Removed %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/audit"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/orders"
+	"github.com/yolodolo42/clifi/internal/quote"
+	"github.com/yolodolo42/clifi/internal/wallet"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+var orderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Off-chain limit orders",
+	Long: `Manage limit orders that 'clifi serve' watches, such as "sell 1 ETH if
+price hits 4200". Prices are polled off-chain; when an order triggers it
+waits for 'clifi order confirm', unless orders.auto_approve is set and
+serve fills it on its own. Either way the swap is quoted fresh and held
+to the policy limits, and the outcome is appended to audit.jsonl.
+
+Orders:
+  sell 1 ETH if price hits 4200          sells for USDC at or above 4200
+  sell 2 ETH if price drops below 3000   stop-loss
+  buy 0.5 ETH with DAI if price hits 3000
+  sell 100 ARB for USDC when ARB > 2`,
+}
+
+var orderAddCmd = &cobra.Command{
+	Use:   "add <order>",
+	Short: "Add a limit order",
+	Example: `  clifi order add "sell 1 ETH if price hits 4200" --chain base
+  clifi order add "buy 0.5 ETH if price drops to 3000" --wallet trading`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runOrderAdd,
+}
+
+var orderListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List limit orders and their status",
+	RunE:  runOrderList,
+}
+
+var orderRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Cancel a limit order",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runOrderRm,
+}
+
+var orderConfirmCmd = &cobra.Command{
+	Use:   "confirm <id>",
+	Short: "Fill a triggered limit order",
+	Long: `Quote a triggered order's swap at the current price, show the preview
+and, once you agree and enter the wallet password, send it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOrderConfirm,
+}
+
+func init() {
+	rootCmd.AddCommand(orderCmd)
+	orderCmd.AddCommand(orderAddCmd)
+	orderCmd.AddCommand(orderListCmd)
+	orderCmd.AddCommand(orderRmCmd)
+	orderCmd.AddCommand(orderConfirmCmd)
+
+	orderAddCmd.Flags().String("chain", "", "Chain to trade and price on (default: the chain setting)")
+	orderAddCmd.Flags().String("wallet", "", "Wallet label or address (default wallet when omitted)")
+	orderAddCmd.Flags().Uint32("slippage-bps", 0, "Slippage tolerance in basis points (default from the policy)")
+}
+
+func runOrderAdd(cmd *cobra.Command, args []string) error {
+	chainName, _ := cmd.Flags().GetString("chain")
+	walletRef, _ := cmd.Flags().GetString("wallet")
+	// Unquoted orders arrive as several args.
+	o, err := orders.ParseOrder(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	if chainName == "" {
+		chainName = viper.GetString("chain")
+	}
+	o.SetChain(chainName)
+	if cmd.Flags().Changed("slippage-bps") {
+		bps, _ := cmd.Flags().GetUint32("slippage-bps")
+		o.SlippageBps = &bps
+	}
+	address, err := resolveAddress(walletRef)
+	if err != nil {
+		return err
+	}
+	o.Wallet = address.Hex()
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+	s, _ := o.Swap(1, 1)
+	if err := tr.CheckSwap(orderSwap(o, s)); err != nil {
+		return err
+	}
+
+	o, err = orders.NewStore(getDataDir()).Add(o)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added order #%d: %s from %s\n", o.ID, o, o.Wallet)
+	fmt.Println("Run 'clifi serve' to watch it.")
+	return nil
+}
+
+func runOrderList(cmd *cobra.Command, args []string) error {
+	list, err := orders.NewStore(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Println("No limit orders. Add one with: clifi order add \"sell 1 ETH if price hits 4200\"")
+		return nil
+	}
+	for _, o := range list {
+		line := fmt.Sprintf("#%-3d %s  from %s  %s", o.ID, o, shortAddress(o.Wallet), o.Status)
+		switch o.Status {
+		case orders.StatusOpen:
+			if o.LastPrice > 0 {
+				line += fmt.Sprintf("  last $%g at %s", o.LastPrice, o.LastChecked.Local().Format(time.DateTime))
+			}
+		case orders.StatusAwaiting:
+			line += fmt.Sprintf(" since %s; run 'clifi order confirm %d'", o.TriggeredAt.Local().Format(time.DateTime), o.ID)
+		case orders.StatusFilled:
+			line += fmt.Sprintf(" %s  %s", o.FilledAt.Local().Format(time.DateTime), o.TxHash)
+		}
+		if o.LastError != "" {
+			line += "  error: " + o.LastError
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runOrderRm(cmd *cobra.Command, args []string) error {
+	id, err := parseOrderID(args[0])
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	ok, err := orders.NewStore(getDataDir()).Remove(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no order #%d", id)
+	}
+	fmt.Printf("Removed order #%d\n", id)
+	return nil
+}
+
+func runOrderConfirm(cmd *cobra.Command, args []string) error {
+	id, err := parseOrderID(args[0])
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	store := orders.NewStore(getDataDir())
+	o, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	if o.Status != orders.StatusAwaiting {
+		return fmt.Errorf("order #%d is %s; only triggered orders can be confirmed", id, o.Status)
+	}
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	prices := watch.ChainSource{Chains: client, Quotes: quote.NewClient()}
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	price, err := prices.Price(ctx, o.Chain, o.Token)
+	var prepared *agent.PreparedSwap
+	if err == nil {
+		var s orders.Swap
+		if s, err = orders.SwapFor(ctx, prices, o, price); err == nil {
+			prepared, err = tr.PrepareSwap(ctx, orderSwap(o, s))
+		}
+	}
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Order #%d: %s\n%s is $%g now", o.ID, o, o.Token, price)
+	if !o.Trigger.Holds(price) {
+		fmt.Fprint(out, "; the condition no longer holds")
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, prepared.Preview)
+	prompt, accept := fmt.Sprintf("Swap %s %s for %s on %s? [y/N] ", prepared.Amount, prepared.FromSymbol, prepared.ToSymbol, o.Chain), "y"
+	if len(prepared.Warnings) > 0 {
+		prompt, accept = "There are warnings (see above). Type yes to swap anyway: ", "yes"
+	}
+	fmt.Fprint(out, prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != accept && a != "yes" {
+		return fmt.Errorf("cancelled; order #%d still awaits confirmation", id)
+	}
+
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to load wallets: %w", err)
+	}
+	var password string
+	if km.NeedsPassword(common.HexToAddress(o.Wallet)) {
+		if password, err = readPassword("Wallet password: "); err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		// A wrong password should leave the order to retry, not fail it.
+		if err := unlockWallets([]string{o.Wallet}, password); err != nil {
+			return err
+		}
+	}
+
+	sendCtx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
+	defer cancel()
+	hash, summary, swapErr := sendSwap(sendCtx, tr, password, prepared)
+	entry := o.Settle(time.Now().UTC(), orders.Execution{TxHash: hash, Summary: summary}, swapErr)
+	if err := audit.New(getDataDir()).Record(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: audit record failed: %v\n", err)
+	}
+	if err := store.Update(o); err != nil {
+		return err
+	}
+	if swapErr != nil {
+		return swapErr
+	}
+	fmt.Fprintf(out, "%s\nTx: %s\n", summary, hash)
+	return nil
+}
+
+func parseOrderID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid order ID: %s", s)
+	}
+	return id, nil
+}
+
+func orderSwap(o orders.Order, s orders.Swap) agent.SwapRequest {
+	return agent.SwapRequest{
+		Chain:       o.Chain,
+		From:        o.Wallet,
+		FromToken:   s.FromToken,
+		ToToken:     s.ToToken,
+		Amount:      s.Amount,
+		SlippageBps: o.SlippageBps,
+	}
+}
+
+// orderExecutor fills triggered orders through the agent's swap path, for
+// orders.auto_approve.
+type orderExecutor struct {
+	tr       *agent.ToolRegistry
+	password string
+}
+
+func (e orderExecutor) Execute(ctx context.Context, o orders.Order, s orders.Swap) (orders.Execution, error) {
+	hash, summary, err := executeSwap(ctx, e.tr, e.password, orderSwap(o, s))
+	return orders.Execution{TxHash: hash, Summary: summary}, err
+}
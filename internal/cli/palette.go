@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// maxPaletteRows is how many commands the palette shows at once.
+const maxPaletteRows = 6
+
+// commandAliases are accepted by handleCommand but not listed.
+var commandAliases = map[string]bool{"/exit": true, "/q": true, "/?": true}
+
+// matchCommands ranks commands for the palette: names starting with the
+// query first, then names containing it, then descriptions containing it.
+// Single letters only match name prefixes.
+func matchCommands(query string) []command {
+	query = strings.ToLower(query)
+	word := strings.TrimPrefix(query, "/")
+	loose := len(word) >= 2
+	var prefix, contains, described []command
+	for _, cmd := range commands {
+		switch {
+		case strings.HasPrefix(cmd.name, query):
+			prefix = append(prefix, cmd)
+		case loose && strings.Contains(cmd.name, word):
+			contains = append(contains, cmd)
+		case loose && strings.Contains(strings.ToLower(cmd.description), word):
+			described = append(described, cmd)
+		}
+	}
+	return append(append(prefix, contains...), described...)
+}
+
+// updateSuggestions filters the palette for the current input. It closes
+// once arguments are being typed.
+func (m *model) updateSuggestions() {
+	input := m.prompt.Value()
+	if !strings.HasPrefix(input, "/") || strings.ContainsAny(input, " \n") {
+		m.suggestions = nil
+		m.suggestionIdx = 0
+		return
+	}
+
+	filtered := matchCommands(input)
+
+	// Reset index if suggestions changed
+	if len(filtered) != len(m.suggestions) || (len(filtered) > 0 && filtered[0] != m.suggestions[0]) {
+		m.suggestionIdx = 0
+	}
+	m.suggestions = filtered
+}
+
+// paletteChoice returns the highlighted command when Enter should run it
+// instead of the typed text, which is the case unless the text is already
+// a command.
+func (m *model) paletteChoice(input string) (command, bool) {
+	if len(m.suggestions) == 0 || m.suggestionIdx >= len(m.suggestions) || commandAliases[input] {
+		return command{}, false
+	}
+	for _, cmd := range commands {
+		if cmd.name == input {
+			return command{}, false
+		}
+	}
+	return m.suggestions[m.suggestionIdx], true
+}
+
+// completeCommand puts cmd in the prompt, ready for its arguments.
+func (m *model) completeCommand(cmd command) {
+	value := cmd.name
+	if cmd.args != "" {
+		value += " "
+	}
+	m.prompt.SetValue(value)
+	m.suggestions = nil
+	m.suggestionIdx = 0
+}
+
+// argHint returns the usage line for a command whose arguments are being
+// typed.
+func (m *model) argHint() string {
+	input := m.prompt.Value()
+	name, _, ok := strings.Cut(input, " ")
+	if !ok || !strings.HasPrefix(name, "/") || strings.Contains(input, "\n") {
+		return ""
+	}
+	for _, cmd := range commands {
+		if cmd.name == strings.ToLower(name) && cmd.args != "" {
+			return "usage: " + cmd.name + " " + cmd.args
+		}
+	}
+	return ""
+}
+
+// paletteHeight is the number of rows paletteView renders.
+func (m *model) paletteHeight() int {
+	if n := len(m.suggestions); n > 0 {
+		if n > maxPaletteRows {
+			return maxPaletteRows + 1
+		}
+		return n
+	}
+	if m.argHint() != "" || m.editing {
+		return 1
+	}
+	return 0
+}
+
+// paletteView renders the matching commands around the highlighted one,
+// or the argument hint once a command is chosen.
+func (m *model) paletteView() string {
+	if len(m.suggestions) == 0 {
+		if hint := m.argHint(); hint != "" {
+			return "  " + ui.SelectorDim.Render(hint) + "\n"
+		}
+		if m.editing {
+			return "  " + ui.SelectorDim.Render("editing your last message · enter resends it, esc cancels") + "\n"
+		}
+		return ""
+	}
+
+	start := 0
+	if m.suggestionIdx >= maxPaletteRows {
+		start = m.suggestionIdx - maxPaletteRows + 1
+	}
+	end := min(start+maxPaletteRows, len(m.suggestions))
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		cmd := m.suggestions[i]
+		prefix := "  "
+		nameStyle := ui.PromptStyle
+		if i == m.suggestionIdx {
+			prefix = ui.SelectorCursor.Render(ui.SymbolArrow) + " "
+			nameStyle = ui.SelectorActive
+		}
+		name := nameStyle.Render(fmt.Sprintf("%-10s", cmd.name))
+		args := ui.SelectorDim.Render(fmt.Sprintf(" %-28s", cmd.args))
+		desc := ui.SelectorItemStyle.Render(cmd.description)
+		b.WriteString(prefix + name + args + desc + "\n")
+	}
+	if len(m.suggestions) > maxPaletteRows {
+		b.WriteString("  " + ui.SelectorDim.Render(fmt.Sprintf("%d/%d  ↑/↓ move · tab complete · enter run", m.suggestionIdx+1, len(m.suggestions))) + "\n")
+	}
+	return b.String()
+}
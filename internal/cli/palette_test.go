@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+func commandNames(cmds []command) []string {
+	names := make([]string, len(cmds))
+	for i, c := range cmds {
+		names[i] = c.name
+	}
+	return names
+}
+
+func TestMatchCommands(t *testing.T) {
+	assert.Len(t, matchCommands("/"), len(commands))
+	assert.Equal(t, []string{"/compact", "/copy", "/context", "/clear"}, commandNames(matchCommands("/c")))
+
+	// Name prefix, then name substring, then description.
+	got := commandNames(matchCommands("/wal"))
+	require.NotEmpty(t, got)
+	assert.Equal(t, "/wallet", got[0])
+	assert.Contains(t, got, "/status") // "...model/wallet info"
+
+	assert.Contains(t, commandNames(matchCommands("/exit")), "/quit")
+	assert.Empty(t, matchCommands("/zzz"))
+}
+
+func TestPaletteChoice(t *testing.T) {
+	m := model{prompt: ui.NewPrompt()}
+
+	m.prompt.SetValue("/bal")
+	m.updateSuggestions()
+	cmd, ok := m.paletteChoice("/bal")
+	require.True(t, ok)
+	assert.Equal(t, "/balance", cmd.name)
+
+	// Exact commands and aliases run as typed.
+	m.prompt.SetValue("/copy")
+	m.updateSuggestions()
+	_, ok = m.paletteChoice("/copy")
+	assert.False(t, ok)
+	m.prompt.SetValue("/q")
+	m.updateSuggestions()
+	_, ok = m.paletteChoice("/q")
+	assert.False(t, ok)
+
+	m.completeCommand(command{name: "/auth", args: "<provider> <api_key>"})
+	assert.Equal(t, "/auth ", m.prompt.Value())
+	assert.Empty(t, m.suggestions)
+	assert.Equal(t, "usage: /auth <provider> <api_key>", m.argHint())
+	assert.Equal(t, 1, m.paletteHeight())
+
+	m.prompt.SetValue("/")
+	m.updateSuggestions()
+	assert.Equal(t, maxPaletteRows+1, m.paletteHeight())
+}
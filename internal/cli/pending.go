@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+// pendingCheckTimeout bounds one pass over the pending transactions.
+const pendingCheckTimeout = time.Minute
+
+// pendingSettledMsg carries the sent transactions found settled at
+// startup.
+type pendingSettledMsg struct {
+	settled []agent.SettledTx
+}
+
+// reconcilePending checks, in the background, the transactions a previous
+// run sent but never saw mined.
+func (m model) reconcilePending() tea.Cmd {
+	if m.agent == nil || m.agent.Tools() == nil {
+		return nil
+	}
+	tools := m.agent.Tools()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), pendingCheckTimeout)
+		defer cancel()
+		settled, err := tools.ReconcilePending(ctx)
+		if err != nil {
+			slog.Warn("pending transactions not checked", "err", err)
+		}
+		return pendingSettledMsg{settled: settled}
+	}
+}
+
+func (m *model) handlePendingSettled(msg pendingSettledMsg) {
+	for _, t := range msg.settled {
+		m.addMessage(chatMessage{kind: "system", content: t.Message(), time: time.Now()})
+	}
+}
+
+// runPendingTxs reconciles pending transactions now and every interval
+// until ctx is done, notifying of each one settled.
+func runPendingTxs(ctx context.Context, tr *agent.ToolRegistry, notifiers []watch.Notifier, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, pendingCheckTimeout)
+		settled, err := tr.ReconcilePending(checkCtx)
+		cancel()
+		if err != nil {
+			slog.Warn("pending transactions not checked", "err", err)
+		}
+		for _, t := range settled {
+			a := watch.Alert{At: time.Now().UTC(), Text: t.Message()}
+			for _, n := range notifiers {
+				if err := n.Notify(ctx, a); err != nil {
+					slog.Warn("pending tx notification failed", "tx", t.Hash, "notifier", fmt.Sprintf("%T", n), "err", err)
+				}
+			}
+		}
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+// planTimeout covers a whole run: every step may wait for a receipt.
+const planTimeout = 10 * time.Minute
+
+// handlePlanCommand plans a multi-step operation and runs it once
+// approved.
+//
+//	/plan <goal>     plan the steps, previewing each one that signs
+//	/plan            show the plan waiting for approval
+//	/plan run        run it (asks for the wallet password if a step signs)
+//	/plan cancel     drop it
+func (m model) handlePlanCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+
+	switch strings.ToLower(arg) {
+	case "":
+		if p := m.agent.PendingPlan(); p != nil {
+			m.addSystem(p.Text() + "\n/plan run to run it, /plan cancel to drop it.")
+		} else {
+			m.addSystem("No plan waiting. Usage: /plan <goal>, e.g. /plan consolidate my dust into ETH on base")
+		}
+		m.updateViewport()
+		return m, nil
+
+	case "cancel", "drop":
+		m.agent.DiscardPlan()
+		m.addSystem("Plan dropped.")
+		m.updateViewport()
+		return m, nil
+
+	case "run", "approve":
+		p := m.agent.PendingPlan()
+		if p == nil {
+			m.addError(agent.ErrNoPlan.Error())
+			m.updateViewport()
+			return m, nil
+		}
+		if p.Signing() {
+			m.password = newPasswordPrompt("", m.width)
+			m.password.forPlan = true
+			m.password.input.Prompt = "Wallet password for the plan: "
+			m.mode = modePassword
+			m.prompt.Blur()
+			return m, nil
+		}
+		return m.runPlan("")
+	}
+
+	m.addUser("/plan " + arg)
+	m.loading = true
+	m.cancelled = false
+	m.updateViewport()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	m.cancelRequest = cancel
+	return m, streamAgent(ctx, cancel, func(ctx context.Context, onEvent func(agent.ChatEvent)) error {
+		p, err := m.agent.MakePlan(ctx, arg, onEvent)
+		if err == nil && p != nil {
+			onEvent(agent.ChatEvent{Type: "plan_step", Content: "/plan run to run these steps, /plan cancel to drop them."})
+		}
+		return err
+	})
+}
+
+// runPlan runs the approved plan in the background, streaming each step.
+func (m model) runPlan(password string) (tea.Model, tea.Cmd) {
+	m.loading = true
+	m.cancelled = false
+	m.updateViewport()
+	ctx, cancel := context.WithTimeout(context.Background(), planTimeout)
+	m.cancelRequest = cancel
+	ag := m.agent
+	return m, tea.Batch(m.prompt.Focus(), streamAgent(ctx, cancel, func(ctx context.Context, onEvent func(agent.ChatEvent)) error {
+		return ag.RunPlan(ctx, password, onEvent)
+	}))
+}
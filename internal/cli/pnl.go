@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+var pnlCmd = &cobra.Command{
+	Use:   "pnl [address|wallet]",
+	Short: "Show cost basis and profit and loss",
+	Long: `Show per-asset cost basis and realized and unrealized P&L for a wallet
+(the default one when omitted), matching disposals to acquisitions first-in
+first-out.
+
+The numbers come from the flows clifi recorded when it sent a transaction
+or fetched its receipt, priced in USD at that time: native value, gas fees
+and ERC-20 transfers to or from your wallets. Funds the wallet held before
+that have no recorded cost basis and count as $0.`,
+	Example: `  clifi pnl
+  clifi pnl trading --csv pnl-2026.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPnL,
+}
+
+func init() {
+	rootCmd.AddCommand(pnlCmd)
+
+	pnlCmd.Flags().String("csv", "", "Write disposals (one row per lot) as CSV to this file, or - for stdout")
+}
+
+func runPnL(cmd *cobra.Command, args []string) error {
+	csvPath, _ := cmd.Flags().GetString("csv")
+	address, err := resolveAddress(argOrEmpty(args))
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	report, err := tr.PnL(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if csvPath == "-" {
+		return report.WriteCSV(cmd.OutOrStdout())
+	}
+	fmt.Fprint(cmd.OutOrStdout(), report.Text())
+	if csvPath == "" {
+		return nil
+	}
+	f, err := os.OpenFile(csvPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\nWrote %d disposals to %s\n", len(report.Disposals), csvPath)
+	return nil
+}
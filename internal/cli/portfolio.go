@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -25,12 +26,14 @@ func init() {
 	portfolioCmd.Flags().String("address", "", "Address to check (uses first wallet if not specified)")
 	portfolioCmd.Flags().StringSlice("chains", []string{"ethereum", "base", "arbitrum", "optimism", "polygon"}, "Chains to query")
 	portfolioCmd.Flags().Bool("testnet", false, "Include testnet chains")
+	portfolioCmd.Flags().StringSlice("token", nil, "ERC20 token to include, as chain:address (repeatable)")
 }
 
 func runPortfolio(cmd *cobra.Command, args []string) error {
 	addressFlag, _ := cmd.Flags().GetString("address")
 	chains, _ := cmd.Flags().GetStringSlice("chains")
 	includeTestnet, _ := cmd.Flags().GetBool("testnet")
+	tokenFlags, _ := cmd.Flags().GetStringSlice("token")
 
 	var address common.Address
 
@@ -40,19 +43,19 @@ func runPortfolio(cmd *cobra.Command, args []string) error {
 		}
 		address = common.HexToAddress(addressFlag)
 	} else {
-		// Try to use first wallet
+		// Fall back to the default wallet
 		dataDir := getDataDir()
 		km, err := wallet.NewKeystoreManager(dataDir)
 		if err != nil {
 			return fmt.Errorf("no address specified and failed to load wallets: %w", err)
 		}
 
-		accounts := km.ListAccounts()
-		if len(accounts) == 0 {
+		def, err := km.DefaultAccount()
+		if err != nil {
 			return fmt.Errorf("no address specified and no wallets found. Use --address or create a wallet first")
 		}
 
-		address = accounts[0].Address
+		address = def.Address
 		fmt.Printf("Using wallet: %s\n\n", address.Hex())
 	}
 
@@ -60,7 +63,15 @@ func runPortfolio(cmd *cobra.Command, args []string) error {
 		chains = append(chains, "sepolia", "base-sepolia")
 	}
 
-	client := chain.NewClient()
+	tokens, err := parseTokenFlags(tokenFlags)
+	if err != nil {
+		return err
+	}
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
 	defer client.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -71,10 +82,15 @@ func runPortfolio(cmd *cobra.Command, args []string) error {
 
 	totalUSD := big.NewFloat(0) // For future USD value tracking
 
+	portfolio, err := client.GetPortfolio(ctx, address, chains, tokens)
+	if err != nil {
+		return err
+	}
+
 	for _, chainName := range chains {
-		balance, err := client.GetNativeBalance(ctx, chainName, address)
-		if err != nil {
-			fmt.Printf("%-12s  ⚠ Error: %v\n", chainName, err)
+		balance, ok := portfolio.NativeBalances[chainName]
+		if !ok {
+			fmt.Printf("%-12s  ⚠ Error: %s\n", chainName, portfolio.Errors[chainName])
 			continue
 		}
 
@@ -87,6 +103,10 @@ func runPortfolio(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Printf("%s %-12s  %s %s\n", indicator, chainName, formattedBalance, balance.Symbol)
+
+		for _, tb := range portfolio.TokenBalances[chainName] {
+			fmt.Printf("  %-12s  %s %s\n", "", chain.FormatBalance(tb.Balance, tb.Decimals), tb.Symbol)
+		}
 	}
 
 	fmt.Println("─────────────────────────────────────────────────────────")
@@ -94,3 +114,16 @@ func runPortfolio(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parseTokenFlags turns "chain:address" values into per-chain token lists.
+func parseTokenFlags(values []string) (map[string][]common.Address, error) {
+	tokens := make(map[string][]common.Address)
+	for _, v := range values {
+		chainName, addr, ok := strings.Cut(v, ":")
+		if !ok || !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid --token %q: expected chain:0x<address>", v)
+		}
+		tokens[chainName] = append(tokens[chainName], common.HexToAddress(addr))
+	}
+	return tokens, nil
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/portfolio"
+	"github.com/yolodolo42/clifi/internal/quote"
+)
+
+var portfolioSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [address|wallet]",
+	Short: "Record the current balances and values",
+	Long: `Record the native and known-token balances of an address (the default
+wallet when omitted), with USD prices, in the local DB. 'clifi serve' takes
+snapshots every portfolio.snapshot_interval.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPortfolioSnapshot,
+}
+
+var portfolioDiffCmd = &cobra.Command{
+	Use:   "diff [address|wallet]",
+	Short: "Show balance and value changes since a snapshot",
+	Long: `Compare the current balances with the snapshot taken --since ago (or
+the oldest one, if none is that old). The current balances are recorded as
+a new snapshot.`,
+	Example: `  clifi portfolio diff --since 7d
+  clifi portfolio diff trading --since 24h`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPortfolioDiff,
+}
+
+func init() {
+	portfolioCmd.AddCommand(portfolioSnapshotCmd)
+	portfolioCmd.AddCommand(portfolioDiffCmd)
+
+	portfolioSnapshotCmd.Flags().StringSlice("chains", nil, "Chains to record (default: "+fmt.Sprint(portfolio.DefaultChains)+")")
+	portfolioDiffCmd.Flags().String("since", "7d", "Look-back period, e.g. 24h, 7d or 2w")
+	portfolioDiffCmd.Flags().StringSlice("chains", nil, "Chains to compare (default: those in the earlier snapshot)")
+}
+
+func runPortfolioSnapshot(cmd *cobra.Command, args []string) error {
+	chains, _ := cmd.Flags().GetStringSlice("chains")
+	address, err := resolveAddress(argOrEmpty(args))
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	snap, err := takeSnapshot(cmd.Context(), address, chains)
+	if err != nil {
+		return err
+	}
+	var total float64
+	for _, a := range snap.Assets {
+		total += a.ValueUSD()
+	}
+	fmt.Printf("Recorded snapshot of %s: %d assets on %d chains, $%.2f\n", snap.Address, len(snap.Assets), len(snap.Chains), total)
+	return nil
+}
+
+func runPortfolioDiff(cmd *cobra.Command, args []string) error {
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	chains, _ := cmd.Flags().GetStringSlice("chains")
+	since, err := portfolio.ParseSince(sinceFlag)
+	if err != nil {
+		return err
+	}
+	address, err := resolveAddress(argOrEmpty(args))
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	store, err := portfolio.OpenStore(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	base, err := store.Baseline(address.Hex(), time.Now().Add(-since))
+	if errors.Is(err, portfolio.ErrNoSnapshot) {
+		return fmt.Errorf("no snapshot of %s yet; take one with 'clifi portfolio snapshot' or run 'clifi serve'", address.Hex())
+	}
+	if err != nil {
+		return err
+	}
+	if len(chains) == 0 {
+		chains = base.Chains
+	}
+
+	current, err := takeSnapshot(cmd.Context(), address, chains)
+	if err != nil {
+		return err
+	}
+	fmt.Print(portfolio.Diff(base, current).Text())
+	return nil
+}
+
+// takeSnapshot reads, prices and stores a snapshot.
+func takeSnapshot(ctx context.Context, address common.Address, chains []string) (portfolio.Snapshot, error) {
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return portfolio.Snapshot{}, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	snap, err := portfolio.Take(ctx, client, quote.NewClient(), address, chains)
+	if err != nil {
+		return portfolio.Snapshot{}, err
+	}
+
+	store, err := portfolio.OpenStore(getDataDir())
+	if err != nil {
+		return portfolio.Snapshot{}, err
+	}
+	defer store.Close()
+	if snap.ID, err = store.Save(snap); err != nil {
+		return portfolio.Snapshot{}, err
+	}
+	return snap, nil
+}
+
+func argOrEmpty(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
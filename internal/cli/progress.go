@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// runningTool is a tool call that has started but not yet returned.
+type runningTool struct {
+	name    string
+	args    string
+	started time.Time
+}
+
+// agentEventMsg carries one event from an in-flight chat. The next event
+// is read from ch once this one is handled, which keeps them in order.
+type agentEventMsg struct {
+	event agent.ChatEvent
+	ch    <-chan tea.Msg
+}
+
+// waitForAgent reads the next message of an in-flight chat.
+func waitForAgent(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// streamChat runs a chat in the background, passing its events to the UI
+// as they happen and finishing with a responseMsg.
+func streamChat(ctx context.Context, cancel context.CancelFunc, ag *agent.Agent, input string) tea.Cmd {
+	return streamAgent(ctx, cancel, func(ctx context.Context, onEvent func(agent.ChatEvent)) error {
+		_, err := ag.ChatStream(ctx, input, onEvent)
+		return err
+	})
+}
+
+// streamAgent runs an agent call that reports ChatEvents, such as a chat
+// or a plan, the same way.
+func streamAgent(ctx context.Context, cancel context.CancelFunc, run func(context.Context, func(agent.ChatEvent)) error) tea.Cmd {
+	ch := make(chan tea.Msg)
+	go func() {
+		defer cancel()
+		err := run(ctx, func(e agent.ChatEvent) {
+			// Stop delivering once the request is cancelled; the UI may
+			// have quit and stopped reading.
+			select {
+			case ch <- agentEventMsg{event: e, ch: ch}:
+			case <-ctx.Done():
+			}
+		})
+		ch <- responseMsg{err: err}
+	}()
+	return waitForAgent(ch)
+}
+
+// handleAgentEvent shows a streamed event. A tool call becomes a spinner
+// line until its result arrives, then settles into the transcript.
+func (m *model) handleAgentEvent(e agent.ChatEvent) {
+	switch e.Type {
+	case "tool_call":
+		m.running = append(m.running, runningTool{name: e.Tool, args: e.Args, started: time.Now()})
+	case "tool_result":
+		call := m.finishTool(e.Tool)
+		m.addToolCall(call.name, call.args, e.Elapsed)
+		m.addToolResult(e.Tool, e.Content, e.Blocks)
+	case "content":
+		m.addAssistant(e.Content)
+	case "plan_step", "notice":
+		m.addSystem(e.Content)
+	}
+}
+
+// finishTool removes the oldest running call of the named tool and
+// returns it.
+func (m *model) finishTool(name string) runningTool {
+	for i, t := range m.running {
+		if t.name == name {
+			m.running = append(m.running[:i], m.running[i+1:]...)
+			return t
+		}
+	}
+	return runningTool{name: name}
+}
+
+// progressView renders the loading line, or one spinner line per running
+// tool.
+func (m model) progressView() string {
+	hint := ""
+	if k := m.keys.Cancel.Keys(); len(k) > 0 {
+		hint = ui.HelpStyle.Render(fmt.Sprintf(" (%s to cancel)", k[0]))
+	}
+	if len(m.running) == 0 {
+		return fmt.Sprintf("  %s Thinking...%s\n", m.spinner.View(), hint)
+	}
+
+	var b strings.Builder
+	for i, t := range m.running {
+		label := ui.ToolCallStyle.Render(t.name)
+		if args := toolArgsHint(t.args, m.width-len(t.name)-20); args != "" {
+			label += ui.SelectorDim.Render(" (" + args + ")")
+		}
+		line := fmt.Sprintf("  %s %s... %s", m.spinner.View(), label, ui.SelectorDim.Render(formatElapsed(time.Since(t.started))))
+		if i == len(m.running)-1 {
+			line += hint
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// progressHeight is the number of rows progressView renders beyond the
+// single loading line the layout always leaves room for.
+func (m *model) progressHeight() int {
+	return max(len(m.running)-1, 0)
+}
+
+// toolArgsHint condenses tool arguments to their values, e.g.
+// `{"chains":["base","arbitrum"]}` becomes "base, arbitrum". Keys are
+// visited in order so the hint is stable.
+func toolArgsHint(args string, maxLen int) string {
+	var input map[string]any
+	if err := json.Unmarshal([]byte(args), &input); err != nil {
+		return summarizeArgs(args, maxLen)
+	}
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	var add func(v any)
+	add = func(v any) {
+		switch v := v.(type) {
+		case string:
+			if v == "***REDACTED***" {
+				return
+			}
+			if len(v) == 42 && strings.HasPrefix(v, "0x") {
+				v = v[:6] + "…" + v[38:]
+			}
+			parts = append(parts, v)
+		case float64, bool:
+			parts = append(parts, fmt.Sprint(v))
+		case []any:
+			for _, item := range v {
+				add(item)
+			}
+		}
+	}
+	for _, k := range keys {
+		add(input[k])
+	}
+	return summarizeArgs(strings.Join(parts, ", "), maxLen)
+}
+
+// formatElapsed shows a duration with one decimal, e.g. "3.2s".
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Truncate(time.Second).String()
+}
+
+// notifyCmd raises a desktop notification when e confirms a transaction
+// while the terminal is unfocused, so a long wait isn't missed.
+func (m model) notifyCmd(e agent.ChatEvent) tea.Cmd {
+	c := e.Confirmed
+	if c == nil || !m.blurred {
+		return nil
+	}
+	title := "clifi: transaction confirmed"
+	if !c.Success {
+		title = "clifi: transaction failed"
+	}
+	body := fmt.Sprintf("%s on %s", c.TxHash, c.Chain)
+	return func() tea.Msg {
+		_ = ui.Notify(title, body)
+		return nil
+	}
+}
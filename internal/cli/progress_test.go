@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+func TestToolArgsHint(t *testing.T) {
+	assert.Equal(t, "base, arbitrum", toolArgsHint(`{"chains":["base","arbitrum"]}`, 80))
+	assert.Equal(t, "0xd8dA…6045, base", toolArgsHint(`{"chain":"base","address":"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"}`, 80))
+	assert.Equal(t, "0.5, true", toolArgsHint(`{"amount":0.5,"confirm":true,"password":"***REDACTED***"}`, 80))
+	assert.Equal(t, "", toolArgsHint(`{}`, 80))
+	assert.Equal(t, "not json", toolArgsHint("not json", 80))
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "3.2s", formatElapsed(3240*time.Millisecond))
+	assert.Equal(t, "0.0s", formatElapsed(10*time.Millisecond))
+	assert.Equal(t, "1m5s", formatElapsed(65*time.Second+300*time.Millisecond))
+}
+
+func TestHandleAgentEvent(t *testing.T) {
+	var m model
+	m.handleAgentEvent(agent.ChatEvent{Type: "tool_call", Tool: "get_balances", Args: `{"chains":["base"]}`})
+	m.handleAgentEvent(agent.ChatEvent{Type: "tool_call", Tool: "list_chains", Args: `{}`})
+	require.Len(t, m.running, 2)
+	assert.Equal(t, 1, m.progressHeight())
+	assert.Empty(t, m.messages, "running tools stay out of the transcript")
+
+	m.handleAgentEvent(agent.ChatEvent{Type: "tool_result", Tool: "get_balances", Content: "ok", Elapsed: 2 * time.Second})
+	require.Len(t, m.running, 1)
+	assert.Equal(t, "list_chains", m.running[0].name)
+
+	require.Len(t, m.messages, 2)
+	assert.Equal(t, "tool_call", m.messages[0].kind)
+	assert.Equal(t, `{"chains":["base"]}`, m.messages[0].toolArgs)
+	assert.Equal(t, 2*time.Second, m.messages[0].elapsed)
+	assert.Equal(t, "tool_result", m.messages[1].kind)
+
+	m.handleAgentEvent(agent.ChatEvent{Type: "content", Content: "done"})
+	assert.Equal(t, "assistant", m.messages[len(m.messages)-1].kind)
+}
+
+func TestNotifyCmdOnlyWhenBlurred(t *testing.T) {
+	confirmed := agent.ChatEvent{Type: "tool_result", Tool: "wait_receipt", Content: "ok",
+		Confirmed: &agent.TxConfirmation{Chain: "base", TxHash: "0xabc", Success: true}}
+
+	m := initialModel(nil)
+	assert.Nil(t, m.notifyCmd(confirmed), "focused terminal")
+
+	updated, _ := m.Update(tea.BlurMsg{})
+	m = updated.(model)
+	assert.NotNil(t, m.notifyCmd(confirmed))
+	assert.Nil(t, m.notifyCmd(agent.ChatEvent{Type: "tool_result", Tool: "get_balances"}))
+
+	updated, _ = m.Update(tea.FocusMsg{})
+	assert.Nil(t, updated.(model).notifyCmd(confirmed))
+}
@@ -5,45 +5,73 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
 	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/chain"
 	"github.com/yolodolo42/clifi/internal/llm"
 	"github.com/yolodolo42/clifi/internal/ui"
 	"github.com/yolodolo42/clifi/internal/wallet"
 )
 
-var mdRenderer *glamour.TermRenderer
-
-func init() {
-	mdRenderer, _ = glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(80),
-	)
+var mdRenderer = newMarkdownRenderer()
+
+// newMarkdownRenderer builds a glamour renderer for the active theme's
+// background.
+func newMarkdownRenderer() *glamour.TermRenderer {
+	style := glamour.WithAutoStyle()
+	switch ui.CurrentTheme().Background {
+	case ui.BackgroundDark:
+		style = glamour.WithStandardStyle("dark")
+	case ui.BackgroundLight:
+		style = glamour.WithStandardStyle("light")
+	}
+	r, _ := glamour.NewTermRenderer(style, glamour.WithWordWrap(80))
+	return r
 }
 
 // command defines a slash command with its description
 type command struct {
 	name        string
+	args        string // argument hint, e.g. "[hash|address]"
 	description string
 }
 
 // commands is the registry of available commands
 var commands = []command{
-	{"/help", "Show available commands"},
-	{"/model", "Select AI model interactively"},
-	{"/provider", "Switch AI provider"},
-	{"/auth", "Connect a provider with API key"},
-	{"/status", "Show current provider/model/wallet info"},
-	{"/clear", "Clear chat history"},
-	{"/logout", "Clear credentials and exit"},
-	{"/quit", "Exit clifi"},
+	{"/help", "", "Show available commands"},
+	{"/model", "[model-id|search]", "Select AI model interactively"},
+	{"/provider", "[provider]", "Switch AI provider"},
+	{"/auth", "<provider> <api_key>", "Connect a provider with API key"},
+	{"/status", "", "Show current provider/model/wallet info"},
+	{"/tokens", "", "Show context window usage"},
+	{"/usage", "", "Show this month's LLM usage and spend"},
+	{"/compact", "", "Summarize the conversation to free context"},
+	{"/set", "[temperature|top_p|max_tokens] [value]", "Show or set sampling parameters"},
+	{"/copy", "[hash|address|list|n]", "Copy last response, hash or address"},
+	{"/export", "[md|json|path]", "Export conversation to Markdown or JSON"},
+	{"/search", "<words>", "Search earlier conversations"},
+	{"/resume", "<#>", "Continue a conversation from a /search match"},
+	{"/theme", "[name]", "Switch color theme"},
+	{"/keys", "", "Show key bindings"},
+	{"/wallet", "[list|create|use|label]", "List, create or switch wallets"},
+	{"/context", "[chain|wallet|clear]", "Session default chain and wallet"},
+	{"/balance", "[address|wallet] [chain...]", "Show balances directly (no model call)"},
+	{"/plan", "[goal|run|cancel]", "Plan a multi-step operation, then run it"},
+	{"/retry", "", "Regenerate the last response"},
+	{"/edit", "[message]", "Edit and resend your last message"},
+	{"/clear", "", "Clear chat history"},
+	{"/logout", "", "Clear credentials and exit"},
+	{"/quit", "", "Exit clifi"},
 }
 
 // replMode represents the current interaction mode
@@ -52,34 +80,52 @@ type replMode int
 const (
 	modeChat replMode = iota
 	modeModelSelector
+	modeThemeSelector
+	modeKeysHelp
+	modePassword
+	modeProviderSelector
 )
 
 // chatMessage represents a message in the chat history
 type chatMessage struct {
-	kind     string // "user", "tool_call", "tool_result", "assistant", "error", "system"
+	kind     string // "user", "tool_call", "tool_result", "assistant", "error", "warning", "system"
 	content  string
 	toolName string
 	toolArgs string
 	blocks   []agent.UIBlock
+	elapsed  time.Duration // tool run time, for tool_call
 	time     time.Time
 }
 
 // model represents the REPL state
 type model struct {
-	agent         *agent.Agent
-	prompt        ui.Prompt
-	viewport      viewport.Model
-	messages      []chatMessage
-	spinner       spinner.Model
-	loading       bool
-	width         int
-	height        int
-	ready         bool
-	quitting      bool
-	mode          replMode
-	modelSelector ui.Selector
-	suggestions   []command
-	suggestionIdx int
+	agent            *agent.Agent
+	prompt           ui.Prompt
+	viewport         viewport.Model
+	messages         []chatMessage
+	spinner          spinner.Model
+	loading          bool
+	width            int
+	height           int
+	ready            bool
+	quitting         bool
+	mode             replMode
+	modelSelector    ui.Selector
+	themeSelector    ui.Selector
+	providerSelector ui.Selector
+	suggestions      []command
+	suggestionIdx    int
+	keys             ui.KeyMap
+	history          []string
+	historyIdx       int
+	historyDraft     string
+	cancelRequest    context.CancelFunc
+	cancelled        bool
+	password         passwordPrompt
+	running          []runningTool
+	contextWarned    float64 // context fill level last warned about
+	editing          bool    // the prompt holds the last message, from /edit
+	blurred          bool    // the terminal reported losing focus
 }
 
 func (m *model) addMessage(msg chatMessage) {
@@ -98,23 +144,45 @@ func (m *model) addError(content string) { m.addMessage(chatMessage{kind: "error
 
 func (m *model) addErrorf(format string, args ...any) { m.addError(fmt.Sprintf(format, args...)) }
 
-func (m *model) addToolCall(name, args string) {
-	m.addMessage(chatMessage{kind: "tool_call", toolName: name, toolArgs: args})
+func (m *model) addWarning(content string) {
+	m.addMessage(chatMessage{kind: "warning", content: content})
+}
+
+func (m *model) addToolCall(name, args string, elapsed time.Duration) {
+	m.addMessage(chatMessage{kind: "tool_call", toolName: name, toolArgs: args, elapsed: elapsed})
 }
 
 func (m *model) addToolResult(name, content string, blocks []agent.UIBlock) {
 	m.addMessage(chatMessage{kind: "tool_result", toolName: name, content: content, blocks: blocks})
 }
 
-// responseMsg is sent when the agent responds
+// responseMsg is sent when the agent has finished responding. Its events
+// arrive beforehand as agentEventMsgs.
 type responseMsg struct {
-	events []agent.ChatEvent
-	err    error
+	err error
 }
 
 // initialModel creates the initial model state
 func initialModel(ag *agent.Agent) model {
+	messages := []chatMessage{
+		{
+			kind:    "system",
+			content: "Welcome to clifi! Type your questions below. Use /help for commands.",
+			time:    time.Now(),
+		},
+	}
+
+	keys, err := ui.LoadKeyMap(viper.GetStringMapString("keys"))
+	if err != nil {
+		messages = append(messages, chatMessage{
+			kind:    "error",
+			content: fmt.Sprintf("Invalid key bindings in config, using defaults: %v", err),
+			time:    time.Now(),
+		})
+	}
+
 	prompt := ui.NewPrompt()
+	prompt.SetKeyMap(keys)
 	prompt.Focus()
 
 	sp := spinner.New()
@@ -122,23 +190,18 @@ func initialModel(ag *agent.Agent) model {
 	sp.Style = lipgloss.NewStyle().Foreground(ui.ColorWarning)
 
 	return model{
-		agent:   ag,
-		prompt:  prompt,
-		spinner: sp,
-		mode:    modeChat,
-		messages: []chatMessage{
-			{
-				kind:    "system",
-				content: "Welcome to clifi! Type your questions below. Use /help for commands.",
-				time:    time.Now(),
-			},
-		},
+		agent:    ag,
+		prompt:   prompt,
+		spinner:  sp,
+		mode:     modeChat,
+		keys:     keys,
+		messages: messages,
 	}
 }
 
 // Init initializes the model
 func (m model) Init() tea.Cmd {
-	return tea.Batch(m.prompt.Focus(), m.spinner.Tick)
+	return tea.Batch(m.prompt.Focus(), m.spinner.Tick, m.reconcilePending())
 }
 
 // Update handles messages and updates state
@@ -149,35 +212,76 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.mode {
 	case modeModelSelector:
 		return m.updateModelSelector(msg)
+	case modeProviderSelector:
+		return m.updateProviderSelector(msg)
+	case modeThemeSelector:
+		return m.updateThemeSelector(msg)
+	case modeKeysHelp:
+		return m.updateKeysHelp(msg)
+	case modePassword:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updatePassword(msg)
+		}
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlC:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			if m.cancelRequest != nil {
+				m.cancelRequest()
+			}
 			m.quitting = true
 			return m, tea.Quit
 
-		case tea.KeyUp:
-			if len(m.suggestions) > 0 && m.suggestionIdx > 0 {
-				m.suggestionIdx--
+		case key.Matches(msg, m.keys.Cancel):
+			if m.loading {
+				if m.cancelRequest != nil && !m.cancelled {
+					m.cancelRequest()
+					m.cancelled = true
+				}
 				return m, nil
 			}
-
-		case tea.KeyDown:
-			if len(m.suggestions) > 0 && m.suggestionIdx < len(m.suggestions)-1 {
-				m.suggestionIdx++
+			if m.prompt.Value() != "" || m.editing {
+				m.prompt.Reset()
+				m.suggestions = nil
+				m.historyIdx = len(m.history)
+				m.editing = false
 				return m, nil
 			}
 
-		case tea.KeyTab:
+		case key.Matches(msg, m.keys.KeysHelp):
+			return m.handleKeysCommand()
+
+		case key.Matches(msg, m.keys.ModelSelector):
+			if !m.loading {
+				return m.handleModelCommand("")
+			}
+			return m, nil
+
+		case msg.Type == tea.KeyUp && len(m.suggestions) > 0 && m.suggestionIdx > 0:
+			m.suggestionIdx--
+			return m, nil
+
+		case msg.Type == tea.KeyDown && len(m.suggestions) > 0 && m.suggestionIdx < len(m.suggestions)-1:
+			m.suggestionIdx++
+			return m, nil
+
+		case key.Matches(msg, m.keys.HistoryPrev) && len(m.suggestions) == 0 && !m.prompt.Multiline():
+			m.historyPrev()
+			return m, nil
+
+		case key.Matches(msg, m.keys.HistoryNext) && len(m.suggestions) == 0 && !m.prompt.Multiline():
+			m.historyNext()
+			return m, nil
+
+		case msg.Type == tea.KeyTab:
 			if m.suggestionIdx >= 0 && m.suggestionIdx < len(m.suggestions) {
-				m.prompt.SetValue(m.suggestions[m.suggestionIdx].name)
-				m.suggestions = nil
+				m.completeCommand(m.suggestions[m.suggestionIdx])
 				return m, nil
 			}
 
-		case tea.KeyEnter:
+		case m.prompt.IsSubmit(msg):
 			if m.loading {
 				return m, nil
 			}
@@ -187,61 +291,96 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+			// Enter on a partial command picks the highlighted one,
+			// stopping for input if it needs arguments.
+			if cmd, ok := m.paletteChoice(input); ok {
+				if strings.HasPrefix(cmd.args, "<") {
+					m.completeCommand(cmd)
+					return m, nil
+				}
+				input = cmd.name
+			}
+			m.pushHistory(input)
+
 			// Handle commands
 			if strings.HasPrefix(input, "/") {
 				m.prompt.Reset()
 				m.suggestions = nil
 				m.suggestionIdx = 0
+				m.editing = false
 				return m.handleCommand(input)
 			}
 
-			m.addUser(input)
-
-			// Clear input and start loading
-			m.prompt.Reset()
-			m.suggestions = nil
-			m.loading = true
-			m.updateViewport()
+			// An edited message replaces the turn it came from.
+			if m.editing && m.agent != nil {
+				m.editing = false
+				if _, err := m.agent.UndoLastTurn(); err == nil {
+					m.dropLastTurn()
+				}
+			}
 
-			// Send to agent
-			return m, m.sendToAgent(input)
+			return m.startChat(input)
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
-		suggestionsHeight := len(m.suggestions)
-		if suggestionsHeight > 6 {
-			suggestionsHeight = 6
-		}
-
+		m.prompt.SetWidth(msg.Width - 2)
 		if !m.ready {
-			m.viewport = viewport.New(msg.Width, msg.Height-6-suggestionsHeight)
+			m.viewport = viewport.New(msg.Width, m.viewportHeight())
 			m.viewport.YPosition = 0
+			m.viewport.KeyMap = viewport.KeyMap{PageUp: m.keys.ScrollUp, PageDown: m.keys.ScrollDown}
 			m.ready = true
 		} else {
 			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - 6 - suggestionsHeight
+			m.viewport.Height = m.viewportHeight()
 		}
-		m.prompt.SetWidth(msg.Width - 2)
 		m.updateViewport()
 
+	case compactedMsg:
+		m.handleCompacted(msg)
+		m.updateViewport()
+		m.viewport.GotoBottom()
+
+	case toolRunMsg:
+		m.handleToolRun(msg)
+		m.updateViewport()
+		m.viewport.GotoBottom()
+
+	case walletCreatedMsg:
+		m.handleWalletCreated(msg)
+		m.updateViewport()
+		m.viewport.GotoBottom()
+
+	case pendingSettledMsg:
+		m.handlePendingSettled(msg)
+		m.updateViewport()
+		m.viewport.GotoBottom()
+
+	case tea.FocusMsg:
+		m.blurred = false
+
+	case tea.BlurMsg:
+		m.blurred = true
+
+	case agentEventMsg:
+		cmds = append(cmds, m.notifyCmd(msg.event))
+		m.handleAgentEvent(msg.event)
+		m.updateViewport()
+		m.viewport.GotoBottom()
+		cmds = append(cmds, waitForAgent(msg.ch))
+
 	case responseMsg:
 		m.loading = false
-		if msg.err != nil {
+		m.cancelRequest = nil
+		m.running = nil
+		if m.cancelled {
+			m.addSystem("Request cancelled.")
+		} else if msg.err != nil {
 			m.addError(msg.err.Error())
 		} else {
-			for _, event := range msg.events {
-				switch event.Type {
-				case "tool_call":
-					m.addToolCall(event.Tool, event.Args)
-				case "tool_result":
-					m.addToolResult(event.Tool, event.Content, event.Blocks)
-				case "content":
-					m.addAssistant(event.Content)
-				}
-			}
+			m.checkContext()
 		}
 		m.updateViewport()
 		m.viewport.GotoBottom()
@@ -261,6 +400,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Update suggestions based on input
 	m.updateSuggestions()
 
+	// The prompt grows with multi-line input; give the rows back to the
+	// viewport as it shrinks.
+	if m.ready {
+		m.viewport.Height = m.viewportHeight()
+	}
+
 	// Update viewport
 	var vpCmd tea.Cmd
 	m.viewport, vpCmd = m.viewport.Update(msg)
@@ -269,28 +414,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// updateSuggestions filters commands based on current input
-func (m *model) updateSuggestions() {
-	input := m.prompt.Value()
-	if !strings.HasPrefix(input, "/") || strings.Contains(input, " ") {
-		m.suggestions = nil
-		m.suggestionIdx = 0
-		return
-	}
-
-	input = strings.ToLower(input)
-	var filtered []command
-	for _, cmd := range commands {
-		if strings.HasPrefix(cmd.name, input) {
-			filtered = append(filtered, cmd)
-		}
-	}
-
-	// Reset index if suggestions changed
-	if len(filtered) != len(m.suggestions) {
-		m.suggestionIdx = 0
-	}
-	m.suggestions = filtered
+// viewportHeight is the terminal height minus the prompt, status lines and
+// command suggestions.
+func (m *model) viewportHeight() int {
+	return max(m.height-5-m.prompt.Height()-m.paletteHeight()-m.progressHeight(), 1)
 }
 
 // updateModelSelector handles input in model selector mode
@@ -343,6 +470,21 @@ func (m model) View() string {
 		b.WriteString(m.modelSelector.View())
 		return b.String()
 	}
+	if m.mode == modeKeysHelp {
+		b.WriteString("\n")
+		b.WriteString(m.keysHelpView())
+		return b.String()
+	}
+	if m.mode == modeProviderSelector {
+		b.WriteString("\n")
+		b.WriteString(m.providerSelector.View())
+		return b.String()
+	}
+	if m.mode == modeThemeSelector {
+		b.WriteString("\n")
+		b.WriteString(m.themeSelector.View())
+		return b.String()
+	}
 
 	// Chat mode
 	// Messages viewport
@@ -351,27 +493,19 @@ func (m model) View() string {
 
 	// Loading indicator
 	if m.loading {
-		b.WriteString(fmt.Sprintf("  %s Thinking...\n", m.spinner.View()))
+		b.WriteString(m.progressView())
 	}
 
 	// Input prompt
-	b.WriteString(m.prompt.View())
+	if m.mode == modePassword {
+		b.WriteString(m.password.input.View())
+	} else {
+		b.WriteString(m.prompt.View())
+	}
 	b.WriteString("\n")
 
-	// Command suggestions (if typing a command)
-	if len(m.suggestions) > 0 {
-		for i, cmd := range m.suggestions {
-			prefix := "  "
-			nameStyle := ui.PromptStyle
-			if i == m.suggestionIdx {
-				prefix = ui.SelectorCursor.Render(ui.SymbolArrow) + " "
-				nameStyle = ui.SelectorActive
-			}
-			name := nameStyle.Render(fmt.Sprintf("%-18s", cmd.name))
-			desc := ui.SelectorDim.Render(cmd.description)
-			b.WriteString(prefix + name + desc + "\n")
-		}
-	}
+	// Command palette (if typing a command)
+	b.WriteString(m.paletteView())
 
 	return b.String()
 }
@@ -385,7 +519,7 @@ func (m *model) updateViewport() {
 		case "user":
 			content.WriteString(ui.PromptStyle.Render(ui.SymbolPrompt))
 			content.WriteString(" ")
-			content.WriteString(msg.content)
+			content.WriteString(strings.ReplaceAll(msg.content, "\n", "\n  "))
 
 		case "tool_call":
 			content.WriteString(ui.ToolCallStyle.Render(ui.SymbolBullet))
@@ -395,13 +529,17 @@ func (m *model) updateViewport() {
 			args := summarizeArgs(msg.toolArgs, m.width-len(msg.toolName)-10)
 			content.WriteString(ui.SelectorDim.Render(args))
 			content.WriteString(ui.SelectorDim.Render(")"))
+			if msg.elapsed > 0 {
+				content.WriteString(ui.SelectorDim.Render(" " + formatElapsed(msg.elapsed)))
+			}
 
 		case "tool_result":
-			body := msg.content
+			body := ""
 			if len(msg.blocks) > 0 {
-				if rendered := renderBlocks(m.width-6, msg.blocks); rendered != "" {
-					body = rendered
-				}
+				body = renderBlocks(m.width-6, msg.blocks)
+			}
+			if body == "" {
+				body = ui.HighlightText(msg.content)
 			}
 			lines := strings.Split(body, "\n")
 			for i, line := range lines {
@@ -412,7 +550,7 @@ func (m *model) updateViewport() {
 				} else {
 					content.WriteString("    ")
 				}
-				content.WriteString(ui.ToolResultStyle.Render(line))
+				content.WriteString(ui.MarkCopyTargets(line, ui.ToolResultStyle))
 				if i < len(lines)-1 {
 					content.WriteString("\n")
 				}
@@ -438,6 +576,12 @@ func (m *model) updateViewport() {
 			content.WriteString(ui.ErrorStyle.Render("Error: "))
 			content.WriteString(msg.content)
 
+		case "warning":
+			content.WriteString(ui.WarningStyle.Render(ui.SymbolBullet))
+			content.WriteString(" ")
+			content.WriteString(ui.WarningStyle.Render("Warning: "))
+			content.WriteString(msg.content)
+
 		case "system":
 			content.WriteString(ui.SystemStyle.Render(msg.content))
 		}
@@ -500,12 +644,61 @@ func (m model) handleCommand(input string) (tea.Model, tea.Cmd) {
 	case "/status":
 		return m.handleStatusCommand()
 
+	case "/tokens":
+		return m.handleTokensCommand()
+
+	case "/usage":
+		return m.handleUsageCommand()
+
+	case "/compact":
+		return m.handleCompactCommand()
+
+	case "/plan":
+		return m.handlePlanCommand(arg)
+
+	case "/set":
+		return m.handleSetCommand(arg)
+
+	case "/retry":
+		return m.handleRetryCommand()
+
+	case "/edit":
+		return m.handleEditCommand(arg)
+
+	case "/copy":
+		return m.handleCopyCommand(arg)
+
+	case "/export":
+		return m.handleExportCommand(arg)
+
+	case "/search":
+		return m.handleSearchCommand(arg)
+
+	case "/resume":
+		return m.handleResumeCommand(arg)
+
+	case "/theme":
+		return m.handleThemeCommand(arg)
+
+	case "/keys":
+		return m.handleKeysCommand()
+
+	case "/wallet":
+		return m.handleWalletCommand(arg)
+
+	case "/context":
+		return m.handleContextCommand(arg)
+
+	case "/balance":
+		return m.handleBalanceCommand(arg)
+
 	case "/help", "/?":
 		var helpText strings.Builder
 		helpText.WriteString("Commands:\n")
 		for _, cmd := range commands {
-			helpText.WriteString(fmt.Sprintf("  %-12s %s\n", cmd.name, cmd.description))
+			helpText.WriteString(fmt.Sprintf("  %-12s %-28s %s\n", cmd.name, cmd.args, cmd.description))
 		}
+		helpText.WriteString("\nKey bindings: /keys\n")
 
 		m.addSystem(helpText.String())
 		m.updateViewport()
@@ -520,11 +713,7 @@ func (m model) handleCommand(input string) (tea.Model, tea.Cmd) {
 
 // handleLogout clears credentials and exits
 func (m model) handleLogout() (tea.Model, tea.Cmd) {
-	home, err := os.UserHomeDir()
-	if err == nil {
-		authPath := filepath.Join(home, ".clifi", "auth.json")
-		_ = os.Remove(authPath)
-	}
+	_ = os.Remove(filepath.Join(getDataDir(), "auth.json"))
 
 	m.addSystem("Credentials cleared. Restart clifi to set up again.")
 	m.updateViewport()
@@ -532,41 +721,53 @@ func (m model) handleLogout() (tea.Model, tea.Cmd) {
 	return m, tea.Quit
 }
 
-// handleModelCommand shows model selector or switches directly
-func (m model) handleModelCommand(modelID string) (tea.Model, tea.Cmd) {
+// handleModelCommand switches to the model named by arg, or shows the
+// model selector, filtered by arg when it isn't a model ID.
+func (m model) handleModelCommand(arg string) (tea.Model, tea.Cmd) {
 	if m.agent == nil {
 		m.addError("Agent not initialized.")
 		m.updateViewport()
 		return m, nil
 	}
 
-	if modelID != "" {
-		if err := m.agent.SetModel(modelID); err != nil {
+	current := m.agent.CurrentModel()
+	models := m.agent.ListModels()
+	provider := m.agent.ProviderName()
+
+	if arg != "" && llm.ValidateModelID(arg, models) == nil {
+		if err := m.agent.SetModel(arg); err != nil {
 			m.addErrorf("Failed to switch model: %v", err)
 			m.updateViewport()
 			return m, nil
 		}
 
-		m.addSystem(fmt.Sprintf("Switched to %s. Conversation cleared.", modelID))
+		m.addSystem(fmt.Sprintf("Switched to %s. Conversation cleared.", arg))
 		m.updateViewport()
 		return m, nil
 	}
 
-	current := m.agent.CurrentModel()
-	models := m.agent.ListModels()
-	provider := m.agent.ProviderName()
-
 	items := make([]ui.SelectorItem, len(models))
 	for i, md := range models {
 		items[i] = ui.SelectorItem{
 			ID:          md.ID,
 			Label:       md.ID,
-			Description: md.Name,
+			Description: modelDescription(md),
 			Current:     md.ID == current,
+			Columns:     modelColumns(md),
 		}
 	}
 
-	m.modelSelector = ui.NewSelector(fmt.Sprintf("Select %s model", provider), items)
+	selector := ui.NewSelector(fmt.Sprintf("Select %s model", provider), items)
+	if arg != "" {
+		selector.SetQuery(arg)
+		if selector.Matches() == 0 {
+			m.addErrorf("No %s model matches %q. Type /model to browse.", provider, arg)
+			m.updateViewport()
+			return m, nil
+		}
+	}
+	m.modelSelector = selector
+	m.modelSelector.SetColumns("Context", "$/1M in/out", "Tools")
 	m.modelSelector.SetWidth(m.width)
 	m.mode = modeModelSelector
 	m.prompt.Blur()
@@ -574,7 +775,51 @@ func (m model) handleModelCommand(modelID string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleProviderCommand lists or switches providers
+// modelColumns returns the context window, price and tool support shown
+// for a model in the selector.
+func modelColumns(md llm.Model) []string {
+	window := "-"
+	switch n := md.ContextWindow; {
+	case n >= 1_000_000:
+		window = strconv.FormatFloat(float64(n)/1_000_000, 'f', -1, 64) + "M"
+	case n >= 1000:
+		window = fmt.Sprintf("%dK", n/1000)
+	case n > 0:
+		window = strconv.Itoa(n)
+	}
+
+	cost := "-"
+	if md.InputCost > 0 || md.OutputCost > 0 {
+		cost = fmt.Sprintf("$%s/$%s", formatPrice(md.InputCost), formatPrice(md.OutputCost))
+	}
+
+	tools := ""
+	if md.SupportsTools {
+		tools = ui.SymbolCheck
+	}
+	return []string{window, cost, tools}
+}
+
+// modelDescription is a model's name, flagged when clifi has no details
+// for it.
+func modelDescription(md llm.Model) string {
+	if md.Unlisted {
+		return strings.TrimSpace(md.Name + " (new: no price or context data)")
+	}
+	return md.Name
+}
+
+// formatPrice shows a per-1M-token price with at least two decimals,
+// keeping any further digits (e.g. 0.075).
+func formatPrice(p float64) string {
+	str := strconv.FormatFloat(p, 'f', -1, 64)
+	if _, frac, _ := strings.Cut(str, "."); len(frac) > 2 {
+		return str
+	}
+	return fmt.Sprintf("%.2f", p)
+}
+
+// handleProviderCommand opens the provider selector or switches directly
 func (m model) handleProviderCommand(providerID string) (tea.Model, tea.Cmd) {
 	if m.agent == nil {
 		m.addError("Agent not initialized.")
@@ -589,49 +834,91 @@ func (m model) handleProviderCommand(providerID string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if providerID == "" {
-		connected := manager.ListConnected()
-		defaultProvider := manager.GetDefaultProvider()
-		current := m.agent.CurrentProviderID()
+	if providerID != "" {
+		m.switchProvider(llm.ProviderID(strings.ToLower(providerID)))
+		m.updateViewport()
+		return m, nil
+	}
 
-		var builder strings.Builder
-		builder.WriteString("Providers:\n")
-		if len(connected) == 0 {
-			builder.WriteString("  (none connected)\n")
-		} else {
-			for _, p := range connected {
-				marker := " "
-				if p == current {
-					marker = "*"
+	connected := manager.ListConnected()
+	if len(connected) == 0 {
+		m.addError("No providers connected. Use /auth <provider> <api_key> or set an API key env var.")
+		m.updateViewport()
+		return m, nil
+	}
+	defaultProvider := manager.GetDefaultProvider()
+	current := m.agent.CurrentProviderID()
+
+	items := make([]ui.SelectorItem, len(connected))
+	for i, p := range connected {
+		desc := "connected"
+		if p == defaultProvider {
+			desc = "connected, default"
+		}
+		items[i] = ui.SelectorItem{
+			ID:          string(p),
+			Label:       string(p),
+			Description: desc,
+			Current:     p == current,
+		}
+	}
+
+	m.providerSelector = ui.NewSelector("Select provider", items)
+	m.providerSelector.SetWidth(m.width)
+	m.mode = modeProviderSelector
+	m.prompt.Blur()
+
+	return m, nil
+}
+
+// updateProviderSelector handles input in provider selector mode
+func (m model) updateProviderSelector(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		selectorPtr, _ := m.providerSelector.Update(msg)
+		m.providerSelector = *selectorPtr
+
+		if !m.providerSelector.Active() {
+			m.mode = modeChat
+			if !m.providerSelector.Cancelled() {
+				selected := llm.ProviderID(m.providerSelector.Selected())
+				if selected != "" && selected != m.agent.CurrentProviderID() {
+					m.switchProvider(selected)
 				}
-				builder.WriteString(fmt.Sprintf("  %s %s\n", marker, p))
 			}
+			m.updateViewport()
+			return m, m.prompt.Focus()
 		}
-		builder.WriteString(fmt.Sprintf("\nCurrent: %s\nDefault: %s\nUse /provider <id> to switch.", current, defaultProvider))
 
-		m.addSystem(builder.String())
-		m.updateViewport()
-		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.providerSelector.SetWidth(msg.Width)
 	}
 
-	target := llm.ProviderID(strings.ToLower(providerID))
+	return m, nil
+}
+
+// switchProvider makes target the active and default provider.
+func (m *model) switchProvider(target llm.ProviderID) {
+	manager, err := getAuthManager()
+	if err != nil {
+		m.addErrorf("Failed to load auth manager: %v", err)
+		return
+	}
 	if !manager.HasCredential(target) {
 		m.addErrorf("Provider %s is not connected. Use /auth %s <api_key> or set env var.", target, target)
-		m.updateViewport()
-		return m, nil
+		return
 	}
 
 	if err := m.agent.SetProvider(target); err != nil {
 		m.addErrorf("Failed to switch provider: %v", err)
-		m.updateViewport()
-		return m, nil
+		return
 	}
 
 	_ = manager.SetDefaultProvider(target)
 
-	m.addSystem(fmt.Sprintf("Switched provider to %s (%s). Conversation cleared.", target, m.agent.ProviderName()))
-	m.updateViewport()
-	return m, nil
+	m.addSystem(fmt.Sprintf("Switched provider to %s (%s), model %s. Conversation cleared.", target, m.agent.ProviderName(), m.agent.CurrentModel()))
 }
 
 // handleAuthCommand stores an API key for a provider (API-key flows only)
@@ -704,11 +991,10 @@ func (m model) handleAuthCommand(arg string) (tea.Model, tea.Cmd) {
 
 // handleStatusCommand shows current provider/model and wallet info
 func (m model) handleStatusCommand() (tea.Model, tea.Cmd) {
-	currentProvider := ""
-	currentModel := ""
-	if m.agent != nil {
-		currentProvider = string(m.agent.CurrentProviderID())
-		currentModel = m.agent.CurrentModel()
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
 	}
 
 	manager, err := getAuthManager()
@@ -719,13 +1005,28 @@ func (m model) handleStatusCommand() (tea.Model, tea.Cmd) {
 		defaultProvider = manager.GetDefaultProvider()
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	tools := "on"
+	switch enabled, known := m.agent.ToolsEnabled(ctx); {
+	case !enabled:
+		tools = "off (model has no tool support; /model to switch)"
+	case !known:
+		tools = "on (model support unknown)"
+	}
+
 	// Wallet info
 	var walletLine string
 	dataDir := getDataDir()
 	if km, err := wallet.NewKeystoreManager(dataDir); err == nil {
-		accounts := km.ListAccounts()
-		if len(accounts) > 0 {
-			walletLine = fmt.Sprintf("%d wallet(s), first: %s", len(accounts), accounts[0].Address.Hex())
+		if def, err := km.DefaultAccount(); err == nil {
+			walletLine = def.Address.Hex()
+			if label := km.Label(def.Address); label != "" {
+				walletLine += " (" + label + ")"
+			}
+			if n := len(km.ListAccounts()); n > 1 {
+				walletLine += fmt.Sprintf(", %d wallets", n)
+			}
 		} else {
 			walletLine = "no wallets configured"
 		}
@@ -733,25 +1034,70 @@ func (m model) handleStatusCommand() (tea.Model, tea.Cmd) {
 		walletLine = fmt.Sprintf("wallet load error: %v", err)
 	}
 
-	var builder strings.Builder
-	builder.WriteString("Status:\n")
-	builder.WriteString(fmt.Sprintf("- Provider: %s (%s)\n", currentProvider, func() string {
-		if m.agent != nil {
-			return m.agent.ProviderName()
-		}
-		return "not initialized"
-	}()))
-	builder.WriteString(fmt.Sprintf("- Model: %s\n", currentModel))
-	builder.WriteString(fmt.Sprintf("- Connected providers: %s\n", strings.Join(providerIDsToStrings(connected), ", ")))
-	builder.WriteString(fmt.Sprintf("- Default provider: %s\n", defaultProvider))
-	builder.WriteString(fmt.Sprintf("- Wallets: %s\n", walletLine))
-	builder.WriteString("Use /provider <id> to switch; /model to change model.")
-
-	m.addSystem(builder.String())
+	chains := "none yet (connected on first use)"
+	if c := m.agent.ConnectedChains(); len(c) > 0 {
+		chains = strings.Join(c, ", ")
+	}
+
+	usage := m.agent.Usage()
+	status := &agent.UIKV{
+		Title: "Status",
+		Items: []agent.KVItem{
+			{Key: "Provider", Value: fmt.Sprintf("%s (%s)", m.agent.CurrentProviderID(), m.agent.ProviderName())},
+			{Key: "Model", Value: m.agent.CurrentModel()},
+			{Key: "Tools", Value: tools},
+			{Key: "Sampling", Value: m.agent.Sampling().String()},
+			{Key: "Providers", Value: fmt.Sprintf("%s (default: %s)", strings.Join(providerIDsToStrings(connected), ", "), defaultProvider)},
+			{Key: "Wallet", Value: walletLine},
+			{Key: "Session", Value: sessionDefaultsSummary(m.agent.Defaults())},
+			{Key: "Chains", Value: chains},
+			{Key: "RPC throttling", Value: throttleSummary(m.agent.ThrottleStats())},
+			{Key: "Policy", Value: m.agent.PolicySummary()},
+			{Key: "Tokens", Value: fmt.Sprintf("%d in / %d out this session", usage.InputTokens, usage.OutputTokens)},
+		},
+	}
+
+	m.addSystem(renderKV(m.width-4, status) + "\nUse /provider <id> to switch; /model to change model.")
 	m.updateViewport()
 	return m, nil
 }
 
+// sessionDefaultsSummary is the /context defaults on one line.
+func sessionDefaultsSummary(d agent.SessionDefaults) string {
+	if d.IsZero() {
+		return "no defaults (/context to set)"
+	}
+	var parts []string
+	if d.Chain != "" {
+		parts = append(parts, "chain "+d.Chain)
+	}
+	if d.Wallet != "" {
+		parts = append(parts, "wallet "+d.Wallet)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// throttleSummary totals the rate limiter waits and names the host that
+// waited longest, the one worth replacing with a private RPC.
+func throttleSummary(stats map[string]chain.ThrottleStats) string {
+	var requests, throttled int64
+	var waited, worst time.Duration
+	var worstHost string
+	for host, s := range stats {
+		requests += s.Requests
+		throttled += s.Throttled
+		waited += s.Waited
+		if s.Waited > worst {
+			worst, worstHost = s.Waited, host
+		}
+	}
+	if throttled == 0 {
+		return fmt.Sprintf("none (%d RPC requests)", requests)
+	}
+	return fmt.Sprintf("%d of %d RPC requests queued, %s total (most: %s)",
+		throttled, requests, waited.Round(time.Millisecond), worstHost)
+}
+
 func providerIDsToStrings(ids []llm.ProviderID) []string {
 	out := make([]string, len(ids))
 	for i, id := range ids {
@@ -760,18 +1106,26 @@ func providerIDsToStrings(ids []llm.ProviderID) []string {
 	return out
 }
 
+// startChat shows input as the user's message and sends it to the agent.
+func (m model) startChat(input string) (tea.Model, tea.Cmd) {
+	m.addUser(input)
+
+	// Clear input and start loading
+	m.prompt.Reset()
+	m.suggestions = nil
+	m.loading = true
+	m.cancelled = false
+	m.updateViewport()
+
+	// Send to agent
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	m.cancelRequest = cancel
+	return m, m.sendToAgent(ctx, cancel, input)
+}
+
 // sendToAgent sends a message to the agent and returns a command
-func (m model) sendToAgent(input string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
-
-		events, err := m.agent.ChatWithEvents(ctx, input)
-		return responseMsg{
-			events: events,
-			err:    err,
-		}
-	}
+func (m model) sendToAgent(ctx context.Context, cancel context.CancelFunc, input string) tea.Cmd {
+	return streamChat(ctx, cancel, m.agent, input)
 }
 
 // RunREPL starts the interactive REPL
@@ -781,12 +1135,61 @@ func RunREPL() error {
 		return fmt.Errorf("failed to create agent: %w", err)
 	}
 	defer ag.Close()
+	return runREPL(ag)
+}
+
+// configureAgent applies the config settings that aren't read by
+// agent.New: debug logging, optional tools, routing and sampling.
+func configureAgent(ag *agent.Agent) error {
+	if viper.GetBool("debug_llm") {
+		ag.SetDebugLLM(true)
+		fmt.Fprintf(os.Stderr, "Writing LLM requests and responses to %s\n", ag.DebugLLMDir())
+	}
+	if viper.GetBool("tools.raw_call") {
+		if err := ag.Tools().EnableRawCall(); err != nil {
+			return err
+		}
+	}
+	ag.SetRouting(routingPolicy())
+	ag.SetSampling(samplingPolicy())
+	ag.SetBudget(agent.Budget{
+		MonthlyUSD:  viper.GetFloat64("llm.budget.monthly_usd"),
+		WarnPercent: viper.GetFloat64("llm.budget.warn_percent"),
+	})
+	return nil
+}
+
+// routingPolicy reads the per-task models under llm.routing.<provider>.
+func routingPolicy() agent.RoutingPolicy {
+	p := agent.RoutingPolicy{}
+	for _, id := range llm.AllProviderIDs() {
+		for _, task := range agent.Tasks {
+			m := strings.TrimSpace(viper.GetString(fmt.Sprintf("llm.routing.%s.%s", id, task)))
+			if m == "" {
+				continue
+			}
+			if p[id] == nil {
+				p[id] = make(map[agent.Task]string)
+			}
+			p[id][task] = m
+		}
+	}
+	return p
+}
+
+func runREPL(ag *agent.Agent) error {
+	if err := configureAgent(ag); err != nil {
+		return err
+	}
+	// Fetch the live model list now so /model opens without waiting.
+	go ag.ListModels()
 
 	p := tea.NewProgram(
 		initialModel(ag),
 		tea.WithAltScreen(),
+		tea.WithReportFocus(),
 	)
 
-	_, err = p.Run()
+	_, err := p.Run()
 	return err
 }
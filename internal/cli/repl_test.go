@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+func TestModelColumns(t *testing.T) {
+	assert.Equal(t, []string{"200K", "$3.00/$15.00", ui.SymbolCheck},
+		modelColumns(llm.Model{ContextWindow: 200000, InputCost: 3, OutputCost: 15, SupportsTools: true}))
+	assert.Equal(t, []string{"1M", "$0.075/$0.30", ""},
+		modelColumns(llm.Model{ContextWindow: 1000000, InputCost: 0.075, OutputCost: 0.30}))
+	assert.Equal(t, []string{"1.5M", "-", ""}, modelColumns(llm.Model{ContextWindow: 1500000}))
+	assert.Equal(t, []string{"-", "-", ""}, modelColumns(llm.Model{}))
+
+	assert.Equal(t, "GPT-4o", modelDescription(llm.Model{Name: "GPT-4o"}))
+	assert.Equal(t, "(new: no price or context data)", modelDescription(llm.Model{Unlisted: true}))
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <session.json|session.jsonl|session-id>",
+	Short: "Re-run a recorded conversation's tool calls and diff the results",
+	Long: `Re-execute the tool calls from a recorded conversation against the current
+code and print a diff wherever a result differs from the recording. Accepts a
+/export json file, a session log, or the ID of a session in the data dir.
+
+Calls that broadcast (confirm=true) are skipped unless their chain is
+pointed at a fork with --fork, e.g. --fork base=http://127.0.0.1:8545 for
+anvil --fork-url <base rpc>. Live chain data changes over time, so replay
+against a fork pinned to a block for stable results.
+
+Exits non-zero if any result changed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringToString("fork", nil, "Point a chain at a fork RPC, as chain=url (repeatable)")
+	replayCmd.Flags().Bool("all", false, "Show unchanged results too")
+	replayCmd.Flags().Duration("timeout", 5*time.Minute, "Timeout for the whole replay")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	forks, _ := cmd.Flags().GetStringToString("fork")
+	showAll, _ := cmd.Flags().GetBool("all")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	path := args[0]
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// A bare ID refers to the data dir's session log.
+		if p := filepath.Join(getDataDir(), "sessions", path+".jsonl"); fileExists(p) {
+			path = p
+		}
+	}
+	calls, err := agent.LoadReplay(path)
+	if err != nil {
+		return err
+	}
+	if len(calls) == 0 {
+		fmt.Println("No tool calls to replay.")
+		return nil
+	}
+	cmd.SilenceUsage = true
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results, err := tr.Replay(ctx, calls, agent.ReplayOptions{
+		Forks: forks,
+		Password: func() (string, error) {
+			return readPassword("Wallet password for sends on forks: ")
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	var changed, skipped int
+	for i, r := range results {
+		label := fmt.Sprintf("[%d] %s", i+1, r.Call.Name)
+		switch {
+		case r.Skipped != "":
+			skipped++
+			fmt.Printf("%s  skipped: %s\n", label, r.Skipped)
+		case r.Diff != "":
+			changed++
+			fmt.Printf("%s  changed\n%s\n", label, indent(r.Diff))
+		case showAll:
+			fmt.Printf("%s  unchanged\n", label)
+		}
+	}
+
+	fmt.Printf("\n%d calls: %d unchanged, %d changed, %d skipped\n",
+		len(results), len(results)-changed-skipped, changed, skipped)
+	if changed > 0 {
+		return fmt.Errorf("%d of %d results changed", changed, len(results))
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = "    " + l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleRetryCommand sends the last message again for a new response.
+func (m model) handleRetryCommand() (tea.Model, tea.Cmd) {
+	input, ok := m.undoLastTurn()
+	if !ok {
+		return m, nil
+	}
+	return m.startChat(input)
+}
+
+// handleEditCommand puts the last message in the prompt to be changed and
+// resent; the original turn is replaced once it is submitted. With an
+// argument, the argument is sent in its place straight away.
+func (m model) handleEditCommand(arg string) (tea.Model, tea.Cmd) {
+	if arg != "" {
+		if _, ok := m.undoLastTurn(); !ok {
+			return m, nil
+		}
+		return m.startChat(arg)
+	}
+
+	last := m.lastUserMessage()
+	if last == "" {
+		m.addError("Nothing to edit yet.")
+		m.updateViewport()
+		return m, nil
+	}
+	m.prompt.SetValue(last)
+	m.editing = true
+	return m, nil
+}
+
+// undoLastTurn removes the last exchange from the agent and the transcript
+// on screen, returning the user's message. It reports failures itself.
+func (m *model) undoLastTurn() (string, bool) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return "", false
+	}
+	input, err := m.agent.UndoLastTurn()
+	if err != nil {
+		m.addErrorf("Cannot resend: %v.", err)
+		m.updateViewport()
+		return "", false
+	}
+	m.dropLastTurn()
+	return input, true
+}
+
+// dropLastTurn removes the last user message and everything shown after it.
+func (m *model) dropLastTurn() {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].kind == "user" {
+			m.messages = m.messages[:i]
+			return
+		}
+	}
+}
+
+// lastUserMessage returns the most recent message the user sent.
+func (m *model) lastUserMessage() string {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].kind == "user" {
+			return m.messages[i].content
+		}
+	}
+	return ""
+}
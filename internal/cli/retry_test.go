@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+func TestDropLastTurn(t *testing.T) {
+	var m model
+	m.addUser("first")
+	m.addAssistant("one")
+	m.addUser("second")
+	m.addToolCall("get_balances", "{}", 0)
+	m.addToolResult("get_balances", "ok", nil)
+	m.addAssistant("two")
+	assert.Equal(t, "second", m.lastUserMessage())
+
+	m.dropLastTurn()
+	require.Len(t, m.messages, 2)
+	assert.Equal(t, "first", m.lastUserMessage())
+
+	m.dropLastTurn()
+	assert.Empty(t, m.messages)
+	assert.Empty(t, m.lastUserMessage())
+}
+
+func TestHandleEditCommand(t *testing.T) {
+	m := model{prompt: ui.NewPrompt()}
+
+	next, _ := m.handleEditCommand("")
+	m = next.(model)
+	assert.False(t, m.editing)
+	assert.Equal(t, "error", m.messages[len(m.messages)-1].kind)
+
+	m.addUser("balance on base")
+	next, _ = m.handleEditCommand("")
+	m = next.(model)
+	assert.True(t, m.editing)
+	assert.Equal(t, "balance on base", m.prompt.Value())
+	assert.Equal(t, 1, m.paletteHeight(), "shows the editing hint")
+}
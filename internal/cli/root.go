@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/logging"
+	"github.com/yolodolo42/clifi/internal/netcfg"
 	"github.com/yolodolo42/clifi/internal/setup"
 )
 
@@ -21,6 +24,10 @@ It provides wallet management, portfolio tracking, and DeFi primitives
 with safety-first design and human-in-the-loop confirmation for all
 state-changing operations.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if demoMode, _ := cmd.Flags().GetBool("demo"); demoMode {
+				return RunDemo()
+			}
+
 			home, err := os.UserHomeDir()
 			if err != nil {
 				return fmt.Errorf("failed to get home directory: %w", err)
@@ -63,6 +70,14 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.clifi/config.yaml)")
 	rootCmd.PersistentFlags().String("chain", "ethereum", "Default chain to use")
 	_ = viper.BindPFlag("chain", rootCmd.PersistentFlags().Lookup("chain"))
+	rootCmd.PersistentFlags().String("log-level", "warn", "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().String("log-file", "", "Log file, or - for stderr (default is $HOME/.clifi/clifi.log)")
+	_ = viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
+	_ = viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
+
+	rootCmd.Flags().Bool("demo", false, "Try clifi offline: scripted replies, simulated chains, no API key or funds needed")
+	rootCmd.Flags().Bool("debug-llm", false, "Write each turn's raw LLM requests and responses (tool inputs redacted) to $HOME/.clifi/debug")
+	_ = viper.BindPFlag("debug_llm", rootCmd.Flags().Lookup("debug-llm"))
 }
 
 func initConfig() {
@@ -87,4 +102,27 @@ func initConfig() {
 
 	// Silently ignore missing config file - it's optional
 	_ = viper.ReadInConfig()
+
+	setupLogging()
+	loadTheme()
+	if err := netcfg.InstallDefault(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: network settings ignored: %v\n", err)
+	}
+}
+
+// setupLogging sends slog output to the configured file. Logging is an aid,
+// so a bad setting is reported and the default logger kept.
+func setupLogging() {
+	path := viper.GetString("log_file")
+	if path == "" {
+		path = filepath.Join(getDataDir(), "clifi.log")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if _, err := logging.Setup(viper.GetString("log_level"), path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: logging disabled: %v\n", err)
+	}
 }
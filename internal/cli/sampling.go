@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// samplingPolicy reads llm.sampling.<provider> from the config. Entries
+// out of the provider's range are logged and skipped rather than sent.
+func samplingPolicy() agent.SamplingPolicy {
+	var raw map[string]struct {
+		llm.Sampling `mapstructure:",squash"`
+		Models       map[string]llm.Sampling `mapstructure:"models"`
+	}
+	if err := viper.UnmarshalKey("llm.sampling", &raw); err != nil {
+		slog.Warn("ignoring llm.sampling", "err", err)
+		return nil
+	}
+	p := agent.SamplingPolicy{}
+	for id, cfg := range raw {
+		pid := llm.ProviderID(id)
+		if err := cfg.Sampling.Validate(pid); err != nil {
+			slog.Warn("ignoring llm.sampling", "provider", id, "err", err)
+			continue
+		}
+		ps := agent.ProviderSampling{Default: cfg.Sampling, Models: map[string]llm.Sampling{}}
+		for model, s := range cfg.Models {
+			if err := s.Validate(pid); err != nil {
+				slog.Warn("ignoring llm.sampling", "provider", id, "model", model, "err", err)
+				continue
+			}
+			ps.Models[model] = s
+		}
+		p[pid] = ps
+	}
+	return p
+}
+
+// handleSetCommand shows or overrides generation parameters for the
+// session: /set temperature 0.2, /set top_p default.
+func (m model) handleSetCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+	fields := strings.Fields(arg)
+	switch len(fields) {
+	case 0:
+		m.addSystem(fmt.Sprintf("Sampling for %s: %s. Change with /set <%s> <value|default>.",
+			m.agent.CurrentModel(), m.agent.Sampling(), strings.Join(llm.SamplingParams, "|")))
+	case 2:
+		s, err := m.agent.SetSamplingParam(fields[0], fields[1])
+		if err != nil {
+			m.addErrorf("Failed to set %s: %v", fields[0], err)
+		} else {
+			m.addSystem("Sampling for this session: " + s.String())
+		}
+	default:
+		m.addError("Usage: /set <" + strings.Join(llm.SamplingParams, "|") + "> <value|default>")
+	}
+	m.updateViewport()
+	return m, nil
+}
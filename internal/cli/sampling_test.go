@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+func TestSamplingPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`llm:
+  sampling:
+    anthropic:
+      temperature: 0.2
+      models:
+        claude-3-5-haiku-20241022:
+          max_tokens: 1024
+    openrouter:
+      top_p: 0.9
+      models:
+        deepseek/deepseek-r1:
+          temperature: 0.6
+        anthropic/claude-3.5-sonnet:
+          max_tokens: 2048
+        meta-llama/llama-3.3-70b:
+          temperature: 5
+    gemini:
+      temperature: 7
+`), 0600))
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	require.NoError(t, viper.ReadInConfig())
+
+	p := samplingPolicy()
+	require.Contains(t, p, llm.ProviderAnthropic)
+	assert.Equal(t, 0.2, *p[llm.ProviderAnthropic].Default.Temperature)
+	assert.Equal(t, 1024, p[llm.ProviderAnthropic].Models["claude-3-5-haiku-20241022"].MaxTokens)
+	assert.Equal(t, 0.9, *p[llm.ProviderOpenRouter].Default.TopP)
+	assert.Equal(t, 0.6, *p[llm.ProviderOpenRouter].Models["deepseek/deepseek-r1"].Temperature)
+	assert.Equal(t, 2048, p[llm.ProviderOpenRouter].Models["anthropic/claude-3.5-sonnet"].MaxTokens, "dots in model IDs survive")
+	assert.NotContains(t, p[llm.ProviderOpenRouter].Models, "meta-llama/llama-3.3-70b", "out of range entries are skipped")
+	assert.NotContains(t, p, llm.ProviderGemini)
+}
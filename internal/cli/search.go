@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+// searchLimit caps the matches /search lists.
+const searchLimit = 20
+
+// handleSearchCommand lists earlier messages, from every session, matching
+// the words given.
+func (m model) handleSearchCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+	if arg == "" {
+		m.addError("Usage: /search <words>, e.g. /search approvals on base")
+		m.updateViewport()
+		return m, nil
+	}
+
+	hits, err := m.agent.SearchHistory(arg, searchLimit)
+	switch {
+	case err != nil:
+		m.addErrorf("Search failed: %v", err)
+	case len(hits) == 0:
+		m.addSystem(fmt.Sprintf("No earlier messages match %q.", arg))
+	default:
+		m.addSystem(renderTable(m.width-4, searchTable(arg, hits)) + "\nPick up from a message with /resume <#>.")
+	}
+	m.updateViewport()
+	return m, nil
+}
+
+// searchTable lays out /search matches, best first.
+func searchTable(query string, hits []agent.ChatHit) *agent.UITable {
+	t := &agent.UITable{
+		Title:   fmt.Sprintf("Messages matching %q", query),
+		Headers: []string{"#", "When", "From", "Message"},
+	}
+	for _, h := range hits {
+		from := "you"
+		if h.Role == "assistant" {
+			from = "clifi"
+		}
+		t.Rows = append(t.Rows, []string{strconv.FormatInt(h.ID, 10), h.At.Format("2006-01-02 15:04"), from, h.Snippet})
+	}
+	return t
+}
+
+// handleResumeCommand replaces the conversation with a stored one, up to
+// the message numbered in /search.
+func (m model) handleResumeCommand(arg string) (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil {
+		m.addError("Usage: /resume <#>, with a message number from /search")
+		m.updateViewport()
+		return m, nil
+	}
+
+	msgs, err := m.agent.Resume(id)
+	if err != nil {
+		m.addErrorf("Resume failed: %v", err)
+		m.updateViewport()
+		return m, nil
+	}
+	m.messages = nil
+	for _, msg := range msgs {
+		if msg.Role == "user" {
+			m.addUser(msg.Content)
+		} else {
+			m.addAssistant(msg.Content)
+		}
+	}
+	m.addSystem(fmt.Sprintf("Resumed the conversation at message #%d (%d messages).", id, len(msgs)))
+	m.updateViewport()
+	return m, nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+var sendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send ETH or an ERC20 token",
+	Long: `Send the native currency or an ERC20 token without the agent. The
+transaction goes through the same policy checks and preview as the chat
+send tools (CLIFI_MAX_TX_ETH, CLIFI_ALLOW_TO, CLIFI_DENY_TO,
+CLIFI_PRIVATE_TX) and recipient screening (CLIFI_SCREEN_*); nothing is
+signed until you confirm and enter the wallet password.
+
+For testnet CI, the environment signer (CLIFI_SIGNER_KEY or
+CLIFI_SIGNER_KEY_FD) signs without a password and --yes skips the
+confirmation; a send with warnings is still refused. It is unsafe for
+real funds and refuses mainnets unless CLIFI_SIGNER_ALLOW_MAINNET=1.`,
+	Example: `  clifi send --chain base --to 0x... --amount 0.1
+  clifi send --chain arbitrum --to 0x... --amount 25 --token usdc
+  CLIFI_SIGNER_KEY=... clifi send --chain sepolia --to 0x... --amount 0.01 --yes`,
+	Args: cobra.NoArgs,
+	RunE: runSend,
+}
+
+func init() {
+	rootCmd.AddCommand(sendCmd)
+
+	sendCmd.Flags().String("to", "", "Recipient address")
+	sendCmd.Flags().String("amount", "", "Amount in ETH, or in token units with --token")
+	sendCmd.Flags().String("token", "", "ERC20 contract address or listed symbol (e.g. USDC)")
+	sendCmd.Flags().String("from", "", "Sending wallet: label, address or index (default wallet if empty)")
+	sendCmd.Flags().Bool("private", false, "Submit through a private relay instead of the public mempool")
+	sendCmd.Flags().Bool("no-wait", false, "Return after broadcasting instead of waiting for the receipt")
+	sendCmd.Flags().Bool("yes", false, "Send without confirming; environment signer only (testnet CI)")
+	_ = sendCmd.MarkFlagRequired("to")
+	_ = sendCmd.MarkFlagRequired("amount")
+}
+
+func runSend(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+	amount, _ := cmd.Flags().GetString("amount")
+	token, _ := cmd.Flags().GetString("token")
+	from, _ := cmd.Flags().GetString("from")
+	noWait, _ := cmd.Flags().GetBool("no-wait")
+	yes, _ := cmd.Flags().GetBool("yes")
+	cmd.SilenceUsage = true
+
+	req := agent.SendRequest{
+		Chain:  strings.ToLower(viper.GetString("chain")),
+		To:     to,
+		Amount: amount,
+		Token:  token,
+	}
+	if from != "" {
+		addr, err := resolveAddress(from)
+		if err != nil {
+			return err
+		}
+		req.From = addr.Hex()
+	}
+	// Unset leaves the CLIFI_PRIVATE_TX default in charge.
+	if cmd.Flags().Changed("private") {
+		private, _ := cmd.Flags().GetBool("private")
+		req.Private = &private
+	}
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	prepared, err := tr.PrepareSend(ctx, req)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize keystore: %w", err)
+	}
+	if yes && !km.IsEnvSigner(prepared.From) {
+		return fmt.Errorf("--yes only works with the environment signer (%s)", wallet.EnvSignerKey)
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, prepared.Preview)
+	switch {
+	case yes && len(prepared.Warnings) > 0:
+		return fmt.Errorf("not sending with warnings (see above) under --yes")
+	case !yes && !confirmSend(os.Stdin, out, prepared):
+		return fmt.Errorf("cancelled; nothing was sent")
+	}
+
+	var password string
+	if km.NeedsPassword(prepared.From) {
+		if password, err = readPassword("Wallet password: "); err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+
+	sendCtx, cancel := context.WithTimeout(cmd.Context(), 3*time.Minute)
+	defer cancel()
+	sent, err := tr.SendPrepared(sendCtx, prepared, password, !noWait)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Broadcast: %s\n", sent.Hash.Hex())
+	if sent.ExplorerURL != "" {
+		fmt.Fprintf(out, "Explorer:  %s\n", sent.ExplorerURL)
+	}
+	if sent.Receipt != "" {
+		fmt.Fprintln(out, sent.Receipt)
+	}
+	if sent.Confirmed != nil && !sent.Confirmed.Success {
+		return fmt.Errorf("transaction reverted")
+	}
+	return nil
+}
+
+// confirmSend asks before signing. Contract and screening warnings need a
+// typed "yes" so they can't be waved through with a reflexive y.
+func confirmSend(in io.Reader, out io.Writer, p *agent.PreparedSend) bool {
+	prompt := fmt.Sprintf("Send %s %s to %s on %s? [y/N] ", p.Amount, p.Symbol, p.To.Hex(), p.Chain)
+	accept := []string{"y", "yes"}
+	if len(p.Warnings) > 0 {
+		prompt = "There are warnings (see above). Type yes to send anyway: "
+		accept = []string{"yes"}
+	}
+	fmt.Fprint(out, prompt)
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, a := range accept {
+		if answer == a {
+			return true
+		}
+	}
+	return false
+}
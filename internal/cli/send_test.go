@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+func TestConfirmSend(t *testing.T) {
+	p := &agent.PreparedSend{Chain: "base", Amount: "0.1", Symbol: "ETH"}
+	var out bytes.Buffer
+	assert.True(t, confirmSend(strings.NewReader("y\n"), &out, p))
+	assert.Contains(t, out.String(), "Send 0.1 ETH")
+	assert.False(t, confirmSend(strings.NewReader("\n"), &out, p), "default is no")
+	assert.False(t, confirmSend(strings.NewReader(""), &out, p))
+
+	p.Warnings = []string{"⚠ WARNING: unverified"}
+	assert.False(t, confirmSend(strings.NewReader("y\n"), &out, p), "warnings need a typed yes")
+	assert.True(t, confirmSend(strings.NewReader("YES\n"), &out, p))
+}
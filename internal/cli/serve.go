@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/audit"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/dca"
+	"github.com/yolodolo42/clifi/internal/orders"
+	"github.com/yolodolo42/clifi/internal/portfolio"
+	"github.com/yolodolo42/clifi/internal/quote"
+	"github.com/yolodolo42/clifi/internal/wallet"
+	"github.com/yolodolo42/clifi/internal/watch"
+	"golang.org/x/term"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run background jobs such as alerts, snapshots, recurring buys and limit orders",
+	Long: `Run clifi's background jobs in the foreground until interrupted. It
+checks the watches added with 'clifi watch add' (or by the agent) and
+notifies when one fires: printed here, as a desktop notification
+(watch.desktop, on by default) and POSTed to watch.webhook when set.
+
+It also snapshots the default wallet's portfolio every
+portfolio.snapshot_interval (24h by default, 0 to turn off), for
+'clifi portfolio diff'.
+
+It checks the transactions clifi sent that were still pending when it
+last ran, and keeps checking new ones every --interval, notifying once
+each is mined (confirmed or failed) or dropped for another transaction
+with its nonce.
+
+It prunes receipts.db daily to db.retention.months and
+db.retention.rows, if set, and vacuums it once a quarter of the file is
+free space.
+
+It watches limit orders ('clifi order add'). A triggered order waits for
+'clifi order confirm' and notifies like a watch, unless
+orders.auto_approve is set, in which case it is filled right away within
+the policy limits.
+
+Recurring swaps ('clifi dca add') and auto-approved orders sign with the
+wallet password from --wallet-password-file or --wallet-password-env (or
+a prompt). Wallets without a password, a KMS key or the environment
+signer, need none. Ones added while serve runs are picked up once it can
+sign, i.e. when some existed at startup or a password flag was passed.`,
+	Example: `  clifi serve
+  clifi serve --wallet-password-file ~/.secrets/clifi`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().Duration("interval", 0, "How often to check watches (default watch.interval, or 1m)")
+	serveCmd.Flags().String("wallet-password-file", "", "File holding the wallet password, for unattended swaps")
+	serveCmd.Flags().String("wallet-password-env", "", "Environment variable holding the wallet password, for unattended swaps")
+	viper.SetDefault("watch.interval", watch.DefaultInterval.String())
+	viper.SetDefault("watch.desktop", true)
+	viper.SetDefault("portfolio.snapshot_interval", "24h")
+	viper.SetDefault("orders.auto_approve", false)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		interval = viper.GetDuration("watch.interval")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid watch.interval %q", viper.GetString("watch.interval"))
+	}
+	cmd.SilenceUsage = true
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	notifiers := []watch.Notifier{watch.Writer{W: os.Stdout}}
+	if viper.GetBool("watch.desktop") {
+		notifiers = append(notifiers, watch.Desktop{})
+	}
+	if hook := viper.GetString("watch.webhook"); hook != "" {
+		notifiers = append(notifiers, watch.Webhook{URL: hook})
+	}
+
+	w := &watch.Watcher{
+		Store:     watch.NewStore(getDataDir()),
+		Source:    watch.ChainSource{Chains: client, Quotes: quote.NewClient()},
+		Notifiers: notifiers,
+		Interval:  interval,
+	}
+
+	// Unattended swaps need the wallet password, unless every wallet they
+	// use signs without one. Ask before anything runs, so the prompt isn't
+	// interleaved with alerts.
+	plans, err := dca.NewStore(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	openOrders, err := orders.NewStore(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	autoApprove := viper.GetBool("orders.auto_approve")
+	var wallets []string
+	for _, p := range plans {
+		wallets = append(wallets, p.Wallet)
+	}
+	for _, o := range openOrders {
+		if autoApprove && o.Status == orders.StatusOpen {
+			wallets = append(wallets, o.Wallet)
+		}
+	}
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to load wallets: %w", err)
+	}
+	needPassword := cmd.Flags().Changed("wallet-password-file") || cmd.Flags().Changed("wallet-password-env")
+	for _, addr := range wallets {
+		needPassword = needPassword || km.NeedsPassword(common.HexToAddress(addr))
+	}
+	var password string
+	if needPassword {
+		if password, err = servePassword(cmd); err != nil {
+			return err
+		}
+		if err := unlockWallets(wallets, password); err != nil {
+			return err
+		}
+	}
+	canSign := password != "" || len(wallets) > 0
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tr := agent.NewToolRegistryWithDataDir(getDataDir())
+	defer tr.Close()
+	auditLog := audit.New(getDataDir())
+	if canSign {
+		runner := &dca.Runner{
+			Store: dca.NewStore(getDataDir()),
+			Exec:  dcaExecutor{tr: tr, password: password},
+			Audit: auditLog,
+			Out:   os.Stdout,
+		}
+		fmt.Printf("Running recurring swaps (%d now); each run is logged to %s.\n", len(plans), audit.File)
+		go func() { _ = runner.Run(ctx) }()
+	}
+
+	monitor := &orders.Monitor{
+		Store:     orders.NewStore(getDataDir()),
+		Prices:    w.Source,
+		Notifiers: notifiers,
+		Audit:     auditLog,
+		Interval:  interval,
+	}
+	if autoApprove && canSign {
+		monitor.Exec = orderExecutor{tr: tr, password: password}
+		fmt.Println("Filling triggered limit orders automatically (orders.auto_approve).")
+	} else {
+		fmt.Println("Triggered limit orders wait for 'clifi order confirm'.")
+	}
+	go func() { _ = monitor.Run(ctx) }()
+
+	fmt.Println("Following transactions sent but not yet mined, and notifying once they settle.")
+	go runPendingTxs(ctx, tr, notifiers, interval)
+
+	if every := viper.GetDuration("portfolio.snapshot_interval"); every > 0 {
+		if address, err := resolveAddress(""); err != nil {
+			fmt.Printf("Not taking portfolio snapshots: %v\n", err)
+		} else {
+			fmt.Printf("Snapshotting %s every %s.\n", address.Hex(), every)
+			go runSnapshots(ctx, client, address, every)
+		}
+	}
+
+	if r := retention(); !r.IsZero() {
+		fmt.Printf("Pruning %s daily to the db.retention settings.\n", agent.ReceiptDBFile)
+	}
+	go runDBMaintenance(ctx)
+
+	fmt.Printf("Checking watches every %s. Ctrl+C to stop.\n", interval)
+	if err := w.Run(ctx); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// runSnapshots records a portfolio snapshot every interval until ctx is
+// done. The first one is taken right away unless the latest snapshot is
+// recent enough, so restarting serve doesn't pile up snapshots.
+func runSnapshots(ctx context.Context, client *chain.Client, address common.Address, interval time.Duration) {
+	store, err := portfolio.OpenStore(getDataDir())
+	if err != nil {
+		slog.Warn("portfolio snapshots disabled", "err", err)
+		return
+	}
+	defer store.Close()
+
+	wait := time.Duration(0)
+	if last, err := store.Latest(address.Hex()); err == nil {
+		wait = max(0, interval-time.Since(last.TakenAt))
+	}
+	prices := quote.NewClient()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		takeCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		snap, err := portfolio.Take(takeCtx, client, prices, address, nil)
+		cancel()
+		if err == nil {
+			_, err = store.Save(snap)
+		}
+		if err != nil {
+			slog.Warn("portfolio snapshot failed", "err", err)
+		}
+		timer.Reset(interval)
+	}
+}
+
+// servePassword reads the wallet password for unattended signing from
+// --wallet-password-file or --wallet-password-env, prompting only when
+// attached to a terminal.
+func servePassword(cmd *cobra.Command) (string, error) {
+	if path, _ := cmd.Flags().GetString("wallet-password-file"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read wallet password: %w", err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if name, _ := cmd.Flags().GetString("wallet-password-env"); name != "" {
+		password := os.Getenv(name)
+		if password == "" {
+			return "", fmt.Errorf("%s is not set", name)
+		}
+		return password, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("recurring swaps and auto-approved orders need the wallet password: pass --wallet-password-file or --wallet-password-env")
+	}
+	return readPassword("Wallet password for unattended swaps: ")
+}
+
+// unlockWallets checks password against each wallet that needs one, so a
+// wrong password fails at startup instead of at the first swap.
+func unlockWallets(wallets []string, password string) error {
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to load wallets: %w", err)
+	}
+	checked := make(map[string]bool)
+	for _, addr := range wallets {
+		if checked[addr] {
+			continue
+		}
+		checked[addr] = true
+		if !km.NeedsPassword(common.HexToAddress(addr)) {
+			continue
+		}
+		if _, err := km.GetSigner(common.HexToAddress(addr), password); err != nil {
+			return fmt.Errorf("cannot unlock %s: %w", addr, err)
+		}
+	}
+	return nil
+}
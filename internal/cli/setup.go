@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/llm"
 	"github.com/yolodolo42/clifi/internal/setup"
 )
 
@@ -15,28 +16,68 @@ var setupCmd = &cobra.Command{
 This command guides you through:
   - Connecting an LLM provider (Anthropic, OpenAI, etc.)
   - Creating or importing a wallet
+  - Choosing chains and private RPC URLs
 
-Use this command to reconfigure clifi or add additional providers.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if !setup.IsInteractive() {
-			setup.PrintEnvInstructions()
-			return fmt.Errorf("setup requires an interactive terminal")
-		}
+Use this command to reconfigure clifi or add additional providers.
 
-		result, err := setup.RunWizard()
-		if err != nil {
-			return fmt.Errorf("setup failed: %w", err)
-		}
+Pass --provider to configure clifi without the wizard, e.g. in CI,
+containers or dotfile installers. Secrets come from environment
+variables or files only:
 
-		if result == nil || result.Cancelled {
-			return nil
-		}
-
-		fmt.Println("\nSetup complete! Run 'clifi' to start.")
-		return nil
-	},
+  clifi setup --provider openai --api-key-env OPENAI_API_KEY \
+    --create-wallet --wallet-password-file ~/.secrets/clifi`,
+	RunE: runSetup,
 }
 
 func init() {
 	rootCmd.AddCommand(setupCmd)
+
+	setupCmd.Flags().String("provider", "", "Configure this provider without the wizard (anthropic, openai, gemini, copilot, venice, openrouter)")
+	setupCmd.Flags().String("api-key-env", "", "Environment variable holding the API key (default: the provider's usual variable)")
+	setupCmd.Flags().String("api-key-file", "", "File holding the API key")
+	setupCmd.Flags().Bool("validate", false, "Make a test request before saving the API key")
+	setupCmd.Flags().Bool("create-wallet", false, "Create a wallet unless one already exists")
+	setupCmd.Flags().String("import-wallet-env", "", "Environment variable holding a private key or seed phrase to import")
+	setupCmd.Flags().String("wallet-password-file", "", "File holding the wallet password")
+	setupCmd.Flags().String("wallet-password-env", "", "Environment variable holding the wallet password")
+}
+
+func runSetup(cmd *cobra.Command, args []string) error {
+	if provider, _ := cmd.Flags().GetString("provider"); provider != "" {
+		return runHeadlessSetup(cmd, llm.ProviderID(provider))
+	}
+
+	if !setup.IsInteractive() {
+		setup.PrintEnvInstructions()
+		return fmt.Errorf("setup requires an interactive terminal (or --provider for non-interactive setup)")
+	}
+
+	result, err := setup.RunWizard()
+	if err != nil {
+		return fmt.Errorf("setup failed: %w", err)
+	}
+
+	if result == nil || result.Cancelled {
+		return nil
+	}
+
+	fmt.Println("\nSetup complete! Run 'clifi' to start.")
+	return nil
+}
+
+func runHeadlessSetup(cmd *cobra.Command, provider llm.ProviderID) error {
+	opts := setup.HeadlessOptions{Provider: provider}
+	opts.APIKeyEnv, _ = cmd.Flags().GetString("api-key-env")
+	opts.APIKeyFile, _ = cmd.Flags().GetString("api-key-file")
+	opts.ValidateKey, _ = cmd.Flags().GetBool("validate")
+	opts.CreateWallet, _ = cmd.Flags().GetBool("create-wallet")
+	opts.ImportWalletEnv, _ = cmd.Flags().GetString("import-wallet-env")
+	opts.WalletPasswordFile, _ = cmd.Flags().GetString("wallet-password-file")
+	opts.WalletPasswordEnv, _ = cmd.Flags().GetString("wallet-password-env")
+
+	if _, err := setup.RunHeadless(cmd.Context(), getDataDir(), opts, cmd.OutOrStdout()); err != nil {
+		return fmt.Errorf("setup failed: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Setup complete! Run 'clifi' to start.")
+	return nil
 }
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/solana"
+)
+
+var walletSolanaCmd = &cobra.Command{
+	Use:   "solana",
+	Short: "Manage Solana keypairs",
+	Long:  `Create, import, and list Solana keypairs. They are stored encrypted in a separate keystore from Ethereum accounts.`,
+}
+
+var walletSolanaCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new Solana keypair",
+	RunE:  runWalletSolanaCreate,
+}
+
+var walletSolanaImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a Solana keypair from a secret key",
+	RunE:  runWalletSolanaImport,
+}
+
+var walletSolanaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List Solana keypairs",
+	RunE:  runWalletSolanaList,
+}
+
+func init() {
+	walletCmd.AddCommand(walletSolanaCmd)
+	walletSolanaCmd.AddCommand(walletSolanaCreateCmd)
+	walletSolanaCmd.AddCommand(walletSolanaImportCmd)
+	walletSolanaCmd.AddCommand(walletSolanaListCmd)
+
+	walletSolanaImportCmd.Flags().String("key", "", "Secret key to import (base58, or a solana-keygen JSON byte array)")
+}
+
+// readNewPassword prompts for a password twice and enforces the minimum length.
+func readNewPassword(prompt string) (string, error) {
+	password, err := readPassword(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	if len(password) < 8 {
+		return "", fmt.Errorf("password must be at least 8 characters")
+	}
+	confirm, err := readPassword("Confirm password: ")
+	if err != nil {
+		return "", fmt.Errorf("failed to read password confirmation: %w", err)
+	}
+	if password != confirm {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return password, nil
+}
+
+func runWalletSolanaCreate(cmd *cobra.Command, args []string) error {
+	ks, err := solana.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize solana keystore: %w", err)
+	}
+
+	password, err := readNewPassword("Enter password for new Solana wallet: ")
+	if err != nil {
+		return err
+	}
+
+	pub, err := ks.Create(password)
+	if err != nil {
+		return fmt.Errorf("failed to create keypair: %w", err)
+	}
+
+	fmt.Println("\nSolana wallet created successfully!")
+	fmt.Printf("Address: %s\n", pub)
+	printWeakScrypt()
+	fmt.Println("\nIMPORTANT: Back up your keystore file and remember your password!")
+	return nil
+}
+
+func runWalletSolanaImport(cmd *cobra.Command, args []string) error {
+	secret, _ := cmd.Flags().GetString("key")
+	if secret == "" {
+		fmt.Print("Enter secret key (base58 or JSON byte array): ")
+		var input string
+		_, _ = fmt.Scanln(&input)
+		secret = strings.TrimSpace(input)
+	}
+	if secret == "" {
+		return fmt.Errorf("secret key is required")
+	}
+
+	ks, err := solana.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize solana keystore: %w", err)
+	}
+
+	password, err := readNewPassword("Enter password to encrypt wallet: ")
+	if err != nil {
+		return err
+	}
+
+	pub, err := ks.Import(secret, password)
+	if err != nil {
+		return fmt.Errorf("failed to import key: %w", err)
+	}
+
+	fmt.Println("\nSolana wallet imported successfully!")
+	fmt.Printf("Address: %s\n", pub)
+	printWeakScrypt()
+	return nil
+}
+
+func runWalletSolanaList(cmd *cobra.Command, args []string) error {
+	ks, err := solana.NewKeystore(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize solana keystore: %w", err)
+	}
+	keys, err := ks.List()
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No Solana wallets found.")
+		fmt.Println("Use 'clifi wallet solana create' to create one.")
+		return nil
+	}
+
+	fmt.Printf("Found %d Solana wallet(s):\n\n", len(keys))
+	for i, k := range keys {
+		fmt.Printf("%d. %s\n", i+1, k)
+	}
+	return nil
+}
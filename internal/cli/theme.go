@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/setup"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// loadTheme applies the theme and theme_colors from config. A bad theme is
+// reported and the default kept, so a typo never blocks startup.
+func loadTheme() {
+	t, err := ui.LoadTheme(viper.GetString("theme"), viper.GetStringMapString("theme_colors"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using default theme\n", err)
+		t = ui.DefaultTheme()
+	}
+	applyTheme(t)
+}
+
+// applyTheme switches every package-level style to t.
+func applyTheme(t ui.Theme) {
+	ui.ApplyTheme(t)
+	setup.RefreshStyles()
+	mdRenderer = newMarkdownRenderer()
+}
+
+// handleThemeCommand switches theme by name or opens the theme picker.
+func (m model) handleThemeCommand(name string) (tea.Model, tea.Cmd) {
+	if name != "" {
+		m.switchTheme(name)
+		m.updateViewport()
+		return m, nil
+	}
+
+	current := ui.CurrentTheme().Name
+	names := append(ui.ThemeNames(), ui.CustomTheme)
+	items := make([]ui.SelectorItem, len(names))
+	for i, n := range names {
+		desc := ""
+		if n == ui.CustomTheme {
+			desc = "dark with theme_colors from config"
+		}
+		items[i] = ui.SelectorItem{
+			ID:          n,
+			Label:       n,
+			Description: desc,
+			Current:     n == current,
+		}
+	}
+
+	m.themeSelector = ui.NewSelector("Select theme", items)
+	m.themeSelector.SetWidth(m.width)
+	m.mode = modeThemeSelector
+	m.prompt.Blur()
+
+	return m, nil
+}
+
+// updateThemeSelector handles input in theme selector mode
+func (m model) updateThemeSelector(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		selectorPtr, _ := m.themeSelector.Update(msg)
+		m.themeSelector = *selectorPtr
+
+		if !m.themeSelector.Active() {
+			m.mode = modeChat
+			if !m.themeSelector.Cancelled() {
+				if selected := m.themeSelector.Selected(); selected != "" && selected != ui.CurrentTheme().Name {
+					m.switchTheme(selected)
+				}
+			}
+			m.updateViewport()
+			return m, m.prompt.Focus()
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.themeSelector.SetWidth(msg.Width)
+	}
+
+	return m, nil
+}
+
+// switchTheme applies the named theme, keeping configured color overrides,
+// and saves it as the default.
+func (m *model) switchTheme(name string) {
+	t, err := ui.LoadTheme(name, viper.GetStringMapString("theme_colors"))
+	if err != nil {
+		m.addErrorf("%v", err)
+		return
+	}
+	applyTheme(t)
+	m.prompt.RefreshStyles()
+
+	if err := saveConfigValue("theme", t.Name); err != nil {
+		m.addSystem(fmt.Sprintf("Switched to %s theme (not saved: %v).", t.Name, err))
+		return
+	}
+	m.addSystem(fmt.Sprintf("Switched to %s theme.", t.Name))
+}
+
+// saveConfigValue writes key to the config file, keeping its other
+// settings. A fresh viper instance is used so env and flag values are not
+// written back.
+func saveConfigValue(key string, value any) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	v, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	v.Set(key, value)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	viper.Set(key, value)
+	return nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+// Context fill levels at which the REPL warns, once each per conversation.
+const (
+	contextWarnAt     = 0.80
+	contextCriticalAt = 0.95
+)
+
+// contextBarWidth is the number of cells in the /tokens bar.
+const contextBarWidth = 30
+
+// handleTokensCommand shows how much of the model's context window the
+// conversation uses.
+func (m model) handleTokensCommand() (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+
+	u := m.agent.ContextUsage()
+	used := groupDigits(u.Tokens) + " tokens"
+	if u.Estimated {
+		used = "~" + used + " (estimated)"
+	}
+	fill := "unknown context window for this model"
+	if u.Window > 0 {
+		fill = fmt.Sprintf("%s %.0f%%", contextBar(u.Fraction(), contextBarWidth), u.Fraction()*100)
+		used += " of " + groupDigits(u.Window)
+	}
+	session := m.agent.Usage()
+
+	kv := &agent.UIKV{
+		Title: "Context",
+		Items: []agent.KVItem{
+			{Key: "Model", Value: m.agent.CurrentModel()},
+			{Key: "Window", Value: fill},
+			{Key: "Used", Value: used},
+			{Key: "Session", Value: fmt.Sprintf("%s in / %s out", groupDigits(session.InputTokens), groupDigits(session.OutputTokens))},
+		},
+	}
+	text := renderKV(m.width-4, kv)
+	if u.Fraction() >= contextWarnAt {
+		text += "\n" + contextAdvice
+	}
+	m.addSystem(text)
+	m.updateViewport()
+	return m, nil
+}
+
+const contextAdvice = "Use /compact to summarize the conversation so far, /clear to start a fresh one, or /model to pick a model with a larger window."
+
+// compactedMsg carries the outcome of /compact.
+type compactedMsg struct {
+	summary string
+	err     error
+}
+
+// handleCompactCommand replaces the conversation with a summary, written
+// by the model routed for summaries, in the background.
+func (m model) handleCompactCommand() (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+	ag := m.agent
+	m.loading = true
+	m.addSystem("Summarizing the conversation...")
+	m.updateViewport()
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		summary, err := ag.Compact(ctx)
+		return compactedMsg{summary: summary, err: err}
+	}
+}
+
+func (m *model) handleCompacted(msg compactedMsg) {
+	m.loading = false
+	if msg.err != nil {
+		m.addErrorf("Compact failed: %v", msg.err)
+		return
+	}
+	m.contextWarned = 0
+	m.addSystem("Conversation compacted; the model continues from this summary:\n" + msg.summary)
+}
+
+// checkContext warns when a reply takes the conversation past a fill
+// level. Levels re-arm once the conversation shrinks, e.g. after /clear.
+func (m *model) checkContext() {
+	if m.agent == nil {
+		return
+	}
+	u := m.agent.ContextUsage()
+	frac := u.Fraction()
+	level := 0.0
+	switch {
+	case frac >= contextCriticalAt:
+		level = contextCriticalAt
+	case frac >= contextWarnAt:
+		level = contextWarnAt
+	}
+	if level <= m.contextWarned {
+		m.contextWarned = level
+		return
+	}
+	m.contextWarned = level
+	m.addWarning(fmt.Sprintf("Context is %.0f%% full (%s of %s tokens). %s",
+		frac*100, groupDigits(u.Tokens), groupDigits(u.Window), contextAdvice))
+}
+
+// contextBar draws a bar filled to frac, colored by how full it is.
+func contextBar(frac float64, width int) string {
+	filled := int(frac*float64(width) + 0.5)
+	filled = min(max(filled, 0), width)
+
+	color := ui.ColorSuccess
+	switch {
+	case frac >= contextCriticalAt:
+		color = ui.ColorError
+	case frac >= contextWarnAt:
+		color = ui.ColorWarning
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		ui.SelectorDim.Render(strings.Repeat("░", width-filled))
+}
+
+// groupDigits formats n with thousands separators, e.g. 12,345.
+func groupDigits(n int) string {
+	if n < 0 {
+		return "-" + groupDigits(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
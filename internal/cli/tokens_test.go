@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupDigits(t *testing.T) {
+	assert.Equal(t, "0", groupDigits(0))
+	assert.Equal(t, "999", groupDigits(999))
+	assert.Equal(t, "12,345", groupDigits(12345))
+	assert.Equal(t, "2,000,000", groupDigits(2000000))
+	assert.Equal(t, "-1,000", groupDigits(-1000))
+}
+
+func TestContextBar(t *testing.T) {
+	bar := ansi.Strip(contextBar(0.25, 20))
+	assert.Equal(t, strings.Repeat("█", 5)+strings.Repeat("░", 15), bar)
+	assert.Equal(t, strings.Repeat("█", 10), ansi.Strip(contextBar(1.4, 10)), "clamped when over the window")
+	assert.Equal(t, strings.Repeat("░", 10), ansi.Strip(contextBar(0, 10)))
+}
@@ -1,12 +1,33 @@
 package cli
 
 import (
-	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/ui"
 )
 
+// Blocks are drawn with plain box-drawing characters; colour comes from the
+// tool result style, which also marks copy targets inside cells.
+const (
+	boxH  = "─"
+	boxV  = "│"
+	boxTL = "╭"
+	boxTR = "╮"
+	boxBL = "╰"
+	boxBR = "╯"
+	boxT  = "┬"
+	boxB  = "┴"
+	boxL  = "├"
+	boxR  = "┤"
+	boxX  = "┼"
+)
+
+// minBlockWidth keeps blocks legible in very narrow terminals.
+const minBlockWidth = 20
+
 func renderBlocks(width int, blocks []agent.UIBlock) string {
 	if len(blocks) == 0 {
 		return ""
@@ -33,34 +54,79 @@ func renderBlocks(width int, blocks []agent.UIBlock) string {
 	return strings.TrimRight(b.String(), "\n")
 }
 
+// renderKV draws a titled panel of aligned key/value rows.
 func renderKV(width int, kv *agent.UIKV) string {
-	var b strings.Builder
-	if kv.Title != "" {
-		b.WriteString(kv.Title)
-		b.WriteString("\n")
+	if len(kv.Items) == 0 {
+		return ""
 	}
 	maxKey := 0
 	for _, it := range kv.Items {
-		if len(it.Key) > maxKey {
-			maxKey = len(it.Key)
-		}
-	}
-	if maxKey > 24 {
-		maxKey = 24
+		maxKey = max(maxKey, lipgloss.Width(it.Key))
 	}
+	maxKey = min(maxKey, 24)
 
+	// inner is the text width between "│ " and " │".
+	limit := max(width, minBlockWidth) - 4
+	inner := 0
+	if kv.Title != "" {
+		inner = lipgloss.Width(kv.Title) + 2
+	}
 	for _, it := range kv.Items {
-		key := it.Key
-		if len(key) > maxKey {
-			key = key[:maxKey]
+		inner = max(inner, maxKey+2+lipgloss.Width(it.Value))
+		if it.URL != "" {
+			inner = max(inner, maxKey+4+lipgloss.Width(it.URL))
 		}
-		line := fmt.Sprintf("%-*s  %s", maxKey, key, it.Value)
-		b.WriteString(truncate(line, width))
+	}
+	inner = min(inner, limit)
+
+	var b strings.Builder
+	b.WriteString(panelTop(kv.Title, inner))
+	for _, it := range kv.Items {
+		line := padRight(truncate(it.Key, maxKey), maxKey) + "  " + it.Value
 		b.WriteString("\n")
+		b.WriteString(panelRow(truncate(line, inner), inner))
+		if it.URL != "" {
+			b.WriteString("\n")
+			b.WriteString(renderLinkLine(inner, maxKey, it.URL))
+		}
 	}
-	return strings.TrimRight(b.String(), "\n")
+	b.WriteString("\n")
+	b.WriteString(boxBL + strings.Repeat(boxH, inner+2) + boxBR)
+	return b.String()
 }
 
+// panelTop draws the top border with the title set into it.
+func panelTop(title string, inner int) string {
+	if title == "" {
+		return boxTL + strings.Repeat(boxH, inner+2) + boxTR
+	}
+	title = truncate(title, inner-1)
+	return boxTL + boxH + " " + title + " " + strings.Repeat(boxH, max(inner-lipgloss.Width(title)-1, 0)) + boxTR
+}
+
+func panelRow(text string, inner int) string {
+	return boxV + " " + padRight(text, inner) + " " + boxV
+}
+
+// renderLinkLine renders a URL on its own indented panel row. In OSC-8
+// capable terminals the visible text is shortened to fit while the link
+// target stays complete; elsewhere the full URL is printed so the terminal
+// can detect it, leaving the row open on the right if it doesn't fit.
+func renderLinkLine(inner, indent int, url string) string {
+	prefix := strings.Repeat(" ", indent) + "  " + ui.SymbolArrow + " "
+	room := inner - lipgloss.Width(prefix)
+	if ui.SupportsHyperlinks() {
+		shown := truncate(url, room)
+		return boxV + " " + prefix + ui.Hyperlink(url, shown) + strings.Repeat(" ", max(room-lipgloss.Width(shown), 0)) + " " + boxV
+	}
+	if lipgloss.Width(url) > room {
+		return boxV + " " + prefix + url
+	}
+	return panelRow(prefix+url, inner)
+}
+
+// renderTable draws a bordered table, narrowing the widest columns until
+// it fits width.
 func renderTable(width int, t *agent.UITable) string {
 	cols := len(t.Headers)
 	if cols == 0 {
@@ -69,34 +135,26 @@ func renderTable(width int, t *agent.UITable) string {
 
 	colW := make([]int, cols)
 	for c := 0; c < cols; c++ {
-		colW[c] = len(t.Headers[c])
+		colW[c] = lipgloss.Width(t.Headers[c])
 	}
 	for _, row := range t.Rows {
 		for c := 0; c < cols && c < len(row); c++ {
-			if l := len(row[c]); l > colW[c] {
-				colW[c] = l
-			}
+			colW[c] = max(colW[c], lipgloss.Width(row[c]))
 		}
 	}
 
-	// Clamp to keep within width (best effort; shrink last columns first).
-	sep := 3 // " | "
-	avail := width
-	if avail < 20 {
-		avail = 20
-	}
-	for totalWidth(colW, sep) > avail {
-		shrunk := false
-		for c := cols - 1; c >= 0; c-- {
-			if colW[c] > 6 {
-				colW[c]--
-				shrunk = true
-				break
+	avail := max(width, minBlockWidth)
+	for totalWidth(colW) > avail {
+		widest := 0
+		for c := range colW {
+			if colW[c] > colW[widest] {
+				widest = c
 			}
 		}
-		if !shrunk {
+		if colW[widest] <= 4 {
 			break
 		}
+		colW[widest]--
 	}
 
 	var b strings.Builder
@@ -105,67 +163,71 @@ func renderTable(width int, t *agent.UITable) string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString(renderTableRow(t.Headers, colW, sep))
+	b.WriteString(renderTableRule(colW, boxTL, boxT, boxTR))
 	b.WriteString("\n")
-	b.WriteString(renderTableSep(colW, sep))
+	b.WriteString(renderTableRow(t.Headers, colW))
 	b.WriteString("\n")
-	for i, row := range t.Rows {
-		b.WriteString(renderTableRow(row, colW, sep))
-		if i < len(t.Rows)-1 {
-			b.WriteString("\n")
-		}
+	b.WriteString(renderTableRule(colW, boxL, boxX, boxR))
+	for _, row := range t.Rows {
+		b.WriteString("\n")
+		b.WriteString(renderTableRow(row, colW))
 	}
+	b.WriteString("\n")
+	b.WriteString(renderTableRule(colW, boxBL, boxB, boxBR))
 	return b.String()
 }
 
-func totalWidth(colW []int, sep int) int {
-	total := 0
+// totalWidth is the rendered table width: each cell is "│ " + text + " ",
+// plus the closing border.
+func totalWidth(colW []int) int {
+	total := 1
 	for _, w := range colW {
-		total += w
+		total += w + 3
 	}
-	total += sep * (len(colW) - 1)
 	return total
 }
 
-func renderTableSep(colW []int, sep int) string {
+func renderTableRule(colW []int, left, mid, right string) string {
 	var b strings.Builder
+	b.WriteString(left)
 	for c, w := range colW {
 		if c > 0 {
-			b.WriteString(strings.Repeat("-", sep))
+			b.WriteString(mid)
 		}
-		b.WriteString(strings.Repeat("-", w))
+		b.WriteString(strings.Repeat(boxH, w+2))
 	}
+	b.WriteString(right)
 	return b.String()
 }
 
-func renderTableRow(cells []string, colW []int, sep int) string {
+func renderTableRow(cells []string, colW []int) string {
 	var b strings.Builder
 	for c, w := range colW {
-		if c > 0 {
-			b.WriteString(" | ")
-		}
 		val := ""
 		if c < len(cells) {
 			val = cells[c]
 		}
+		b.WriteString(boxV + " ")
 		b.WriteString(padRight(truncate(val, w), w))
+		b.WriteString(" ")
 	}
+	b.WriteString(boxV)
 	return b.String()
 }
 
 func padRight(s string, w int) string {
-	if len(s) >= w {
-		return s
+	if n := lipgloss.Width(s); n < w {
+		return s + strings.Repeat(" ", w-n)
 	}
-	return s + strings.Repeat(" ", w-len(s))
+	return s
 }
 
 func truncate(s string, w int) string {
-	if w <= 0 || len(s) <= w {
+	if w <= 0 || lipgloss.Width(s) <= w {
 		return s
 	}
 	if w <= 3 {
-		return s[:w]
+		return ansi.Truncate(s, w, "")
 	}
-	return s[:w-3] + "..."
+	return ansi.Truncate(s, w, "...")
 }
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
+
+func TestRenderTable(t *testing.T) {
+	out := renderTable(80, &agent.UITable{
+		Title:   "Quotes",
+		Headers: []string{"#", "Route", "Out"},
+		Rows: [][]string{
+			{"1", "ETH → USDC", "3012.5"},
+			{"2", "ETH → WETH → USDC"}, // short row
+		},
+	})
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 7)
+	assert.Equal(t, "Quotes", lines[0])
+	assert.Equal(t, "╭───┬───────────────────┬────────╮", lines[1])
+	assert.Equal(t, "│ # │ Route             │ Out    │", lines[2])
+	assert.Equal(t, "├───┼───────────────────┼────────┤", lines[3])
+	assert.Equal(t, "│ 1 │ ETH → USDC        │ 3012.5 │", lines[4])
+	assert.Equal(t, "│ 2 │ ETH → WETH → USDC │        │", lines[5])
+	assert.Equal(t, "╰───┴───────────────────┴────────╯", lines[6])
+}
+
+func TestRenderTableNarrow(t *testing.T) {
+	out := renderTable(30, &agent.UITable{
+		Headers: []string{"Token", "Address"},
+		Rows:    [][]string{{"USDC", "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"}},
+	})
+	for _, line := range strings.Split(out, "\n") {
+		assert.Equal(t, 30, lipgloss.Width(line), line)
+	}
+	assert.Contains(t, out, "0xA0b86991c6218...")
+}
+
+func TestRenderKV(t *testing.T) {
+	t.Setenv("CLIFI_HYPERLINKS", "0")
+	out := renderKV(60, &agent.UIKV{
+		Title: "Transfer",
+		Items: []agent.KVItem{
+			{Key: "Status", Value: "confirmed"},
+			{Key: "Gas", Value: "21000"},
+		},
+	})
+	assert.Equal(t, strings.Join([]string{
+		"╭─ Transfer ────────╮",
+		"│ Status  confirmed │",
+		"│ Gas     21000     │",
+		"╰───────────────────╯",
+	}, "\n"), out)
+
+	// A URL too long for the panel is printed whole, open on the right.
+	url := "https://etherscan.io/tx/0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	out = renderKV(60, &agent.UIKV{Items: []agent.KVItem{{Key: "Tx", Value: "0x88df...944b", URL: url}}})
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, 60, lipgloss.Width(lines[0]))
+	assert.Equal(t, "│     "+ui.SymbolArrow+" "+url, lines[2])
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// Exit codes for tx status and tx wait, so scripts can branch without
+// parsing output.
+const (
+	exitReverted = 2 // mined but failed
+	exitPending  = 3 // not mined yet, or not enough confirmations in time
+	exitNotFound = 4 // the node doesn't know the hash
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx",
+	Short: "Inspect transactions",
+	Long: `Check on transactions by hash. Exit codes:
+
+  0  mined and succeeded
+  1  error (bad input, RPC failure)
+  2  mined but reverted
+  3  pending (or not confirmed before --timeout)
+  4  not found`,
+}
+
+var txStatusCmd = &cobra.Command{
+	Use:   "status <chain> <hash>",
+	Short: "Show a transaction's status",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTxStatus,
+}
+
+var txWaitCmd = &cobra.Command{
+	Use:     "wait <chain> <hash>",
+	Short:   "Wait for a transaction to confirm",
+	Example: `  clifi tx wait base 0x... --confirmations 3 && echo confirmed`,
+	Args:    cobra.ExactArgs(2),
+	RunE:    runTxWait,
+}
+
+func init() {
+	rootCmd.AddCommand(txCmd)
+	txCmd.AddCommand(txStatusCmd)
+	txCmd.AddCommand(txWaitCmd)
+
+	txWaitCmd.Flags().Uint64("confirmations", 1, "Blocks to wait for, counting the one that includes the tx")
+	txWaitCmd.Flags().Duration("timeout", 10*time.Minute, "Give up after this long")
+}
+
+// exitError carries a specific process exit code.
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string { return e.msg }
+
+// ExitCode maps an Execute error to the process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return 1
+}
+
+// txSource is the subset of chain.Client used to track a transaction.
+type txSource interface {
+	GetTransactionReceipt(ctx context.Context, chainName string, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, chainName string, txHash common.Hash) (*types.Transaction, bool, error)
+	BlockNumber(ctx context.Context, chainName string) (uint64, error)
+}
+
+type txState string
+
+const (
+	txSuccess  txState = "success"
+	txReverted txState = "reverted"
+	txPending  txState = "pending"
+	txNotFound txState = "not found"
+)
+
+type txReport struct {
+	state         txState
+	receipt       *types.Receipt
+	confirmations uint64
+}
+
+// lookupTx reports where a transaction stands. A missing receipt is told
+// apart from an unknown hash by asking for the transaction itself.
+func lookupTx(ctx context.Context, src txSource, chainName string, hash common.Hash) (txReport, error) {
+	receipt, err := src.GetTransactionReceipt(ctx, chainName, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		_, _, err := src.TransactionByHash(ctx, chainName, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return txReport{state: txNotFound}, nil
+		}
+		if err != nil {
+			return txReport{}, err
+		}
+		return txReport{state: txPending}, nil
+	}
+	if err != nil {
+		return txReport{}, err
+	}
+
+	r := txReport{state: txSuccess, receipt: receipt}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		r.state = txReverted
+	}
+	head, err := src.BlockNumber(ctx, chainName)
+	if err != nil {
+		return txReport{}, err
+	}
+	if receipt.BlockNumber != nil && head >= receipt.BlockNumber.Uint64() {
+		r.confirmations = head - receipt.BlockNumber.Uint64() + 1
+	}
+	return r, nil
+}
+
+// txPollInterval is how often tx wait polls; tests shorten it.
+var txPollInterval = 2 * time.Second
+
+// waitTx polls until the transaction has confirmations blocks or ctx ends,
+// returning the last report seen.
+func waitTx(ctx context.Context, src txSource, chainName string, hash common.Hash, confirmations uint64) (txReport, error) {
+	ticker := time.NewTicker(txPollInterval)
+	defer ticker.Stop()
+
+	var last txReport
+	for {
+		r, err := lookupTx(ctx, src, chainName, hash)
+		switch {
+		case err == nil:
+			last = r
+			if r.receipt != nil && r.confirmations >= confirmations {
+				return r, nil
+			}
+		case ctx.Err() != nil:
+			// The deadline cut the lookup short; report what we had.
+		default:
+			return r, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseTxArgs(args []string) (string, common.Hash, error) {
+	chainName := strings.ToLower(args[0])
+	hash := args[1]
+	if !strings.HasPrefix(hash, "0x") || len(hash) != 66 {
+		return "", common.Hash{}, fmt.Errorf("invalid tx hash %q", hash)
+	}
+	return chainName, common.HexToHash(hash), nil
+}
+
+func runTxStatus(cmd *cobra.Command, args []string) error {
+	chainName, hash, err := parseTxArgs(args)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, err := client.GetChainConfig(chainName); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+	r, err := lookupTx(ctx, client, chainName, hash)
+	if err != nil {
+		return err
+	}
+	printTxReport(cmd.OutOrStdout(), chainName, hash, r)
+	saveTxReceipt(chainName, r)
+	return txExitError(r, 0)
+}
+
+func runTxWait(cmd *cobra.Command, args []string) error {
+	chainName, hash, err := parseTxArgs(args)
+	if err != nil {
+		return err
+	}
+	confirmations, _ := cmd.Flags().GetUint64("confirmations")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if confirmations == 0 {
+		confirmations = 1
+	}
+	cmd.SilenceUsage = true
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, err := client.GetChainConfig(chainName); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+	r, err := waitTx(ctx, client, chainName, hash, confirmations)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	printTxReport(cmd.OutOrStdout(), chainName, hash, r)
+	saveTxReceipt(chainName, r)
+	return txExitError(r, confirmations)
+}
+
+// txExitError turns a report into the documented exit code. A mined tx
+// still short of the wanted confirmations counts as pending.
+func txExitError(r txReport, confirmations uint64) error {
+	switch {
+	case r.state == txReverted:
+		return &exitError{code: exitReverted, msg: "transaction reverted"}
+	case r.state == txNotFound:
+		return &exitError{code: exitNotFound, msg: "transaction not found"}
+	case r.state == txPending || r.state == "":
+		return &exitError{code: exitPending, msg: "transaction pending"}
+	case r.confirmations < confirmations:
+		return &exitError{code: exitPending, msg: fmt.Sprintf("only %d of %d confirmations", r.confirmations, confirmations)}
+	}
+	return nil
+}
+
+func printTxReport(w io.Writer, chainName string, hash common.Hash, r txReport) {
+	state := r.state
+	if state == "" {
+		state = txPending
+	}
+	fmt.Fprintf(w, "Chain:          %s\n", chainName)
+	fmt.Fprintf(w, "Tx:             %s\n", hash.Hex())
+	fmt.Fprintf(w, "Status:         %s\n", state)
+	if r.receipt == nil {
+		return
+	}
+	fmt.Fprintf(w, "Block:          %s\n", r.receipt.BlockNumber)
+	fmt.Fprintf(w, "Confirmations:  %d\n", r.confirmations)
+	fmt.Fprintf(w, "Gas used:       %d\n", r.receipt.GasUsed)
+}
+
+// saveTxReceipt records a mined receipt like the agent's receipt tools do.
+// It is best effort: the status output matters more than the history.
+func saveTxReceipt(chainName string, r txReport) {
+	if r.receipt == nil {
+		return
+	}
+	rs, err := agent.OpenReceiptStore(getDataDir())
+	if err != nil {
+		return
+	}
+	defer rs.Close()
+	_ = rs.Upsert(chainName, r.receipt)
+}
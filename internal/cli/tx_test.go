@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTxSource struct {
+	receipt *types.Receipt
+	known   bool
+	head    uint64
+}
+
+func (f *fakeTxSource) GetTransactionReceipt(context.Context, string, common.Hash) (*types.Receipt, error) {
+	if f.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return f.receipt, nil
+}
+
+func (f *fakeTxSource) TransactionByHash(context.Context, string, common.Hash) (*types.Transaction, bool, error) {
+	if !f.known {
+		return nil, false, ethereum.NotFound
+	}
+	return nil, true, nil
+}
+
+func (f *fakeTxSource) BlockNumber(context.Context, string) (uint64, error) {
+	f.head++
+	return f.head, nil
+}
+
+func TestLookupTx(t *testing.T) {
+	ctx := context.Background()
+
+	r, err := lookupTx(ctx, &fakeTxSource{}, "base", common.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, txNotFound, r.state)
+	assert.Equal(t, exitNotFound, ExitCode(txExitError(r, 0)))
+
+	r, err = lookupTx(ctx, &fakeTxSource{known: true}, "base", common.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, txPending, r.state)
+	assert.Equal(t, exitPending, ExitCode(txExitError(r, 0)))
+
+	src := &fakeTxSource{head: 101, receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)}}
+	r, err = lookupTx(ctx, src, "base", common.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, txSuccess, r.state)
+	assert.Equal(t, uint64(3), r.confirmations)
+	assert.Equal(t, 0, ExitCode(txExitError(r, 3)))
+	assert.Equal(t, exitPending, ExitCode(txExitError(r, 5)))
+
+	src.receipt.Status = types.ReceiptStatusFailed
+	r, err = lookupTx(ctx, src, "base", common.Hash{})
+	require.NoError(t, err)
+	assert.Equal(t, exitReverted, ExitCode(txExitError(r, 0)))
+
+	assert.Equal(t, 1, ExitCode(errors.New("rpc down")))
+}
+
+func TestWaitTx(t *testing.T) {
+	txPollInterval = time.Millisecond
+	t.Cleanup(func() { txPollInterval = 2 * time.Second })
+
+	// The head advances one block per lookup.
+	src := &fakeTxSource{head: 99, receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(100)}}
+	r, err := waitTx(context.Background(), src, "base", common.Hash{}, 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), r.confirmations)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r, err = waitTx(ctx, &fakeTxSource{known: true}, "base", common.Hash{}, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, exitPending, ExitCode(txExitError(r, 1)))
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show LLM tokens and estimated spend by provider and model",
+	Long: `Show the requests, tokens and estimated cost of a month's LLM usage per
+provider and model, from every session; this month unless --month is
+given. Costs come from clifi's model price list, so models it has no
+prices for count tokens only.
+
+This month's spend is also shown against the budget in
+llm.budget.monthly_usd. Once the budget is spent, requests to paid models
+are refused until the next month; free models such as Copilot's still
+work.`,
+	Example: `  clifi usage
+  clifi usage --month 2026-06 --json`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+var usageResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Clear this month's spend toward the budget, lifting a reached one",
+	Long: `Clear this month's spend toward llm.budget.monthly_usd, so paid models
+work again. The usage history that clifi usage lists is kept.`,
+	Args: cobra.NoArgs,
+	RunE: runUsageReset,
+}
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+	usageCmd.AddCommand(usageResetCmd)
+
+	usageCmd.Flags().String("month", "", "Month to report, e.g. 2026-06 (default this month)")
+	usageCmd.Flags().Bool("json", false, "Print JSON instead of a table")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	thisMonth := time.Now().Format(usageMonthLayout)
+	month, _ := cmd.Flags().GetString("month")
+	if month == "" {
+		month = thisMonth
+	}
+	if _, err := time.Parse(usageMonthLayout, month); err != nil {
+		return fmt.Errorf("invalid --month %q: expected YYYY-MM, e.g. 2026-06", month)
+	}
+	asJSON, _ := cmd.Flags().GetBool("json")
+	cmd.SilenceUsage = true
+
+	rs, err := agent.OpenReceiptStore(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+	rows, err := rs.LLMUsage(month)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	fmt.Fprintln(out, formatMonth(month))
+	if month == thisMonth {
+		s, err := agent.LoadSpend(getDataDir())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, budgetLine(s, viper.GetFloat64("llm.budget.monthly_usd")))
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(out, "No LLM requests recorded.")
+		return nil
+	}
+	printUsage(out, rows)
+	return nil
+}
+
+const usageMonthLayout = "2006-01"
+
+// formatMonth spells out a YYYY-MM month, e.g. "June 2026".
+func formatMonth(month string) string {
+	if t, err := time.Parse(usageMonthLayout, month); err == nil {
+		return t.Format("January 2006")
+	}
+	return month
+}
+
+// budgetLine shows this month's spend against the budget.
+func budgetLine(s agent.Spend, budget float64) string {
+	if budget <= 0 {
+		return fmt.Sprintf("Budget: none set (llm.budget.monthly_usd); $%.2f spent", s.CostUSD)
+	}
+	return fmt.Sprintf("Budget: $%.2f of $%.2f spent (%.0f%%)", s.CostUSD, budget, s.CostUSD/budget*100)
+}
+
+func printUsage(w io.Writer, rows []agent.ModelUsage) {
+	const format = "%-11s %-40s %8s %12s %12s %9s\n"
+	fmt.Fprintf(w, format, "PROVIDER", "MODEL", "REQUESTS", "IN", "OUT", "COST")
+	var total agent.ModelUsage
+	for _, r := range rows {
+		fmt.Fprintf(w, format, r.Provider, r.Model, strconv.Itoa(r.Calls), groupDigits(int(r.InputTokens)), groupDigits(int(r.OutputTokens)), fmt.Sprintf("$%.2f", r.CostUSD))
+		total.Calls += r.Calls
+		total.InputTokens += r.InputTokens
+		total.OutputTokens += r.OutputTokens
+		total.CostUSD += r.CostUSD
+	}
+	if len(rows) > 1 {
+		fmt.Fprintf(w, format, "TOTAL", "", strconv.Itoa(total.Calls), groupDigits(int(total.InputTokens)), groupDigits(int(total.OutputTokens)), fmt.Sprintf("$%.2f", total.CostUSD))
+	}
+}
+
+// usageTable lays out a month's usage for the REPL.
+func usageTable(month string, rows []agent.ModelUsage) *agent.UITable {
+	t := &agent.UITable{
+		Title:   "LLM usage, " + formatMonth(month),
+		Headers: []string{"Provider", "Model", "Requests", "In", "Out", "Cost"},
+	}
+	for _, r := range rows {
+		t.Rows = append(t.Rows, []string{r.Provider, r.Model, strconv.Itoa(r.Calls), groupDigits(int(r.InputTokens)), groupDigits(int(r.OutputTokens)), fmt.Sprintf("$%.2f", r.CostUSD)})
+	}
+	return t
+}
+
+// handleUsageCommand shows this month's LLM usage across sessions and the
+// budget.
+func (m model) handleUsageCommand() (tea.Model, tea.Cmd) {
+	if m.agent == nil {
+		m.addError("Agent not initialized.")
+		m.updateViewport()
+		return m, nil
+	}
+
+	month := time.Now().Format(usageMonthLayout)
+	rows, err := m.agent.UsageHistory(month)
+	if err != nil {
+		m.addErrorf("Failed to load usage: %v", err)
+		m.updateViewport()
+		return m, nil
+	}
+	spend, err := m.agent.Spend()
+	if err != nil {
+		m.addErrorf("Failed to load usage: %v", err)
+		m.updateViewport()
+		return m, nil
+	}
+
+	session := m.agent.Usage()
+	text := fmt.Sprintf("Session: %s in / %s out\n%s", groupDigits(session.InputTokens), groupDigits(session.OutputTokens), budgetLine(spend, m.agent.Budget().MonthlyUSD))
+	if len(rows) > 0 {
+		text = renderTable(m.width-4, usageTable(month, rows)) + "\n" + text
+	}
+	m.addSystem(text)
+	m.updateViewport()
+	return m, nil
+}
+
+func runUsageReset(cmd *cobra.Command, args []string) error {
+	if err := agent.ResetSpend(getDataDir()); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Cleared this month's spend toward the budget.")
+	return nil
+}
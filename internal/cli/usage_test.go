@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+func TestBudgetLine(t *testing.T) {
+	s := agent.Spend{Month: "2026-10", CostUSD: 4.5}
+	assert.Equal(t, "Budget: $4.50 of $20.00 spent (22%)", budgetLine(s, 20))
+	assert.Equal(t, "Budget: none set (llm.budget.monthly_usd); $4.50 spent", budgetLine(s, 0))
+	assert.Equal(t, "October 2026", formatMonth(s.Month))
+}
+
+func TestPrintUsage(t *testing.T) {
+	var buf bytes.Buffer
+	printUsage(&buf, []agent.ModelUsage{
+		{Provider: "anthropic", Model: "claude-sonnet-4-20250514", Calls: 12, InputTokens: 150000, OutputTokens: 4000, CostUSD: 0.51},
+		{Provider: "copilot", Model: "gpt-4o", Calls: 3, InputTokens: 9000, OutputTokens: 700},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "claude-sonnet-4-20250514")
+	assert.Contains(t, out, "150,000")
+	assert.Regexp(t, `TOTAL\s+15\s+159,000\s+4,700\s+\$0\.51`, out)
+}
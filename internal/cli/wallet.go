@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/yolodolo42/clifi/internal/wallet"
@@ -21,7 +23,19 @@ var walletCmd = &cobra.Command{
 var walletCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new wallet",
-	RunE:  runWalletCreate,
+	Long: `Create a new wallet with a random key, or with --mnemonic an HD wallet
+from a new BIP-39 seed phrase. The phrase is shown once and some of its
+words asked back before the wallet is written.
+
+--shares splits the phrase into shares instead, any T of N of which
+recover it with 'clifi wallet recover'; fewer reveal nothing. Each share
+is shown on its own and checked the same way. The phrase itself is never
+shown.`,
+	Example: `  clifi wallet create
+  clifi wallet create --mnemonic --words 24
+  clifi wallet create --shares 2-of-3`,
+	Args: cobra.NoArgs,
+	RunE: runWalletCreate,
 }
 
 var walletImportCmd = &cobra.Command{
@@ -36,16 +50,72 @@ var walletListCmd = &cobra.Command{
 	RunE:  runWalletList,
 }
 
+var walletPasswdCmd = &cobra.Command{
+	Use:   "passwd <address>",
+	Short: "Change a wallet's password",
+	Long: `Change the password of a wallet, given by address, label or index as in
+'clifi wallet list'. The keystore file is re-encrypted with the new
+password and replaced in one step, so an interruption never leaves it
+half-written.
+
+Update --wallet-password-file and the like for clifi serve afterwards.`,
+	Example: `  clifi wallet passwd 0x742d35Cc6634C0532925a3b844Bc454e4438f44e
+  clifi wallet passwd trading`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWalletPasswd,
+}
+
+var walletBackupCmd = &cobra.Command{
+	Use:   "backup --out <file>",
+	Short: "Write an encrypted backup of all wallets",
+	Long: `Write the keystore files of every wallet (EVM, Solana and Cosmos) and
+their labels to one age-encrypted archive, for 'clifi wallet restore' on
+another machine. The archive is encrypted with a backup passphrase; the
+key files inside stay encrypted with their own passwords, which restoring
+still needs.
+
+The file can also be opened with age -d. An external keystore
+(wallet.keystore_dir) is included in read-write mode, and restored to
+wherever new wallets go; in read-only mode it is not, since clifi doesn't
+own it.`,
+	Example: `  clifi wallet backup --out clifi-wallets.age`,
+	Args:    cobra.NoArgs,
+	RunE:    runWalletBackup,
+}
+
+var walletRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore wallets from a backup",
+	Long: `Add the wallets in a 'clifi wallet backup' archive to this machine.
+Wallets already here are kept as they are, and so are their labels and
+the default wallet; the backup only fills in what is missing.`,
+	Example: `  clifi wallet restore clifi-wallets.age`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runWalletRestore,
+}
+
 func init() {
 	rootCmd.AddCommand(walletCmd)
 	walletCmd.AddCommand(walletCreateCmd)
 	walletCmd.AddCommand(walletImportCmd)
 	walletCmd.AddCommand(walletListCmd)
+	walletCmd.AddCommand(walletPasswdCmd)
+	walletCmd.AddCommand(walletBackupCmd)
+	walletCmd.AddCommand(walletRestoreCmd)
 
 	walletImportCmd.Flags().String("key", "", "Private key to import (hex, with or without 0x prefix)")
+	walletBackupCmd.Flags().String("out", "", "File to write the backup to (must not exist)")
+	_ = walletBackupCmd.MarkFlagRequired("out")
 }
 
+// dataDirOverride replaces ~/.clifi while it is set, so the demo never
+// touches the user's wallets or credentials.
+var dataDirOverride string
+
 func getDataDir() string {
+	if dataDirOverride != "" {
+		return dataDirOverride
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ".clifi"
@@ -64,6 +134,18 @@ func readPassword(prompt string) (string, error) {
 }
 
 func runWalletCreate(cmd *cobra.Command, args []string) error {
+	seed, _ := cmd.Flags().GetBool("mnemonic")
+	if spec, _ := cmd.Flags().GetString("shares"); spec != "" {
+		if _, _, err := parseShareSpec(spec); err != nil {
+			return err
+		}
+		seed = true
+	}
+	if words, _ := cmd.Flags().GetInt("words"); words != 12 && words != 24 {
+		return fmt.Errorf("--words must be 12 or 24")
+	}
+	cmd.SilenceUsage = true
+
 	dataDir := getDataDir()
 	km, err := wallet.NewKeystoreManager(dataDir)
 	if err != nil {
@@ -88,7 +170,12 @@ func runWalletCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("passwords do not match")
 	}
 
-	account, err := km.CreateAccount(password)
+	var account accounts.Account
+	if seed {
+		account, err = newSeedWallet(cmd, km, password)
+	} else {
+		account, err = km.CreateAccount(password)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create account: %w", err)
 	}
@@ -96,6 +183,7 @@ func runWalletCreate(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nWallet created successfully!")
 	fmt.Printf("Address: %s\n", account.Address.Hex())
 	fmt.Printf("Keystore: %s\n", account.URL.Path)
+	printWeakScrypt()
 	fmt.Println("\nIMPORTANT: Back up your keystore file and remember your password!")
 
 	return nil
@@ -147,6 +235,7 @@ func runWalletImport(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nWallet imported successfully!")
 	fmt.Printf("Address: %s\n", account.Address.Hex())
 	fmt.Printf("Keystore: %s\n", account.URL.Path)
+	printWeakScrypt()
 
 	return nil
 }
@@ -166,16 +255,157 @@ func runWalletList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	def, _ := km.DefaultAccount()
 	fmt.Printf("Found %d wallet(s):\n\n", len(accounts))
 	for i, acc := range accounts {
-		fmt.Printf("%d. %s\n", i+1, acc.Address.Hex())
+		line := fmt.Sprintf("%d. %s", i+1, acc.Address.Hex())
+		if label := km.Label(acc.Address); label != "" {
+			line += " (" + label + ")"
+		}
+		if acc.Address == def.Address {
+			line += " [default]"
+		}
+		if scrypt, err := km.Scrypt(acc.Address); err == nil && scrypt.Weak() {
+			line += " [" + scrypt.String() + " scrypt]"
+		}
+		if km.ReadOnly(acc.Address) {
+			line += " [read-only]"
+		}
+		if km.IsEnvSigner(acc.Address) {
+			line += " [environment signer: testnets only, unsafe for real funds]"
+		}
+		if provider := km.KMSProvider(acc.Address); provider != "" {
+			line += " [" + provider + "]"
+		}
+		fmt.Println(line)
 	}
 
 	return nil
 }
 
+func runWalletPasswd(cmd *cobra.Command, args []string) error {
+	address, err := resolveAddress(args[0])
+	if err != nil {
+		return err
+	}
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize keystore: %w", err)
+	}
+	if !km.HasAccount(address) {
+		return fmt.Errorf("no wallet %s in %s", address.Hex(), getDataDir())
+	}
+	cmd.SilenceUsage = true
+
+	old, err := readPassword("Current password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	password, err := readPassword("New password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+	confirm, err := readPassword("Confirm new password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password confirmation: %w", err)
+	}
+	if password != confirm {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	if err := km.ChangePassword(address, old, password); err != nil {
+		return err
+	}
+	fmt.Printf("Password changed for %s.\n", address.Hex())
+	return nil
+}
+
+func runWalletBackup(cmd *cobra.Command, args []string) error {
+	out, _ := cmd.Flags().GetString("out")
+	cmd.SilenceUsage = true
+
+	passphrase, err := readPassword("Backup passphrase: ")
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) < 8 {
+		return fmt.Errorf("passphrase must be at least 8 characters")
+	}
+	confirm, err := readPassword("Confirm backup passphrase: ")
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase confirmation: %w", err)
+	}
+	if passphrase != confirm {
+		return fmt.Errorf("passphrases do not match")
+	}
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	manifest, err := wallet.Backup(getDataDir(), f, passphrase)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(out)
+		return err
+	}
+	fmt.Printf("Backed up %d key file(s) to %s:\n", manifest.Keys(), out)
+	for _, dir := range []string{"keystore", "solana", "cosmos", "external"} {
+		if n := len(manifest.Files[dir]); n > 0 {
+			fmt.Printf("  %-9s %d\n", dir, n)
+		}
+	}
+	if ext, ok, err := wallet.ConfiguredExternalKeystore(); err == nil && ok && ext.ReadOnly {
+		fmt.Printf("\nNot included: the read-only external keystore %s (wallet.keystore_dir). Back it up separately.\n", ext.Dir)
+	}
+	fmt.Println("\nKeep the passphrase apart from the file: restoring needs it and the wallet passwords.")
+	return nil
+}
+
+func runWalletRestore(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cmd.SilenceUsage = true
+
+	passphrase, err := readPassword("Backup passphrase: ")
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	res, err := wallet.Restore(getDataDir(), f, passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %d key file(s) from a backup taken %s.\n", len(res.Restored), res.Manifest.CreatedAt.Local().Format(time.DateTime))
+	for _, name := range res.Restored {
+		fmt.Printf("  %s\n", name)
+	}
+	if len(res.Skipped) > 0 {
+		fmt.Printf("Skipped %d already here.\n", len(res.Skipped))
+	}
+	if res.Labels > 0 {
+		fmt.Printf("Added %d label(s).\n", res.Labels)
+	}
+	return nil
+}
+
+// printWeakScrypt warns that a wallet just written is encrypted with a
+// cheaper key derivation than the default.
+func printWeakScrypt() {
+	if scrypt := wallet.ConfiguredScrypt(); scrypt.Weak() {
+		fmt.Printf("Encrypted with %s scrypt (wallet.scrypt): fine for test wallets, too weak for real funds.\n", scrypt)
+	}
+}
+
 // GetSigner returns a signer for the specified address
-func GetSigner(addressHex string, password string) (*wallet.KeystoreSigner, error) {
+func GetSigner(addressHex string, password string) (wallet.Signer, error) {
 	dataDir := getDataDir()
 	km, err := wallet.NewKeystoreManager(dataDir)
 	if err != nil {
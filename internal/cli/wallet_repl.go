@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/ui"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// minWalletPassword matches the `clifi wallet create` requirement.
+const minWalletPassword = 8
+
+// passwordPrompt collects a new wallet password, then its confirmation,
+// with input masked. With forPlan it asks once, for the wallet password
+// an approved plan signs with.
+type passwordPrompt struct {
+	input      textinput.Model
+	label      string // label for the new wallet, if given
+	first      string
+	confirming bool
+	forPlan    bool
+}
+
+func newPasswordPrompt(label string, width int) passwordPrompt {
+	ti := textinput.New()
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.Prompt = "Password for new wallet: "
+	ti.PromptStyle = ui.PromptStyle
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.Width = max(width-len(ti.Prompt)-2, 10)
+	ti.Focus()
+	return passwordPrompt{input: ti, label: label}
+}
+
+// walletCreatedMsg reports the result of the background key generation.
+type walletCreatedMsg struct {
+	account accounts.Account
+	label   string
+	err     error
+}
+
+// handleWalletCommand manages keystore wallets without leaving the REPL.
+//
+//	/wallet [list]              list wallets, labels and the default
+//	/wallet create [label]      create a wallet (password prompted)
+//	/wallet use <ref>           make a wallet the default
+//	/wallet label <ref> <name>  label a wallet
+//
+// <ref> is a label, an address or a number from /wallet list.
+func (m model) handleWalletCommand(arg string) (tea.Model, tea.Cmd) {
+	sub, rest, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	rest = strings.TrimSpace(rest)
+
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		m.addErrorf("Failed to load keystore: %v", err)
+		m.updateViewport()
+		return m, nil
+	}
+
+	switch strings.ToLower(sub) {
+	case "", "list", "ls":
+		m.addSystem(walletList(km, m.width-4))
+
+	case "create", "new":
+		if rest != "" {
+			if err := wallet.ValidateLabel(rest); err != nil {
+				m.addErrorf("%v", err)
+				break
+			}
+			if _, err := km.FindAccount(rest); err == nil {
+				m.addErrorf("A wallet is already labelled %q.", rest)
+				break
+			}
+		}
+		m.password = newPasswordPrompt(rest, m.width)
+		m.mode = modePassword
+		m.prompt.Blur()
+		return m, nil
+
+	case "use", "default":
+		if rest == "" {
+			m.addError("Usage: /wallet use <label|address|n>")
+			break
+		}
+		acc, err := km.FindAccount(rest)
+		if err == nil {
+			err = km.SetDefault(acc.Address)
+		}
+		if err != nil {
+			m.addErrorf("Failed to switch wallet: %v", err)
+			break
+		}
+		m.addSystem(fmt.Sprintf("Default wallet is now %s.", walletName(km, acc)))
+
+	case "label", "rename":
+		ref, label, _ := strings.Cut(rest, " ")
+		label = strings.TrimSpace(label)
+		if ref == "" || label == "" {
+			m.addError("Usage: /wallet label <label|address|n> <new-label>")
+			break
+		}
+		acc, err := km.FindAccount(ref)
+		if err == nil {
+			err = km.SetLabel(acc.Address, label)
+		}
+		if err != nil {
+			m.addErrorf("Failed to label wallet: %v", err)
+			break
+		}
+		m.addSystem(fmt.Sprintf("Labelled %s.", walletName(km, acc)))
+
+	default:
+		m.addError("Usage: /wallet [list|create [label]|use <ref>|label <ref> <name>]")
+	}
+
+	m.updateViewport()
+	return m, nil
+}
+
+// updatePassword handles keys while the masked password prompt is open.
+func (m model) updatePassword(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		if m.password.forPlan {
+			m.addSystem("Plan not run; /plan run to try again.")
+		} else {
+			m.addSystem("Wallet creation cancelled.")
+		}
+		m.password = passwordPrompt{}
+		m.mode = modeChat
+		m.updateViewport()
+		return m, m.prompt.Focus()
+
+	case msg.Type == tea.KeyEnter:
+		p := &m.password
+		value := p.input.Value()
+		p.input.Reset()
+		if p.forPlan {
+			m.password = passwordPrompt{}
+			m.mode = modeChat
+			return m.runPlan(value)
+		}
+		if !p.confirming {
+			if len(value) < minWalletPassword {
+				m.addErrorf("Password must be at least %d characters.", minWalletPassword)
+				m.updateViewport()
+				return m, nil
+			}
+			p.first = value
+			p.confirming = true
+			p.input.Prompt = "Confirm password: "
+			return m, nil
+		}
+		if value != p.first {
+			p.first, p.confirming = "", false
+			p.input.Prompt = "Password for new wallet: "
+			m.addError("Passwords do not match. Try again.")
+			m.updateViewport()
+			return m, nil
+		}
+
+		label := p.label
+		m.password = passwordPrompt{}
+		m.mode = modeChat
+		m.loading = true
+		m.addSystem("Creating wallet...")
+		m.updateViewport()
+		return m, tea.Batch(m.prompt.Focus(), createWallet(value, label))
+	}
+
+	var cmd tea.Cmd
+	m.password.input, cmd = m.password.input.Update(msg)
+	return m, cmd
+}
+
+// createWallet encrypts a new key in the background; scrypt takes a
+// second or so.
+func createWallet(password, label string) tea.Cmd {
+	return func() tea.Msg {
+		km, err := wallet.NewKeystoreManager(getDataDir())
+		if err != nil {
+			return walletCreatedMsg{err: err}
+		}
+		acc, err := km.CreateAccount(password)
+		if err != nil {
+			return walletCreatedMsg{err: err}
+		}
+		if label != "" {
+			if err := km.SetLabel(acc.Address, label); err != nil {
+				return walletCreatedMsg{account: acc, err: fmt.Errorf("created %s but could not label it: %w", acc.Address.Hex(), err)}
+			}
+		}
+		return walletCreatedMsg{account: acc, label: label}
+	}
+}
+
+// handleWalletCreated reports a finished /wallet create.
+func (m *model) handleWalletCreated(msg walletCreatedMsg) {
+	m.loading = false
+	if msg.err != nil {
+		m.addErrorf("Wallet creation failed: %v", msg.err)
+		return
+	}
+	name := msg.account.Address.Hex()
+	if msg.label != "" {
+		name = msg.label + " (" + name + ")"
+	}
+	m.addSystem(fmt.Sprintf("Created wallet %s.\nKeystore: %s\nBack up the keystore file and remember the password!", name, msg.account.URL.Path))
+}
+
+func walletName(km *wallet.KeystoreManager, acc accounts.Account) string {
+	if label := km.Label(acc.Address); label != "" {
+		return label + " (" + acc.Address.Hex() + ")"
+	}
+	return acc.Address.Hex()
+}
+
+func walletList(km *wallet.KeystoreManager, width int) string {
+	accs := km.ListAccounts()
+	if len(accs) == 0 {
+		return "No wallets yet. Create one with /wallet create [label]."
+	}
+	def, _ := km.DefaultAccount()
+	table := &agent.UITable{
+		Title:   fmt.Sprintf("Wallets (%d)", len(accs)),
+		Headers: []string{"#", "Label", "Address", "Default"},
+	}
+	for i, acc := range accs {
+		isDefault := ""
+		if acc.Address == def.Address {
+			isDefault = "yes"
+		}
+		table.Rows = append(table.Rows, []string{fmt.Sprintf("%d", i+1), km.Label(acc.Address), acc.Address.Hex(), isDefault})
+	}
+	return renderTable(width, table) + "\n/wallet use <label|address|n> to change the default."
+}
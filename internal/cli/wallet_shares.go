@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/wallet"
+	"golang.org/x/term"
+)
+
+var walletRecoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Recover a wallet from seed shares",
+	Long: `Rebuild a seed phrase from the shares 'clifi wallet create --shares'
+printed, and import its first account. Type one share per line; clifi
+asks for more until it has as many as the shares were split for.`,
+	Example: `  clifi wallet recover
+  clifi wallet recover --show`,
+	Args: cobra.NoArgs,
+	RunE: runWalletRecover,
+}
+
+func init() {
+	walletCmd.AddCommand(walletRecoverCmd)
+
+	walletCreateCmd.Flags().Bool("mnemonic", false, "Create an HD wallet from a new seed phrase")
+	walletCreateCmd.Flags().Int("words", 12, "Seed phrase length, 12 or 24 (with --mnemonic)")
+	walletCreateCmd.Flags().String("shares", "", "Split the seed phrase into shares, e.g. 2-of-3 (implies --mnemonic)")
+	walletRecoverCmd.Flags().Bool("show", false, "Also print the recovered seed phrase")
+}
+
+// shareCheckWords is how many words of each share are asked back.
+const shareCheckWords = 3
+
+// pickCheckWords chooses which of a share's n words to ask back.
+var pickCheckWords = func(n int) []int {
+	picked := rand.Perm(n)[:shareCheckWords]
+	slices.Sort(picked)
+	return picked
+}
+
+// parseShareSpec parses a --shares value, e.g. 2-of-3.
+func parseShareSpec(spec string) (threshold, n int, err error) {
+	t, total, ok := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "-of-")
+	if ok {
+		threshold, err = strconv.Atoi(t)
+		if err == nil {
+			n, err = strconv.Atoi(total)
+		}
+	}
+	if !ok || err != nil || threshold < 2 || threshold > n || n > wallet.MaxShares {
+		return 0, 0, fmt.Errorf("--shares must be T-of-N with 2 <= T <= N <= %d, e.g. 2-of-3; got %q", wallet.MaxShares, spec)
+	}
+	return threshold, n, nil
+}
+
+// newSeedWallet creates an HD wallet from a new seed phrase. The phrase,
+// or its shares, is handed out and checked before the wallet is written,
+// so a wallet is never created without a backup of its seed.
+func newSeedWallet(cmd *cobra.Command, km *wallet.KeystoreManager, password string) (accounts.Account, error) {
+	words, _ := cmd.Flags().GetInt("words")
+	spec, _ := cmd.Flags().GetString("shares")
+	mnemonic, err := wallet.NewMnemonic(words)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	clearScreen := term.IsTerminal(int(os.Stdout.Fd()))
+	if spec == "" {
+		err = handOutShares(in, os.Stdout, []string{mnemonic}, "Seed phrase", clearScreen)
+	} else {
+		threshold, n, _ := parseShareSpec(spec)
+		var shares []string
+		if shares, err = wallet.SplitMnemonic(mnemonic, threshold, n); err != nil {
+			return accounts.Account{}, err
+		}
+		fmt.Printf("\nThe seed phrase is split into %d shares; any %d of them recover it.\n", n, threshold)
+		fmt.Println("Write each down and keep them in different places.")
+		err = handOutShares(in, os.Stdout, shares, fmt.Sprintf("Share (%d-of-%d)", threshold, n), clearScreen)
+	}
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return km.ImportMnemonic(mnemonic, accounts.DefaultBaseDerivationPath, password)
+}
+
+// handOutShares shows each share on its own and has the user type back
+// some of its words before moving on, showing it again on a mismatch.
+// When clearScreen is set the screen is cleared after each, so no two
+// shares are on it at once.
+func handOutShares(in *bufio.Reader, out io.Writer, shares []string, title string, clearScreen bool) error {
+	for i, share := range shares {
+		words := strings.Fields(share)
+		for {
+			name := title
+			if len(shares) > 1 {
+				name = fmt.Sprintf("%s #%d", title, i+1)
+			}
+			fmt.Fprintf(out, "\n%s:\n\n", name)
+			for j, w := range words {
+				fmt.Fprintf(out, "%3d. %-10s", j+1, w)
+				if j%6 == 5 || j == len(words)-1 {
+					fmt.Fprintln(out)
+				}
+			}
+			fmt.Fprint(out, "\nWrite it down, then press Enter.")
+			if _, err := readLine(in); err != nil {
+				return err
+			}
+			if clearScreen {
+				fmt.Fprint(out, "\033[H\033[2J")
+			}
+
+			ok := true
+			for _, j := range pickCheckWords(len(words)) {
+				fmt.Fprintf(out, "%s, word %d: ", name, j+1)
+				answer, err := readLine(in)
+				if err != nil {
+					return err
+				}
+				if strings.ToLower(answer) != words[j] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				break
+			}
+			fmt.Fprintln(out, "That doesn't match; here it is again.")
+		}
+	}
+	return nil
+}
+
+// readLine reads a trimmed line; running out of input aborts.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", fmt.Errorf("aborted: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readShares reads shares a line at a time until it has a threshold of
+// one set. Lines that don't parse, or belong to another set, are
+// reported and asked for again.
+func readShares(in *bufio.Reader, out io.Writer) ([]wallet.Share, error) {
+	var shares []wallet.Share
+	for len(shares) == 0 || len(shares) < shares[0].Threshold {
+		if len(shares) == 0 {
+			fmt.Fprint(out, "Share: ")
+		} else {
+			fmt.Fprintf(out, "Share (%d of %d): ", len(shares)+1, shares[0].Threshold)
+		}
+		line, err := readLine(in)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			continue
+		}
+		s, err := wallet.ParseShare(line)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+		if len(shares) > 0 {
+			first := shares[0]
+			if s.SetID != first.SetID || s.Threshold != first.Threshold {
+				fmt.Fprintln(out, "That share is from another set.")
+				continue
+			}
+			if slices.ContainsFunc(shares, func(have wallet.Share) bool { return have.Index == s.Index }) {
+				fmt.Fprintf(out, "Already have share #%d.\n", s.Index)
+				continue
+			}
+		}
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+func runWalletRecover(cmd *cobra.Command, args []string) error {
+	show, _ := cmd.Flags().GetBool("show")
+	km, err := wallet.NewKeystoreManager(getDataDir())
+	if err != nil {
+		return fmt.Errorf("failed to initialize keystore: %w", err)
+	}
+	cmd.SilenceUsage = true
+
+	shares, err := readShares(bufio.NewReader(os.Stdin), os.Stdout)
+	if err != nil {
+		return err
+	}
+	mnemonic, err := wallet.CombineShares(shares)
+	if err != nil {
+		return err
+	}
+
+	password, err := readPassword("Enter password to encrypt wallet: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password: %w", err)
+	}
+	if len(password) < 8 {
+		return fmt.Errorf("password must be at least 8 characters")
+	}
+	confirm, err := readPassword("Confirm password: ")
+	if err != nil {
+		return fmt.Errorf("failed to read password confirmation: %w", err)
+	}
+	if password != confirm {
+		return fmt.Errorf("passwords do not match")
+	}
+
+	account, err := km.ImportMnemonic(mnemonic, accounts.DefaultBaseDerivationPath, password)
+	if err != nil {
+		return fmt.Errorf("failed to import recovered seed: %w", err)
+	}
+	fmt.Println("\nWallet recovered successfully!")
+	fmt.Printf("Address: %s\n", account.Address.Hex())
+	fmt.Printf("Keystore: %s\n", account.URL.Path)
+	printWeakScrypt()
+	if show {
+		fmt.Printf("Seed phrase: %s\n", mnemonic)
+	}
+	return nil
+}
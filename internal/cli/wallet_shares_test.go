@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+func TestParseShareSpec(t *testing.T) {
+	threshold, n, err := parseShareSpec("2-of-3")
+	require.NoError(t, err)
+	assert.Equal(t, 2, threshold)
+	assert.Equal(t, 3, n)
+
+	for _, bad := range []string{"2", "1-of-3", "4-of-3", "2-of-16", "two-of-three"} {
+		_, _, err := parseShareSpec(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestHandOutSharesAsksAgainOnMismatch(t *testing.T) {
+	old := pickCheckWords
+	pickCheckWords = func(int) []int { return []int{0, 2} }
+	t.Cleanup(func() { pickCheckWords = old })
+
+	shares := []string{"apple banana cherry", "delta echo fox"}
+	input := strings.Join([]string{
+		"", "apple", "wrong", // first try fails on word 3
+		"", "APPLE", "cherry",
+		"", "delta", "fox",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	err := handOutShares(bufio.NewReader(strings.NewReader(input)), &out, shares, "Share", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(out.String(), "Share #1:\n"))
+	assert.Equal(t, 1, strings.Count(out.String(), "Share #2:\n"))
+
+	err = handOutShares(bufio.NewReader(strings.NewReader("\napple\n")), &out, shares, "Share", false)
+	assert.ErrorContains(t, err, "aborted")
+}
+
+func TestReadShares(t *testing.T) {
+	mnemonic, err := wallet.NewMnemonic(12)
+	require.NoError(t, err)
+	shares, err := wallet.SplitMnemonic(mnemonic, 2, 3)
+	require.NoError(t, err)
+	other, err := wallet.SplitMnemonic(mnemonic, 2, 3)
+	require.NoError(t, err)
+
+	input := strings.Join([]string{shares[2], "not a share", shares[2], other[0], shares[0]}, "\n") + "\n"
+	var out bytes.Buffer
+	got, err := readShares(bufio.NewReader(strings.NewReader(input)), &out)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Contains(t, out.String(), "Already have share #3")
+
+	recovered, err := wallet.CombineShares(got)
+	require.NoError(t, err)
+	assert.Equal(t, mnemonic, recovered)
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/wallet"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Price and balance alerts",
+	Long: `Manage watches: conditions on token prices or wallet balances that
+'clifi serve' checks in the background, notifying when one starts to hold.
+
+Conditions:
+  ETH > 4000                 USD price (priced on ethereum)
+  ARB@arbitrum < 0.5         USD price of a token on another chain
+  balance base < 0.1         native balance of the default wallet
+  balance base hot < 0.1     native balance of a labelled wallet`,
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <condition>",
+	Short: "Add a watch",
+	Example: `  clifi watch add "ETH > 4000"
+  clifi watch add "balance base < 0.05" --once`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWatchAdd,
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List watches and their last values",
+	RunE:  runWatchList,
+}
+
+var watchRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a watch",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWatchRm,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchAddCmd)
+	watchCmd.AddCommand(watchListCmd)
+	watchCmd.AddCommand(watchRmCmd)
+
+	watchAddCmd.Flags().Bool("once", false, "Remove the watch after it fires")
+}
+
+func runWatchAdd(cmd *cobra.Command, args []string) error {
+	once, _ := cmd.Flags().GetBool("once")
+	// Unquoted conditions arrive as several args.
+	cond, err := watch.ParseCondition(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	client, err := chain.NewConfiguredClient(getDataDir())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if _, err := client.GetChainConfig(cond.Chain); err != nil {
+		return err
+	}
+	if cond.Kind == watch.KindBalance {
+		km, err := wallet.NewKeystoreManager(getDataDir())
+		if err != nil {
+			return fmt.Errorf("failed to load wallets: %w", err)
+		}
+		if err := cond.ResolveWallet(km); err != nil {
+			return err
+		}
+	}
+
+	w, err := watch.NewStore(getDataDir()).Add(cond, once)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added watch #%d: %s\n", w.ID, w.Condition)
+	fmt.Println("Run 'clifi serve' to check watches and get notified.")
+	return nil
+}
+
+func runWatchList(cmd *cobra.Command, args []string) error {
+	watches, err := watch.NewStore(getDataDir()).List()
+	if err != nil {
+		return err
+	}
+	if len(watches) == 0 {
+		fmt.Println("No watches. Add one with: clifi watch add \"ETH > 4000\"")
+		return nil
+	}
+	for _, w := range watches {
+		line := fmt.Sprintf("#%-3d %s", w.ID, w.Condition)
+		if w.Once {
+			line += " (once)"
+		}
+		switch {
+		case w.LastError != "":
+			line += "  error: " + w.LastError
+		case !w.LastChecked.IsZero():
+			line += fmt.Sprintf("  last %g at %s", w.LastValue, w.LastChecked.Local().Format(time.DateTime))
+		default:
+			line += "  not checked yet"
+		}
+		if w.Triggered {
+			line += "  [triggered]"
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runWatchRm(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return fmt.Errorf("invalid watch ID: %s", args[0])
+	}
+	cmd.SilenceUsage = true
+	ok, err := watch.NewStore(getDataDir()).Remove(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no watch #%d", id)
+	}
+	fmt.Printf("Removed watch #%d\n", id)
+	return nil
+}
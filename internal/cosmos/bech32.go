@@ -0,0 +1,119 @@
+package cosmos
+
+import (
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Gen = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range 5 {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups a byte slice from one bit width to another, as used
+// to move between 8-bit data and 5-bit bech32 words.
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  []byte
+	)
+	maxv := uint32(1)<<to - 1
+	for _, b := range data {
+		if uint32(b)>>from != 0 {
+			return nil, fmt.Errorf("invalid data for %d-bit conversion", from)
+		}
+		acc = acc<<from | uint32(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return out, nil
+}
+
+// EncodeBech32 encodes data (e.g. a 20-byte account address) with the
+// human-readable prefix hrp, e.g. "cosmos" or "osmo".
+func EncodeBech32(hrp string, data []byte) (string, error) {
+	words, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	values := append(bech32HRPExpand(hrp), words...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, w := range words {
+		sb.WriteByte(bech32Charset[w])
+	}
+	for i := range 6 {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+// DecodeBech32 returns the prefix and data bytes of a bech32 string.
+func DecodeBech32(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("invalid bech32 %q: mixed case", s)
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, fmt.Errorf("invalid bech32 %q", s)
+	}
+
+	hrp := s[:sep]
+	words := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		idx := strings.IndexByte(bech32Charset, s[i])
+		if idx < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 %q: bad character %q", s, s[i])
+		}
+		words = append(words, byte(idx))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), words...)) != 1 {
+		return "", nil, fmt.Errorf("invalid bech32 %q: bad checksum", s)
+	}
+
+	data, err := convertBits(words[:len(words)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid bech32 %q: %w", s, err)
+	}
+	return hrp, data, nil
+}
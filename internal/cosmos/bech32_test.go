@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBech32(t *testing.T) {
+	t.Run("decodes BIP-173 vector", func(t *testing.T) {
+		hrp, data, err := DecodeBech32("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+		require.NoError(t, err)
+		assert.Equal(t, "abcdef", hrp)
+		assert.Len(t, data, 20)
+	})
+
+	t.Run("round trips", func(t *testing.T) {
+		raw := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}
+		addr, err := EncodeBech32("cosmos", raw)
+		require.NoError(t, err)
+
+		hrp, data, err := DecodeBech32(addr)
+		require.NoError(t, err)
+		assert.Equal(t, "cosmos", hrp)
+		assert.Equal(t, raw, data)
+	})
+
+	t.Run("rejects bad checksum", func(t *testing.T) {
+		_, _, err := DecodeBech32("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx")
+		assert.ErrorContains(t, err, "checksum")
+	})
+
+	t.Run("rejects mixed case", func(t *testing.T) {
+		_, _, err := DecodeBech32("Abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+		assert.Error(t, err)
+	})
+}
+
+func TestChainConfig_ParseAddress(t *testing.T) {
+	chains := DefaultChains()
+	raw := make([]byte, 20)
+	osmo := chains["osmosis"].Address(raw)
+
+	got, err := chains["osmosis"].ParseAddress(osmo)
+	require.NoError(t, err)
+	assert.Equal(t, raw, got)
+
+	_, err = chains["cosmoshub"].ParseAddress(osmo)
+	assert.ErrorContains(t, err, "expected prefix")
+
+	assert.Equal(t, "https://www.mintscan.io/osmosis/tx/ABC", chains["osmosis"].TxURL("ABC"))
+}
@@ -0,0 +1,406 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when the node reports that a resource (account,
+// transaction, denom trace) does not exist.
+var ErrNotFound = errors.New("not found")
+
+// APIError is an error response from a chain's REST API.
+type APIError struct {
+	Status  int
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("cosmos api error %d (code %d): %s", e.Status, e.Code, e.Message)
+}
+
+// Client talks to Cosmos SDK chains over their REST (LCD) API.
+type Client struct {
+	httpClient *http.Client
+
+	mu     sync.RWMutex
+	chains map[string]*ChainConfig
+	// denoms caches resolved IBC denom traces, which are immutable.
+	denoms map[string]DenomTrace
+}
+
+// NewClient creates a new client with the default chains.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		chains:     DefaultChains(),
+		denoms:     make(map[string]DenomTrace),
+	}
+}
+
+// AddChain adds or replaces a chain configuration.
+func (c *Client) AddChain(name string, config *ChainConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chains[name] = config
+}
+
+// GetChainConfig returns the configuration for a chain.
+func (c *Client) GetChainConfig(chainName string) (*ChainConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	config, ok := c.chains[chainName]
+	if !ok {
+		return nil, fmt.Errorf("unknown cosmos chain: %s", chainName)
+	}
+	return config, nil
+}
+
+// ListChains returns all configured chain names, sorted.
+func (c *Client) ListChains() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.chains))
+	for name := range c.chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// do sends a request to each REST URL in order until one answers. API error
+// responses are final; only transport failures and 5xx move on to the next
+// endpoint.
+func (c *Client) do(ctx context.Context, chainName, method, path string, body any, out any) error {
+	config, err := c.GetChainConfig(chainName)
+	if err != nil {
+		return err
+	}
+	if len(config.RESTURLs) == 0 {
+		return fmt.Errorf("no REST URLs configured for %s", chainName)
+	}
+
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	var lastErr error
+	for _, base := range config.RESTURLs {
+		err := c.request(ctx, method, strings.TrimRight(base, "/")+path, payload, out)
+		if err == nil {
+			return nil
+		}
+		var apiErr *APIError
+		if errors.Is(err, ErrNotFound) || (errors.As(err, &apiErr) && apiErr.Status < 500) || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("%s %s failed on all endpoints for %s: %w", method, path, chainName, lastErr)
+}
+
+func (c *Client) request(ctx context.Context, method, endpoint string, payload []byte, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Status: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(apiErr)
+		if resp.StatusCode == http.StatusNotFound || strings.Contains(apiErr.Message, "not found") {
+			return fmt.Errorf("%w: %s", ErrNotFound, apiErr.Message)
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// Balance is a bank balance. For IBC vouchers Trace describes the original
+// asset.
+type Balance struct {
+	Denom  string      `json:"denom"`
+	Amount *big.Int    `json:"amount"`
+	Trace  *DenomTrace `json:"trace,omitempty"`
+}
+
+// GetBalances returns all bank balances of address, with IBC denoms
+// resolved to their base denom where the chain can resolve them.
+func (c *Client) GetBalances(ctx context.Context, chainName, address string) ([]Balance, error) {
+	var res struct {
+		Balances []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balances"`
+	}
+	path := "/cosmos/bank/v1beta1/balances/" + url.PathEscape(address) + "?pagination.limit=200"
+	if err := c.do(ctx, chainName, http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, 0, len(res.Balances))
+	for _, b := range res.Balances {
+		amount, ok := new(big.Int).SetString(b.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %q for %s", b.Amount, b.Denom)
+		}
+		bal := Balance{Denom: b.Denom, Amount: amount}
+		if strings.HasPrefix(b.Denom, "ibc/") {
+			// An unresolvable trace still leaves a usable balance.
+			if trace, err := c.ResolveDenom(ctx, chainName, b.Denom); err == nil {
+				bal.Trace = &trace
+			}
+		}
+		balances = append(balances, bal)
+	}
+	return balances, nil
+}
+
+// GetBalance returns the bank balance of a single denom.
+func (c *Client) GetBalance(ctx context.Context, chainName, address, denom string) (*big.Int, error) {
+	var res struct {
+		Balance struct {
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}
+	path := "/cosmos/bank/v1beta1/balances/" + url.PathEscape(address) + "/by_denom?denom=" + url.QueryEscape(denom)
+	if err := c.do(ctx, chainName, http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+	if res.Balance.Amount == "" {
+		return new(big.Int), nil
+	}
+	amount, ok := new(big.Int).SetString(res.Balance.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q for %s", res.Balance.Amount, denom)
+	}
+	return amount, nil
+}
+
+// DenomTrace describes where an IBC voucher denom came from.
+type DenomTrace struct {
+	// Path is the chain of port/channel hops, e.g. "transfer/channel-0".
+	Path      string `json:"path"`
+	BaseDenom string `json:"base_denom"`
+}
+
+// ResolveDenom resolves an "ibc/<hash>" denom to its trace. It tries the
+// ibc-go v1 denom_traces endpoint first and falls back to the v2 denoms
+// endpoint served by newer chains.
+func (c *Client) ResolveDenom(ctx context.Context, chainName, denom string) (DenomTrace, error) {
+	hash, ok := strings.CutPrefix(denom, "ibc/")
+	if !ok {
+		return DenomTrace{}, fmt.Errorf("%s is not an IBC denom", denom)
+	}
+
+	cacheKey := chainName + "/" + hash
+	c.mu.RLock()
+	cached, ok := c.denoms[cacheKey]
+	c.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var v1 struct {
+		DenomTrace DenomTrace `json:"denom_trace"`
+	}
+	trace := DenomTrace{}
+	err := c.do(ctx, chainName, http.MethodGet, "/ibc/apps/transfer/v1/denom_traces/"+hash, nil, &v1)
+	if err == nil {
+		trace = v1.DenomTrace
+	} else {
+		var v2 struct {
+			Denom struct {
+				Base  string `json:"base"`
+				Trace []struct {
+					PortID    string `json:"port_id"`
+					ChannelID string `json:"channel_id"`
+				} `json:"trace"`
+			} `json:"denom"`
+		}
+		if err2 := c.do(ctx, chainName, http.MethodGet, "/ibc/apps/transfer/v1/denoms/"+hash, nil, &v2); err2 != nil {
+			return DenomTrace{}, fmt.Errorf("resolve %s: %w", denom, err)
+		}
+		hops := make([]string, 0, len(v2.Denom.Trace))
+		for _, h := range v2.Denom.Trace {
+			hops = append(hops, h.PortID+"/"+h.ChannelID)
+		}
+		trace = DenomTrace{Path: strings.Join(hops, "/"), BaseDenom: v2.Denom.Base}
+	}
+	if trace.BaseDenom == "" {
+		return DenomTrace{}, fmt.Errorf("resolve %s: empty trace", denom)
+	}
+
+	c.mu.Lock()
+	c.denoms[cacheKey] = trace
+	c.mu.Unlock()
+	return trace, nil
+}
+
+// Account holds the signing state of an on-chain account.
+type Account struct {
+	AccountNumber uint64
+	Sequence      uint64
+}
+
+// GetAccount returns the account number and sequence for address. Accounts
+// that have never received funds do not exist on chain and return
+// ErrNotFound.
+func (c *Client) GetAccount(ctx context.Context, chainName, address string) (Account, error) {
+	var res struct {
+		Account struct {
+			AccountNumber string `json:"account_number"`
+			Sequence      string `json:"sequence"`
+			// Vesting and module accounts nest the base account.
+			BaseAccount *struct {
+				AccountNumber string `json:"account_number"`
+				Sequence      string `json:"sequence"`
+			} `json:"base_account"`
+		} `json:"account"`
+	}
+	if err := c.do(ctx, chainName, http.MethodGet, "/cosmos/auth/v1beta1/accounts/"+url.PathEscape(address), nil, &res); err != nil {
+		return Account{}, err
+	}
+
+	num, seq := res.Account.AccountNumber, res.Account.Sequence
+	if res.Account.BaseAccount != nil {
+		num, seq = res.Account.BaseAccount.AccountNumber, res.Account.BaseAccount.Sequence
+	}
+	var acct Account
+	var err error
+	if acct.AccountNumber, err = parseUintField(num); err != nil {
+		return Account{}, fmt.Errorf("account_number: %w", err)
+	}
+	if acct.Sequence, err = parseUintField(seq); err != nil {
+		return Account{}, fmt.Errorf("sequence: %w", err)
+	}
+	return acct, nil
+}
+
+func parseUintField(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// Simulate returns the gas a transaction would use. The signature may be
+// empty; nodes skip signature verification in simulation.
+func (c *Client) Simulate(ctx context.Context, chainName string, txBytes []byte) (uint64, error) {
+	var res struct {
+		GasInfo struct {
+			GasUsed string `json:"gas_used"`
+		} `json:"gas_info"`
+	}
+	body := map[string]string{"tx_bytes": base64.StdEncoding.EncodeToString(txBytes)}
+	if err := c.do(ctx, chainName, http.MethodPost, "/cosmos/tx/v1beta1/simulate", body, &res); err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(res.GasInfo.GasUsed, 10, 64)
+}
+
+// TxResult is the outcome of a broadcast or included transaction.
+type TxResult struct {
+	TxHash string `json:"txhash"`
+	Height string `json:"height"`
+	Code   uint32 `json:"code"`
+	RawLog string `json:"raw_log"`
+}
+
+// Broadcast submits a signed transaction in sync mode, returning once it has
+// passed CheckTx. A non-zero code means the node rejected it.
+func (c *Client) Broadcast(ctx context.Context, chainName string, txBytes []byte) (*TxResult, error) {
+	var res struct {
+		TxResponse TxResult `json:"tx_response"`
+	}
+	body := map[string]string{
+		"tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+		"mode":     "BROADCAST_MODE_SYNC",
+	}
+	if err := c.do(ctx, chainName, http.MethodPost, "/cosmos/tx/v1beta1/txs", body, &res); err != nil {
+		return nil, err
+	}
+	if res.TxResponse.Code != 0 {
+		return &res.TxResponse, fmt.Errorf("transaction rejected (code %d): %s", res.TxResponse.Code, res.TxResponse.RawLog)
+	}
+	return &res.TxResponse, nil
+}
+
+// GetTx returns an included transaction, or ErrNotFound if it is not in a
+// block yet.
+func (c *Client) GetTx(ctx context.Context, chainName, txHash string) (*TxResult, error) {
+	var res struct {
+		TxResponse TxResult `json:"tx_response"`
+	}
+	if err := c.do(ctx, chainName, http.MethodGet, "/cosmos/tx/v1beta1/txs/"+url.PathEscape(txHash), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res.TxResponse, nil
+}
+
+// WaitForTx polls until the transaction is included in a block, or ctx is
+// done. A transaction included with a non-zero code is returned with an
+// error.
+func (c *Client) WaitForTx(ctx context.Context, chainName, txHash string, interval time.Duration) (*TxResult, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		res, err := c.GetTx(ctx, chainName, txHash)
+		switch {
+		case err == nil && res.Code != 0:
+			return res, fmt.Errorf("transaction %s failed (code %d): %s", txHash, res.Code, res.RawLog)
+		case err == nil:
+			return res, nil
+		case !errors.Is(err, ErrNotFound):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for %s: %w", txHash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeREST routes requests to the handler with the longest matching path
+// prefix.
+func fakeREST(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		best := ""
+		for prefix := range routes {
+			if strings.HasPrefix(r.URL.Path, prefix) && len(prefix) > len(best) {
+				best = prefix
+			}
+		}
+		if h, ok := routes[best]; ok {
+			h(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotImplemented)
+		_, _ = w.Write([]byte(`{"code":12,"message":"Not Implemented"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestClient(urls ...string) *Client {
+	c := NewClient()
+	c.AddChain("test", &ChainConfig{Name: "Test", ChainID: "test-1", RESTURLs: urls, Bech32Prefix: "cosmos", Denom: "uatom", DisplayDenom: "ATOM", Decimals: 6, GasPrice: "0.01"})
+	return c
+}
+
+func TestClient_GetBalancesResolvesIBCDenoms(t *testing.T) {
+	const hash = "27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+	var traceLookups int
+	srv := fakeREST(t, map[string]http.HandlerFunc{
+		"/cosmos/bank/v1beta1/balances/": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"balances": []map[string]string{
+				{"denom": "ibc/" + hash, "amount": "42"},
+				{"denom": "uatom", "amount": "1500000"},
+			}})
+		},
+		// Only the newer denoms endpoint is served, so the client must fall back.
+		"/ibc/apps/transfer/v1/denoms/": func(w http.ResponseWriter, r *http.Request) {
+			traceLookups++
+			writeJSON(w, map[string]any{"denom": map[string]any{
+				"base":  "uosmo",
+				"trace": []map[string]string{{"port_id": "transfer", "channel_id": "channel-141"}},
+			}})
+		},
+	})
+	c := newTestClient(srv.URL)
+
+	for range 2 {
+		balances, err := c.GetBalances(context.Background(), "test", "cosmos1x")
+		require.NoError(t, err)
+		require.Len(t, balances, 2)
+		require.NotNil(t, balances[0].Trace)
+		assert.Equal(t, "uosmo", balances[0].Trace.BaseDenom)
+		assert.Equal(t, "transfer/channel-141", balances[0].Trace.Path)
+		assert.Equal(t, "1500000", balances[1].Amount.String())
+		assert.Nil(t, balances[1].Trace)
+	}
+	assert.Equal(t, 1, traceLookups, "denom traces are cached")
+}
+
+func TestClient_GetAccount(t *testing.T) {
+	srv := fakeREST(t, map[string]http.HandlerFunc{
+		"/cosmos/auth/v1beta1/accounts/cosmos1vesting": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"account": map[string]any{
+				"@type":        "/cosmos.vesting.v1beta1.ContinuousVestingAccount",
+				"base_account": map[string]string{"account_number": "12", "sequence": "4"},
+			}})
+		},
+		"/cosmos/auth/v1beta1/accounts/cosmos1base": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"account": map[string]string{"account_number": "5", "sequence": "0"}})
+		},
+		"/cosmos/auth/v1beta1/accounts/": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":5,"message":"rpc error: code = NotFound desc = account not found"}`))
+		},
+	})
+	c := newTestClient(srv.URL)
+
+	acct, err := c.GetAccount(context.Background(), "test", "cosmos1base")
+	require.NoError(t, err)
+	assert.Equal(t, Account{AccountNumber: 5}, acct)
+
+	acct, err = c.GetAccount(context.Background(), "test", "cosmos1vesting")
+	require.NoError(t, err)
+	assert.Equal(t, Account{AccountNumber: 12, Sequence: 4}, acct)
+
+	_, err = c.GetAccount(context.Background(), "test", "cosmos1new")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_FallsBackOnServerErrorOnly(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	good := fakeREST(t, map[string]http.HandlerFunc{
+		"/cosmos/bank/v1beta1/balances/": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"balance": map[string]string{"denom": "uatom", "amount": "7"}})
+		},
+	})
+
+	bal, err := newTestClient(down.URL, good.URL).GetBalance(context.Background(), "test", "cosmos1x", "uatom")
+	require.NoError(t, err)
+	assert.Equal(t, "7", bal.String())
+
+	// A 4xx is the node rejecting the request; another node would too.
+	badReq := fakeREST(t, map[string]http.HandlerFunc{
+		"/": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":3,"message":"invalid address"}`))
+		},
+	})
+	_, err = newTestClient(badReq.URL, good.URL).GetBalance(context.Background(), "test", "cosmos1x", "uatom")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "invalid address", apiErr.Message)
+}
+
+func TestClient_BroadcastAndWait(t *testing.T) {
+	var polls int
+	srv := fakeREST(t, map[string]http.HandlerFunc{
+		"/cosmos/tx/v1beta1/simulate": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"gas_info": map[string]string{"gas_used": "81234"}})
+		},
+		"/cosmos/tx/v1beta1/txs/ABC": func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			if polls == 1 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":5,"message":"tx not found: ABC"}`))
+				return
+			}
+			writeJSON(w, map[string]any{"tx_response": map[string]any{"txhash": "ABC", "height": "100", "code": 0}})
+		},
+		"/cosmos/tx/v1beta1/txs": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "BROADCAST_MODE_SYNC", body["mode"])
+			writeJSON(w, map[string]any{"tx_response": map[string]any{"txhash": "ABC", "code": 0}})
+		},
+	})
+	c := newTestClient(srv.URL)
+
+	gas, err := c.Simulate(context.Background(), "test", []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(81234), gas)
+
+	res, err := c.Broadcast(context.Background(), "test", []byte{1})
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", res.TxHash)
+
+	included, err := c.WaitForTx(context.Background(), "test", "ABC", 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, "100", included.Height)
+	assert.Equal(t, 2, polls)
+}
+
+func TestClient_BroadcastRejected(t *testing.T) {
+	srv := fakeREST(t, map[string]http.HandlerFunc{
+		"/cosmos/tx/v1beta1/txs": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]any{"tx_response": map[string]any{"txhash": "ABC", "code": 13, "raw_log": "insufficient fee"}})
+		},
+	})
+
+	_, err := newTestClient(srv.URL).Broadcast(context.Background(), "test", []byte{1})
+	assert.ErrorContains(t, err, "insufficient fee")
+}
@@ -0,0 +1,96 @@
+package cosmos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChainConfig holds configuration for a Cosmos SDK chain.
+type ChainConfig struct {
+	Name    string `yaml:"name"`
+	ChainID string `yaml:"chain_id"`
+	// RESTURLs are LCD (gRPC-gateway) endpoints, tried in order.
+	RESTURLs     []string `yaml:"rest_urls"`
+	Bech32Prefix string   `yaml:"bech32_prefix"`
+	// Denom is the base fee/staking denom (e.g. "uatom"); DisplayDenom and
+	// Decimals describe its human-readable unit.
+	Denom        string `yaml:"denom"`
+	DisplayDenom string `yaml:"display_denom"`
+	Decimals     uint8  `yaml:"decimals"`
+	// GasPrice is the fee per unit of gas in Denom, as a decimal string.
+	GasPrice    string `yaml:"gas_price"`
+	ExplorerURL string `yaml:"explorer_url"`
+	IsTestnet   bool   `yaml:"is_testnet"`
+}
+
+// TxURL returns the block explorer page for a transaction hash, or "" when
+// the chain has no explorer configured.
+func (c *ChainConfig) TxURL(txHash string) string {
+	return c.explorerPath("tx", txHash)
+}
+
+// AddressURL returns the block explorer page for an account, or "" when the
+// chain has no explorer configured.
+func (c *ChainConfig) AddressURL(address string) string {
+	return c.explorerPath("address", address)
+}
+
+func (c *ChainConfig) explorerPath(kind, id string) string {
+	if c == nil || c.ExplorerURL == "" || id == "" {
+		return ""
+	}
+	return strings.TrimRight(c.ExplorerURL, "/") + "/" + kind + "/" + id
+}
+
+// Address renders a 20-byte account address with the chain's bech32 prefix.
+func (c *ChainConfig) Address(raw []byte) string {
+	addr, err := EncodeBech32(c.Bech32Prefix, raw)
+	if err != nil {
+		// Only reachable with a malformed prefix in config.
+		return ""
+	}
+	return addr
+}
+
+// ParseAddress decodes a bech32 address and checks it belongs to this chain.
+func (c *ChainConfig) ParseAddress(s string) ([]byte, error) {
+	hrp, data, err := DecodeBech32(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if hrp != c.Bech32Prefix {
+		return nil, fmt.Errorf("address %s is not a %s address (expected prefix %q)", s, c.Name, c.Bech32Prefix)
+	}
+	if len(data) != 20 && len(data) != 32 {
+		return nil, fmt.Errorf("invalid address length %d", len(data))
+	}
+	return data, nil
+}
+
+// DefaultChains returns the default Cosmos chain configurations.
+func DefaultChains() map[string]*ChainConfig {
+	return map[string]*ChainConfig{
+		"cosmoshub": {
+			Name:         "Cosmos Hub",
+			ChainID:      "cosmoshub-4",
+			RESTURLs:     []string{"https://cosmos-rest.publicnode.com", "https://rest.cosmos.directory/cosmoshub"},
+			Bech32Prefix: "cosmos",
+			Denom:        "uatom",
+			DisplayDenom: "ATOM",
+			Decimals:     6,
+			GasPrice:     "0.005",
+			ExplorerURL:  "https://www.mintscan.io/cosmos",
+		},
+		"osmosis": {
+			Name:         "Osmosis",
+			ChainID:      "osmosis-1",
+			RESTURLs:     []string{"https://osmosis-rest.publicnode.com", "https://rest.cosmos.directory/osmosis"},
+			Bech32Prefix: "osmo",
+			Denom:        "uosmo",
+			DisplayDenom: "OSMO",
+			Decimals:     6,
+			GasPrice:     "0.025",
+			ExplorerURL:  "https://www.mintscan.io/osmosis",
+		},
+	}
+}
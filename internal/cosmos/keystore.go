@@ -0,0 +1,240 @@
+package cosmos
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/yolodolo42/clifi/internal/wallet"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // Cosmos addresses are defined with RIPEMD-160.
+)
+
+var (
+	ErrKeyNotFound = errors.New("cosmos key not found")
+	ErrKeyLocked   = errors.New("cosmos key is locked")
+	ErrInvalidKey  = errors.New("invalid cosmos private key")
+)
+
+const keyFileVersion = 1
+
+// keyFile is the on-disk format of a key. The public key is stored in the
+// clear so addresses can be listed without the password.
+type keyFile struct {
+	Address string              `json:"address"`
+	PubKey  string              `json:"pubkey"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	Version int                 `json:"version"`
+}
+
+// Key identifies a stored secp256k1 key. The same 20-byte address is valid
+// on every Cosmos chain; only the bech32 prefix differs.
+type Key struct {
+	Address []byte
+	PubKey  []byte
+}
+
+// AddressFromPubKey derives the account address of a compressed secp256k1
+// public key: RIPEMD-160(SHA-256(pubkey)).
+func AddressFromPubKey(pubKey []byte) []byte {
+	sha := sha256.Sum256(pubKey)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	return h.Sum(nil)
+}
+
+// Keystore stores encrypted secp256k1 keys under <dataDir>/cosmos. Keys are
+// kept apart from the EVM keystore because Cosmos wallets derive keys on a
+// different HD path, so the same mnemonic yields different keys.
+type Keystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// NewKeystore creates a keystore rooted at dataDir.
+func NewKeystore(dataDir string) (*Keystore, error) {
+	dir := filepath.Join(dataDir, "cosmos")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cosmos keystore directory: %w", err)
+	}
+	// Like EVM wallets, new files get wallet.scrypt.
+	scrypt := wallet.ConfiguredScrypt()
+	return &Keystore{
+		dir:     dir,
+		scryptN: scrypt.N,
+		scryptP: scrypt.P,
+	}, nil
+}
+
+// Create generates a new key and stores it encrypted with password.
+func (ks *Keystore) Create(password string) (Key, error) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		return Key{}, err
+	}
+	return ks.store(priv, password)
+}
+
+// Import stores a hex-encoded private key, as exported by Keplr.
+func (ks *Keystore) Import(privateKeyHex, password string) (Key, error) {
+	privateKeyHex = strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x")
+	priv, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return ks.store(priv, password)
+}
+
+func (ks *Keystore) store(priv *ecdsa.PrivateKey, password string) (Key, error) {
+	pub := crypto.CompressPubkey(&priv.PublicKey)
+	key := Key{Address: AddressFromPubKey(pub), PubKey: pub}
+	if ks.Has(key.Address) {
+		return key, fmt.Errorf("key %x already exists", key.Address)
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3(crypto.FromECDSA(priv), []byte(password), ks.scryptN, ks.scryptP)
+	if err != nil {
+		return Key{}, fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	data, err := json.Marshal(keyFile{
+		Address: hex.EncodeToString(key.Address),
+		PubKey:  hex.EncodeToString(pub),
+		Crypto:  cryptoJSON,
+		Version: keyFileVersion,
+	})
+	if err != nil {
+		return Key{}, err
+	}
+	if err := os.WriteFile(ks.path(key.Address), data, 0600); err != nil {
+		return Key{}, fmt.Errorf("failed to write key: %w", err)
+	}
+	return key, nil
+}
+
+// List returns all stored keys, sorted by address.
+func (ks *Keystore) List() ([]Key, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		kf, err := ks.readFile(filepath.Join(ks.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		addr, err1 := hex.DecodeString(kf.Address)
+		pub, err2 := hex.DecodeString(kf.PubKey)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		keys = append(keys, Key{Address: addr, PubKey: pub})
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i].Address, keys[j].Address) < 0 })
+	return keys, nil
+}
+
+// Has reports whether a key for address is stored.
+func (ks *Keystore) Has(address []byte) bool {
+	_, err := os.Stat(ks.path(address))
+	return err == nil
+}
+
+// Signer decrypts the key for address.
+func (ks *Keystore) Signer(address []byte, password string) (*Signer, error) {
+	kf, err := ks.readFile(ks.path(address))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := keystore.DecryptDataV3(kf.Crypto, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock key: %w", err)
+	}
+	priv, err := crypto.ToECDSA(raw)
+	clear(raw)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt key file: %w", err)
+	}
+
+	pub := crypto.CompressPubkey(&priv.PublicKey)
+	if !bytes.Equal(AddressFromPubKey(pub), address) {
+		return nil, fmt.Errorf("corrupt key file for %x", address)
+	}
+	return &Signer{pubKey: pub, key: priv}, nil
+}
+
+func (ks *Keystore) readFile(path string) (*keyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("corrupt key file: %w", err)
+	}
+	return &kf, nil
+}
+
+func (ks *Keystore) path(address []byte) string {
+	return filepath.Join(ks.dir, hex.EncodeToString(address)+".json")
+}
+
+// Signer signs Cosmos transactions with an unlocked key.
+type Signer struct {
+	// mu protects key so signing cannot race with Lock zeroing it.
+	mu     sync.RWMutex
+	pubKey []byte
+	key    *ecdsa.PrivateKey // nil when locked
+}
+
+// PubKey returns the 33-byte compressed public key.
+func (s *Signer) PubKey() []byte {
+	return s.pubKey
+}
+
+// Sign signs tx's SignDoc and returns the 64-byte R||S signature Cosmos
+// expects (no recovery byte).
+func (s *Signer) Sign(tx *SendTx) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.key == nil {
+		return nil, ErrKeyLocked
+	}
+	// crypto.Sign produces low-S signatures, which Cosmos requires.
+	sig, err := crypto.Sign(tx.SignDigest(), s.key)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:64], nil
+}
+
+// Lock zeros the private key. Safe to call multiple times.
+func (s *Signer) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.key != nil {
+		s.key.D.SetInt64(0)
+		s.key = nil
+	}
+}
@@ -0,0 +1,72 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeystore(t *testing.T) *Keystore {
+	t.Helper()
+	ks, err := NewKeystore(t.TempDir())
+	require.NoError(t, err)
+	// Standard scrypt parameters take seconds per operation.
+	ks.scryptN, ks.scryptP = keystore.LightScryptN, keystore.LightScryptP
+	return ks
+}
+
+func TestKeystore_CreateAndSign(t *testing.T) {
+	ks := newTestKeystore(t)
+
+	key, err := ks.Create("password123")
+	require.NoError(t, err)
+	assert.Len(t, key.Address, 20)
+	assert.Equal(t, AddressFromPubKey(key.PubKey), key.Address)
+
+	keys, err := ks.List()
+	require.NoError(t, err)
+	assert.Equal(t, []Key{key}, keys)
+
+	_, err = ks.Signer(key.Address, "wrong")
+	require.Error(t, err)
+
+	signer, err := ks.Signer(key.Address, "password123")
+	require.NoError(t, err)
+	assert.Equal(t, key.PubKey, signer.PubKey())
+
+	p, _ := testSendParams(t)
+	p.PubKey = key.PubKey
+	tx, err := NewSendTx(p)
+	require.NoError(t, err)
+	sig, err := signer.Sign(tx)
+	require.NoError(t, err)
+	assert.Len(t, sig, 64)
+	assert.True(t, crypto.VerifySignature(key.PubKey, tx.SignDigest(), sig))
+
+	signer.Lock()
+	signer.Lock()
+	_, err = signer.Sign(tx)
+	assert.ErrorIs(t, err, ErrKeyLocked)
+}
+
+func TestKeystore_Import(t *testing.T) {
+	ks := newTestKeystore(t)
+
+	// Private key 1 has a well-known public key, so its address is fixed.
+	key, err := ks.Import("0x0000000000000000000000000000000000000000000000000000000000000001", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(key.PubKey))
+
+	_, err = ks.Import("0x0000000000000000000000000000000000000000000000000000000000000001", "password123")
+	assert.Error(t, err, "duplicate import should fail")
+
+	_, err = ks.Import("zz", "password123")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = ks.Signer(make([]byte, 20), "password123")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
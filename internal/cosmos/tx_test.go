@@ -0,0 +1,86 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSendParams(t *testing.T) (SendParams, []byte) {
+	t.Helper()
+	priv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return SendParams{
+		ChainID:       "cosmoshub-4",
+		AccountNumber: 7,
+		Sequence:      3,
+		PubKey:        crypto.CompressPubkey(&priv.PublicKey),
+		From:          "a",
+		To:            "b",
+		Amount:        Coin{Denom: "u", Amount: big.NewInt(1)},
+		Fee:           Coin{Denom: "u", Amount: big.NewInt(500)},
+		GasLimit:      100000,
+	}, crypto.FromECDSA(priv)
+}
+
+func TestNewSendTx_BodyEncoding(t *testing.T) {
+	p, _ := testSendParams(t)
+	tx, err := NewSendTx(p)
+	require.NoError(t, err)
+
+	// TxBody{messages: [Any{type_url, MsgSend{from "a", to "b", amount [{u 1}]}}]}
+	want := "0a2e" + "0a1c" + hex.EncodeToString([]byte(typeURLMsgSend)) + "120e" +
+		"0a0161" + "120162" + "1a06" + "0a0175" + "120131"
+	assert.Equal(t, want, hex.EncodeToString(tx.BodyBytes))
+}
+
+func TestNewSendTx_SignDoc(t *testing.T) {
+	p, privBytes := testSendParams(t)
+	tx, err := NewSendTx(p)
+	require.NoError(t, err)
+
+	// SignDoc ends with chain_id (field 3) and account_number (field 4).
+	suffix := "1a0b" + hex.EncodeToString([]byte("cosmoshub-4")) + "2007"
+	assert.Equal(t, suffix, hex.EncodeToString(tx.SignBytes[len(tx.SignBytes)-len(suffix)/2:]))
+
+	priv, err := crypto.ToECDSA(privBytes)
+	require.NoError(t, err)
+	sig, err := crypto.Sign(tx.SignDigest(), priv)
+	require.NoError(t, err)
+	assert.True(t, crypto.VerifySignature(p.PubKey, tx.SignDigest(), sig[:64]))
+
+	// An unsigned raw tx keeps an empty signature slot for simulation.
+	raw := tx.Raw(nil)
+	assert.Equal(t, "1a00", hex.EncodeToString(raw[len(raw)-2:]))
+}
+
+func TestNewSendTx_Validates(t *testing.T) {
+	p, _ := testSendParams(t)
+
+	p.Amount.Amount = big.NewInt(0)
+	_, err := NewSendTx(p)
+	assert.Error(t, err)
+
+	p, _ = testSendParams(t)
+	p.PubKey = p.PubKey[:32]
+	_, err = NewSendTx(p)
+	assert.Error(t, err)
+}
+
+func TestFeeFor(t *testing.T) {
+	fee, err := FeeFor(100000, "0.025")
+	require.NoError(t, err)
+	assert.Equal(t, "2500", fee.String())
+
+	// Fractional fees round up so the node never sees an underpaid fee.
+	fee, err = FeeFor(3, "0.5")
+	require.NoError(t, err)
+	assert.Equal(t, "2", fee.String())
+
+	_, err = FeeFor(1, "cheap")
+	assert.Error(t, err)
+}
@@ -0,0 +1,314 @@
+// Package dca stores recurring buys ("swap 50 USDC to ETH weekly") and
+// runs them when they fall due.
+package dca
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Unit is a schedule's period unit.
+type Unit string
+
+const (
+	Hour  Unit = "hour"
+	Day   Unit = "day"
+	Week  Unit = "week"
+	Month Unit = "month"
+)
+
+var adverbs = map[Unit]string{Hour: "hourly", Day: "daily", Week: "weekly", Month: "monthly"}
+
+// Schedule repeats every N units.
+type Schedule struct {
+	N    int
+	Unit Unit
+}
+
+// ParseSchedule parses hourly, daily, weekly, monthly, "every week",
+// "every 3 days" or "every 12h" (h, d, w).
+func ParseSchedule(s string) (Schedule, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	for u, adverb := range adverbs {
+		if len(fields) == 1 && fields[0] == adverb {
+			return Schedule{N: 1, Unit: u}, nil
+		}
+	}
+	invalid := fmt.Errorf("invalid schedule %q: use hourly, daily, weekly, monthly or e.g. \"every 3 days\"", s)
+	if len(fields) < 2 || fields[0] != "every" {
+		return Schedule{}, invalid
+	}
+	n, unit := 1, ""
+	switch len(fields) {
+	case 2:
+		unit = fields[1]
+		// "every 12h"
+		if i := strings.IndexFunc(unit, func(r rune) bool { return r < '0' || r > '9' }); i > 0 {
+			v, err := strconv.Atoi(unit[:i])
+			if err != nil {
+				return Schedule{}, invalid
+			}
+			n, unit = v, unit[i:]
+		}
+	case 3:
+		v, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return Schedule{}, invalid
+		}
+		n, unit = v, fields[2]
+	default:
+		return Schedule{}, invalid
+	}
+	if n < 1 {
+		return Schedule{}, invalid
+	}
+	switch strings.TrimSuffix(unit, "s") {
+	case "h", "hour", "hr":
+		return Schedule{N: n, Unit: Hour}, nil
+	case "d", "day":
+		return Schedule{N: n, Unit: Day}, nil
+	case "w", "week", "wk":
+		return Schedule{N: n, Unit: Week}, nil
+	case "month", "mo":
+		return Schedule{N: n, Unit: Month}, nil
+	}
+	return Schedule{}, invalid
+}
+
+func (s Schedule) String() string {
+	if s.N == 1 {
+		return adverbs[s.Unit]
+	}
+	return fmt.Sprintf("every %d %ss", s.N, s.Unit)
+}
+
+// Next returns the run after t. Months are calendar months.
+func (s Schedule) Next(t time.Time) time.Time {
+	switch s.Unit {
+	case Hour:
+		return t.Add(time.Duration(s.N) * time.Hour)
+	case Day:
+		return t.AddDate(0, 0, s.N)
+	case Week:
+		return t.AddDate(0, 0, 7*s.N)
+	default:
+		return t.AddDate(0, s.N, 0)
+	}
+}
+
+func (s Schedule) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Schedule) UnmarshalText(b []byte) error {
+	v, err := ParseSchedule(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// Plan is a recurring same-chain swap and its run state.
+type Plan struct {
+	ID          int       `json:"id"`
+	Chain       string    `json:"chain"`
+	Wallet      string    `json:"wallet"` // address
+	Amount      string    `json:"amount"` // in FromToken units
+	FromToken   string    `json:"from_token"`
+	ToToken     string    `json:"to_token"`
+	Every       Schedule  `json:"every"`
+	SlippageBps *uint32   `json:"slippage_bps,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// NextRun is the scheduled slot; a failed run is retried at RetryAt
+	// without moving it, so retries don't shift the schedule.
+	NextRun   time.Time `json:"next_run"`
+	RetryAt   time.Time `json:"retry_at,omitzero"`
+	Failures  int       `json:"failures,omitempty"`
+	Runs      int       `json:"runs,omitempty"`
+	LastRun   time.Time `json:"last_run,omitzero"`
+	LastTx    string    `json:"last_tx,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// ParsePlan parses
+//
+//	[swap] <amount> <FROM> to <TO> [on <chain>] <schedule>
+//
+// e.g. "swap 50 USDC to ETH weekly" or "swap 0.1 ETH to USDC on base
+// every 3 days". Tokens are symbols, addresses or native. The chain and
+// wallet are left empty when the text doesn't name them.
+func ParsePlan(text string) (Plan, error) {
+	fields := strings.Fields(text)
+	if len(fields) > 0 && strings.EqualFold(fields[0], "swap") {
+		fields = fields[1:]
+	}
+	usage := fmt.Errorf("invalid plan %q: want e.g. \"swap 50 USDC to ETH weekly\"", text)
+	if len(fields) < 5 {
+		return Plan{}, usage
+	}
+	switch strings.ToLower(fields[2]) {
+	case "to", "for", "into":
+	default:
+		return Plan{}, usage
+	}
+	amount := strings.TrimPrefix(fields[0], "$")
+	if v, err := strconv.ParseFloat(amount, 64); err != nil || v <= 0 {
+		return Plan{}, fmt.Errorf("invalid plan %q: %q is not a positive amount", text, fields[0])
+	}
+	p := Plan{Amount: amount, FromToken: token(fields[1]), ToToken: token(fields[3])}
+	if strings.EqualFold(p.FromToken, p.ToToken) {
+		return Plan{}, fmt.Errorf("invalid plan %q: nothing to swap %s to itself", text, p.FromToken)
+	}
+
+	rest := fields[4:]
+	for i := 0; i < len(rest)-1; i++ {
+		if strings.EqualFold(rest[i], "on") {
+			p.Chain = strings.ToLower(rest[i+1])
+			rest = append(rest[:i:i], rest[i+2:]...)
+			break
+		}
+	}
+	every, err := ParseSchedule(strings.Join(rest, " "))
+	if err != nil {
+		return Plan{}, err
+	}
+	p.Every = every
+	return p, nil
+}
+
+func token(s string) string {
+	if common.IsHexAddress(s) {
+		return s
+	}
+	if strings.EqualFold(s, "native") {
+		return "native"
+	}
+	return strings.ToUpper(s)
+}
+
+func (p Plan) String() string {
+	s := fmt.Sprintf("swap %s %s to %s", p.Amount, p.FromToken, p.ToToken)
+	if p.Chain != "" {
+		s += " on " + p.Chain
+	}
+	return s + " " + p.Every.String()
+}
+
+// File is where plans are kept, in the data dir.
+const File = "dca.json"
+
+// Store keeps plans in dataDir/dca.json. `clifi dca` and `clifi serve`
+// share it from separate processes, so every change is a read-modify-write
+// of the whole file.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store in dataDir.
+func NewStore(dataDir string) *Store {
+	return &Store{path: filepath.Join(dataDir, File)}
+}
+
+type storeFile struct {
+	NextID int    `json:"next_id"`
+	Plans  []Plan `json:"plans"`
+}
+
+func (s *Store) load() (*storeFile, error) {
+	f := &storeFile{NextID: 1}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", File, err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", File, err)
+	}
+	return f, nil
+}
+
+func (s *Store) save(f *storeFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// List returns the plans, oldest first.
+func (s *Store) List() ([]Plan, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return f.Plans, nil
+}
+
+// Add stores a new plan and returns it with its ID. A plan without a
+// NextRun first runs at the next check.
+func (s *Store) Add(p Plan) (Plan, error) {
+	if p.Chain == "" || p.Wallet == "" {
+		return Plan{}, fmt.Errorf("plan needs a chain and a wallet")
+	}
+	f, err := s.load()
+	if err != nil {
+		return Plan{}, err
+	}
+	p.ID = f.NextID
+	p.CreatedAt = time.Now().UTC()
+	if p.NextRun.IsZero() {
+		p.NextRun = p.CreatedAt
+	}
+	f.NextID++
+	f.Plans = append(f.Plans, p)
+	return p, s.save(f)
+}
+
+// Remove deletes a plan, reporting whether it existed.
+func (s *Store) Remove(id int) (bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	for i, p := range f.Plans {
+		if p.ID == id {
+			f.Plans = append(f.Plans[:i], f.Plans[i+1:]...)
+			return true, s.save(f)
+		}
+	}
+	return false, nil
+}
+
+// update stores run state by ID. Plans removed since they were read stay
+// removed.
+func (s *Store) update(ran map[int]Plan) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, p := range f.Plans {
+		if r, ok := ran[p.ID]; ok {
+			f.Plans[i] = r
+		}
+	}
+	return s.save(f)
+}
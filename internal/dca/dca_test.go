@@ -0,0 +1,141 @@
+package dca
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/audit"
+)
+
+func TestParsePlan(t *testing.T) {
+	cases := map[string]struct {
+		text string
+		want string
+	}{
+		"weekly":          {"swap 50 USDC to ETH weekly", "swap 50 USDC to ETH weekly"},
+		"lowercase":       {"swap 50 usdc for eth daily", "swap 50 USDC to ETH daily"},
+		"no verb":         {"0.1 ETH to USDC monthly", "swap 0.1 ETH to USDC monthly"},
+		"chain":           {"swap 50 USDC to ETH on base every 3 days", "swap 50 USDC to ETH on base every 3 days"},
+		"chain last":      {"swap 50 USDC to ETH every 2 weeks on Arbitrum", "swap 50 USDC to ETH on arbitrum every 2 weeks"},
+		"compact":         {"swap 10 USDC to native every 12h", "swap 10 USDC to native every 12 hours"},
+		"every unit":      {"swap 10 USDC to ETH every week", "swap 10 USDC to ETH weekly"},
+		"dollar":          {"swap $25 USDC to ETH hourly", "swap 25 USDC to ETH hourly"},
+		"token addresses": {"swap 1 0x00000000000000000000000000000000000000aa to ETH daily", "swap 1 0x00000000000000000000000000000000000000aa to ETH daily"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParsePlan(tc.text)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, p.String())
+		})
+	}
+
+	for _, bad := range []string{
+		"",
+		"swap 50 USDC ETH weekly",
+		"swap 50 USDC to ETH",
+		"swap 0 USDC to ETH weekly",
+		"swap x USDC to ETH weekly",
+		"swap 50 USDC to usdc weekly",
+		"swap 50 USDC to ETH yearly",
+		"swap 50 USDC to ETH every 0 days",
+		"swap 50 USDC to ETH every 5 minutes",
+	} {
+		_, err := ParsePlan(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	start := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, start.Add(12*time.Hour), Schedule{N: 12, Unit: Hour}.Next(start))
+	assert.Equal(t, start.AddDate(0, 0, 14), Schedule{N: 2, Unit: Week}.Next(start))
+	assert.Equal(t, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), Schedule{N: 1, Unit: Month}.Next(start))
+}
+
+type fakeExec struct {
+	err   error
+	calls []int
+}
+
+func (f *fakeExec) Execute(_ context.Context, p Plan) (Execution, error) {
+	f.calls = append(f.calls, p.ID)
+	if f.err != nil {
+		return Execution{}, f.err
+	}
+	return Execution{TxHash: "0xabc", Summary: "Swapped " + p.Amount + " " + p.FromToken}, nil
+}
+
+func TestRunner(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	log := audit.New(dir)
+	exec := &fakeExec{}
+	r := &Runner{Store: store, Exec: exec, Audit: log}
+
+	p, err := ParsePlan("swap 50 USDC to ETH on base weekly")
+	require.NoError(t, err)
+	p.Wallet = "0x00000000000000000000000000000000000000bb"
+	p, err = store.Add(p)
+	require.NoError(t, err)
+	start := p.NextRun
+
+	// Due right away.
+	require.NoError(t, r.RunDue(context.Background(), start))
+	plans, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, 1, plans[0].Runs)
+	assert.Equal(t, "0xabc", plans[0].LastTx)
+	assert.Equal(t, start.AddDate(0, 0, 7), plans[0].NextRun)
+
+	// Not due again within the week.
+	require.NoError(t, r.RunDue(context.Background(), start.Add(48*time.Hour)))
+	assert.Len(t, exec.calls, 1)
+
+	// Failures retry the slot, then skip it.
+	exec.err = errors.New("policy: exceeds max per tx")
+	slot := start.AddDate(0, 0, 7)
+	now := slot
+	for i := 1; i < MaxAttempts; i++ {
+		require.NoError(t, r.RunDue(context.Background(), now))
+		plans, _ = store.List()
+		assert.Equal(t, slot, plans[0].NextRun)
+		assert.Equal(t, now.Add(RetryDelay), plans[0].RetryAt)
+		require.NoError(t, r.RunDue(context.Background(), now.Add(time.Minute)))
+		assert.Len(t, exec.calls, 1+i, "not retried before RetryAt")
+		now = now.Add(RetryDelay)
+	}
+	require.NoError(t, r.RunDue(context.Background(), now))
+	plans, _ = store.List()
+	assert.Equal(t, slot.AddDate(0, 0, 7), plans[0].NextRun)
+	assert.True(t, plans[0].RetryAt.IsZero())
+	assert.Equal(t, "policy: exceeds max per tx", plans[0].LastError)
+
+	// Slots missed while serve was down are not caught up.
+	exec.err = nil
+	late := slot.AddDate(0, 0, 7*5).Add(time.Hour)
+	require.NoError(t, r.RunDue(context.Background(), late))
+	plans, _ = store.List()
+	assert.Equal(t, slot.AddDate(0, 0, 7*6), plans[0].NextRun)
+	assert.Len(t, exec.calls, 1+MaxAttempts+1)
+
+	entries, err := log.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, len(exec.calls))
+	assert.Equal(t, audit.StatusOK, entries[0].Status)
+	assert.Equal(t, "dca #1", entries[0].Source)
+	assert.Equal(t, "0xabc", entries[0].TxHash)
+	assert.Equal(t, audit.StatusFailed, entries[1].Status)
+	assert.Equal(t, "policy: exceeds max per tx", entries[1].Error)
+}
+
+func TestStoreRejectsIncompletePlan(t *testing.T) {
+	p, err := ParsePlan("swap 50 USDC to ETH weekly")
+	require.NoError(t, err)
+	_, err = NewStore(t.TempDir()).Add(p)
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package dca
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/audit"
+)
+
+const (
+	// DefaultInterval is how often `clifi serve` looks for due plans.
+	DefaultInterval = time.Minute
+	// RetryDelay is the wait before retrying a failed run.
+	RetryDelay = 15 * time.Minute
+	// MaxAttempts is how often a slot is tried before it is skipped.
+	MaxAttempts = 3
+)
+
+// Execution is a completed swap.
+type Execution struct {
+	TxHash  string
+	Summary string
+}
+
+// Executor performs a plan's swap. An error means nothing was swapped.
+type Executor interface {
+	Execute(ctx context.Context, p Plan) (Execution, error)
+}
+
+// Runner runs due plans and records every run in the audit trail.
+type Runner struct {
+	Store    *Store
+	Exec     Executor
+	Audit    *audit.Log
+	Out      io.Writer // optional; one line per run
+	Interval time.Duration
+}
+
+// Run checks for due plans every Interval until ctx is done.
+func (r *Runner) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.RunDue(ctx, time.Now().UTC()); err != nil {
+			slog.Warn("dca check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Due reports whether p should run at now.
+func (p Plan) Due(now time.Time) bool {
+	if p.NextRun.After(now) {
+		return false
+	}
+	return p.RetryAt.IsZero() || !p.RetryAt.After(now)
+}
+
+// RunDue runs the plans due at now, one at a time.
+func (r *Runner) RunDue(ctx context.Context, now time.Time) error {
+	plans, err := r.Store.List()
+	if err != nil {
+		return err
+	}
+	ran := make(map[int]Plan)
+	for _, p := range plans {
+		if !p.Due(now) || ctx.Err() != nil {
+			continue
+		}
+		ex, err := r.Exec.Execute(ctx, p)
+		p.advance(now, ex, err)
+		ran[p.ID] = p
+		r.record(p, ex, err)
+	}
+	if len(ran) == 0 {
+		return nil
+	}
+	return r.Store.update(ran)
+}
+
+func (r *Runner) record(p Plan, ex Execution, err error) {
+	e := audit.Entry{
+		Source:  fmt.Sprintf("dca #%d", p.ID),
+		Action:  "swap",
+		Chain:   p.Chain,
+		From:    p.Wallet,
+		Summary: ex.Summary,
+		TxHash:  ex.TxHash,
+		Status:  audit.StatusOK,
+	}
+	line := fmt.Sprintf("DCA #%d: %s", p.ID, ex.Summary)
+	if err != nil {
+		e.Summary = p.String()
+		e.Status = audit.StatusFailed
+		e.Error = err.Error()
+		line = fmt.Sprintf("DCA #%d failed (%s): %v", p.ID, p.String(), err)
+		if !p.RetryAt.IsZero() {
+			line += fmt.Sprintf("; retrying at %s", p.RetryAt.Local().Format("15:04"))
+		}
+	} else if ex.TxHash != "" {
+		line += " (" + ex.TxHash + ")"
+	}
+	if r.Audit != nil {
+		if err := r.Audit.Record(e); err != nil {
+			slog.Warn("audit record failed", "plan", p.ID, "err", err)
+		}
+	}
+	if r.Out != nil {
+		fmt.Fprintln(r.Out, line)
+	}
+}
+
+// advance updates p after a run at now. A failed slot is retried after
+// RetryDelay, up to MaxAttempts; slots missed while nothing was running
+// are skipped rather than bought all at once.
+func (p *Plan) advance(now time.Time, ex Execution, err error) {
+	p.LastRun = now
+	if err != nil {
+		p.LastError = err.Error()
+		p.Failures++
+		if p.Failures < MaxAttempts {
+			p.RetryAt = now.Add(RetryDelay)
+			return
+		}
+	} else {
+		p.Runs++
+		p.LastTx = ex.TxHash
+		p.LastError = ""
+	}
+	p.Failures = 0
+	p.RetryAt = time.Time{}
+	next := p.Every.Next(p.NextRun)
+	for !next.After(now) {
+		next = p.Every.Next(next)
+	}
+	p.NextRun = next
+}
@@ -0,0 +1,337 @@
+// Package decode turns EVM calldata and event logs into readable calls:
+// function and event names with their arguments. Signatures come from
+// built-in well-known ones plus any contract ABIs or text signatures
+// added to a Decoder.
+package decode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Where a decoded signature came from.
+const (
+	SourceBuiltin = "built-in"
+	SourceABI     = "contract ABI"
+)
+
+var (
+	// ErrUnknownSelector means no known function has the calldata's
+	// selector.
+	ErrUnknownSelector = errors.New("unknown function selector")
+	// ErrUnknownEvent means no known event matches the log's topic.
+	ErrUnknownEvent = errors.New("unknown event")
+)
+
+// Arg is one decoded argument.
+type Arg struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Call is decoded calldata.
+type Call struct {
+	Selector  string `json:"selector"`
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Args      []Arg  `json:"args"`
+	Source    string `json:"source"`
+}
+
+// Event is a decoded log.
+type Event struct {
+	Address   common.Address `json:"address"`
+	Name      string         `json:"name"`
+	Signature string         `json:"signature"`
+	Args      []Arg          `json:"args"`
+	Source    string         `json:"source"`
+}
+
+// Arg returns the value of the named argument, or "".
+func (e *Event) Arg(name string) string {
+	for _, a := range e.Args {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// eventKey tells apart events sharing a signature but not their indexed
+// arguments, such as ERC-20 and ERC-721 Transfer.
+type eventKey struct {
+	topic   common.Hash
+	indexed int
+}
+
+type method struct {
+	abi.Method
+	source string
+}
+
+type event struct {
+	abi.Event
+	source string
+}
+
+// Decoder holds the known function and event signatures. It is safe for
+// concurrent use.
+type Decoder struct {
+	mu      sync.RWMutex
+	methods map[[4]byte]method
+	events  map[eventKey]event
+}
+
+// Empty returns a Decoder that knows no signatures.
+func Empty() *Decoder {
+	return &Decoder{methods: make(map[[4]byte]method), events: make(map[eventKey]event)}
+}
+
+// New returns a Decoder that knows the built-in signatures.
+func New() *Decoder {
+	d := Empty()
+	for _, sig := range builtinFunctions {
+		if err := d.AddSignature(sig, SourceBuiltin); err != nil {
+			panic(fmt.Sprintf("decode: builtin %s: %v", sig, err))
+		}
+	}
+	for _, sig := range builtinEvents {
+		if err := d.AddEventSignature(sig, SourceBuiltin); err != nil {
+			panic(fmt.Sprintf("decode: builtin %s: %v", sig, err))
+		}
+	}
+	return d
+}
+
+// AddABI adds every function and event of a contract's JSON ABI. Names
+// from an ABI replace those of a built-in signature with the same
+// selector.
+func (d *Decoder) AddABI(abiJSON string) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return fmt.Errorf("parse ABI: %w", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range parsed.Methods {
+		d.methods[[4]byte(m.ID)] = method{m, SourceABI}
+	}
+	for _, e := range parsed.Events {
+		if !e.Anonymous {
+			d.events[eventKey{e.ID, indexedCount(e.Inputs)}] = event{e, SourceABI}
+		}
+	}
+	return nil
+}
+
+// AddSignature adds a function from a text signature such as
+// "transfer(address to,uint256 amount)"; argument names are optional.
+// An existing function with the same selector is kept.
+func (d *Decoder) AddSignature(sig, source string) error {
+	name, inputs, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	m := abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.methods[[4]byte(m.ID)]; !ok {
+		d.methods[[4]byte(m.ID)] = method{m, source}
+	}
+	return nil
+}
+
+// AddEventSignature adds an event from a text signature such as
+// "Transfer(address indexed from,address indexed to,uint256 value)".
+func (d *Decoder) AddEventSignature(sig, source string) error {
+	name, inputs, err := parseSignature(sig)
+	if err != nil {
+		return err
+	}
+	e := abi.NewEvent(name, name, false, inputs)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := eventKey{e.ID, indexedCount(inputs)}
+	if _, ok := d.events[key]; !ok {
+		d.events[key] = event{e, source}
+	}
+	return nil
+}
+
+func indexedCount(args abi.Arguments) int {
+	n := 0
+	for _, a := range args {
+		if a.Indexed {
+			n++
+		}
+	}
+	return n
+}
+
+// Selector returns the 4-byte selector of calldata.
+func Selector(data []byte) ([4]byte, error) {
+	if len(data) < 4 {
+		return [4]byte{}, fmt.Errorf("calldata is %d bytes, shorter than a selector", len(data))
+	}
+	return [4]byte(data[:4]), nil
+}
+
+// Call decodes calldata, or returns ErrUnknownSelector.
+func (d *Decoder) Call(data []byte) (*Call, error) {
+	sel, err := Selector(data)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.RLock()
+	m, ok := d.methods[sel]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownSelector, hexutil.Encode(sel[:]))
+	}
+	values, err := m.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", m.Sig, err)
+	}
+	c := &Call{Selector: hexutil.Encode(sel[:]), Name: m.RawName, Signature: m.Sig, Source: m.source}
+	for i, in := range m.Inputs {
+		c.Args = append(c.Args, Arg{Name: in.Name, Type: in.Type.String(), Value: Format(values[i])})
+	}
+	return c, nil
+}
+
+// Log decodes an event log, or returns ErrUnknownEvent.
+func (d *Decoder) Log(l *types.Log) (*Event, error) {
+	if len(l.Topics) == 0 {
+		return nil, fmt.Errorf("%w: anonymous log", ErrUnknownEvent)
+	}
+	d.mu.RLock()
+	e, ok := d.events[eventKey{l.Topics[0], len(l.Topics) - 1}]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownEvent, l.Topics[0].Hex())
+	}
+
+	data, err := e.Inputs.NonIndexed().Unpack(l.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", e.Sig, err)
+	}
+	ev := &Event{Address: l.Address, Name: e.RawName, Signature: e.Sig, Source: e.source}
+	topic := 1
+	for _, in := range e.Inputs {
+		a := Arg{Name: in.Name, Type: in.Type.String()}
+		if in.Indexed {
+			a.Value = topicValue(in.Type, l.Topics[topic])
+			topic++
+		} else {
+			a.Value = Format(data[0])
+			data = data[1:]
+		}
+		ev.Args = append(ev.Args, a)
+	}
+	return ev, nil
+}
+
+// topicValue decodes an indexed argument. Dynamic types are stored as
+// their hash, which is all that can be shown.
+func topicValue(t abi.Type, topic common.Hash) string {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return "hash " + topic.Hex()
+	}
+	values, err := abi.Arguments{{Type: t}}.Unpack(topic.Bytes())
+	if err != nil || len(values) == 0 {
+		return topic.Hex()
+	}
+	return Format(values[0])
+}
+
+// Format renders a decoded value: addresses checksummed, integers in
+// decimal, bytes in hex, and arrays and tuples bracketed.
+func Format(v interface{}) string {
+	switch x := v.(type) {
+	case common.Address:
+		return x.Hex()
+	case *big.Int:
+		return x.String()
+	case []byte:
+		return hexutil.Encode(x)
+	case string:
+		b, _ := json.Marshal(x)
+		return string(b)
+	case bool:
+		return fmt.Sprint(x)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = Format(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case reflect.Struct:
+		parts := make([]string, rv.NumField())
+		for i := range parts {
+			parts[i] = Format(rv.Field(i).Interface())
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+	return fmt.Sprint(v)
+}
+
+// String renders the call as name(arg=value, ...).
+func (c *Call) String() string {
+	return c.Name + "(" + joinArgs(c.Args) + ")"
+}
+
+// String renders the event as Name(arg=value, ...).
+func (e *Event) String() string {
+	return e.Name + "(" + joinArgs(e.Args) + ")"
+}
+
+func joinArgs(args []Arg) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			parts[i] = a.Name + "=" + a.Value
+		} else {
+			parts[i] = a.Value
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseHex reads calldata written as hex, with or without 0x; whitespace
+// and line breaks from copy-pasting are ignored.
+func ParseHex(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("calldata has an odd number of hex digits")
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("calldata is not hex: %w", err)
+	}
+	return data, nil
+}
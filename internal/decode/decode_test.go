@@ -0,0 +1,111 @@
+package decode
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	bob   = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+)
+
+func word(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+
+func TestCall_Builtin(t *testing.T) {
+	data := append(common.FromHex("0xa9059cbb"), word(bob.Bytes())...)
+	data = append(data, word(big.NewInt(1_500_000).Bytes())...)
+
+	c, err := New().Call(data)
+	require.NoError(t, err)
+	assert.Equal(t, "0xa9059cbb", c.Selector)
+	assert.Equal(t, "transfer(address,uint256)", c.Signature)
+	assert.Equal(t, SourceBuiltin, c.Source)
+	assert.Equal(t, []Arg{{Name: "to", Type: "address", Value: bob.Hex()}, {Name: "amount", Type: "uint256", Value: "1500000"}}, c.Args)
+	assert.Equal(t, "transfer(to="+bob.Hex()+", amount=1500000)", c.String())
+
+	_, err = New().Call(common.FromHex("0xdeadbeef"))
+	assert.True(t, errors.Is(err, ErrUnknownSelector))
+	assert.Contains(t, err.Error(), "0xdeadbeef")
+	_, err = New().Call([]byte{1, 2})
+	assert.Error(t, err)
+}
+
+func TestCall_ABIAndTuples(t *testing.T) {
+	d := New()
+	require.NoError(t, d.AddABI(`[{"type":"function","name":"transfer","inputs":[{"name":"recipient","type":"address"},{"name":"value","type":"uint256"}],"outputs":[]}]`))
+	data := append(common.FromHex("0xa9059cbb"), word(bob.Bytes())...)
+	data = append(data, word([]byte{7})...)
+	c, err := d.Call(data)
+	require.NoError(t, err)
+	// The contract's own names win over the built-in ones.
+	assert.Equal(t, SourceABI, c.Source)
+	assert.Equal(t, "recipient", c.Args[0].Name)
+
+	require.NoError(t, d.AddSignature("batch((address,uint)[],string)", "test"))
+	name, args, err := parseSignature("batch((address,uint256)[],string)")
+	require.NoError(t, err)
+	m, ok := d.methods[[4]byte(crypto.Keccak256([]byte("batch((address,uint256)[],string)")))]
+	require.True(t, ok)
+	require.Equal(t, name, m.RawName)
+	packed, err := args.Pack([]struct {
+		Field0 common.Address
+		Field1 *big.Int
+	}{{alice, big.NewInt(1)}, {bob, big.NewInt(2)}}, "hi")
+	require.NoError(t, err)
+	c, err = d.Call(append(m.ID, packed...))
+	require.NoError(t, err)
+	assert.Equal(t, "test", c.Source)
+	assert.Equal(t, "[("+alice.Hex()+", 1), ("+bob.Hex()+", 2)]", c.Args[0].Value)
+	assert.Equal(t, `"hi"`, c.Args[1].Value)
+}
+
+func TestLog(t *testing.T) {
+	d := New()
+	token := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	// ERC-20: the value is data.
+	e, err := d.Log(&types.Log{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())},
+		Data: word(big.NewInt(42).Bytes())})
+	require.NoError(t, err)
+	assert.Equal(t, "Transfer", e.Name)
+	assert.Equal(t, token, e.Address)
+	assert.Equal(t, alice.Hex(), e.Arg("from"))
+	assert.Equal(t, "42", e.Arg("value"))
+
+	// ERC-721: the same topic with the token ID indexed.
+	e, err = d.Log(&types.Log{Topics: []common.Hash{transfer, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes()), common.BigToHash(big.NewInt(9))}})
+	require.NoError(t, err)
+	assert.Equal(t, "9", e.Arg("tokenId"))
+
+	_, err = d.Log(&types.Log{Topics: []common.Hash{common.HexToHash("0x01")}})
+	assert.True(t, errors.Is(err, ErrUnknownEvent))
+}
+
+func TestParseSignature(t *testing.T) {
+	for _, bad := range []string{"transfer", "(address)", "f(address", "f(address to extra)", "f(foo)", "f((address)"} {
+		_, _, err := parseSignature(bad)
+		assert.Error(t, err, bad)
+	}
+	_, args, err := parseSignature("Approval(address indexed owner, address indexed spender, uint value)")
+	require.NoError(t, err)
+	assert.True(t, args[0].Indexed)
+	assert.Equal(t, "uint256", args[2].Type.String())
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "0x0102", Format([2]byte{1, 2}))
+	assert.Equal(t, "[1, 2]", Format([]*big.Int{big.NewInt(1), big.NewInt(2)}))
+	assert.Equal(t, "true", Format(true))
+	assert.Equal(t, "7", Format(uint8(7)))
+	assert.Equal(t, hexutil.Encode([]byte("x")), Format([]byte("x")))
+}
@@ -0,0 +1,115 @@
+package decode
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EventTopics builds eth_getLogs topics for an event signature such as
+// "Transfer(address indexed from,address indexed to,uint256 value)":
+// the event's topic, then for each indexed argument the values in where
+// (keyed by argument name) or a wildcard. Values are written as they
+// would be typed: addresses, decimal integers, true/false, hex for fixed
+// bytes, and plain text for strings, which topics store hashed.
+func EventTopics(sig string, where map[string][]string) ([][]common.Hash, error) {
+	name, inputs, err := parseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	topics := [][]common.Hash{{abi.NewEvent(name, name, false, inputs).ID}}
+
+	used := make(map[string]bool)
+	for _, in := range inputs {
+		if !in.Indexed {
+			continue
+		}
+		values := where[in.Name]
+		used[in.Name] = true
+		var position []common.Hash
+		for _, v := range values {
+			h, err := Topic(in.Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", in.Name, err)
+			}
+			position = append(position, h)
+		}
+		topics = append(topics, position)
+	}
+	var unknown []string
+	for k := range where {
+		if !used[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%s has no indexed argument named %s; only indexed arguments can be filtered", name, strings.Join(unknown, ", "))
+	}
+	// Trailing wildcards are implied.
+	for len(topics) > 1 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
+	return topics, nil
+}
+
+// Topic encodes one indexed argument value the way the EVM stores it.
+func Topic(t abi.Type, value string) (common.Hash, error) {
+	value = strings.TrimSpace(value)
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(value) {
+			return common.Hash{}, fmt.Errorf("%q is not an address", value)
+		}
+		return common.BytesToHash(common.HexToAddress(value).Bytes()), nil
+	case abi.UintTy, abi.IntTy:
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return common.Hash{}, fmt.Errorf("%q is not an integer", value)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return common.Hash{}, fmt.Errorf("%q is negative", value)
+		}
+		return common.BytesToHash(math256(n)), nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("%q is not true or false", value)
+		}
+		if b {
+			return common.BigToHash(big.NewInt(1)), nil
+		}
+		return common.Hash{}, nil
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil || len(b) > t.Size {
+			return common.Hash{}, fmt.Errorf("%q is not %s hex", value, t.String())
+		}
+		return common.BytesToHash(common.RightPadBytes(b, 32)), nil
+	case abi.StringTy:
+		return crypto.Keccak256Hash([]byte(value)), nil
+	case abi.BytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("%q is not hex", value)
+		}
+		return crypto.Keccak256Hash(b), nil
+	}
+	return common.Hash{}, fmt.Errorf("filtering on %s arguments is not supported", t.String())
+}
+
+// math256 is n as a 32-byte two's complement word.
+func math256(n *big.Int) []byte {
+	if n.Sign() >= 0 {
+		return common.LeftPadBytes(n.Bytes(), 32)
+	}
+	mod := new(big.Int).Lsh(big.NewInt(1), 256)
+	return common.LeftPadBytes(new(big.Int).Add(mod, n).Bytes(), 32)
+}
@@ -0,0 +1,49 @@
+package decode
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTopics(t *testing.T) {
+	const transfer = "Transfer(address indexed from,address indexed to,uint256 value)"
+	topics, err := EventTopics(transfer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]common.Hash{{common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")}}, topics)
+
+	// Filtering on the second indexed argument leaves the first a wildcard.
+	topics, err = EventTopics(transfer, map[string][]string{"to": {alice.Hex(), bob.Hex()}})
+	require.NoError(t, err)
+	require.Len(t, topics, 3)
+	assert.Empty(t, topics[1])
+	assert.Equal(t, []common.Hash{common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())}, topics[2])
+
+	_, err = EventTopics(transfer, map[string][]string{"value": {"1"}})
+	assert.ErrorContains(t, err, "no indexed argument named value")
+	_, err = EventTopics(transfer, map[string][]string{"from": {"nope"}})
+	assert.ErrorContains(t, err, "not an address")
+}
+
+func TestTopic(t *testing.T) {
+	_, args, err := parseSignature("E(int256 indexed a,bool indexed b,bytes4 indexed c,string indexed d)")
+	require.NoError(t, err)
+
+	h, err := Topic(args[0].Type, "-1")
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"), h)
+	h, err = Topic(args[1].Type, "true")
+	require.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(1)), h)
+	h, err = Topic(args[2].Type, "0xa9059cbb")
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0xa9059cbb00000000000000000000000000000000000000000000000000000000"), h)
+	_, err = Topic(args[2].Type, "0xa9059cbb00")
+	assert.Error(t, err)
+	h, err = Topic(args[3].Type, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "0x1c8aff950685c2ed4bc3174f3472287b56d9517b9c948127319a09a7a36deac8", h.Hex())
+}
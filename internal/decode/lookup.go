@@ -0,0 +1,162 @@
+package decode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SourceSignatureDB marks signatures found in the public signature
+// database rather than an ABI.
+const SourceSignatureDB = "4byte.directory"
+
+const defaultSignatureDBURL = "https://www.4byte.directory/api/v1/signatures/"
+
+// SignatureDB looks selectors up in 4byte.directory, which maps them to
+// every text signature anyone has registered for them.
+type SignatureDB struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// NewSignatureDB returns a client for the public 4byte.directory API.
+func NewSignatureDB() *SignatureDB {
+	return &SignatureDB{URL: defaultSignatureDBURL, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Functions returns the signatures registered for selector, oldest first:
+// later entries are more often spam colliding with a real function.
+func (db *SignatureDB) Functions(ctx context.Context, selector [4]byte) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, db.URL+"?hex_signature="+url.QueryEscape(hexutil.Encode(selector[:])), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := db.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("signature lookup: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signature lookup: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Results []struct {
+			ID            int    `json:"id"`
+			TextSignature string `json:"text_signature"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("signature lookup: decode response: %w", err)
+	}
+	sort.Slice(body.Results, func(i, j int) bool { return body.Results[i].ID < body.Results[j].ID })
+	sigs := make([]string, 0, len(body.Results))
+	for _, r := range body.Results {
+		sigs = append(sigs, r.TextSignature)
+	}
+	return sigs, nil
+}
+
+// Resolution is calldata decoded with whatever signature source worked.
+type Resolution struct {
+	*Call
+	// Alternatives are other registered signatures that also decode the
+	// data; the selector alone can't tell them apart.
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// Resolve decodes data with the signatures d knows and, when the selector
+// is unknown and db is set, with the candidates db returns. A candidate
+// that re-encodes to exactly the same bytes wins over one that merely
+// unpacks, which filters out most selector collisions.
+func (d *Decoder) Resolve(ctx context.Context, data []byte, db *SignatureDB) (*Resolution, error) {
+	c, err := d.Call(data)
+	if err == nil {
+		return &Resolution{Call: c}, nil
+	}
+	if !errors.Is(err, ErrUnknownSelector) || db == nil {
+		return nil, err
+	}
+	sel, _ := Selector(data)
+	sigs, lerr := db.Functions(ctx, sel)
+	if lerr != nil {
+		return nil, fmt.Errorf("%w; %v", err, lerr)
+	}
+
+	var exact, loose []*Call
+	for _, sig := range sigs {
+		cand := Empty()
+		if cand.AddSignature(sig, SourceSignatureDB) != nil {
+			continue
+		}
+		m, ok := cand.methods[sel]
+		if !ok {
+			continue
+		}
+		c, cerr := cand.Call(data)
+		if cerr != nil {
+			continue
+		}
+		if reencodes(m.Inputs, data[4:]) {
+			exact = append(exact, c)
+		} else {
+			loose = append(loose, c)
+		}
+	}
+	matches := append(exact, loose...)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d registered signatures decode the arguments", err, len(sigs))
+	}
+	r := &Resolution{Call: matches[0]}
+	for _, m := range matches[1:] {
+		r.Alternatives = append(r.Alternatives, m.Signature)
+	}
+	// Later lookups of the selector can skip the database.
+	_ = d.AddSignature(r.Signature, SourceSignatureDB)
+	return r, nil
+}
+
+// reencodes reports whether args pack the values unpacked from data back
+// into exactly data.
+func reencodes(args abi.Arguments, data []byte) bool {
+	values, err := args.Unpack(data)
+	if err != nil {
+		return false
+	}
+	packed, err := args.Pack(values...)
+	return err == nil && bytes.Equal(packed, data)
+}
+
+// Text renders the resolution for a terminal: the function, then one
+// argument per line.
+func (r *Resolution) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Function: %s (%s)\nSelector: %s\n", r.Signature, r.Source, r.Selector)
+	if len(r.Args) > 0 {
+		b.WriteString("Arguments:\n")
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for i, a := range r.Args {
+			name := a.Name
+			if name == "" {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, a.Type, a.Value)
+		}
+		_ = tw.Flush()
+	}
+	if len(r.Alternatives) > 0 {
+		fmt.Fprintf(&b, "Other signatures that fit: %s\n", strings.Join(r.Alternatives, ", "))
+	}
+	return b.String()
+}
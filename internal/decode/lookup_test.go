@@ -0,0 +1,69 @@
+package decode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve_SignatureDB(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Query().Get("hex_signature") {
+		case "0xe8e33700":
+			// A later spam registration that does not fit the arguments
+			// comes back first.
+			_, _ = w.Write([]byte(`{"results":[
+				{"id": 900, "text_signature": "addLiquidity(string)"},
+				{"id": 12, "text_signature": "addLiquidity(address,address,uint256,uint256,uint256,uint256,address,uint256)"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"results":[]}`))
+		}
+	}))
+	defer srv.Close()
+	db := &SignatureDB{URL: srv.URL + "/", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	data := common.FromHex("0xe8e33700")
+	for _, v := range [][]byte{alice.Bytes(), bob.Bytes(), {1}, {2}, {3}, {4}, alice.Bytes(), {5}} {
+		data = append(data, word(v)...)
+	}
+	d := New()
+	r, err := d.Resolve(ctx, data, db)
+	require.NoError(t, err)
+	assert.Equal(t, SourceSignatureDB, r.Source)
+	assert.Equal(t, "addLiquidity(address,address,uint256,uint256,uint256,uint256,address,uint256)", r.Signature)
+	assert.Empty(t, r.Alternatives)
+	assert.Equal(t, alice.Hex(), r.Args[0].Value)
+	assert.Contains(t, r.Text(), "arg7  uint256  5")
+
+	// The answer is remembered.
+	_, err = d.Resolve(ctx, data, db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	_, err = d.Resolve(ctx, common.FromHex("0x01020304"), db)
+	assert.True(t, errors.Is(err, ErrUnknownSelector))
+	assert.Contains(t, err.Error(), "none of the 0 registered signatures")
+
+	// Without a database only known signatures decode.
+	_, err = New().Resolve(ctx, data, nil)
+	assert.True(t, errors.Is(err, ErrUnknownSelector))
+}
+
+func TestParseHex(t *testing.T) {
+	data, err := ParseHex(" 0xa9059cbb\n0001 ")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xa9, 0x05, 0x9c, 0xbb, 0, 1}, data)
+	_, err = ParseHex("0xabc")
+	assert.Error(t, err)
+	_, err = ParseHex("0xzz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+package decode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// builtinFunctions are decoded without any ABI: token standards, WETH and
+// the common Uniswap router entry points.
+var builtinFunctions = []string{
+	"transfer(address to,uint256 amount)",
+	"approve(address spender,uint256 amount)",
+	"transferFrom(address from,address to,uint256 amount)",
+	"increaseAllowance(address spender,uint256 addedValue)",
+	"decreaseAllowance(address spender,uint256 subtractedValue)",
+	"permit(address owner,address spender,uint256 value,uint256 deadline,uint8 v,bytes32 r,bytes32 s)",
+	"setApprovalForAll(address operator,bool approved)",
+	"safeTransferFrom(address from,address to,uint256 tokenId)",
+	"safeTransferFrom(address from,address to,uint256 tokenId,bytes data)",
+	"safeTransferFrom(address from,address to,uint256 id,uint256 amount,bytes data)",
+	"deposit()",
+	"withdraw(uint256 amount)",
+	"multicall(bytes[] data)",
+	"multicall(uint256 deadline,bytes[] data)",
+	"execute(bytes commands,bytes[] inputs)",
+	"execute(bytes commands,bytes[] inputs,uint256 deadline)",
+	"swapExactTokensForTokens(uint256 amountIn,uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+	"swapTokensForExactTokens(uint256 amountOut,uint256 amountInMax,address[] path,address to,uint256 deadline)",
+	"swapExactETHForTokens(uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+	"swapExactTokensForETH(uint256 amountIn,uint256 amountOutMin,address[] path,address to,uint256 deadline)",
+	"exactInputSingle((address tokenIn,address tokenOut,uint24 fee,address recipient,uint256 amountIn,uint256 amountOutMinimum,uint160 sqrtPriceLimitX96) params)",
+	"exactInput((bytes path,address recipient,uint256 amountIn,uint256 amountOutMinimum) params)",
+}
+
+var builtinEvents = []string{
+	"Transfer(address indexed from,address indexed to,uint256 value)",
+	"Transfer(address indexed from,address indexed to,uint256 indexed tokenId)",
+	"Approval(address indexed owner,address indexed spender,uint256 value)",
+	"Approval(address indexed owner,address indexed approved,uint256 indexed tokenId)",
+	"ApprovalForAll(address indexed owner,address indexed operator,bool approved)",
+	"TransferSingle(address indexed operator,address indexed from,address indexed to,uint256 id,uint256 value)",
+	"Deposit(address indexed dst,uint256 wad)",
+	"Withdrawal(address indexed src,uint256 wad)",
+	"Swap(address indexed sender,uint256 amount0In,uint256 amount1In,uint256 amount0Out,uint256 amount1Out,address indexed to)",
+	"Swap(address indexed sender,address indexed recipient,int256 amount0,int256 amount1,uint160 sqrtPriceX96,uint128 liquidity,int24 tick)",
+}
+
+// FunctionSelector returns the selector of a text signature, ignoring
+// argument names and spacing.
+func FunctionSelector(sig string) ([4]byte, error) {
+	name, inputs, err := parseSignature(sig)
+	if err != nil {
+		return [4]byte{}, err
+	}
+	return [4]byte(abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil).ID), nil
+}
+
+// parseSignature reads "name(type [indexed] [name],...)". Tuples are
+// written in parentheses, as in "f((address,uint256)[] items)".
+func parseSignature(sig string) (string, abi.Arguments, error) {
+	sig = strings.TrimSpace(sig)
+	open := strings.IndexByte(sig, '(')
+	if open <= 0 || !strings.HasSuffix(sig, ")") {
+		return "", nil, fmt.Errorf("signature %q: want name(types...)", sig)
+	}
+	name := sig[:open]
+	if strings.ContainsAny(name, " ,()") {
+		return "", nil, fmt.Errorf("signature %q: bad name", sig)
+	}
+	params, err := splitParams(sig[open+1 : len(sig)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("signature %q: %w", sig, err)
+	}
+	args := make(abi.Arguments, 0, len(params))
+	for i, p := range params {
+		m, err := parseParam(p, i)
+		if err != nil {
+			return "", nil, fmt.Errorf("signature %q: %w", sig, err)
+		}
+		t, err := abi.NewType(m.Type, "", m.Components)
+		if err != nil {
+			return "", nil, fmt.Errorf("signature %q: %w", sig, err)
+		}
+		args = append(args, abi.Argument{Name: m.Name, Type: t, Indexed: m.Indexed})
+	}
+	return name, args, nil
+}
+
+// parseParam reads one "type [indexed] [name]". Unnamed tuple components
+// get placeholder names, since the ABI package needs them.
+func parseParam(p string, i int) (abi.ArgumentMarshaling, error) {
+	p = strings.TrimSpace(p)
+	var m abi.ArgumentMarshaling
+	rest := p
+	if strings.HasPrefix(p, "(") {
+		end, err := closing(p)
+		if err != nil {
+			return m, err
+		}
+		inner, err := splitParams(p[1:end])
+		if err != nil {
+			return m, err
+		}
+		for j, c := range inner {
+			cm, err := parseParam(c, j)
+			if err != nil {
+				return m, err
+			}
+			if cm.Name == "" {
+				cm.Name = fmt.Sprintf("field%d", j)
+			}
+			m.Components = append(m.Components, cm)
+		}
+		typ, tail, _ := strings.Cut(p[end+1:], " ")
+		m.Type = "tuple" + typ
+		rest = tail
+	} else {
+		typ, tail, _ := strings.Cut(p, " ")
+		m.Type = canonical(typ)
+		rest = tail
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) > 0 && fields[0] == "indexed" {
+		m.Indexed = true
+		fields = fields[1:]
+	}
+	switch len(fields) {
+	case 0:
+	case 1:
+		m.Name = fields[0]
+	default:
+		return m, fmt.Errorf("parameter %d %q: unexpected %q", i, p, strings.Join(fields[1:], " "))
+	}
+	if m.Type == "" {
+		return m, fmt.Errorf("parameter %d is empty", i)
+	}
+	return m, nil
+}
+
+// canonical expands the uint and int aliases, keeping array suffixes.
+func canonical(t string) string {
+	base, suffix := t, ""
+	if i := strings.IndexByte(t, '['); i >= 0 {
+		base, suffix = t[:i], t[i:]
+	}
+	switch base {
+	case "uint":
+		base = "uint256"
+	case "int":
+		base = "int256"
+	}
+	return base + suffix
+}
+
+// splitParams splits on commas outside parentheses.
+func splitParams(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var out []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return append(out, s[start:]), nil
+}
+
+// closing returns the index of the parenthesis closing s[0].
+func closing(s string) (int, error) {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses in %q", s)
+}
@@ -0,0 +1,102 @@
+// Package demo runs clifi offline: a scripted LLM provider drives the real
+// agent and tools against a simulated chain backend, for demos,
+// screenshots and trying clifi without API keys or funds.
+package demo
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// Password unlocks the demo wallet. The script passes it when it confirms
+// a send, since the demo has no real secrets to protect.
+const Password = "demo"
+
+// walletMnemonic is the well-known development mnemonic, so the demo
+// address is recognisable as a test account.
+const walletMnemonic = "test test test test test test test test test test test junk"
+
+// Env is a throwaway data directory wired to a running Sim.
+type Env struct {
+	DataDir string
+	Address common.Address
+	Sim     *Sim
+}
+
+// Start creates a temporary data dir whose chains point at a new Sim and
+// whose keystore holds a funded demo wallet. Close removes both.
+func Start() (*Env, error) {
+	sim, err := StartSim()
+	if err != nil {
+		return nil, fmt.Errorf("start simulated chains: %w", err)
+	}
+	dataDir, err := os.MkdirTemp("", "clifi-demo-*")
+	if err != nil {
+		_ = sim.Close()
+		return nil, err
+	}
+	env := &Env{DataDir: dataDir, Sim: sim}
+	if err := env.init(); err != nil {
+		_ = env.Close()
+		return nil, err
+	}
+	return env, nil
+}
+
+func (e *Env) init() error {
+	settings := &chain.Settings{RPCURLs: make(map[string][]string), RPS: -1}
+	for name := range chain.DefaultChains() {
+		settings.RPCURLs[name] = []string{e.Sim.ChainURL(name)}
+	}
+	if err := settings.Save(e.DataDir); err != nil {
+		return err
+	}
+
+	// The demo wallet is encrypted with light scrypt parameters: unlocking
+	// it for a send would otherwise take most of the send's time budget.
+	key, err := wallet.KeyFromMnemonic(walletMnemonic, accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		return fmt.Errorf("create demo wallet: %w", err)
+	}
+	ks := keystore.NewKeyStore(filepath.Join(e.DataDir, "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, Password)
+	if err != nil {
+		return fmt.Errorf("create demo wallet: %w", err)
+	}
+	km, err := wallet.NewKeystoreManager(e.DataDir)
+	if err != nil {
+		return err
+	}
+	_ = km.SetLabel(account.Address, "demo")
+	e.Address = account.Address
+
+	eth := func(milli int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(milli), big.NewInt(1_000_000_000_000_000))
+	}
+	e.Sim.Fund("ethereum", e.Address, eth(1_250))
+	e.Sim.Fund("base", e.Address, eth(420))
+	e.Sim.Fund("arbitrum", e.Address, eth(75))
+	e.Sim.Fund("optimism", e.Address, eth(30))
+	e.Sim.Fund("polygon", e.Address, eth(58_000))
+	e.Sim.FundToken("ethereum", "USDC", e.Address, 2_500)
+	e.Sim.FundToken("base", "USDC", e.Address, 1_000)
+	e.Sim.FundToken("arbitrum", "ARB", e.Address, 300)
+	return nil
+}
+
+// Close stops the simulator and deletes the data dir.
+func (e *Env) Close() error {
+	err := e.Sim.Close()
+	if rmErr := os.RemoveAll(e.DataDir); err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package demo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+func TestDemo_ScriptRunsAgainstSim(t *testing.T) {
+	env, err := Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = env.Close() })
+	t.Setenv("CLIFI_PRIVATE_TX", "")
+
+	ag := agent.NewWithProvider(Provider(env.Address), env.DataDir)
+	t.Cleanup(ag.Close)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	toolResult := func(msg string) string {
+		t.Helper()
+		events, err := ag.ChatWithEvents(ctx, msg)
+		require.NoError(t, err)
+		var out []string
+		for _, e := range events {
+			if e.Type == "tool_result" {
+				require.False(t, e.IsError, e.Content)
+				out = append(out, e.Content)
+			}
+		}
+		return strings.Join(out, "\n")
+	}
+
+	balances := toolResult("show my balances")
+	assert.Contains(t, balances, "1.25")
+	assert.Contains(t, balances, "USDC")
+
+	preview := toolResult("send 0.05 ETH on base")
+	assert.Contains(t, preview, "Preview")
+
+	sent := toolResult("confirm")
+	assert.Contains(t, sent, "0x", "the broadcast hash is reported")
+
+	after := toolResult("balances again")
+	assert.Contains(t, after, "0.369", "0.42 ETH minus 0.05 and gas")
+
+	events, err := ag.ChatWithEvents(ctx, "what can you do?")
+	require.NoError(t, err)
+	assert.Equal(t, Help, events[len(events)-1].Content)
+}
@@ -0,0 +1,63 @@
+package demo
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// recipient is the demo's send target, the second development account.
+const recipient = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+
+// Help is the reply to anything the script doesn't cover.
+const Help = `This is clifi's offline demo: replies are scripted and the chains are simulated, so nothing here touches a real network. Try:
+- "show my balances"
+- "list my wallets"
+- "what's gas on base?"
+- "send 0.05 ETH on base", then "confirm"
+- "which chains are supported?"`
+
+// Provider returns the scripted provider for a demo wallet at addr.
+func Provider(addr common.Address) *llm.MockProvider {
+	balances := toolTurn("", "get_balances", map[string]any{
+		"address": addr.Hex(),
+		"chains":  []string{"ethereum", "base", "arbitrum", "optimism", "polygon"},
+		"tokens": map[string][]string{
+			"ethereum": {"USDC"},
+			"base":     {"USDC"},
+			"arbitrum": {"ARB"},
+		},
+	}, "Here's the demo wallet across the five mainnets. Ask me to send some, or to check gas first.")
+	balances.Match = "balance"
+	portfolio := balances
+	portfolio.Match = "portfolio"
+
+	return llm.NewMockProvider(
+		balances,
+		portfolio,
+		toolTurn("wallet", "list_wallets", map[string]any{}, "That's the one demo wallet in this throwaway keystore."),
+		toolTurn("gas", "get_gas_price", map[string]any{"chain": "base"}, "Base fees are low right now; a plain ETH transfer costs a fraction of a cent."),
+		toolTurn("confirm", "send_native", map[string]any{
+			"chain": "base", "to": recipient, "amount_eth": "0.05",
+			"confirm": true, "password": Password, "wait": true,
+		}, "Done: the transfer is mined. Ask for your balances again to see it."),
+		toolTurn("send", "send_native", map[string]any{
+			"chain": "base", "to": recipient, "amount_eth": "0.05",
+		}, `That's the preview; nothing is signed yet. Reply "confirm" to sign with the demo wallet and broadcast.`),
+		toolTurn("chain", "list_chains", map[string]any{}, "All of these are simulated in the demo."),
+		llm.MockTurn{Responses: []llm.ChatResponse{{Content: Help}}},
+	)
+}
+
+// toolTurn scripts a turn that calls one tool and then says final.
+func toolTurn(match, tool string, input map[string]any, final string) llm.MockTurn {
+	b, _ := json.Marshal(input)
+	return llm.MockTurn{
+		Match: match,
+		Responses: []llm.ChatResponse{
+			{ToolCalls: []llm.ToolCall{{Name: tool, Input: b}}},
+			{Content: final},
+		},
+	}
+}
@@ -0,0 +1,430 @@
+package demo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// Selectors the simulator answers; every other eth_call returns empty
+// data, which is what a successful call to a plain address returns.
+const (
+	selBalanceOf = "70a08231"
+	selDecimals  = "313ce567"
+	selSymbol    = "95d89b41"
+	selName      = "06fdde03"
+	selTransfer  = "a9059cbb"
+	selApprove   = "095ea7b3"
+	selL1Fee     = "49948e0e" // OP stack GasPriceOracle.getL1Fee
+	selL1Gas     = "77d488a2" // Arbitrum NodeInterface.gasEstimateL1Component
+)
+
+var (
+	gwei       = big.NewInt(1_000_000_000)
+	simGasTip  = gwei
+	simBaseFee = new(big.Int).Mul(big.NewInt(12), gwei)
+)
+
+// Sim is an in-memory JSON-RPC backend for every default chain, served at
+// <URL>/<chain name>. It keeps balances, nonces and receipts so sends show
+// up in later balance checks; there is no EVM, so only native and ERC20
+// transfers change state.
+type Sim struct {
+	URL string
+
+	mu     sync.Mutex
+	server *http.Server
+	chains map[string]*simChain
+}
+
+type simChain struct {
+	id       *big.Int
+	block    uint64
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	tokens   map[common.Address]*simToken
+	txs      map[common.Hash]*simTx
+}
+
+type simToken struct {
+	meta     chain.TokenMetadata
+	balances map[common.Address]*big.Int
+}
+
+type simTx struct {
+	tx    *types.Transaction
+	from  common.Address
+	block uint64
+}
+
+// StartSim serves the simulator on a loopback port until Close.
+func StartSim() (*Sim, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Sim{URL: "http://" + l.Addr().String(), chains: make(map[string]*simChain)}
+	for name, cfg := range chain.DefaultChains() {
+		c := &simChain{
+			id:       cfg.ChainID,
+			block:    20_000_000,
+			balances: make(map[common.Address]*big.Int),
+			nonces:   make(map[common.Address]uint64),
+			tokens:   make(map[common.Address]*simToken),
+			txs:      make(map[common.Hash]*simTx),
+		}
+		for _, meta := range chain.KnownTokens()[name] {
+			c.tokens[common.HexToAddress(meta.Address)] = &simToken{meta: meta, balances: make(map[common.Address]*big.Int)}
+		}
+		s.chains[name] = c
+	}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	go func() { _ = s.server.Serve(l) }()
+	return s, nil
+}
+
+// Close stops the server.
+func (s *Sim) Close() error {
+	return s.server.Close()
+}
+
+// ChainURL is the RPC URL of one simulated chain.
+func (s *Sim) ChainURL(name string) string {
+	return s.URL + "/" + name
+}
+
+// Fund sets an account's native balance on a chain, in wei.
+func (s *Sim) Fund(chainName string, addr common.Address, wei *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c := s.chains[chainName]; c != nil {
+		c.balances[addr] = new(big.Int).Set(wei)
+	}
+}
+
+// FundToken sets an account's balance of a known token, in token units.
+func (s *Sim) FundToken(chainName, symbol string, addr common.Address, amount int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.chains[chainName]
+	if c == nil {
+		return
+	}
+	for _, t := range c.tokens {
+		if strings.EqualFold(t.meta.Symbol, symbol) {
+			unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.meta.Decimals)), nil)
+			t.balances[addr] = new(big.Int).Mul(big.NewInt(amount), unit)
+		}
+	}
+}
+
+type simRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type simResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *simError       `json:"error,omitempty"`
+}
+
+type simError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *Sim) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	c := s.chains[strings.Trim(r.URL.Path, "/")]
+	s.mu.Unlock()
+	if c == nil {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []simRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]simResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i] = s.respond(c, req)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req simRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.respond(c, req))
+}
+
+func (s *Sim) respond(c *simChain, req simRequest) simResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := simResponse{JSONRPC: "2.0", ID: req.ID}
+	result, err := c.call(req.Method, req.Params)
+	if err != nil {
+		resp.Error = &simError{Code: -32000, Message: err.Error()}
+		return resp
+	}
+	if result == nil {
+		// JSON-RPC encodes "not found" as a null result.
+		result = json.RawMessage("null")
+	}
+	resp.Result = result
+	return resp
+}
+
+func (c *simChain) call(method string, params []json.RawMessage) (any, error) {
+	switch method {
+	case "eth_chainId":
+		return (*hexutil.Big)(c.id), nil
+	case "eth_blockNumber":
+		return hexutil.Uint64(c.block), nil
+	case "eth_gasPrice":
+		return (*hexutil.Big)(new(big.Int).Add(simBaseFee, simGasTip)), nil
+	case "eth_maxPriorityFeePerGas":
+		return (*hexutil.Big)(simGasTip), nil
+	case "eth_blobBaseFee":
+		return (*hexutil.Big)(big.NewInt(1)), nil
+	case "eth_getBlockByNumber":
+		return map[string]any{
+			"number":        hexutil.Uint64(c.block),
+			"baseFeePerGas": (*hexutil.Big)(simBaseFee),
+		}, nil
+	case "eth_getBalance":
+		var addr common.Address
+		if err := param(params, 0, &addr); err != nil {
+			return nil, err
+		}
+		return (*hexutil.Big)(balance(c.balances, addr)), nil
+	case "eth_getTransactionCount":
+		var addr common.Address
+		if err := param(params, 0, &addr); err != nil {
+			return nil, err
+		}
+		return hexutil.Uint64(c.nonces[addr]), nil
+	case "eth_getCode":
+		var addr common.Address
+		if err := param(params, 0, &addr); err != nil {
+			return nil, err
+		}
+		if _, ok := c.tokens[addr]; ok {
+			return hexutil.Bytes{0x60, 0x80, 0x60, 0x40}, nil
+		}
+		return hexutil.Bytes{}, nil
+	case "eth_estimateGas":
+		var msg struct {
+			Data  hexutil.Bytes `json:"data"`
+			Input hexutil.Bytes `json:"input"`
+		}
+		if err := param(params, 0, &msg); err != nil {
+			return nil, err
+		}
+		if len(msg.Data) == 0 && len(msg.Input) == 0 {
+			return hexutil.Uint64(21_000), nil
+		}
+		return hexutil.Uint64(52_000), nil
+	case "eth_call":
+		var msg struct {
+			To    *common.Address `json:"to"`
+			Data  hexutil.Bytes   `json:"data"`
+			Input hexutil.Bytes   `json:"input"`
+		}
+		if err := param(params, 0, &msg); err != nil {
+			return nil, err
+		}
+		data := msg.Input
+		if len(data) == 0 {
+			data = msg.Data
+		}
+		return c.ethCall(msg.To, data), nil
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		if err := param(params, 0, &raw); err != nil {
+			return nil, err
+		}
+		return c.send(raw)
+	case "eth_getTransactionReceipt":
+		var hash common.Hash
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		return c.receipt(hash), nil
+	case "eth_getTransactionByHash":
+		var hash common.Hash
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		return c.transaction(hash)
+	}
+	return nil, fmt.Errorf("the demo chain does not support %s", method)
+}
+
+func param(params []json.RawMessage, i int, v any) error {
+	if i >= len(params) {
+		return fmt.Errorf("missing parameter %d", i)
+	}
+	return json.Unmarshal(params[i], v)
+}
+
+func balance(m map[common.Address]*big.Int, addr common.Address) *big.Int {
+	if b, ok := m[addr]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+func (c *simChain) ethCall(to *common.Address, data []byte) hexutil.Bytes {
+	if to == nil || len(data) < 4 {
+		return hexutil.Bytes{}
+	}
+	sel := common.Bytes2Hex(data[:4])
+	switch sel {
+	case selL1Fee:
+		return common.LeftPadBytes(big.NewInt(20_000_000_000_000).Bytes(), 32) // 0.00002 ETH
+	case selL1Gas:
+		out := common.LeftPadBytes(big.NewInt(3_000).Bytes(), 32)
+		return append(out, common.LeftPadBytes(simBaseFee.Bytes(), 32)...)
+	}
+
+	t, ok := c.tokens[*to]
+	if !ok {
+		return hexutil.Bytes{}
+	}
+	switch sel {
+	case selBalanceOf:
+		if len(data) < 36 {
+			return hexutil.Bytes{}
+		}
+		holder := common.BytesToAddress(data[4:36])
+		return common.LeftPadBytes(balance(t.balances, holder).Bytes(), 32)
+	case selDecimals:
+		return common.LeftPadBytes([]byte{t.meta.Decimals}, 32)
+	case selSymbol:
+		return abiString(t.meta.Symbol)
+	case selName:
+		return abiString(t.meta.Name)
+	case selTransfer, selApprove:
+		return common.LeftPadBytes([]byte{1}, 32)
+	}
+	return hexutil.Bytes{}
+}
+
+func abiString(s string) []byte {
+	out := common.LeftPadBytes([]byte{0x20}, 32)
+	out = append(out, common.LeftPadBytes(big.NewInt(int64(len(s))).Bytes(), 32)...)
+	padded := make([]byte, (len(s)+31)/32*32)
+	copy(padded, s)
+	return append(out, padded...)
+}
+
+// send applies a signed transaction and mines it into its own block.
+func (c *simChain) send(raw []byte) (any, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(c.id), tx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if tx.Nonce() != c.nonces[from] {
+		return nil, fmt.Errorf("nonce too low: next nonce %d, tx nonce %d", c.nonces[from], tx.Nonce())
+	}
+	fee := new(big.Int).Mul(new(big.Int).Add(simBaseFee, simGasTip), new(big.Int).SetUint64(tx.Gas()))
+	cost := new(big.Int).Add(tx.Value(), fee)
+	if balance(c.balances, from).Cmp(cost) < 0 {
+		return nil, errors.New("insufficient funds for gas * price + value")
+	}
+
+	c.balances[from] = new(big.Int).Sub(balance(c.balances, from), cost)
+	if to := tx.To(); to != nil {
+		c.balances[*to] = new(big.Int).Add(balance(c.balances, *to), tx.Value())
+		if t, ok := c.tokens[*to]; ok {
+			if err := t.transfer(from, tx.Data()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.nonces[from]++
+	c.block++
+	c.txs[tx.Hash()] = &simTx{tx: tx, from: from, block: c.block}
+	return tx.Hash(), nil
+}
+
+func (t *simToken) transfer(from common.Address, data []byte) error {
+	if len(data) < 68 || common.Bytes2Hex(data[:4]) != selTransfer {
+		return nil
+	}
+	to := common.BytesToAddress(data[4:36])
+	amount := new(big.Int).SetBytes(data[36:68])
+	if balance(t.balances, from).Cmp(amount) < 0 {
+		return errors.New("execution reverted: ERC20: transfer amount exceeds balance")
+	}
+	t.balances[from] = new(big.Int).Sub(balance(t.balances, from), amount)
+	t.balances[to] = new(big.Int).Add(balance(t.balances, to), amount)
+	return nil
+}
+
+func (c *simChain) receipt(hash common.Hash) any {
+	st, ok := c.txs[hash]
+	if !ok {
+		return nil
+	}
+	return &types.Receipt{
+		Type:              st.tx.Type(),
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: st.tx.Gas(),
+		Logs:              []*types.Log{},
+		TxHash:            hash,
+		GasUsed:           st.tx.Gas(),
+		EffectiveGasPrice: new(big.Int).Add(simBaseFee, simGasTip),
+		BlockHash:         common.BigToHash(new(big.Int).SetUint64(st.block)),
+		BlockNumber:       new(big.Int).SetUint64(st.block),
+	}
+}
+
+func (c *simChain) transaction(hash common.Hash) (any, error) {
+	st, ok := c.txs[hash]
+	if !ok {
+		return nil, nil
+	}
+	b, err := st.tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	fields["from"] = st.from
+	fields["blockNumber"] = hexutil.Uint64(st.block)
+	fields["blockHash"] = common.BigToHash(new(big.Int).SetUint64(st.block))
+	return fields, nil
+}
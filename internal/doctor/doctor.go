@@ -0,0 +1,375 @@
+// Package doctor diagnoses a clifi installation: the data directory,
+// provider credentials, chain RPCs, the keystore and the local database.
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/llm"
+
+	_ "modernc.org/sqlite"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+	StatusSkip
+)
+
+// Check is one diagnostic result. Fix, when set, is what the user should
+// do about a warning or failure.
+type Check struct {
+	Group  string
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+// Options selects what Run checks.
+type Options struct {
+	DataDir string
+	// Offline skips the checks that need the network (provider pings and
+	// RPC calls).
+	Offline bool
+	// Ping tests a provider's credentials; nil uses a one-token chat
+	// request.
+	Ping func(ctx context.Context, m *auth.Manager, id llm.ProviderID) error
+}
+
+// Run performs every check and returns the results in display order.
+func Run(ctx context.Context, opts Options) []Check {
+	if opts.Ping == nil {
+		opts.Ping = pingProvider
+	}
+	var checks []Check
+	checks = append(checks, checkDataDir(opts.DataDir)...)
+	checks = append(checks, checkProviders(ctx, opts)...)
+	checks = append(checks, checkChains(ctx, opts)...)
+	checks = append(checks, checkKeystore(opts.DataDir)...)
+	checks = append(checks, checkDatabase(ctx, opts.DataDir)...)
+	return checks
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDataDir(dataDir string) []Check {
+	const group = "Data directory"
+	info, err := os.Stat(dataDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Check{{Group: group, Name: dataDir, Status: StatusFail, Detail: "does not exist", Fix: "run `clifi setup`"}}
+	}
+	if err != nil {
+		return []Check{{Group: group, Name: dataDir, Status: StatusFail, Detail: err.Error()}}
+	}
+	if !info.IsDir() {
+		return []Check{{Group: group, Name: dataDir, Status: StatusFail, Detail: "is not a directory", Fix: "move it aside and run `clifi setup`"}}
+	}
+
+	checks := []Check{permCheck(group, dataDir, info.Mode(), 0700)}
+
+	probe, err := os.CreateTemp(dataDir, ".doctor-*")
+	if err != nil {
+		checks = append(checks, Check{Group: group, Name: "writable", Status: StatusFail, Detail: err.Error(),
+			Fix: fmt.Sprintf("chown -R $(whoami) %s", dataDir)})
+	} else {
+		_ = probe.Close()
+		_ = os.Remove(probe.Name())
+		checks = append(checks, Check{Group: group, Name: "writable", Status: StatusOK})
+	}
+
+	for _, name := range []string{"auth.json", "chains.yaml", "wallets.json"} {
+		path := filepath.Join(dataDir, name)
+		if info, err := os.Stat(path); err == nil {
+			checks = append(checks, permCheck(group, path, info.Mode(), 0600))
+		}
+	}
+	return checks
+}
+
+// permCheck warns when group or others can access a private path.
+func permCheck(group, path string, mode os.FileMode, want os.FileMode) Check {
+	c := Check{Group: group, Name: path, Status: StatusOK, Detail: fmt.Sprintf("mode %04o", mode.Perm())}
+	if runtime.GOOS == "windows" {
+		// Unix permission bits don't describe Windows ACLs.
+		return c
+	}
+	if mode.Perm()&0077 != 0 {
+		c.Status = StatusWarn
+		c.Detail += ", readable by other users"
+		c.Fix = fmt.Sprintf("chmod %o %s", want, path)
+	}
+	return c
+}
+
+func checkProviders(ctx context.Context, opts Options) []Check {
+	const group = "Providers"
+	m, err := auth.NewManager(opts.DataDir)
+	if err != nil {
+		return []Check{{Group: group, Name: "auth.json", Status: StatusFail, Detail: err.Error(),
+			Fix: "fix or remove auth.json, then run `clifi auth connect <provider>`"}}
+	}
+	connected := m.ListConnected()
+	if len(connected) == 0 {
+		return []Check{{Group: group, Name: "credentials", Status: StatusFail, Detail: "no provider connected",
+			Fix: "run `clifi setup` or set an API key variable such as ANTHROPIC_API_KEY"}}
+	}
+	sort.Slice(connected, func(i, j int) bool { return connected[i] < connected[j] })
+
+	checks := make([]Check, len(connected))
+	var wg sync.WaitGroup
+	for i, id := range connected {
+		checks[i] = Check{Group: group, Name: string(id)}
+		if id == m.GetDefaultProvider() {
+			checks[i].Name += " (default)"
+		}
+		if opts.Offline {
+			checks[i].Status = StatusSkip
+			checks[i].Detail = "credential present, not pinged (offline)"
+			continue
+		}
+		wg.Add(1)
+		go func(c *Check, id llm.ProviderID) {
+			defer wg.Done()
+			pingCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+			defer cancel()
+			start := time.Now()
+			if err := opts.Ping(pingCtx, m, id); err != nil {
+				c.Status = StatusFail
+				c.Detail = err.Error()
+				c.Fix = fmt.Sprintf("reconnect with `clifi auth connect %s`", id)
+				return
+			}
+			c.Status = StatusOK
+			c.Detail = fmt.Sprintf("responded in %s", time.Since(start).Round(time.Millisecond))
+		}(&checks[i], id)
+	}
+	wg.Wait()
+	return checks
+}
+
+// pingProvider makes the smallest possible chat request.
+func pingProvider(ctx context.Context, m *auth.Manager, id llm.ProviderID) error {
+	provider, err := agent.CreateProvider(m, id)
+	if err != nil {
+		return err
+	}
+	if gemini, ok := provider.(*llm.GeminiProvider); ok {
+		defer func() { _ = gemini.Close() }()
+	}
+	_, err = provider.Chat(ctx, &llm.ChatRequest{
+		Messages:  []llm.Message{{Role: "user", Content: "ping"}},
+		MaxTokens: 1,
+	})
+	return err
+}
+
+func checkChains(ctx context.Context, opts Options) []Check {
+	const group = "Chains"
+	client, err := chain.NewConfiguredClient(opts.DataDir)
+	if err != nil {
+		return []Check{{Group: group, Name: chain.SettingsFile, Status: StatusFail, Detail: err.Error(),
+			Fix: fmt.Sprintf("fix %s or rerun `clifi setup`", filepath.Join(opts.DataDir, chain.SettingsFile))}}
+	}
+	defer client.Close()
+
+	names := client.ListChains()
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		cfg, _ := client.GetChainConfig(name)
+		checks[i] = Check{Group: group, Name: name}
+		if opts.Offline {
+			checks[i].Status = StatusSkip
+			checks[i].Detail = fmt.Sprintf("%d RPC URL(s), not contacted (offline)", len(cfg.RPCURLs))
+			continue
+		}
+		wg.Add(1)
+		go func(c *Check, cfg *chain.ChainConfig) {
+			defer wg.Done()
+			*c = checkChainRPCs(ctx, c.Name, cfg)
+		}(&checks[i], cfg)
+	}
+	wg.Wait()
+	return checks
+}
+
+// checkChainRPCs checks each RPC URL answers with the chain's ID. One
+// working URL is enough to use the chain, so partial failures only warn.
+func checkChainRPCs(ctx context.Context, name string, cfg *chain.ChainConfig) Check {
+	c := Check{Group: "Chains", Name: name}
+	var failed []string
+	for _, url := range cfg.RPCURLs {
+		rpcCtx, cancel := context.WithTimeout(ctx, 8*time.Second)
+		err := chain.ValidateRPCURL(rpcCtx, url, cfg.ChainID)
+		cancel()
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
+		}
+	}
+	ok := len(cfg.RPCURLs) - len(failed)
+	switch {
+	case len(cfg.RPCURLs) == 0:
+		c.Status = StatusFail
+		c.Detail = "no RPC URLs configured"
+	case ok == 0:
+		c.Status = StatusFail
+		c.Detail = strings.Join(failed, "; ")
+	case len(failed) > 0:
+		c.Status = StatusWarn
+		c.Detail = fmt.Sprintf("%d/%d RPCs ok; %s", ok, len(cfg.RPCURLs), strings.Join(failed, "; "))
+	default:
+		c.Status = StatusOK
+		c.Detail = fmt.Sprintf("%d/%d RPCs ok, chain ID %s", ok, len(cfg.RPCURLs), cfg.ChainID)
+	}
+	if c.Status != StatusOK {
+		c.Fix = "set a working RPC for " + name + " in the `clifi setup` chain step"
+	}
+	return c
+}
+
+// keyFile is the part of a V3 keystore file that must be present for
+// go-ethereum to load it. Files that fail to parse are silently skipped
+// by the keystore, which looks like a lost wallet.
+type keyFile struct {
+	Address string          `json:"address"`
+	Crypto  json.RawMessage `json:"crypto"`
+	Legacy  json.RawMessage `json:"Crypto"`
+	Version int             `json:"version"`
+}
+
+func checkKeystore(dataDir string) []Check {
+	const group = "Keystore"
+	dir := filepath.Join(dataDir, "keystore")
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Check{{Group: group, Name: dir, Status: StatusSkip, Detail: "no keystore yet"}}
+	}
+	if err != nil {
+		return []Check{{Group: group, Name: dir, Status: StatusFail, Detail: err.Error()}}
+	}
+
+	info, _ := os.Stat(dir)
+	checks := []Check{permCheck(group, dir, info.Mode(), 0700)}
+	addresses := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		c := Check{Group: group, Name: e.Name(), Status: StatusOK}
+		var kf keyFile
+		b, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			c.Status, c.Detail = StatusFail, err.Error()
+		case json.Unmarshal(b, &kf) != nil:
+			c.Status, c.Detail = StatusFail, "not valid JSON"
+		case !common.IsHexAddress(kf.Address):
+			c.Status, c.Detail = StatusFail, "missing or invalid address"
+		case kf.Version != 3 || (len(kf.Crypto) == 0 && len(kf.Legacy) == 0):
+			c.Status, c.Detail = StatusFail, fmt.Sprintf("not a version 3 keystore (version %d)", kf.Version)
+		default:
+			addr := common.HexToAddress(kf.Address).Hex()
+			c.Detail = addr
+			if addresses[addr] {
+				c.Status = StatusWarn
+				c.Detail += " (duplicate)"
+				c.Fix = "remove one of the duplicate files; clifi uses the first it finds"
+			}
+			addresses[addr] = true
+		}
+		if c.Status == StatusFail {
+			c.Fix = fmt.Sprintf("move %s out of the keystore directory; clifi ignores it", path)
+		}
+		checks = append(checks, c)
+	}
+	if len(addresses) == 0 {
+		checks = append(checks, Check{Group: group, Name: "wallets", Status: StatusWarn, Detail: "no usable wallets",
+			Fix: "run `clifi wallet create` or `clifi wallet import`"})
+	}
+
+	var book struct {
+		Default string `json:"default"`
+	}
+	if b, err := os.ReadFile(filepath.Join(dataDir, "wallets.json")); err == nil {
+		if err := json.Unmarshal(b, &book); err != nil {
+			checks = append(checks, Check{Group: group, Name: "wallets.json", Status: StatusFail, Detail: "not valid JSON",
+				Fix: "remove wallets.json; labels and the default wallet will need to be set again"})
+		} else if book.Default != "" && !addresses[book.Default] {
+			checks = append(checks, Check{Group: group, Name: "default wallet", Status: StatusWarn,
+				Detail: book.Default + " is not in the keystore", Fix: "pick another with /wallet use <wallet> in the REPL"})
+		}
+	}
+	return checks
+}
+
+func checkDatabase(ctx context.Context, dataDir string) []Check {
+	const group = "Database"
+	path := filepath.Join(dataDir, "receipts.db")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return []Check{{Group: group, Name: "receipts.db", Status: StatusSkip, Detail: "not created yet"}}
+	}
+
+	c := Check{Group: group, Name: "receipts.db"}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		c.Status, c.Detail = StatusFail, err.Error()
+		return []Check{c}
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		c.Status, c.Detail = StatusFail, err.Error()
+		c.Fix = "the file may be locked by another clifi process or not a database; close clifi and retry, or move it aside"
+		return []Check{c}
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err == nil && line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		c.Status = StatusFail
+		c.Detail = strings.Join(problems, "; ")
+		c.Fix = fmt.Sprintf("move %s aside; clifi recreates it (stored receipts and token metadata are lost)", path)
+		return []Check{c}
+	}
+	c.Status, c.Detail = StatusOK, "integrity check passed"
+	return []Check{c}
+}
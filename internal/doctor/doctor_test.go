@@ -0,0 +1,135 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func find(t *testing.T, checks []Check, group, name string) Check {
+	t.Helper()
+	for _, c := range checks {
+		if c.Group == group && c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %s check named %s in %+v", group, name, checks)
+	return Check{}
+}
+
+func TestRun(t *testing.T) {
+	for _, p := range llm.AllProviderIDs() {
+		t.Setenv(llm.EnvVarForProvider(p), "")
+	}
+	dir := testutil.TempDir(t)
+	require.NoError(t, os.Chmod(dir, 0700))
+
+	m, err := auth.NewManager(dir)
+	require.NoError(t, err)
+	require.NoError(t, m.SetAPIKey(llm.ProviderOpenAI, "sk-good"))
+	require.NoError(t, m.SetAPIKey(llm.ProviderVenice, "bad"))
+	require.NoError(t, m.SetDefaultProvider(llm.ProviderOpenAI))
+
+	ks := filepath.Join(dir, "keystore")
+	require.NoError(t, os.MkdirAll(ks, 0700))
+	good := `{"address":"f39fd6e51aad88f6f4ce6ab8827279cfffb92266","crypto":{"cipher":"aes-128-ctr"},"version":3}`
+	require.NoError(t, os.WriteFile(filepath.Join(ks, "good.json"), []byte(good), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(ks, "broken.json"), []byte("{"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wallets.json"), []byte(`{"default":"0x70997970C51812dc3A010C7d01b50e0d17dc79C8"}`), 0644))
+
+	rs, err := agent.OpenReceiptStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, rs.Close())
+
+	checks := Run(context.Background(), Options{
+		DataDir: dir,
+		Offline: true,
+		Ping: func(_ context.Context, m *auth.Manager, id llm.ProviderID) error {
+			if key, _ := m.GetAPIKey(id); key == "bad" {
+				return errors.New("401 unauthorized")
+			}
+			return nil
+		},
+	})
+
+	assert.Equal(t, StatusOK, find(t, checks, "Data directory", dir).Status)
+	perms := find(t, checks, "Data directory", filepath.Join(dir, "wallets.json"))
+	assert.Equal(t, StatusWarn, perms.Status)
+	assert.Contains(t, perms.Fix, "chmod 600")
+
+	assert.Equal(t, StatusSkip, find(t, checks, "Providers", "openai (default)").Status, "offline skips pings")
+	assert.Equal(t, StatusSkip, find(t, checks, "Chains", "ethereum").Status)
+
+	assert.Equal(t, StatusOK, find(t, checks, "Keystore", "good.json").Status)
+	broken := find(t, checks, "Keystore", "broken.json")
+	assert.Equal(t, StatusFail, broken.Status)
+	assert.Contains(t, broken.Fix, "move")
+	assert.Equal(t, StatusWarn, find(t, checks, "Keystore", "default wallet").Status)
+
+	assert.Equal(t, StatusOK, find(t, checks, "Database", "receipts.db").Status)
+	assert.True(t, Failed(checks))
+
+	online := checkProviders(context.Background(), Options{DataDir: dir, Ping: func(_ context.Context, m *auth.Manager, id llm.ProviderID) error {
+		if id == llm.ProviderVenice {
+			return errors.New("401 unauthorized")
+		}
+		return nil
+	}})
+	assert.Equal(t, StatusOK, find(t, online, "Providers", "openai (default)").Status)
+	venice := find(t, online, "Providers", "venice")
+	assert.Equal(t, StatusFail, venice.Status)
+	assert.Contains(t, venice.Fix, "clifi auth connect venice")
+}
+
+func TestCheckDatabaseCorrupt(t *testing.T) {
+	dir := testutil.TempDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "receipts.db"), []byte("not a database, just text padding it out"), 0600))
+
+	checks := checkDatabase(context.Background(), dir)
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatusFail, checks[0].Status)
+	assert.NotEmpty(t, checks[0].Fix)
+}
+
+func TestCheckChainRPCs(t *testing.T) {
+	rpc := func(chainID string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var req struct {
+				ID json.RawMessage `json:"id"`
+			}
+			_ = json.Unmarshal(body, &req)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.ID, chainID)
+		}))
+	}
+	base, mainnet := rpc("0x2105"), rpc("0x1")
+	defer base.Close()
+	defer mainnet.Close()
+
+	cfg := &chain.ChainConfig{ChainID: big.NewInt(8453), RPCURLs: []string{base.URL}}
+	assert.Equal(t, StatusOK, checkChainRPCs(context.Background(), "base", cfg).Status)
+
+	cfg.RPCURLs = []string{base.URL, mainnet.URL}
+	c := checkChainRPCs(context.Background(), "base", cfg)
+	assert.Equal(t, StatusWarn, c.Status)
+	assert.Contains(t, c.Detail, "expected 8453")
+
+	cfg.RPCURLs = []string{mainnet.URL}
+	assert.Equal(t, StatusFail, checkChainRPCs(context.Background(), "base", cfg).Status)
+}
@@ -0,0 +1,152 @@
+// Package faucet requests testnet funds from configured faucet endpoints.
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Faucet is an HTTP endpoint that drips testnet funds. clifi POSTs
+// {"address", "chain", "chain_id"} as JSON and treats any 2xx as accepted.
+type Faucet struct {
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+}
+
+// Result describes an accepted faucet request.
+type Result struct {
+	Faucet string
+	// TxHash is set when the faucet reports the funding transaction.
+	TxHash string
+}
+
+// BalanceSource is the subset of chain.Client used to watch for funds.
+type BalanceSource interface {
+	GetBalance(ctx context.Context, chainName string, address common.Address) (*big.Int, error)
+	InvalidateBalance(chainName string, address common.Address)
+}
+
+// Configured returns the faucets configured for a chain under
+// faucets.<chain> in config.yaml, e.g.
+//
+//	faucets:
+//	  sepolia:
+//	    - name: team-faucet
+//	      url: https://faucet.example.com/api/claim
+func Configured(chainName string) []Faucet {
+	var faucets []Faucet
+	if err := viper.UnmarshalKey("faucets."+chainName, &faucets); err != nil {
+		return nil
+	}
+	out := faucets[:0]
+	for _, f := range faucets {
+		if f.URL == "" {
+			continue
+		}
+		if f.Name == "" {
+			f.Name = f.URL
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// Request asks each faucet in order until one accepts. Public faucets are
+// frequently rate limited or down, so failures fall through to the next.
+func Request(ctx context.Context, httpClient *http.Client, faucets []Faucet, chainName string, chainID int64, address common.Address) (*Result, error) {
+	if len(faucets) == 0 {
+		return nil, fmt.Errorf("no faucets configured for %s (set faucets.%s in config.yaml)", chainName, chainName)
+	}
+
+	var errs []error
+	for _, f := range faucets {
+		txHash, err := requestOne(ctx, httpClient, f, chainName, chainID, address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Name, err))
+			continue
+		}
+		return &Result{Faucet: f.Name, TxHash: txHash}, nil
+	}
+	return nil, fmt.Errorf("all faucets failed: %w", errors.Join(errs...))
+}
+
+func requestOne(ctx context.Context, httpClient *http.Client, f Faucet, chainName string, chainID int64, address common.Address) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"address":  address.Hex(),
+		"chain":    chainName,
+		"chain_id": chainID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(respBody))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return "", fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+
+	// Faucets disagree on naming; accept the common spellings.
+	var parsed struct {
+		TxHash      string `json:"tx_hash"`
+		TxHashCamel string `json:"txHash"`
+		Hash        string `json:"hash"`
+	}
+	_ = json.Unmarshal(respBody, &parsed)
+	for _, h := range []string{parsed.TxHash, parsed.TxHashCamel, parsed.Hash} {
+		if h != "" {
+			return h, nil
+		}
+	}
+	return "", nil
+}
+
+// WaitForFunds polls until address's balance rises above baseline, returning
+// the new balance. The balance cache is bypassed on every poll.
+func WaitForFunds(ctx context.Context, src BalanceSource, chainName string, address common.Address, baseline *big.Int, interval time.Duration) (*big.Int, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("funds did not arrive: %w", ctx.Err())
+		case <-ticker.C:
+			src.InvalidateBalance(chainName, address)
+			bal, err := src.GetBalance(ctx, chainName, address)
+			if err != nil {
+				// Transient RPC errors shouldn't end the wait.
+				continue
+			}
+			if bal.Cmp(baseline) > 0 {
+				return bal, nil
+			}
+		}
+	}
+}
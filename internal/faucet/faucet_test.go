@@ -0,0 +1,116 @@
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigured(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.Set("faucets", map[string]interface{}{
+		"sepolia": []map[string]interface{}{
+			{"name": "primary", "url": "https://a.example/claim"},
+			{"url": "https://b.example/claim"},
+			{"name": "broken"},
+		},
+	})
+
+	faucets := Configured("sepolia")
+	require.Len(t, faucets, 2)
+	assert.Equal(t, "primary", faucets[0].Name)
+	assert.Equal(t, "https://b.example/claim", faucets[1].Name, "name defaults to URL")
+
+	assert.Empty(t, Configured("base-sepolia"))
+}
+
+func TestRequest(t *testing.T) {
+	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	var got map[string]interface{}
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"txHash":"0xabc"}`))
+	}))
+	defer ok.Close()
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+
+	t.Run("falls through to next faucet", func(t *testing.T) {
+		res, err := Request(context.Background(), http.DefaultClient, []Faucet{
+			{Name: "limited", URL: limited.URL},
+			{Name: "ok", URL: ok.URL},
+		}, "sepolia", 11155111, addr)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", res.Faucet)
+		assert.Equal(t, "0xabc", res.TxHash)
+		assert.Equal(t, addr.Hex(), got["address"])
+		assert.Equal(t, "sepolia", got["chain"])
+		assert.Equal(t, float64(11155111), got["chain_id"])
+	})
+
+	t.Run("all failing reports each faucet", func(t *testing.T) {
+		_, err := Request(context.Background(), http.DefaultClient, []Faucet{{Name: "limited", URL: limited.URL}}, "sepolia", 11155111, addr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limited: status 429")
+	})
+
+	t.Run("none configured", func(t *testing.T) {
+		_, err := Request(context.Background(), http.DefaultClient, nil, "sepolia", 11155111, addr)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "faucets.sepolia")
+	})
+}
+
+type fakeBalances struct {
+	mu          sync.Mutex
+	balances    []*big.Int
+	calls       int
+	invalidated int
+}
+
+func (f *fakeBalances) GetBalance(ctx context.Context, chainName string, address common.Address) (*big.Int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bal := f.balances[min(f.calls, len(f.balances)-1)]
+	f.calls++
+	return bal, nil
+}
+
+func (f *fakeBalances) InvalidateBalance(chainName string, address common.Address) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.invalidated++
+}
+
+func TestWaitForFunds(t *testing.T) {
+	t.Run("returns once balance rises", func(t *testing.T) {
+		src := &fakeBalances{balances: []*big.Int{big.NewInt(0), big.NewInt(0), big.NewInt(5)}}
+		bal, err := WaitForFunds(context.Background(), src, "sepolia", common.Address{}, big.NewInt(0), time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), bal.Int64())
+		assert.Equal(t, src.calls, src.invalidated, "every poll bypasses the cache")
+	})
+
+	t.Run("times out when nothing arrives", func(t *testing.T) {
+		src := &fakeBalances{balances: []*big.Int{big.NewInt(1)}}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := WaitForFunds(ctx, src, "sepolia", common.Address{}, big.NewInt(1), time.Millisecond)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,304 @@
+// Package gasreport summarizes gas spent by stored transactions per chain
+// and month, comparing the price paid with the block's base fee to flag
+// overpayments.
+package gasreport
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultThreshold is the premium over the base fee, as a fraction, above
+// which a transaction counts as overpaid: paying more than twice the base
+// fee.
+const DefaultThreshold = 1.0
+
+// MinTip is the priority fee per gas, in wei, below which a transaction is
+// never flagged. L2 base fees are so low that any tip is a large premium
+// while costing next to nothing.
+var MinTip = big.NewInt(100_000_000) // 0.1 gwei
+
+// Tx is one mined transaction. BaseFee is nil when the block has none
+// (pre-EIP-1559 chains) or it could not be looked up. FeeUSD is the fee's
+// USD value when it was recorded, zero when unknown.
+type Tx struct {
+	Chain   string
+	Hash    string
+	From    string
+	Symbol  string // native currency
+	At      time.Time
+	GasUsed uint64
+	Price   *big.Int // effective gas price, wei
+	BaseFee *big.Int
+	FeeUSD  float64
+}
+
+// Fee is the gas paid in wei, excluding any rollup L1 data fee.
+func (t Tx) Fee() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(t.GasUsed), t.Price)
+}
+
+// Tip is the price paid above the base fee per gas, or nil without one.
+func (t Tx) Tip() *big.Int {
+	if t.BaseFee == nil {
+		return nil
+	}
+	tip := new(big.Int).Sub(t.Price, t.BaseFee)
+	if tip.Sign() < 0 {
+		tip.SetInt64(0)
+	}
+	return tip
+}
+
+// Premium is the tip as a fraction of the base fee.
+func (t Tx) Premium() (float64, bool) {
+	tip := t.Tip()
+	if tip == nil || t.BaseFee.Sign() == 0 {
+		return 0, false
+	}
+	p, _ := new(big.Rat).SetFrac(tip, t.BaseFee).Float64()
+	return p, true
+}
+
+// Month is the gas spent on one chain in one calendar month (UTC).
+type Month struct {
+	Chain   string
+	Symbol  string
+	Month   string // YYYY-MM
+	Txs     int
+	GasUsed uint64
+	Fee     *big.Int
+	FeeUSD  float64
+	// Unpriced counts transactions without a recorded USD fee.
+	Unpriced int
+
+	// Gas-weighted sums for the averages. Transactions without a base
+	// fee are left out of both base sums.
+	baseGas  uint64
+	basePaid *big.Int
+	baseSum  *big.Int
+}
+
+// AvgPrice is the average effective gas price, weighted by gas used.
+func (m Month) AvgPrice() *big.Int {
+	if m.GasUsed == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(m.Fee, new(big.Int).SetUint64(m.GasUsed))
+}
+
+// AvgVsBase returns the average price paid and the average base fee over
+// the transactions whose base fee is known, both weighted by gas used.
+func (m Month) AvgVsBase() (paid, base *big.Int, ok bool) {
+	if m.baseGas == 0 {
+		return nil, nil, false
+	}
+	gas := new(big.Int).SetUint64(m.baseGas)
+	return new(big.Int).Div(m.basePaid, gas), new(big.Int).Div(m.baseSum, gas), true
+}
+
+// Overpayment is a transaction whose premium exceeded the threshold.
+type Overpayment struct {
+	Tx
+	Premium float64
+	// Excess is the tip paid in wei: what the transaction cost above the
+	// base fee.
+	Excess *big.Int
+}
+
+// Report is the gas spent by a set of transactions.
+type Report struct {
+	Months    []Month // by chain, then month
+	Overpaid  []Overpayment
+	Threshold float64
+	// NoBaseFee counts transactions compared with no base fee.
+	NoBaseFee int
+	// Unresolved counts stored receipts whose sender or block could not be
+	// looked up; they are left out.
+	Unresolved int
+}
+
+// Build groups txs by chain and month and flags those that paid more than
+// threshold over the base fee.
+func Build(txs []Tx, threshold float64) Report {
+	r := Report{Threshold: threshold}
+	index := make(map[string]int)
+	for _, t := range txs {
+		if t.Price == nil {
+			continue
+		}
+		month := t.At.UTC().Format("2006-01")
+		key := t.Chain + "/" + month
+		i, ok := index[key]
+		if !ok {
+			i = len(r.Months)
+			index[key] = i
+			r.Months = append(r.Months, Month{Chain: t.Chain, Symbol: t.Symbol, Month: month,
+				Fee: new(big.Int), basePaid: new(big.Int), baseSum: new(big.Int)})
+		}
+		m := &r.Months[i]
+		m.Txs++
+		m.GasUsed += t.GasUsed
+		m.Fee.Add(m.Fee, t.Fee())
+		m.FeeUSD += t.FeeUSD
+		if t.FeeUSD == 0 {
+			m.Unpriced++
+		}
+
+		if t.BaseFee == nil {
+			r.NoBaseFee++
+			continue
+		}
+		gas := new(big.Int).SetUint64(t.GasUsed)
+		m.baseGas += t.GasUsed
+		m.basePaid.Add(m.basePaid, new(big.Int).Mul(gas, t.Price))
+		m.baseSum.Add(m.baseSum, new(big.Int).Mul(gas, t.BaseFee))
+
+		premium, ok := t.Premium()
+		if ok && premium > threshold && t.Tip().Cmp(MinTip) > 0 {
+			r.Overpaid = append(r.Overpaid, Overpayment{Tx: t, Premium: premium, Excess: new(big.Int).Mul(gas, t.Tip())})
+		}
+	}
+	sort.SliceStable(r.Months, func(i, j int) bool {
+		if r.Months[i].Chain != r.Months[j].Chain {
+			return r.Months[i].Chain < r.Months[j].Chain
+		}
+		return r.Months[i].Month < r.Months[j].Month
+	})
+	sort.SliceStable(r.Overpaid, func(i, j int) bool { return r.Overpaid[i].At.Before(r.Overpaid[j].At) })
+	return r
+}
+
+// Headers are the column names for Rows.
+var Headers = []string{"Chain", "Month", "Txs", "Gas spent", "USD", "Avg paid", "Avg base fee", "Premium"}
+
+// Rows renders the months as table rows, gas prices in gwei.
+func (r Report) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Months))
+	for _, m := range r.Months {
+		usd := "$" + strconv.FormatFloat(m.FeeUSD, 'f', 2, 64)
+		if m.Unpriced == m.Txs {
+			usd = "n/a"
+		}
+		avgBase, premium := "n/a", "n/a"
+		if paid, base, ok := m.AvgVsBase(); ok {
+			avgBase = gwei(base)
+			if base.Sign() > 0 {
+				p, _ := new(big.Rat).SetFrac(new(big.Int).Sub(paid, base), base).Float64()
+				premium = percent(p)
+			}
+		}
+		rows = append(rows, []string{
+			m.Chain, m.Month, strconv.Itoa(m.Txs), ether(m.Fee) + " " + m.Symbol, usd, gwei(m.AvgPrice()), avgBase, premium,
+		})
+	}
+	return rows
+}
+
+// Notes lists caveats about the numbers, one per line.
+func (r Report) Notes() []string {
+	var notes []string
+	if r.NoBaseFee > 0 {
+		notes = append(notes, fmt.Sprintf("%d transactions have no base fee to compare with (legacy chains or blocks that could not be fetched).", r.NoBaseFee))
+	}
+	if r.Unresolved > 0 {
+		notes = append(notes, r.unresolvedNote())
+	}
+	notes = append(notes, "Rollup L1 data fees are not included.")
+	return notes
+}
+
+func (r Report) unresolvedNote() string {
+	return fmt.Sprintf("%d stored receipts were skipped because their sender or block could not be fetched; run again when the RPC is reachable.", r.Unresolved)
+}
+
+// Text renders the report as plain text.
+func (r Report) Text() string {
+	var b strings.Builder
+	if len(r.Months) == 0 {
+		b.WriteString("No transactions recorded yet. clifi keeps receipts of transactions it sends or fetches receipts for.\n")
+		if r.Unresolved > 0 {
+			b.WriteString("\nNote: " + r.unresolvedNote() + "\n")
+		}
+		return b.String()
+	}
+	b.WriteString("Gas spent\n\n")
+	writeTable(&b, Headers, r.Rows(), 2)
+
+	b.WriteString(fmt.Sprintf("\nOverpaid (more than %s over the base fee): ", percent(r.Threshold)))
+	if len(r.Overpaid) == 0 {
+		b.WriteString("none\n")
+	} else {
+		b.WriteString(strconv.Itoa(len(r.Overpaid)) + "\n\n")
+		rows := make([][]string, 0, len(r.Overpaid))
+		for _, o := range r.Overpaid {
+			rows = append(rows, []string{
+				o.Chain, o.At.UTC().Format(time.DateOnly), o.Hash, gwei(o.Price), gwei(o.BaseFee), percent(o.Premium), ether(o.Excess) + " " + o.Symbol,
+			})
+		}
+		writeTable(&b, []string{"Chain", "Date", "Tx", "Paid", "Base fee", "Premium", "Tip paid"}, rows, 3)
+		b.WriteString("\nA lower priority fee (or your wallet's \"slow\" setting) would likely have been mined as fast.\n")
+	}
+	for _, n := range r.Notes() {
+		b.WriteString("\nNote: " + n)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// writeTable aligns rows under headers, right-aligning columns from
+// rightFrom on.
+func writeTable(b *strings.Builder, headers []string, rows [][]string, rightFrom int) {
+	rows = append([][]string{headers}, rows...)
+	widths := make([]int, len(headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if i >= rightFrom {
+				b.WriteString(pad + cell)
+			} else {
+				b.WriteString(cell + pad)
+			}
+			if i < len(row)-1 {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+}
+
+// gwei formats wei per gas in gwei, keeping the precision L2 prices need.
+func gwei(wei *big.Int) string {
+	v, _ := new(big.Rat).SetFrac(wei, big.NewInt(1e9)).Float64()
+	switch {
+	case v == 0:
+		return "0 gwei"
+	case v >= 1:
+		return strconv.FormatFloat(v, 'f', 2, 64) + " gwei"
+	default:
+		return strconv.FormatFloat(v, 'g', 3, 64) + " gwei"
+	}
+}
+
+func ether(wei *big.Int) string {
+	v, _ := new(big.Rat).SetFrac(wei, big.NewInt(1e18)).Float64()
+	s := strconv.FormatFloat(v, 'f', 6, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "0" && v > 0 {
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	}
+	return s
+}
+
+func percent(f float64) string {
+	return strconv.FormatFloat(f*100, 'f', 0, 64) + "%"
+}
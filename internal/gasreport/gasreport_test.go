@@ -0,0 +1,78 @@
+package gasreport
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func inGwei(v float64) *big.Int {
+	return big.NewInt(int64(math.Round(v * 1e9)))
+}
+
+func TestBuild(t *testing.T) {
+	jan := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	txs := []Tx{
+		// Paid 3 gwei at a 1 gwei base fee: 200% premium, flagged.
+		{Chain: "ethereum", Hash: "0x1", Symbol: "ETH", At: jan, GasUsed: 21000, Price: inGwei(3), BaseFee: inGwei(1), FeeUSD: 0.2},
+		// 1.2 gwei at 1 gwei: fine.
+		{Chain: "ethereum", Hash: "0x2", Symbol: "ETH", At: jan.Add(time.Hour), GasUsed: 63000, Price: inGwei(1.2), BaseFee: inGwei(1)},
+		{Chain: "ethereum", Hash: "0x3", Symbol: "ETH", At: feb, GasUsed: 21000, Price: inGwei(2), BaseFee: inGwei(2)},
+		// A large premium on a tiny L2 base fee, but under MinTip.
+		{Chain: "base", Hash: "0x4", Symbol: "ETH", At: feb, GasUsed: 100000, Price: inGwei(0.011), BaseFee: inGwei(0.001)},
+		// Legacy chain: no base fee.
+		{Chain: "bsc", Hash: "0x5", Symbol: "BNB", At: feb, GasUsed: 21000, Price: inGwei(1)},
+	}
+
+	r := Build(txs, DefaultThreshold)
+	require.Len(t, r.Months, 4)
+	assert.Equal(t, []string{"base", "bsc", "ethereum", "ethereum"},
+		[]string{r.Months[0].Chain, r.Months[1].Chain, r.Months[2].Chain, r.Months[3].Chain})
+
+	jm := r.Months[2]
+	assert.Equal(t, "2026-01", jm.Month)
+	assert.Equal(t, 2, jm.Txs)
+	assert.Equal(t, uint64(84000), jm.GasUsed)
+	// 21000*3 + 63000*1.2 gwei
+	assert.Equal(t, inGwei(21000*3+63000*1.2).String(), jm.Fee.String())
+	assert.Equal(t, 1, jm.Unpriced)
+	paid, base, ok := jm.AvgVsBase()
+	require.True(t, ok)
+	assert.Equal(t, inGwei(1.65).String(), paid.String())
+	assert.Equal(t, inGwei(1).String(), base.String())
+
+	require.Len(t, r.Overpaid, 1)
+	o := r.Overpaid[0]
+	assert.Equal(t, "0x1", o.Hash)
+	assert.InDelta(t, 2.0, o.Premium, 1e-9)
+	assert.Equal(t, inGwei(21000*2).String(), o.Excess.String())
+	assert.Equal(t, 1, r.NoBaseFee)
+
+	_, _, ok = r.Months[1].AvgVsBase()
+	assert.False(t, ok)
+	rows := r.Rows()
+	assert.Equal(t, []string{"bsc", "2026-02", "1", "0.000021 BNB", "n/a", "1.00 gwei", "n/a", "n/a"}, rows[1])
+	assert.Equal(t, []string{"ethereum", "2026-01", "2", "0.000139 ETH", "$0.20", "1.65 gwei", "1.00 gwei", "65%"}, rows[2])
+
+	// A 20% premium is flagged under a lower threshold, unlike the L2 tip.
+	assert.Len(t, Build(txs, 0.1).Overpaid, 2)
+}
+
+func TestReportText(t *testing.T) {
+	empty := Report{Unresolved: 2}.Text()
+	assert.Contains(t, empty, "No transactions recorded yet")
+	assert.Contains(t, empty, "2 stored receipts were skipped")
+
+	r := Build([]Tx{{Chain: "ethereum", Hash: "0xabc", Symbol: "ETH", At: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		GasUsed: 21000, Price: inGwei(30), BaseFee: inGwei(10)}}, DefaultThreshold)
+	text := r.Text()
+	assert.Contains(t, text, "Overpaid (more than 100% over the base fee): 1")
+	assert.Contains(t, text, "0xabc")
+	assert.True(t, strings.Contains(text, "200%"), text)
+}
@@ -0,0 +1,158 @@
+// Package history merges a wallet's ERC-20 transfers, read from Transfer
+// logs, with the native transactions clifi sent into one timeline.
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of entry.
+const (
+	KindNative = "native"
+	KindToken  = "token"
+)
+
+// Directions, relative to the wallet.
+const (
+	In   = "in"
+	Out  = "out"
+	Self = "self"
+)
+
+// Entry is one transfer into or out of the wallet. Time is zero when the
+// block could not be looked up, Block is zero for a transaction not yet
+// mined.
+type Entry struct {
+	Chain        string    `json:"chain"`
+	Block        uint64    `json:"block,omitempty"`
+	Time         time.Time `json:"time"`
+	TxHash       string    `json:"tx_hash"`
+	LogIndex     uint      `json:"log_index,omitempty"`
+	Kind         string    `json:"kind"`
+	Direction    string    `json:"direction"`
+	Asset        string    `json:"asset"`
+	Token        string    `json:"token,omitempty"`
+	Amount       string    `json:"amount"`
+	Counterparty string    `json:"counterparty"`
+	Status       string    `json:"status,omitempty"`
+}
+
+func (e Entry) key() string {
+	return fmt.Sprintf("%s/%s/%s/%d", e.Chain, strings.ToLower(e.TxHash), e.Kind, e.LogIndex)
+}
+
+// Range is the block range scanned for token transfers on one chain.
+type Range struct {
+	Chain string `json:"chain"`
+	From  uint64 `json:"from"`
+	To    uint64 `json:"to"`
+}
+
+// Report is a wallet's history across chains.
+type Report struct {
+	Address string   `json:"address"`
+	Entries []Entry  `json:"entries"`
+	Ranges  []Range  `json:"ranges"`
+	Notes   []string `json:"notes,omitempty"`
+	// Truncated is set when entries were dropped to stay within the limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Merge sorts entries newest first, drops duplicates and keeps at most
+// limit (all of them when limit is zero). Pending transactions come first;
+// within a block, later logs first.
+func Merge(entries []Entry, limit int) ([]Entry, bool) {
+	seen := make(map[string]bool, len(entries))
+	out := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if k := e.key(); !seen[k] {
+			seen[k] = true
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if (a.Block == 0) != (b.Block == 0) {
+			return a.Block == 0
+		}
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		if a.Block != b.Block {
+			return a.Block > b.Block
+		}
+		return a.LogIndex > b.LogIndex
+	})
+	if limit > 0 && len(out) > limit {
+		return out[:limit], true
+	}
+	return out, false
+}
+
+// Headers are the column names for Rows.
+var Headers = []string{"Time", "Chain", "Dir", "Amount", "Asset", "Counterparty", "Tx", "Status"}
+
+// Rows renders the entries as table rows.
+func (r Report) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Entries))
+	for _, e := range r.Entries {
+		at := "pending"
+		if e.Block > 0 {
+			at = fmt.Sprintf("block %d", e.Block)
+		}
+		if !e.Time.IsZero() {
+			at = e.Time.UTC().Format("2006-01-02 15:04")
+		}
+		rows = append(rows, []string{at, e.Chain, e.Direction, e.Amount, e.Asset, e.Counterparty, e.TxHash, e.Status})
+	}
+	return rows
+}
+
+// Text renders the report as plain text.
+func (r Report) Text() string {
+	var b strings.Builder
+	if len(r.Entries) == 0 {
+		fmt.Fprintf(&b, "No transfers found for %s.\n", r.Address)
+	} else {
+		fmt.Fprintf(&b, "History of %s\n\n", r.Address)
+		writeTable(&b, Headers, r.Rows())
+	}
+	if r.Truncated {
+		fmt.Fprintf(&b, "\nShowing the %d most recent transfers; raise --limit for more.\n", len(r.Entries))
+	}
+	if len(r.Ranges) > 0 {
+		scanned := make([]string, 0, len(r.Ranges))
+		for _, rg := range r.Ranges {
+			scanned = append(scanned, fmt.Sprintf("%s %d-%d", rg.Chain, rg.From, rg.To))
+		}
+		b.WriteString("\nToken transfers scanned in blocks: " + strings.Join(scanned, ", ") + "\n")
+	}
+	for _, n := range r.Notes {
+		b.WriteString("Note: " + n + "\n")
+	}
+	return b.String()
+}
+
+// writeTable left-aligns rows under headers.
+func writeTable(b *strings.Builder, headers []string, rows [][]string) {
+	rows = append([][]string{headers}, rows...)
+	widths := make([]int, len(headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-len([]rune(cell))) + "  ")
+		}
+		b.WriteString("\n")
+	}
+}
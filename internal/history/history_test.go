@@ -0,0 +1,64 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Chain: "base", Block: 100, Time: t0, TxHash: "0xa", LogIndex: 1, Kind: KindToken},
+		{Chain: "base", Block: 100, Time: t0, TxHash: "0xa", LogIndex: 4, Kind: KindToken},
+		{Chain: "ethereum", Block: 50, Time: t0.Add(time.Hour), TxHash: "0xb", Kind: KindNative},
+		{Chain: "base", TxHash: "0xc", Kind: KindNative, Status: "pending"},
+		// The same log found by both the from and the to query.
+		{Chain: "base", Block: 100, Time: t0, TxHash: "0xA", LogIndex: 4, Kind: KindToken},
+		// A native transfer and a token transfer in one transaction both stay.
+		{Chain: "base", Block: 90, Time: t0.Add(-time.Hour), TxHash: "0xd", Kind: KindNative},
+		{Chain: "base", Block: 90, Time: t0.Add(-time.Hour), TxHash: "0xd", Kind: KindToken},
+	}
+
+	got, truncated := Merge(entries, 0)
+	assert.False(t, truncated)
+	var order []string
+	for _, e := range got {
+		order = append(order, e.TxHash)
+	}
+	assert.Equal(t, []string{"0xc", "0xb", "0xa", "0xa", "0xd", "0xd"}, order)
+	assert.Equal(t, uint(4), got[2].LogIndex)
+
+	got, truncated = Merge(entries, 2)
+	assert.True(t, truncated)
+	assert.Len(t, got, 2)
+}
+
+func TestReportText(t *testing.T) {
+	r := Report{
+		Address: "0x1111111111111111111111111111111111111111",
+		Entries: []Entry{
+			{Chain: "base", Block: 10, Time: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC), TxHash: "0xa", Kind: KindToken,
+				Direction: In, Asset: "USDC", Amount: "25", Counterparty: "0x2222222222222222222222222222222222222222", Status: "success"},
+			{Chain: "base", TxHash: "0xb", Kind: KindNative, Direction: Out, Asset: "ETH", Amount: "0.1",
+				Counterparty: "0x3333333333333333333333333333333333333333", Status: "pending"},
+		},
+		Ranges:    []Range{{Chain: "base", From: 1, To: 10}},
+		Notes:     []string{"arbitrum: rpc down"},
+		Truncated: true,
+	}
+	text := r.Text()
+	lines := strings.Split(text, "\n")
+	require.GreaterOrEqual(t, len(lines), 5)
+	assert.True(t, strings.HasPrefix(lines[2], "Time "))
+	assert.Contains(t, lines[3], "2026-03-01 09:30  base   in   25")
+	assert.Contains(t, lines[4], "pending")
+	assert.Contains(t, text, "Showing the 2 most recent transfers")
+	assert.Contains(t, text, "blocks: base 1-10")
+	assert.Contains(t, text, "Note: arbitrum: rpc down")
+
+	assert.Contains(t, Report{Address: "0x1"}.Text(), "No transfers found for 0x1.")
+}
@@ -0,0 +1,455 @@
+// Package jsonschema validates JSON values against the commonly used
+// subset of JSON Schema: type, enum, const, properties, required,
+// additionalProperties, items, the numeric, string and array bounds,
+// pattern, allOf/anyOf/oneOf/not, and local $refs. Keywords outside it are
+// ignored, so a schema using them is checked less strictly rather than
+// rejected.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	root any
+	raw  json.RawMessage
+}
+
+// Parse reads a schema document.
+func Parse(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("invalid schema: want an object or a boolean")
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, err
+	}
+	s := &Schema{root: root, raw: compact.Bytes()}
+	// Surface bad patterns and refs now rather than on the first value.
+	if err := s.check(root, "#", map[string]bool{}); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return s, nil
+}
+
+// Raw returns the schema as compact JSON.
+func (s *Schema) Raw() json.RawMessage {
+	return s.raw
+}
+
+// ValidationError lists every way a value failed the schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validate checks data, a JSON document, against the schema.
+func (s *Schema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("not valid JSON: trailing data after the value")
+	}
+	var problems []string
+	s.validate(s.root, v, "$", &problems, 0)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// maxDepth stops $ref cycles.
+const maxDepth = 64
+
+func (s *Schema) validate(schema, v any, path string, problems *[]string, depth int) {
+	fail := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if depth > maxDepth {
+		fail("schema nests too deeply")
+		return
+	}
+	sch, ok := schema.(map[string]any)
+	if !ok {
+		if allowed, isBool := schema.(bool); isBool && !allowed {
+			fail("not allowed")
+		}
+		return
+	}
+
+	if ref, ok := sch["$ref"].(string); ok {
+		target, err := s.resolve(ref)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		s.validate(target, v, path, problems, depth+1)
+	}
+
+	if t, ok := sch["type"]; ok && !matchesType(t, v) {
+		fail("want %s, got %s", typeNames(t), typeOf(v))
+		return
+	}
+	if enum, ok := sch["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", compactJSON(enum))
+		}
+	}
+	if c, ok := sch["const"]; ok && !equal(c, v) {
+		fail("must be %s", compactJSON(c))
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		f, _ := val.Float64()
+		if m, ok := number(sch["minimum"]); ok && f < m {
+			fail("must be at least %v", m)
+		}
+		if m, ok := number(sch["maximum"]); ok && f > m {
+			fail("must be at most %v", m)
+		}
+		if m, ok := number(sch["exclusiveMinimum"]); ok && f <= m {
+			fail("must be more than %v", m)
+		}
+		if m, ok := number(sch["exclusiveMaximum"]); ok && f >= m {
+			fail("must be less than %v", m)
+		}
+		if m, ok := number(sch["multipleOf"]); ok && m > 0 {
+			if q := f / m; math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("must be a multiple of %v", m)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if m, ok := number(sch["minLength"]); ok && float64(n) < m {
+			fail("must be at least %v characters", m)
+		}
+		if m, ok := number(sch["maxLength"]); ok && float64(n) > m {
+			fail("must be at most %v characters", m)
+		}
+		if p, ok := sch["pattern"].(string); ok {
+			// Parse checked the pattern compiles.
+			if !regexp.MustCompile(p).MatchString(val) {
+				fail("must match %s", p)
+			}
+		}
+	case []any:
+		if m, ok := number(sch["minItems"]); ok && float64(len(val)) < m {
+			fail("must have at least %v items", m)
+		}
+		if m, ok := number(sch["maxItems"]); ok && float64(len(val)) > m {
+			fail("must have at most %v items", m)
+		}
+		if items, ok := sch["items"]; ok {
+			for i, item := range val {
+				s.validate(items, item, path+"["+strconv.Itoa(i)+"]", problems, depth+1)
+			}
+		}
+		if unique, _ := sch["uniqueItems"].(bool); unique {
+			for i := range val {
+				for j := i + 1; j < len(val); j++ {
+					if equal(val[i], val[j]) {
+						fail("items %d and %d are the same", i, j)
+					}
+				}
+			}
+		}
+	case map[string]any:
+		if req, ok := sch["required"].([]any); ok {
+			for _, r := range req {
+				if name, ok := r.(string); ok {
+					if _, present := val[name]; !present {
+						fail("missing required property %q", name)
+					}
+				}
+			}
+		}
+		props, _ := sch["properties"].(map[string]any)
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			if ps, ok := props[k]; ok {
+				s.validate(ps, val[k], child, problems, depth+1)
+				continue
+			}
+			if ap, ok := sch["additionalProperties"]; ok {
+				if b, isBool := ap.(bool); isBool && !b {
+					*problems = append(*problems, child+": property not allowed")
+					continue
+				}
+				s.validate(ap, val[k], child, problems, depth+1)
+			}
+		}
+		if m, ok := number(sch["minProperties"]); ok && float64(len(val)) < m {
+			fail("must have at least %v properties", m)
+		}
+		if m, ok := number(sch["maxProperties"]); ok && float64(len(val)) > m {
+			fail("must have at most %v properties", m)
+		}
+	}
+
+	if all, ok := sch["allOf"].([]any); ok {
+		for _, sub := range all {
+			s.validate(sub, v, path, problems, depth+1)
+		}
+	}
+	if anyOf, ok := sch["anyOf"].([]any); ok {
+		if s.countMatches(anyOf, v, depth) == 0 {
+			fail("matches none of anyOf")
+		}
+	}
+	if oneOf, ok := sch["oneOf"].([]any); ok {
+		if n := s.countMatches(oneOf, v, depth); n != 1 {
+			fail("matches %d of oneOf, want exactly 1", n)
+		}
+	}
+	if not, ok := sch["not"]; ok {
+		var sub []string
+		s.validate(not, v, path, &sub, depth+1)
+		if len(sub) == 0 {
+			fail("must not match the \"not\" schema")
+		}
+	}
+}
+
+func (s *Schema) countMatches(schemas []any, v any, depth int) int {
+	n := 0
+	for _, sub := range schemas {
+		var problems []string
+		s.validate(sub, v, "$", &problems, depth+1)
+		if len(problems) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// resolve follows a local JSON pointer such as #/$defs/address.
+func (s *Schema) resolve(ref string) (any, error) {
+	if ref == "#" {
+		return s.root, nil
+	}
+	rest, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q: only local refs (#/...) are resolved", ref)
+	}
+	var node any = s.root
+	for _, part := range strings.Split(rest, "/") {
+		part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$ref %q not found", ref)
+		}
+		if node, ok = m[part]; !ok {
+			return nil, fmt.Errorf("$ref %q not found", ref)
+		}
+	}
+	return node, nil
+}
+
+// check walks the schema for patterns that don't compile and refs that
+// don't resolve.
+func (s *Schema) check(node any, at string, seen map[string]bool) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if p, ok := n["pattern"].(string); ok {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("%s: bad pattern: %w", at, err)
+			}
+		}
+		if ref, ok := n["$ref"].(string); ok && !seen[ref] {
+			seen[ref] = true
+			if _, err := s.resolve(ref); err != nil {
+				return fmt.Errorf("%s: %w", at, err)
+			}
+		}
+		for k, child := range n {
+			if k == "enum" || k == "const" {
+				continue
+			}
+			if err := s.check(child, at+"/"+k, seen); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, child := range n {
+			if err := s.check(child, at+"/"+strconv.Itoa(i), seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func typeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+func matchesType(t any, v any) bool {
+	got := typeOf(v)
+	ok := func(name string) bool {
+		return name == got || (name == "number" && got == "integer")
+	}
+	switch tt := t.(type) {
+	case string:
+		return ok(tt)
+	case []any:
+		for _, x := range tt {
+			if name, isStr := x.(string); isStr && ok(name) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeNames(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, x := range list {
+			names = append(names, fmt.Sprint(x))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equal compares a schema value (float64 numbers) with a decoded value
+// (json.Number numbers).
+func equal(a, b any) bool {
+	na, aNum := number(a)
+	nb, bNum := number(b)
+	if aNum || bNum {
+		return aNum && bNum && na == nb
+	}
+	switch av := a.(type) {
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, x := range av {
+			y, present := bv[k]
+			if !present || !equal(x, y) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Extract pulls a JSON value out of a model's reply, which may wrap it in
+// a code fence or a sentence despite being told not to.
+func Extract(reply string) string {
+	s := strings.TrimSpace(reply)
+	if i := strings.Index(s, "```"); i >= 0 {
+		body := s[i+3:]
+		if nl := strings.IndexByte(body, '\n'); nl >= 0 && !strings.ContainsAny(body[:nl], "{[") {
+			body = body[nl+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			return strings.TrimSpace(body[:end])
+		}
+	}
+	if json.Valid([]byte(s)) {
+		return s
+	}
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return s
+	}
+	closer := byte('}')
+	if s[start] == '[' {
+		closer = ']'
+	}
+	if end := strings.LastIndexByte(s, closer); end > start {
+		return s[start : end+1]
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const balanceSchema = `{
+  "type": "object",
+  "required": ["chain", "balances"],
+  "additionalProperties": false,
+  "properties": {
+    "chain": {"enum": ["ethereum", "base"]},
+    "balances": {
+      "type": "array",
+      "minItems": 1,
+      "items": {"$ref": "#/$defs/balance"}
+    }
+  },
+  "$defs": {
+    "balance": {
+      "type": "object",
+      "required": ["symbol", "amount"],
+      "properties": {
+        "symbol": {"type": "string", "pattern": "^[A-Z]+$"},
+        "amount": {"type": "number", "minimum": 0},
+        "decimals": {"type": "integer"}
+      }
+    }
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(balanceSchema))
+	require.NoError(t, err)
+
+	assert.NoError(t, s.Validate([]byte(`{"chain":"base","balances":[{"symbol":"ETH","amount":1.5,"decimals":18}]}`)))
+
+	err = s.Validate([]byte(`{"chain":"solana","balances":[{"symbol":"eth","amount":-1,"decimals":1.5}],"note":"x"}`))
+	var ve *ValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, []string{
+		`$.balances[0].amount: must be at least 0`,
+		`$.balances[0].decimals: want integer, got number`,
+		`$.balances[0].symbol: must match ^[A-Z]+$`,
+		`$.chain: must be one of ["ethereum","base"]`,
+		`$.note: property not allowed`,
+	}, ve.Problems)
+
+	assert.ErrorContains(t, s.Validate([]byte(`{"chain":"base"}`)), `missing required property "balances"`)
+	assert.ErrorContains(t, s.Validate([]byte(`{"chain":"base","balances":[]}`)), "at least 1 items")
+	assert.ErrorContains(t, s.Validate([]byte(`[]`)), "want object, got array")
+	assert.ErrorContains(t, s.Validate([]byte(`{"chain":`)), "not valid JSON")
+}
+
+func TestCombinators(t *testing.T) {
+	s, err := Parse([]byte(`{"oneOf":[{"type":"string"},{"type":"integer","not":{"const":0}}]}`))
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`"0x1"`)))
+	assert.NoError(t, s.Validate([]byte(`7`)))
+	assert.ErrorContains(t, s.Validate([]byte(`0`)), "matches 0 of oneOf")
+	assert.Error(t, s.Validate([]byte(`null`)))
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, bad := range []string{`[]`, `{"pattern":"(("}`, `{"$ref":"#/$defs/missing"}`, `{"$ref":"https://example.com/s.json"}`, `{`} {
+		_, err := Parse([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := map[string]string{
+		`{"a":1}`:                                 `{"a":1}`,
+		"```json\n{\"a\":1}\n```":                 `{"a":1}`,
+		"Here you go:\n```\n[1,2]\n```\nThanks":   `[1,2]`,
+		`The answer is {"a": {"b": 2}} as asked.`: `{"a": {"b": 2}}`,
+		`"plain string"`:                          `"plain string"`,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, Extract(in), in)
+	}
+}
@@ -2,7 +2,9 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/liushuangls/go-anthropic/v2"
 )
@@ -10,9 +12,13 @@ import (
 // AnthropicProvider implements the Provider interface for Anthropic Claude
 type AnthropicProvider struct {
 	client *anthropic.Client
+	apiKey string
 	model  string
 }
 
+// anthropicModelsURL is a variable so tests can point it at a fake server.
+var anthropicModelsURL = "https://api.anthropic.com/v1/models?limit=1000"
+
 // AnthropicModels lists available Anthropic models
 var AnthropicModels = []Model{
 	{
@@ -55,7 +61,11 @@ func NewAnthropicProvider(apiKey string, model string) (*AnthropicProvider, erro
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	client := anthropic.NewClient(apiKey)
+	hc, err := httpClient(ProviderAnthropic)
+	if err != nil {
+		return nil, err
+	}
+	client := anthropic.NewClient(apiKey, anthropic.WithHTTPClient(hc))
 
 	if model == "" {
 		model = "claude-3-5-sonnet-20241022"
@@ -63,6 +73,7 @@ func NewAnthropicProvider(apiKey string, model string) (*AnthropicProvider, erro
 
 	return &AnthropicProvider{
 		client: client,
+		apiKey: apiKey,
 		model:  model,
 	}, nil
 }
@@ -82,9 +93,55 @@ func (p *AnthropicProvider) SupportsTools() bool {
 	return true
 }
 
-// Models returns available models
+// Models returns the models the API lists for this key, or the static
+// list when it can't be fetched.
 func (p *AnthropicProvider) Models() []Model {
-	return AnthropicModels
+	return liveModels(anthropicCatalog, AnthropicModels, func(ctx context.Context) ([]Model, error) {
+		return FetchAnthropicModels(ctx, p.apiKey)
+	})
+}
+
+// FetchAnthropicModels returns the models available to apiKey. The API
+// gives IDs and names only; every Claude model supports tools.
+func FetchAnthropicModels(ctx context.Context, apiKey string) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, anthropicModelsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	client, err := httpClient(ProviderAnthropic)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("anthropic models: %s", resp.Status)
+	}
+
+	var body struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	models := make([]Model, 0, len(body.Data))
+	for _, m := range body.Data {
+		if m.ID != "" {
+			models = append(models, Model{ID: m.ID, Name: m.DisplayName, SupportsTools: true})
+		}
+	}
+	return models, nil
 }
 
 // DefaultModel returns the default model
@@ -147,6 +204,13 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRe
 
 	if len(anthropicTools) > 0 {
 		anthropicReq.Tools = anthropicTools
+		anthropicReq.ToolChoice = anthropicToolChoice(req.ToolChoice)
+	}
+	if req.Temperature != nil {
+		anthropicReq.SetTemperature(float32(*req.Temperature))
+	}
+	if req.TopP != nil {
+		anthropicReq.SetTopP(float32(*req.TopP))
 	}
 
 	resp, err := p.client.CreateMessages(ctx, anthropicReq)
@@ -252,6 +316,13 @@ func (p *AnthropicProvider) ChatWithToolResults(ctx context.Context, req *ChatRe
 
 	if len(anthropicTools) > 0 {
 		anthropicReq.Tools = anthropicTools
+		anthropicReq.ToolChoice = anthropicToolChoice(req.ToolChoice)
+	}
+	if req.Temperature != nil {
+		anthropicReq.SetTemperature(float32(*req.Temperature))
+	}
+	if req.TopP != nil {
+		anthropicReq.SetTopP(float32(*req.TopP))
 	}
 
 	resp, err := p.client.CreateMessages(ctx, anthropicReq)
@@ -284,3 +355,18 @@ func (p *AnthropicProvider) ChatWithToolResults(ctx context.Context, req *ChatRe
 
 	return response, nil
 }
+
+// anthropicToolChoice maps choice to Anthropic's tool_choice; auto is the
+// default and is left out.
+func anthropicToolChoice(choice ToolChoice) *anthropic.ToolChoice {
+	switch choice.Mode {
+	case ToolChoiceNone:
+		return &anthropic.ToolChoice{Type: "none"}
+	case ToolChoiceForce:
+		if choice.Name == "" {
+			return nil
+		}
+		return &anthropic.ToolChoice{Type: "tool", Name: choice.Name}
+	}
+	return nil
+}
@@ -3,25 +3,100 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
-// ToolCapabilitiesCache caches per-provider model capability lookups.
-type ToolCapabilitiesCache struct {
-	mu      sync.Mutex
-	entries map[ProviderID]capEntry
+const (
+	// catalogTTL is how long a live model list is reused.
+	catalogTTL = 6 * time.Hour
+	// catalogRetryAfter spaces out fetches after a failed one, so an
+	// unreachable API doesn't stall every lookup.
+	catalogRetryAfter = time.Minute
+	// catalogTimeout bounds a fetch made while listing or switching
+	// models, after which the static list is used.
+	catalogTimeout = 5 * time.Second
+)
+
+// modelCatalog caches a provider's live model list.
+type modelCatalog struct {
+	mu     sync.Mutex
+	models []Model
+	err    error
+	expiry time.Time
 }
 
-type capEntry struct {
-	expiry  time.Time
-	support map[string]bool // modelID -> supportsTools
+var (
+	openRouterCatalog = &modelCatalog{}
+	anthropicCatalog  = &modelCatalog{}
+	openAICatalog     = &modelCatalog{}
+)
+
+// OpenRouterCatalog returns OpenRouter's live model list, fetched at most
+// every few hours. apiKey may be empty: the list is public.
+func OpenRouterCatalog(ctx context.Context, apiKey string) ([]Model, error) {
+	return openRouterCatalog.get(ctx, func(ctx context.Context) ([]Model, error) {
+		return FetchOpenRouterModels(ctx, apiKey)
+	})
 }
 
-var toolCapCache = &ToolCapabilitiesCache{
-	entries: make(map[ProviderID]capEntry),
+// get returns the cached list, calling fetch when it has expired.
+func (c *modelCatalog) get(ctx context.Context, fetch func(context.Context) ([]Model, error)) ([]Model, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Now().Before(c.expiry) {
+		return c.models, c.err
+	}
+	models, err := fetch(ctx)
+	if err != nil {
+		slog.Debug("model list fetch failed", "err", err)
+		c.err, c.expiry = err, time.Now().Add(catalogRetryAfter)
+		return c.models, err
+	}
+	c.models, c.err, c.expiry = models, nil, time.Now().Add(catalogTTL)
+	return models, nil
+}
+
+// liveModels returns the models a provider's API lists, priced from
+// static where clifi knows them, or static itself when the list can't be
+// fetched.
+func liveModels(c *modelCatalog, static []Model, fetch func(context.Context) ([]Model, error)) []Model {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+	live, _ := c.get(ctx, fetch)
+	if len(live) == 0 {
+		return static
+	}
+	return mergeModels(live, static)
+}
+
+// mergeModels lists the live models: those in static first, in its order
+// and with its details, then the rest marked Unlisted.
+func mergeModels(live, static []Model) []Model {
+	available := make(map[string]bool, len(live))
+	for _, m := range live {
+		available[m.ID] = true
+	}
+	known := make(map[string]bool, len(static))
+	var out []Model
+	for _, m := range static {
+		known[m.ID] = true
+		if available[m.ID] {
+			out = append(out, m)
+		}
+	}
+	for _, m := range live {
+		if !known[m.ID] {
+			m.Unlisted = true
+			out = append(out, m)
+		}
+	}
+	return out
 }
 
 // SupportsToolsForModel returns (supports, known) for a provider/model.
@@ -29,8 +104,11 @@ var toolCapCache = &ToolCapabilitiesCache{
 func SupportsToolsForModel(ctx context.Context, provider Provider, modelID string, openRouterAPIKey string) (bool, bool) {
 	// For OpenRouter, prefer live capability data so we don't rely on stale static lists.
 	if provider.ID() == ProviderOpenRouter {
-		if supports, known := toolCapCache.fetchOpenRouter(ctx, openRouterAPIKey, modelID); known {
-			return supports, true
+		models, _ := OpenRouterCatalog(ctx, openRouterAPIKey)
+		for _, m := range models {
+			if m.ID == modelID {
+				return m.SupportsTools, true
+			}
 		}
 	}
 
@@ -44,56 +122,56 @@ func SupportsToolsForModel(ctx context.Context, provider Provider, modelID strin
 	return true, false // default optimistic
 }
 
-func (c *ToolCapabilitiesCache) fetchOpenRouter(ctx context.Context, apiKey, targetModel string) (bool, bool) {
-	if apiKey == "" {
-		return false, false
-	}
-
-	c.mu.Lock()
-	entry, ok := c.entries[ProviderOpenRouter]
-	now := time.Now()
-	if ok && now.Before(entry.expiry) {
-		if v, found := entry.support[targetModel]; found {
-			c.mu.Unlock()
-			return v, true
+// SearchModels returns the models whose ID or name contains every word of
+// query, ignoring case.
+func SearchModels(models []Model, query string) []Model {
+	words := strings.Fields(strings.ToLower(query))
+	var out []Model
+	for _, m := range models {
+		text := strings.ToLower(m.ID + " " + m.Name)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, m)
 		}
 	}
-	c.mu.Unlock()
+	return out
+}
 
-	// Refresh
-	support, err := pullOpenRouterModels(ctx, apiKey)
-	if err != nil {
-		return false, false
-	}
+// openRouterModelsURL is a variable so tests can point it at a fake server.
+var openRouterModelsURL = openRouterBaseURL + "/models"
 
-	c.mu.Lock()
-	c.entries[ProviderOpenRouter] = capEntry{
-		expiry:  time.Now().Add(6 * time.Hour),
-		support: support,
+// FetchOpenRouterModels returns OpenRouter's live model list with context
+// windows, prices and tool support, which change far more often than the
+// static OpenRouterModels.
+func FetchOpenRouterModels(ctx context.Context, apiKey string) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, openRouterModelsURL, nil)
+	if err != nil {
+		return nil, err
 	}
-	v, found := support[targetModel]
-	c.mu.Unlock()
-
-	if found {
-		return v, true
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
 	}
-	return false, false
-}
 
-func pullOpenRouterModels(ctx context.Context, apiKey string) (map[string]bool, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://openrouter.ai/api/v1/models", nil)
+	client, err := httpClient(ProviderOpenRouter)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openrouter models: %s", resp.Status)
+	}
 
 	var body struct {
 		Data []json.RawMessage `json:"data"`
@@ -102,7 +180,7 @@ func pullOpenRouterModels(ctx context.Context, apiKey string) (map[string]bool,
 		return nil, err
 	}
 
-	out := make(map[string]bool)
+	models := make([]Model, 0, len(body.Data))
 	for _, raw := range body.Data {
 		var m map[string]any
 		if err := json.Unmarshal(raw, &m); err != nil {
@@ -112,13 +190,28 @@ func pullOpenRouterModels(ctx context.Context, apiKey string) (map[string]bool,
 		if id == "" {
 			continue
 		}
-		if supportsToolsInOpenRouter(m) {
-			out[id] = true
-		} else {
-			out[id] = false
+		model := Model{ID: id, SupportsTools: supportsToolsInOpenRouter(m)}
+		model.Name, _ = m["name"].(string)
+		if n, ok := m["context_length"].(float64); ok {
+			model.ContextWindow = int(n)
 		}
+		// Prices are USD per token, as strings.
+		if pricing, ok := m["pricing"].(map[string]any); ok {
+			model.InputCost = perMillion(pricing["prompt"])
+			model.OutputCost = perMillion(pricing["completion"])
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+func perMillion(v any) float64 {
+	s, _ := v.(string)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0
 	}
-	return out, nil
+	return f * 1_000_000
 }
 
 func supportsToolsInOpenRouter(m map[string]any) bool {
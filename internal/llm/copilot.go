@@ -1,8 +1,24 @@
 package llm
 
-import "fmt"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
 
-const copilotBaseURL = "https://api.githubcopilot.com"
+const (
+	copilotBaseURL = "https://api.githubcopilot.com"
+	// copilotTokenURL exchanges a GitHub OAuth token for a short-lived
+	// Copilot API token.
+	copilotTokenURL = "https://api.github.com/copilot_internal/v2/token"
+	// copilotTokenMargin renews the Copilot token this long before it
+	// expires, so a request never goes out with a stale one.
+	copilotTokenMargin = time.Minute
+)
 
 type CopilotProvider = OpenAICompatProvider
 
@@ -26,19 +42,134 @@ var CopilotModels = []Model{
 	},
 }
 
-// NewCopilotProvider creates a new GitHub Copilot provider
-func NewCopilotProvider(accessToken string, model string) (*CopilotProvider, error) {
-	if accessToken == "" {
+// NewCopilotProvider creates a new GitHub Copilot provider. githubToken is
+// the GitHub OAuth token from `clifi auth connect copilot` (or
+// GITHUB_TOKEN); it is exchanged for Copilot API tokens as they expire.
+func NewCopilotProvider(githubToken string, model string) (*CopilotProvider, error) {
+	if githubToken == "" {
 		return nil, fmt.Errorf("access token is required")
 	}
+	if model == "" {
+		model = "gpt-4o"
+	}
+
+	hc, err := httpClient(ProviderCopilot)
+	if err != nil {
+		return nil, err
+	}
+	tokens := &copilotTokenSource{githubToken: githubToken, url: copilotTokenURL, client: hc}
+	client := &http.Client{Transport: &copilotTransport{tokens: tokens, base: hc.Transport}}
+
+	base, err := newOpenAIProviderWithClient(githubToken, model, copilotBaseURL, ProviderCopilot, client)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAICompatProvider{
+		id:             ProviderCopilot,
+		name:           "GitHub Copilot",
+		models:         CopilotModels,
+		OpenAIProvider: base,
+	}, nil
+}
+
+// copilotTokenSource caches the Copilot API token for a GitHub token.
+type copilotTokenSource struct {
+	githubToken string
+	url         string
+	client      *http.Client
+
+	mu       sync.Mutex
+	token    string
+	endpoint string // API base URL for the account's plan, if given
+	expires  time.Time
+}
+
+// get returns a valid Copilot token, exchanging the GitHub token when the
+// cached one is missing or about to expire.
+func (s *copilotTokenSource) get(ctx context.Context) (token, endpoint string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Add(copilotTokenMargin).Before(s.expires) {
+		return s.token, s.endpoint, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "token "+s.githubToken)
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("copilot token exchange: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", "", fmt.Errorf("GitHub rejected the token; run: clifi auth connect copilot")
+	case http.StatusForbidden, http.StatusNotFound:
+		return "", "", fmt.Errorf("this GitHub account has no Copilot access (status %d)", resp.StatusCode)
+	default:
+		return "", "", fmt.Errorf("copilot token exchange returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token     string `json:"token"`
+		ExpiresAt int64  `json:"expires_at"`
+		Endpoints struct {
+			API string `json:"api"`
+		} `json:"endpoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", "", fmt.Errorf("copilot token exchange: %w", err)
+	}
+	if body.Token == "" {
+		return "", "", fmt.Errorf("copilot token exchange returned no token")
+	}
+	s.token, s.endpoint = body.Token, body.Endpoints.API
+	s.expires = time.Unix(body.ExpiresAt, 0)
+	return s.token, s.endpoint, nil
+}
+
+// invalidate drops the cached token after the API rejected it.
+func (s *copilotTokenSource) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
 
-	return newOpenAICompatProvider(
-		accessToken,
-		model,
-		copilotBaseURL,
-		ProviderCopilot,
-		"GitHub Copilot",
-		CopilotModels,
-		"gpt-4o",
-	)
+// copilotTransport authenticates requests with the current Copilot token
+// and sends them to the account's API endpoint.
+type copilotTransport struct {
+	tokens *copilotTokenSource
+	base   http.RoundTripper
+}
+
+func (t *copilotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, endpoint, err := t.tokens.get(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	if endpoint != "" {
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			req.URL.Scheme, req.URL.Host, req.Host = u.Scheme, u.Host, u.Host
+		}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	// The API only serves requests that name a known editor integration.
+	req.Header.Set("Copilot-Integration-Id", "vscode-chat")
+	req.Header.Set("Editor-Version", "vscode/1.95.0")
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		t.tokens.invalidate()
+	}
+	return resp, err
 }
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopilotTransport(t *testing.T) {
+	var exchanges atomic.Int32
+	var expiresAt atomic.Int64
+	expiresAt.Store(time.Now().Add(30 * time.Minute).Unix())
+
+	var api *httptest.Server
+	api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.Equal(t, "token gho_github", r.Header.Get("Authorization"))
+			n := exchanges.Add(1)
+			_, _ = fmt.Fprintf(w, `{"token":"cop_%d","expires_at":%d,"endpoints":{"api":%q}}`, n, expiresAt.Load(), api.URL)
+		case "/chat":
+			assert.Equal(t, "vscode-chat", r.Header.Get("Copilot-Integration-Id"))
+			_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+		}
+	}))
+	defer api.Close()
+
+	tokens := &copilotTokenSource{githubToken: "gho_github", url: api.URL + "/token", client: api.Client()}
+	client := &http.Client{Transport: &copilotTransport{tokens: tokens}}
+	get := func() string {
+		// The request names the default host; the token's endpoint replaces it.
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, copilotBaseURL+"/chat", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var b [64]byte
+		n, _ := resp.Body.Read(b[:])
+		return string(b[:n])
+	}
+
+	assert.Equal(t, "Bearer cop_1", get())
+	assert.Equal(t, "Bearer cop_1", get(), "the token is cached until it nears expiry")
+
+	tokens.mu.Lock()
+	tokens.expires = time.Now().Add(copilotTokenMargin / 2)
+	tokens.mu.Unlock()
+	assert.Equal(t, "Bearer cop_2", get(), "a token about to expire is renewed")
+}
+
+func TestCopilotTokenRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	tokens := &copilotTokenSource{githubToken: "ghp_x", url: srv.URL, client: srv.Client()}
+	_, _, err := tokens.get(context.Background())
+	assert.ErrorContains(t, err, "no Copilot access")
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
+	"google.golang.org/genai"
 )
 
+// geminiLocalID prefixes the IDs clifi gives function calls that came
+// without one, so they are not sent back to the API as its own.
+const geminiLocalID = "clifi-call-"
+
 // GeminiProvider implements the Provider interface for Google Gemini
 type GeminiProvider struct {
 	client *genai.Client
@@ -18,27 +22,35 @@ type GeminiProvider struct {
 // GeminiModels lists available Gemini models
 var GeminiModels = []Model{
 	{
-		ID:            "gemini-2.0-flash",
-		Name:          "Gemini 2.0 Flash",
-		ContextWindow: 1000000,
-		InputCost:     0.10,
-		OutputCost:    0.40,
+		ID:            "gemini-2.5-flash",
+		Name:          "Gemini 2.5 Flash",
+		ContextWindow: 1048576,
+		InputCost:     0.30,
+		OutputCost:    2.50,
 		SupportsTools: true,
 	},
 	{
-		ID:            "gemini-1.5-pro",
-		Name:          "Gemini 1.5 Pro",
-		ContextWindow: 2000000,
+		ID:            "gemini-2.5-pro",
+		Name:          "Gemini 2.5 Pro",
+		ContextWindow: 1048576,
 		InputCost:     1.25,
-		OutputCost:    5.0,
+		OutputCost:    10.0,
 		SupportsTools: true,
 	},
 	{
-		ID:            "gemini-1.5-flash",
-		Name:          "Gemini 1.5 Flash",
+		ID:            "gemini-2.5-flash-lite",
+		Name:          "Gemini 2.5 Flash-Lite",
+		ContextWindow: 1048576,
+		InputCost:     0.10,
+		OutputCost:    0.40,
+		SupportsTools: true,
+	},
+	{
+		ID:            "gemini-2.0-flash",
+		Name:          "Gemini 2.0 Flash",
 		ContextWindow: 1000000,
-		InputCost:     0.075,
-		OutputCost:    0.30,
+		InputCost:     0.10,
+		OutputCost:    0.40,
 		SupportsTools: true,
 	},
 }
@@ -49,13 +61,21 @@ func NewGeminiProvider(ctx context.Context, apiKey string, model string) (*Gemin
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	hc, err := httpClient(ProviderGemini)
+	if err != nil {
+		return nil, err
+	}
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: hc,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
 
 	if model == "" {
-		model = "gemini-2.0-flash"
+		model = "gemini-2.5-flash"
 	}
 
 	return &GeminiProvider{
@@ -100,158 +120,85 @@ func (p *GeminiProvider) SetModel(modelID string) error {
 
 // Chat sends a message and returns the response
 func (p *GeminiProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
-	modelName := req.Model
-	if modelName == "" {
-		modelName = p.model
-	}
-
-	model := p.client.GenerativeModel(modelName)
-
-	// Set system instruction
-	if req.SystemPrompt != "" {
-		model.SystemInstruction = &genai.Content{
-			Parts: []genai.Part{genai.Text(req.SystemPrompt)},
-		}
-	}
-
-	// Configure tools
-	if len(req.Tools) > 0 {
-		var funcDecls []*genai.FunctionDeclaration
-		for _, tool := range req.Tools {
-			var params map[string]any
-			_ = json.Unmarshal(tool.InputSchema, &params) // Schema already validated at registration
-
-			funcDecls = append(funcDecls, &genai.FunctionDeclaration{
-				Name:        tool.Name,
-				Description: tool.Description,
-				Parameters:  convertToSchema(params),
-			})
-		}
-		model.Tools = []*genai.Tool{{FunctionDeclarations: funcDecls}}
-	}
-
-	// Build content from messages
-	var contents []*genai.Content
-	for _, msg := range req.Messages {
-		role := "user"
-		if msg.Role == "assistant" {
-			role = "model"
-		}
-		contents = append(contents, &genai.Content{
-			Role:  role,
-			Parts: []genai.Part{genai.Text(msg.Content)},
-		})
-	}
-
-	// Start chat session
-	cs := model.StartChat()
-	cs.History = contents[:len(contents)-1] // All but last message
-
-	// Send last message
-	lastMsg := contents[len(contents)-1]
-	resp, err := cs.SendMessage(ctx, lastMsg.Parts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send message: %w", err)
-	}
-
-	return parseGeminiResponse(resp)
+	return p.generate(ctx, req, geminiContents(req.Messages))
 }
 
 // ChatWithToolResults continues a conversation with tool results
 func (p *GeminiProvider) ChatWithToolResults(ctx context.Context, req *ChatRequest, toolCalls []ToolCall, toolResults []ToolResult) (*ChatResponse, error) {
-	modelName := req.Model
-	if modelName == "" {
-		modelName = p.model
-	}
-
-	model := p.client.GenerativeModel(modelName)
-
-	// Set system instruction
-	if req.SystemPrompt != "" {
-		model.SystemInstruction = &genai.Content{
-			Parts: []genai.Part{genai.Text(req.SystemPrompt)},
-		}
-	}
-
-	// Configure tools
-	if len(req.Tools) > 0 {
-		var funcDecls []*genai.FunctionDeclaration
-		for _, tool := range req.Tools {
-			var params map[string]any
-			_ = json.Unmarshal(tool.InputSchema, &params) // Schema already validated at registration
-
-			funcDecls = append(funcDecls, &genai.FunctionDeclaration{
-				Name:        tool.Name,
-				Description: tool.Description,
-				Parameters:  convertToSchema(params),
-			})
-		}
-		model.Tools = []*genai.Tool{{FunctionDeclarations: funcDecls}}
-	}
-
-	// Build content from messages
-	var contents []*genai.Content
-	for _, msg := range req.Messages {
-		role := "user"
-		if msg.Role == "assistant" {
-			role = "model"
-		}
-		contents = append(contents, &genai.Content{
-			Role:  role,
-			Parts: []genai.Part{genai.Text(msg.Content)},
-		})
-	}
+	contents := geminiContents(req.Messages)
 
 	// Add model message with function calls (only if there are tool calls)
+	names := make(map[string]string, len(toolCalls))
 	if len(toolCalls) > 0 {
-		var functionCallParts []genai.Part
+		var parts []*genai.Part
 		for _, tc := range toolCalls {
 			var args map[string]any
 			_ = json.Unmarshal(tc.Input, &args)
-			functionCallParts = append(functionCallParts, genai.FunctionCall{
-				Name: tc.Name,
-				Args: args,
+			names[tc.ID] = tc.Name
+			parts = append(parts, &genai.Part{
+				FunctionCall:     &genai.FunctionCall{ID: geminiCallID(tc.ID), Name: tc.Name, Args: args},
+				ThoughtSignature: tc.Signature,
 			})
 		}
-		contents = append(contents, &genai.Content{
-			Role:  "model",
-			Parts: functionCallParts,
-		})
+		contents = append(contents, &genai.Content{Role: genai.RoleModel, Parts: parts})
 	}
 
-	// Add tool results
-	var toolResultParts []genai.Part
+	// Add tool results, matched to their calls by ID
+	var parts []*genai.Part
 	for _, result := range toolResults {
-		toolResultParts = append(toolResultParts, genai.FunctionResponse{
-			Name: result.ToolUseID, // Gemini uses function name, not ID
+		parts = append(parts, &genai.Part{FunctionResponse: &genai.FunctionResponse{
+			ID:   geminiCallID(result.ToolUseID),
+			Name: names[result.ToolUseID],
 			Response: map[string]any{
 				"result": result.Content,
 			},
-		})
+		}})
 	}
-	if len(toolResultParts) > 0 {
-		contents = append(contents, &genai.Content{
-			Role:  "user",
-			Parts: toolResultParts,
-		})
+	if len(parts) > 0 {
+		contents = append(contents, &genai.Content{Role: genai.RoleUser, Parts: parts})
 	}
 
-	// Start chat session
-	cs := model.StartChat()
-	cs.History = contents[:len(contents)-1]
+	return p.generate(ctx, req, contents)
+}
+
+// Close releases the client. The genai client holds no connections of its
+// own.
+func (p *GeminiProvider) Close() error {
+	return nil
+}
+
+func (p *GeminiProvider) generate(ctx context.Context, req *ChatRequest, contents []*genai.Content) (*ChatResponse, error) {
+	modelName := req.Model
+	if modelName == "" {
+		modelName = p.model
+	}
 
-	lastMsg := contents[len(contents)-1]
-	resp, err := cs.SendMessage(ctx, lastMsg.Parts...)
+	resp, err := p.client.Models.GenerateContent(ctx, modelName, contents, geminiConfig(req))
 	if err != nil {
 		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
-
 	return parseGeminiResponse(resp)
 }
 
-// Close closes the client
-func (p *GeminiProvider) Close() error {
-	return p.client.Close()
+func geminiContents(msgs []Message) []*genai.Content {
+	contents := make([]*genai.Content, 0, len(msgs))
+	for _, msg := range msgs {
+		role := genai.RoleUser
+		if msg.Role == "assistant" {
+			role = genai.RoleModel
+		}
+		contents = append(contents, genai.NewContentFromText(msg.Content, genai.Role(role)))
+	}
+	return contents
+}
+
+// geminiCallID is the ID to send back for a call: the API's own, or none
+// for calls it left unnamed.
+func geminiCallID(id string) string {
+	if strings.HasPrefix(id, geminiLocalID) {
+		return ""
+	}
+	return id
 }
 
 func parseGeminiResponse(resp *genai.GenerateContentResponse) (*ChatResponse, error) {
@@ -264,86 +211,100 @@ func parseGeminiResponse(resp *genai.GenerateContentResponse) (*ChatResponse, er
 		StopReason: string(candidate.FinishReason),
 	}
 
-	if resp.UsageMetadata != nil {
+	if u := resp.UsageMetadata; u != nil {
 		response.Usage = Usage{
-			InputTokens:  int(resp.UsageMetadata.PromptTokenCount),
-			OutputTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+			InputTokens: int(u.PromptTokenCount),
+			// Thinking is billed as output.
+			OutputTokens: int(u.CandidatesTokenCount + u.ThoughtsTokenCount),
 		}
 	}
 
 	// Parse content
 	if candidate.Content != nil {
+		var text strings.Builder
 		for _, part := range candidate.Content.Parts {
-			switch v := part.(type) {
-			case genai.Text:
-				response.Content = string(v)
-			case genai.FunctionCall:
-				argsJSON, _ := json.Marshal(v.Args)
+			switch {
+			case part.Thought:
+			case part.FunctionCall != nil:
+				fc := part.FunctionCall
+				argsJSON, _ := json.Marshal(fc.Args)
+				id := fc.ID
+				if id == "" {
+					// Parallel calls to one tool need distinct IDs.
+					id = fmt.Sprintf("%s%d", geminiLocalID, len(response.ToolCalls))
+				}
 				response.ToolCalls = append(response.ToolCalls, ToolCall{
-					ID:    v.Name, // Gemini uses name as ID
-					Name:  v.Name,
-					Input: argsJSON,
+					ID:        id,
+					Name:      fc.Name,
+					Input:     argsJSON,
+					Signature: part.ThoughtSignature,
 				})
+			case part.Text != "":
+				text.WriteString(part.Text)
 			}
 		}
+		response.Content = text.String()
 	}
 
 	return response, nil
 }
 
-// convertToSchema converts a map to genai.Schema
-func convertToSchema(params map[string]any) *genai.Schema {
-	if params == nil {
-		return nil
-	}
-
-	schema := &genai.Schema{
-		Type: genai.TypeObject,
+// geminiConfig builds the request's generation config: system prompt,
+// tools, generation parameters and response schema.
+func geminiConfig(req *ChatRequest) *genai.GenerateContentConfig {
+	cfg := &genai.GenerateContentConfig{}
+	if req.SystemPrompt != "" {
+		cfg.SystemInstruction = genai.NewContentFromText(req.SystemPrompt, genai.RoleUser)
 	}
 
-	if props, ok := params["properties"].(map[string]any); ok {
-		schema.Properties = make(map[string]*genai.Schema)
-		for name, prop := range props {
-			if propMap, ok := prop.(map[string]any); ok {
-				schema.Properties[name] = convertPropertyToSchema(propMap)
+	if len(req.Tools) > 0 {
+		decls := make([]*genai.FunctionDeclaration, len(req.Tools))
+		for i, tool := range req.Tools {
+			decls[i] = &genai.FunctionDeclaration{
+				Name:                 tool.Name,
+				Description:          tool.Description,
+				ParametersJsonSchema: tool.InputSchema,
 			}
 		}
+		cfg.Tools = []*genai.Tool{{FunctionDeclarations: decls}}
+		cfg.ToolConfig = geminiToolConfig(req.ToolChoice)
 	}
 
-	if required, ok := params["required"].([]any); ok {
-		for _, r := range required {
-			if s, ok := r.(string); ok {
-				schema.Required = append(schema.Required, s)
-			}
-		}
+	if req.Temperature != nil {
+		cfg.Temperature = genai.Ptr(float32(*req.Temperature))
 	}
-
-	return schema
+	if req.TopP != nil {
+		cfg.TopP = genai.Ptr(float32(*req.TopP))
+	}
+	if req.MaxTokens > 0 {
+		cfg.MaxOutputTokens = int32(req.MaxTokens)
+	}
+	if req.ThinkingBudget != nil {
+		cfg.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: genai.Ptr(int32(*req.ThinkingBudget))}
+	}
+	// Gemini refuses a JSON response type alongside function calling.
+	if len(req.ResponseSchema) > 0 && len(req.Tools) == 0 {
+		cfg.ResponseMIMEType = "application/json"
+		cfg.ResponseJsonSchema = req.ResponseSchema
+	}
+	return cfg
 }
 
-func convertPropertyToSchema(prop map[string]any) *genai.Schema {
-	schema := &genai.Schema{}
-
-	if t, ok := prop["type"].(string); ok {
-		switch t {
-		case "string":
-			schema.Type = genai.TypeString
-		case "number":
-			schema.Type = genai.TypeNumber
-		case "integer":
-			schema.Type = genai.TypeInteger
-		case "boolean":
-			schema.Type = genai.TypeBoolean
-		case "array":
-			schema.Type = genai.TypeArray
-		case "object":
-			schema.Type = genai.TypeObject
+// geminiToolConfig maps choice to Gemini's function calling mode; auto is
+// the default and needs none.
+func geminiToolConfig(choice ToolChoice) *genai.ToolConfig {
+	var cfg genai.FunctionCallingConfig
+	switch choice.Mode {
+	case ToolChoiceNone:
+		cfg.Mode = genai.FunctionCallingConfigModeNone
+	case ToolChoiceForce:
+		if choice.Name == "" {
+			return nil
 		}
+		cfg.Mode = genai.FunctionCallingConfigModeAny
+		cfg.AllowedFunctionNames = []string{choice.Name}
+	default:
+		return nil
 	}
-
-	if desc, ok := prop["description"].(string); ok {
-		schema.Description = desc
-	}
-
-	return schema
+	return &genai.ToolConfig{FunctionCallingConfig: &cfg}
 }
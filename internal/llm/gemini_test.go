@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestParseGeminiResponse_ParallelCalls(t *testing.T) {
+	resp := &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			FinishReason: genai.FinishReasonStop,
+			Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+				{Text: "weighing options", Thought: true},
+				{FunctionCall: &genai.FunctionCall{Name: "get_balances", Args: map[string]any{"chain": "base"}}, ThoughtSignature: []byte("sig")},
+				{FunctionCall: &genai.FunctionCall{Name: "get_balances", Args: map[string]any{"chain": "arbitrum"}}},
+			}},
+		}},
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, ThoughtsTokenCount: 20},
+	}
+
+	got, err := parseGeminiResponse(resp)
+	require.NoError(t, err)
+	assert.Empty(t, got.Content, "thoughts are not part of the answer")
+	require.Len(t, got.ToolCalls, 2)
+	assert.NotEqual(t, got.ToolCalls[0].ID, got.ToolCalls[1].ID, "calls to the same tool get distinct IDs")
+	assert.Equal(t, []byte("sig"), got.ToolCalls[0].Signature)
+	assert.Equal(t, Usage{InputTokens: 10, OutputTokens: 25}, got.Usage)
+}
+
+func TestGeminiConfig(t *testing.T) {
+	budget, temp := 0, 0.4
+	cfg := geminiConfig(&ChatRequest{
+		SystemPrompt:   "be brief",
+		Tools:          []Tool{{Name: "get_balances", InputSchema: json.RawMessage(`{"type":"object"}`)}},
+		Temperature:    &temp,
+		ThinkingBudget: &budget,
+		ResponseSchema: json.RawMessage(`{"type":"object"}`),
+	})
+	require.NotNil(t, cfg.ThinkingConfig)
+	assert.Equal(t, int32(0), *cfg.ThinkingConfig.ThinkingBudget)
+	assert.Equal(t, float32(0.4), *cfg.Temperature)
+	assert.Equal(t, "be brief", cfg.SystemInstruction.Parts[0].Text)
+	assert.Empty(t, cfg.ResponseMIMEType, "no JSON mode alongside tools")
+
+	cfg = geminiConfig(&ChatRequest{ResponseSchema: json.RawMessage(`{"type":"array"}`)})
+	assert.Equal(t, "application/json", cfg.ResponseMIMEType)
+	assert.Nil(t, cfg.ThinkingConfig)
+}
+
+func TestGeminiProvider_ToolResultsKeepIDs(t *testing.T) {
+	var sent struct {
+		Contents []*genai.Content `json:"contents"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &sent))
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"role":"model","parts":[{"text":"done"}]},"finishReason":"STOP"}]}`))
+	}))
+	defer srv.Close()
+
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPClient:  srv.Client(),
+		HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+	})
+	require.NoError(t, err)
+	p := &GeminiProvider{client: client, model: "gemini-2.5-flash"}
+
+	calls := []ToolCall{
+		{ID: "call-a", Name: "get_balances", Input: json.RawMessage(`{"chain":"base"}`)},
+		{ID: geminiLocalID + "1", Name: "get_balances", Input: json.RawMessage(`{"chain":"arbitrum"}`)},
+	}
+	results := []ToolResult{{ToolUseID: "call-a", Content: "1 ETH"}, {ToolUseID: geminiLocalID + "1", Content: "2 ETH"}}
+	resp, err := p.ChatWithToolResults(context.Background(), &ChatRequest{Messages: []Message{{Role: "user", Content: "balances?"}}}, calls, results)
+	require.NoError(t, err)
+	assert.Equal(t, "done", resp.Content)
+
+	require.Len(t, sent.Contents, 3)
+	responses := sent.Contents[2].Parts
+	require.Len(t, responses, 2)
+	assert.Equal(t, "call-a", responses[0].FunctionResponse.ID)
+	assert.Equal(t, "get_balances", responses[0].FunctionResponse.Name, "responses name the function, not the call ID")
+	assert.Empty(t, responses[1].FunctionResponse.ID, "local IDs are not sent")
+	assert.Equal(t, "get_balances", responses[1].FunctionResponse.Name)
+}
@@ -0,0 +1,13 @@
+package llm
+
+import (
+	"net/http"
+
+	"github.com/yolodolo42/clifi/internal/netcfg"
+)
+
+// httpClient is the client for a provider's requests, using its proxy and
+// CA settings (llm.providers.<id>.proxy and .ca_bundle over network.*).
+func httpClient(id ProviderID) (*http.Client, error) {
+	return netcfg.Client(netcfg.ProviderScope(string(id)))
+}
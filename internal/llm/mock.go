@@ -0,0 +1,160 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ProviderMock is the ID of MockProvider. It is not in AllProviderIDs: the
+// mock has no credentials and can't be connected.
+const ProviderMock ProviderID = "mock"
+
+// MockModels lists the mock's single model.
+var MockModels = []Model{
+	{ID: "mock-1", Name: "Scripted mock", ContextWindow: 200000, SupportsTools: true},
+}
+
+// MockTurn scripts the provider's side of one user turn. Responses[0]
+// answers Chat and each later response answers the following
+// ChatWithToolResults.
+type MockTurn struct {
+	// Match selects the turn when the user's message contains it
+	// (case-insensitive). Turns without Match are used in order for
+	// messages no Match fits.
+	Match     string
+	Responses []ChatResponse
+}
+
+// MockProvider replays scripted responses, tool calls included, so the
+// agent loop can run deterministically without an API key.
+type MockProvider struct {
+	mu       sync.Mutex
+	turns    []MockTurn
+	fallback int // next unmatched turn
+	current  *MockTurn
+	step     int
+	requests []ChatRequest
+}
+
+// NewMockProvider returns a provider that plays turns.
+func NewMockProvider(turns ...MockTurn) *MockProvider {
+	return &MockProvider{turns: turns}
+}
+
+func (p *MockProvider) ID() ProviderID      { return ProviderMock }
+func (p *MockProvider) Name() string        { return "Mock" }
+func (p *MockProvider) SupportsTools() bool { return true }
+func (p *MockProvider) Models() []Model     { return MockModels }
+
+func (p *MockProvider) DefaultModel() string { return MockModels[0].ID }
+
+func (p *MockProvider) SetModel(modelID string) error {
+	return ValidateModelID(modelID, MockModels)
+}
+
+// Requests returns the requests received so far, oldest first.
+func (p *MockProvider) Requests() []ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ChatRequest(nil), p.requests...)
+}
+
+// Chat starts a turn: it picks the scripted turn for the latest user
+// message and returns its first response.
+func (p *MockProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, *req)
+
+	p.current = p.pickTurn(lastUserMessage(req.Messages))
+	p.step = 0
+	if p.current == nil {
+		return mockResponse(req, "The mock provider has no scripted reply for that."), nil
+	}
+	return p.nextResponse(req, nil), nil
+}
+
+// ChatWithToolResults continues the current turn. Once its script runs out
+// it answers with the tool results themselves, so a turn can script just
+// the tool calls.
+func (p *MockProvider) ChatWithToolResults(ctx context.Context, req *ChatRequest, toolCalls []ToolCall, toolResults []ToolResult) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, *req)
+	return p.nextResponse(req, toolResults), nil
+}
+
+func (p *MockProvider) pickTurn(message string) *MockTurn {
+	lower := strings.ToLower(message)
+	for i := range p.turns {
+		if m := p.turns[i].Match; m != "" && strings.Contains(lower, strings.ToLower(m)) {
+			return &p.turns[i]
+		}
+	}
+	for range p.turns {
+		i := p.fallback % len(p.turns)
+		p.fallback++
+		if p.turns[i].Match == "" {
+			return &p.turns[i]
+		}
+	}
+	return nil
+}
+
+func (p *MockProvider) nextResponse(req *ChatRequest, results []ToolResult) *ChatResponse {
+	if p.current == nil || p.step >= len(p.current.Responses) {
+		var parts []string
+		for _, r := range results {
+			parts = append(parts, r.Content)
+		}
+		return mockResponse(req, strings.Join(parts, "\n"))
+	}
+	resp := p.current.Responses[p.step]
+	p.step++
+	// Tool call IDs must be unique across the conversation.
+	resp.ToolCalls = append([]ToolCall(nil), resp.ToolCalls...)
+	for i := range resp.ToolCalls {
+		if resp.ToolCalls[i].ID == "" {
+			resp.ToolCalls[i].ID = fmt.Sprintf("mock_%d_%d", len(p.requests), i)
+		}
+	}
+	if resp.StopReason == "" {
+		resp.StopReason = "end_turn"
+		if len(resp.ToolCalls) > 0 {
+			resp.StopReason = "tool_use"
+		}
+	}
+	resp.Usage = mockUsage(req, resp.Content)
+	return &resp
+}
+
+func mockResponse(req *ChatRequest, content string) *ChatResponse {
+	return &ChatResponse{Content: content, StopReason: "end_turn", Usage: mockUsage(req, content)}
+}
+
+// mockUsage estimates tokens at four characters each so usage displays
+// move plausibly.
+func mockUsage(req *ChatRequest, content string) Usage {
+	in := len(req.SystemPrompt)
+	for _, m := range req.Messages {
+		in += len(m.Content)
+	}
+	return Usage{InputTokens: in / 4, OutputTokens: len(content) / 4}
+}
+
+func lastUserMessage(msgs []Message) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == "user" {
+			return msgs[i].Content
+		}
+	}
+	return ""
+}
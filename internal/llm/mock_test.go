@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userReq(msg string) *ChatRequest {
+	return &ChatRequest{Messages: []Message{{Role: "user", Content: msg}}}
+}
+
+func TestMockProvider_ScriptedTurns(t *testing.T) {
+	ctx := context.Background()
+	p := NewMockProvider(
+		MockTurn{Match: "balance", Responses: []ChatResponse{
+			{ToolCalls: []ToolCall{{Name: "get_balances", Input: json.RawMessage(`{}`)}}},
+			{Content: "here they are"},
+		}},
+		MockTurn{Responses: []ChatResponse{{Content: "first"}}},
+		MockTurn{Responses: []ChatResponse{{Content: "second"}}},
+	)
+
+	resp, err := p.Chat(ctx, userReq("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", resp.Content, "unmatched messages take the unmatched turns in order")
+
+	resp, err = p.Chat(ctx, userReq("Show my BALANCES"))
+	require.NoError(t, err)
+	require.Len(t, resp.ToolCalls, 1)
+	assert.Equal(t, "get_balances", resp.ToolCalls[0].Name)
+	assert.NotEmpty(t, resp.ToolCalls[0].ID)
+	assert.Equal(t, "tool_use", resp.StopReason)
+
+	resp, err = p.ChatWithToolResults(ctx, userReq("Show my BALANCES"), resp.ToolCalls, []ToolResult{{Content: "1 ETH"}})
+	require.NoError(t, err)
+	assert.Equal(t, "here they are", resp.Content)
+
+	resp, err = p.ChatWithToolResults(ctx, userReq("Show my BALANCES"), nil, []ToolResult{{Content: "1 ETH"}})
+	require.NoError(t, err)
+	assert.Equal(t, "1 ETH", resp.Content, "past the script, tool results are echoed")
+
+	resp, err = p.Chat(ctx, userReq("again"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", resp.Content)
+	resp, err = p.Chat(ctx, userReq("and again"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", resp.Content, "unmatched turns wrap around")
+
+	assert.Len(t, p.Requests(), 6)
+}
+
+func TestMockProvider_NoScript(t *testing.T) {
+	p := NewMockProvider()
+	resp, err := p.Chat(context.Background(), userReq("hi"))
+	require.NoError(t, err)
+	assert.Contains(t, resp.Content, "no scripted reply")
+	assert.NoError(t, p.SetModel("mock-1"))
+	assert.Error(t, p.SetModel("gpt-4"))
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
+	"net/http"
 	"strings"
 
 	openai "github.com/sashabaranov/go-openai"
@@ -17,6 +20,9 @@ type OpenAIProvider struct {
 	model   string
 	baseURL string
 	stream  bool
+	// structured marks APIs with json_schema response formats; most
+	// OpenAI-compatible services lack them.
+	structured bool
 }
 
 // OpenAIModels lists available OpenAI models
@@ -57,14 +63,31 @@ var OpenAIModels = []Model{
 
 // NewOpenAIProvider creates a new OpenAI provider
 func NewOpenAIProvider(apiKey string, model string, baseURL string) (*OpenAIProvider, error) {
+	return newOpenAIProvider(apiKey, model, baseURL, ProviderOpenAI)
+}
+
+// newOpenAIProvider creates a client for an OpenAI-compatible API, using
+// the network settings of provider id.
+func newOpenAIProvider(apiKey, model, baseURL string, id ProviderID) (*OpenAIProvider, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 
+	hc, err := httpClient(id)
+	if err != nil {
+		return nil, err
+	}
+	return newOpenAIProviderWithClient(apiKey, model, baseURL, id, hc)
+}
+
+// newOpenAIProviderWithClient is newOpenAIProvider for a caller that
+// wraps the HTTP client, e.g. to add its own authentication.
+func newOpenAIProviderWithClient(apiKey, model, baseURL string, id ProviderID, hc *http.Client) (*OpenAIProvider, error) {
 	config := openai.DefaultConfig(apiKey)
 	if baseURL != "" {
 		config.BaseURL = baseURL
 	}
+	config.HTTPClient = hc
 
 	client := openai.NewClientWithConfig(config)
 
@@ -73,10 +96,11 @@ func NewOpenAIProvider(apiKey string, model string, baseURL string) (*OpenAIProv
 	}
 
 	return &OpenAIProvider{
-		client:  client,
-		model:   model,
-		baseURL: baseURL,
-		stream:  true,
+		client:     client,
+		model:      model,
+		baseURL:    baseURL,
+		stream:     true,
+		structured: id == ProviderOpenAI,
 	}, nil
 }
 
@@ -95,9 +119,47 @@ func (p *OpenAIProvider) SupportsTools() bool {
 	return true
 }
 
-// Models returns available models
+// Models returns the chat models the API lists for this key, or the
+// static list when it can't be fetched.
 func (p *OpenAIProvider) Models() []Model {
-	return OpenAIModels
+	return liveModels(openAICatalog, OpenAIModels, p.fetchModels)
+}
+
+// fetchModels lists the API's chat models. The API gives IDs only; tool
+// support is assumed, as for an unknown model.
+func (p *OpenAIProvider) fetchModels(ctx context.Context) ([]Model, error) {
+	list, err := p.client.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var models []Model
+	for _, m := range list.Models {
+		if isOpenAIChatModel(m.ID) {
+			models = append(models, Model{ID: m.ID, SupportsTools: true})
+		}
+	}
+	return models, nil
+}
+
+// isOpenAIChatModel filters the embedding, audio and image models out of
+// OpenAI's model list.
+func isOpenAIChatModel(id string) bool {
+	chat := false
+	for _, prefix := range []string{"gpt-", "chatgpt-", "o1", "o3", "o4"} {
+		if strings.HasPrefix(id, prefix) {
+			chat = true
+			break
+		}
+	}
+	if !chat {
+		return false
+	}
+	for _, kind := range []string{"audio", "realtime", "tts", "transcribe", "image", "search", "instruct"} {
+		if strings.Contains(id, kind) {
+			return false
+		}
+	}
+	return true
 }
 
 // DefaultModel returns the default model
@@ -173,6 +235,13 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRespo
 	if len(tools) > 0 {
 		openaiReq.Tools = tools
 	}
+	setSampling(&openaiReq, req)
+	if p.structured && len(req.ResponseSchema) > 0 {
+		openaiReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type:       openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{Name: "answer", Schema: req.ResponseSchema},
+		}
+	}
 
 	if tc := mapToolChoice(req.ToolChoice, len(tools) > 0); tc != nil {
 		openaiReq.ToolChoice = tc
@@ -186,6 +255,9 @@ func (p *OpenAIProvider) Chat(ctx context.Context, req *ChatRequest) (*ChatRespo
 		resp, err = p.streamChat(ctx, openaiReq)
 	}
 	if resp == nil || err != nil {
+		if err != nil && p.stream {
+			slog.Debug("streaming failed, retrying without", "provider", p.ID(), "err", err)
+		}
 		nonStream, err2 := p.client.CreateChatCompletion(ctx, openaiReq)
 		if err2 != nil {
 			return nil, fmt.Errorf("failed to create chat completion: %w", err2)
@@ -363,6 +435,13 @@ func (p *OpenAIProvider) ChatWithToolResults(ctx context.Context, req *ChatReque
 	if len(tools) > 0 {
 		openaiReq.Tools = tools
 	}
+	setSampling(&openaiReq, req)
+	if p.structured && len(req.ResponseSchema) > 0 {
+		openaiReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type:       openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{Name: "answer", Schema: req.ResponseSchema},
+		}
+	}
 
 	if tc := mapToolChoice(req.ToolChoice, len(tools) > 0); tc != nil {
 		openaiReq.ToolChoice = tc
@@ -431,3 +510,21 @@ func mapToolChoice(choice ToolChoice, hasTools bool) any {
 		return "auto"
 	}
 }
+
+// setSampling copies temperature and top_p to an OpenAI request. The
+// client drops zero values, so an explicit 0 is sent as the smallest
+// positive float instead.
+func setSampling(openaiReq *openai.ChatCompletionRequest, req *ChatRequest) {
+	nonZero := func(v float64) float32 {
+		if v == 0 {
+			return math.SmallestNonzeroFloat32
+		}
+		return float32(v)
+	}
+	if req.Temperature != nil {
+		openaiReq.Temperature = nonZero(*req.Temperature)
+	}
+	if req.TopP != nil {
+		openaiReq.TopP = nonZero(*req.TopP)
+	}
+}
@@ -16,7 +16,7 @@ func newOpenAICompatProvider(apiKey, model, baseURL string, id ProviderID, name
 		model = defaultModel
 	}
 
-	base, err := NewOpenAIProvider(apiKey, model, baseURL)
+	base, err := newOpenAIProvider(apiKey, model, baseURL, id)
 	if err != nil {
 		return nil, err
 	}
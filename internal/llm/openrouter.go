@@ -1,14 +1,21 @@
 package llm
 
 import (
+	"context"
 	"fmt"
 )
 
 const openRouterBaseURL = "https://openrouter.ai/api/v1"
 
-type OpenRouterProvider = OpenAICompatProvider
+// OpenRouterProvider is an OpenAI-compatible provider whose model list is
+// OpenRouter's live catalog rather than a fixed one.
+type OpenRouterProvider struct {
+	*OpenAICompatProvider
+	apiKey string
+}
 
-// OpenRouterModels lists popular OpenRouter models
+// OpenRouterModels lists popular OpenRouter models, used when the live
+// catalog can't be fetched
 var OpenRouterModels = []Model{
 	{
 		ID:            "anthropic/claude-3.7-sonnet",
@@ -65,7 +72,7 @@ func NewOpenRouterProvider(apiKey string, model string) (*OpenRouterProvider, er
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
-	return newOpenAICompatProvider(
+	base, err := newOpenAICompatProvider(
 		apiKey,
 		model,
 		openRouterBaseURL,
@@ -74,4 +81,28 @@ func NewOpenRouterProvider(apiKey string, model string) (*OpenRouterProvider, er
 		OpenRouterModels,
 		"anthropic/claude-3.5-sonnet",
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenRouterProvider{OpenAICompatProvider: base, apiKey: apiKey}, nil
+}
+
+// Models returns OpenRouter's live catalog, or the static list when it
+// can't be fetched.
+func (p *OpenRouterProvider) Models() []Model {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+	if models, _ := OpenRouterCatalog(ctx, p.apiKey); len(models) > 0 {
+		return models
+	}
+	return p.models
+}
+
+// SetModel switches to any model in the catalog.
+func (p *OpenRouterProvider) SetModel(modelID string) error {
+	if err := ValidateModelID(modelID, p.Models()); err != nil {
+		return err
+	}
+	p.model = modelID
+	return nil
 }
@@ -54,6 +54,9 @@ type Model struct {
 	InputCost     float64 `json:"input_cost"`  // per 1M tokens
 	OutputCost    float64 `json:"output_cost"` // per 1M tokens
 	SupportsTools bool    `json:"supports_tools"`
+	// Unlisted marks a model the provider offers that clifi has no details
+	// for, so its context window and prices are unknown.
+	Unlisted bool `json:"unlisted,omitempty"`
 }
 
 // Message represents a conversation message
@@ -67,6 +70,9 @@ type ToolCall struct {
 	ID    string          `json:"id"`
 	Name  string          `json:"name"`
 	Input json.RawMessage `json:"input"`
+	// Signature is opaque provider state that must go back with the call,
+	// such as a Gemini thought signature.
+	Signature []byte `json:"signature,omitempty"`
 }
 
 // ChatRequest is a provider-agnostic chat request
@@ -77,6 +83,15 @@ type ChatRequest struct {
 	Model        string     `json:"model,omitempty"` // Uses default if empty
 	ToolChoice   ToolChoice `json:"tool_choice,omitempty"`
 	MaxTokens    int        `json:"max_tokens,omitempty"`
+	Temperature  *float64   `json:"temperature,omitempty"`
+	TopP         *float64   `json:"top_p,omitempty"`
+	// ThinkingBudget caps a Gemini model's thinking tokens: 0 turns
+	// thinking off, -1 lets the model decide.
+	ThinkingBudget *int `json:"thinking_budget,omitempty"`
+	// ResponseSchema asks for a final answer that is JSON matching this
+	// JSON Schema. Providers that can enforce it natively do; the rest
+	// ignore it, so callers still validate the answer.
+	ResponseSchema json.RawMessage `json:"response_schema,omitempty"`
 }
 
 // ChatResponse is a provider-agnostic chat response
@@ -2,10 +2,15 @@ package llm
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/liushuangls/go-anthropic/v2"
+	"github.com/sashabaranov/go-openai"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
 )
 
 // mockProvider is a test implementation of Provider
@@ -193,3 +198,168 @@ func TestMockProvider_SetModel(t *testing.T) {
 		assert.Contains(t, err.Error(), "unknown model")
 	})
 }
+
+func TestFetchOpenRouterModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer or-key", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "anthropic/claude-sonnet-4", "name": "Claude Sonnet 4", "context_length": 200000,
+			 "pricing": {"prompt": "0.000003", "completion": "0.000015"},
+			 "supported_parameters": ["tools", "temperature"]},
+			{"id": "some/base-model", "pricing": {"prompt": "0", "completion": "0"}},
+			{"name": "no id"}
+		]}`))
+	}))
+	defer server.Close()
+	orig := openRouterModelsURL
+	openRouterModelsURL = server.URL
+	defer func() { openRouterModelsURL = orig }()
+
+	models, err := FetchOpenRouterModels(context.Background(), "or-key")
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.Equal(t, "Claude Sonnet 4", models[0].Name)
+	assert.Equal(t, 200000, models[0].ContextWindow)
+	assert.InDelta(t, 3.0, models[0].InputCost, 1e-9)
+	assert.InDelta(t, 15.0, models[0].OutputCost, 1e-9)
+	assert.True(t, models[0].SupportsTools)
+	assert.False(t, models[1].SupportsTools)
+}
+
+func TestOpenRouterProvider_LiveCatalog(t *testing.T) {
+	fail := false
+	var fetches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "qwen/qwen3-coder", "name": "Qwen3 Coder", "supported_parameters": ["tools"]},
+			{"id": "anthropic/claude-sonnet-4", "name": "Claude Sonnet 4"}
+		]}`))
+	}))
+	defer server.Close()
+	orig, origCatalog := openRouterModelsURL, openRouterCatalog
+	openRouterModelsURL, openRouterCatalog = server.URL, &modelCatalog{}
+	defer func() { openRouterModelsURL, openRouterCatalog = orig, origCatalog }()
+
+	p, err := NewOpenRouterProvider("or-key", "")
+	require.NoError(t, err)
+	require.Len(t, p.Models(), 2)
+	require.NoError(t, p.SetModel("qwen/qwen3-coder"), "models outside the static list")
+	assert.Equal(t, "qwen/qwen3-coder", p.DefaultModel())
+	assert.Error(t, p.SetModel("no/such-model"))
+	assert.Equal(t, 1, fetches, "the catalog is cached")
+
+	supports, known := SupportsToolsForModel(context.Background(), p, "qwen/qwen3-coder", "or-key")
+	assert.True(t, known)
+	assert.True(t, supports)
+
+	// Without the catalog the static list is the fallback.
+	fail = true
+	openRouterCatalog = &modelCatalog{}
+	assert.Equal(t, OpenRouterModels, p.Models())
+	assert.Equal(t, OpenRouterModels, p.Models())
+	assert.Equal(t, 2, fetches, "a failed fetch isn't retried at once")
+}
+
+func TestAnthropicProvider_LiveModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "sk-ant", r.Header.Get("x-api-key"))
+		assert.NotEmpty(t, r.Header.Get("anthropic-version"))
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "claude-opus-4-1-20250805", "display_name": "Claude Opus 4.1"},
+			{"id": "claude-sonnet-4-20250514", "display_name": "Claude Sonnet 4"}
+		]}`))
+	}))
+	defer server.Close()
+	orig, origCatalog := anthropicModelsURL, anthropicCatalog
+	anthropicModelsURL, anthropicCatalog = server.URL, &modelCatalog{}
+	defer func() { anthropicModelsURL, anthropicCatalog = orig, origCatalog }()
+
+	p, err := NewAnthropicProvider("sk-ant", "")
+	require.NoError(t, err)
+	models := p.Models()
+	require.Len(t, models, 2)
+	assert.Equal(t, AnthropicModels[0], models[0], "known models keep their prices")
+	assert.Equal(t, Model{ID: "claude-opus-4-1-20250805", Name: "Claude Opus 4.1", SupportsTools: true, Unlisted: true}, models[1])
+	require.NoError(t, p.SetModel("claude-opus-4-1-20250805"))
+	assert.Error(t, p.SetModel("claude-3-opus-20240229"), "no longer offered")
+}
+
+func TestOpenAIProvider_LiveModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/models", r.URL.Path)
+		_, _ = w.Write([]byte(`{"object": "list", "data": [
+			{"id": "gpt-4o", "object": "model"},
+			{"id": "text-embedding-3-small", "object": "model"},
+			{"id": "gpt-4o-realtime-preview", "object": "model"},
+			{"id": "o3-mini", "object": "model"}
+		]}`))
+	}))
+	defer server.Close()
+	orig := openAICatalog
+	openAICatalog = &modelCatalog{}
+	defer func() { openAICatalog = orig }()
+
+	p, err := NewOpenAIProvider("sk-test", "", server.URL)
+	require.NoError(t, err)
+	models := p.Models()
+	require.Len(t, models, 2)
+	assert.Equal(t, "gpt-4o", models[0].ID)
+	assert.False(t, models[0].Unlisted)
+	assert.Equal(t, "o3-mini", models[1].ID)
+	assert.True(t, models[1].Unlisted)
+}
+
+func TestIsOpenAIChatModel(t *testing.T) {
+	for _, id := range []string{"gpt-4.1", "gpt-5-mini", "chatgpt-4o-latest", "o1", "o4-mini"} {
+		assert.True(t, isOpenAIChatModel(id), id)
+	}
+	for _, id := range []string{"text-embedding-3-large", "whisper-1", "dall-e-3", "gpt-4o-mini-tts", "gpt-image-1", "gpt-3.5-turbo-instruct", "omni-moderation-latest"} {
+		assert.False(t, isOpenAIChatModel(id), id)
+	}
+}
+
+func TestSearchModels(t *testing.T) {
+	models := []Model{
+		{ID: "anthropic/claude-sonnet-4", Name: "Claude Sonnet 4"},
+		{ID: "openai/gpt-4o", Name: "GPT-4o"},
+		{ID: "qwen/qwen3-coder", Name: "Qwen3 Coder"},
+	}
+	ids := func(ms []Model) []string {
+		var out []string
+		for _, m := range ms {
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	assert.Len(t, SearchModels(models, ""), 3)
+	assert.Equal(t, []string{"anthropic/claude-sonnet-4"}, ids(SearchModels(models, "Claude")))
+	assert.Equal(t, []string{"qwen/qwen3-coder"}, ids(SearchModels(models, "qwen coder")), "every word must match")
+	assert.Empty(t, SearchModels(models, "claude coder"))
+	assert.Equal(t, []string{"openai/gpt-4o"}, ids(SearchModels(models, "openai/")))
+}
+
+func TestToolChoiceMapping(t *testing.T) {
+	force := ToolChoice{Mode: ToolChoiceForce, Name: "get_balances"}
+	none := ToolChoice{Mode: ToolChoiceNone}
+
+	assert.Nil(t, mapToolChoice(ToolChoice{}, true))
+	assert.Equal(t, "none", mapToolChoice(none, true))
+	assert.Equal(t, openai.ToolChoice{Type: openai.ToolTypeFunction, Function: openai.ToolFunction{Name: "get_balances"}}, mapToolChoice(force, true))
+
+	assert.Nil(t, anthropicToolChoice(ToolChoice{}))
+	assert.Equal(t, &anthropic.ToolChoice{Type: "none"}, anthropicToolChoice(none))
+	assert.Equal(t, &anthropic.ToolChoice{Type: "tool", Name: "get_balances"}, anthropicToolChoice(force))
+
+	assert.Nil(t, geminiToolConfig(ToolChoice{Mode: ToolChoiceAuto}))
+	cfg := geminiToolConfig(force)
+	require.NotNil(t, cfg)
+	assert.Equal(t, genai.FunctionCallingConfigModeAny, cfg.FunctionCallingConfig.Mode)
+	assert.Equal(t, []string{"get_balances"}, cfg.FunctionCallingConfig.AllowedFunctionNames)
+	assert.Equal(t, genai.FunctionCallingConfigModeNone, geminiToolConfig(none).FunctionCallingConfig.Mode)
+}
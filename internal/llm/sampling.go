@@ -0,0 +1,142 @@
+package llm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sampling holds generation parameters. Unset fields leave the provider's
+// default in place.
+type Sampling struct {
+	Temperature *float64 `json:"temperature,omitempty" mapstructure:"temperature"`
+	TopP        *float64 `json:"top_p,omitempty" mapstructure:"top_p"`
+	MaxTokens   int      `json:"max_tokens,omitempty" mapstructure:"max_tokens"`
+	// ThinkingBudget is Gemini's thinking token budget; -1 is dynamic.
+	ThinkingBudget *int `json:"thinking_budget,omitempty" mapstructure:"thinking_budget"`
+}
+
+// SamplingParams are the names Sampling.Set accepts.
+var SamplingParams = []string{"temperature", "top_p", "max_tokens", "thinking_budget"}
+
+// maxTemperature is the highest temperature a provider accepts.
+func maxTemperature(id ProviderID) float64 {
+	if id == ProviderAnthropic {
+		return 1
+	}
+	return 2
+}
+
+// IsZero reports whether no parameter is set.
+func (s Sampling) IsZero() bool {
+	return s.Temperature == nil && s.TopP == nil && s.MaxTokens == 0 && s.ThinkingBudget == nil
+}
+
+// Merge returns s with the parameters set in over replacing its own.
+func (s Sampling) Merge(over Sampling) Sampling {
+	if over.Temperature != nil {
+		s.Temperature = over.Temperature
+	}
+	if over.TopP != nil {
+		s.TopP = over.TopP
+	}
+	if over.MaxTokens != 0 {
+		s.MaxTokens = over.MaxTokens
+	}
+	if over.ThinkingBudget != nil {
+		s.ThinkingBudget = over.ThinkingBudget
+	}
+	return s
+}
+
+// Set parses value into the named parameter. "default" clears it.
+func (s *Sampling) Set(name, value string) error {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", "_"))
+	reset := strings.EqualFold(value, "default")
+	switch name {
+	case "temperature", "top_p":
+		p := &s.Temperature
+		if name == "top_p" {
+			p = &s.TopP
+		}
+		if reset {
+			*p = nil
+			return nil
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a number", name, value)
+		}
+		*p = &v
+	case "max_tokens":
+		if reset {
+			s.MaxTokens = 0
+			return nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil || v <= 0 {
+			return fmt.Errorf("max_tokens: %q is not a positive whole number", value)
+		}
+		s.MaxTokens = v
+	case "thinking_budget":
+		if reset {
+			s.ThinkingBudget = nil
+			return nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil || v < -1 {
+			return fmt.Errorf("thinking_budget: %q is not a token count (0 is off, -1 dynamic)", value)
+		}
+		s.ThinkingBudget = &v
+	default:
+		return fmt.Errorf("unknown parameter %q (want %s)", name, strings.Join(SamplingParams, ", "))
+	}
+	return nil
+}
+
+// Validate checks the parameters against provider id's ranges.
+func (s Sampling) Validate(id ProviderID) error {
+	if t := s.Temperature; t != nil {
+		if maxT := maxTemperature(id); *t < 0 || *t > maxT {
+			return fmt.Errorf("temperature must be between 0 and %g for %s", maxT, id)
+		}
+	}
+	if p := s.TopP; p != nil && (*p <= 0 || *p > 1) {
+		return fmt.Errorf("top_p must be above 0 and at most 1")
+	}
+	if s.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be positive")
+	}
+	if s.ThinkingBudget != nil && id != ProviderGemini {
+		return fmt.Errorf("thinking_budget is only supported by %s", ProviderGemini)
+	}
+	return nil
+}
+
+// Apply sets the parameters on req.
+func (s Sampling) Apply(req *ChatRequest) {
+	req.Temperature, req.TopP, req.ThinkingBudget = s.Temperature, s.TopP, s.ThinkingBudget
+	if s.MaxTokens > 0 {
+		req.MaxTokens = s.MaxTokens
+	}
+}
+
+func (s Sampling) String() string {
+	var parts []string
+	if s.Temperature != nil {
+		parts = append(parts, "temperature "+strconv.FormatFloat(*s.Temperature, 'g', -1, 64))
+	}
+	if s.TopP != nil {
+		parts = append(parts, "top_p "+strconv.FormatFloat(*s.TopP, 'g', -1, 64))
+	}
+	if s.MaxTokens > 0 {
+		parts = append(parts, "max_tokens "+strconv.Itoa(s.MaxTokens))
+	}
+	if s.ThinkingBudget != nil {
+		parts = append(parts, "thinking_budget "+strconv.Itoa(*s.ThinkingBudget))
+	}
+	if len(parts) == 0 {
+		return "provider defaults"
+	}
+	return strings.Join(parts, ", ")
+}
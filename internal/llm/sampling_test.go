@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampling(t *testing.T) {
+	var s Sampling
+	require.NoError(t, s.Set("temperature", "0.2"))
+	require.NoError(t, s.Set("top-p", "0.9"))
+	require.NoError(t, s.Set("max_tokens", "2000"))
+	assert.Equal(t, "temperature 0.2, top_p 0.9, max_tokens 2000", s.String())
+
+	assert.Error(t, s.Set("temperature", "warm"))
+	assert.Error(t, s.Set("max_tokens", "-1"))
+	assert.ErrorContains(t, s.Set("top_k", "5"), "unknown parameter")
+
+	hot := 1.5
+	base := Sampling{Temperature: &hot, MaxTokens: 100}
+	merged := base.Merge(s)
+	assert.Equal(t, 0.2, *merged.Temperature)
+	assert.Equal(t, 2000, merged.MaxTokens)
+
+	require.NoError(t, s.Set("temperature", "default"))
+	assert.Nil(t, s.Temperature)
+	assert.Equal(t, 1.5, *base.Merge(s).Temperature, "unset parameters fall through")
+
+	assert.NoError(t, base.Validate(ProviderOpenAI))
+	assert.ErrorContains(t, base.Validate(ProviderAnthropic), "between 0 and 1")
+	zero := 0.0
+	assert.Error(t, Sampling{TopP: &zero}.Validate(ProviderGemini))
+
+	req := &ChatRequest{MaxTokens: 4096}
+	Sampling{Temperature: &hot}.Apply(req)
+	assert.Equal(t, 1.5, *req.Temperature)
+	assert.Equal(t, 4096, req.MaxTokens)
+	assert.Equal(t, "provider defaults", Sampling{}.String())
+
+	var g Sampling
+	require.NoError(t, g.Set("thinking_budget", "-1"))
+	assert.NoError(t, g.Validate(ProviderGemini))
+	assert.ErrorContains(t, g.Validate(ProviderOpenAI), "only supported by gemini")
+	assert.Error(t, g.Set("thinking_budget", "-2"))
+	g.Apply(req)
+	assert.Equal(t, -1, *req.ThinkingBudget)
+}
@@ -43,7 +43,7 @@ func CryptoTools() []Tool {
 	return []Tool{
 		{
 			Name:        "get_balances",
-			Description: "Get native token balances for an address across multiple chains",
+			Description: "Get native token balances, and optionally ERC20 token balances, for an address across multiple chains",
 			InputSchema: json.RawMessage(`{
 				"type": "object",
 				"properties": {
@@ -55,6 +55,11 @@ func CryptoTools() []Tool {
 						"type": "array",
 						"items": {"type": "string"},
 						"description": "List of chains to query (e.g., ethereum, base, arbitrum)"
+					},
+					"tokens": {
+						"type": "object",
+						"additionalProperties": {"type": "array", "items": {"type": "string"}},
+						"description": "Optional ERC20 tokens to include, keyed by chain, as contract addresses or listed symbols (e.g., {\"base\": [\"USDC\", \"0x...\"]})"
 					}
 				},
 				"required": ["address"]
@@ -72,7 +77,7 @@ func CryptoTools() []Tool {
 					},
 					"token": {
 						"type": "string",
-						"description": "Token contract address"
+						"description": "Token contract address, or a symbol such as USDC that is on a configured token list"
 					},
 					"chain": {
 						"type": "string",
@@ -112,6 +117,53 @@ func CryptoTools() []Tool {
 				"properties": {}
 			}`),
 		},
+		{
+			Name:        "get_gas_price",
+			Description: "Get current gas prices on a chain: base fee, priority fee and, on Ethereum L1, the EIP-4844 blob base fee",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {
+						"type": "string",
+						"description": "Chain name (default: ethereum)"
+					}
+				}
+			}`),
+		},
+		{
+			Name:        "get_quote",
+			Description: "Compare swap and bridge routes without building a transaction: expected output, minimum received after slippage, price impact, gas cost and time for the best routes",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"from_chain": {
+						"type": "string",
+						"description": "Chain to swap or bridge from"
+					},
+					"to_chain": {
+						"type": "string",
+						"description": "Destination chain (default: from_chain, i.e. a same-chain swap)"
+					},
+					"from_token": {
+						"type": "string",
+						"description": "Token to sell: contract address, token list symbol (e.g. USDC), or \"native\"/the native currency symbol"
+					},
+					"to_token": {
+						"type": "string",
+						"description": "Token to receive on to_chain, in the same forms as from_token"
+					},
+					"amount": {
+						"type": "string",
+						"description": "Amount of from_token to sell, in human units (e.g. \"1.5\")"
+					},
+					"slippage_bps": {
+						"type": "integer",
+						"description": "Slippage tolerance in basis points (default: CLIFI_SLIPPAGE_BPS or 50)"
+					}
+				},
+				"required": ["from_chain", "from_token", "to_token", "amount"]
+			}`),
+		},
 		{
 			Name:        "send_native",
 			Description: "Send native tokens on an EVM chain with safety checks and confirmation",
@@ -124,7 +176,8 @@ func CryptoTools() []Tool {
 					"amount_eth": {"type": "string", "description": "Amount in ETH (decimal string)"},
 					"password": {"type": "string", "description": "Keystore password for the from account"},
 					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false},
-					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true}
+					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true},
+					"private": {"type": "boolean", "description": "Submit via a private relay (Flashbots Protect) instead of the public mempool to avoid sandwiching; defaults to CLIFI_PRIVATE_TX, false forces public"}
 				},
 				"required": ["to", "chain", "amount_eth"]
 			}`),
@@ -137,12 +190,13 @@ func CryptoTools() []Tool {
 				"properties": {
 					"from": {"type": "string", "description": "Sender address (0x...), defaults to first keystore account"},
 					"to": {"type": "string", "description": "Recipient address (0x...)"},
-					"token": {"type": "string", "description": "ERC20 contract address"},
+					"token": {"type": "string", "description": "ERC20 contract address, or a symbol such as USDC that is on a configured token list (ambiguous or unlisted symbols are rejected)"},
 					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
 					"amount_tokens": {"type": "string", "description": "Token amount in human-readable units"},
 					"password": {"type": "string", "description": "Keystore password for the from account"},
 					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false},
-					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true}
+					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true},
+					"private": {"type": "boolean", "description": "Submit via a private relay (Flashbots Protect) instead of the public mempool to avoid sandwiching; defaults to CLIFI_PRIVATE_TX, false forces public"}
 				},
 				"required": ["to", "token", "chain", "amount_tokens"]
 			}`),
@@ -155,16 +209,76 @@ func CryptoTools() []Tool {
 				"properties": {
 					"from": {"type": "string", "description": "Owner address (0x...), defaults to first keystore account"},
 					"spender": {"type": "string", "description": "Spender address (0x...)", "default": ""},
-					"token": {"type": "string", "description": "ERC20 contract address"},
+					"token": {"type": "string", "description": "ERC20 contract address, or a symbol such as USDC that is on a configured token list (ambiguous or unlisted symbols are rejected)"},
 					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
 					"amount_tokens": {"type": "string", "description": "Allowance amount in human-readable units"},
 					"password": {"type": "string", "description": "Keystore password"},
 					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false},
-					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true}
+					"wait": {"type": "boolean", "description": "Wait for receipt (default true)", "default": true},
+					"private": {"type": "boolean", "description": "Submit via a private relay (Flashbots Protect) instead of the public mempool to avoid sandwiching; defaults to CLIFI_PRIVATE_TX, false forces public"},
+					"permit2": {"type": "boolean", "description": "Grant the allowance as a signed Permit2 permit that expires, for protocols that accept Permit2 (e.g. Uniswap), instead of a lasting ERC20 allowance to the spender", "default": false},
+					"expiration_days": {"type": "integer", "description": "Days until a Permit2 allowance expires (default 30, max 365)"}
 				},
 				"required": ["spender", "token", "chain", "amount_tokens"]
 			}`),
 		},
+		{
+			Name:        "sign_permit",
+			Description: "Sign a gasless EIP-2612 permit letting a spender transfer an amount of a token that supports permit() (e.g. USDC), with a deadline. Preview first. With relay, a keystore wallet as spender submits the permit and a transferFrom right away, paying the gas, so the owner needs no native gas",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"owner": {"type": "string", "description": "Token owner address (0x...), defaults to first keystore account"},
+					"token": {"type": "string", "description": "ERC20 contract address, or a symbol such as USDC that is on a configured token list"},
+					"spender": {"type": "string", "description": "Spender address (0x...)"},
+					"amount_tokens": {"type": "string", "description": "Amount in human-readable units"},
+					"deadline_minutes": {"type": "integer", "description": "Minutes until the permit can no longer be submitted (default 60, max 10080)"},
+					"relay": {"type": "boolean", "description": "Submit permit() and transferFrom from the spender, which must be a keystore wallet", "default": false},
+					"to": {"type": "string", "description": "Recipient of the relayed transferFrom (default the spender)"},
+					"password": {"type": "string", "description": "Keystore password of the owner"},
+					"spender_password": {"type": "string", "description": "Keystore password of the spender when relaying (default the owner's)"},
+					"confirm": {"type": "boolean", "description": "Set true to sign after preview", "default": false}
+				},
+				"required": ["chain", "token", "spender", "amount_tokens"]
+			}`),
+		},
+		{
+			Name:        "send_token_gasless",
+			Description: "Send an EIP-3009 token (e.g. USDC) from a wallet with no native gas: the owner signs a transfer authorization and Gelato's relay submits it. The relay fee is quoted in the token and, when a fee recipient is configured, deducted from the amount. Preview first; needs CLIFI_RELAY_API_KEY",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., base, arbitrum"},
+					"from": {"type": "string", "description": "Sender address (0x...), defaults to first keystore account"},
+					"to": {"type": "string", "description": "Recipient address (0x...)"},
+					"token": {"type": "string", "description": "ERC20 contract address, or a symbol such as USDC that is on a configured token list"},
+					"amount_tokens": {"type": "string", "description": "Amount leaving the wallet in human-readable units; the recipient gets it less the relay fee"},
+					"max_fee_tokens": {"type": "string", "description": "Most relay fee to pay, in human-readable units: the fee from the preview. Required with confirm when the fee is deducted; a higher quote is refused"},
+					"password": {"type": "string", "description": "Keystore password"},
+					"confirm": {"type": "boolean", "description": "Set true to sign and relay after preview", "default": false}
+				},
+				"required": ["chain", "to", "token", "amount_tokens"]
+			}`),
+		},
+		{
+			Name:        "swap",
+			Description: "Swap tokens on one EVM mainnet through the best aggregator route. Sends an exact approval first when the router needs one. Preview first; waits for the swap to be mined",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"from": {"type": "string", "description": "Wallet address (0x...), defaults to first keystore account"},
+					"from_token": {"type": "string", "description": "Token to sell: symbol (e.g. USDC), contract address, or native"},
+					"to_token": {"type": "string", "description": "Token to buy: symbol (e.g. ETH), contract address, or native"},
+					"amount": {"type": "string", "description": "Amount of from_token in human-readable units"},
+					"slippage_bps": {"type": "integer", "description": "Slippage tolerance in basis points (default and ceiling from the policy)"},
+					"password": {"type": "string", "description": "Keystore password"},
+					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false}
+				},
+				"required": ["chain", "from_token", "to_token", "amount"]
+			}`),
+		},
 		{
 			Name:        "get_receipt",
 			Description: "Get a transaction receipt (cached when available) for an EVM chain",
@@ -177,6 +291,103 @@ func CryptoTools() []Tool {
 				"required": ["chain", "tx_hash"]
 			}`),
 		},
+		{
+			Name:        "explain_tx",
+			Description: "Explain any EVM transaction by hash: sender, value, fee, the decoded function call and decoded event logs (token transfers, approvals, swaps), using the contracts' published ABIs when available. Use it to audit unfamiliar activity",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"tx_hash": {"type": "string", "description": "Transaction hash (0x...)"}
+				},
+				"required": ["chain", "tx_hash"]
+			}`),
+		},
+		{
+			Name:        "trace_tx",
+			Description: "Trace a transaction's internal calls (contract-to-contract calls, reverts) and the native value each moved. Needs an RPC that supports debug_traceTransaction or trace_transaction; says so when it does not",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"tx_hash": {"type": "string", "description": "Transaction hash (0x...)"}
+				},
+				"required": ["chain", "tx_hash"]
+			}`),
+		},
+		{
+			Name:        "decode_calldata",
+			Description: "Decode raw EVM calldata (hex) into the function and its arguments, without any chain connection. Unknown selectors are looked up on 4byte.directory unless offline",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"data": {"type": "string", "description": "Calldata as hex (0x...)"},
+					"signature": {"type": "string", "description": "Optional function signature to decode with, e.g. transfer(address,uint256)"},
+					"abi": {"type": "string", "description": "Optional contract JSON ABI"},
+					"offline": {"type": "boolean", "description": "Skip the 4byte.directory lookup", "default": false}
+				},
+				"required": ["data"]
+			}`),
+		},
+		{
+			Name:        "query_logs",
+			Description: "Query EVM event logs (eth_getLogs) and show them decoded, newest first. Filter by contract address and by an event signature, whose indexed arguments can be matched by name with where; raw topics are also accepted",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"address": {"type": "array", "items": {"type": "string"}, "description": "Contract addresses emitting the logs"},
+					"event": {"type": "string", "description": "Event signature with indexed markers and names, e.g. Transfer(address indexed from,address indexed to,uint256 value)"},
+					"where": {"type": "object", "additionalProperties": {"type": "array", "items": {"type": "string"}}, "description": "Values to match per indexed argument name, e.g. {\"to\": [\"0x...\"]}; requires event"},
+					"topics": {"type": "array", "items": {"type": ["array", "null"], "items": {"type": "string"}}, "description": "Raw topics per position (32-byte hex or address); null matches anything. Use instead of event"},
+					"from_block": {"type": "string", "description": "Block number, latest, or an offset before to_block such as -5000 (default: 1000 blocks back)"},
+					"to_block": {"type": "string", "description": "Block number, latest, or an offset such as -100 (default latest)"},
+					"limit": {"type": "integer", "description": "Maximum logs to return (default 50, max 500)", "default": 50}
+				},
+				"required": ["chain"]
+			}`),
+		},
+		{
+			Name:        "get_account_state",
+			Description: "Diagnose stuck or missing sends: an address's latest (mined) and pending nonce, and the transactions clifi sent from it that are not mined yet, with whether the node still has them",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"address": {"type": "string", "description": "Account address (0x...)"}
+				},
+				"required": ["chain", "address"]
+			}`),
+		},
+		{
+			Name:        "read_storage",
+			Description: "Read a contract's storage slot, e.g. a proxy's implementation. Without slot it reads the EIP-1967 implementation, admin and beacon slots. Mapping entries are found from the mapping's declared slot and its keys",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"address": {"type": "string", "description": "Contract address (0x...)"},
+					"slot": {"type": "string", "description": "Slot number (decimal or hex), a 32-byte hex slot, or implementation, admin or beacon for the EIP-1967 slots. Omit to read all three EIP-1967 slots"},
+					"keys": {"type": "array", "items": {"type": "string"}, "description": "Mapping keys, outermost first, for a mapping declared at slot: addresses, unsigned integers, true/false or 32-byte hex"},
+					"index": {"type": "integer", "description": "Element of a dynamic array declared at slot (after any keys)"},
+					"block": {"type": "string", "description": "Block number, latest, or an offset such as -100 (default latest)"}
+				},
+				"required": ["chain", "address"]
+			}`),
+		},
+		{
+			Name:        "audit_approvals",
+			Description: "List the token allowances a wallet has granted that are still live: ERC20 approvals and Permit2 allowances with their expiry, flagging unlimited ones. Approvals are found from logs in recent blocks",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"owner": {"type": "string", "description": "Wallet address (0x...)"},
+					"blocks": {"type": "integer", "description": "Blocks to scan back from the head for approvals (default 50000, max 100000)"}
+				},
+				"required": ["chain", "owner"]
+			}`),
+		},
 		{
 			Name:        "wait_receipt",
 			Description: "Wait for a transaction to be mined and return its receipt",
@@ -190,5 +401,253 @@ func CryptoTools() []Tool {
 				"required": ["chain", "tx_hash"]
 			}`),
 		},
+		{
+			Name:        "get_private_tx_status",
+			Description: "Check the status of a transaction submitted through a private relay (Flashbots Protect): PENDING, INCLUDED, FAILED or CANCELLED",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name (default: ethereum)"},
+					"tx_hash": {"type": "string", "description": "Transaction hash (0x...)"}
+				},
+				"required": ["tx_hash"]
+			}`),
+		},
+		{
+			Name:        "request_faucet",
+			Description: "Request testnet funds (sepolia, base-sepolia) from configured faucets for a keystore wallet, optionally waiting until the balance arrives",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Testnet chain name, e.g., sepolia, base-sepolia"},
+					"address": {"type": "string", "description": "Keystore wallet address (defaults to the first wallet)"},
+					"wait": {"type": "boolean", "description": "Wait until the funds arrive (default true)", "default": true},
+					"timeout_sec": {"type": "integer", "description": "How long to wait for funds in seconds (default 120)", "default": 120}
+				},
+				"required": ["chain"]
+			}`),
+		},
+		{
+			Name:        "set_context",
+			Description: "Set the EVM chain and/or wallet to use for the rest of the session, e.g. when the user says \"use base and my hot wallet from now on\". Later tool calls that omit chain or from use them",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Chain name, e.g., base"},
+					"wallet": {"type": "string", "description": "Wallet label, address or number from list_wallets"},
+					"clear": {"type": "boolean", "description": "Drop the current defaults first", "default": false}
+				}
+			}`),
+		},
+		{
+			Name:        "get_portfolio_diff",
+			Description: "Show how an EVM portfolio's balances and USD values changed over a period, per asset, by comparing with a stored snapshot. Also records the current balances as a new snapshot",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"address": {"type": "string", "description": "EVM address (defaults to the default wallet)"},
+					"since": {"type": "string", "description": "Look-back period, e.g. 24h, 7d, 2w", "default": "7d"},
+					"chains": {"type": "array", "items": {"type": "string"}, "description": "Chains to compare (defaults to those in the earlier snapshot)"}
+				}
+			}`),
+		},
+		{
+			Name:        "get_pnl",
+			Description: "Show per-asset cost basis and realized/unrealized profit and loss (FIFO) for an EVM wallet, from the transactions clifi has recorded. Optionally export the disposals as CSV for taxes",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"address": {"type": "string", "description": "EVM address (defaults to the default wallet)"},
+					"csv": {"type": "boolean", "description": "Also write a CSV of disposals (one row per lot) under the data directory", "default": false}
+				}
+			}`),
+		},
+		{
+			Name:        "create_watch",
+			Description: "Create a price or balance alert, e.g. \"tell me when ETH crosses 4000\". Alerts are delivered while `clifi serve` is running",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"condition": {"type": "string", "description": "Condition such as \"ETH > 4000\", \"ARB@arbitrum < 0.5\" (USD price) or \"balance base < 0.1\", \"balance base hot < 0.1\" (native balance of the default or a labelled wallet)"},
+					"once": {"type": "boolean", "description": "Remove the watch after it fires once", "default": false}
+				},
+				"required": ["condition"]
+			}`),
+		},
+		{
+			Name:        "remember",
+			Description: "Save a note about the user that persists across sessions, such as an address they mention (\"alice is 0x...\") or a preference (\"always use fast gas\"). Saving under an existing key replaces it. Never store private keys, seed phrases or passwords",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"key": {"type": "string", "description": "Short name for the note, e.g. alice, gas-preference"},
+					"value": {"type": "string", "description": "What to remember"},
+					"forget": {"type": "boolean", "description": "Delete the note under key instead", "default": false}
+				},
+				"required": ["key"]
+			}`),
+		},
+		{
+			Name:        "recall",
+			Description: "Search the notes saved with remember. Everything remembered is also listed in the system prompt; use this to look entries up by word",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"query": {"type": "string", "description": "Words to match in keys and values; omit to list every note"}
+				}
+			}`),
+		},
+		{
+			Name:        "search_notes",
+			Description: "Search the notes the user imported (protocol docs, lists of contract addresses). Use it before stating a contract address or protocol detail, and prefer its answer to what you recall",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"query": {"type": "string", "description": "Words to search for, e.g. \"aave pool base\" or an address"},
+					"limit": {"type": "integer", "description": "Most passages to return (default 5, max 10)"}
+				},
+				"required": ["query"]
+			}`),
+		},
+		{
+			Name:        "create_dca",
+			Description: "Create a recurring swap (dollar-cost averaging), e.g. \"buy ETH with 50 USDC every week\". Runs unattended under the policy limits while `clifi serve` is running. Preview first",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"plan": {"type": "string", "description": "Plan such as \"swap 50 USDC to ETH weekly\" or \"swap 0.1 ETH to USDC every 3 days\" (hourly, daily, weekly, monthly, every N hours/days/weeks/months)"},
+					"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+					"wallet": {"type": "string", "description": "Wallet address (0x...), defaults to first keystore account"},
+					"slippage_bps": {"type": "integer", "description": "Slippage tolerance in basis points (default and ceiling from the policy)"},
+					"confirm": {"type": "boolean", "description": "Set true to create the plan after preview", "default": false}
+				},
+				"required": ["plan", "chain"]
+			}`),
+		},
+		{
+			Name:        "create_limit_order",
+			Description: "Create an off-chain limit order, e.g. \"sell 1 ETH if price hits 4200\". `clifi serve` polls the price; a triggered order waits for the user's `clifi order confirm` unless auto-approve is configured. Preview first",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"order": {"type": "string", "description": "Order such as \"sell 1 ETH if price hits 4200\", \"sell 2 ETH if price drops below 3000\" or \"buy 0.5 ETH with DAI if price hits 3000\" (sells for and buys with USDC unless named)"},
+					"chain": {"type": "string", "description": "Chain to trade and price on, e.g., ethereum, base"},
+					"wallet": {"type": "string", "description": "Wallet address (0x...), defaults to first keystore account"},
+					"slippage_bps": {"type": "integer", "description": "Slippage tolerance in basis points (default and ceiling from the policy)"},
+					"confirm": {"type": "boolean", "description": "Set true to create the order after preview", "default": false}
+				},
+				"required": ["order", "chain"]
+			}`),
+		},
+		{
+			Name:        "get_solana_balance",
+			Description: "Get the native SOL balance of a Solana address",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"address": {"type": "string", "description": "Solana address (base58), defaults to the first Solana wallet"},
+					"cluster": {"type": "string", "description": "Cluster: solana (mainnet), solana-devnet, solana-testnet", "default": "solana"}
+				}
+			}`),
+		},
+		{
+			Name:        "get_spl_token_balances",
+			Description: "Get SPL token balances (Token and Token-2022 programs) held by a Solana address",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"address": {"type": "string", "description": "Solana address (base58), defaults to the first Solana wallet"},
+					"cluster": {"type": "string", "description": "Cluster: solana (mainnet), solana-devnet, solana-testnet", "default": "solana"},
+					"mint": {"type": "string", "description": "Only return this token mint (base58)"}
+				}
+			}`),
+		},
+		{
+			Name:        "list_solana_wallets",
+			Description: "List Solana wallets in the local Solana keystore",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {}
+			}`),
+		},
+		{
+			Name:        "send_sol",
+			Description: "Send native SOL on a Solana cluster with a preview and confirmation",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"from": {"type": "string", "description": "Sender address (base58), defaults to the first Solana wallet"},
+					"to": {"type": "string", "description": "Recipient address (base58)"},
+					"cluster": {"type": "string", "description": "Cluster: solana (mainnet), solana-devnet, solana-testnet", "default": "solana"},
+					"amount_sol": {"type": "string", "description": "Amount in SOL (decimal string)"},
+					"password": {"type": "string", "description": "Keystore password for the from wallet"},
+					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false},
+					"wait": {"type": "boolean", "description": "Wait for confirmation (default true)", "default": true}
+				},
+				"required": ["to", "amount_sol"]
+			}`),
+		},
+		{
+			Name:        "get_cosmos_balances",
+			Description: "Get bank balances on a Cosmos SDK chain, with IBC denoms resolved to their origin",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"chain": {"type": "string", "description": "Cosmos chain: cosmoshub, osmosis"},
+					"address": {"type": "string", "description": "Bech32 address (cosmos1..., osmo1...), defaults to the first Cosmos wallet"}
+				},
+				"required": ["chain"]
+			}`),
+		},
+		{
+			Name:        "list_cosmos_wallets",
+			Description: "List Cosmos wallets in the local Cosmos keystore with their address on each chain",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {}
+			}`),
+		},
+		{
+			Name:        "send_cosmos",
+			Description: "Send tokens on a Cosmos SDK chain (bank MsgSend) with safety checks and confirmation",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"from": {"type": "string", "description": "Sender bech32 address, defaults to the first Cosmos wallet"},
+					"to": {"type": "string", "description": "Recipient bech32 address on the same chain"},
+					"chain": {"type": "string", "description": "Cosmos chain: cosmoshub, osmosis"},
+					"amount": {"type": "string", "description": "Amount in display units (e.g. 1.5 ATOM)"},
+					"denom": {"type": "string", "description": "Denom to send; defaults to the chain's native token. Use the base denom (e.g. ibc/...) for other assets"},
+					"decimals": {"type": "integer", "description": "Decimals of denom; required for non-native denoms"},
+					"memo": {"type": "string", "description": "Optional memo (required by some exchanges)"},
+					"password": {"type": "string", "description": "Keystore password for the from wallet"},
+					"confirm": {"type": "boolean", "description": "Set true to broadcast after preview", "default": false},
+					"wait": {"type": "boolean", "description": "Wait for inclusion (default true)", "default": true}
+				},
+				"required": ["to", "chain", "amount"]
+			}`),
+		},
+	}
+}
+
+// RawCallTool is eth_call with arbitrary calldata. It is not among
+// CryptoTools: it lets the model probe any contract, so it is offered only
+// when the user opts in.
+func RawCallTool() Tool {
+	return Tool{
+		Name:        "raw_call",
+		Description: "Low-level eth_call: run calldata against a contract without sending a transaction and return the raw hex output. Prefer the specific tools; use this for contracts they do not cover",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"chain": {"type": "string", "description": "Chain name, e.g., ethereum, base"},
+				"to": {"type": "string", "description": "Contract address (0x...)"},
+				"data": {"type": "string", "description": "Calldata as hex (0x...)"},
+				"from": {"type": "string", "description": "Optional caller address"},
+				"value": {"type": "string", "description": "Optional value sent with the call, in wei"},
+				"block": {"type": "string", "description": "Block number, latest, or an offset such as -100 (default latest)"}
+			},
+			"required": ["chain", "to", "data"]
+		}`),
 	}
 }
@@ -0,0 +1,63 @@
+// Package logging configures the process-wide slog logger. The REPL owns
+// the terminal, so logs go to a file by default rather than stderr.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxFileSize is the size past which the log is rotated at startup, keeping
+// one previous file.
+const maxFileSize = 10 << 20
+
+// Stderr is the log file name that selects standard error.
+const Stderr = "-"
+
+// ParseLevel accepts debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (use debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+// Setup installs a text logger at level writing to path, or to stderr when
+// path is Stderr. The returned closer releases the file.
+func Setup(level, path string) (io.Closer, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var w io.WriteCloser = nopCloser{os.Stderr}
+	if path != Stderr {
+		f, err := openLogFile(path)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})))
+	return w, nil
+}
+
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxFileSize {
+		_ = os.Rename(path, path+".1")
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	path := filepath.Join(t.TempDir(), "logs", "clifi.log")
+	closer, err := Setup("info", path)
+	require.NoError(t, err)
+
+	slog.Debug("hidden")
+	slog.Warn("rpc dial failed", "chain", "base")
+	require.NoError(t, closer.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `msg="rpc dial failed" chain=base`)
+	assert.NotContains(t, string(b), "hidden")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = Setup("loud", path)
+	assert.ErrorContains(t, err, "invalid log level")
+}
+
+func TestSetupRotatesLargeFile(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	path := filepath.Join(t.TempDir(), "clifi.log")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", maxFileSize+1)), 0600))
+
+	closer, err := Setup("warn", path)
+	require.NoError(t, err)
+	require.NoError(t, closer.Close())
+
+	old, err := os.Stat(path + ".1")
+	require.NoError(t, err)
+	assert.Greater(t, old.Size(), int64(maxFileSize))
+	cur, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, cur.Size())
+}
@@ -0,0 +1,208 @@
+// Package memory is the agent's notes about the user that outlive a
+// session: addresses they mention, preferences such as "always use fast
+// gas". Entries are plain key/value pairs kept in the data dir, so each
+// data dir (profile) has its own memory.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File is where memories are kept, in the data dir.
+const File = "memory.json"
+
+const (
+	// MaxEntries bounds the store; every entry goes into each prompt.
+	MaxEntries = 100
+	// MaxKeyLen and MaxValueLen keep entries to notes rather than
+	// documents.
+	MaxKeyLen   = 64
+	MaxValueLen = 500
+)
+
+// ErrSecret means a value looked like a private key. The memory file is
+// not encrypted and is sent to the LLM with every request.
+var ErrSecret = errors.New("refusing to remember what looks like a private key")
+
+var privateKeyPattern = regexp.MustCompile(`(?i)(^|[^0-9a-f])(0x)?[0-9a-f]{64}($|[^0-9a-f])`)
+
+// Entry is one remembered fact.
+type Entry struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store keeps entries in dataDir/memory.json. The REPL and `clifi memory`
+// may run at once, so every change is a read-modify-write of the whole
+// file.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store in dataDir.
+func NewStore(dataDir string) *Store {
+	return &Store{path: filepath.Join(dataDir, File)}
+}
+
+type storeFile struct {
+	Entries []Entry `json:"entries"`
+}
+
+func (s *Store) load() (*storeFile, error) {
+	f := &storeFile{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", File, err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", File, err)
+	}
+	return f, nil
+}
+
+func (s *Store) save(f *storeFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// NormalizeKey lowercases a key and joins its words with dashes, so
+// "Hot Wallet" and "hot-wallet" are the same entry.
+func NormalizeKey(key string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(key, "_", " "))), "-")
+}
+
+// List returns the entries sorted by key.
+func (s *Store) List() ([]Entry, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(f.Entries, func(i, j int) bool { return f.Entries[i].Key < f.Entries[j].Key })
+	return f.Entries, nil
+}
+
+// Get returns the entry for key.
+func (s *Store) Get(key string) (Entry, bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	key = NormalizeKey(key)
+	for _, e := range f.Entries {
+		if e.Key == key {
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// Set stores value under key, replacing any earlier value.
+func (s *Store) Set(key, value string) (Entry, error) {
+	key = NormalizeKey(key)
+	value = strings.TrimSpace(value)
+	switch {
+	case key == "":
+		return Entry{}, fmt.Errorf("key is required")
+	case len(key) > MaxKeyLen:
+		return Entry{}, fmt.Errorf("key is longer than %d characters", MaxKeyLen)
+	case value == "":
+		return Entry{}, fmt.Errorf("value is required")
+	case len(value) > MaxValueLen:
+		return Entry{}, fmt.Errorf("value is longer than %d characters", MaxValueLen)
+	case privateKeyPattern.MatchString(value):
+		return Entry{}, ErrSecret
+	}
+
+	f, err := s.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Key: key, Value: value, UpdatedAt: time.Now().UTC()}
+	for i := range f.Entries {
+		if f.Entries[i].Key == key {
+			f.Entries[i] = e
+			return e, s.save(f)
+		}
+	}
+	if len(f.Entries) >= MaxEntries {
+		return Entry{}, fmt.Errorf("memory is full (%d entries); remove some with `clifi memory rm`", MaxEntries)
+	}
+	f.Entries = append(f.Entries, e)
+	return e, s.save(f)
+}
+
+// Remove deletes the entry for key, reporting whether it existed.
+func (s *Store) Remove(key string) (bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	key = NormalizeKey(key)
+	for i, e := range f.Entries {
+		if e.Key == key {
+			f.Entries = append(f.Entries[:i], f.Entries[i+1:]...)
+			return true, s.save(f)
+		}
+	}
+	return false, nil
+}
+
+// Clear deletes every entry, returning how many there were.
+func (s *Store) Clear() (int, error) {
+	f, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	n := len(f.Entries)
+	if n == 0 {
+		return 0, nil
+	}
+	return n, s.save(&storeFile{})
+}
+
+// Search returns the entries whose key or value contains every word of
+// query, ignoring case, sorted by key.
+func (s *Store) Search(query string) ([]Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	words := strings.Fields(strings.ToLower(query))
+	var out []Entry
+	for _, e := range entries {
+		text := strings.ToLower(e.Key + " " + e.Value)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
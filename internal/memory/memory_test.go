@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	entries, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = s.Set("Gas Preference", "always use fast gas")
+	require.NoError(t, err)
+	_, err = s.Set("alice", "0x1111111111111111111111111111111111111111 on base")
+	require.NoError(t, err)
+	e, err := s.Set("gas_preference", "  standard gas unless urgent ")
+	require.NoError(t, err)
+	assert.Equal(t, "gas-preference", e.Key, "keys are normalized")
+
+	got, ok, err := s.Get("GAS PREFERENCE")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "standard gas unless urgent", got.Value, "Set replaces")
+
+	entries, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "alice", entries[0].Key)
+
+	found, err := s.Search("BASE 0x1111")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "alice", found[0].Key)
+	found, err = s.Search("gas urgent")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	removed, err := s.Remove("alice")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = s.Remove("alice")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	n, err := s.Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	entries, err = s.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSetRejects(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	_, err := s.Set("key", "0x"+strings.Repeat("ab", 32))
+	assert.ErrorIs(t, err, ErrSecret)
+	_, err = s.Set("key", "my key is "+strings.Repeat("cd", 32)+".")
+	assert.ErrorIs(t, err, ErrSecret)
+	_, err = s.Set("", "value")
+	assert.Error(t, err)
+	_, err = s.Set("key", " ")
+	assert.Error(t, err)
+	_, err = s.Set("key", strings.Repeat("x", MaxValueLen+1))
+	assert.Error(t, err)
+
+	for i := 0; i < MaxEntries; i++ {
+		_, err := s.Set("k"+strings.Repeat("x", i%10)+string(rune('a'+i/10)), "v")
+		require.NoError(t, err)
+	}
+	_, err = s.Set("one-more", "v")
+	assert.ErrorContains(t, err, "memory is full")
+}
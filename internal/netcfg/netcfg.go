@@ -0,0 +1,198 @@
+// Package netcfg builds the HTTP transports for LLM providers and RPC
+// endpoints from the proxy and CA settings, for networks where traffic
+// must go through a proxy that re-signs TLS.
+//
+// Without settings, proxies come from HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// as usual. network.proxy, network.no_proxy and network.ca_bundle (or
+// CLIFI_CA_BUNDLE) set them for everything; a scope such as
+// llm.providers.openai, rpc or kms overrides the proxy and CA bundle with its own
+// <scope>.proxy and <scope>.ca_bundle.
+package netcfg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ScopeRPC is the scope of chain RPC connections.
+const ScopeRPC = "rpc"
+
+// ScopeKMS is the scope of KMS signer requests, AWS KMS, Google Cloud KMS
+// and Google's OAuth token exchange.
+const ScopeKMS = "kms"
+
+// Direct as a proxy setting bypasses any proxy, e.g. for a provider on the
+// local network.
+const Direct = "direct"
+
+// ProviderScope is the scope of an LLM provider's requests.
+func ProviderScope(id string) string {
+	return "llm.providers." + id
+}
+
+// Settings are the proxy and CA settings in effect for a scope.
+type Settings struct {
+	// Proxy is a proxy URL, Direct, or empty to read the environment.
+	Proxy    string
+	NoProxy  string
+	CABundle string
+}
+
+// Resolve returns the settings for scope, which may be empty for the
+// global ones.
+func Resolve(scope string) Settings {
+	s := Settings{
+		Proxy:    viper.GetString("network.proxy"),
+		NoProxy:  viper.GetString("network.no_proxy"),
+		CABundle: viper.GetString("network.ca_bundle"),
+	}
+	if s.CABundle == "" {
+		s.CABundle = os.Getenv("CLIFI_CA_BUNDLE")
+	}
+	if scope != "" {
+		if v := viper.GetString(scope + ".proxy"); v != "" {
+			s.Proxy = v
+		}
+		if v := viper.GetString(scope + ".ca_bundle"); v != "" {
+			s.CABundle = v
+		}
+	}
+	return s
+}
+
+var (
+	mu         sync.Mutex
+	transports = map[Settings]*http.Transport{}
+)
+
+// Transport returns the transport for scope. Scopes with the same
+// settings share one, and so its connection pool.
+func Transport(scope string) (*http.Transport, error) {
+	s := Resolve(scope)
+	mu.Lock()
+	defer mu.Unlock()
+	if t, ok := transports[s]; ok {
+		return t, nil
+	}
+	t, err := s.transport()
+	if err != nil {
+		if scope != "" {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		return nil, err
+	}
+	transports[s] = t
+	return t, nil
+}
+
+// InstallDefault applies the global settings to http.DefaultTransport, so
+// the other HTTP clients (quotes, explorers, relays) follow them too. Call
+// it once the config is read, before any requests.
+func InstallDefault() error {
+	s := Resolve("")
+	if s == (Settings{}) {
+		return nil
+	}
+	t, err := s.transport()
+	if err != nil {
+		return err
+	}
+	def := http.DefaultTransport.(*http.Transport)
+	def.Proxy, def.TLSClientConfig = t.Proxy, t.TLSClientConfig
+	return nil
+}
+
+// Client returns an HTTP client for scope.
+func Client(scope string) (*http.Client, error) {
+	t, err := Transport(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: t}, nil
+}
+
+func (s Settings) transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	proxy, err := s.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	t.Proxy = proxy
+	if s.CABundle != "" {
+		pool, err := LoadCABundle(s.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return t, nil
+}
+
+func (s Settings) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if strings.EqualFold(s.Proxy, Direct) || strings.EqualFold(s.Proxy, "none") {
+		return nil, nil
+	}
+	cfg := httpproxy.FromEnvironment()
+	if s.Proxy != "" {
+		if err := CheckProxyURL(s.Proxy); err != nil {
+			return nil, err
+		}
+		cfg.HTTPProxy, cfg.HTTPSProxy = s.Proxy, s.Proxy
+	}
+	if s.NoProxy != "" {
+		cfg.NoProxy = s.NoProxy
+	}
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// CheckProxyURL reports whether v is a usable proxy setting: an http,
+// https or socks5 URL, or Direct.
+func CheckProxyURL(v string) error {
+	if strings.EqualFold(v, Direct) || strings.EqualFold(v, "none") {
+		return nil
+	}
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy %q: want e.g. http://proxy.corp:3128 or %q", v, Direct)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf("invalid proxy %q: scheme must be http, https or socks5", v)
+}
+
+// LoadCABundle returns the system roots plus the PEM certificates in path,
+// so a proxy's CA is trusted without losing the public ones.
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s has no PEM certificates", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,85 @@
+package netcfg
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	t.Setenv("CLIFI_CA_BUNDLE", "/etc/corp-ca.pem")
+	viper.Set("network.proxy", "http://proxy.corp:3128")
+	viper.Set("network.no_proxy", "localhost,.corp")
+	viper.Set(ProviderScope("openai")+".proxy", Direct)
+
+	assert.Equal(t, Settings{Proxy: "http://proxy.corp:3128", NoProxy: "localhost,.corp", CABundle: "/etc/corp-ca.pem"}, Resolve(ScopeRPC))
+	assert.Equal(t, Settings{Proxy: Direct, NoProxy: "localhost,.corp", CABundle: "/etc/corp-ca.pem"}, Resolve(ProviderScope("openai")))
+}
+
+func TestTransportProxy(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("network.proxy", "http://proxy.corp:3128")
+	viper.Set("network.no_proxy", "internal.corp")
+	viper.Set("llm.providers.venice.proxy", "direct")
+
+	tr, err := Transport(ScopeRPC)
+	require.NoError(t, err)
+	req, _ := http.NewRequest(http.MethodGet, "https://rpc.example.org", nil)
+	u, err := tr.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.corp:3128", u.Host)
+	req, _ = http.NewRequest(http.MethodGet, "https://node.internal.corp", nil)
+	u, err = tr.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, u, "no_proxy hosts go direct")
+
+	same, err := Transport(ProviderScope("anthropic"))
+	require.NoError(t, err)
+	assert.Same(t, tr, same, "scopes with the same settings share a transport")
+
+	direct, err := Transport(ProviderScope("venice"))
+	require.NoError(t, err)
+	assert.Nil(t, direct.Proxy)
+
+	viper.Set("rpc.proxy", "ftp://proxy.corp")
+	_, err = Transport(ScopeRPC)
+	assert.ErrorContains(t, err, "rpc: invalid proxy")
+}
+
+func TestTransportCABundle(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// The test server's certificate is self-signed, as a TLS-inspecting
+	// proxy's would be to the system roots.
+	plain, err := Client("")
+	require.NoError(t, err)
+	_, err = plain.Get(srv.URL)
+	require.Error(t, err)
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := srv.TLS.Certificates[0].Certificate[0]
+	require.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600))
+	viper.Set(ScopeRPC+".ca_bundle", bundle)
+	c, err := Client(ScopeRPC)
+	require.NoError(t, err)
+	resp, err := c.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, uint16(tls.VersionTLS12), c.Transport.(*http.Transport).TLSClientConfig.MinVersion)
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0600))
+	_, err = LoadCABundle(empty)
+	assert.ErrorContains(t, err, "no PEM certificates")
+}
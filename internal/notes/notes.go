@@ -0,0 +1,300 @@
+// Package notes is a local knowledge base of documents the user imports:
+// protocol notes, lists of contract addresses. The agent searches it so
+// answers about a protocol rest on the user's own data rather than on
+// addresses the model recalls, which may be wrong.
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir is where imported documents are kept, in the data dir.
+const Dir = "notes"
+
+// MaxFileSize bounds an imported document.
+const MaxFileSize = 1 << 20
+
+// Extensions are the file types Import accepts: plain text that reads
+// well in a prompt.
+var Extensions = []string{".md", ".markdown", ".txt", ".csv", ".json", ".yaml", ".yml"}
+
+// Doc is an imported document.
+type Doc struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Library holds the documents in dataDir/notes. Documents are copied in,
+// so later edits to the originals need another import.
+type Library struct {
+	dir string
+}
+
+// Open returns the library in dataDir.
+func Open(dataDir string) *Library {
+	return &Library{dir: filepath.Join(dataDir, Dir)}
+}
+
+func supported(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Import copies a file, or the supported files under a directory, into
+// the library and returns the names they were stored under. A document
+// with the same name is replaced.
+func (l *Library) Import(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		if !supported(path) {
+			return nil, fmt.Errorf("%s: unsupported file type (want one of %s)", path, strings.Join(Extensions, ", "))
+		}
+		name, err := l.importFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []string{name}, nil
+	}
+
+	var names []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !supported(p) {
+			return nil
+		}
+		name, err := l.importFile(p)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return names, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no %s files under %s", strings.Join(Extensions, ", "), path)
+	}
+	return names, nil
+}
+
+func (l *Library) importFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, MaxFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxFileSize {
+		return "", fmt.Errorf("%s is larger than %d KB", path, MaxFileSize>>10)
+	}
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return "", err
+	}
+	name := filepath.Base(path)
+	tmp := filepath.Join(l.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to import %s: %w", path, err)
+	}
+	return name, os.Rename(tmp, filepath.Join(l.dir, name))
+}
+
+// List returns the imported documents sorted by name.
+func (l *Library) List() ([]Doc, error) {
+	entries, err := os.ReadDir(l.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var docs []Doc
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !supported(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, Doc{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return docs, nil
+}
+
+// Remove deletes an imported document, reporting whether it existed.
+func (l *Library) Remove(name string) (bool, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return false, fmt.Errorf("invalid document name %q", name)
+	}
+	err := os.Remove(filepath.Join(l.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Chunk is a searchable piece of a document: a paragraph, or a few short
+// ones, under the nearest heading.
+type Chunk struct {
+	Doc     string
+	Section string
+	Text    string
+}
+
+// maxChunk is roughly how much text a chunk gathers before a new one
+// starts at the next paragraph break.
+const maxChunk = 800
+
+var headingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// Split breaks a document into chunks at markdown headings and blank
+// lines. Lines of a table or list stay together until a blank line.
+func Split(doc, text string) []Chunk {
+	var chunks []Chunk
+	var section string
+	var cur strings.Builder
+	flush := func() {
+		if t := strings.TrimSpace(cur.String()); t != "" {
+			chunks = append(chunks, Chunk{Doc: doc, Section: section, Text: t})
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			section = m[1]
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			if cur.Len() >= maxChunk/2 {
+				flush()
+			} else if cur.Len() > 0 {
+				cur.WriteString("\n")
+			}
+			continue
+		}
+		if cur.Len() >= maxChunk {
+			flush()
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+	}
+	flush()
+	return chunks
+}
+
+var tokenPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// tokens lowercases text into words; an address is one token.
+func tokens(text string) []string {
+	return tokenPattern.FindAllString(strings.ToLower(text), -1)
+}
+
+// Result is a chunk matching a search, with its BM25 score.
+type Result struct {
+	Chunk
+	Score float64
+}
+
+// Search ranks every chunk of every document against query with BM25
+// and returns the best limit with a positive score. Libraries are small
+// enough to index on each search, which keeps imports a plain file copy.
+func (l *Library) Search(query string, limit int) ([]Result, error) {
+	docs, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	terms := tokens(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("query has no words to search for")
+	}
+
+	var chunks []Chunk
+	for _, d := range docs {
+		data, err := os.ReadFile(filepath.Join(l.dir, d.Name))
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, Split(d.Name, string(data))...)
+	}
+	return rank(chunks, terms, limit), nil
+}
+
+func rank(chunks []Chunk, terms []string, limit int) []Result {
+	const k1, b = 1.2, 0.75
+	tf := make([]map[string]int, len(chunks))
+	lengths := make([]int, len(chunks))
+	df := map[string]int{}
+	total := 0
+	for i, c := range chunks {
+		// The document name and heading count as part of the chunk, so
+		// "aave pool" finds the pool address under an "Aave" heading.
+		words := tokens(c.Doc + " " + c.Section + " " + c.Text)
+		counts := map[string]int{}
+		for _, w := range words {
+			counts[w]++
+		}
+		for w := range counts {
+			df[w]++
+		}
+		tf[i], lengths[i] = counts, len(words)
+		total += len(words)
+	}
+	if len(chunks) == 0 {
+		return nil
+	}
+	avg := float64(total) / float64(len(chunks))
+
+	var results []Result
+	n := float64(len(chunks))
+	for i, c := range chunks {
+		score := 0.0
+		for _, t := range terms {
+			f := float64(tf[i][t])
+			if f == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[t])+0.5)/(float64(df[t])+0.5))
+			score += idf * f * (k1 + 1) / (f + k1*(1-b+b*float64(lengths[i])/avg))
+		}
+		if score > 0 {
+			results = append(results, Result{Chunk: c, Score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const aaveNotes = `# Aave v3
+
+Lending protocol. Supply assets to earn interest, borrow against them.
+
+## Addresses
+
+Pool on base: 0xA238Dd80C259a72e81d7e4664a9801593F98d1c5
+Pool on ethereum: 0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2
+
+## Risks
+
+Liquidation when the health factor drops below 1.
+`
+
+func TestSplit(t *testing.T) {
+	chunks := Split("aave.md", aaveNotes)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, Chunk{Doc: "aave.md", Section: "Aave v3", Text: "Lending protocol. Supply assets to earn interest, borrow against them."}, chunks[0])
+	assert.Equal(t, "Addresses", chunks[1].Section)
+	assert.Contains(t, chunks[1].Text, "Pool on base")
+	assert.Contains(t, chunks[1].Text, "Pool on ethereum", "short paragraphs stay together")
+
+	long := strings.Repeat("word ", maxChunk/5) + "\n\n" + strings.Repeat("more ", maxChunk/5)
+	assert.Len(t, Split("long.txt", long), 2)
+}
+
+func TestLibrary(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "aave.md"), []byte(aaveNotes), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "uniswap.txt"), []byte("Uniswap universal router on base: 0x6fF5693b99212Da76ad316178A184AB56D299b43\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "photo.png"), []byte{0x89}, 0600))
+
+	lib := Open(t.TempDir())
+	docs, err := lib.List()
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	names, err := lib.Import(src)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"aave.md", "uniswap.txt"}, names)
+	_, err = lib.Import(filepath.Join(src, "photo.png"))
+	assert.ErrorContains(t, err, "unsupported file type")
+
+	results, err := lib.Search("aave pool base", 3)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "aave.md", results[0].Doc)
+	assert.Contains(t, results[0].Text, "0xA238Dd80C259a72e81d7e4664a9801593F98d1c5")
+
+	results, err = lib.Search("0x6ff5693b99212da76ad316178a184ab56d299b43", 3)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "uniswap.txt", results[0].Doc)
+
+	results, err = lib.Search("compound", 3)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	_, err = lib.Search("  ?! ", 3)
+	assert.Error(t, err)
+
+	ok, err := lib.Remove("uniswap.txt")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = lib.Remove("uniswap.txt")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = lib.Remove("../memory.json")
+	assert.Error(t, err)
+
+	docs, err = lib.List()
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "aave.md", docs[0].Name)
+}
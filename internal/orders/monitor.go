@@ -0,0 +1,185 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/audit"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+// Prices returns a token's USD price on a chain. watch.ChainSource is one.
+type Prices interface {
+	Price(ctx context.Context, chainName, symbol string) (float64, error)
+}
+
+// Execution is a completed swap.
+type Execution struct {
+	TxHash  string
+	Summary string
+}
+
+// Executor performs an order's swap. An error means nothing was swapped.
+type Executor interface {
+	Execute(ctx context.Context, o Order, s Swap) (Execution, error)
+}
+
+// SwapFor sizes o's swap with the token at tokenPrice, pricing the quote
+// token for a buy.
+func SwapFor(ctx context.Context, prices Prices, o Order, tokenPrice float64) (Swap, error) {
+	if o.Side == Sell {
+		return o.Swap(0, 0)
+	}
+	quotePrice, err := prices.Price(ctx, o.Chain, o.Quote)
+	if err != nil {
+		return Swap{}, fmt.Errorf("price %s: %w", o.Quote, err)
+	}
+	return o.Swap(tokenPrice, quotePrice)
+}
+
+// Settle records the outcome of o's swap and returns its audit entry.
+func (o *Order) Settle(now time.Time, ex Execution, err error) audit.Entry {
+	e := audit.Entry{
+		Time:   now,
+		Source: fmt.Sprintf("order #%d", o.ID),
+		Action: "swap",
+		Chain:  o.Chain,
+		From:   o.Wallet,
+		TxHash: ex.TxHash,
+	}
+	if err != nil {
+		o.Status = StatusFailed
+		o.LastError = err.Error()
+		e.Summary = o.String()
+		e.Status = audit.StatusFailed
+		e.Error = err.Error()
+		return e
+	}
+	o.Status = StatusFilled
+	o.FilledAt = now
+	o.TxHash = ex.TxHash
+	o.LastError = ""
+	e.Summary = ex.Summary
+	e.Status = audit.StatusOK
+	return e
+}
+
+// Monitor watches open orders' prices and acts when one triggers.
+type Monitor struct {
+	Store  *Store
+	Prices Prices
+	// Exec fills triggered orders. Without it, they wait for
+	// `clifi order confirm`.
+	Exec      Executor
+	Notifiers []watch.Notifier
+	Audit     *audit.Log
+	Interval  time.Duration
+}
+
+// Run checks orders every Interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = watch.DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := m.Check(ctx); err != nil {
+			slog.Warn("order check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check prices every open order once and handles those that trigger.
+func (m *Monitor) Check(ctx context.Context) error {
+	list, err := m.Store.List()
+	if err != nil {
+		return err
+	}
+	// Orders on one token share a price lookup.
+	prices := make(map[string]float64)
+	var checked []Order
+	for _, o := range list {
+		if o.Status != StatusOpen || ctx.Err() != nil {
+			continue
+		}
+		now := time.Now().UTC()
+		o.LastChecked = now
+		key := o.Chain + "/" + o.Token
+		price, ok := prices[key]
+		if !ok {
+			if price, err = m.Prices.Price(ctx, o.Chain, o.Token); err != nil {
+				o.LastError = err.Error()
+				checked = append(checked, o)
+				slog.Warn("order price unavailable", "order", o.ID, "token", o.Token, "err", err)
+				continue
+			}
+			prices[key] = price
+		}
+		o.LastPrice = price
+		o.LastError = ""
+		if !o.Trigger.Holds(price) {
+			checked = append(checked, o)
+			continue
+		}
+
+		// Stored as awaiting before swapping, so an order interrupted
+		// mid-swap asks for confirmation instead of filling twice.
+		o.Status = StatusAwaiting
+		o.TriggeredAt = now
+		if err := m.Store.Update(o); err != nil {
+			return err
+		}
+		if m.Exec == nil {
+			m.notify(ctx, o, fmt.Sprintf("Limit order #%d triggered at $%g: %s. Confirm with: clifi order confirm %d", o.ID, price, o, o.ID))
+			continue
+		}
+		m.fill(ctx, &o, price)
+		checked = append(checked, o)
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+	return m.Store.Update(checked...)
+}
+
+func (m *Monitor) fill(ctx context.Context, o *Order, price float64) {
+	s, err := SwapFor(ctx, m.Prices, *o, price)
+	var ex Execution
+	if err == nil {
+		ex, err = m.Exec.Execute(ctx, *o, s)
+	}
+	entry := o.Settle(time.Now().UTC(), ex, err)
+	if m.Audit != nil {
+		if err := m.Audit.Record(entry); err != nil {
+			slog.Warn("audit record failed", "order", o.ID, "err", err)
+		}
+	}
+	msg := fmt.Sprintf("Limit order #%d filled at $%g: %s (%s)", o.ID, price, ex.Summary, ex.TxHash)
+	if err != nil {
+		msg = fmt.Sprintf("Limit order #%d triggered at $%g but failed: %v", o.ID, price, err)
+	}
+	m.notify(ctx, *o, msg)
+}
+
+func (m *Monitor) notify(ctx context.Context, o Order, msg string) {
+	a := watch.Alert{
+		Watch: watch.Watch{ID: o.ID, Condition: o.Trigger},
+		Value: o.LastPrice,
+		At:    time.Now().UTC(),
+		Text:  msg,
+	}
+	for _, n := range m.Notifiers {
+		if err := n.Notify(ctx, a); err != nil {
+			slog.Warn("order notification failed", "order", o.ID, "notifier", fmt.Sprintf("%T", n), "err", err)
+		}
+	}
+}
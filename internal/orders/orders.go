@@ -0,0 +1,326 @@
+// Package orders keeps off-chain limit orders ("sell 1 ETH if price hits
+// 4200") that `clifi serve` watches, swapping when the price condition
+// holds.
+package orders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+// Side is whether an order sells or buys its token.
+type Side string
+
+const (
+	Sell Side = "sell"
+	Buy  Side = "buy"
+)
+
+// Status is where an order is in its life.
+type Status string
+
+const (
+	// StatusOpen orders are watched.
+	StatusOpen Status = "open"
+	// StatusAwaiting orders triggered and wait for `clifi order confirm`.
+	StatusAwaiting Status = "awaiting"
+	StatusFilled   Status = "filled"
+	StatusFailed   Status = "failed"
+)
+
+// DefaultQuote is what orders sell for or buy with when they name nothing.
+const DefaultQuote = "USDC"
+
+// Order swaps Amount of Token for Quote (sell) or Quote for Amount of
+// Token (buy) once Trigger holds. Trigger prices Token on Chain.
+type Order struct {
+	ID          int             `json:"id"`
+	Chain       string          `json:"chain"`
+	Wallet      string          `json:"wallet"` // address
+	Side        Side            `json:"side"`
+	Amount      string          `json:"amount"` // in Token units
+	Token       string          `json:"token"`
+	Quote       string          `json:"quote"`
+	Trigger     watch.Condition `json:"trigger"`
+	SlippageBps *uint32         `json:"slippage_bps,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+
+	Status      Status    `json:"status"`
+	LastPrice   float64   `json:"last_price,omitempty"`
+	LastChecked time.Time `json:"last_checked,omitzero"`
+	TriggeredAt time.Time `json:"triggered_at,omitzero"`
+	FilledAt    time.Time `json:"filled_at,omitzero"`
+	TxHash      string    `json:"tx_hash,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Phrases for the trigger, after an optional "price" or token subject.
+// "hits" and "at" mean the price reached: up to it for a sell, down to it
+// for a buy.
+var (
+	abovePhrases = map[string]string{">": ">", ">=": ">=", "above": ">", "over": ">", "is above": ">",
+		"rises to": ">=", "rises above": ">", "goes above": ">", "climbs to": ">="}
+	belowPhrases = map[string]string{"<": "<", "<=": "<=", "below": "<", "under": "<", "is below": "<",
+		"drops to": "<=", "drops below": "<", "falls to": "<=", "falls below": "<", "dips to": "<="}
+	reachPhrases = map[string]bool{"": true, "hits": true, "reaches": true, "at": true, "is": true}
+)
+
+// ParseOrder parses
+//
+//	sell <amount> <TOKEN> [for <QUOTE>] if|when <trigger>
+//	buy <amount> <TOKEN> [with <QUOTE>] if|when <trigger>
+//	sell|buy <amount> <TOKEN> [for|with <QUOTE>] at <price>
+//
+// where trigger is e.g. "price hits 4200", "ETH > 4200" or "price drops
+// below 3000". QUOTE defaults to USDC. The chain and wallet are left
+// empty.
+func ParseOrder(text string) (Order, error) {
+	usage := fmt.Errorf("invalid order %q: want e.g. \"sell 1 ETH if price hits 4200\"", text)
+	fields := strings.Fields(strings.NewReplacer(">=", " >= ", "<=", " <= ", ">", " > ", "<", " < ").Replace(text))
+	if len(fields) < 5 {
+		return Order{}, usage
+	}
+	o := Order{Side: Side(strings.ToLower(fields[0])), Quote: DefaultQuote}
+	if o.Side != Sell && o.Side != Buy {
+		return Order{}, fmt.Errorf("invalid order %q: start with sell or buy", text)
+	}
+	if v, err := strconv.ParseFloat(fields[1], 64); err != nil || v <= 0 {
+		return Order{}, fmt.Errorf("invalid order %q: %q is not a positive amount", text, fields[1])
+	}
+	o.Amount = fields[1]
+	o.Token = token(fields[2])
+	rest := fields[3:]
+	switch strings.ToLower(rest[0]) {
+	case "for", "with", "to", "into":
+		if len(rest) < 2 {
+			return Order{}, usage
+		}
+		o.Quote = token(rest[1])
+		rest = rest[2:]
+	}
+	if strings.EqualFold(o.Token, o.Quote) {
+		return Order{}, fmt.Errorf("invalid order %q: nothing to swap %s to itself", text, o.Token)
+	}
+	if len(rest) < 2 {
+		return Order{}, usage
+	}
+
+	var phrase []string
+	switch strings.ToLower(rest[0]) {
+	case "if", "when", "once":
+		phrase = rest[1 : len(rest)-1]
+		if len(phrase) > 0 && (strings.EqualFold(phrase[0], "price") || strings.EqualFold(phrase[0], o.Token)) {
+			phrase = phrase[1:]
+		}
+	case "at":
+	default:
+		return Order{}, usage
+	}
+	priceText := rest[len(rest)-1]
+	price, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "", "_", "").Replace(priceText), 64)
+	if err != nil || price <= 0 {
+		return Order{}, fmt.Errorf("invalid order %q: %q is not a price", text, priceText)
+	}
+
+	key := strings.ToLower(strings.Join(phrase, " "))
+	op, ok := abovePhrases[key]
+	if !ok {
+		op, ok = belowPhrases[key]
+	}
+	if !ok && reachPhrases[key] {
+		op, ok = ">=", true
+		if o.Side == Buy {
+			op = "<="
+		}
+	}
+	if !ok {
+		return Order{}, fmt.Errorf("invalid order %q: say e.g. \"price hits\", \"above\" or \"drops below\" before the price", text)
+	}
+	o.Trigger = watch.Condition{Kind: watch.KindPrice, Symbol: o.Token, Op: op, Value: price}
+	return o, nil
+}
+
+func token(s string) string {
+	if common.IsHexAddress(s) {
+		return s
+	}
+	return strings.ToUpper(s)
+}
+
+// SetChain sets the order's chain, which is also where its trigger is
+// priced.
+func (o *Order) SetChain(chainName string) {
+	o.Chain = strings.ToLower(chainName)
+	o.Trigger.Chain = o.Chain
+}
+
+func (o Order) String() string {
+	verb := "for"
+	if o.Side == Buy {
+		verb = "with"
+	}
+	s := fmt.Sprintf("%s %s %s %s %s when %s %s %s", o.Side, o.Amount, o.Token, verb, o.Quote,
+		o.Token, o.Trigger.Op, strconv.FormatFloat(o.Trigger.Value, 'f', -1, 64))
+	if o.Chain != "" {
+		s += " on " + o.Chain
+	}
+	return s
+}
+
+// Swap is what an order trades when it fills.
+type Swap struct {
+	FromToken string
+	ToToken   string
+	Amount    string // in FromToken units
+}
+
+// Swap returns the trade for the order. A buy names the amount to
+// receive, so it spends that amount's worth of Quote at the given prices.
+func (o Order) Swap(tokenPrice, quotePrice float64) (Swap, error) {
+	if o.Side == Sell {
+		return Swap{FromToken: o.Token, ToToken: o.Quote, Amount: o.Amount}, nil
+	}
+	amount, err := strconv.ParseFloat(o.Amount, 64)
+	if err != nil {
+		return Swap{}, err
+	}
+	if tokenPrice <= 0 || quotePrice <= 0 {
+		return Swap{}, fmt.Errorf("no price to size the buy of %s with %s", o.Token, o.Quote)
+	}
+	spend := strconv.FormatFloat(amount*tokenPrice/quotePrice, 'f', 6, 64)
+	spend = strings.TrimRight(strings.TrimRight(spend, "0"), ".")
+	return Swap{FromToken: o.Quote, ToToken: o.Token, Amount: spend}, nil
+}
+
+// File is where orders are kept, in the data dir.
+const File = "orders.json"
+
+// Store keeps orders in dataDir/orders.json. `clifi order` and
+// `clifi serve` share it from separate processes, so every change is a
+// read-modify-write of the whole file.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store in dataDir.
+func NewStore(dataDir string) *Store {
+	return &Store{path: filepath.Join(dataDir, File)}
+}
+
+type storeFile struct {
+	NextID int     `json:"next_id"`
+	Orders []Order `json:"orders"`
+}
+
+func (s *Store) load() (*storeFile, error) {
+	f := &storeFile{NextID: 1}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", File, err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", File, err)
+	}
+	return f, nil
+}
+
+func (s *Store) save(f *storeFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// List returns the orders, oldest first.
+func (s *Store) List() ([]Order, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return f.Orders, nil
+}
+
+// Get returns one order.
+func (s *Store) Get(id int) (Order, error) {
+	f, err := s.load()
+	if err != nil {
+		return Order{}, err
+	}
+	for _, o := range f.Orders {
+		if o.ID == id {
+			return o, nil
+		}
+	}
+	return Order{}, fmt.Errorf("no order #%d", id)
+}
+
+// Add stores a new open order and returns it with its ID.
+func (s *Store) Add(o Order) (Order, error) {
+	if o.Chain == "" || o.Wallet == "" {
+		return Order{}, fmt.Errorf("order needs a chain and a wallet")
+	}
+	f, err := s.load()
+	if err != nil {
+		return Order{}, err
+	}
+	o.ID = f.NextID
+	o.CreatedAt = time.Now().UTC()
+	o.Status = StatusOpen
+	f.NextID++
+	f.Orders = append(f.Orders, o)
+	return o, s.save(f)
+}
+
+// Remove deletes an order, reporting whether it existed.
+func (s *Store) Remove(id int) (bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	for i, o := range f.Orders {
+		if o.ID == id {
+			f.Orders = append(f.Orders[:i], f.Orders[i+1:]...)
+			return true, s.save(f)
+		}
+	}
+	return false, nil
+}
+
+// Update stores orders by ID. Orders removed since they were read stay
+// removed.
+func (s *Store) Update(changed ...Order) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]Order, len(changed))
+	for _, o := range changed {
+		byID[o.ID] = o
+	}
+	for i, o := range f.Orders {
+		if c, ok := byID[o.ID]; ok {
+			f.Orders[i] = c
+		}
+	}
+	return s.save(f)
+}
@@ -0,0 +1,196 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/audit"
+	"github.com/yolodolo42/clifi/internal/watch"
+)
+
+func TestParseOrder(t *testing.T) {
+	cases := map[string]struct {
+		text string
+		want string
+	}{
+		"sell hits":     {"sell 1 ETH if price hits 4200", "sell 1 ETH for USDC when ETH >= 4200"},
+		"buy hits":      {"buy 0.5 eth if price hits 3000", "buy 0.5 ETH with USDC when ETH <= 3000"},
+		"at":            {"sell 1 ETH at $4,200", "sell 1 ETH for USDC when ETH >= 4200"},
+		"quote":         {"sell 1 ETH for DAI when ETH > 4200", "sell 1 ETH for DAI when ETH > 4200"},
+		"no spaces":     {"sell 1 ETH if ETH>=4200", "sell 1 ETH for USDC when ETH >= 4200"},
+		"stop loss":     {"sell 2 ETH if price drops below 3000", "sell 2 ETH for USDC when ETH < 3000"},
+		"buy with":      {"buy 0.1 WBTC with USDT when price falls to 90000", "buy 0.1 WBTC with USDT when WBTC <= 90000"},
+		"above phrase":  {"sell 100 ARB if ARB is above 2", "sell 100 ARB for USDC when ARB > 2"},
+		"reaches token": {"sell 1 ETH when ETH reaches 5000", "sell 1 ETH for USDC when ETH >= 5000"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := ParseOrder(tc.text)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, o.String())
+			assert.Equal(t, watch.KindPrice, o.Trigger.Kind)
+		})
+	}
+
+	for _, bad := range []string{
+		"",
+		"hold 1 ETH if price hits 4200",
+		"sell x ETH if price hits 4200",
+		"sell 1 ETH for ETH if price hits 4200",
+		"sell 1 ETH if price hits lots",
+		"sell 1 ETH if price wobbles 4200",
+		"sell 1 ETH maybe 4200",
+	} {
+		_, err := ParseOrder(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestOrderSwap(t *testing.T) {
+	sell, err := ParseOrder("sell 1 ETH if price hits 4200")
+	require.NoError(t, err)
+	s, err := sell.Swap(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Swap{FromToken: "ETH", ToToken: "USDC", Amount: "1"}, s)
+
+	buy, err := ParseOrder("buy 0.5 ETH if price hits 3000")
+	require.NoError(t, err)
+	s, err = buy.Swap(3000, 1.0001)
+	require.NoError(t, err)
+	assert.Equal(t, Swap{FromToken: "USDC", ToToken: "ETH", Amount: "1499.850015"}, s)
+	_, err = buy.Swap(3000, 0)
+	assert.Error(t, err)
+}
+
+type fakePrices map[string]float64
+
+func (f fakePrices) Price(_ context.Context, chainName, symbol string) (float64, error) {
+	p, ok := f[chainName+"/"+symbol]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", symbol)
+	}
+	return p, nil
+}
+
+type fakeExec struct {
+	err   error
+	swaps []Swap
+}
+
+func (f *fakeExec) Execute(_ context.Context, o Order, s Swap) (Execution, error) {
+	f.swaps = append(f.swaps, s)
+	if f.err != nil {
+		return Execution{}, f.err
+	}
+	return Execution{TxHash: "0xabc", Summary: "Swapped " + s.Amount + " " + s.FromToken}, nil
+}
+
+type recorder struct{ msgs []string }
+
+func (r *recorder) Notify(_ context.Context, a watch.Alert) error {
+	r.msgs = append(r.msgs, a.Message())
+	return nil
+}
+
+func addOrder(t *testing.T, store *Store, text string) Order {
+	t.Helper()
+	o, err := ParseOrder(text)
+	require.NoError(t, err)
+	o.SetChain("base")
+	o.Wallet = "0x00000000000000000000000000000000000000bb"
+	o, err = store.Add(o)
+	require.NoError(t, err)
+	return o
+}
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	prices := fakePrices{"base/ETH": 4000, "base/USDC": 1}
+
+	t.Run("waits for confirmation without an executor", func(t *testing.T) {
+		store := NewStore(t.TempDir())
+		notes := &recorder{}
+		m := &Monitor{Store: store, Prices: prices, Notifiers: []watch.Notifier{notes}}
+		o := addOrder(t, store, "sell 1 ETH if price hits 4200")
+
+		require.NoError(t, m.Check(ctx))
+		got, err := store.Get(o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusOpen, got.Status)
+		assert.Equal(t, 4000.0, got.LastPrice)
+		assert.Empty(t, notes.msgs)
+
+		prices["base/ETH"] = 4250
+		defer func() { prices["base/ETH"] = 4000 }()
+		require.NoError(t, m.Check(ctx))
+		got, _ = store.Get(o.ID)
+		assert.Equal(t, StatusAwaiting, got.Status)
+		require.Len(t, notes.msgs, 1)
+		assert.Contains(t, notes.msgs[0], "clifi order confirm 1")
+
+		// Not re-notified while it waits.
+		require.NoError(t, m.Check(ctx))
+		assert.Len(t, notes.msgs, 1)
+	})
+
+	t.Run("fills with an executor and audits", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewStore(dir)
+		exec := &fakeExec{}
+		log := audit.New(dir)
+		notes := &recorder{}
+		m := &Monitor{Store: store, Prices: prices, Exec: exec, Audit: log, Notifiers: []watch.Notifier{notes}}
+		buy := addOrder(t, store, "buy 0.5 ETH if price drops to 4000")
+		sell := addOrder(t, store, "sell 1 ETH if price hits 4200")
+
+		require.NoError(t, m.Check(ctx))
+		require.Len(t, exec.swaps, 1)
+		assert.Equal(t, Swap{FromToken: "USDC", ToToken: "ETH", Amount: "2000"}, exec.swaps[0])
+		got, _ := store.Get(buy.ID)
+		assert.Equal(t, StatusFilled, got.Status)
+		assert.Equal(t, "0xabc", got.TxHash)
+		got, _ = store.Get(sell.ID)
+		assert.Equal(t, StatusOpen, got.Status)
+
+		entries, err := log.Entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "order #1", entries[0].Source)
+		assert.Equal(t, audit.StatusOK, entries[0].Status)
+		require.Len(t, notes.msgs, 1)
+		assert.True(t, strings.HasPrefix(notes.msgs[0], "Limit order #1 filled"))
+	})
+
+	t.Run("failed swap", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewStore(dir)
+		exec := &fakeExec{err: errors.New("policy: destination not in allowlist")}
+		m := &Monitor{Store: store, Prices: prices, Exec: exec, Audit: audit.New(dir)}
+		o := addOrder(t, store, "sell 1 ETH if ETH < 4100")
+
+		require.NoError(t, m.Check(ctx))
+		got, _ := store.Get(o.ID)
+		assert.Equal(t, StatusFailed, got.Status)
+		assert.Contains(t, got.LastError, "allowlist")
+
+		// Failed orders are not retried.
+		require.NoError(t, m.Check(ctx))
+		assert.Len(t, exec.swaps, 1)
+	})
+
+	t.Run("price errors keep the order open", func(t *testing.T) {
+		store := NewStore(t.TempDir())
+		m := &Monitor{Store: store, Prices: prices, Exec: &fakeExec{}}
+		o := addOrder(t, store, "sell 1 ZZZ if price hits 1")
+
+		require.NoError(t, m.Check(ctx))
+		got, _ := store.Get(o.ID)
+		assert.Equal(t, StatusOpen, got.Status)
+		assert.Contains(t, got.LastError, "no price")
+	})
+}
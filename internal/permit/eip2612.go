@@ -0,0 +1,117 @@
+package permit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var permitSelector = crypto.Keccak256([]byte("permit(address,address,uint256,uint256,uint8,bytes32,bytes32)"))[:4]
+
+// Domain is an EIP-2612 token's EIP-712 domain. Version is empty for
+// tokens whose domain has no version field.
+type Domain struct {
+	Name    string
+	Version string
+	ChainID *big.Int
+	Token   common.Address
+}
+
+func (d Domain) typedData() (apitypes.Types, apitypes.TypedDataDomain) {
+	fields := []apitypes.Type{{Name: "name", Type: "string"}}
+	if d.Version != "" {
+		fields = append(fields, apitypes.Type{Name: "version", Type: "string"})
+	}
+	fields = append(fields,
+		apitypes.Type{Name: "chainId", Type: "uint256"},
+		apitypes.Type{Name: "verifyingContract", Type: "address"},
+	)
+	return apitypes.Types{"EIP712Domain": fields}, apitypes.TypedDataDomain{
+		Name:              d.Name,
+		Version:           d.Version,
+		ChainId:           (*math.HexOrDecimal256)(d.ChainID),
+		VerifyingContract: d.Token.Hex(),
+	}
+}
+
+// Separator is the domain's EIP-712 hash, which the token exposes as
+// DOMAIN_SEPARATOR().
+func (d Domain) Separator() (common.Hash, error) {
+	types, domain := d.typedData()
+	td := apitypes.TypedData{Types: types, Domain: domain}
+	h, err := td.HashStruct("EIP712Domain", domain.Map())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(h), nil
+}
+
+// MatchDomain finds the domain a token signs permits under: the token's
+// name with its version(), "1", "2" or no version, whichever reproduces
+// its DOMAIN_SEPARATOR. Tokens hash their domains in different ways, and
+// a permit signed under the wrong one is rejected on-chain.
+func MatchDomain(name, version string, chainID *big.Int, token common.Address, separator common.Hash) (Domain, error) {
+	candidates := []string{version, "1", "2", ""}
+	for _, v := range candidates {
+		d := Domain{Name: name, Version: v, ChainID: chainID, Token: token}
+		if s, err := d.Separator(); err == nil && s == separator {
+			return d, nil
+		}
+	}
+	return Domain{}, fmt.Errorf("the token's DOMAIN_SEPARATOR does not match its name %q with any known version; can't sign a permit it would accept", name)
+}
+
+// Permit lets Spender transfer Value of the owner's tokens, once the
+// permit is submitted before Deadline (unix seconds). Nonce is the owner's
+// current nonces() on the token.
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// TypedData is the permit as EIP-712 typed data under d.
+func (p Permit) TypedData(d Domain) apitypes.TypedData {
+	types, domain := d.typedData()
+	types["Permit"] = []apitypes.Type{
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}
+	return apitypes.TypedData{
+		Types:       types,
+		PrimaryType: "Permit",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"owner":    p.Owner.Hex(),
+			"spender":  p.Spender.Hex(),
+			"value":    p.Value.String(),
+			"nonce":    p.Nonce.String(),
+			"deadline": p.Deadline.String(),
+		},
+	}
+}
+
+// Calldata is the token's permit(owner, spender, value, deadline, v, r, s)
+// for a 65-byte signature with v of 27 or 28.
+func (p Permit) Calldata(sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("signature is %d bytes, want 65", len(sig))
+	}
+	data := append([]byte{}, permitSelector...)
+	for _, a := range []common.Address{p.Owner, p.Spender} {
+		data = append(data, common.LeftPadBytes(a.Bytes(), 32)...)
+	}
+	data = append(data, common.LeftPadBytes(p.Value.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(p.Deadline.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes([]byte{sig[64]}, 32)...)
+	return append(data, sig[:64]...), nil
+}
@@ -0,0 +1,52 @@
+package permit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var usdc = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+func TestMatchDomain(t *testing.T) {
+	// USDC's DOMAIN_SEPARATOR on Ethereum: "USD Coin", version "2".
+	separator := common.HexToHash("0x06c37168a7db5138defc7866392bb87a741f9b3d104deb5094588ce041cae335")
+	d, err := MatchDomain("USD Coin", "", big.NewInt(1), usdc, separator)
+	require.NoError(t, err)
+	assert.Equal(t, "2", d.Version)
+
+	_, err = MatchDomain("USD Coin", "", big.NewInt(8453), usdc, separator)
+	assert.Error(t, err)
+}
+
+func TestPermit(t *testing.T) {
+	p := Permit{
+		Owner:    common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Spender:  common.HexToAddress("0x00000000000000000000000000000000000000bb"),
+		Value:    big.NewInt(1_000_000),
+		Nonce:    big.NewInt(0),
+		Deadline: big.NewInt(1_800_000_000),
+	}
+	td := p.TypedData(Domain{Name: "USD Coin", Version: "2", ChainID: big.NewInt(1), Token: usdc})
+	assert.Equal(t, "0x6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9", td.TypeHash("Permit").String())
+	_, _, err := apitypes.TypedDataAndHash(td)
+	require.NoError(t, err)
+
+	sig := make([]byte, 65)
+	sig[0], sig[32], sig[64] = 0x11, 0x22, 28
+	data, err := p.Calldata(sig)
+	require.NoError(t, err)
+	assert.Equal(t, "0xd505accf", hexutil.Encode(data[:4]))
+	require.Len(t, data, 4+7*32)
+	assert.Equal(t, byte(28), data[4+5*32-1])
+	assert.Equal(t, byte(0x11), data[4+5*32])
+	assert.Equal(t, byte(0x22), data[4+6*32])
+
+	_, err = p.Calldata(sig[:64])
+	assert.Error(t, err)
+}
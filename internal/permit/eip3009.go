@@ -0,0 +1,71 @@
+package permit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var transferWithAuthorizationSelector = crypto.Keccak256([]byte("transferWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)"))[:4]
+
+// TransferAuthorization is an EIP-3009 signed transfer (USDC and EURC
+// support it): anyone may submit it, so a relayer can move the owner's
+// tokens without the owner holding gas. Authorizations carry a random
+// Nonce rather than a sequential one, so several can be pending at once.
+type TransferAuthorization struct {
+	From        common.Address
+	To          common.Address
+	Value       *big.Int
+	ValidAfter  *big.Int // unix seconds
+	ValidBefore *big.Int // unix seconds
+	Nonce       common.Hash
+}
+
+// TypedData is the authorization as EIP-712 typed data under d, the
+// token's permit domain.
+func (a TransferAuthorization) TypedData(d Domain) apitypes.TypedData {
+	types, domain := d.typedData()
+	types["TransferWithAuthorization"] = []apitypes.Type{
+		{Name: "from", Type: "address"},
+		{Name: "to", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "validAfter", Type: "uint256"},
+		{Name: "validBefore", Type: "uint256"},
+		{Name: "nonce", Type: "bytes32"},
+	}
+	return apitypes.TypedData{
+		Types:       types,
+		PrimaryType: "TransferWithAuthorization",
+		Domain:      domain,
+		Message: apitypes.TypedDataMessage{
+			"from":        a.From.Hex(),
+			"to":          a.To.Hex(),
+			"value":       a.Value.String(),
+			"validAfter":  a.ValidAfter.String(),
+			"validBefore": a.ValidBefore.String(),
+			"nonce":       a.Nonce.Hex(),
+		},
+	}
+}
+
+// Calldata is the token's transferWithAuthorization(from, to, value,
+// validAfter, validBefore, nonce, v, r, s) for a 65-byte signature with v
+// of 27 or 28.
+func (a TransferAuthorization) Calldata(sig []byte) ([]byte, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("signature is %d bytes, want 65", len(sig))
+	}
+	data := append([]byte{}, transferWithAuthorizationSelector...)
+	for _, addr := range []common.Address{a.From, a.To} {
+		data = append(data, common.LeftPadBytes(addr.Bytes(), 32)...)
+	}
+	for _, n := range []*big.Int{a.Value, a.ValidAfter, a.ValidBefore} {
+		data = append(data, common.LeftPadBytes(n.Bytes(), 32)...)
+	}
+	data = append(data, a.Nonce.Bytes()...)
+	data = append(data, common.LeftPadBytes([]byte{sig[64]}, 32)...)
+	return append(data, sig[:64]...), nil
+}
@@ -0,0 +1,39 @@
+package permit
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferAuthorization(t *testing.T) {
+	a := TransferAuthorization{
+		From:        common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		To:          common.HexToAddress("0x00000000000000000000000000000000000000bb"),
+		Value:       big.NewInt(1_000_000),
+		ValidAfter:  big.NewInt(0),
+		ValidBefore: big.NewInt(1_800_000_000),
+		Nonce:       common.HexToHash("0x01"),
+	}
+	td := a.TypedData(Domain{Name: "USD Coin", Version: "2", ChainID: big.NewInt(1), Token: usdc})
+	// USDC's TRANSFER_WITH_AUTHORIZATION_TYPEHASH
+	assert.Equal(t, "0x7c7c6cdb67a18743f49ec6fa9b35f50d52ed05cbed4cc592e13b44501c1a2267", td.TypeHash("TransferWithAuthorization").String())
+	_, _, err := apitypes.TypedDataAndHash(td)
+	require.NoError(t, err)
+
+	sig := make([]byte, 65)
+	sig[0], sig[32], sig[64] = 0x11, 0x22, 27
+	data, err := a.Calldata(sig)
+	require.NoError(t, err)
+	assert.Equal(t, "0xe3ee160e", hexutil.Encode(data[:4]))
+	require.Len(t, data, 4+9*32)
+	assert.Equal(t, byte(1), data[4+6*32-1])
+	assert.Equal(t, byte(27), data[4+7*32-1])
+	assert.Equal(t, byte(0x11), data[4+7*32])
+	assert.Equal(t, byte(0x22), data[4+8*32])
+}
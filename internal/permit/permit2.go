@@ -0,0 +1,143 @@
+// Package permit builds EIP-712 permits: signed messages that grant a
+// token allowance, which the spender submits instead of the owner sending
+// an approval transaction.
+package permit
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Permit2Address is Uniswap's Permit2, deployed at the same address on
+// every chain.
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+// MaxPermit2Amount is the largest Permit2 allowance (uint160), which
+// Permit2 never decreases: an unlimited allowance.
+var MaxPermit2Amount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// maxUint48 bounds Permit2's expirations and nonces.
+const maxUint48 = 1<<48 - 1
+
+// Permit2 events that set an allowance, for finding the spenders an owner
+// allowed.
+const (
+	Permit2ApprovalEvent = "Approval(address indexed owner,address indexed token,address indexed spender,uint160 amount,uint48 expiration)"
+	Permit2PermitEvent   = "Permit(address indexed owner,address indexed token,address indexed spender,uint160 amount,uint48 expiration,uint48 nonce)"
+)
+
+var permit2AllowanceSelector = crypto.Keccak256([]byte("allowance(address,address,address)"))[:4]
+
+// PermitSingle lets Spender transfer up to Amount of Token through
+// Permit2 until Expiration (unix seconds). Nonce must be the owner's
+// current Permit2 nonce for the token and spender; the signature is only
+// accepted until SigDeadline.
+type PermitSingle struct {
+	Token       common.Address
+	Amount      *big.Int
+	Expiration  uint64
+	Nonce       uint64
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// Validate checks the fields fit Permit2's types.
+func (p PermitSingle) Validate() error {
+	switch {
+	case p.Amount == nil || p.Amount.Sign() < 0 || p.Amount.Cmp(MaxPermit2Amount) > 0:
+		return fmt.Errorf("permit amount must be between 0 and 2^160-1")
+	case p.Expiration > maxUint48:
+		return fmt.Errorf("permit expiration %d does not fit uint48", p.Expiration)
+	case p.Nonce > maxUint48:
+		return fmt.Errorf("permit nonce %d does not fit uint48", p.Nonce)
+	case p.SigDeadline == nil || p.SigDeadline.Sign() <= 0:
+		return fmt.Errorf("permit signature deadline is required")
+	}
+	return nil
+}
+
+// TypedData is the permit as EIP-712 typed data for Permit2 on chainID.
+func (p PermitSingle) TypedData(chainID *big.Int) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitSingle",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           (*math.HexOrDecimal256)(chainID),
+			VerifyingContract: Permit2Address.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      p.Token.Hex(),
+				"amount":     p.Amount.String(),
+				"expiration": new(big.Int).SetUint64(p.Expiration).String(),
+				"nonce":      new(big.Int).SetUint64(p.Nonce).String(),
+			},
+			"spender":     p.Spender.Hex(),
+			"sigDeadline": p.SigDeadline.String(),
+		},
+	}
+}
+
+// Allowance is what Permit2 lets a spender transfer of an owner's token.
+type Allowance struct {
+	Amount     *big.Int
+	Expiration uint64
+	Nonce      uint64
+}
+
+// Expired reports whether the allowance can no longer be used at now.
+// Permit2 accepts a transfer up to and including the expiration second.
+func (a Allowance) Expired(now time.Time) bool {
+	return uint64(now.Unix()) > a.Expiration
+}
+
+// AllowanceCalldata is Permit2's allowance(owner, token, spender).
+func AllowanceCalldata(owner, token, spender common.Address) []byte {
+	data := append([]byte{}, permit2AllowanceSelector...)
+	for _, a := range []common.Address{owner, token, spender} {
+		data = append(data, common.LeftPadBytes(a.Bytes(), 32)...)
+	}
+	return data
+}
+
+// ParseAllowance decodes allowance's (uint160 amount, uint48 expiration,
+// uint48 nonce).
+func ParseAllowance(out []byte) (Allowance, error) {
+	if len(out) != 96 {
+		return Allowance{}, fmt.Errorf("permit2 allowance returned %d bytes, want 96", len(out))
+	}
+	expiration := new(big.Int).SetBytes(out[32:64])
+	nonce := new(big.Int).SetBytes(out[64:96])
+	if !expiration.IsUint64() || !nonce.IsUint64() {
+		return Allowance{}, fmt.Errorf("permit2 allowance out of range")
+	}
+	return Allowance{
+		Amount:     new(big.Int).SetBytes(out[:32]),
+		Expiration: expiration.Uint64(),
+		Nonce:      nonce.Uint64(),
+	}, nil
+}
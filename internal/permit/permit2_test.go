@@ -0,0 +1,56 @@
+package permit
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermitSingle_TypedData(t *testing.T) {
+	p := PermitSingle{
+		Token:       common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		Amount:      big.NewInt(25_000_000),
+		Expiration:  1_800_000_000,
+		Nonce:       3,
+		Spender:     common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"),
+		SigDeadline: big.NewInt(1_790_000_000),
+	}
+	require.NoError(t, p.Validate())
+
+	td := p.TypedData(big.NewInt(1))
+	// Permit2's PERMIT_SINGLE_TYPEHASH.
+	assert.Equal(t, "0xf3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0", td.TypeHash("PermitSingle").String())
+	_, _, err := apitypes.TypedDataAndHash(td)
+	require.NoError(t, err)
+
+	p.Amount = new(big.Int).Add(MaxPermit2Amount, big.NewInt(1))
+	assert.Error(t, p.Validate())
+}
+
+func TestParseAllowance(t *testing.T) {
+	out := common.LeftPadBytes(big.NewInt(500).Bytes(), 32)
+	out = append(out, common.LeftPadBytes(big.NewInt(1_700_000_000).Bytes(), 32)...)
+	out = append(out, common.LeftPadBytes(big.NewInt(7).Bytes(), 32)...)
+
+	a, err := ParseAllowance(out)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(500), a.Amount)
+	assert.Equal(t, uint64(7), a.Nonce)
+	assert.False(t, a.Expired(time.Unix(1_700_000_000, 0)))
+	assert.True(t, a.Expired(time.Unix(1_700_000_001, 0)))
+
+	_, err = ParseAllowance(out[:64])
+	assert.Error(t, err)
+}
+
+func TestAllowanceCalldata(t *testing.T) {
+	data := AllowanceCalldata(common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03"))
+	assert.Equal(t, "0x927da105", hexutil.Encode(data[:4]))
+	assert.Len(t, data, 4+3*32)
+}
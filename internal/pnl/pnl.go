@@ -0,0 +1,336 @@
+// Package pnl computes cost basis and profit and loss from recorded asset
+// flows, matching disposals to acquisitions first-in first-out.
+package pnl
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind says what moved an asset.
+type Kind string
+
+const (
+	KindTransfer Kind = "transfer" // value or token moved in or out
+	KindFee      Kind = "fee"      // gas paid
+)
+
+// Event is one asset flow for one address: positive Amount is an
+// acquisition, negative a disposal. PriceUSD is the price when the flow
+// was recorded, zero when unknown. Token is empty for the native currency.
+type Event struct {
+	Chain    string
+	TxHash   string
+	Index    int
+	Address  string
+	Symbol   string
+	Token    string
+	Amount   float64
+	PriceUSD float64
+	Kind     Kind
+	At       time.Time
+}
+
+func (e Event) asset() string {
+	return e.Chain + "/" + strings.ToLower(e.Token)
+}
+
+// Disposal is part of an outflow matched against one acquisition lot. An
+// outflow that spans several lots yields one Disposal per lot. When nothing
+// was recorded as acquired, Acquired is zero and Cost is unknown (zero).
+type Disposal struct {
+	Chain    string
+	Symbol   string
+	Token    string
+	TxHash   string
+	Kind     Kind
+	Amount   float64
+	Proceeds float64
+	Cost     float64
+	Acquired time.Time
+	Disposed time.Time
+}
+
+// Gain is the realized profit (negative for a loss).
+func (d Disposal) Gain() float64 {
+	return d.Proceeds - d.Cost
+}
+
+// LongTerm reports whether the lot was held for more than a year.
+func (d Disposal) LongTerm() bool {
+	return !d.Acquired.IsZero() && d.Disposed.Sub(d.Acquired) > 365*24*time.Hour
+}
+
+// Position is the P&L of one asset.
+type Position struct {
+	Chain    string
+	Symbol   string
+	Token    string
+	Held     float64 // amount still held from recorded acquisitions
+	Cost     float64 // cost basis of Held
+	Realized float64
+	// NoBasis is the amount disposed without a recorded acquisition, e.g.
+	// funds the wallet held before clifi recorded its transactions.
+	NoBasis float64
+	Price   float64 // current price; zero when unknown
+}
+
+// Value is Held at the current price.
+func (p Position) Value() float64 {
+	return p.Held * p.Price
+}
+
+// Unrealized is the paper gain of Held, or zero without a current price.
+func (p Position) Unrealized() float64 {
+	if p.Price == 0 {
+		return 0
+	}
+	return p.Value() - p.Cost
+}
+
+// Report is the P&L of an address.
+type Report struct {
+	Address   string
+	Positions []Position
+	Disposals []Disposal
+	// Unpriced counts events recorded without a price; their cost or
+	// proceeds count as zero.
+	Unpriced int
+}
+
+type lot struct {
+	amount float64
+	price  float64
+	at     time.Time
+}
+
+// dust is the amount below which a lot counts as used up, absorbing float
+// rounding.
+const dust = 1e-12
+
+// Compute matches events FIFO per asset. Events are sorted by time first.
+// price returns an asset's current USD price, or zero when unknown; it may
+// be nil.
+func Compute(address string, events []Event, price func(chain, token string) float64) Report {
+	events = append([]Event(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	r := Report{Address: address}
+	lots := make(map[string][]lot)
+	index := make(map[string]int)
+	for _, e := range events {
+		if e.PriceUSD == 0 {
+			r.Unpriced++
+		}
+		key := e.asset()
+		i, ok := index[key]
+		if !ok {
+			i = len(r.Positions)
+			index[key] = i
+			r.Positions = append(r.Positions, Position{Chain: e.Chain, Symbol: e.Symbol, Token: e.Token})
+		}
+		pos := &r.Positions[i]
+
+		if e.Amount > 0 {
+			lots[key] = append(lots[key], lot{amount: e.Amount, price: e.PriceUSD, at: e.At})
+			continue
+		}
+		remaining := -e.Amount
+		for remaining > dust && len(lots[key]) > 0 {
+			l := &lots[key][0]
+			take := min(remaining, l.amount)
+			d := Disposal{
+				Chain: e.Chain, Symbol: e.Symbol, Token: e.Token, TxHash: e.TxHash, Kind: e.Kind,
+				Amount:   take,
+				Proceeds: take * e.PriceUSD,
+				Cost:     take * l.price,
+				Acquired: l.at,
+				Disposed: e.At,
+			}
+			r.Disposals = append(r.Disposals, d)
+			pos.Realized += d.Gain()
+			l.amount -= take
+			remaining -= take
+			if l.amount <= dust {
+				lots[key] = lots[key][1:]
+			}
+		}
+		if remaining > dust {
+			d := Disposal{
+				Chain: e.Chain, Symbol: e.Symbol, Token: e.Token, TxHash: e.TxHash, Kind: e.Kind,
+				Amount:   remaining,
+				Proceeds: remaining * e.PriceUSD,
+				Disposed: e.At,
+			}
+			r.Disposals = append(r.Disposals, d)
+			pos.Realized += d.Gain()
+			pos.NoBasis += remaining
+		}
+	}
+
+	for i := range r.Positions {
+		pos := &r.Positions[i]
+		for _, l := range lots[pos.Chain+"/"+strings.ToLower(pos.Token)] {
+			pos.Held += l.amount
+			pos.Cost += l.amount * l.price
+		}
+		if price != nil && pos.Held > dust {
+			pos.Price = price(pos.Chain, pos.Token)
+		}
+	}
+	return r
+}
+
+// Totals sums realized and unrealized P&L over all positions.
+func (r Report) Totals() (realized, unrealized float64) {
+	for _, p := range r.Positions {
+		realized += p.Realized
+		unrealized += p.Unrealized()
+	}
+	return realized, unrealized
+}
+
+// Headers are the column names for Rows.
+var Headers = []string{"Chain", "Asset", "Held", "Cost basis", "Value", "Unrealized", "Realized"}
+
+// Rows renders the positions as table rows.
+func (r Report) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Positions))
+	for _, p := range r.Positions {
+		value, unrealized := "n/a", "n/a"
+		if p.Price != 0 {
+			value = "$" + usd(p.Value())
+			unrealized = signedUSD(p.Unrealized())
+		}
+		if p.Held <= dust {
+			value, unrealized = "-", "-"
+		}
+		rows = append(rows, []string{
+			p.Chain, p.Symbol, amount(p.Held), "$" + usd(p.Cost), value, unrealized, signedUSD(p.Realized),
+		})
+	}
+	return rows
+}
+
+// Notes lists caveats about the numbers, one per line.
+func (r Report) Notes() []string {
+	var notes []string
+	for _, p := range r.Positions {
+		if p.NoBasis > dust {
+			notes = append(notes, fmt.Sprintf("%s %s on %s was disposed of without a recorded acquisition; its cost basis counts as $0.", amount(p.NoBasis), p.Symbol, p.Chain))
+		}
+	}
+	if r.Unpriced > 0 {
+		notes = append(notes, fmt.Sprintf("%d flows had no price when recorded and count as $0.", r.Unpriced))
+	}
+	return notes
+}
+
+// Summary gives the totals in one line.
+func (r Report) Summary() string {
+	realized, unrealized := r.Totals()
+	return fmt.Sprintf("P&L for %s: realized %s, unrealized %s", r.Address, signedUSD(realized), signedUSD(unrealized))
+}
+
+// Text renders the report as plain text.
+func (r Report) Text() string {
+	var b strings.Builder
+	b.WriteString(r.Summary() + "\n")
+	if len(r.Positions) == 0 {
+		b.WriteString("\nNo transactions recorded yet. clifi records the flows of transactions it sends or fetches receipts for.\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	rows := append([][]string{Headers}, r.Rows()...)
+	widths := make([]int, len(Headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if i >= 2 {
+				b.WriteString(pad + cell)
+			} else {
+				b.WriteString(cell + pad)
+			}
+			if i < len(row)-1 {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	notes := r.Notes()
+	for _, n := range notes {
+		b.WriteString("\nNote: " + n)
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// WriteCSV writes one row per disposal lot, in the shape tax forms such as
+// Form 8949 ask for.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"description", "date_acquired", "date_sold", "proceeds_usd", "cost_basis_usd", "gain_usd", "term", "kind", "chain", "token", "tx_hash"})
+	for _, d := range r.Disposals {
+		acquired, term := "unknown", "unknown"
+		if !d.Acquired.IsZero() {
+			acquired = d.Acquired.UTC().Format(time.DateOnly)
+			term = "short"
+			if d.LongTerm() {
+				term = "long"
+			}
+		}
+		_ = cw.Write([]string{
+			fmt.Sprintf("%s %s", amount(d.Amount), d.Symbol),
+			acquired,
+			d.Disposed.UTC().Format(time.DateOnly),
+			strconv.FormatFloat(d.Proceeds, 'f', 2, 64),
+			strconv.FormatFloat(d.Cost, 'f', 2, 64),
+			strconv.FormatFloat(d.Gain(), 'f', 2, 64),
+			term,
+			string(d.Kind),
+			d.Chain,
+			d.Token,
+			d.TxHash,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func amount(v float64) string {
+	if v <= dust && v >= -dust {
+		return "0"
+	}
+	s := strconv.FormatFloat(v, 'f', 6, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "0" || s == "-0" {
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	}
+	return s
+}
+
+func usd(v float64) string {
+	if v < 0 {
+		v = -v
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func signedUSD(v float64) string {
+	if v < 0 {
+		return "-$" + usd(v)
+	}
+	return "+$" + usd(v)
+}
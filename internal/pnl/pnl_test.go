@@ -0,0 +1,75 @@
+package pnl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute_FIFO(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	events := []Event{
+		{Chain: "base", Symbol: "ETH", Amount: 1, PriceUSD: 2000, At: t0, TxHash: "0x1"},
+		{Chain: "base", Symbol: "ETH", Amount: 1, PriceUSD: 3000, At: t0.Add(day), TxHash: "0x2"},
+		// Sells the whole first lot and half the second.
+		{Chain: "base", Symbol: "ETH", Amount: -1.5, PriceUSD: 4000, At: t0.Add(400 * day), TxHash: "0x3"},
+		{Chain: "base", Symbol: "ETH", Amount: -0.01, PriceUSD: 4000, At: t0.Add(400 * day), TxHash: "0x3", Index: 1, Kind: KindFee},
+		// USDC that was never recorded coming in.
+		{Chain: "base", Symbol: "USDC", Token: "0xUSDC", Amount: -100, PriceUSD: 1, At: t0.Add(2 * day), TxHash: "0x4"},
+	}
+	price := func(chain, token string) float64 {
+		if token == "" {
+			return 5000
+		}
+		return 0
+	}
+
+	r := Compute("0xabc", events, price)
+	require.Len(t, r.Positions, 2)
+
+	eth := r.Positions[0]
+	assert.InDelta(t, 0.49, eth.Held, 1e-9)
+	assert.InDelta(t, 0.49*3000, eth.Cost, 1e-6)
+	// (1*4000-2000) + (0.5*4000-1500) + (0.01*4000-30)
+	assert.InDelta(t, 2000+500+10, eth.Realized, 1e-6)
+	assert.InDelta(t, 0.49*5000-0.49*3000, eth.Unrealized(), 1e-6)
+
+	usdc := r.Positions[1]
+	assert.InDelta(t, 100, usdc.NoBasis, 1e-9)
+	assert.InDelta(t, 100, usdc.Realized, 1e-9, "no basis, so proceeds are all gain")
+	assert.Zero(t, usdc.Price, "nothing held, so not priced")
+
+	// In time order: the USDC first.
+	require.Len(t, r.Disposals, 4)
+	assert.True(t, r.Disposals[0].Acquired.IsZero())
+	assert.True(t, r.Disposals[1].LongTerm())
+	assert.InDelta(t, 0.5, r.Disposals[2].Amount, 1e-9)
+	assert.Equal(t, KindFee, r.Disposals[3].Kind)
+
+	realized, unrealized := r.Totals()
+	assert.InDelta(t, 2610, realized, 1e-6)
+	assert.InDelta(t, 980, unrealized, 1e-6)
+	assert.Contains(t, r.Text(), "100 USDC on base was disposed of without a recorded acquisition")
+}
+
+func TestWriteCSV(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := Compute("0xabc", []Event{
+		{Chain: "base", Symbol: "ETH", Amount: 1, PriceUSD: 2000, At: t0},
+		{Chain: "base", Symbol: "ETH", Amount: -1, PriceUSD: 1500, At: t0.Add(time.Hour), TxHash: "0x2", Kind: KindTransfer},
+		{Chain: "base", Symbol: "ETH", Amount: -0.5, PriceUSD: 1500, At: t0.Add(2 * time.Hour), TxHash: "0x3", Kind: KindTransfer},
+	}, nil)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "description,date_acquired,date_sold,proceeds_usd,cost_basis_usd,gain_usd,term,kind,chain,token,tx_hash", lines[0])
+	assert.Equal(t, "1 ETH,2025-01-01,2025-01-01,1500.00,2000.00,-500.00,short,transfer,base,,0x2", lines[1])
+	assert.Equal(t, "0.5 ETH,unknown,2025-01-01,750.00,0.00,750.00,unknown,transfer,base,,0x3", lines[2])
+}
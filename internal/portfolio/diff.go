@@ -0,0 +1,230 @@
+package portfolio
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change is how one asset moved between two snapshots. Before or After is
+// zero for an asset that appeared or disappeared.
+type Change struct {
+	Chain     string
+	Symbol    string
+	Token     string
+	Before    float64
+	After     float64
+	BeforeUSD float64
+	AfterUSD  float64
+	// Priced is false when neither snapshot had a price for the asset.
+	Priced bool
+}
+
+// Report compares two snapshots of one address.
+type Report struct {
+	Address string
+	From    time.Time
+	To      time.Time
+	Changes []Change
+	// Chains read in only one of the snapshots are left out of Changes.
+	Skipped []string
+}
+
+// Diff compares old with new over the chains both snapshots read.
+func Diff(old, new Snapshot) Report {
+	r := Report{Address: new.Address, From: old.TakenAt, To: new.TakenAt}
+	var chains []string
+	for _, c := range new.Chains {
+		if slices.Contains(old.Chains, c) {
+			chains = append(chains, c)
+		} else {
+			r.Skipped = append(r.Skipped, c)
+		}
+	}
+	for _, c := range old.Chains {
+		if !slices.Contains(new.Chains, c) {
+			r.Skipped = append(r.Skipped, c)
+		}
+	}
+
+	before := make(map[string]Asset)
+	for _, a := range old.Assets {
+		before[a.key()] = a
+	}
+	seen := make(map[string]bool)
+	add := func(o, n Asset) {
+		// An asset priced in only one snapshot is valued at that price in
+		// both, so the change reflects the balance.
+		op, np := o.PriceUSD, n.PriceUSD
+		if op == 0 {
+			op = np
+		}
+		if np == 0 {
+			np = op
+		}
+		ref := n
+		if ref.Symbol == "" {
+			ref = o
+		}
+		r.Changes = append(r.Changes, Change{
+			Chain:     ref.Chain,
+			Symbol:    ref.Symbol,
+			Token:     ref.Token,
+			Before:    o.Amount,
+			After:     n.Amount,
+			BeforeUSD: o.Amount * op,
+			AfterUSD:  n.Amount * np,
+			Priced:    np != 0,
+		})
+	}
+	for _, n := range new.Assets {
+		if !slices.Contains(chains, n.Chain) {
+			continue
+		}
+		seen[n.key()] = true
+		add(before[n.key()], n)
+	}
+	for _, o := range old.Assets {
+		if !slices.Contains(chains, o.Chain) || seen[o.key()] {
+			continue
+		}
+		add(o, Asset{})
+	}
+	return r
+}
+
+// TotalsUSD sums the priced assets' values before and after.
+func (r Report) TotalsUSD() (before, after float64) {
+	for _, c := range r.Changes {
+		before += c.BeforeUSD
+		after += c.AfterUSD
+	}
+	return before, after
+}
+
+// Headers are the column names for Rows.
+var Headers = []string{"Chain", "Asset", "Before", "After", "Change", "Value change"}
+
+// Rows renders the changes as table rows.
+func (r Report) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Changes))
+	for _, c := range r.Changes {
+		value := "n/a"
+		if c.Priced {
+			value = signedUSD(c.AfterUSD - c.BeforeUSD)
+		}
+		rows = append(rows, []string{
+			c.Chain, c.Symbol,
+			formatAmount(c.Before), formatAmount(c.After),
+			signed(formatAmount(c.After-c.Before), c.After-c.Before),
+			value,
+		})
+	}
+	return rows
+}
+
+// Summary describes the period and the total value change in one line.
+func (r Report) Summary() string {
+	before, after := r.TotalsUSD()
+	s := fmt.Sprintf("Portfolio of %s from %s to %s: $%s → $%s (%s",
+		r.Address, r.From.Local().Format("2006-01-02 15:04"), r.To.Local().Format("2006-01-02 15:04"),
+		formatUSD(before), formatUSD(after), signedUSD(after-before))
+	if before > 0 {
+		s += fmt.Sprintf(", %+.1f%%", (after-before)/before*100)
+	}
+	s += ")"
+	if len(r.Skipped) > 0 {
+		s += fmt.Sprintf("; not compared: %s (missing from one snapshot)", strings.Join(r.Skipped, ", "))
+	}
+	return s
+}
+
+// Text renders the report as a plain-text table.
+func (r Report) Text() string {
+	rows := append([][]string{Headers}, r.Rows()...)
+	widths := make([]int, len(Headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	var b strings.Builder
+	b.WriteString(r.Summary())
+	b.WriteString("\n\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("No assets to compare.\n")
+		return b.String()
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			pad := widths[i] - len([]rune(cell))
+			if i >= 2 {
+				b.WriteString(strings.Repeat(" ", pad) + cell)
+			} else {
+				b.WriteString(cell + strings.Repeat(" ", pad))
+			}
+			if i < len(row)-1 {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatAmount(v float64) string {
+	if v == 0 {
+		return "0"
+	}
+	s := strconv.FormatFloat(v, 'f', 6, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "0" || s == "-0" {
+		// Too small for six places; keep it from reading as zero.
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	}
+	return s
+}
+
+func formatUSD(v float64) string {
+	return strconv.FormatFloat(math.Abs(v), 'f', 2, 64)
+}
+
+func signed(s string, v float64) string {
+	if v > 0 {
+		return "+" + s
+	}
+	return s
+}
+
+func signedUSD(v float64) string {
+	if v < 0 {
+		return "-$" + formatUSD(v)
+	}
+	return "+$" + formatUSD(v)
+}
+
+// ParseSince parses a look-back period such as 7d, 2w, 12h or 90m.
+func ParseSince(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit *= 7
+		}
+		var n float64
+		n, err = strconv.ParseFloat(s[:len(s)-1], 64)
+		d = time.Duration(n * float64(unit))
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid period %q: use e.g. 7d, 2w or 12h", s)
+	}
+	return d, nil
+}
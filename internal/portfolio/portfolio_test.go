@@ -0,0 +1,100 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addr = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+
+func snapshot(at time.Time, chains []string, assets ...Asset) Snapshot {
+	return Snapshot{Address: addr, TakenAt: at, Chains: chains, Assets: assets}
+}
+
+func TestDiff(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	old := snapshot(t0, []string{"base", "arbitrum"},
+		Asset{Chain: "base", Symbol: "ETH", Amount: 1, PriceUSD: 3000},
+		Asset{Chain: "base", Symbol: "USDC", Token: "0xUSDC", Amount: 500, PriceUSD: 1},
+		Asset{Chain: "arbitrum", Symbol: "ETH", Amount: 2, PriceUSD: 3000},
+	)
+	// arbitrum failed to load this time, polygon is new.
+	cur := snapshot(t0.Add(7*24*time.Hour), []string{"base", "polygon"},
+		Asset{Chain: "base", Symbol: "ETH", Amount: 1.5, PriceUSD: 4000},
+		Asset{Chain: "base", Symbol: "WETH", Token: "0xWETH", Amount: 0.1},
+		Asset{Chain: "polygon", Symbol: "POL", Amount: 10, PriceUSD: 0.5},
+	)
+
+	r := Diff(old, cur)
+	assert.Equal(t, []string{"polygon", "arbitrum"}, r.Skipped)
+	require.Len(t, r.Changes, 3)
+
+	assert.Equal(t, Change{Chain: "base", Symbol: "ETH", Before: 1, After: 1.5, BeforeUSD: 3000, AfterUSD: 6000, Priced: true}, r.Changes[0])
+	assert.Equal(t, "WETH", r.Changes[1].Symbol)
+	assert.False(t, r.Changes[1].Priced)
+	assert.Equal(t, Change{Chain: "base", Symbol: "USDC", Token: "0xUSDC", Before: 500, After: 0, BeforeUSD: 500, AfterUSD: 0, Priced: true}, r.Changes[2])
+
+	before, after := r.TotalsUSD()
+	assert.InDelta(t, 3500, before, 1e-9)
+	assert.InDelta(t, 6000, after, 1e-9)
+
+	rows := r.Rows()
+	assert.Equal(t, []string{"base", "ETH", "1", "1.5", "+0.5", "+$3000.00"}, rows[0])
+	assert.Equal(t, []string{"base", "WETH", "0", "0.1", "+0.1", "n/a"}, rows[1])
+	assert.Equal(t, []string{"base", "USDC", "500", "0", "-500", "-$500.00"}, rows[2])
+	assert.Contains(t, r.Summary(), "$3500.00 → $6000.00 (+$2500.00, +71.4%)")
+	assert.Contains(t, r.Summary(), "not compared: polygon, arbitrum")
+}
+
+func TestStore_Baseline(t *testing.T) {
+	s, err := OpenStoreDSN(":memory:")
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Baseline(addr, time.Now())
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, amount := range []float64{1, 2, 3} {
+		_, err := s.Save(snapshot(t0.Add(time.Duration(i)*24*time.Hour), []string{"base"},
+			Asset{Chain: "base", Symbol: "ETH", Amount: amount, PriceUSD: 3000}))
+		require.NoError(t, err)
+	}
+
+	// Latest at or before the cutoff.
+	got, err := s.Baseline(addr, t0.Add(36*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, t0.Add(24*time.Hour), got.TakenAt)
+	assert.Equal(t, []string{"base"}, got.Chains)
+	require.Len(t, got.Assets, 1)
+	assert.InDelta(t, 2, got.Assets[0].Amount, 1e-9)
+
+	// Nothing that old: the oldest one. Addresses match case-insensitively.
+	got, err = s.Baseline("0x70997970c51812dc3a010c7d01b50e0d17dc79c8", t0.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, t0, got.TakenAt)
+
+	got, err = s.Latest(addr)
+	require.NoError(t, err)
+	assert.InDelta(t, 3, got.Assets[0].Amount, 1e-9)
+}
+
+func TestParseSince(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"7d":   7 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	} {
+		got, err := ParseSince(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"", "d", "-1d", "0h", "week"} {
+		_, err := ParseSince(bad)
+		assert.Error(t, err, bad)
+	}
+}
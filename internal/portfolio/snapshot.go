@@ -0,0 +1,135 @@
+// Package portfolio records balance snapshots over time and compares them.
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/quote"
+)
+
+// DefaultChains are snapshotted when no chains are given; the same set
+// `clifi portfolio` shows.
+var DefaultChains = []string{"ethereum", "base", "arbitrum", "optimism", "polygon"}
+
+// Asset is one holding in a snapshot. Token is empty for the native
+// currency. PriceUSD is zero when no price was available.
+type Asset struct {
+	Chain    string
+	Symbol   string
+	Token    string
+	Amount   float64
+	PriceUSD float64
+}
+
+// ValueUSD is the asset's value, or zero when it has no price.
+func (a Asset) ValueUSD() float64 {
+	return a.Amount * a.PriceUSD
+}
+
+func (a Asset) key() string {
+	return a.Chain + "/" + strings.ToLower(a.Token)
+}
+
+// Snapshot is an address's balances at one point in time. Chains lists the
+// chains that were read successfully; a chain missing from it says nothing
+// about the balances there.
+type Snapshot struct {
+	ID      int64
+	Address string
+	TakenAt time.Time
+	Chains  []string
+	Assets  []Asset
+}
+
+// Prices looks up USD prices. *quote.Client implements it.
+type Prices interface {
+	TokenPrice(ctx context.Context, chainID int64, token string) (float64, error)
+}
+
+// Take reads the native balance and the known tokens (see
+// chain.KnownTokens) of address on each chain and prices them. Tokens with
+// a zero balance are left out. Chains that cannot be read are skipped; Take
+// fails only when none could be read.
+func Take(ctx context.Context, client *chain.Client, prices Prices, address common.Address, chains []string) (Snapshot, error) {
+	if len(chains) == 0 {
+		chains = DefaultChains
+	}
+	known := chain.KnownTokens()
+	tokens := make(map[string][]common.Address)
+	for _, name := range chains {
+		if _, err := client.GetChainConfig(name); err != nil {
+			return Snapshot{}, err
+		}
+		// Snapshots must be current, not served from the balance cache.
+		client.InvalidateBalance(name, address)
+		for _, t := range known[name] {
+			tokens[name] = append(tokens[name], common.HexToAddress(t.Address))
+		}
+	}
+
+	p, err := client.GetPortfolio(ctx, address, chains, tokens)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{Address: address.Hex(), TakenAt: time.Now().UTC()}
+	for _, name := range chains {
+		native, ok := p.NativeBalances[name]
+		if !ok {
+			slog.Warn("portfolio snapshot skipped chain", "chain", name, "err", p.Errors[name])
+			continue
+		}
+		cfg, _ := client.GetChainConfig(name)
+		price := func(token string) float64 {
+			if prices == nil || cfg.IsTestnet {
+				return 0
+			}
+			v, err := prices.TokenPrice(ctx, cfg.ChainID.Int64(), token)
+			if err != nil {
+				slog.Debug("no price for snapshot asset", "chain", name, "token", token, "err", err)
+				return 0
+			}
+			return v
+		}
+
+		snap.Chains = append(snap.Chains, name)
+		snap.Assets = append(snap.Assets, Asset{
+			Chain:    name,
+			Symbol:   native.Symbol,
+			Amount:   toFloat(native.Balance, native.Decimals),
+			PriceUSD: price(quote.NativeToken.Hex()),
+		})
+		for _, tb := range p.TokenBalances[name] {
+			if tb.Balance == nil || tb.Balance.Sign() == 0 {
+				continue
+			}
+			snap.Assets = append(snap.Assets, Asset{
+				Chain:    name,
+				Symbol:   tb.Symbol,
+				Token:    tb.TokenAddress,
+				Amount:   toFloat(tb.Balance, tb.Decimals),
+				PriceUSD: price(tb.TokenAddress),
+			})
+		}
+	}
+	if len(snap.Chains) == 0 {
+		return Snapshot{}, fmt.Errorf("no chain could be read")
+	}
+	return snap, nil
+}
+
+func toFloat(amount *big.Int, decimals uint8) float64 {
+	if amount == nil {
+		return 0
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+	return v
+}
@@ -0,0 +1,154 @@
+package portfolio
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNoSnapshot means no snapshot has been taken for the address yet.
+var ErrNoSnapshot = errors.New("no portfolio snapshot yet")
+
+// Store keeps snapshots in the local DB (dataDir/receipts.db), next to
+// receipts and token metadata.
+type Store struct {
+	db *sql.DB
+}
+
+// OpenStore opens (or creates) the snapshot tables under dataDir.
+func OpenStore(dataDir string) (*Store, error) {
+	return OpenStoreDSN(filepath.Join(dataDir, "receipts.db"))
+}
+
+// OpenStoreDSN opens a snapshot store using the given sqlite DSN/path.
+// Tests may pass ":memory:".
+func OpenStoreDSN(dsn string) (*Store, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open portfolio db: %w", err)
+	}
+	if dsn == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
+	_, err = db.Exec(`
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	address TEXT NOT NULL,
+	taken_at INTEGER NOT NULL,
+	chains TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS portfolio_snapshots_address ON portfolio_snapshots (address, taken_at);
+CREATE TABLE IF NOT EXISTS portfolio_assets (
+	snapshot_id INTEGER NOT NULL,
+	chain TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	token TEXT NOT NULL,
+	amount REAL NOT NULL,
+	price_usd REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS portfolio_assets_snapshot ON portfolio_assets (snapshot_id);
+`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create portfolio tables: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying DB.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save stores a snapshot and returns its ID.
+func (s *Store) Save(snap Snapshot) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("save snapshot: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`INSERT INTO portfolio_snapshots (address, taken_at, chains) VALUES (?, ?, ?)`,
+		addressKey(snap.Address), snap.TakenAt.Unix(), strings.Join(snap.Chains, ","))
+	if err != nil {
+		return 0, fmt.Errorf("save snapshot: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("save snapshot: %w", err)
+	}
+	for _, a := range snap.Assets {
+		_, err := tx.Exec(`INSERT INTO portfolio_assets (snapshot_id, chain, symbol, token, amount, price_usd) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, a.Chain, a.Symbol, a.Token, a.Amount, a.PriceUSD)
+		if err != nil {
+			return 0, fmt.Errorf("save snapshot: %w", err)
+		}
+	}
+	return id, tx.Commit()
+}
+
+// Baseline returns the snapshot to compare against for changes since t:
+// the latest one taken at or before t, or the oldest one when every
+// snapshot is newer than t.
+func (s *Store) Baseline(address string, t time.Time) (Snapshot, error) {
+	addr := addressKey(address)
+	row := s.db.QueryRow(`SELECT id, taken_at, chains FROM portfolio_snapshots WHERE address = ? AND taken_at <= ? ORDER BY taken_at DESC, id DESC LIMIT 1`, addr, t.Unix())
+	snap, err := s.scan(address, row)
+	if errors.Is(err, sql.ErrNoRows) {
+		row = s.db.QueryRow(`SELECT id, taken_at, chains FROM portfolio_snapshots WHERE address = ? ORDER BY taken_at, id LIMIT 1`, addr)
+		snap, err = s.scan(address, row)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrNoSnapshot
+	}
+	return snap, err
+}
+
+// Latest returns the most recent snapshot of address.
+func (s *Store) Latest(address string) (Snapshot, error) {
+	row := s.db.QueryRow(`SELECT id, taken_at, chains FROM portfolio_snapshots WHERE address = ? ORDER BY taken_at DESC, id DESC LIMIT 1`, addressKey(address))
+	snap, err := s.scan(address, row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrNoSnapshot
+	}
+	return snap, err
+}
+
+func (s *Store) scan(address string, row *sql.Row) (Snapshot, error) {
+	var (
+		snap   Snapshot
+		taken  int64
+		chains string
+	)
+	if err := row.Scan(&snap.ID, &taken, &chains); err != nil {
+		return Snapshot{}, err
+	}
+	snap.Address = address
+	snap.TakenAt = time.Unix(taken, 0).UTC()
+	if chains != "" {
+		snap.Chains = strings.Split(chains, ",")
+	}
+
+	rows, err := s.db.Query(`SELECT chain, symbol, token, amount, price_usd FROM portfolio_assets WHERE snapshot_id = ? ORDER BY rowid`, snap.ID)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("load snapshot: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a Asset
+		if err := rows.Scan(&a.Chain, &a.Symbol, &a.Token, &a.Amount, &a.PriceUSD); err != nil {
+			return Snapshot{}, fmt.Errorf("load snapshot: %w", err)
+		}
+		snap.Assets = append(snap.Assets, a)
+	}
+	return snap, rows.Err()
+}
+
+func addressKey(address string) string {
+	return strings.ToLower(address)
+}
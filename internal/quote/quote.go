@@ -0,0 +1,320 @@
+// Package quote fetches swap and bridge quotes from the LI.FI aggregator.
+// Quotes are informational: routes are requested without transaction data.
+// SwapTx is the exception, returning an unsigned transaction that callers
+// validate against their policy before signing.
+package quote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultBaseURL is the LI.FI API root.
+const DefaultBaseURL = "https://li.quest/v1"
+
+// NativeToken is the address LI.FI uses for a chain's native currency.
+var NativeToken = common.Address{}
+
+// Client requests routes from LI.FI. LIFI_API_KEY raises the rate limit but
+// is not required.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+}
+
+// NewClient creates a client for the public LI.FI API.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+		baseURL:    DefaultBaseURL,
+		apiKey:     os.Getenv("LIFI_API_KEY"),
+	}
+}
+
+// Request describes what to quote. A swap has equal chain IDs; a bridge
+// does not. Token addresses of NativeToken mean the native currency.
+type Request struct {
+	FromChainID int64
+	ToChainID   int64
+	FromToken   common.Address
+	ToToken     common.Address
+	FromAmount  *big.Int // in FromToken base units
+	SlippageBps uint32
+	// FromAddress is optional; some bridges quote better for known senders.
+	FromAddress *common.Address
+}
+
+// Token identifies one side of a quote.
+type Token struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	ChainID  int64  `json:"chainId"`
+}
+
+// Step is one hop of a route, e.g. a DEX swap or a bridge transfer.
+type Step struct {
+	Type        string // "swap", "cross" (bridge) or "lifi" (combined)
+	Tool        string // e.g. "uniswap", "stargate"
+	FromChainID int64
+	ToChainID   int64
+	FromSymbol  string
+	ToSymbol    string
+}
+
+// GasCost is the network fee of a route in one token.
+type GasCost struct {
+	Amount    *big.Int
+	Token     Token
+	AmountUSD string
+}
+
+// Quote is one route returned by the aggregator.
+type Quote struct {
+	FromToken     Token
+	ToToken       Token
+	FromAmount    *big.Int
+	ToAmount      *big.Int
+	FromAmountUSD string
+	ToAmountUSD   string
+	GasCostUSD    string
+	GasCosts      []GasCost
+	Steps         []Step
+	Duration      time.Duration
+	Tags          []string // e.g. "RECOMMENDED", "CHEAPEST", "FASTEST"
+}
+
+// PriceImpact returns the share of value lost between input and output in
+// USD terms (0.01 = 1%), excluding gas. ok is false when the aggregator had
+// no USD prices for the pair.
+func (q *Quote) PriceImpact() (impact float64, ok bool) {
+	from, err1 := strconv.ParseFloat(q.FromAmountUSD, 64)
+	to, err2 := strconv.ParseFloat(q.ToAmountUSD, 64)
+	if err1 != nil || err2 != nil || from <= 0 {
+		return 0, false
+	}
+	return (from - to) / from, true
+}
+
+// Route renders the steps, e.g. "Uniswap V3 (ETH->USDC) -> stargate (USDC base->arbitrum)".
+func (q *Quote) Route(chainName func(int64) string) string {
+	parts := make([]string, len(q.Steps))
+	for i, s := range q.Steps {
+		if s.FromChainID != s.ToChainID {
+			parts[i] = fmt.Sprintf("%s (%s %s->%s)", s.Tool, s.FromSymbol, chainName(s.FromChainID), chainName(s.ToChainID))
+		} else {
+			parts[i] = fmt.Sprintf("%s (%s->%s)", s.Tool, s.FromSymbol, s.ToSymbol)
+		}
+	}
+	return strings.Join(parts, " -> ")
+}
+
+type routesRequest struct {
+	FromChainID      int64         `json:"fromChainId"`
+	ToChainID        int64         `json:"toChainId"`
+	FromTokenAddress string        `json:"fromTokenAddress"`
+	ToTokenAddress   string        `json:"toTokenAddress"`
+	FromAmount       string        `json:"fromAmount"`
+	FromAddress      string        `json:"fromAddress,omitempty"`
+	Options          routesOptions `json:"options"`
+}
+
+type routesOptions struct {
+	Slippage float64 `json:"slippage"`
+	Order    string  `json:"order"`
+}
+
+type routesResponse struct {
+	Routes []struct {
+		FromAmountUSD string   `json:"fromAmountUSD"`
+		ToAmountUSD   string   `json:"toAmountUSD"`
+		FromAmount    string   `json:"fromAmount"`
+		ToAmount      string   `json:"toAmount"`
+		GasCostUSD    string   `json:"gasCostUSD"`
+		FromToken     Token    `json:"fromToken"`
+		ToToken       Token    `json:"toToken"`
+		Tags          []string `json:"tags"`
+		Steps         []struct {
+			Type        string `json:"type"`
+			Tool        string `json:"tool"`
+			ToolDetails struct {
+				Name string `json:"name"`
+			} `json:"toolDetails"`
+			Action struct {
+				FromChainID int64 `json:"fromChainId"`
+				ToChainID   int64 `json:"toChainId"`
+				FromToken   Token `json:"fromToken"`
+				ToToken     Token `json:"toToken"`
+			} `json:"action"`
+			Estimate struct {
+				ExecutionDuration float64 `json:"executionDuration"`
+				GasCosts          []struct {
+					Amount    string `json:"amount"`
+					AmountUSD string `json:"amountUSD"`
+					Token     Token  `json:"token"`
+				} `json:"gasCosts"`
+			} `json:"estimate"`
+		} `json:"steps"`
+	} `json:"routes"`
+}
+
+// Quotes returns the available routes, best first.
+func (c *Client) Quotes(ctx context.Context, req Request) ([]Quote, error) {
+	if req.FromAmount == nil || req.FromAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	body := routesRequest{
+		FromChainID:      req.FromChainID,
+		ToChainID:        req.ToChainID,
+		FromTokenAddress: req.FromToken.Hex(),
+		ToTokenAddress:   req.ToToken.Hex(),
+		FromAmount:       req.FromAmount.String(),
+		Options: routesOptions{
+			Slippage: float64(req.SlippageBps) / 10_000,
+			Order:    "RECOMMENDED",
+		},
+	}
+	if req.FromAddress != nil {
+		body.FromAddress = req.FromAddress.Hex()
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/advanced/routes", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("x-lifi-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("quote request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("quote request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(respBody))
+		}
+		return nil, fmt.Errorf("quote request failed: status %d: %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var parsed routesResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid quote response: %w", err)
+	}
+
+	quotes := make([]Quote, 0, len(parsed.Routes))
+	for _, r := range parsed.Routes {
+		q := Quote{
+			FromToken:     r.FromToken,
+			ToToken:       r.ToToken,
+			FromAmount:    parseAmount(r.FromAmount),
+			ToAmount:      parseAmount(r.ToAmount),
+			FromAmountUSD: r.FromAmountUSD,
+			ToAmountUSD:   r.ToAmountUSD,
+			GasCostUSD:    r.GasCostUSD,
+			Tags:          r.Tags,
+		}
+		for _, s := range r.Steps {
+			tool := s.ToolDetails.Name
+			if tool == "" {
+				tool = s.Tool
+			}
+			q.Steps = append(q.Steps, Step{
+				Type:        s.Type,
+				Tool:        tool,
+				FromChainID: s.Action.FromChainID,
+				ToChainID:   s.Action.ToChainID,
+				FromSymbol:  s.Action.FromToken.Symbol,
+				ToSymbol:    s.Action.ToToken.Symbol,
+			})
+			q.Duration += time.Duration(s.Estimate.ExecutionDuration * float64(time.Second))
+			for _, g := range s.Estimate.GasCosts {
+				q.GasCosts = append(q.GasCosts, GasCost{Amount: parseAmount(g.Amount), Token: g.Token, AmountUSD: g.AmountUSD})
+			}
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+func parseAmount(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return v
+}
+
+// TokenPrice returns a token's USD price on a chain. token is a symbol
+// (e.g. "ETH") or an address.
+func (c *Client) TokenPrice(ctx context.Context, chainID int64, token string) (float64, error) {
+	q := url.Values{"chain": {strconv.FormatInt(chainID, 10)}, "token": {token}}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/token?"+q.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("x-lifi-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("price request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("price request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+		return 0, fmt.Errorf("price request failed: status %d: %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var parsed struct {
+		PriceUSD string `json:"priceUSD"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0, fmt.Errorf("invalid price response: %w", err)
+	}
+	price, err := strconv.ParseFloat(parsed.PriceUSD, 64)
+	if err != nil {
+		return 0, fmt.Errorf("no USD price for %s", token)
+	}
+	return price, nil
+}
@@ -0,0 +1,173 @@
+package quote
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRoutes = `{"routes": [{
+	"fromAmountUSD": "1000.00",
+	"toAmountUSD": "990.00",
+	"fromAmount": "1000000000000000000",
+	"toAmount": "990000000",
+	"gasCostUSD": "1.25",
+	"fromToken": {"address": "0x0000000000000000000000000000000000000000", "symbol": "ETH", "decimals": 18, "chainId": 8453},
+	"toToken": {"address": "0xaf88d065e77c8cC2239327C5EDb3A432268e5831", "symbol": "USDC", "decimals": 6, "chainId": 42161},
+	"tags": ["RECOMMENDED"],
+	"steps": [
+		{"type": "swap", "tool": "uniswap", "toolDetails": {"name": "Uniswap V3"},
+		 "action": {"fromChainId": 8453, "toChainId": 8453, "fromToken": {"symbol": "ETH"}, "toToken": {"symbol": "USDC"}},
+		 "estimate": {"executionDuration": 30, "gasCosts": [{"amount": "100000000000000", "amountUSD": "0.25", "token": {"symbol": "ETH", "decimals": 18}}]}},
+		{"type": "cross", "tool": "stargate",
+		 "action": {"fromChainId": 8453, "toChainId": 42161, "fromToken": {"symbol": "USDC"}, "toToken": {"symbol": "USDC"}},
+		 "estimate": {"executionDuration": 60, "gasCosts": [{"amount": "400000000000000", "amountUSD": "1.00", "token": {"symbol": "ETH", "decimals": 18}}]}}
+	]
+}]}`
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &Client{httpClient: srv.Client(), baseURL: srv.URL, apiKey: "test-key"}
+}
+
+func TestQuotes(t *testing.T) {
+	usdc := common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831")
+	req := Request{
+		FromChainID: 8453,
+		ToChainID:   42161,
+		FromToken:   NativeToken,
+		ToToken:     usdc,
+		FromAmount:  big.NewInt(1_000_000_000_000_000_000),
+		SlippageBps: 50,
+	}
+
+	t.Run("parses routes", func(t *testing.T) {
+		var got routesRequest
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/advanced/routes", r.URL.Path)
+			assert.Equal(t, "test-key", r.Header.Get("x-lifi-api-key"))
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_, _ = w.Write([]byte(testRoutes))
+		})
+
+		quotes, err := c.Quotes(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42161), got.ToChainID)
+		assert.Equal(t, "1000000000000000000", got.FromAmount)
+		assert.Equal(t, usdc.Hex(), got.ToTokenAddress)
+		assert.InDelta(t, 0.005, got.Options.Slippage, 1e-9)
+
+		require.Len(t, quotes, 1)
+		q := quotes[0]
+		assert.Equal(t, "990000000", q.ToAmount.String())
+		assert.Equal(t, "USDC", q.ToToken.Symbol)
+		assert.Equal(t, 90*time.Second, q.Duration)
+		assert.Len(t, q.GasCosts, 2)
+		assert.Equal(t, "Uniswap V3", q.Steps[0].Tool, "display name preferred over tool key")
+
+		impact, ok := q.PriceImpact()
+		require.True(t, ok)
+		assert.InDelta(t, 0.01, impact, 1e-9)
+
+		route := q.Route(func(id int64) string { return strconv.FormatInt(id, 10) })
+		assert.Equal(t, "Uniswap V3 (ETH->USDC) -> stargate (USDC 8453->42161)", route)
+	})
+
+	t.Run("surfaces API errors", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "Invalid toTokenAddress"}`))
+		})
+		_, err := c.Quotes(context.Background(), req)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Invalid toTokenAddress")
+	})
+
+	t.Run("rejects zero amount", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("no request expected")
+		})
+		bad := req
+		bad.FromAmount = new(big.Int)
+		_, err := c.Quotes(context.Background(), bad)
+		assert.Error(t, err)
+	})
+}
+
+func TestPriceImpactWithoutPrices(t *testing.T) {
+	q := Quote{FromAmountUSD: "", ToAmountUSD: "5"}
+	_, ok := q.PriceImpact()
+	assert.False(t, ok)
+}
+
+func TestTokenPrice(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/token", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("chain"))
+		switch r.URL.Query().Get("token") {
+		case "ETH":
+			_, _ = w.Write([]byte(`{"symbol": "ETH", "priceUSD": "3021.50"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Token not found"}`))
+		}
+	})
+
+	price, err := c.TokenPrice(context.Background(), 1, "ETH")
+	require.NoError(t, err)
+	assert.InDelta(t, 3021.5, price, 1e-9)
+
+	_, err = c.TokenPrice(context.Background(), 1, "NOPE")
+	assert.ErrorContains(t, err, "Token not found")
+}
+
+func TestSwapTx(t *testing.T) {
+	usdc := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	from := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	req := Request{
+		FromChainID: 8453,
+		ToChainID:   8453,
+		FromToken:   usdc,
+		ToToken:     NativeToken,
+		FromAmount:  big.NewInt(50_000_000),
+		SlippageBps: 50,
+		FromAddress: &from,
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/quote", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "50000000", q.Get("fromAmount"))
+		assert.Equal(t, "0.005", q.Get("slippage"))
+		assert.Equal(t, from.Hex(), q.Get("fromAddress"))
+		_, _ = w.Write([]byte(`{
+			"tool": "uniswap", "toolDetails": {"name": "Uniswap V3"},
+			"action": {"fromToken": {"symbol": "USDC", "decimals": 6}, "toToken": {"symbol": "ETH", "decimals": 18}, "fromAmount": "50000000"},
+			"estimate": {"approvalAddress": "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE", "toAmount": "12500000000000000", "toAmountMin": "12437500000000000"},
+			"transactionRequest": {"to": "0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE", "data": "0xdeadbeef", "value": "0x0"}
+		}`))
+	})
+
+	s, err := c.SwapTx(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Uniswap V3", s.Tool)
+	assert.Equal(t, common.HexToAddress("0x1231DEB6f5749EF6cE6943a275A1D3E7486F4EaE"), s.ApprovalAddress)
+	assert.Equal(t, s.ApprovalAddress, s.To)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, s.Data)
+	assert.Zero(t, s.Value.Sign())
+	assert.Equal(t, "12437500000000000", s.ToAmountMin.String())
+
+	req.FromAddress = nil
+	_, err = c.SwapTx(context.Background(), req)
+	assert.ErrorContains(t, err, "from address")
+}
@@ -0,0 +1,146 @@
+package quote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Swap is a single-route quote with the transaction that executes it. The
+// transaction is unsigned; callers validate, sign and broadcast it.
+type Swap struct {
+	FromToken   Token
+	ToToken     Token
+	FromAmount  *big.Int
+	ToAmount    *big.Int
+	ToAmountMin *big.Int
+	Tool        string
+	// ApprovalAddress must be allowed to spend FromAmount of an ERC-20
+	// FromToken before the transaction is sent.
+	ApprovalAddress common.Address
+
+	To    common.Address
+	Data  []byte
+	Value *big.Int
+}
+
+type swapResponse struct {
+	Tool        string `json:"tool"`
+	ToolDetails struct {
+		Name string `json:"name"`
+	} `json:"toolDetails"`
+	Action struct {
+		FromToken  Token  `json:"fromToken"`
+		ToToken    Token  `json:"toToken"`
+		FromAmount string `json:"fromAmount"`
+	} `json:"action"`
+	Estimate struct {
+		ApprovalAddress string `json:"approvalAddress"`
+		ToAmount        string `json:"toAmount"`
+		ToAmountMin     string `json:"toAmountMin"`
+	} `json:"estimate"`
+	TransactionRequest *struct {
+		To    string `json:"to"`
+		Data  string `json:"data"`
+		Value string `json:"value"`
+	} `json:"transactionRequest"`
+}
+
+// SwapTx requests the best route for req with transaction data. Unlike
+// Quotes it needs req.FromAddress, which the transaction is built for.
+func (c *Client) SwapTx(ctx context.Context, req Request) (*Swap, error) {
+	if req.FromAmount == nil || req.FromAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	if req.FromAddress == nil {
+		return nil, fmt.Errorf("from address is required to build a swap")
+	}
+	q := url.Values{
+		"fromChain":   {strconv.FormatInt(req.FromChainID, 10)},
+		"toChain":     {strconv.FormatInt(req.ToChainID, 10)},
+		"fromToken":   {req.FromToken.Hex()},
+		"toToken":     {req.ToToken.Hex()},
+		"fromAmount":  {req.FromAmount.String()},
+		"fromAddress": {req.FromAddress.Hex()},
+		"slippage":    {strconv.FormatFloat(float64(req.SlippageBps)/10_000, 'f', -1, 64)},
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/quote?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("x-lifi-api-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("swap request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, fmt.Errorf("swap request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(body))
+		}
+		return nil, fmt.Errorf("swap request failed: status %d: %s", resp.StatusCode, apiErr.Message)
+	}
+
+	var parsed swapResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid swap response: %w", err)
+	}
+	txReq := parsed.TransactionRequest
+	if txReq == nil || !common.IsHexAddress(txReq.To) {
+		return nil, fmt.Errorf("swap response has no transaction")
+	}
+	data, err := hexutil.Decode(txReq.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid swap transaction data: %w", err)
+	}
+	value := new(big.Int)
+	if txReq.Value != "" {
+		if value, err = hexutil.DecodeBig(txReq.Value); err != nil {
+			return nil, fmt.Errorf("invalid swap transaction value: %w", err)
+		}
+	}
+
+	tool := parsed.ToolDetails.Name
+	if tool == "" {
+		tool = parsed.Tool
+	}
+	s := &Swap{
+		FromToken:   parsed.Action.FromToken,
+		ToToken:     parsed.Action.ToToken,
+		FromAmount:  parseAmount(parsed.Action.FromAmount),
+		ToAmount:    parseAmount(parsed.Estimate.ToAmount),
+		ToAmountMin: parseAmount(parsed.Estimate.ToAmountMin),
+		Tool:        tool,
+		To:          common.HexToAddress(txReq.To),
+		Data:        data,
+		Value:       value,
+	}
+	if common.IsHexAddress(parsed.Estimate.ApprovalAddress) {
+		s.ApprovalAddress = common.HexToAddress(parsed.Estimate.ApprovalAddress)
+	}
+	// The route must spend what was asked, no more.
+	if s.FromAmount.Cmp(req.FromAmount) != 0 {
+		return nil, fmt.Errorf("swap route spends %s, not the requested %s", s.FromAmount, req.FromAmount)
+	}
+	return s, nil
+}
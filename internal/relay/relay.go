@@ -0,0 +1,246 @@
+// Package relay submits transactions through Gelato's relay, which pays the
+// gas, so a wallet without native gas can still act. Calls are sponsored by
+// the 1Balance behind CLIFI_RELAY_API_KEY; callers reimburse it in tokens.
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultBaseURL is the Gelato relay API root.
+const DefaultBaseURL = "https://api.gelato.digital"
+
+// Multicall3Address is Multicall3, deployed at the same address on every
+// supported chain. Batching through it makes several calls one relay task
+// that succeeds or reverts as a whole.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// ErrNotConfigured means no relay API key is set.
+var ErrNotConfigured = errors.New("gasless relaying needs CLIFI_RELAY_API_KEY (a Gelato relay sponsor key)")
+
+// Client talks to the Gelato relay API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	// FeeRecipient is paid the relay fee in tokens, reimbursing the
+	// sponsor; when unset the sponsor absorbs it.
+	FeeRecipient *common.Address
+}
+
+// New creates a client from CLIFI_RELAY_API_KEY, CLIFI_RELAY_URL and
+// CLIFI_RELAY_FEE_RECIPIENT. An invalid fee recipient is an error rather
+// than a silently free relay.
+func New() (*Client, error) {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+		baseURL:    DefaultBaseURL,
+		apiKey:     strings.TrimSpace(os.Getenv("CLIFI_RELAY_API_KEY")),
+	}
+	if v := strings.TrimSpace(os.Getenv("CLIFI_RELAY_URL")); v != "" {
+		c.baseURL = strings.TrimRight(v, "/")
+	}
+	if v := strings.TrimSpace(os.Getenv("CLIFI_RELAY_FEE_RECIPIENT")); v != "" {
+		if !common.IsHexAddress(v) {
+			return nil, fmt.Errorf("CLIFI_RELAY_FEE_RECIPIENT %q is not an address", v)
+		}
+		addr := common.HexToAddress(v)
+		c.FeeRecipient = &addr
+	}
+	return c, nil
+}
+
+// Enabled reports whether an API key is set.
+func (c *Client) Enabled() bool {
+	return c.apiKey != ""
+}
+
+// EstimateFee quotes what relaying a call of gasLimit gas costs, in base
+// units of token.
+func (c *Client) EstimateFee(ctx context.Context, chainID *big.Int, token common.Address, gasLimit uint64) (*big.Int, error) {
+	q := url.Values{}
+	q.Set("paymentToken", token.Hex())
+	q.Set("gasLimit", strconv.FormatUint(gasLimit, 10))
+	q.Set("isHighPriority", "false")
+	var out struct {
+		EstimatedFee string `json:"estimatedFee"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/oracles/"+chainID.String()+"/estimate?"+q.Encode(), nil, &out); err != nil {
+		return nil, fmt.Errorf("relay fee quote: %w", err)
+	}
+	fee, ok := new(big.Int).SetString(out.EstimatedFee, 10)
+	if !ok || fee.Sign() < 0 {
+		return nil, fmt.Errorf("relay fee quote: invalid fee %q", out.EstimatedFee)
+	}
+	return fee, nil
+}
+
+// SponsoredCall submits a call to target and returns the relay task ID.
+func (c *Client) SponsoredCall(ctx context.Context, chainID *big.Int, target common.Address, data []byte) (string, error) {
+	if !c.Enabled() {
+		return "", ErrNotConfigured
+	}
+	body := map[string]string{
+		"chainId":       chainID.String(),
+		"target":        target.Hex(),
+		"data":          hexutil.Encode(data),
+		"sponsorApiKey": c.apiKey,
+	}
+	var out struct {
+		TaskID string `json:"taskId"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/relays/v2/sponsored-call", body, &out); err != nil {
+		return "", fmt.Errorf("relay submit: %w", err)
+	}
+	if out.TaskID == "" {
+		return "", fmt.Errorf("relay submit: no task ID in response")
+	}
+	return out.TaskID, nil
+}
+
+// Task states Gelato reports
+const (
+	StateSuccess   = "ExecSuccess"
+	StateReverted  = "ExecReverted"
+	StateCancelled = "Cancelled"
+)
+
+// Status is a relay task's progress. TxHash is set once the relayer has
+// sent the transaction.
+type Status struct {
+	State   string
+	TxHash  common.Hash
+	Message string
+}
+
+// Done reports whether the task has reached a final state.
+func (s Status) Done() bool {
+	return s.State == StateSuccess || s.State == StateReverted || s.State == StateCancelled
+}
+
+// TaskStatus looks a relay task up.
+func (c *Client) TaskStatus(ctx context.Context, taskID string) (Status, error) {
+	var out struct {
+		Task struct {
+			TaskState        string `json:"taskState"`
+			TransactionHash  string `json:"transactionHash"`
+			LastCheckMessage string `json:"lastCheckMessage"`
+		} `json:"task"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/tasks/status/"+url.PathEscape(taskID), nil, &out); err != nil {
+		return Status{}, fmt.Errorf("relay task %s: %w", taskID, err)
+	}
+	s := Status{State: out.Task.TaskState, Message: out.Task.LastCheckMessage}
+	if out.Task.TransactionHash != "" {
+		s.TxHash = common.HexToHash(out.Task.TransactionHash)
+	}
+	return s, nil
+}
+
+// Wait polls a task until it is done or ctx ends, returning the last
+// status seen.
+func (c *Client) Wait(ctx context.Context, taskID string, interval time.Duration) (Status, error) {
+	for {
+		s, err := c.TaskStatus(ctx, taskID)
+		if err == nil && s.Done() {
+			return s, nil
+		}
+		select {
+		case <-ctx.Done():
+			return s, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(buf)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s (HTTP %d)", apiErr.Message, resp.StatusCode)
+		}
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Call is one call in a Multicall3 batch.
+type Call struct {
+	Target common.Address
+	Data   []byte
+}
+
+var aggregate3 abi.Method
+
+func init() {
+	parsed, err := abi.JSON(strings.NewReader(`[{"name":"aggregate3","type":"function","stateMutability":"payable",
+		"inputs":[{"name":"calls","type":"tuple[]","components":[
+			{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}]}],
+		"outputs":[{"name":"returnData","type":"tuple[]","components":[
+			{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}]`))
+	if err != nil {
+		panic(err)
+	}
+	aggregate3 = parsed.Methods["aggregate3"]
+}
+
+// Batch encodes Multicall3's aggregate3 for calls, none of which may fail.
+func Batch(calls []Call) ([]byte, error) {
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+	args := make([]call3, len(calls))
+	for i, c := range calls {
+		args[i] = call3{Target: c.Target, CallData: c.Data}
+	}
+	packed, err := aggregate3.Inputs.Pack(args)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, aggregate3.ID...), packed...), nil
+}
@@ -0,0 +1,112 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var usdc = common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+
+func newTestRelay(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	t.Setenv("CLIFI_RELAY_API_KEY", "sponsor-key")
+	t.Setenv("CLIFI_RELAY_URL", srv.URL+"/")
+	t.Setenv("CLIFI_RELAY_FEE_RECIPIENT", "")
+	c, err := New()
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("CLIFI_RELAY_API_KEY", "")
+	t.Setenv("CLIFI_RELAY_FEE_RECIPIENT", "0x00000000000000000000000000000000000000fe")
+	c, err := New()
+	require.NoError(t, err)
+	assert.False(t, c.Enabled())
+	require.NotNil(t, c.FeeRecipient)
+	assert.Equal(t, common.HexToAddress("0xfe"), *c.FeeRecipient)
+
+	_, err = c.SponsoredCall(context.Background(), big.NewInt(8453), usdc, nil)
+	assert.ErrorIs(t, err, ErrNotConfigured)
+
+	t.Setenv("CLIFI_RELAY_FEE_RECIPIENT", "treasury")
+	_, err = New()
+	assert.ErrorContains(t, err, "not an address")
+}
+
+func TestRelay(t *testing.T) {
+	polls := 0
+	c := newTestRelay(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oracles/8453/estimate":
+			assert.Equal(t, usdc.Hex(), r.URL.Query().Get("paymentToken"))
+			assert.Equal(t, "250000", r.URL.Query().Get("gasLimit"))
+			_, _ = w.Write([]byte(`{"estimatedFee":"31250"}`))
+		case "/relays/v2/sponsored-call":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "8453", body["chainId"])
+			assert.Equal(t, "sponsor-key", body["sponsorApiKey"])
+			assert.Equal(t, "0x1234", body["data"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"taskId":"0xtask"}`))
+		case "/tasks/status/0xtask":
+			polls++
+			if polls == 1 {
+				_, _ = w.Write([]byte(`{"task":{"taskState":"ExecPending"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"task":{"taskState":"ExecSuccess","transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000ab"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Unsupported chain"}`))
+		}
+	})
+	ctx := context.Background()
+
+	fee, err := c.EstimateFee(ctx, big.NewInt(8453), usdc, 250_000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(31250), fee.Int64())
+
+	_, err = c.EstimateFee(ctx, big.NewInt(1), usdc, 250_000)
+	assert.ErrorContains(t, err, "Unsupported chain (HTTP 404)")
+
+	task, err := c.SponsoredCall(ctx, big.NewInt(8453), usdc, []byte{0x12, 0x34})
+	require.NoError(t, err)
+	assert.Equal(t, "0xtask", task)
+
+	s, err := c.Wait(ctx, task, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, StateSuccess, s.State)
+	assert.Equal(t, common.HexToHash("0xab"), s.TxHash)
+	assert.Equal(t, 2, polls)
+}
+
+func TestBatch(t *testing.T) {
+	data, err := Batch([]Call{{Target: usdc, Data: []byte{0xaa}}, {Target: usdc, Data: []byte{0xbb, 0xcc}}})
+	require.NoError(t, err)
+	assert.Equal(t, "0x82ad56cb", hexutil.Encode(data[:4]))
+	args, err := aggregate3.Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	calls := args[0].([]struct {
+		Target       common.Address `json:"target"`
+		AllowFailure bool           `json:"allowFailure"`
+		CallData     []byte         `json:"callData"`
+	})
+	require.Len(t, calls, 2)
+	assert.Equal(t, usdc, calls[1].Target)
+	assert.False(t, calls[1].AllowFailure)
+	assert.Equal(t, []byte{0xbb, 0xcc}, calls[1].CallData)
+}
@@ -0,0 +1,284 @@
+// Package screen checks transaction recipients against sanction, scam and
+// drainer lists before clifi signs: local list files, and optionally a
+// Chainalysis-compatible sanctions API.
+package screen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/cosmos"
+)
+
+// Dir holds list files in the data dir; every file in it is loaded.
+const Dir = "screening"
+
+// DefaultCategory is what an entry is flagged as when its list does not
+// say.
+const DefaultCategory = "flagged"
+
+const defaultAPIURL = "https://public.chainalysis.com/api/v1/address/"
+
+// Hit is one reason an address is flagged.
+type Hit struct {
+	Category string // e.g. sanctioned, scam, drainer
+	Note     string
+	Source   string // list file name, or the API host
+}
+
+func (h Hit) String() string {
+	s := h.Category
+	if h.Note != "" {
+		s += " (" + h.Note + ")"
+	}
+	return s + " per " + h.Source
+}
+
+// Result is the outcome of screening one address. Problems lists what
+// could not be checked, such as an unreadable list or an unreachable API;
+// they never flag the address by themselves.
+type Result struct {
+	Hits     []Hit
+	Problems []string
+}
+
+// Screener looks addresses up in the loaded lists and the API. Lists are
+// read once, when it is created.
+type Screener struct {
+	lists    map[string][]Hit
+	sources  []string
+	problems []string
+
+	apiURL     string
+	apiKey     string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string][]Hit
+}
+
+// New loads every file in dataDir/screening and those named in
+// CLIFI_SCREEN_LISTS (comma separated). The API is used when
+// CLIFI_SCREEN_API_KEY is set; CLIFI_SCREEN_API_URL overrides its
+// endpoint.
+func New(dataDir string) *Screener {
+	s := &Screener{
+		lists:      make(map[string][]Hit),
+		apiURL:     defaultAPIURL,
+		apiKey:     strings.TrimSpace(os.Getenv("CLIFI_SCREEN_API_KEY")),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string][]Hit),
+	}
+	if v := strings.TrimSpace(os.Getenv("CLIFI_SCREEN_API_URL")); v != "" {
+		s.apiURL = v
+	}
+
+	var paths []string
+	if dataDir != "" {
+		entries, err := os.ReadDir(filepath.Join(dataDir, Dir))
+		if err != nil && !os.IsNotExist(err) {
+			s.problems = append(s.problems, fmt.Sprintf("list dir: %v", err))
+		}
+		for _, e := range entries {
+			if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				paths = append(paths, filepath.Join(dataDir, Dir, e.Name()))
+			}
+		}
+	}
+	for _, p := range strings.Split(os.Getenv("CLIFI_SCREEN_LISTS"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	for _, p := range paths {
+		if err := s.loadFile(p); err != nil {
+			s.problems = append(s.problems, err.Error())
+		}
+	}
+	return s
+}
+
+func (s *Screener) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("list %s: %w", filepath.Base(path), err)
+	}
+	source := filepath.Base(path)
+	entries, err := Parse(data, source)
+	if err != nil {
+		return fmt.Errorf("list %s: %w", source, err)
+	}
+	s.Add(entries...)
+	s.sources = append(s.sources, source)
+	return nil
+}
+
+// Entry is one flagged address from a list.
+type Entry struct {
+	Address string
+	Hit
+}
+
+// Parse reads a list: either a JSON array of addresses, or one entry per
+// line as "<address> [category] [note...]", with # starting a comment.
+func Parse(data []byte, source string) ([]Entry, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var addrs []string
+		if err := json.Unmarshal(trimmed, &addrs); err != nil {
+			return nil, fmt.Errorf("want a JSON array of addresses: %w", err)
+		}
+		out := make([]Entry, 0, len(addrs))
+		for _, a := range addrs {
+			if a = strings.TrimSpace(a); a != "" {
+				out = append(out, Entry{Address: a, Hit: Hit{Category: DefaultCategory, Source: source}})
+			}
+		}
+		return out, nil
+	}
+
+	var out []Entry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		e := Entry{Address: strings.TrimSuffix(fields[0], ","), Hit: Hit{Category: DefaultCategory, Source: source}}
+		if strings.HasPrefix(e.Address, "0x") && !common.IsHexAddress(e.Address) {
+			return nil, fmt.Errorf("line %d: %q is not an address", n, fields[0])
+		}
+		if len(fields) > 1 {
+			e.Category = strings.ToLower(fields[1])
+		}
+		if len(fields) > 2 {
+			e.Note = strings.Join(fields[2:], " ")
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// Add flags addresses, as if they came from a list.
+func (s *Screener) Add(entries ...Entry) {
+	for _, e := range entries {
+		k := key(e.Address)
+		s.lists[k] = append(s.lists[k], e.Hit)
+	}
+}
+
+// key normalizes an address for lookup. EVM and bech32 addresses are case
+// insensitive; others (e.g. Solana's base58) are not.
+func key(address string) string {
+	address = strings.TrimSpace(address)
+	if common.IsHexAddress(address) {
+		return strings.ToLower(address)
+	}
+	if _, _, err := cosmos.DecodeBech32(address); err == nil {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// Enabled reports whether there is anything to screen against.
+func (s *Screener) Enabled() bool {
+	return len(s.lists) > 0 || s.apiKey != "" || len(s.problems) > 0
+}
+
+// Describe names what addresses are screened against, e.g.
+// "2 lists, sanctions API".
+func (s *Screener) Describe() string {
+	var parts []string
+	switch n := len(s.sources); n {
+	case 0:
+	case 1:
+		parts = append(parts, s.sources[0])
+	default:
+		parts = append(parts, fmt.Sprintf("%d lists", n))
+	}
+	if s.apiKey != "" {
+		parts = append(parts, "sanctions API")
+	}
+	if len(parts) == 0 {
+		return "no lists"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Screen checks address against the lists and, when configured, the API.
+func (s *Screener) Screen(ctx context.Context, address string) Result {
+	k := key(address)
+	r := Result{Hits: append([]Hit(nil), s.lists[k]...), Problems: append([]string(nil), s.problems...)}
+	if s.apiKey == "" {
+		return r
+	}
+
+	s.mu.Lock()
+	cached, ok := s.cache[k]
+	s.mu.Unlock()
+	if !ok {
+		var err error
+		if cached, err = s.checkAPI(ctx, address); err != nil {
+			r.Problems = append(r.Problems, err.Error())
+			return r
+		}
+		s.mu.Lock()
+		s.cache[k] = cached
+		s.mu.Unlock()
+	}
+	r.Hits = append(r.Hits, cached...)
+	sort.SliceStable(r.Hits, func(i, j int) bool { return r.Hits[i].Category < r.Hits[j].Category })
+	return r
+}
+
+// checkAPI asks a Chainalysis-compatible endpoint, which lists the
+// sanctions an address is identified with.
+func (s *Screener) checkAPI(ctx context.Context, address string) ([]Hit, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.apiURL+url.PathEscape(address), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-API-Key", s.apiKey)
+	req.Header.Set("Accept", "application/json")
+	host := req.URL.Host
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sanctions API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sanctions API: unexpected status %s", resp.Status)
+	}
+
+	var body struct {
+		Identifications []struct {
+			Category string `json:"category"`
+			Name     string `json:"name"`
+		} `json:"identifications"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("sanctions API: decode response: %w", err)
+	}
+	hits := make([]Hit, 0, len(body.Identifications))
+	for _, id := range body.Identifications {
+		category := strings.ToLower(id.Category)
+		if category == "" || category == "sanctions" {
+			category = "sanctioned"
+		}
+		hits = append(hits, Hit{Category: category, Note: id.Name, Source: host})
+	}
+	return hits, nil
+}
@@ -0,0 +1,116 @@
+package screen
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bad   = "0x00000000000000000000000000000000000000Aa"
+	clean = "0x00000000000000000000000000000000000000bb"
+)
+
+func TestParse(t *testing.T) {
+	entries, err := Parse([]byte(`# sanctions
+0x00000000000000000000000000000000000000aa sanctioned OFAC SDN  # added 2026-01
+0x00000000000000000000000000000000000000cc drainer
+
+cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a,
+`), "local.txt")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Hit{Category: "sanctioned", Note: "OFAC SDN", Source: "local.txt"}, entries[0].Hit)
+	assert.Equal(t, "drainer", entries[1].Category)
+	assert.Equal(t, DefaultCategory, entries[2].Category)
+	assert.Equal(t, "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a", entries[2].Address)
+
+	entries, err = Parse([]byte(` ["0x00000000000000000000000000000000000000aa", ""]`), "address.json")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, DefaultCategory, entries[0].Category)
+
+	_, err = Parse([]byte("0x1234 scam"), "broken.txt")
+	assert.Error(t, err)
+	_, err = Parse([]byte(`[{"address": "0xaa"}]`), "objects.json")
+	assert.Error(t, err)
+}
+
+func TestScreen_Lists(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, Dir), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, Dir, "scams.txt"), []byte("0x00000000000000000000000000000000000000aa scam fake airdrop\n"), 0600))
+	extra := filepath.Join(t.TempDir(), "sdn.json")
+	require.NoError(t, os.WriteFile(extra, []byte(`["`+bad+`"]`), 0600))
+	t.Setenv("CLIFI_SCREEN_LISTS", extra+", "+filepath.Join(dir, "missing.txt"))
+	t.Setenv("CLIFI_SCREEN_API_KEY", "")
+
+	s := New(dir)
+	assert.True(t, s.Enabled())
+	assert.Equal(t, "2 lists", s.Describe())
+
+	// Lookups ignore EVM address case.
+	r := s.Screen(context.Background(), "0x00000000000000000000000000000000000000AA")
+	require.Len(t, r.Hits, 2)
+	assert.Equal(t, "scam (fake airdrop) per scams.txt", r.Hits[0].String())
+	assert.Equal(t, "sdn.json", r.Hits[1].Source)
+	// The unreadable list is reported, not silently skipped.
+	require.Len(t, r.Problems, 1)
+	assert.Contains(t, r.Problems[0], "missing.txt")
+
+	assert.Empty(t, s.Screen(context.Background(), clean).Hits)
+}
+
+func TestScreen_NothingConfigured(t *testing.T) {
+	t.Setenv("CLIFI_SCREEN_LISTS", "")
+	t.Setenv("CLIFI_SCREEN_API_KEY", "")
+	s := New(t.TempDir())
+	assert.False(t, s.Enabled())
+	assert.Equal(t, "no lists", s.Describe())
+}
+
+func TestScreen_API(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		if r.URL.Path == "/address/"+bad {
+			_, _ = w.Write([]byte(`{"identifications":[{"category":"sanctions","name":"SANCTIONS: OFAC SDN Example"}]}`))
+			return
+		}
+		if r.URL.Path == "/address/"+clean {
+			_, _ = w.Write([]byte(`{"identifications":[]}`))
+			return
+		}
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	t.Setenv("CLIFI_SCREEN_LISTS", "")
+	t.Setenv("CLIFI_SCREEN_API_KEY", "secret")
+	t.Setenv("CLIFI_SCREEN_API_URL", srv.URL+"/address/")
+
+	s := New("")
+	assert.Equal(t, "sanctions API", s.Describe())
+	ctx := context.Background()
+
+	r := s.Screen(ctx, bad)
+	require.Len(t, r.Hits, 1)
+	assert.Equal(t, "sanctioned", r.Hits[0].Category)
+	assert.Equal(t, "SANCTIONS: OFAC SDN Example", r.Hits[0].Note)
+	// Answers are cached.
+	s.Screen(ctx, bad)
+	assert.Equal(t, 1, calls)
+
+	assert.Empty(t, s.Screen(ctx, clean).Hits)
+
+	r = s.Screen(ctx, "0x00000000000000000000000000000000000000dd")
+	assert.Empty(t, r.Hits)
+	require.Len(t, r.Problems, 1)
+	assert.Contains(t, r.Problems[0], "sanctions API")
+}
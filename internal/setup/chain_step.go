@@ -0,0 +1,258 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// chainItem is one row of the chain checklist.
+type chainItem struct {
+	name    string
+	config  *chain.ChainConfig
+	enabled bool
+	rpcURL  string // custom RPC, "" for the defaults
+}
+
+type rpcValidatedMsg struct {
+	chain string
+	url   string
+	err   error
+}
+
+type chainsSavedMsg struct {
+	err error
+}
+
+// loadChainItems lists the built-in chains, mainnets first, with the
+// enabled flags and RPC overrides from an existing chains.yaml.
+func loadChainItems(dataDir string) []chainItem {
+	settings, err := chain.LoadSettings(dataDir)
+	if err != nil {
+		settings = &chain.Settings{}
+	}
+
+	defaults := chain.DefaultChains()
+	items := make([]chainItem, 0, len(defaults))
+	for name, cfg := range defaults {
+		item := chainItem{name: name, config: cfg, enabled: len(settings.Enabled) == 0}
+		for _, e := range settings.Enabled {
+			if e == name {
+				item.enabled = true
+			}
+		}
+		if urls := settings.RPCURLs[name]; len(urls) > 0 {
+			item.rpcURL = urls[0]
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].config.IsTestnet != items[j].config.IsTestnet {
+			return !items[i].config.IsTestnet
+		}
+		return items[i].config.ChainIDInt < items[j].config.ChainIDInt
+	})
+	return items
+}
+
+func newRPCInput() textinput.Model {
+	in := textinput.New()
+	in.Prompt = ""
+	in.Placeholder = "https://... (empty to use the defaults)"
+	in.CharLimit = 300
+	in.Width = 60
+	return in
+}
+
+// enterChains moves on from the wallet step to the optional chain step.
+func (m WizardModel) enterChains() WizardModel {
+	m.chainItems = loadChainItems(m.dataDir)
+	m.chainCursor = 0
+	m.chainError = ""
+	m.step = StepChains
+	return m
+}
+
+func (m WizardModel) updateChains(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.savingChains {
+		return m, nil
+	}
+	switch msg.String() {
+	case "up", "k":
+		if m.chainCursor > 0 {
+			m.chainCursor--
+		}
+	case "down", "j":
+		if m.chainCursor < len(m.chainItems)-1 {
+			m.chainCursor++
+		}
+	case " ", "x":
+		item := &m.chainItems[m.chainCursor]
+		item.enabled = !item.enabled
+		m.chainError = ""
+	case "r":
+		item := m.chainItems[m.chainCursor]
+		m.rpcInput.SetValue(item.rpcURL)
+		m.rpcInput.CursorEnd()
+		m.rpcInput.Focus()
+		m.chainError = ""
+		m.step = StepChainRPC
+	case "s":
+		m.step = StepComplete
+	case "enter":
+		if len(m.enabledChains()) == 0 {
+			m.chainError = "Enable at least one chain"
+			return m, nil
+		}
+		m.savingChains = true
+		return m, m.saveChains()
+	}
+	return m, nil
+}
+
+func (m WizardModel) updateChainRPC(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.validatingRPC {
+		return m, nil
+	}
+	item := &m.chainItems[m.chainCursor]
+	rpcURL := strings.TrimSpace(m.rpcInput.Value())
+	if rpcURL == "" {
+		item.rpcURL = ""
+		m.rpcInput.Blur()
+		m.step = StepChains
+		return m, nil
+	}
+	m.validatingRPC = true
+	m.chainError = ""
+	return m, validateRPC(item.name, rpcURL, item.config)
+}
+
+// validateRPC checks the URL answers eth_chainId for the chain.
+func validateRPC(name, rpcURL string, cfg *chain.ChainConfig) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return rpcValidatedMsg{chain: name, url: rpcURL, err: chain.ValidateRPCURL(ctx, rpcURL, cfg.ChainID)}
+	}
+}
+
+func (m WizardModel) handleRPCValidated(msg rpcValidatedMsg) WizardModel {
+	m.validatingRPC = false
+	if msg.err != nil {
+		m.chainError = msg.err.Error()
+		return m
+	}
+	for i := range m.chainItems {
+		if m.chainItems[i].name == msg.chain {
+			m.chainItems[i].rpcURL = msg.url
+			m.chainItems[i].enabled = true
+		}
+	}
+	m.rpcInput.Blur()
+	m.step = StepChains
+	return m
+}
+
+func (m WizardModel) enabledChains() []string {
+	var names []string
+	for _, item := range m.chainItems {
+		if item.enabled {
+			names = append(names, item.name)
+		}
+	}
+	return names
+}
+
+// chainSettings turns the checklist into chains.yaml settings. Enabling
+// every chain is stored as no list, so chains added in later releases
+// are on by default.
+func (m WizardModel) chainSettings() *chain.Settings {
+	s := &chain.Settings{}
+	if enabled := m.enabledChains(); len(enabled) < len(m.chainItems) {
+		s.Enabled = enabled
+	}
+	for _, item := range m.chainItems {
+		if item.rpcURL != "" {
+			if s.RPCURLs == nil {
+				s.RPCURLs = make(map[string][]string)
+			}
+			s.RPCURLs[item.name] = []string{item.rpcURL}
+		}
+	}
+	return s
+}
+
+func (m WizardModel) saveChains() tea.Cmd {
+	settings := m.chainSettings()
+	dataDir := m.dataDir
+	return func() tea.Msg {
+		return chainsSavedMsg{err: settings.Save(dataDir)}
+	}
+}
+
+func (m WizardModel) viewChains() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	b.WriteString(TitleStyle.Render("  Chains (optional)"))
+	b.WriteString("\n\n")
+	b.WriteString(DimStyle.Render("  Pick the chains to use and add private RPC URLs if you have them.\n\n"))
+
+	for i, item := range m.chainItems {
+		cursor := "  "
+		style := NormalStyle
+		if i == m.chainCursor {
+			cursor = CursorStyle.Render("> ")
+			style = SelectedStyle
+		}
+		check := "[ ]"
+		if item.enabled {
+			check = "[" + Checkmark + "]"
+		}
+		line := fmt.Sprintf("  %s%s %s", cursor, check, style.Render(fmt.Sprintf("%-14s", item.name)))
+		if item.rpcURL != "" {
+			line += DimStyle.Render(" RPC: " + item.rpcURL)
+		} else if item.config.IsTestnet {
+			line += DimStyle.Render(" testnet")
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if m.savingChains {
+		b.WriteString(fmt.Sprintf("\n  %s Saving...\n", m.spinner.View()))
+	} else if m.chainError != "" {
+		b.WriteString(fmt.Sprintf("\n  %s\n", ErrorStyle.Render("✗ "+m.chainError)))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("  Space toggle • r set RPC • Enter save • s skip"))
+	return b.String()
+}
+
+func (m WizardModel) viewChainRPC() string {
+	var b strings.Builder
+	item := m.chainItems[m.chainCursor]
+	b.WriteString("\n")
+	b.WriteString(TitleStyle.Render(fmt.Sprintf("  RPC URL for %s", item.config.Name)))
+	b.WriteString("\n\n")
+	b.WriteString(DimStyle.Render(fmt.Sprintf("  Checked with eth_chainId (expects %d).\n\n", item.config.ChainIDInt)))
+
+	b.WriteString("  ")
+	b.WriteString(m.rpcInput.View())
+	b.WriteString("\n")
+
+	if m.validatingRPC {
+		b.WriteString(fmt.Sprintf("\n  %s Checking RPC...\n", m.spinner.View()))
+	} else if m.chainError != "" {
+		b.WriteString(fmt.Sprintf("\n  %s\n", ErrorStyle.Render("✗ "+m.chainError)))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("  Enter to check • Esc back"))
+	return b.String()
+}
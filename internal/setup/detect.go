@@ -37,24 +37,29 @@ func DetectSetupStatus(dataDir string) (*SetupStatus, error) {
 		}
 	}
 
-	// Check for wallet
-	keystoreDir := filepath.Join(dataDir, "keystore")
-	if entries, err := os.ReadDir(keystoreDir); err == nil {
-		// Filter out directories and hidden files
-		for _, entry := range entries {
-			if !entry.IsDir() && entry.Name()[0] != '.' {
-				status.HasWallet = true
-				break
+	// Check for wallet, in an external keystore too
+	keystoreDirs := []string{filepath.Join(dataDir, "keystore")}
+	if ext, ok, err := wallet.ConfiguredExternalKeystore(); err == nil && ok {
+		keystoreDirs = append(keystoreDirs, ext.Dir)
+	}
+	for _, keystoreDir := range keystoreDirs {
+		if entries, err := os.ReadDir(keystoreDir); err == nil {
+			// Filter out directories and hidden files
+			for _, entry := range entries {
+				if !entry.IsDir() && entry.Name()[0] != '.' {
+					status.HasWallet = true
+					break
+				}
 			}
 		}
 	}
 
-	// Get first wallet address for display if we have one
+	// Get default wallet address for display if we have one
 	if status.HasWallet {
 		km, err := wallet.NewKeystoreManager(dataDir)
 		if err == nil {
-			if accounts := km.ListAccounts(); len(accounts) > 0 {
-				status.WalletAddress = accounts[0].Address.Hex()
+			if def, err := km.DefaultAccount(); err == nil {
+				status.WalletAddress = def.Address.Hex()
 			}
 		}
 	}
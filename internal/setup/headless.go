@@ -0,0 +1,182 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// HeadlessOptions configures setup without the TUI, for CI, containers and
+// dotfile installers. Secrets are read from environment variables or
+// files, never from flags, so they stay out of shell history and ps.
+type HeadlessOptions struct {
+	Provider llm.ProviderID
+	// APIKeyEnv names the variable holding the API key; it defaults to
+	// the provider's usual variable (e.g. ANTHROPIC_API_KEY).
+	APIKeyEnv string
+	// APIKeyFile is read instead of APIKeyEnv when set.
+	APIKeyFile string
+	// ValidateKey makes a test request before saving the key.
+	ValidateKey bool
+
+	// CreateWallet creates a wallet unless the keystore already has one,
+	// so rerunning setup is harmless.
+	CreateWallet bool
+	// ImportWalletEnv names a variable holding a private key or seed
+	// phrase to import.
+	ImportWalletEnv string
+	// WalletPasswordFile and WalletPasswordEnv supply the keystore
+	// password; the file wins when both are set.
+	WalletPasswordFile string
+	WalletPasswordEnv  string
+}
+
+// RunHeadless configures the provider and, optionally, a wallet, writing
+// progress to out.
+func RunHeadless(ctx context.Context, dataDir string, opts HeadlessOptions, out io.Writer) (*SetupResult, error) {
+	if !slices.Contains(llm.AllProviderIDs(), opts.Provider) {
+		return nil, fmt.Errorf("unknown provider %q", opts.Provider)
+	}
+	if opts.CreateWallet && opts.ImportWalletEnv != "" {
+		return nil, errors.New("--create-wallet and --import-wallet-env are mutually exclusive")
+	}
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	apiKey, source, err := headlessAPIKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.ValidateKey {
+		validateCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := testProviderKey(validateCtx, opts.Provider, apiKey)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("validate %s key: %w", opts.Provider, err)
+		}
+	}
+
+	authManager, err := auth.NewManager(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auth manager: %w", err)
+	}
+	if err := authManager.SetAPIKey(opts.Provider, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+	if err := authManager.SetDefaultProvider(opts.Provider); err != nil {
+		return nil, fmt.Errorf("failed to set default provider: %w", err)
+	}
+	fmt.Fprintf(out, "Provider: %s (key from %s)\n", opts.Provider, source)
+
+	result := &SetupResult{ProviderID: opts.Provider}
+	if !opts.CreateWallet && opts.ImportWalletEnv == "" {
+		return result, nil
+	}
+
+	km, err := wallet.NewKeystoreManager(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize keystore: %w", err)
+	}
+	if opts.CreateWallet {
+		if def, err := km.DefaultAccount(); err == nil {
+			fmt.Fprintf(out, "Wallet: %s (existing)\n", def.Address.Hex())
+			result.WalletAddress = def.Address.Hex()
+			return result, nil
+		}
+	}
+
+	password, err := headlessPassword(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var account accounts.Account
+	if opts.CreateWallet {
+		account, err = km.CreateAccount(password)
+	} else {
+		secret := strings.TrimSpace(os.Getenv(opts.ImportWalletEnv))
+		if secret == "" {
+			return nil, fmt.Errorf("%s is not set", opts.ImportWalletEnv)
+		}
+		if wallet.IsMnemonic(secret) {
+			account, err = km.ImportMnemonic(secret, accounts.DefaultBaseDerivationPath, password)
+		} else {
+			account, err = km.ImportKey(secret, password)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("wallet setup failed: %w", err)
+	}
+
+	result.WalletCreated = true
+	result.WalletAddress = account.Address.Hex()
+	fmt.Fprintf(out, "Wallet: %s\n", account.Address.Hex())
+	return result, nil
+}
+
+// headlessAPIKey reads the API key and says where it came from.
+func headlessAPIKey(opts HeadlessOptions) (key, source string, err error) {
+	if opts.APIKeyFile != "" {
+		key, err := readSecretFile(opts.APIKeyFile)
+		if err != nil {
+			return "", "", fmt.Errorf("read API key: %w", err)
+		}
+		return key, opts.APIKeyFile, nil
+	}
+
+	envVar := opts.APIKeyEnv
+	if envVar == "" {
+		envVar = llm.EnvVarForProvider(opts.Provider)
+	}
+	if envVar == "" {
+		return "", "", fmt.Errorf("no default API key variable for %s; pass --api-key-env or --api-key-file", opts.Provider)
+	}
+	key = strings.TrimSpace(os.Getenv(envVar))
+	if key == "" {
+		return "", "", fmt.Errorf("%s is not set", envVar)
+	}
+	return key, envVar, nil
+}
+
+// headlessPassword reads the wallet password, enforcing the wizard's
+// minimum length.
+func headlessPassword(opts HeadlessOptions) (string, error) {
+	var password string
+	switch {
+	case opts.WalletPasswordFile != "":
+		p, err := readSecretFile(opts.WalletPasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("read wallet password: %w", err)
+		}
+		password = p
+	case opts.WalletPasswordEnv != "":
+		password = os.Getenv(opts.WalletPasswordEnv)
+	default:
+		return "", errors.New("wallet password required: pass --wallet-password-file or --wallet-password-env")
+	}
+	if len(password) < 8 {
+		return "", errors.New("wallet password must be at least 8 characters")
+	}
+	return password, nil
+}
+
+// readSecretFile reads a one-line secret, dropping the trailing newline
+// editors and `echo` add.
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,62 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/auth"
+	"github.com/yolodolo42/clifi/internal/llm"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func TestRunHeadless(t *testing.T) {
+	t.Run("saves key from env and imports wallet", func(t *testing.T) {
+		dir := testutil.TempDir(t)
+		t.Setenv("CI_OPENAI_KEY", "sk-test-123")
+		t.Setenv("CI_WALLET", "test test test test test test test test test test test junk")
+		pwFile := filepath.Join(dir, "pw")
+		require.NoError(t, os.WriteFile(pwFile, []byte("correct horse\n"), 0600))
+
+		var out bytes.Buffer
+		result, err := RunHeadless(context.Background(), dir, HeadlessOptions{
+			Provider:           llm.ProviderOpenAI,
+			APIKeyEnv:          "CI_OPENAI_KEY",
+			ImportWalletEnv:    "CI_WALLET",
+			WalletPasswordFile: pwFile,
+		}, &out)
+		require.NoError(t, err)
+		assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", result.WalletAddress)
+		assert.Contains(t, out.String(), "key from CI_OPENAI_KEY")
+
+		m, err := auth.NewManager(dir)
+		require.NoError(t, err)
+		assert.Equal(t, llm.ProviderOpenAI, m.GetDefaultProvider())
+		assert.True(t, m.HasCredential(llm.ProviderOpenAI))
+
+		status, err := DetectSetupStatus(dir)
+		require.NoError(t, err)
+		assert.True(t, status.HasWallet)
+	})
+
+	t.Run("rejects missing secrets", func(t *testing.T) {
+		dir := testutil.TempDir(t)
+		_, err := RunHeadless(context.Background(), dir, HeadlessOptions{Provider: llm.ProviderOpenAI, APIKeyEnv: "CI_UNSET_KEY"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "CI_UNSET_KEY is not set")
+
+		t.Setenv("CI_OPENAI_KEY", "sk-test-123")
+		_, err = RunHeadless(context.Background(), dir, HeadlessOptions{Provider: llm.ProviderOpenAI, APIKeyEnv: "CI_OPENAI_KEY", CreateWallet: true}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "wallet password required")
+
+		t.Setenv("CI_PW", "short")
+		_, err = RunHeadless(context.Background(), dir, HeadlessOptions{Provider: llm.ProviderOpenAI, APIKeyEnv: "CI_OPENAI_KEY", CreateWallet: true, WalletPasswordEnv: "CI_PW"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "at least 8 characters")
+
+		_, err = RunHeadless(context.Background(), dir, HeadlessOptions{Provider: "nope"}, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "unknown provider")
+	})
+}
@@ -13,56 +13,63 @@ import (
 // validateKey validates the API key by making a test API call
 func (m WizardModel) validateKey() tea.Cmd {
 	apiKey := m.apiKeyInput.Value()
+	providerID := m.selectedProvider
 
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		var provider llm.Provider
-		var err error
-
-		switch m.selectedProvider {
-		case llm.ProviderAnthropic:
-			provider, err = llm.NewAnthropicProvider(apiKey, "")
-		case llm.ProviderOpenAI:
-			provider, err = llm.NewOpenAIProvider(apiKey, "", "")
-		case llm.ProviderGemini:
-			provider, err = llm.NewGeminiProvider(ctx, apiKey, "")
-		case llm.ProviderVenice:
-			provider, err = llm.NewVeniceProvider(apiKey, "")
-		case llm.ProviderCopilot:
-			provider, err = llm.NewCopilotProvider(apiKey, "")
-		case llm.ProviderOpenRouter:
-			provider, err = llm.NewOpenRouterProvider(apiKey, "")
-		default:
-			return keyValidatedMsg{success: false, err: fmt.Errorf("unknown provider")}
-		}
-
-		if err != nil {
+		if err := testProviderKey(ctx, providerID, apiKey); err != nil {
 			return keyValidatedMsg{success: false, err: err}
 		}
+		return keyValidatedMsg{success: true}
+	}
+}
 
-		// Make a minimal test request
-		testReq := &llm.ChatRequest{
-			SystemPrompt: "You are a test assistant.",
-			Messages: []llm.Message{
-				{Role: "user", Content: "Say 'ok' and nothing else."},
-			},
-			MaxTokens: 10,
-		}
+// testProviderKey makes a minimal chat request with apiKey.
+func testProviderKey(ctx context.Context, providerID llm.ProviderID, apiKey string) error {
+	var provider llm.Provider
+	var err error
+
+	switch providerID {
+	case llm.ProviderAnthropic:
+		provider, err = llm.NewAnthropicProvider(apiKey, "")
+	case llm.ProviderOpenAI:
+		provider, err = llm.NewOpenAIProvider(apiKey, "", "")
+	case llm.ProviderGemini:
+		provider, err = llm.NewGeminiProvider(ctx, apiKey, "")
+	case llm.ProviderVenice:
+		provider, err = llm.NewVeniceProvider(apiKey, "")
+	case llm.ProviderCopilot:
+		provider, err = llm.NewCopilotProvider(apiKey, "")
+	case llm.ProviderOpenRouter:
+		provider, err = llm.NewOpenRouterProvider(apiKey, "")
+	default:
+		return fmt.Errorf("unknown provider")
+	}
 
-		_, err = provider.Chat(ctx, testReq)
-		if err != nil {
-			return keyValidatedMsg{success: false, err: fmt.Errorf("API test failed: %w", err)}
-		}
+	if err != nil {
+		return err
+	}
 
-		// Close Gemini client if applicable
-		if gemini, ok := provider.(*llm.GeminiProvider); ok {
-			_ = gemini.Close()
-		}
+	// Close Gemini client if applicable
+	if gemini, ok := provider.(*llm.GeminiProvider); ok {
+		defer func() { _ = gemini.Close() }()
+	}
 
-		return keyValidatedMsg{success: true}
+	// Make a minimal test request
+	testReq := &llm.ChatRequest{
+		SystemPrompt: "You are a test assistant.",
+		Messages: []llm.Message{
+			{Role: "user", Content: "Say 'ok' and nothing else."},
+		},
+		MaxTokens: 10,
 	}
+
+	if _, err := provider.Chat(ctx, testReq); err != nil {
+		return fmt.Errorf("API test failed: %w", err)
+	}
+	return nil
 }
 
 // saveProviderKey saves the API key to auth.json
@@ -85,8 +92,21 @@ func (m WizardModel) saveProviderKey() error {
 	return nil
 }
 
-// startOAuthFlow initiates the OAuth flow for the selected provider
+// startOAuthFlow initiates the OAuth flow for the selected provider. A
+// device login first returns the code to show, then finishDeviceFlow waits.
 func (m WizardModel) startOAuthFlow() tea.Cmd {
+	if c := auth.GetOAuthConfig(m.selectedProvider); c != nil && c.DeviceCodeURL != "" {
+		providerID := m.selectedProvider
+		return func() tea.Msg {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			code, err := auth.StartDeviceFlow(ctx, *c)
+			if err != nil {
+				return deviceCodeMsg{err: fmt.Errorf("%s login failed: %w", providerID, err)}
+			}
+			return deviceCodeMsg{code: code}
+		}
+	}
 	return func() tea.Msg {
 		authManager, err := auth.NewManager(m.dataDir)
 		if err != nil {
@@ -108,3 +128,25 @@ func (m WizardModel) startOAuthFlow() tea.Cmd {
 		return oauthCompleteMsg{success: true}
 	}
 }
+
+// finishDeviceFlow waits for the user to approve the device code, then
+// stores the token and makes the provider the default.
+func (m WizardModel) finishDeviceFlow(code *auth.DeviceCode) tea.Cmd {
+	return func() tea.Msg {
+		authManager, err := auth.NewManager(m.dataDir)
+		if err != nil {
+			return oauthCompleteMsg{success: false, err: fmt.Errorf("failed to create auth manager: %w", err)}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+		defer cancel()
+
+		if err := authManager.FinishDeviceFlow(ctx, m.selectedProvider, code); err != nil {
+			return oauthCompleteMsg{success: false, err: err}
+		}
+		if err := authManager.SetDefaultProvider(m.selectedProvider); err != nil {
+			return oauthCompleteMsg{success: false, err: fmt.Errorf("failed to set default provider: %w", err)}
+		}
+		return oauthCompleteMsg{success: true}
+	}
+}
@@ -1,69 +1,99 @@
 package setup
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yolodolo42/clifi/internal/ui"
+)
 
+// Styles follow the active ui theme; RefreshStyles rebuilds them after
+// ui.ApplyTheme.
 var (
-	// Colors
-	primaryColor   = lipgloss.Color("205") // Pink/magenta
-	successColor   = lipgloss.Color("35")  // Green
-	dimColor       = lipgloss.Color("241") // Gray
-	accentColor    = lipgloss.Color("39")  // Blue
-	borderColor    = lipgloss.Color("62")  // Purple
-	highlightColor = lipgloss.Color("212") // Light pink
-
 	// Box style for welcome/complete screens
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
-			Padding(1, 2)
+	BoxStyle lipgloss.Style
 
 	// Title style
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor)
+	TitleStyle lipgloss.Style
 
 	// Subtitle/description
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
+	SubtitleStyle lipgloss.Style
 
 	// Success messages
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(successColor)
+	SuccessStyle lipgloss.Style
 
 	// Dim text
-	DimStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
+	DimStyle lipgloss.Style
 
 	// Step indicator (e.g., "Step 1 of 2")
-	StepStyle = lipgloss.NewStyle().
-			Foreground(accentColor).
-			Bold(true)
+	StepStyle lipgloss.Style
 
 	// Selected item in list
-	SelectedStyle = lipgloss.NewStyle().
-			Foreground(highlightColor).
-			Bold(true)
+	SelectedStyle lipgloss.Style
 
 	// Normal item in list
-	NormalStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252"))
+	NormalStyle lipgloss.Style
 
 	// Cursor
-	CursorStyle = lipgloss.NewStyle().
-			Foreground(primaryColor)
+	CursorStyle lipgloss.Style
 
 	// Error text
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")) // Red
+	ErrorStyle lipgloss.Style
 
 	// Help text at bottom
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
+	HelpStyle lipgloss.Style
 
 	// Checkmark
-	Checkmark = SuccessStyle.Render("✓")
+	Checkmark string
 
 	// Spinner style
-	SpinnerStyle = lipgloss.NewStyle().
-			Foreground(primaryColor)
+	SpinnerStyle lipgloss.Style
 )
+
+func init() {
+	RefreshStyles()
+}
+
+// RefreshStyles rebuilds the wizard styles from the active ui theme.
+func RefreshStyles() {
+	BoxStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorBorder).
+		Padding(1, 2)
+
+	TitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ui.ColorPrimary)
+
+	SubtitleStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorDim)
+
+	SuccessStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorSuccess)
+
+	DimStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorDim)
+
+	StepStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorAccent).
+		Bold(true)
+
+	SelectedStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorHighlight).
+		Bold(true)
+
+	NormalStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorText)
+
+	CursorStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorPrimary)
+
+	ErrorStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorError)
+
+	HelpStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorDim)
+
+	Checkmark = SuccessStyle.Render("✓")
+
+	SpinnerStyle = lipgloss.NewStyle().
+		Foreground(ui.ColorPrimary)
+}
@@ -1,13 +1,21 @@
 package setup
 
 import (
+	"crypto/ecdsa"
+	"errors"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/yolodolo42/clifi/internal/wallet"
 )
 
-// createWallet creates a new wallet with the entered password
+// createWallet creates a new wallet with the entered password, or
+// encrypts the imported key when there is one.
 func (m WizardModel) createWallet() tea.Cmd {
 	password := m.passwordInput.Value()
+	secret := m.importSecret
 
 	return func() tea.Msg {
 		km, err := wallet.NewKeystoreManager(m.dataDir)
@@ -15,7 +23,15 @@ func (m WizardModel) createWallet() tea.Cmd {
 			return walletCreatedMsg{err: err}
 		}
 
-		account, err := km.CreateAccount(password)
+		var account accounts.Account
+		switch {
+		case secret == "":
+			account, err = km.CreateAccount(password)
+		case wallet.IsMnemonic(secret):
+			account, err = km.ImportMnemonic(secret, accounts.DefaultBaseDerivationPath, password)
+		default:
+			account, err = km.ImportKey(secret, password)
+		}
 		if err != nil {
 			return walletCreatedMsg{err: err}
 		}
@@ -23,3 +39,45 @@ func (m WizardModel) createWallet() tea.Cmd {
 		return walletCreatedMsg{address: account.Address.Hex()}
 	}
 }
+
+// updateWalletImport checks the pasted key or phrase and, when it is
+// valid, moves on to the password with the derived address shown.
+func (m WizardModel) updateWalletImport() (tea.Model, tea.Cmd) {
+	secret := strings.TrimSpace(m.importInput.Value())
+	if secret == "" {
+		m.importError = "Private key or seed phrase is required"
+		return m, nil
+	}
+
+	key, err := parseImportSecret(secret)
+	if err != nil {
+		m.importError = err.Error()
+		return m, nil
+	}
+	m.importAddress = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	key.D.SetInt64(0)
+
+	m.importSecret = secret
+	m.importError = ""
+	m.importInput.Blur()
+	m.passwordStep = 0
+	m.passwordInput.Focus()
+	m.step = StepWalletPassword
+	return m, nil
+}
+
+// parseImportSecret reads a hex private key or a BIP-39 seed phrase.
+func parseImportSecret(secret string) (*ecdsa.PrivateKey, error) {
+	if wallet.IsMnemonic(secret) {
+		key, err := wallet.KeyFromMnemonic(secret, accounts.DefaultBaseDerivationPath)
+		if errors.Is(err, wallet.ErrInvalidMnemonic) {
+			return nil, errors.New("invalid seed phrase: check the words and their order")
+		}
+		return key, err
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(secret, "0x"))
+	if err != nil {
+		return nil, errors.New("invalid private key: expected 64 hex characters")
+	}
+	return key, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/browser"
 	"github.com/yolodolo42/clifi/internal/auth"
 	"github.com/yolodolo42/clifi/internal/llm"
 	"github.com/yolodolo42/clifi/internal/ui"
@@ -25,11 +26,14 @@ const (
 	StepProviderKey
 	StepOAuthWaiting
 	StepWalletChoice
+	StepWalletImport
 	StepWalletPassword
+	StepChains
+	StepChainRPC
 	StepComplete
 )
 
-const totalSteps = 3 // Provider, Wallet, Complete
+const totalSteps = 4 // Provider, Wallet, Chains, Complete
 
 // SetupResult contains the result of the setup wizard
 type SetupResult struct {
@@ -60,6 +64,7 @@ type WizardModel struct {
 	authSelector ui.Selector
 	selectedAuth string // "api" or "oauth"
 	oauthError   string
+	deviceCode   *auth.DeviceCode // shown while a device login waits
 
 	// Wallet step
 	walletChoices  []string
@@ -71,6 +76,20 @@ type WizardModel struct {
 	walletCreated  bool
 	walletAddress  string
 
+	// Import step: the pasted key or phrase, and the address it derives
+	importInput   textinput.Model
+	importError   string
+	importSecret  string
+	importAddress string
+
+	// Chain step
+	chainItems    []chainItem
+	chainCursor   int
+	chainError    string
+	rpcInput      textinput.Model
+	validatingRPC bool
+	savingChains  bool
+
 	// UI
 	spinner  spinner.Model
 	progress progress.Model
@@ -103,6 +122,11 @@ type oauthCompleteMsg struct {
 	err     error
 }
 
+type deviceCodeMsg struct {
+	code *auth.DeviceCode
+	err  error
+}
+
 type walletCreatedMsg struct {
 	address string
 	err     error
@@ -141,7 +165,7 @@ func walletSelectorItems(choices []string) []ui.SelectorItem {
 	for i, c := range choices {
 		desc := ""
 		if i == 1 {
-			desc = "disabled"
+			desc = "private key or seed phrase"
 		}
 		items = append(items, ui.SelectorItem{
 			ID:          fmt.Sprintf("%d", i),
@@ -191,6 +215,14 @@ func NewWizard(dataDir string) *WizardModel {
 	confirmInput.CharLimit = 100
 	confirmInput.Width = 40
 
+	importInput := textinput.New()
+	importInput.Prompt = ""
+	importInput.Placeholder = "Private key (0x...) or 12/24-word seed phrase"
+	importInput.EchoMode = textinput.EchoPassword
+	importInput.EchoCharacter = '•'
+	importInput.CharLimit = 300
+	importInput.Width = 50
+
 	providers := []providerItem{
 		{id: llm.ProviderAnthropic, name: "Anthropic (Claude)", description: "Best reasoning & tool use", recommended: true},
 		{id: llm.ProviderOpenAI, name: "OpenAI (GPT-4)", description: "Fast responses, widely used"},
@@ -202,7 +234,7 @@ func NewWizard(dataDir string) *WizardModel {
 
 	walletChoices := []string{
 		"Create a new wallet",
-		"Import existing wallet (coming soon)",
+		"Import existing wallet",
 		"Continue without wallet",
 	}
 
@@ -222,6 +254,8 @@ func NewWizard(dataDir string) *WizardModel {
 		apiKeyInput:      apiInput,
 		passwordInput:    passInput,
 		confirmInput:     confirmInput,
+		importInput:      importInput,
+		rpcInput:         newRPCInput(),
 	}
 
 	// Check for environment keys
@@ -313,6 +347,7 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case StepOAuthWaiting:
 			if msg.Type == tea.KeyEsc {
 				m.oauthError = ""
+				m.deviceCode = nil
 				if !m.providerHasAuthChoice() {
 					m.step = StepProviderSelect
 				} else {
@@ -326,6 +361,19 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case StepWalletChoice:
 			return m.updateWalletChoice(msg)
 
+		case StepWalletImport:
+			if msg.Type == tea.KeyEsc {
+				m.importInput.Blur()
+				m.importInput.Reset()
+				m.importError = ""
+				m.step = StepWalletChoice
+				return m, nil
+			}
+			if msg.Type == tea.KeyEnter {
+				return m.updateWalletImport()
+			}
+			// Fall through to let input update happen
+
 		case StepWalletPassword:
 			if msg.Type == tea.KeyEsc {
 				m.passwordStep = 0
@@ -333,6 +381,12 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.passwordInput.Reset()
 				m.confirmInput.Reset()
 				m.step = StepWalletChoice
+				if m.importSecret != "" {
+					// Back to the key so it can be corrected.
+					m.importSecret, m.importAddress = "", ""
+					m.importInput.Focus()
+					m.step = StepWalletImport
+				}
 				return m, nil
 			}
 			if msg.Type == tea.KeyEnter {
@@ -340,6 +394,25 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			// Fall through to let input update happen
 
+		case StepChains:
+			if msg.Type == tea.KeyEsc {
+				m.step = StepComplete
+				return m, nil
+			}
+			return m.updateChains(msg)
+
+		case StepChainRPC:
+			if msg.Type == tea.KeyEsc && !m.validatingRPC {
+				m.rpcInput.Blur()
+				m.chainError = ""
+				m.step = StepChains
+				return m, nil
+			}
+			if msg.Type == tea.KeyEnter {
+				return m.updateChainRPC(msg)
+			}
+			// Fall through to let input update happen
+
 		case StepComplete:
 			if msg.Type == tea.KeyEnter {
 				m.result = &SetupResult{
@@ -383,11 +456,36 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.walletCreated = true
 			m.walletAddress = msg.address
+			m.importSecret = ""
+			m.importInput.Reset()
+			m = m.enterChains()
+		}
+		return m, nil
+
+	case rpcValidatedMsg:
+		return m.handleRPCValidated(msg), nil
+
+	case chainsSavedMsg:
+		m.savingChains = false
+		if msg.err != nil {
+			m.chainError = fmt.Sprintf("Failed to save: %v", msg.err)
+		} else {
 			m.step = StepComplete
 		}
 		return m, nil
 
+	case deviceCodeMsg:
+		if msg.err != nil {
+			m.oauthError = msg.err.Error()
+			m.step = StepAuthMethod
+			return m, nil
+		}
+		m.deviceCode = msg.code
+		_ = browser.OpenURL(msg.code.VerificationURI)
+		return m, m.finishDeviceFlow(msg.code)
+
 	case oauthCompleteMsg:
+		m.deviceCode = nil
 		if msg.success {
 			m.step = StepWalletChoice
 		} else {
@@ -404,6 +502,18 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	if m.step == StepChainRPC && !m.validatingRPC {
+		var cmd tea.Cmd
+		m.rpcInput, cmd = m.rpcInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.step == StepWalletImport {
+		var cmd tea.Cmd
+		m.importInput, cmd = m.importInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	if m.step == StepWalletPassword {
 		var cmd tea.Cmd
 		if m.passwordStep == 0 {
@@ -598,13 +708,14 @@ func (m WizardModel) updateWalletChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.step = StepWalletPassword
 		m.passwordStep = 0
 		return m, nil
-	case "1": // import disabled
-		m.passwordError = "Import wallet coming soon. Choose another option."
-		m.walletSelector = ui.NewSelector("Set up wallet (optional)", walletSelectorItems(m.walletChoices))
+	case "1": // import
+		m.passwordError = ""
+		m.importError = ""
+		m.importInput.Focus()
+		m.step = StepWalletImport
 		return m, nil
 	default: // skip
-		m.step = StepComplete
-		return m, nil
+		return m.enterChains(), nil
 	}
 }
 
@@ -663,8 +774,14 @@ func (m WizardModel) View() string {
 		b.WriteString(m.viewOAuthWaiting())
 	case StepWalletChoice:
 		b.WriteString(m.viewWalletChoice())
+	case StepWalletImport:
+		b.WriteString(m.viewWalletImport())
 	case StepWalletPassword:
 		b.WriteString(m.viewWalletPassword())
+	case StepChains:
+		b.WriteString(m.viewChains())
+	case StepChainRPC:
+		b.WriteString(m.viewChainRPC())
 	case StepComplete:
 		b.WriteString(m.viewComplete())
 	}
@@ -677,16 +794,18 @@ func (m WizardModel) renderProgress() string {
 	switch m.step {
 	case StepProviderSelect, StepAuthMethod, StepProviderKey, StepOAuthWaiting:
 		currentStep = 1
-	case StepWalletChoice, StepWalletPassword:
+	case StepWalletChoice, StepWalletImport, StepWalletPassword:
 		currentStep = 2
-	case StepComplete:
+	case StepChains, StepChainRPC:
 		currentStep = 3
+	case StepComplete:
+		currentStep = 4
 	}
 
 	percent := float64(currentStep) / float64(totalSteps)
 	bar := m.progress.ViewAs(percent)
 
-	labels := "  Provider      Wallet       Ready"
+	labels := "  Provider   Wallet   Chains   Ready"
 	return fmt.Sprintf("  %s\n%s", bar, DimStyle.Render(labels))
 }
 
@@ -744,6 +863,14 @@ func (m WizardModel) viewOAuthWaiting() string {
 
 	b.WriteString(TitleStyle.Render(fmt.Sprintf("  Connecting to %s", providerName)))
 	b.WriteString("\n\n")
+	if c := m.deviceCode; c != nil {
+		b.WriteString(fmt.Sprintf("  Enter this code at %s:\n\n", c.VerificationURI))
+		b.WriteString("      " + TitleStyle.Render(c.UserCode) + "\n\n")
+		b.WriteString(fmt.Sprintf("  %s Waiting for approval...\n", m.spinner.View()))
+		b.WriteString("\n")
+		b.WriteString(HelpStyle.Render("  Esc to cancel"))
+		return b.String()
+	}
 	b.WriteString(fmt.Sprintf("  %s Opening browser for authentication...\n\n", m.spinner.View()))
 	b.WriteString(DimStyle.Render("  Complete the login in your browser.\n"))
 	b.WriteString(DimStyle.Render("  Waiting for callback... (timeout: 5 minutes)\n"))
@@ -796,12 +923,40 @@ func (m WizardModel) viewWalletChoice() string {
 	return b.String()
 }
 
-func (m WizardModel) viewWalletPassword() string {
+func (m WizardModel) viewWalletImport() string {
 	var b strings.Builder
 	b.WriteString("\n")
-	b.WriteString(TitleStyle.Render("  Create Wallet Password"))
+	b.WriteString(TitleStyle.Render("  Import Existing Wallet"))
 	b.WriteString("\n\n")
 
+	b.WriteString(DimStyle.Render("  Paste a hex private key or a BIP-39 seed phrase.\n"))
+	b.WriteString(DimStyle.Render("  Seed phrases use the first account (m/44'/60'/0'/0/0).\n\n"))
+
+	b.WriteString("  ")
+	b.WriteString(m.importInput.View())
+	b.WriteString("\n")
+
+	if m.importError != "" {
+		b.WriteString(fmt.Sprintf("\n  %s\n", ErrorStyle.Render("✗ "+m.importError)))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("  Enter to continue • Esc back"))
+	return b.String()
+}
+
+func (m WizardModel) viewWalletPassword() string {
+	var b strings.Builder
+	b.WriteString("\n")
+	if m.importAddress != "" {
+		b.WriteString(TitleStyle.Render("  Encrypt Imported Wallet"))
+		b.WriteString("\n\n")
+		b.WriteString(fmt.Sprintf("  Address: %s\n\n", SuccessStyle.Render(m.importAddress)))
+	} else {
+		b.WriteString(TitleStyle.Render("  Create Wallet Password"))
+		b.WriteString("\n\n")
+	}
+
 	b.WriteString(DimStyle.Render("  This encrypts your wallet on disk.\n"))
 	b.WriteString(DimStyle.Render("  Requirements: 8+ characters\n\n"))
 
@@ -917,7 +1072,9 @@ func PrintEnvInstructions() {
 	fmt.Println("  VENICE_API_KEY=...")
 	fmt.Println("  OPENROUTER_API_KEY=...")
 	fmt.Println("")
-	fmt.Println("Or run clifi interactively to complete guided setup.")
+	fmt.Println("Or run clifi interactively to complete guided setup, or configure it")
+	fmt.Println("non-interactively with e.g.:")
+	fmt.Println("  clifi setup --provider openai --api-key-env OPENAI_API_KEY")
 }
 
 // IsInteractive returns true if running in a terminal
@@ -3,7 +3,9 @@ package setup
 import (
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewWizard_InputPrompts(t *testing.T) {
@@ -33,3 +35,71 @@ func TestNewWizard_Initialization(t *testing.T) {
 		assert.Greater(t, len(m.walletChoices), 0, "should have wallet choices")
 	})
 }
+
+func TestWizard_ImportWallet(t *testing.T) {
+	start := func() WizardModel {
+		m := NewWizard("")
+		m.step = StepWalletChoice
+		m.walletSelector.Update(tea.KeyMsg{Type: tea.KeyDown})
+		updated, _ := m.updateWalletChoice(tea.KeyMsg{Type: tea.KeyEnter})
+		require.Equal(t, StepWalletImport, updated.(WizardModel).step)
+		return updated.(WizardModel)
+	}
+
+	t.Run("seed phrase shows derived address", func(t *testing.T) {
+		m := start()
+		m.importInput.SetValue("test test test test test test test test test test test junk")
+		updated, _ := m.updateWalletImport()
+		m = updated.(WizardModel)
+
+		assert.Equal(t, StepWalletPassword, m.step)
+		assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", m.importAddress)
+		assert.Contains(t, m.viewWalletPassword(), m.importAddress)
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		m := start()
+		m.importInput.SetValue("0x59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d")
+		updated, _ := m.updateWalletImport()
+		assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", updated.(WizardModel).importAddress)
+	})
+
+	t.Run("invalid input stays on the step", func(t *testing.T) {
+		m := start()
+		m.importInput.SetValue("test test test test test test test test test test test test")
+		updated, _ := m.updateWalletImport()
+		m = updated.(WizardModel)
+		assert.Equal(t, StepWalletImport, m.step)
+		assert.Contains(t, m.importError, "seed phrase")
+
+		m.importInput.SetValue("0x1234")
+		updated, _ = m.updateWalletImport()
+		assert.Contains(t, updated.(WizardModel).importError, "private key")
+	})
+}
+
+func TestWizard_ChainStep(t *testing.T) {
+	dir := t.TempDir()
+	m := NewWizard(dir).enterChains()
+	require.Equal(t, StepChains, m.step)
+	require.NotEmpty(t, m.chainItems)
+	assert.Equal(t, "ethereum", m.chainItems[0].name, "mainnets first, by chain ID")
+
+	// Untick the first chain and give the second a private RPC.
+	updated, _ := m.updateChains(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = updated.(WizardModel)
+	m.chainCursor = 1
+	updated, _ = m.updateChains(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(WizardModel)
+	require.Equal(t, StepChainRPC, m.step)
+	m = m.handleRPCValidated(rpcValidatedMsg{chain: m.chainItems[1].name, url: "https://rpc.example"})
+	assert.Equal(t, StepChains, m.step)
+
+	s := m.chainSettings()
+	assert.NotContains(t, s.Enabled, "ethereum")
+	assert.Len(t, s.Enabled, len(m.chainItems)-1)
+	assert.Equal(t, []string{"https://rpc.example"}, s.RPCURLs[m.chainItems[1].name])
+
+	failed := m.handleRPCValidated(rpcValidatedMsg{chain: "base", url: "https://bad", err: assert.AnError})
+	assert.Equal(t, assert.AnError.Error(), failed.chainError)
+}
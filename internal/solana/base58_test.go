@@ -0,0 +1,26 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase58(t *testing.T) {
+	t.Run("round trips with leading zeros", func(t *testing.T) {
+		in := []byte{0, 0, 1, 2, 3, 255}
+		out, err := decodeBase58(encodeBase58(in))
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("system program is all ones", func(t *testing.T) {
+		assert.Equal(t, "11111111111111111111111111111111", SystemProgramID.String())
+	})
+
+	t.Run("rejects characters outside the alphabet", func(t *testing.T) {
+		_, err := decodeBase58("0OIl")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,336 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// RPCError is an error response returned by a Solana node.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("solana rpc error %d: %s", e.Code, e.Message)
+}
+
+// Client talks JSON-RPC to Solana clusters.
+type Client struct {
+	httpClient *http.Client
+	nextID     atomic.Int64
+
+	mu       sync.RWMutex
+	clusters map[string]*ClusterConfig
+}
+
+// NewClient creates a new client with the default clusters.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		clusters:   DefaultClusters(),
+	}
+}
+
+// AddCluster adds or replaces a cluster configuration.
+func (c *Client) AddCluster(name string, config *ClusterConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clusters[name] = config
+}
+
+// GetClusterConfig returns the configuration for a cluster.
+func (c *Client) GetClusterConfig(cluster string) (*ClusterConfig, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	config, ok := c.clusters[cluster]
+	if !ok {
+		return nil, fmt.Errorf("unknown solana cluster: %s", cluster)
+	}
+	return config, nil
+}
+
+// ListClusters returns all configured cluster names, sorted.
+func (c *Client) ListClusters() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.clusters))
+	for name := range c.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// call performs a JSON-RPC request, trying each RPC URL in order. Node error
+// responses are returned as *RPCError without trying the remaining URLs,
+// since another node would reject the request the same way.
+func (c *Client) call(ctx context.Context, cluster, method string, params []any, out any) error {
+	config, err := c.GetClusterConfig(cluster)
+	if err != nil {
+		return err
+	}
+	if len(config.RPCURLs) == 0 {
+		return fmt.Errorf("no RPC URLs configured for %s", cluster)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      c.nextID.Add(1),
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, url := range config.RPCURLs {
+		err := c.post(ctx, url, body, out)
+		if err == nil {
+			return nil
+		}
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("%s failed on all RPCs for %s: %w", method, cluster, lastErr)
+}
+
+func (c *Client) post(ctx context.Context, url string, body []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if envelope.Error != nil {
+		return envelope.Error
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(envelope.Result, out)
+}
+
+// GetBalance returns the lamport balance of an account.
+func (c *Client) GetBalance(ctx context.Context, cluster string, account PublicKey) (uint64, error) {
+	var res struct {
+		Value uint64 `json:"value"`
+	}
+	params := []any{account.String(), map[string]string{"commitment": "confirmed"}}
+	if err := c.call(ctx, cluster, "getBalance", params, &res); err != nil {
+		return 0, err
+	}
+	return res.Value, nil
+}
+
+// TokenBalance is an SPL token balance held by an owner, summed across all of
+// the owner's token accounts for the mint.
+type TokenBalance struct {
+	Mint     string `json:"mint"`
+	Amount   string `json:"amount"`
+	Decimals uint8  `json:"decimals"`
+	// UIAmount is Amount scaled by Decimals, as a decimal string.
+	UIAmount string `json:"ui_amount"`
+}
+
+// GetTokenBalances returns the SPL token balances held by owner under both
+// the Token and Token-2022 programs. When mint is non-nil only that mint is
+// returned.
+func (c *Client) GetTokenBalances(ctx context.Context, cluster string, owner PublicKey, mint *PublicKey) ([]TokenBalance, error) {
+	filters := []map[string]string{
+		{"programId": TokenProgramID.String()},
+		{"programId": Token2022ProgramID.String()},
+	}
+	if mint != nil {
+		// A mint filter matches regardless of which token program owns it.
+		filters = []map[string]string{{"mint": mint.String()}}
+	}
+
+	totals := make(map[string]*tokenTotal)
+	var order []string
+	for _, filter := range filters {
+		var res struct {
+			Value []struct {
+				Account struct {
+					Data struct {
+						Parsed struct {
+							Info struct {
+								Mint        string `json:"mint"`
+								TokenAmount struct {
+									Amount   string `json:"amount"`
+									Decimals uint8  `json:"decimals"`
+								} `json:"tokenAmount"`
+							} `json:"info"`
+						} `json:"parsed"`
+					} `json:"data"`
+				} `json:"account"`
+			} `json:"value"`
+		}
+		params := []any{owner.String(), filter, map[string]string{"encoding": "jsonParsed", "commitment": "confirmed"}}
+		if err := c.call(ctx, cluster, "getTokenAccountsByOwner", params, &res); err != nil {
+			return nil, err
+		}
+
+		for _, acct := range res.Value {
+			info := acct.Account.Data.Parsed.Info
+			t, ok := totals[info.Mint]
+			if !ok {
+				t = &tokenTotal{decimals: info.TokenAmount.Decimals}
+				totals[info.Mint] = t
+				order = append(order, info.Mint)
+			}
+			if err := t.add(info.TokenAmount.Amount); err != nil {
+				return nil, fmt.Errorf("token account for mint %s: %w", info.Mint, err)
+			}
+		}
+	}
+
+	balances := make([]TokenBalance, 0, len(order))
+	for _, m := range order {
+		t := totals[m]
+		balances = append(balances, TokenBalance{
+			Mint:     m,
+			Amount:   t.amount.String(),
+			Decimals: t.decimals,
+			UIAmount: chain.FormatBalance(&t.amount, t.decimals),
+		})
+	}
+	return balances, nil
+}
+
+type tokenTotal struct {
+	amount   big.Int
+	decimals uint8
+}
+
+func (t *tokenTotal) add(raw string) error {
+	v, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return fmt.Errorf("invalid token amount %q", raw)
+	}
+	t.amount.Add(&t.amount, v)
+	return nil
+}
+
+// GetLatestBlockhash returns a recent blockhash to anchor a transaction.
+func (c *Client) GetLatestBlockhash(ctx context.Context, cluster string) ([32]byte, error) {
+	var res struct {
+		Value struct {
+			Blockhash string `json:"blockhash"`
+		} `json:"value"`
+	}
+	params := []any{map[string]string{"commitment": "confirmed"}}
+	if err := c.call(ctx, cluster, "getLatestBlockhash", params, &res); err != nil {
+		return [32]byte{}, err
+	}
+	b, err := decodeBase58(res.Value.Blockhash)
+	if err != nil || len(b) != 32 {
+		return [32]byte{}, fmt.Errorf("invalid blockhash from node: %q", res.Value.Blockhash)
+	}
+	return [32]byte(b), nil
+}
+
+// SendTransaction submits a signed, serialized transaction and returns its
+// base58 signature.
+func (c *Client) SendTransaction(ctx context.Context, cluster string, rawTx []byte) (string, error) {
+	var sig string
+	params := []any{
+		base64.StdEncoding.EncodeToString(rawTx),
+		map[string]any{"encoding": "base64", "preflightCommitment": "confirmed"},
+	}
+	if err := c.call(ctx, cluster, "sendTransaction", params, &sig); err != nil {
+		return "", err
+	}
+	return sig, nil
+}
+
+// SignatureStatus is the processing state of a submitted transaction.
+type SignatureStatus struct {
+	Slot               uint64 `json:"slot"`
+	ConfirmationStatus string `json:"confirmationStatus"`
+	// Err is the raw transaction error, or nil when the transaction succeeded.
+	Err json.RawMessage `json:"err"`
+}
+
+// Failed reports whether the transaction was processed with an error.
+func (s *SignatureStatus) Failed() bool {
+	return len(s.Err) > 0 && string(s.Err) != "null"
+}
+
+// GetSignatureStatus returns the status of a transaction, or nil if the
+// cluster has not seen it yet.
+func (c *Client) GetSignatureStatus(ctx context.Context, cluster, signature string) (*SignatureStatus, error) {
+	var res struct {
+		Value []*SignatureStatus `json:"value"`
+	}
+	params := []any{[]string{signature}, map[string]bool{"searchTransactionHistory": true}}
+	if err := c.call(ctx, cluster, "getSignatureStatuses", params, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Value) == 0 {
+		return nil, nil
+	}
+	return res.Value[0], nil
+}
+
+// WaitForConfirmation polls until the transaction reaches confirmed or
+// finalized commitment, or ctx is done.
+func (c *Client) WaitForConfirmation(ctx context.Context, cluster, signature string, interval time.Duration) (*SignatureStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetSignatureStatus(ctx, cluster, signature)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			if status.Failed() {
+				return status, fmt.Errorf("transaction %s failed: %s", signature, status.Err)
+			}
+			if status.ConfirmationStatus == "confirmed" || status.ConfirmationStatus == "finalized" {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for confirmation of %s: %w", signature, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRPC serves canned JSON-RPC results keyed by method name.
+func fakeRPC(t *testing.T, results map[string]func(params []json.RawMessage) any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int64             `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if fn, ok := results[req.Method]; ok {
+			resp["result"] = fn(req.Params)
+		} else {
+			resp["error"] = map[string]any{"code": -32601, "message": "method not found"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestClient(urls ...string) *Client {
+	c := NewClient()
+	c.AddCluster("test", &ClusterConfig{Name: "Test", RPCURLs: urls, IsTestnet: true})
+	return c
+}
+
+func tokenAccount(mint, amount string, decimals int) map[string]any {
+	return map[string]any{"account": map[string]any{"data": map[string]any{"parsed": map[string]any{"info": map[string]any{
+		"mint":        mint,
+		"tokenAmount": map[string]any{"amount": amount, "decimals": decimals},
+	}}}}}
+}
+
+func TestClient_GetBalance(t *testing.T) {
+	srv := fakeRPC(t, map[string]func([]json.RawMessage) any{
+		"getBalance": func([]json.RawMessage) any { return map[string]any{"value": 1_500_000_000} },
+	})
+	c := newTestClient(srv.URL)
+
+	bal, err := c.GetBalance(context.Background(), "test", SystemProgramID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1_500_000_000), bal)
+
+	_, err = c.GetBalance(context.Background(), "nope", SystemProgramID)
+	assert.ErrorContains(t, err, "unknown solana cluster")
+}
+
+func TestClient_FallsBackOnTransportErrorOnly(t *testing.T) {
+	good := fakeRPC(t, map[string]func([]json.RawMessage) any{
+		"getBalance": func([]json.RawMessage) any { return map[string]any{"value": 7} },
+	})
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+
+	bal, err := newTestClient(down.URL, good.URL).GetBalance(context.Background(), "test", SystemProgramID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), bal)
+
+	// A node error is final: another node would reject the request too.
+	_, err = newTestClient(good.URL, good.URL).GetLatestBlockhash(context.Background(), "test")
+	var rpcErr *RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32601, rpcErr.Code)
+}
+
+func TestClient_GetTokenBalances(t *testing.T) {
+	usdc := "EPjFWdd5AufqSSqeM2qJznb4vs2zuuBNctWkqwwDTZ5a"
+	pyusd := "2b1kV6DkPAnxd5ixfnxCpjxmKwqjjaYmCZfHsFu24GXo"
+	srv := fakeRPC(t, map[string]func([]json.RawMessage) any{
+		"getTokenAccountsByOwner": func(params []json.RawMessage) any {
+			var filter map[string]string
+			_ = json.Unmarshal(params[1], &filter)
+			switch {
+			case filter["programId"] == TokenProgramID.String():
+				// Two accounts for the same mint are summed.
+				return map[string]any{"value": []any{tokenAccount(usdc, "1500000", 6), tokenAccount(usdc, "500000", 6)}}
+			case filter["programId"] == Token2022ProgramID.String():
+				return map[string]any{"value": []any{tokenAccount(pyusd, "250", 6)}}
+			case filter["mint"] == usdc:
+				return map[string]any{"value": []any{tokenAccount(usdc, "42", 6)}}
+			}
+			return map[string]any{"value": []any{}}
+		},
+	})
+	c := newTestClient(srv.URL)
+
+	t.Run("all mints across both token programs", func(t *testing.T) {
+		balances, err := c.GetTokenBalances(context.Background(), "test", SystemProgramID, nil)
+		require.NoError(t, err)
+		require.Len(t, balances, 2)
+		assert.Equal(t, usdc, balances[0].Mint)
+		assert.Equal(t, "2000000", balances[0].Amount)
+		assert.Equal(t, "2.000000", balances[0].UIAmount)
+		assert.Equal(t, pyusd, balances[1].Mint)
+		assert.Equal(t, "250", balances[1].Amount)
+	})
+
+	t.Run("single mint", func(t *testing.T) {
+		mint := MustParsePublicKey(usdc)
+		balances, err := c.GetTokenBalances(context.Background(), "test", SystemProgramID, &mint)
+		require.NoError(t, err)
+		require.Len(t, balances, 1)
+		assert.Equal(t, "42", balances[0].Amount)
+	})
+}
+
+func TestClient_SendAndConfirm(t *testing.T) {
+	var polls int
+	srv := fakeRPC(t, map[string]func([]json.RawMessage) any{
+		"sendTransaction": func([]json.RawMessage) any { return "sig123" },
+		"getSignatureStatuses": func([]json.RawMessage) any {
+			polls++
+			if polls == 1 {
+				return map[string]any{"value": []any{nil}}
+			}
+			return map[string]any{"value": []any{map[string]any{"slot": 9, "confirmationStatus": "confirmed", "err": nil}}}
+		},
+	})
+	c := newTestClient(srv.URL)
+
+	sig, err := c.SendTransaction(context.Background(), "test", []byte{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, "sig123", sig)
+
+	status, err := c.WaitForConfirmation(context.Background(), "test", sig, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), status.Slot)
+	assert.Equal(t, 2, polls)
+}
+
+func TestClient_WaitForConfirmationReportsFailure(t *testing.T) {
+	srv := fakeRPC(t, map[string]func([]json.RawMessage) any{
+		"getSignatureStatuses": func([]json.RawMessage) any {
+			return map[string]any{"value": []any{map[string]any{"slot": 1, "confirmationStatus": "processed", "err": map[string]any{"InstructionError": []any{0, "Custom"}}}}}
+		},
+	})
+
+	_, err := newTestClient(srv.URL).WaitForConfirmation(context.Background(), "test", "sig", 10*time.Millisecond)
+	assert.ErrorContains(t, err, "failed")
+}
@@ -0,0 +1,145 @@
+package solana
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LamportsPerSOL is the number of lamports in one SOL.
+const LamportsPerSOL = 1_000_000_000
+
+// PublicKey is an ed25519 public key, used as a Solana account address.
+type PublicKey [32]byte
+
+var (
+	// SystemProgramID owns native SOL accounts and handles transfers.
+	SystemProgramID = PublicKey{}
+	// TokenProgramID is the SPL Token program.
+	TokenProgramID = MustParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	// Token2022ProgramID is the SPL Token-2022 program.
+	Token2022ProgramID = MustParsePublicKey("TokenzQdBNbLqP5VEhdkAS6EPFLC1PgnBqvQKLjqcHu")
+)
+
+// ParsePublicKey decodes a base58 Solana address.
+func ParsePublicKey(s string) (PublicKey, error) {
+	b, err := decodeBase58(strings.TrimSpace(s))
+	if err != nil || len(b) != len(PublicKey{}) {
+		return PublicKey{}, fmt.Errorf("invalid solana address: %s", s)
+	}
+	return PublicKey(b), nil
+}
+
+// MustParsePublicKey is ParsePublicKey for compile-time constants.
+func MustParsePublicKey(s string) PublicKey {
+	pk, err := ParsePublicKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return pk
+}
+
+// String returns the base58 encoding of the key.
+func (pk PublicKey) String() string {
+	return encodeBase58(pk[:])
+}
+
+// ClusterConfig holds configuration for a Solana cluster.
+type ClusterConfig struct {
+	Name        string   `yaml:"name"`
+	RPCURLs     []string `yaml:"rpc_urls"`
+	ExplorerURL string   `yaml:"explorer_url"`
+	IsTestnet   bool     `yaml:"is_testnet"`
+	// ExplorerCluster is appended as ?cluster= to explorer links for
+	// non-mainnet clusters, which share the mainnet explorer host.
+	ExplorerCluster string `yaml:"explorer_cluster"`
+}
+
+// TxURL returns the block explorer page for a transaction signature, or ""
+// when the cluster has no explorer configured.
+func (c *ClusterConfig) TxURL(signature string) string {
+	return c.explorerPath("tx", signature)
+}
+
+// AddressURL returns the block explorer page for an account, or "" when the
+// cluster has no explorer configured.
+func (c *ClusterConfig) AddressURL(address string) string {
+	return c.explorerPath("address", address)
+}
+
+func (c *ClusterConfig) explorerPath(kind, id string) string {
+	if c == nil || c.ExplorerURL == "" || id == "" {
+		return ""
+	}
+	u := strings.TrimRight(c.ExplorerURL, "/") + "/" + kind + "/" + id
+	if c.ExplorerCluster != "" {
+		u += "?cluster=" + c.ExplorerCluster
+	}
+	return u
+}
+
+// DefaultClusters returns the default cluster configurations. Cluster names
+// are prefixed with "solana" so they never collide with EVM chain names.
+func DefaultClusters() map[string]*ClusterConfig {
+	return map[string]*ClusterConfig{
+		"solana": {
+			Name:        "Solana Mainnet Beta",
+			RPCURLs:     []string{"https://api.mainnet-beta.solana.com"},
+			ExplorerURL: "https://explorer.solana.com",
+			IsTestnet:   false,
+		},
+		"solana-devnet": {
+			Name:            "Solana Devnet",
+			RPCURLs:         []string{"https://api.devnet.solana.com"},
+			ExplorerURL:     "https://explorer.solana.com",
+			IsTestnet:       true,
+			ExplorerCluster: "devnet",
+		},
+		"solana-testnet": {
+			Name:            "Solana Testnet",
+			RPCURLs:         []string{"https://api.testnet.solana.com"},
+			ExplorerURL:     "https://explorer.solana.com",
+			IsTestnet:       true,
+			ExplorerCluster: "testnet",
+		},
+	}
+}
+
+// FormatSOL formats a lamport amount as a decimal SOL string.
+func FormatSOL(lamports uint64) string {
+	whole := lamports / LamportsPerSOL
+	frac := lamports % LamportsPerSOL
+	if frac == 0 {
+		return fmt.Sprintf("%d", whole)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%09d", whole, frac), "0")
+}
+
+// ParseSOL parses a decimal SOL amount into lamports.
+func ParseSOL(amount string) (uint64, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("empty amount")
+	}
+	if len(frac) > 9 {
+		return 0, fmt.Errorf("too many decimal places (max 9)")
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	frac += strings.Repeat("0", 9-len(frac))
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %s", amount)
+	}
+	f, err := strconv.ParseUint(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %s", amount)
+	}
+	if w > (math.MaxUint64-f)/LamportsPerSOL {
+		return 0, fmt.Errorf("amount too large: %s", amount)
+	}
+	return w*LamportsPerSOL + f, nil
+}
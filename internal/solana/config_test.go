@@ -0,0 +1,61 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	pk, err := ParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	require.NoError(t, err)
+	assert.Equal(t, "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", pk.String())
+
+	_, err = ParsePublicKey("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	assert.Error(t, err)
+	_, err = ParsePublicKey("abc")
+	assert.Error(t, err)
+}
+
+func TestClusterConfig_ExplorerLinks(t *testing.T) {
+	clusters := DefaultClusters()
+
+	assert.Equal(t, "https://explorer.solana.com/tx/sig", clusters["solana"].TxURL("sig"))
+	assert.Equal(t, "https://explorer.solana.com/tx/sig?cluster=devnet", clusters["solana-devnet"].TxURL("sig"))
+	assert.Equal(t, "https://explorer.solana.com/address/addr?cluster=testnet", clusters["solana-testnet"].AddressURL("addr"))
+	assert.Empty(t, (&ClusterConfig{}).TxURL("sig"))
+}
+
+func TestParseSOL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+	}{
+		{"1", LamportsPerSOL},
+		{"0.5", LamportsPerSOL / 2},
+		{".000000001", 1},
+		{"12.345", 12_345_000_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSOL(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, bad := range []string{"", "-1", "1.0000000001", "abc", "1e9", "99999999999999999999"} {
+		t.Run("rejects "+bad, func(t *testing.T) {
+			_, err := ParseSOL(bad)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFormatSOL(t *testing.T) {
+	assert.Equal(t, "0", FormatSOL(0))
+	assert.Equal(t, "1", FormatSOL(LamportsPerSOL))
+	assert.Equal(t, "1.5", FormatSOL(LamportsPerSOL+LamportsPerSOL/2))
+	assert.Equal(t, "0.000000001", FormatSOL(1))
+}
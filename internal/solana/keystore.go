@@ -0,0 +1,208 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+var (
+	ErrKeypairNotFound = errors.New("solana keypair not found")
+	ErrKeypairLocked   = errors.New("solana keypair is locked")
+	ErrInvalidSecret   = errors.New("invalid solana secret key")
+)
+
+// keyFileVersion is bumped if the on-disk format changes.
+const keyFileVersion = 1
+
+// keyFile is the on-disk format of a keypair: the 64-byte ed25519 private key
+// encrypted with the same scrypt/AES-CTR scheme as EVM keystore files.
+type keyFile struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	Version int                 `json:"version"`
+}
+
+// Keystore stores encrypted Solana keypairs under <dataDir>/solana, separate
+// from the EVM keystore since the key types and address formats differ.
+type Keystore struct {
+	dir     string
+	scryptN int
+	scryptP int
+}
+
+// NewKeystore creates a keystore rooted at dataDir.
+func NewKeystore(dataDir string) (*Keystore, error) {
+	dir := filepath.Join(dataDir, "solana")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create solana keystore directory: %w", err)
+	}
+	// Like EVM wallets, new files get wallet.scrypt.
+	scrypt := wallet.ConfiguredScrypt()
+	return &Keystore{
+		dir:     dir,
+		scryptN: scrypt.N,
+		scryptP: scrypt.P,
+	}, nil
+}
+
+// Create generates a new keypair and stores it encrypted with password.
+func (ks *Keystore) Create(password string) (PublicKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	return ks.store(priv, password)
+}
+
+// Import stores an existing secret key. It accepts the base58 form exported
+// by wallets such as Phantom and the JSON byte array written by
+// solana-keygen; both hold the 64-byte seed+public key.
+func (ks *Keystore) Import(secret, password string) (PublicKey, error) {
+	secret = strings.TrimSpace(secret)
+
+	var raw []byte
+	if strings.HasPrefix(secret, "[") {
+		if err := json.Unmarshal([]byte(secret), &raw); err != nil {
+			return PublicKey{}, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+		}
+	} else {
+		b, err := decodeBase58(secret)
+		if err != nil {
+			return PublicKey{}, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+		}
+		raw = b
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return PublicKey{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSecret, ed25519.PrivateKeySize, len(raw))
+	}
+
+	// Rebuild from the seed and check the embedded public key so a corrupted
+	// or mismatched export is rejected instead of stored.
+	priv := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+	if !ed25519.PublicKey(raw[ed25519.SeedSize:]).Equal(priv.Public()) {
+		return PublicKey{}, fmt.Errorf("%w: public key does not match seed", ErrInvalidSecret)
+	}
+	return ks.store(priv, password)
+}
+
+func (ks *Keystore) store(priv ed25519.PrivateKey, password string) (PublicKey, error) {
+	pub := PublicKey(priv.Public().(ed25519.PublicKey))
+	if ks.Has(pub) {
+		return pub, fmt.Errorf("keypair %s already exists", pub)
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3(priv, []byte(password), ks.scryptN, ks.scryptP)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("failed to encrypt keypair: %w", err)
+	}
+	data, err := json.Marshal(keyFile{Address: pub.String(), Crypto: cryptoJSON, Version: keyFileVersion})
+	if err != nil {
+		return PublicKey{}, err
+	}
+	if err := os.WriteFile(ks.path(pub), data, 0600); err != nil {
+		return PublicKey{}, fmt.Errorf("failed to write keypair: %w", err)
+	}
+	return pub, nil
+}
+
+// List returns the public keys of all stored keypairs, sorted by address.
+func (ks *Keystore) List() ([]PublicKey, error) {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []PublicKey
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		pk, err := ParsePublicKey(name)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, pk)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys, nil
+}
+
+// Has reports whether a keypair for pub is stored.
+func (ks *Keystore) Has(pub PublicKey) bool {
+	_, err := os.Stat(ks.path(pub))
+	return err == nil
+}
+
+// Signer decrypts the keypair for pub.
+func (ks *Keystore) Signer(pub PublicKey, password string) (*Signer, error) {
+	data, err := os.ReadFile(ks.path(pub))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrKeypairNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("corrupt keypair file: %w", err)
+	}
+	priv, err := keystore.DecryptDataV3(kf.Crypto, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock keypair: %w", err)
+	}
+	if len(priv) != ed25519.PrivateKeySize || PublicKey(priv[ed25519.SeedSize:]) != pub {
+		return nil, fmt.Errorf("corrupt keypair file for %s", pub)
+	}
+	return &Signer{pub: pub, key: priv}, nil
+}
+
+func (ks *Keystore) path(pub PublicKey) string {
+	return filepath.Join(ks.dir, pub.String()+".json")
+}
+
+// Signer signs Solana transactions with an unlocked keypair.
+type Signer struct {
+	// mu protects key so signing cannot race with Lock zeroing it.
+	mu  sync.RWMutex
+	pub PublicKey
+	key ed25519.PrivateKey // nil when locked
+}
+
+// PublicKey returns the signer's address.
+func (s *Signer) PublicKey() PublicKey {
+	return s.pub
+}
+
+// SignTransaction signs tx.Message and stores the signature on tx.
+func (s *Signer) SignTransaction(tx *Transaction) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.key == nil {
+		return ErrKeypairLocked
+	}
+	tx.Signature = ed25519.Sign(s.key, tx.Message)
+	return nil
+}
+
+// Lock zeros the private key. Safe to call multiple times.
+func (s *Signer) Lock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.key)
+	s.key = nil
+}
@@ -0,0 +1,96 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeystore(t *testing.T) *Keystore {
+	t.Helper()
+	ks, err := NewKeystore(t.TempDir())
+	require.NoError(t, err)
+	// Standard scrypt parameters take seconds per operation.
+	ks.scryptN, ks.scryptP = keystore.LightScryptN, keystore.LightScryptP
+	return ks
+}
+
+func TestKeystore_CreateAndSign(t *testing.T) {
+	ks := newTestKeystore(t)
+
+	pub, err := ks.Create("password123")
+	require.NoError(t, err)
+	assert.True(t, ks.Has(pub))
+
+	keys, err := ks.List()
+	require.NoError(t, err)
+	assert.Equal(t, []PublicKey{pub}, keys)
+
+	_, err = ks.Signer(pub, "wrong")
+	require.Error(t, err)
+
+	signer, err := ks.Signer(pub, "password123")
+	require.NoError(t, err)
+	assert.Equal(t, pub, signer.PublicKey())
+
+	tx := &Transaction{Message: []byte("message")}
+	require.NoError(t, signer.SignTransaction(tx))
+	assert.True(t, ed25519.Verify(ed25519.PublicKey(pub[:]), tx.Message, tx.Signature))
+
+	signer.Lock()
+	signer.Lock()
+	assert.ErrorIs(t, signer.SignTransaction(tx), ErrKeypairLocked)
+}
+
+func TestKeystore_Import(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	t.Run("base58 secret", func(t *testing.T) {
+		ks := newTestKeystore(t)
+		got, err := ks.Import(encodeBase58(priv), "password123")
+		require.NoError(t, err)
+		assert.Equal(t, PublicKey(pub), got)
+
+		_, err = ks.Import(encodeBase58(priv), "password123")
+		assert.Error(t, err, "duplicate import should fail")
+	})
+
+	t.Run("solana-keygen byte array", func(t *testing.T) {
+		ks := newTestKeystore(t)
+		ints := make([]int, len(priv))
+		for i, b := range priv {
+			ints[i] = int(b)
+		}
+		arr, err := json.Marshal(ints)
+		require.NoError(t, err)
+
+		got, err := ks.Import(string(arr), "password123")
+		require.NoError(t, err)
+		assert.Equal(t, PublicKey(pub), got)
+	})
+
+	t.Run("rejects mismatched public key", func(t *testing.T) {
+		ks := newTestKeystore(t)
+		bad := append([]byte{}, priv...)
+		bad[63] ^= 1
+		_, err := ks.Import(encodeBase58(bad), "password123")
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+
+	t.Run("rejects wrong length", func(t *testing.T) {
+		ks := newTestKeystore(t)
+		_, err := ks.Import(encodeBase58(priv[:32]), "password123")
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+}
+
+func TestKeystore_SignerNotFound(t *testing.T) {
+	ks := newTestKeystore(t)
+	_, err := ks.Signer(SystemProgramID, "password123")
+	assert.ErrorIs(t, err, ErrKeypairNotFound)
+}
@@ -0,0 +1,82 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+)
+
+// systemTransferInstruction is the System program's Transfer discriminant.
+const systemTransferInstruction = 2
+
+// Transaction is a single-signer legacy Solana transaction.
+type Transaction struct {
+	// Message is the serialized message that the fee payer signs.
+	Message   []byte
+	Signature []byte
+}
+
+// NewTransferMessage builds the message for a System program transfer of
+// lamports from one account to another, paid for by from.
+//
+// Layout (legacy message):
+//
+//	header:       [num_required_signatures, num_readonly_signed, num_readonly_unsigned]
+//	account_keys: compact-u16 length, then 32-byte keys (from, to, system program)
+//	blockhash:    32 bytes
+//	instructions: compact-u16 length, then program index, account indexes, data
+func NewTransferMessage(from, to PublicKey, lamports uint64, recentBlockhash [32]byte) ([]byte, error) {
+	if from == to {
+		return nil, fmt.Errorf("sender and recipient are the same account")
+	}
+
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], systemTransferInstruction)
+	binary.LittleEndian.PutUint64(data[4:12], lamports)
+
+	// Only the fee payer signs; the system program is the one read-only
+	// unsigned account, and the recipient is writable.
+	msg := []byte{1, 0, 1}
+	msg = appendCompactU16(msg, 3)
+	msg = append(msg, from[:]...)
+	msg = append(msg, to[:]...)
+	msg = append(msg, SystemProgramID[:]...)
+	msg = append(msg, recentBlockhash[:]...)
+
+	msg = appendCompactU16(msg, 1)
+	msg = append(msg, 2) // program id index: system program
+	msg = appendCompactU16(msg, 2)
+	msg = append(msg, 0, 1) // from, to
+	msg = appendCompactU16(msg, len(data))
+	msg = append(msg, data...)
+	return msg, nil
+}
+
+// Serialize returns the wire encoding of a signed transaction.
+func (tx *Transaction) Serialize() ([]byte, error) {
+	if len(tx.Signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("transaction is not signed")
+	}
+	out := appendCompactU16(nil, 1)
+	out = append(out, tx.Signature...)
+	return append(out, tx.Message...), nil
+}
+
+// ID returns the transaction signature in base58, which Solana uses as the
+// transaction identifier.
+func (tx *Transaction) ID() string {
+	return encodeBase58(tx.Signature)
+}
+
+// appendCompactU16 appends n in Solana's "shortvec" encoding: 7 bits per
+// byte, high bit set when more bytes follow.
+func appendCompactU16(b []byte, n int) []byte {
+	for {
+		v := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(b, v)
+		}
+		b = append(b, v|0x80)
+	}
+}
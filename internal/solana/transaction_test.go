@@ -0,0 +1,58 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransferMessage(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	from := PublicKey(pub)
+	to := MustParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	var blockhash [32]byte
+	blockhash[0] = 7
+
+	msg, err := NewTransferMessage(from, to, 1234, blockhash)
+	require.NoError(t, err)
+
+	// header + 3 keys + blockhash + instruction (count, program, 2 accounts, 12 data bytes)
+	require.Len(t, msg, 3+1+3*32+32+1+1+1+2+1+12)
+	assert.Equal(t, []byte{1, 0, 1, 3}, msg[:4])
+	assert.Equal(t, from[:], msg[4:36])
+	assert.Equal(t, to[:], msg[36:68])
+	assert.Equal(t, SystemProgramID[:], msg[68:100])
+	assert.Equal(t, blockhash[:], msg[100:132])
+
+	data := msg[len(msg)-12:]
+	assert.Equal(t, uint32(systemTransferInstruction), binary.LittleEndian.Uint32(data[:4]))
+	assert.Equal(t, uint64(1234), binary.LittleEndian.Uint64(data[4:]))
+
+	tx := &Transaction{Message: msg}
+	_, err = tx.Serialize()
+	require.Error(t, err, "unsigned transactions must not serialize")
+
+	tx.Signature = ed25519.Sign(priv, msg)
+	raw, err := tx.Serialize()
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), raw[0])
+	assert.True(t, ed25519.Verify(pub, raw[1+64:], raw[1:1+64]))
+	assert.Equal(t, encodeBase58(tx.Signature), tx.ID())
+}
+
+func TestNewTransferMessage_RejectsSelfTransfer(t *testing.T) {
+	pk := MustParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	_, err := NewTransferMessage(pk, pk, 1, [32]byte{})
+	assert.Error(t, err)
+}
+
+func TestAppendCompactU16(t *testing.T) {
+	assert.Equal(t, []byte{0x00}, appendCompactU16(nil, 0))
+	assert.Equal(t, []byte{0x7f}, appendCompactU16(nil, 127))
+	assert.Equal(t, []byte{0x80, 0x01}, appendCompactU16(nil, 128))
+	assert.Equal(t, []byte{0xff, 0xff, 0x03}, appendCompactU16(nil, 0xffff))
+}
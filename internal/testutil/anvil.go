@@ -0,0 +1,107 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// AnvilMnemonic is the mnemonic of the accounts anvil funds with 10000 ETH
+// each. The first one is 0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266.
+const AnvilMnemonic = "test test test test test test test test test test test junk"
+
+// AnvilChainID is the chain ID the node is started with, which is also
+// the local chain preset's, so tests need no chains.yaml chain_id.
+const AnvilChainID = 31337
+
+// Anvil is a running anvil node.
+type Anvil struct {
+	URL string
+	// Forked is set when the node forks CLIFI_FORK_URL, so mainnet
+	// contracts such as USDC exist on it.
+	Forked bool
+}
+
+// StartAnvil starts anvil on a free port and stops it when the test ends.
+// When CLIFI_FORK_URL is set the node forks that network. The test is
+// skipped if anvil isn't installed.
+func StartAnvil(t *testing.T) *Anvil {
+	t.Helper()
+	bin, err := exec.LookPath("anvil")
+	if err != nil {
+		t.Skip("anvil not installed; skipping local node test (see https://book.getfoundry.sh)")
+	}
+
+	port, err := freePort()
+	if err != nil {
+		t.Fatalf("find free port: %v", err)
+	}
+	args := []string{"--port", strconv.Itoa(port), "--chain-id", strconv.Itoa(AnvilChainID), "--silent"}
+	forkURL := os.Getenv("CLIFI_FORK_URL")
+	if forkURL != "" {
+		args = append(args, "--fork-url", forkURL)
+	}
+
+	cmd := exec.Command(bin, args...)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start anvil: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	a := &Anvil{URL: fmt.Sprintf("http://127.0.0.1:%d", port), Forked: forkURL != ""}
+	// A fork fetches state from the upstream RPC before it answers.
+	if err := waitForRPC(a.URL, 30*time.Second); err != nil {
+		t.Fatalf("anvil did not start: %v", err)
+	}
+	return a
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitForRPC polls eth_chainId until the node answers or timeout passes.
+func waitForRPC(url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)
+
+	var lastErr error
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("status %s", resp.Status)
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return lastErr
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
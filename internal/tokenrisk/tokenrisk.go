@@ -0,0 +1,141 @@
+// Package tokenrisk rates how likely an ERC-20 token is a scam, from what
+// is known about it: token lists, scam lists, its name and symbol, and
+// whether a transfer of it goes through in simulation.
+package tokenrisk
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Level is how risky a token looks.
+type Level int
+
+const (
+	Low Level = iota
+	Medium
+	High
+)
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// Transfer is the outcome of simulating a transfer of the token.
+type Transfer int
+
+const (
+	// TransferNotRun means there was nothing to simulate with, e.g. no
+	// balance.
+	TransferNotRun Transfer = iota
+	TransferOK
+	TransferFailed
+)
+
+// Facts is what is known about a token.
+type Facts struct {
+	Symbol string
+	Name   string
+	// Listed names the token lists that include this contract.
+	Listed []string
+	// Impersonates describes listed contracts that use the same symbol,
+	// e.g. "USDC 0xA0b8…".
+	Impersonates []string
+	// Flagged is why scam lists name the contract.
+	Flagged []string
+	// Transfer and TransferError are the outcome of simulating a transfer
+	// from the holder.
+	Transfer      Transfer
+	TransferError string
+}
+
+// Verdict is a token's risk and why.
+type Verdict struct {
+	Level   Level
+	Reasons []string
+}
+
+func (v Verdict) String() string {
+	return v.Level.String() + " risk: " + strings.Join(v.Reasons, "; ")
+}
+
+// Assess rates a token. Anything on a scam list, posing as a listed
+// token, advertising a site in its name or failing to transfer is high
+// risk; a token no list knows is medium.
+func Assess(f Facts) Verdict {
+	var high []string
+	for _, h := range f.Flagged {
+		high = append(high, "on a scam list: "+h)
+	}
+	if len(f.Listed) == 0 && len(f.Impersonates) > 0 {
+		high = append(high, fmt.Sprintf("uses the symbol of listed %s but is a different contract", strings.Join(f.Impersonates, ", ")))
+	}
+	if reason := Phishing(f.Name, f.Symbol); reason != "" {
+		high = append(high, reason)
+	}
+	if f.Transfer == TransferFailed {
+		reason := "a transfer fails in simulation, so it may be impossible to sell or move (honeypot)"
+		if f.TransferError != "" {
+			reason += ": " + f.TransferError
+		}
+		high = append(high, reason)
+	}
+	if len(high) > 0 {
+		return Verdict{Level: High, Reasons: high}
+	}
+
+	var reasons []string
+	level := Low
+	if len(f.Listed) > 0 {
+		reasons = append(reasons, "listed on "+strings.Join(f.Listed, ", "))
+	} else {
+		level = Medium
+		reasons = append(reasons, "not on any token list")
+	}
+	switch {
+	case f.Transfer == TransferOK:
+		reasons = append(reasons, "a transfer succeeds in simulation")
+	case level == Medium:
+		// Without a balance to move, a honeypot looks like any other
+		// unlisted token.
+		reasons = append(reasons, "no transfer could be simulated, so it may still be a honeypot")
+	}
+	return Verdict{Level: level, Reasons: reasons}
+}
+
+// phishingWords are what spam airdrop tokens use to lure holders to a
+// drainer site.
+var phishingWords = []string{"claim", "visit", "reward", "airdrop", "voucher", "redeem", "eligible"}
+
+var siteMarkers = []string{"http", "www.", ".com", ".io", ".org", ".net", ".xyz", ".app", ".site", ".fi", ".gift", ".top", "t.me/"}
+
+// Phishing explains why a token's name or symbol looks like a phishing
+// lure, or returns "" when it does not: a website or call to action in
+// the name, or non-ASCII letters that can pass for another token's symbol.
+func Phishing(name, symbol string) string {
+	text := strings.ToLower(name + " " + symbol)
+	for _, m := range siteMarkers {
+		if strings.Contains(text, m) {
+			return fmt.Sprintf("its name advertises a website (%q), a common airdrop phishing lure", strings.TrimSpace(name+" "+symbol))
+		}
+	}
+	for _, w := range phishingWords {
+		if strings.Contains(text, w) {
+			return fmt.Sprintf("its name asks holders to %s something (%q), a common airdrop phishing lure", w, strings.TrimSpace(name+" "+symbol))
+		}
+	}
+	for _, r := range symbol {
+		if r > unicode.MaxASCII {
+			return fmt.Sprintf("its symbol %q has non-ASCII characters that can imitate another token's", symbol)
+		}
+	}
+	return ""
+}
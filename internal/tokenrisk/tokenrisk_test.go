@@ -0,0 +1,40 @@
+package tokenrisk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssess(t *testing.T) {
+	v := Assess(Facts{Symbol: "USDC", Name: "USD Coin", Listed: []string{"builtin"}, Transfer: TransferOK})
+	assert.Equal(t, Low, v.Level)
+	assert.Equal(t, "low risk: listed on builtin; a transfer succeeds in simulation", v.String())
+
+	v = Assess(Facts{Symbol: "FROG", Name: "Frog"})
+	assert.Equal(t, Medium, v.Level)
+	assert.Equal(t, []string{"not on any token list", "no transfer could be simulated, so it may still be a honeypot"}, v.Reasons)
+	v = Assess(Facts{Symbol: "FROG", Name: "Frog", Transfer: TransferOK})
+	assert.Equal(t, "medium risk: not on any token list; a transfer succeeds in simulation", v.String())
+
+	// An unlisted contract reusing a listed symbol.
+	v = Assess(Facts{Symbol: "USDC", Name: "USD Coin", Impersonates: []string{"USDC 0xA0b8"}})
+	assert.Equal(t, High, v.Level)
+	assert.Contains(t, v.String(), "uses the symbol of listed USDC 0xA0b8")
+
+	v = Assess(Facts{Symbol: "FROG", Name: "Frog", Transfer: TransferFailed, TransferError: "execution reverted: trading disabled"})
+	assert.Equal(t, High, v.Level)
+	assert.Contains(t, v.String(), "honeypot): execution reverted: trading disabled")
+
+	v = Assess(Facts{Symbol: "FROG", Listed: []string{"uniswap"}, Flagged: []string{"scam per scams.txt"}})
+	assert.Equal(t, High, v.Level)
+	assert.Equal(t, []string{"on a scam list: scam per scams.txt"}, v.Reasons)
+}
+
+func TestPhishing(t *testing.T) {
+	assert.Empty(t, Phishing("Wrapped Ether", "WETH"))
+	assert.Empty(t, Phishing("Rocket Pool ETH", "rETH"))
+	assert.Contains(t, Phishing("Visit usdc-bonus.com to claim", "$ USDC"), "advertises a website")
+	assert.Contains(t, Phishing("Uniswap Airdrop Voucher", "UNI"), "airdrop")
+	assert.Contains(t, Phishing("Tether", "UЅDT"), "non-ASCII")
+}
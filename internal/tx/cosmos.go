@@ -0,0 +1,37 @@
+package tx
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// CosmosIntent is the Cosmos SDK counterpart of Intent: a bank send of one
+// coin between bech32 addresses.
+type CosmosIntent struct {
+	Chain  string   // chain name (e.g., "cosmoshub")
+	From   string   // bech32 sender
+	To     string   // bech32 recipient
+	Denom  string   // base denom (e.g., "uatom" or "ibc/...")
+	Amount *big.Int // amount in base denom units
+}
+
+// ValidateCosmos applies the policy's bech32 allow/deny lists and per-denom
+// spend limits to a Cosmos send.
+func ValidateCosmos(intent CosmosIntent, policy Policy) error {
+	if intent.Amount == nil {
+		return fmt.Errorf("value missing")
+	}
+	if slices.Contains(policy.DenyToBech32, intent.To) {
+		return fmt.Errorf("destination denied by policy")
+	}
+	// An allowlist made only of EVM addresses still restricts Cosmos sends.
+	allowlisted := len(policy.AllowTo) > 0 || len(policy.AllowToBech32) > 0
+	if allowlisted && !slices.Contains(policy.AllowToBech32, intent.To) {
+		return fmt.Errorf("destination not in allowlist")
+	}
+	if limit, ok := policy.MaxPerTxDenom[intent.Denom]; ok && intent.Amount.Cmp(limit) > 0 {
+		return fmt.Errorf("value exceeds max per tx limit for %s", intent.Denom)
+	}
+	return nil
+}
@@ -3,7 +3,10 @@ package tx
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math/big"
+	"sort"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -29,6 +32,51 @@ type Policy struct {
 	MaxPerTxWei *big.Int
 	AllowTo     []common.Address
 	DenyTo      []common.Address
+
+	// Cosmos destinations and limits; see ValidateCosmos.
+	AllowToBech32 []string
+	DenyToBech32  []string
+	MaxPerTxDenom map[string]*big.Int // base denom -> max amount
+
+	// MaxSlippageBps caps swap slippage; see ValidateSwapLimits.
+	MaxSlippageBps uint32
+
+	// ScreenWarnOnly lets sends to recipients on a screening list go
+	// ahead after a warning instead of refusing them.
+	ScreenWarnOnly bool
+}
+
+// Summary describes the active limits in one line, e.g.
+// "max 0.5 ETH/tx, 2 allowed, 1 denied, slippage <= 1.00%".
+func (p Policy) Summary() string {
+	var parts []string
+	if p.MaxPerTxWei != nil {
+		parts = append(parts, fmt.Sprintf("max %s ETH/tx", chain.FormatBalance(p.MaxPerTxWei, 18)))
+	}
+	if n := len(p.MaxPerTxDenom); n > 0 {
+		denoms := make([]string, 0, n)
+		for d := range p.MaxPerTxDenom {
+			denoms = append(denoms, d)
+		}
+		sort.Strings(denoms)
+		parts = append(parts, "denom caps: "+strings.Join(denoms, ", "))
+	}
+	if n := len(p.AllowTo) + len(p.AllowToBech32); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d allowed", n))
+	}
+	if n := len(p.DenyTo) + len(p.DenyToBech32); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d denied", n))
+	}
+	if p.MaxSlippageBps > 0 {
+		parts = append(parts, "slippage <= "+FormatBps(p.MaxSlippageBps))
+	}
+	if p.ScreenWarnOnly {
+		parts = append(parts, "flagged recipients warn only")
+	}
+	if len(parts) == 0 {
+		return "no limits"
+	}
+	return strings.Join(parts, ", ")
 }
 
 // SuggestedFees carries gas estimates so the caller can render them.
@@ -37,6 +85,12 @@ type SuggestedFees struct {
 	MaxFeePerGas     *big.Int
 	MaxPriorityFee   *big.Int
 	EstimatedCostWei *big.Int
+
+	// L1FeeWei is the L1 data fee on rollups, nil elsewhere. When
+	// L1FeeIncluded is set it is already part of GasLimit (Arbitrum);
+	// otherwise it is charged on top and added to EstimatedCostWei (OP stack).
+	L1FeeWei      *big.Int
+	L1FeeIncluded bool
 }
 
 // Validate applies simple allow/deny and spend limits.
@@ -52,7 +106,7 @@ func Validate(intent Intent, policy Policy) error {
 			}
 		}
 	}
-	if len(policy.AllowTo) > 0 {
+	if len(policy.AllowTo) > 0 || len(policy.AllowToBech32) > 0 {
 		allowed := false
 		for _, a := range policy.AllowTo {
 			if a == intent.To {
@@ -123,13 +177,14 @@ func BuildUnsignedTx(ctx context.Context, cc *chain.Client, intent Intent) (*typ
 		}
 		gl, err := cc.EstimateGas(ctx, intent.Chain, call)
 		if err != nil {
+			slog.Warn("gas estimate failed", "chain", intent.Chain, "from", intent.From.Hex(), "to", intent.To.Hex(), "err", err)
 			return nil, SuggestedFees{}, err
 		}
 		gasLimit = gl
 	}
 
 	// Optional eth_call simulation
-	_, _ = cc.CallContract(ctx, intent.Chain, ethereum.CallMsg{
+	if _, err := cc.CallContract(ctx, intent.Chain, ethereum.CallMsg{
 		From:      intent.From,
 		To:        &intent.To,
 		Gas:       gasLimit,
@@ -137,7 +192,9 @@ func BuildUnsignedTx(ctx context.Context, cc *chain.Client, intent Intent) (*typ
 		GasTipCap: maxPrio,
 		Value:     intent.ValueWei,
 		Data:      intent.Data,
-	})
+	}); err != nil {
+		slog.Debug("simulation failed", "chain", intent.Chain, "to", intent.To.Hex(), "err", err)
+	}
 
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   nil, // set by signer
@@ -153,10 +210,58 @@ func BuildUnsignedTx(ctx context.Context, cc *chain.Client, intent Intent) (*typ
 	total := new(big.Int).Mul(maxFee, big.NewInt(int64(gasLimit)))
 	total.Add(total, intent.ValueWei)
 
-	return tx, SuggestedFees{
+	fees := SuggestedFees{
 		GasLimit:         gasLimit,
 		MaxFeePerGas:     maxFee,
 		MaxPriorityFee:   maxPrio,
 		EstimatedCostWei: total,
-	}, nil
+	}
+	if err := addL1Fee(ctx, cc, intent, tx, &fees); err != nil {
+		slog.Warn("l1 fee estimate failed", "chain", intent.Chain, "err", err)
+		return nil, SuggestedFees{}, fmt.Errorf("estimate L1 data fee: %w", err)
+	}
+	slog.Debug("built transaction", "chain", intent.Chain, "nonce", nonce, "gas", gasLimit, "max_fee", maxFee, "max_priority_fee", maxPrio)
+	return tx, fees, nil
+}
+
+// addL1Fee fills in the L1 data fee for rollup chains.
+func addL1Fee(ctx context.Context, cc *chain.Client, intent Intent, tx *types.Transaction, fees *SuggestedFees) error {
+	cfg, err := cc.GetChainConfig(intent.Chain)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.L2Kind {
+	case chain.L2OPStack:
+		// The oracle prices the unsigned RLP encoding, which needs the chain ID.
+		unsigned := types.NewTx(&types.DynamicFeeTx{
+			ChainID:   cfg.ChainID,
+			Nonce:     tx.Nonce(),
+			GasTipCap: tx.GasTipCap(),
+			GasFeeCap: tx.GasFeeCap(),
+			Gas:       tx.Gas(),
+			To:        tx.To(),
+			Value:     tx.Value(),
+			Data:      tx.Data(),
+		})
+		raw, err := unsigned.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		l1Fee, err := cc.OPStackL1Fee(ctx, intent.Chain, raw)
+		if err != nil {
+			return err
+		}
+		fees.L1FeeWei = l1Fee
+		fees.EstimatedCostWei.Add(fees.EstimatedCostWei, l1Fee)
+
+	case chain.L2Arbitrum:
+		l1Gas, baseFee, err := cc.ArbitrumL1Gas(ctx, intent.Chain, intent.To, intent.Data)
+		if err != nil {
+			return err
+		}
+		fees.L1FeeWei = new(big.Int).Mul(baseFee, new(big.Int).SetUint64(l1Gas))
+		fees.L1FeeIncluded = true
+	}
+	return nil
 }
@@ -0,0 +1,213 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// MaxResubmits bounds how often Queue.Submit retries one transaction.
+const MaxResubmits = 3
+
+// ErrFeeAboveConfirmed is returned when the node wants fees above the max
+// fee the user confirmed, which Submit never raises: the transaction must
+// be previewed again.
+var ErrFeeAboveConfirmed = errors.New("network fees rose above the confirmed max fee; preview the transaction again")
+
+// ErrNonceHeld is returned when another pending transaction from the
+// account, sent outside this queue, holds the nonce. Submit doesn't queue
+// behind it, since that would leave the send waiting on a transaction the
+// user never saw.
+var ErrNonceHeld = errors.New("nonce held by another pending transaction from this account; wait for it to be mined, or replace it")
+
+// NonceSource reads an account's next nonce, counting its transactions in
+// the node's mempool, and looks up transactions by hash.
+type NonceSource interface {
+	GetNonce(ctx context.Context, chainName string, addr common.Address) (uint64, error)
+	// TransactionByHash returns ethereum.NotFound for a hash the node has
+	// never seen, or has dropped from its mempool.
+	TransactionByHash(ctx context.Context, chainName string, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// SendFunc signs and broadcasts unsigned, returning the signed transaction.
+type SendFunc func(ctx context.Context, unsigned *types.Transaction) (*types.Transaction, error)
+
+// Queue serializes each account's sends. Nonces are assigned when a
+// transaction is submitted rather than when it is built, one after the
+// other, so several sends prepared in the same turn don't all take the
+// account's pending nonce.
+type Queue struct {
+	Nonces NonceSource
+
+	mu       sync.Mutex
+	accounts map[string]*account
+}
+
+// account is one sender's place in the queue.
+type account struct {
+	mu sync.Mutex
+	// next is the nonce after the last one this queue sent, 0 before any.
+	next uint64
+	// last is the hash of the last transaction this queue sent.
+	last common.Hash
+	// relay is the private relay last was sent through, nil if it went to
+	// the public mempool.
+	relay *chain.PrivateRelay
+}
+
+func (q *Queue) account(chainName string, from common.Address) *account {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.accounts == nil {
+		q.accounts = make(map[string]*account)
+	}
+	key := chainName + "/" + from.Hex()
+	a, ok := q.accounts[key]
+	if !ok {
+		a = &account{}
+		q.accounts[key] = a
+	}
+	return a
+}
+
+// Submit sends unsigned from from after the account's earlier submissions,
+// with the next nonce: the node's pending nonce, or past the last one this
+// queue sent if the node is only lagging behind it. When that transaction
+// went to the public mempool and the node no longer knows it (dropped or
+// replaced elsewhere), its pending nonce is used, so the gap it left is
+// filled rather than queued behind. One sent through a private relay never
+// reaches the node's mempool, so its nonce is only reused once the relay
+// reports it failed or cancelled. relay is the private relay send goes
+// through, nil for the public mempool.
+//
+// When the node rejects a transaction, Submit retries up to MaxResubmits
+// times:
+//   - underpriced (below the node's minimum tip): with the tip raised by
+//     an eighth, but never the max fee the user confirmed, so the tip
+//     stops at it;
+//   - its nonce already mined: with the node's nonce read again, or the
+//     next one if the node still reports it.
+//
+// A max fee below the base fee fails with ErrFeeAboveConfirmed, and a
+// nonce held by a pending transaction sent elsewhere with ErrNonceHeld.
+func (q *Queue) Submit(ctx context.Context, chainName string, from common.Address, unsigned *types.Transaction, relay *chain.PrivateRelay, send SendFunc) (*types.Transaction, error) {
+	a := q.account(chainName, from)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	nonce, err := q.Nonces.GetNonce(ctx, chainName, from)
+	if err != nil {
+		return nil, err
+	}
+	if nonce < a.next && q.dropped(ctx, chainName, a) {
+		slog.Info("last sent transaction dropped; reusing its nonce", "chain", chainName, "from", from.Hex(), "tx", a.last.Hex(), "nonce", nonce)
+		a.next = nonce
+	}
+	nonce = max(nonce, a.next)
+	tip, feeCap := unsigned.GasTipCap(), unsigned.GasFeeCap()
+	for attempt := 0; ; attempt++ {
+		signed, err := send(ctx, withNonceAndFees(unsigned, nonce, tip, feeCap))
+		if err == nil {
+			a.next, a.last, a.relay = nonce+1, signed.Hash(), relay
+			return signed, nil
+		}
+		if attempt == MaxResubmits {
+			return nil, err
+		}
+		switch {
+		case isNonceHeld(err):
+			return nil, fmt.Errorf("%w (nonce %d): %v", ErrNonceHeld, nonce, err)
+		case isNonceMined(err):
+			fresh, nerr := q.Nonces.GetNonce(ctx, chainName, from)
+			if nerr != nil {
+				return nil, err
+			}
+			nonce = max(fresh, nonce+1)
+		case isBelowBaseFee(err):
+			return nil, fmt.Errorf("%w: %v", ErrFeeAboveConfirmed, err)
+		case isUnderpriced(err):
+			bumped := bumpFee(tip)
+			if feeCap != nil && bumped.Cmp(feeCap) > 0 {
+				bumped = new(big.Int).Set(feeCap)
+			}
+			if bumped.Cmp(tip) <= 0 {
+				return nil, fmt.Errorf("%w: %v", ErrFeeAboveConfirmed, err)
+			}
+			tip = bumped
+		default:
+			return nil, err
+		}
+		slog.Info("resubmitting transaction", "chain", chainName, "from", from.Hex(), "nonce", nonce, "tip", tip, "max_fee", feeCap, "err", err)
+	}
+}
+
+// dropped reports whether a's last transaction is gone: unknown to the
+// node if it was sent publicly, failed or cancelled by the relay if it was
+// sent privately. A failed lookup counts as not dropped, keeping the
+// queue's own nonce.
+func (q *Queue) dropped(ctx context.Context, chainName string, a *account) bool {
+	if a.relay != nil {
+		status, err := a.relay.TxStatus(ctx, a.last)
+		if err != nil {
+			return false
+		}
+		return status.Status == chain.PrivateTxFailed || status.Status == chain.PrivateTxCancelled
+	}
+	_, _, err := q.Nonces.TransactionByHash(ctx, chainName, a.last)
+	return errors.Is(err, ethereum.NotFound)
+}
+
+// isNonceMined matches the error nodes return for a nonce that is mined.
+func isNonceMined(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+// isNonceHeld matches the error nodes return for a nonce held by another
+// pending transaction.
+func isNonceHeld(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced")
+}
+
+// isBelowBaseFee matches the error nodes return for a max fee below the
+// block's base fee.
+func isBelowBaseFee(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "less than block base fee")
+}
+
+// isUnderpriced matches the errors nodes return for a tip too low to be
+// accepted.
+func isUnderpriced(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "underpriced") || strings.Contains(msg, "fee too low")
+}
+
+// bumpFee raises a fee by an eighth.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Rsh(fee, 3)
+	bumped.Add(bumped, fee)
+	return bumped.Add(bumped, big.NewInt(1))
+}
+
+// withNonceAndFees returns a copy of tx with another nonce and fees.
+func withNonceAndFees(tx *types.Transaction, nonce uint64, tip, feeCap *big.Int) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:    tx.ChainId(),
+		Nonce:      nonce,
+		GasTipCap:  tip,
+		GasFeeCap:  feeCap,
+		Gas:        tx.Gas(),
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	})
+}
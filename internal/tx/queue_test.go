@@ -0,0 +1,220 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// fakeNonces reports a fixed pending nonce, and knows every transaction
+// unless they were dropped.
+type fakeNonces struct {
+	mu      sync.Mutex
+	nonce   uint64
+	dropped bool
+}
+
+func (f *fakeNonces) GetNonce(context.Context, string, common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nonce, nil
+}
+
+func (f *fakeNonces) TransactionByHash(context.Context, string, common.Hash) (*types.Transaction, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dropped {
+		return nil, false, ethereum.NotFound
+	}
+	return nil, true, nil
+}
+
+func unsignedTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	return types.NewTx(&types.DynamicFeeTx{Nonce: 7, To: &to, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(8e9), Gas: 21000, Value: big.NewInt(1)})
+}
+
+func TestQueue_SequentialNonces(t *testing.T) {
+	// The node lags: it reports nonce 7 until told otherwise.
+	q := &Queue{Nonces: &fakeNonces{nonce: 7}}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	var mu sync.Mutex
+	var sent []uint64
+	send := func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, tx.Nonce())
+		return tx, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			_, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, send)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, []uint64{7, 8, 9, 10, 11}, sent)
+
+	// Another account, or chain, has its own nonces.
+	other, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), other.Nonce())
+}
+
+func TestQueue_Resubmits(t *testing.T) {
+	nonces := &fakeNonces{nonce: 3}
+	q := &Queue{Nonces: nonces}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	var tries []*types.Transaction
+	errs := []error{
+		errors.New("transaction underpriced"),
+		errors.New("nonce too low"),
+		nil,
+	}
+	signed, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		tries = append(tries, tx)
+		return tx, errs[len(tries)-1]
+	})
+	require.NoError(t, err)
+	require.Len(t, tries, 3)
+	assert.Equal(t, uint64(3), tries[0].Nonce())
+	assert.Equal(t, big.NewInt(1_125_000_001), tries[1].GasTipCap(), "underpriced: tip raised by an eighth")
+	assert.Equal(t, big.NewInt(8e9), tries[1].GasFeeCap(), "never above the confirmed max fee")
+	assert.Equal(t, uint64(3), tries[1].Nonce())
+	assert.Equal(t, uint64(4), signed.Nonce(), "nonce mined: the next one")
+
+	_, err = q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(context.Context, *types.Transaction) (*types.Transaction, error) {
+		return nil, errors.New("insufficient funds for gas * price + value")
+	})
+	assert.ErrorContains(t, err, "insufficient funds")
+
+	calls := 0
+	_, err = q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(context.Context, *types.Transaction) (*types.Transaction, error) {
+		calls++
+		return nil, errors.New("transaction underpriced")
+	})
+	assert.ErrorContains(t, err, "underpriced")
+	assert.Equal(t, MaxResubmits+1, calls)
+
+	// A failed send leaves its nonce for the next one.
+	next, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		return tx, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), next.Nonce())
+}
+
+func TestQueue_NeverExceedsConfirmedFee(t *testing.T) {
+	q := &Queue{Nonces: &fakeNonces{nonce: 3}}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	calls := 0
+	_, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(context.Context, *types.Transaction) (*types.Transaction, error) {
+		calls++
+		return nil, errors.New("max fee per gas less than block base fee")
+	})
+	assert.ErrorIs(t, err, ErrFeeAboveConfirmed)
+	assert.Equal(t, 1, calls, "the max fee is not raised past what was confirmed")
+
+	// The tip stops at the max fee.
+	tx := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(7.5e9), GasFeeCap: big.NewInt(8e9), Gas: 21000})
+	var tips []*big.Int
+	_, err = q.Submit(context.Background(), "base", from, tx, nil, func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		tips = append(tips, tx.GasTipCap())
+		return nil, errors.New("transaction underpriced")
+	})
+	assert.ErrorIs(t, err, ErrFeeAboveConfirmed)
+	assert.Equal(t, []*big.Int{big.NewInt(7.5e9), big.NewInt(8e9)}, tips)
+}
+
+func TestQueue_NonceHeldIsReported(t *testing.T) {
+	q := &Queue{Nonces: &fakeNonces{nonce: 3}}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	var nonces []uint64
+	_, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) {
+		nonces = append(nonces, tx.Nonce())
+		return nil, errors.New("replacement transaction underpriced")
+	})
+	assert.ErrorIs(t, err, ErrNonceHeld)
+	assert.ErrorContains(t, err, "nonce 3")
+	assert.Equal(t, []uint64{3}, nonces, "not queued behind the other transaction")
+}
+
+func TestQueue_DroppedTransactionFreesNonce(t *testing.T) {
+	nonces := &fakeNonces{nonce: 3}
+	q := &Queue{Nonces: nonces}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	send := func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) { return tx, nil }
+
+	first, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), first.Nonce())
+
+	// The node lags behind a transaction it knows: the queue's nonce wins.
+	second, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), second.Nonce())
+
+	// It was dropped: the node's pending nonce goes back below the queue's.
+	nonces.dropped = true
+	third, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), third.Nonce(), "no gap left behind the dropped transaction")
+
+	nonces.dropped = false
+	fourth, err := q.Submit(context.Background(), "base", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), fourth.Nonce())
+}
+
+func TestQueue_PrivateTransactionKeepsNonce(t *testing.T) {
+	// The public node never sees a privately sent transaction.
+	nonces := &fakeNonces{nonce: 3, dropped: true}
+	q := &Queue{Nonces: nonces}
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	send := func(_ context.Context, tx *types.Transaction) (*types.Transaction, error) { return tx, nil }
+
+	status := chain.PrivateTxPending
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"` + status + `"}`))
+	}))
+	defer srv.Close()
+	relay := &chain.PrivateRelay{Name: "test", StatusURL: srv.URL + "/tx/"}
+
+	first, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), relay, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), first.Nonce())
+
+	second, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), relay, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), second.Nonce(), "pending at the relay: its nonce is not reused")
+
+	// Without a status API the queue can't tell, so it keeps its nonce.
+	third, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), &chain.PrivateRelay{Name: "none"}, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), third.Nonce())
+	fourth, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), relay, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), fourth.Nonce())
+
+	status = chain.PrivateTxFailed
+	fifth, err := q.Submit(context.Background(), "ethereum", from, unsignedTx(), nil, send)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), fifth.Nonce(), "failed at the relay: its nonce is reused")
+}
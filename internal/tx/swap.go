@@ -0,0 +1,59 @@
+package tx
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Swap protection defaults. Slippage is in basis points (1 bps = 0.01%).
+const (
+	DefaultSlippageBps    = 50  // 0.5%
+	DefaultMaxSlippageBps = 300 // 3%; policy ceiling when none is configured
+	DefaultSwapDeadline   = 20 * time.Minute
+
+	bpsDenominator = 10_000
+)
+
+// SwapLimits are the protections attached to a swap: how far the output may
+// fall below the quote, and how long the transaction stays valid.
+type SwapLimits struct {
+	SlippageBps uint32
+	Deadline    time.Duration
+}
+
+// MinReceived returns the least output a swap with the given slippage may
+// settle for, rounding down so the bound never exceeds the tolerance.
+func MinReceived(expectedOut *big.Int, slippageBps uint32) *big.Int {
+	if expectedOut == nil {
+		return nil
+	}
+	out := new(big.Int).Mul(expectedOut, big.NewInt(int64(bpsDenominator-min(slippageBps, bpsDenominator))))
+	return out.Quo(out, big.NewInt(bpsDenominator))
+}
+
+// DeadlineAt returns the unix timestamp a router should reject the swap after.
+func (l SwapLimits) DeadlineAt(now time.Time) *big.Int {
+	return big.NewInt(now.Add(l.Deadline).Unix())
+}
+
+// ValidateSwapLimits checks requested limits against the policy's slippage
+// ceiling (DefaultMaxSlippageBps when unset).
+func ValidateSwapLimits(limits SwapLimits, policy Policy) error {
+	ceiling := policy.MaxSlippageBps
+	if ceiling == 0 {
+		ceiling = DefaultMaxSlippageBps
+	}
+	if limits.SlippageBps > ceiling {
+		return fmt.Errorf("slippage %s exceeds policy maximum %s", FormatBps(limits.SlippageBps), FormatBps(ceiling))
+	}
+	if limits.Deadline <= 0 {
+		return fmt.Errorf("deadline must be positive")
+	}
+	return nil
+}
+
+// FormatBps renders basis points as a percentage, e.g. 50 -> "0.50%".
+func FormatBps(bps uint32) string {
+	return new(big.Rat).SetFrac64(int64(bps), 100).FloatString(2) + "%"
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CopyTargetStyle marks addresses and hashes that /copy can pick up.
+var CopyTargetStyle lipgloss.Style
+
+// Copy target kinds.
+const (
+	CopyHash    = "hash"
+	CopyAddress = "address"
+)
+
+// CopyTarget is an address or transaction hash found in chat output.
+type CopyTarget struct {
+	Kind  string
+	Value string
+}
+
+// copyTargetPattern matches 32-byte hashes, 20-byte EVM addresses and
+// Cosmos bech32 addresses. Longer hex runs (calldata) don't match.
+var copyTargetPattern = regexp.MustCompile(`\b0x(?:[0-9a-fA-F]{64}|[0-9a-fA-F]{40})\b|\b(?:cosmos|osmo)1[02-9ac-hj-np-z]{38,58}\b`)
+
+// FindCopyTargets returns the addresses and hashes in text in order of
+// appearance, without duplicates.
+func FindCopyTargets(text string) []CopyTarget {
+	var targets []CopyTarget
+	seen := make(map[string]bool)
+	for _, v := range copyTargetPattern.FindAllString(text, -1) {
+		if seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		kind := CopyAddress
+		if len(v) == 66 {
+			kind = CopyHash
+		}
+		targets = append(targets, CopyTarget{Kind: kind, Value: v})
+	}
+	return targets
+}
+
+// MarkCopyTargets renders line in base, with copy targets in
+// CopyTargetStyle. Highlighted lines are returned as they are, and lines
+// with hyperlinks only get base, since styling inside an escape sequence
+// would corrupt it.
+func MarkCopyTargets(line string, base lipgloss.Style) string {
+	if strings.Contains(line, "\x1b[") {
+		// Already highlighted; base would override its colours.
+		return line
+	}
+	if strings.Contains(line, "\x1b") {
+		return base.Render(line)
+	}
+	locs := copyTargetPattern.FindAllStringIndex(line, -1)
+	if len(locs) == 0 {
+		return base.Render(line)
+	}
+	var b strings.Builder
+	prev := 0
+	for _, loc := range locs {
+		if loc[0] > prev {
+			b.WriteString(base.Render(line[prev:loc[0]]))
+		}
+		b.WriteString(CopyTargetStyle.Render(line[loc[0]:loc[1]]))
+		prev = loc[1]
+	}
+	if prev < len(line) {
+		b.WriteString(base.Render(line[prev:]))
+	}
+	return b.String()
+}
+
+// osc52Out receives the OSC 52 fallback sequence.
+var osc52Out io.Writer = os.Stderr
+
+// CopyToClipboard writes text to the system clipboard. Without a native
+// clipboard (headless Linux, SSH sessions) it falls back to OSC 52, which
+// most terminals apply to the local clipboard.
+func CopyToClipboard(text string) error {
+	if !clipboard.Unsupported {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return fmt.Errorf("no clipboard available")
+	}
+	_, err := fmt.Fprintf(osc52Out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
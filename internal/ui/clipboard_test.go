@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCopyTargets(t *testing.T) {
+	addr := "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+	hash := "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	text := "From: " + addr + "\nTx hash: " + hash + "\nTo: " + addr +
+		"\nData: 0xa9059cbb000000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045" +
+		"\nRecipient: cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"
+
+	targets := FindCopyTargets(text)
+	assert.Equal(t, []CopyTarget{
+		{Kind: CopyAddress, Value: addr},
+		{Kind: CopyHash, Value: hash},
+		{Kind: CopyAddress, Value: "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"},
+	}, targets, "duplicates and calldata are skipped")
+}
+
+func TestMarkCopyTargets(t *testing.T) {
+	base := lipgloss.NewStyle()
+	line := "To: 0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045 (vitalik)"
+	assert.Equal(t, line, sgr.ReplaceAllString(MarkCopyTargets(line, base), ""))
+
+	highlighted := "\x1b[38;5;81m0xa9059cbb\x1b[0m"
+	assert.Equal(t, highlighted, MarkCopyTargets(highlighted, base))
+}
+
+func TestCopyToClipboardFallback(t *testing.T) {
+	orig, origOut := clipboard.Unsupported, osc52Out
+	t.Cleanup(func() { clipboard.Unsupported, osc52Out = orig, origOut })
+	clipboard.Unsupported = true
+	var out bytes.Buffer
+	osc52Out = &out
+	t.Setenv("TERM", "xterm")
+
+	require.NoError(t, CopyToClipboard("0xabc"))
+	assert.Equal(t, "\x1b]52;c;MHhhYmM=\a", out.String())
+}
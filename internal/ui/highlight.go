@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// calldataLexer splits ABI-encoded calldata into the 4-byte selector and
+// 32-byte argument words, dimming each word's zero padding so the meaningful
+// bytes stand out.
+var calldataLexer = chroma.MustNewLexer(
+	&chroma.Config{Name: "calldata", Aliases: []string{"calldata"}},
+	func() chroma.Rules {
+		return chroma.Rules{
+			"root": {
+				{Pattern: `0[xX]`, Type: chroma.Punctuation},
+				{Pattern: `[0-9a-fA-F]{8}`, Type: chroma.NameFunction, Mutator: chroma.Push("words")},
+			},
+			"words": {
+				{Pattern: `[0-9a-fA-F]{64}`, Type: chroma.EmitterFunc(emitCalldataWord)},
+				{Pattern: `[0-9a-fA-F]+`, Type: chroma.Error},
+			},
+		}
+	},
+)
+
+func emitCalldataWord(groups []string, _ *chroma.LexerState) chroma.Iterator {
+	word := groups[0]
+	value := strings.TrimLeft(word, "0")
+	if value == "" {
+		return chroma.Literator(chroma.Token{Type: chroma.Comment, Value: word})
+	}
+	// Left-padded values (uints, addresses) keep their digits bright;
+	// right-padded ones (bytesN, strings) are shown as-is.
+	return chroma.Literator(
+		chroma.Token{Type: chroma.Comment, Value: word[:len(word)-len(value)]},
+		chroma.Token{Type: chroma.LiteralNumberHex, Value: value},
+	)
+}
+
+// calldataPattern matches a selector followed by whole 32-byte words, which
+// keeps addresses (20 bytes) and hashes (32 bytes, no selector) out.
+var calldataPattern = regexp.MustCompile(`0x[0-9a-fA-F]{8}(?:[0-9a-fA-F]{64})+\b`)
+
+var (
+	highlightMu        sync.Mutex
+	highlightStyle     *chroma.Style
+	highlightFormatter chroma.Formatter
+)
+
+// colorEnabled mirrors SupportsHyperlinks' escape hatches: NO_COLOR and dumb
+// terminals get plain text.
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+}
+
+func highlighter() (*chroma.Style, chroma.Formatter) {
+	highlightMu.Lock()
+	defer highlightMu.Unlock()
+	if highlightStyle != nil {
+		return highlightStyle, highlightFormatter
+	}
+
+	base := styles.Get("monokai")
+	if !CurrentTheme().IsDark() {
+		base = styles.Get("github")
+	}
+	// Drop the style's background so highlighted code sits on the
+	// terminal's own background like the rest of the chat.
+	style, err := base.Builder().Transform(func(e chroma.StyleEntry) chroma.StyleEntry {
+		e.Background = 0
+		return e
+	}).Build()
+	if err != nil {
+		style = base
+	}
+	highlightStyle = style
+
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		highlightFormatter = formatters.Get("terminal16m")
+	default:
+		highlightFormatter = formatters.Get("terminal256")
+	}
+	return highlightStyle, highlightFormatter
+}
+
+// resetHighlighter drops the cached palette after a theme change.
+func resetHighlighter() {
+	highlightMu.Lock()
+	defer highlightMu.Unlock()
+	highlightStyle = nil
+}
+
+// Highlight renders code in the given language ("json", "solidity",
+// "calldata", ...) with terminal colours. Unknown languages are guessed from
+// the content; code is returned unchanged when colour is disabled or
+// nothing matches.
+func Highlight(code, lang string) string {
+	if !colorEnabled() || strings.TrimSpace(code) == "" {
+		return code
+	}
+	var lexer chroma.Lexer
+	if strings.EqualFold(lang, "calldata") {
+		lexer = calldataLexer
+	} else if lang != "" {
+		lexer = lexers.Get(lang)
+	}
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		return code
+	}
+
+	iter, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err != nil {
+		return code
+	}
+	style, formatter := highlighter()
+	var b strings.Builder
+	if err := formatter.Format(&b, style, iter); err != nil {
+		return code
+	}
+	// Lexers append a newline to unterminated input; keep the caller's.
+	out := b.String()
+	if !strings.HasSuffix(code, "\n") {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return out
+}
+
+// maxJSONLines bounds how far HighlightText looks for the end of a JSON
+// value that starts on a line.
+const maxJSONLines = 500
+
+// HighlightText highlights the code inside free-form tool output: fenced
+// ``` blocks (fences removed), JSON objects and arrays that start a line, and
+// ABI calldata anywhere in a line. Everything else passes through untouched.
+func HighlightText(text string) string {
+	if !colorEnabled() {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			end := i + 1
+			for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+				end++
+			}
+			if end < len(lines) {
+				out = append(out, Highlight(strings.Join(lines[i+1:end], "\n"), lang))
+				i = end
+				continue
+			}
+		}
+
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			if end, ok := jsonEnd(lines, i); ok {
+				out = append(out, Highlight(strings.Join(lines[i:end+1], "\n"), "json"))
+				i = end
+				continue
+			}
+		}
+
+		out = append(out, calldataPattern.ReplaceAllStringFunc(line, func(data string) string {
+			return Highlight(data, "calldata")
+		}))
+	}
+	return strings.Join(out, "\n")
+}
+
+// jsonEnd returns the last line of the JSON value starting at lines[start].
+func jsonEnd(lines []string, start int) (int, bool) {
+	var b strings.Builder
+	for end := start; end < len(lines) && end < start+maxJSONLines; end++ {
+		if end > start {
+			b.WriteString("\n")
+		}
+		b.WriteString(lines[end])
+		if json.Valid([]byte(b.String())) {
+			return end, true
+		}
+	}
+	return 0, false
+}
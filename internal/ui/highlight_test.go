@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sgr = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestHighlightText(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	calldata := "0xa9059cbb" +
+		"000000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045" +
+		"00000000000000000000000000000000000000000000000000000000000f4240"
+
+	t.Run("calldata", func(t *testing.T) {
+		in := "Data: " + calldata
+		out := HighlightText(in)
+		assert.Contains(t, out, "\x1b[")
+		assert.Equal(t, in, sgr.ReplaceAllString(out, ""))
+	})
+
+	t.Run("addresses and hashes are left alone", func(t *testing.T) {
+		in := "To: 0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045\nHash: 0x" + strings.Repeat("ab", 32)
+		assert.Equal(t, in, HighlightText(in))
+	})
+
+	t.Run("fenced code drops the fences", func(t *testing.T) {
+		out := HighlightText("Source:\n```solidity\nfunction f() public {}\n```\ndone")
+		assert.Equal(t, "Source:\nfunction f() public {}\ndone", sgr.ReplaceAllString(out, ""))
+		assert.Contains(t, out, "\x1b[")
+	})
+
+	t.Run("multi-line json", func(t *testing.T) {
+		in := "Result:\n{\n  \"ok\": true\n}\nnot json {"
+		out := HighlightText(in)
+		assert.Equal(t, in, sgr.ReplaceAllString(out, ""))
+		assert.True(t, strings.HasSuffix(out, "\nnot json {"))
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		in := "Data: " + calldata
+		assert.Equal(t, in, HighlightText(in))
+	})
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SupportsHyperlinks reports whether the terminal is known to render OSC-8
+// hyperlinks. Terminals that don't understand OSC-8 usually print the escape
+// sequence as garbage, so detection is allow-list based.
+// CLIFI_HYPERLINKS=1/0 overrides detection.
+func SupportsHyperlinks() bool {
+	if v := os.Getenv("CLIFI_HYPERLINKS"); v != "" {
+		on, err := strconv.ParseBool(v)
+		return err == nil && on
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	// GNOME Terminal and other VTE terminals support OSC-8 since VTE 0.50.
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.Contains(term, "kitty") || strings.Contains(term, "alacritty") || strings.Contains(term, "foot")
+}
+
+// Hyperlink wraps text in an OSC-8 hyperlink to url.
+func Hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the remappable REPL keys. Plain Enter always submits
+// single-line input; Submit keys send any input.
+type KeyMap struct {
+	Submit        key.Binding
+	Newline       key.Binding
+	Cancel        key.Binding
+	Quit          key.Binding
+	HistoryPrev   key.Binding
+	HistoryNext   key.Binding
+	ScrollUp      key.Binding
+	ScrollDown    key.Binding
+	ModelSelector key.Binding
+	KeysHelp      key.Binding
+}
+
+// keyAction ties a config name under `keys:` to its binding.
+type keyAction struct {
+	name string
+	desc string
+	get  func(*KeyMap) *key.Binding
+}
+
+var keyActions = []keyAction{
+	{"submit", "Send input (also Enter on a single line)", func(k *KeyMap) *key.Binding { return &k.Submit }},
+	{"newline", "Insert a newline", func(k *KeyMap) *key.Binding { return &k.Newline }},
+	{"cancel", "Cancel the running request or clear input", func(k *KeyMap) *key.Binding { return &k.Cancel }},
+	{"quit", "Exit clifi", func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"history_prev", "Previous input", func(k *KeyMap) *key.Binding { return &k.HistoryPrev }},
+	{"history_next", "Next input", func(k *KeyMap) *key.Binding { return &k.HistoryNext }},
+	{"scroll_up", "Scroll chat up a page", func(k *KeyMap) *key.Binding { return &k.ScrollUp }},
+	{"scroll_down", "Scroll chat down a page", func(k *KeyMap) *key.Binding { return &k.ScrollDown }},
+	{"model_selector", "Open the model selector", func(k *KeyMap) *key.Binding { return &k.ModelSelector }},
+	{"keys_help", "Show key bindings", func(k *KeyMap) *key.Binding { return &k.KeysHelp }},
+}
+
+// DefaultKeyMap returns the built-in bindings. Newline keys honour
+// CLIFI_NEWLINE_KEYS.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Submit:        key.NewBinding(key.WithKeys("alt+enter")),
+		Newline:       key.NewBinding(key.WithKeys(newlineKeys()...)),
+		Cancel:        key.NewBinding(key.WithKeys("esc")),
+		Quit:          key.NewBinding(key.WithKeys("ctrl+c")),
+		HistoryPrev:   key.NewBinding(key.WithKeys("up")),
+		HistoryNext:   key.NewBinding(key.WithKeys("down")),
+		ScrollUp:      key.NewBinding(key.WithKeys("pgup")),
+		ScrollDown:    key.NewBinding(key.WithKeys("pgdown")),
+		ModelSelector: key.NewBinding(key.WithKeys("ctrl+o")),
+		KeysHelp:      key.NewBinding(key.WithKeys("f1")),
+	}
+}
+
+// LoadKeyMap applies config overrides such as {"submit": "ctrl+s"} to the
+// defaults. Values are comma-separated key names as bubbletea reports them
+// ("ctrl+s", "alt+enter", "pgup"). A key bound to two actions is an error.
+func LoadKeyMap(overrides map[string]string) (KeyMap, error) {
+	km := DefaultKeyMap()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		action, ok := findKeyAction(name)
+		if !ok {
+			return DefaultKeyMap(), fmt.Errorf("unknown key action %q (available: %s)", name, strings.Join(KeyActionNames(), ", "))
+		}
+		var keys []string
+		for _, k := range strings.Split(overrides[name], ",") {
+			if k = strings.TrimSpace(strings.ToLower(k)); k != "" {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return DefaultKeyMap(), fmt.Errorf("keys.%s: no keys given", action.name)
+		}
+		*action.get(&km) = key.NewBinding(key.WithKeys(keys...))
+	}
+
+	owner := make(map[string]string)
+	for _, a := range keyActions {
+		for _, k := range a.get(&km).Keys() {
+			if prev, dup := owner[k]; dup {
+				return DefaultKeyMap(), fmt.Errorf("key %q is bound to both %s and %s", k, prev, a.name)
+			}
+			owner[k] = a.name
+		}
+	}
+	return km, nil
+}
+
+func findKeyAction(name string) (keyAction, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, a := range keyActions {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return keyAction{}, false
+}
+
+// KeyActionNames returns the config names of the remappable actions.
+func KeyActionNames() []string {
+	names := make([]string, len(keyActions))
+	for i, a := range keyActions {
+		names[i] = a.name
+	}
+	return names
+}
+
+// KeyHelp is one row of the key bindings overlay.
+type KeyHelp struct {
+	Action string
+	Keys   string
+	Desc   string
+}
+
+// Help lists the bindings in display order.
+func (k KeyMap) Help() []KeyHelp {
+	rows := make([]KeyHelp, len(keyActions))
+	for i, a := range keyActions {
+		rows[i] = KeyHelp{
+			Action: a.name,
+			Keys:   strings.Join(a.get(&k).Keys(), " / "),
+			Desc:   a.desc,
+		}
+	}
+	return rows
+}
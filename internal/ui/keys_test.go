@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeyMap(t *testing.T) {
+	t.Setenv("CLIFI_NEWLINE_KEYS", "")
+
+	km, err := LoadKeyMap(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alt+enter"}, km.Submit.Keys())
+	assert.Equal(t, []string{"ctrl+j"}, km.Newline.Keys())
+
+	km, err = LoadKeyMap(map[string]string{
+		"submit":       "Ctrl+S, alt+enter",
+		"history_prev": "ctrl+p",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ctrl+s", "alt+enter"}, km.Submit.Keys())
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlP}, km.HistoryPrev))
+	assert.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyUp}, km.HistoryPrev))
+
+	_, err = LoadKeyMap(map[string]string{"launch": "f2"})
+	assert.ErrorContains(t, err, `unknown key action "launch"`)
+
+	_, err = LoadKeyMap(map[string]string{"quit": " , "})
+	assert.ErrorContains(t, err, "keys.quit: no keys given")
+
+	_, err = LoadKeyMap(map[string]string{"model_selector": "esc"})
+	assert.ErrorContains(t, err, `key "esc" is bound to both cancel and model_selector`)
+}
+
+func TestPromptSubmitKeys(t *testing.T) {
+	t.Setenv("CLIFI_NEWLINE_KEYS", "")
+	km, err := LoadKeyMap(map[string]string{"submit": "ctrl+s", "newline": "enter,ctrl+j"})
+	require.NoError(t, err)
+
+	p := NewPrompt()
+	p.SetKeyMap(km)
+	p.Focus()
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi")})
+
+	assert.False(t, p.IsSubmit(tea.KeyMsg{Type: tea.KeyEnter}), "enter bound to newline")
+	assert.False(t, p.IsSubmit(tea.KeyMsg{Type: tea.KeyEnter, Alt: true}))
+	assert.True(t, p.IsSubmit(tea.KeyMsg{Type: tea.KeyCtrlS}))
+}
+
+func TestKeyMapHelp(t *testing.T) {
+	km := DefaultKeyMap()
+	help := km.Help()
+	require.Len(t, help, len(keyActions))
+	assert.Equal(t, "submit", help[0].Action)
+	assert.Equal(t, "alt+enter", help[0].Keys)
+}
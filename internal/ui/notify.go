@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// bellOut receives the terminal bell when no notifier is available.
+var bellOut io.Writer = os.Stderr
+
+// notifyCommand builds the OS notifier invocation. It is a variable so
+// tests can stub it out.
+var notifyCommand = func(title, body string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		return exec.Command("osascript", "-e", script)
+	case "linux", "freebsd", "openbsd":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil
+		}
+		return exec.Command("notify-send", "--app-name=clifi", title, body)
+	case "windows":
+		script := fmt.Sprintf(`[reflection.assembly]::loadwithpartialname('System.Windows.Forms') | Out-Null; `+
+			`$n = New-Object System.Windows.Forms.NotifyIcon; $n.Icon = [System.Drawing.SystemIcons]::Information; `+
+			`$n.Visible = $true; $n.ShowBalloonTip(5000, '%s', '%s', 'Info'); Start-Sleep -s 6; $n.Dispose()`,
+			strings.ReplaceAll(title, "'", "''"), strings.ReplaceAll(body, "'", "''"))
+		return exec.Command("powershell", "-NoProfile", "-Command", script)
+	}
+	return nil
+}
+
+// Notify shows a desktop notification. Where there is no notifier (SSH
+// sessions, headless machines) or it fails, it rings the terminal bell
+// instead.
+func Notify(title, body string) error {
+	if cmd := notifyCommand(title, body); cmd != nil {
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
+	}
+	_, err := io.WriteString(bellOut, "\a")
+	return err
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyFallsBackToBell(t *testing.T) {
+	origCmd, origOut := notifyCommand, bellOut
+	t.Cleanup(func() { notifyCommand, bellOut = origCmd, origOut })
+	var out bytes.Buffer
+	bellOut = &out
+
+	notifyCommand = func(string, string) *exec.Cmd { return nil }
+	require.NoError(t, Notify("title", "body"))
+	assert.Equal(t, "\a", out.String())
+
+	out.Reset()
+	notifyCommand = func(string, string) *exec.Cmd { return exec.Command("false") }
+	require.NoError(t, Notify("title", "body"))
+	assert.Equal(t, "\a", out.String(), "a failing notifier rings the bell")
+}
@@ -1,30 +1,108 @@
 package ui
 
 import (
-	"github.com/charmbracelet/bubbles/textinput"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-// Prompt is a single-line input with a styled prefix
+// maxPromptLines is how tall the prompt grows before it scrolls.
+const maxPromptLines = 8
+
+// defaultNewlineKeys insert a newline without submitting. Most terminals
+// send the same byte for Enter and Shift+Enter, so Shift+Enter works only
+// where the terminal is set to send Ctrl+J (a bare line feed) for it.
+var defaultNewlineKeys = []string{"ctrl+j"}
+
+// Prompt is the chat input. Enter submits single-line input; once the input
+// spans several lines (typed or pasted) Enter adds a line and the Submit
+// keys (Alt+Enter by default) send it. CLIFI_NEWLINE_KEYS (comma-separated,
+// e.g. "ctrl+j,alt+n") overrides the keys that always insert a newline.
 type Prompt struct {
-	input   textinput.Model
+	input   textarea.Model
+	keys    KeyMap
 	width   int
 	focused bool
 }
 
 // NewPrompt creates a new prompt component
 func NewPrompt() Prompt {
-	ti := textinput.New()
-	ti.Prompt = ""
-	ti.Placeholder = ""
-	ti.CharLimit = 2000
-	ti.Width = 80
-
-	return Prompt{
-		input:   ti,
+	ta := textarea.New()
+	ta.Placeholder = ""
+	ta.CharLimit = 20000
+	ta.ShowLineNumbers = false
+	ta.MaxHeight = maxPromptLines
+	ta.SetHeight(1)
+	ta.SetPromptFunc(2, func(lineIdx int) string {
+		if lineIdx == 0 {
+			return SymbolPrompt + " "
+		}
+		return "  "
+	})
+	ta.SetWidth(80)
+
+	p := Prompt{
+		input:   ta,
 		width:   80,
 		focused: true,
 	}
+	p.SetKeyMap(DefaultKeyMap())
+	p.RefreshStyles()
+	return p
+}
+
+// SetKeyMap sets the submit and newline keys.
+func (p *Prompt) SetKeyMap(km KeyMap) {
+	p.keys = km
+	p.input.KeyMap.InsertNewline = key.NewBinding(key.WithKeys(append(km.Newline.Keys(), "enter")...))
+}
+
+// RefreshStyles picks up the active theme's colors.
+func (p *Prompt) RefreshStyles() {
+	p.input.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	p.input.FocusedStyle.Prompt = PromptStyle
+	p.input.BlurredStyle.Prompt = SelectorDim
+}
+
+func newlineKeys() []string {
+	var keys []string
+	for _, k := range strings.Split(os.Getenv("CLIFI_NEWLINE_KEYS"), ",") {
+		if k = strings.TrimSpace(strings.ToLower(k)); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return defaultNewlineKeys
+	}
+	return keys
+}
+
+// IsSubmit reports whether msg should send the input rather than edit it.
+func (p *Prompt) IsSubmit(msg tea.KeyMsg) bool {
+	if msg.Paste {
+		return false
+	}
+	if key.Matches(msg, p.keys.Submit) {
+		return true
+	}
+	if msg.Type != tea.KeyEnter || msg.Alt || key.Matches(msg, p.keys.Newline) {
+		return false
+	}
+	return !p.Multiline()
+}
+
+// Multiline reports whether the input spans more than one line.
+func (p *Prompt) Multiline() bool {
+	return strings.Contains(p.input.Value(), "\n")
+}
+
+// Height returns how many rows the prompt occupies.
+func (p *Prompt) Height() int {
+	return p.input.Height()
 }
 
 // Focus sets focus on the prompt
@@ -47,7 +125,7 @@ func (p *Prompt) Focused() bool {
 // SetWidth sets the width of the input
 func (p *Prompt) SetWidth(w int) {
 	p.width = w
-	p.input.Width = w - 4 // Account for prompt symbol and spacing
+	p.input.SetWidth(w)
 }
 
 // Value returns the current input value
@@ -58,25 +136,29 @@ func (p *Prompt) Value() string {
 // SetValue sets the input value
 func (p *Prompt) SetValue(s string) {
 	p.input.SetValue(s)
+	p.fitHeight()
 }
 
 // Reset clears the input
 func (p *Prompt) Reset() {
 	p.input.Reset()
+	p.fitHeight()
 }
 
 // Update handles input events
 func (p *Prompt) Update(msg tea.Msg) (*Prompt, tea.Cmd) {
 	var cmd tea.Cmd
 	p.input, cmd = p.input.Update(msg)
+	p.fitHeight()
 	return p, cmd
 }
 
+// fitHeight grows or shrinks the input to its line count.
+func (p *Prompt) fitHeight() {
+	p.input.SetHeight(min(p.input.LineCount(), maxPromptLines))
+}
+
 // View renders the prompt
 func (p *Prompt) View() string {
-	style := SelectorDim
-	if p.focused {
-		style = PromptStyle
-	}
-	return style.Render(SymbolPrompt) + " " + p.input.View()
+	return p.input.View()
 }
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromptMultiline(t *testing.T) {
+	t.Setenv("CLIFI_NEWLINE_KEYS", "")
+	p := NewPrompt()
+	p.Focus()
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	altEnter := tea.KeyMsg{Type: tea.KeyEnter, Alt: true}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("send")})
+	assert.True(t, p.IsSubmit(enter), "enter submits single-line input")
+
+	p.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("more")})
+	assert.False(t, p.IsSubmit(enter), "enter adds a line once input is multi-line")
+	assert.True(t, p.IsSubmit(altEnter))
+
+	p.Update(enter)
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0xa\n0xb"), Paste: true})
+	assert.Equal(t, "send\nmore\n0xa\n0xb", p.Value())
+	assert.Equal(t, 4, p.Height())
+
+	p.Reset()
+	assert.Equal(t, 1, p.Height())
+}
+
+func TestNewlineKeys(t *testing.T) {
+	t.Setenv("CLIFI_NEWLINE_KEYS", "Alt+N, ctrl+j")
+	assert.Equal(t, []string{"alt+n", "ctrl+j"}, newlineKeys())
+
+	t.Setenv("CLIFI_NEWLINE_KEYS", "")
+	assert.Equal(t, defaultNewlineKeys, newlineKeys())
+}
@@ -2,9 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // SelectorItem represents an item in the selector
@@ -13,16 +16,30 @@ type SelectorItem struct {
 	Label       string
 	Description string
 	Current     bool
+	// Columns are shown between the label and the description, aligned
+	// across items; see SetColumns.
+	Columns []string
 }
 
-// Selector is an interactive list selector
+// Selector is an interactive list selector. Typing filters the items
+// with fuzzy matching.
 type Selector struct {
 	title    string
 	items    []SelectorItem
-	cursor   int
-	selected int
+	cursor   int // index into visible
+	selected int // index into items
 	active   bool
 	width    int
+	query    string
+	visible  []selectorMatch
+	headers  []string
+}
+
+// selectorMatch is an item that matches the filter, with the label runes
+// that matched it.
+type selectorMatch struct {
+	index     int
+	positions []int
 }
 
 // NewSelector creates a new selector
@@ -36,14 +53,16 @@ func NewSelector(title string, items []SelectorItem) Selector {
 		}
 	}
 
-	return Selector{
+	s := Selector{
 		title:    title,
 		items:    items,
-		cursor:   selected,
 		selected: selected,
 		active:   true,
 		width:    80,
 	}
+	s.filter()
+	s.cursor = selected
+	return s
 }
 
 // SetWidth sets the selector width
@@ -69,6 +88,21 @@ func (s *Selector) Cancelled() bool {
 	return !s.active && s.selected == -1
 }
 
+// SetColumns sets the header row for the items' Columns.
+func (s *Selector) SetColumns(headers ...string) {
+	s.headers = headers
+}
+
+// Query returns the current filter text.
+func (s *Selector) Query() string {
+	return s.query
+}
+
+// Matches returns how many items match the filter.
+func (s *Selector) Matches() int {
+	return len(s.visible)
+}
+
 // Update handles selector input
 func (s *Selector) Update(msg tea.Msg) (*Selector, tea.Cmd) {
 	if !s.active {
@@ -77,27 +111,217 @@ func (s *Selector) Update(msg tea.Msg) (*Selector, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch msg.Type {
+		case tea.KeyUp, tea.KeyCtrlP:
 			if s.cursor > 0 {
 				s.cursor--
 			}
-		case "down", "j":
-			if s.cursor < len(s.items)-1 {
+		case tea.KeyDown, tea.KeyCtrlN:
+			if s.cursor < len(s.visible)-1 {
 				s.cursor++
 			}
-		case "enter":
-			s.selected = s.cursor
-			s.active = false
-		case "esc", "q":
+		case tea.KeyEnter:
+			if s.cursor < len(s.visible) {
+				s.selected = s.visible[s.cursor].index
+				s.active = false
+			}
+		case tea.KeyEsc:
+			// The first esc clears the filter.
+			if s.query != "" {
+				s.SetQuery("")
+				break
+			}
 			s.selected = -1
 			s.active = false
+		case tea.KeyBackspace:
+			if r := []rune(s.query); len(r) > 0 {
+				s.SetQuery(string(r[:len(r)-1]))
+			}
+		case tea.KeyCtrlU:
+			s.SetQuery("")
+		case tea.KeySpace:
+			s.SetQuery(s.query + " ")
+		case tea.KeyRunes:
+			s.SetQuery(s.query + string(msg.Runes))
 		}
 	}
 
 	return s, nil
 }
 
+// SetQuery changes the filter and puts the cursor on the best match.
+func (s *Selector) SetQuery(q string) {
+	s.query = q
+	s.filter()
+	s.cursor = 0
+	if q == "" {
+		s.cursor = s.selectedVisible()
+	}
+}
+
+// selectedVisible returns the visible row of the selected item, or 0.
+func (s *Selector) selectedVisible() int {
+	for i, v := range s.visible {
+		if v.index == s.selected {
+			return i
+		}
+	}
+	return 0
+}
+
+// filter recomputes the visible items for the query, best matches first.
+// Items match on their label, ID or description.
+func (s *Selector) filter() {
+	s.visible = s.visible[:0]
+	if s.query == "" {
+		for i := range s.items {
+			s.visible = append(s.visible, selectorMatch{index: i})
+		}
+		return
+	}
+
+	scores := make(map[int]int)
+	for i, item := range s.items {
+		positions, score, ok := fuzzyMatch(s.query, item.display())
+		if !ok {
+			if _, score, ok = fuzzyMatch(s.query, item.ID); !ok {
+				if _, score, ok = fuzzyMatch(s.query, item.Description); !ok {
+					continue
+				}
+				score -= 1000 // description matches rank last
+			}
+		}
+		scores[i] = score
+		s.visible = append(s.visible, selectorMatch{index: i, positions: positions})
+	}
+	sort.SliceStable(s.visible, func(a, b int) bool {
+		return scores[s.visible[a].index] > scores[s.visible[b].index]
+	})
+}
+
+func (item SelectorItem) display() string {
+	if item.Label != "" {
+		return item.Label
+	}
+	return item.ID
+}
+
+// fuzzyMatch reports whether the runes of query appear in order in text,
+// ignoring case, and the rune positions of text they matched. Substrings
+// score highest, then matches with fewer gaps that start earlier.
+func fuzzyMatch(query, text string) (positions []int, score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 {
+		return nil, 0, true
+	}
+
+	if i := strings.Index(string(t), string(q)); i >= 0 {
+		start := len([]rune(string(t)[:i]))
+		for j := range q {
+			positions = append(positions, start+j)
+		}
+		return positions, 1000 - start, true
+	}
+
+	gaps := 0
+	qi := 0
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			if qi > 0 {
+				gaps++
+			}
+			continue
+		}
+		positions = append(positions, ti)
+		qi++
+	}
+	if qi < len(q) {
+		return nil, 0, false
+	}
+	return positions, 500 - gaps - positions[0], true
+}
+
+// selectorLabelWidth is the width the item labels are padded to.
+const selectorLabelWidth = 35
+
+// fit truncates a row to the selector width so it doesn't wrap.
+func (s *Selector) fit(row string) string {
+	if s.width <= 0 {
+		return row
+	}
+	return ansi.Truncate(row, s.width-1, "…")
+}
+
+// highlightLabel renders label with the runes at positions emphasised,
+// padded to width.
+func highlightLabel(label string, positions []int, width int, base lipgloss.Style) string {
+	runes := []rune(label)
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	runMarked := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMarked {
+			b.WriteString(SelectorMatch.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range runes {
+		if marked[i] != runMarked {
+			flush()
+			runMarked = marked[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	if pad := width - len(runes); pad > 0 {
+		b.WriteString(base.Render(strings.Repeat(" ", pad)))
+	}
+	return b.String()
+}
+
+// columnWidths returns the width of each column across the header and
+// all items.
+func (s *Selector) columnWidths() []int {
+	var widths []int
+	grow := func(cols []string) {
+		for i, c := range cols {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], lipgloss.Width(c))
+		}
+	}
+	grow(s.headers)
+	for _, item := range s.items {
+		grow(item.Columns)
+	}
+	return widths
+}
+
+// formatColumns pads cols to widths, two spaces apart.
+func formatColumns(cols []string, widths []int) string {
+	var b strings.Builder
+	for i, w := range widths {
+		c := ""
+		if i < len(cols) {
+			c = cols[i]
+		}
+		b.WriteString(c + strings.Repeat(" ", w-lipgloss.Width(c)+2))
+	}
+	return b.String()
+}
+
 // View renders the selector
 func (s *Selector) View() string {
 	if !s.active {
@@ -106,27 +330,45 @@ func (s *Selector) View() string {
 
 	var b strings.Builder
 
-	b.WriteString(HelpStyle.Render(s.title + " (↑/↓ navigate, enter select, esc cancel)"))
-	b.WriteString("\n\n")
+	b.WriteString(s.fit(HelpStyle.Render(s.title + " (type to filter, ↑/↓ navigate, enter select, esc cancel)")))
+	b.WriteString("\n")
+	if s.query != "" {
+		b.WriteString(PromptStyle.Render("Filter: ") + s.query)
+		b.WriteString(SelectorDim.Render(fmt.Sprintf("  %d/%d", len(s.visible), len(s.items))))
+	}
+	b.WriteString("\n")
 
-	for i, item := range s.items {
+	if len(s.visible) == 0 {
+		b.WriteString(SelectorDim.Render("  No matches. Backspace or esc to clear the filter."))
+		b.WriteString("\n")
+	}
+
+	widths := s.columnWidths()
+	if len(s.headers) > 0 && len(s.visible) > 0 {
+		header := "  " + strings.Repeat(" ", selectorLabelWidth) + formatColumns(s.headers, widths)
+		b.WriteString(s.fit(SelectorDim.Render(strings.TrimRight(header, " "))))
+		b.WriteString("\n")
+	}
+
+	for i, match := range s.visible {
+		item := s.items[match.index]
 		isCursor := i == s.cursor
 
+		var row strings.Builder
 		if isCursor {
-			b.WriteString(SelectorCursor.Render(SymbolArrow) + " ")
+			row.WriteString(SelectorCursor.Render(SymbolArrow) + " ")
 		} else {
-			b.WriteString("  ")
+			row.WriteString("  ")
 		}
 
-		display := item.Label
-		if display == "" {
-			display = item.ID
-		}
-		label := fmt.Sprintf("%-35s", display)
+		style := SelectorItemStyle
 		if isCursor {
-			b.WriteString(SelectorActive.Render(label))
-		} else {
-			b.WriteString(SelectorItemStyle.Render(label))
+			style = SelectorActive
+		}
+		row.WriteString(highlightLabel(item.display(), match.positions, selectorLabelWidth, style))
+
+		if len(widths) > 0 {
+			row.WriteString(formatColumns(item.Columns, widths))
 		}
 
 		if item.Description != "" {
@@ -134,9 +376,10 @@ func (s *Selector) View() string {
 			if item.Current {
 				desc += " (current)"
 			}
-			b.WriteString(SelectorDim.Render(desc))
+			row.WriteString(SelectorDim.Render(desc))
 		}
 
+		b.WriteString(s.fit(row.String()))
 		b.WriteString("\n")
 	}
 
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typeKeys(s *Selector, text string) {
+	for _, r := range text {
+		s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func visibleIDs(s *Selector) []string {
+	ids := make([]string, len(s.visible))
+	for i, v := range s.visible {
+		ids[i] = s.items[v.index].ID
+	}
+	return ids
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	pos, _, ok := fuzzyMatch("son", "claude-3.5-Sonnet")
+	require.True(t, ok)
+	assert.Equal(t, []int{11, 12, 13}, pos)
+
+	pos, _, ok = fuzzyMatch("c35s", "claude-3.5-sonnet")
+	require.True(t, ok)
+	assert.Equal(t, []int{0, 7, 9, 11}, pos)
+
+	_, _, ok = fuzzyMatch("xyz", "claude-3.5-sonnet")
+	assert.False(t, ok)
+
+	_, substr, _ := fuzzyMatch("gpt", "openai/gpt-4o")
+	_, scattered, _ := fuzzyMatch("gpt", "google/gemini-pro-turbo")
+	assert.Greater(t, substr, scattered)
+}
+
+func TestSelector_Filter(t *testing.T) {
+	s := NewSelector("Select model", []SelectorItem{
+		{ID: "anthropic/claude-sonnet-4"},
+		{ID: "openai/gpt-4o", Current: true},
+		{ID: "google/gemini-2.5-pro", Description: "fast"},
+		{ID: "qwen/qwen3-coder"},
+	})
+	assert.Equal(t, 1, s.cursor, "starts on the current item")
+
+	typeKeys(&s, "qw")
+	assert.Equal(t, "qw", s.Query())
+	assert.Equal(t, []string{"qwen/qwen3-coder"}, visibleIDs(&s))
+	assert.Equal(t, 0, s.cursor)
+
+	s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	s.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeKeys(&s, "fast")
+	assert.Equal(t, []string{"google/gemini-2.5-pro"}, visibleIDs(&s), "descriptions match too")
+
+	// The first esc clears the filter and returns to the current item.
+	s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.True(t, s.Active())
+	assert.Empty(t, s.Query())
+	assert.Len(t, s.visible, 4)
+	assert.Equal(t, 1, s.cursor)
+
+	typeKeys(&s, "zzz")
+	assert.Empty(t, s.visible)
+	s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, s.Active(), "enter does nothing without matches")
+	assert.Contains(t, s.View(), "No matches")
+
+	s.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeKeys(&s, "gem")
+	s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, s.Active())
+	assert.False(t, s.Cancelled())
+	assert.Equal(t, "google/gemini-2.5-pro", s.Selected())
+}
+
+func TestSelector_Cancel(t *testing.T) {
+	s := NewSelector("Select", []SelectorItem{{ID: "a"}, {ID: "b"}})
+	s.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.True(t, s.Cancelled())
+	assert.Empty(t, s.Selected())
+}
+
+func TestHighlightLabel(t *testing.T) {
+	out := highlightLabel("gpt-4o", []int{0, 1}, 10, SelectorItemStyle)
+	assert.Contains(t, out, "gp")
+	assert.Contains(t, out, "t-4o")
+	assert.Equal(t, 10, lipgloss.Width(out))
+}
+
+func TestSelector_Columns(t *testing.T) {
+	s := NewSelector("Select model", []SelectorItem{
+		{ID: "gpt-4o", Columns: []string{"128K", "$2.50/$10.00", SymbolCheck}},
+		{ID: "gpt-3.5-turbo", Columns: []string{"16K", "$0.50/$1.50"}},
+	})
+	s.SetColumns("Context", "$/1M in/out", "Tools")
+	s.SetWidth(200)
+
+	lines := strings.Split(ansi.Strip(s.View()), "\n")
+	require.GreaterOrEqual(t, len(lines), 5)
+	header, first, second := lines[2], lines[3], lines[4]
+	col := func(line, sub string) int { return ansi.StringWidth(line[:strings.Index(line, sub)]) }
+	assert.Equal(t, col(header, "Context"), col(first, "128K"))
+	assert.Equal(t, col(header, "$/1M"), col(second, "$0.50"))
+	assert.Contains(t, first, SymbolCheck)
+
+	s.SetWidth(30)
+	for _, line := range strings.Split(s.View(), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 29)
+	}
+}
+
+func TestSelector_SetQuery(t *testing.T) {
+	s := NewSelector("Select model", []SelectorItem{
+		{ID: "anthropic/claude-sonnet-4", Current: true},
+		{ID: "qwen/qwen3-coder"},
+	})
+	s.SetQuery("qwen")
+	assert.Equal(t, 1, s.Matches())
+	s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "qwen/qwen3-coder", s.Selected())
+
+	s = NewSelector("Select model", []SelectorItem{{ID: "openai/gpt-4o"}})
+	s.SetQuery("zzz")
+	assert.Zero(t, s.Matches())
+}
@@ -2,14 +2,17 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// Colors of the active theme; see ApplyTheme.
 var (
-	ColorPrimary   = lipgloss.Color("205") // Pink/magenta
-	ColorSuccess   = lipgloss.Color("35")  // Green
-	ColorWarning   = lipgloss.Color("214") // Gold/yellow
-	ColorError     = lipgloss.Color("196") // Red
-	ColorDim       = lipgloss.Color("241") // Gray
-	ColorAccent    = lipgloss.Color("39")  // Blue
-	ColorHighlight = lipgloss.Color("212") // Light pink
+	ColorPrimary   lipgloss.Color
+	ColorSuccess   lipgloss.Color
+	ColorWarning   lipgloss.Color
+	ColorError     lipgloss.Color
+	ColorDim       lipgloss.Color
+	ColorAccent    lipgloss.Color
+	ColorHighlight lipgloss.Color
+	ColorText      lipgloss.Color
+	ColorBorder    lipgloss.Color
 )
 
 const (
@@ -24,47 +27,82 @@ const (
 	SymbolTreePipe   = "│"
 )
 
+// Styles built from the active theme's colors; see ApplyTheme.
 var (
+	PromptStyle       lipgloss.Style
+	UserStyle         lipgloss.Style
+	AssistantStyle    lipgloss.Style
+	ToolCallStyle     lipgloss.Style
+	ToolResultStyle   lipgloss.Style
+	ErrorStyle        lipgloss.Style
+	WarningStyle      lipgloss.Style
+	SystemStyle       lipgloss.Style
+	SelectorCursor    lipgloss.Style
+	SelectorItemStyle lipgloss.Style
+	SelectorDim       lipgloss.Style
+	SelectorActive    lipgloss.Style
+	SelectorMatch     lipgloss.Style
+	TitleStyle        lipgloss.Style
+	HelpStyle         lipgloss.Style
+)
+
+func init() {
+	ApplyTheme(DefaultTheme())
+}
+
+func buildStyles() {
 	PromptStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true)
+		Foreground(ColorAccent).
+		Bold(true)
 
 	UserStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent)
+		Foreground(ColorAccent)
 
 	AssistantStyle = lipgloss.NewStyle().
-			Foreground(ColorSuccess)
+		Foreground(ColorSuccess)
 
 	ToolCallStyle = lipgloss.NewStyle().
-			Foreground(ColorWarning)
+		Foreground(ColorWarning)
 
 	ToolResultStyle = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Foreground(ColorDim)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorError)
+		Foreground(ColorError)
+
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(ColorWarning)
 
 	SystemStyle = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Foreground(ColorDim)
 
 	SelectorCursor = lipgloss.NewStyle().
-			Foreground(ColorAccent).
-			Bold(true)
+		Foreground(ColorAccent).
+		Bold(true)
 
 	SelectorItemStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("252"))
+		Foreground(ColorText)
 
 	SelectorDim = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Foreground(ColorDim)
 
 	SelectorActive = lipgloss.NewStyle().
-			Foreground(ColorHighlight).
-			Bold(true)
+		Foreground(ColorHighlight).
+		Bold(true)
+
+	SelectorMatch = lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Bold(true).
+		Underline(true)
 
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
-			Bold(true)
+		Foreground(ColorPrimary).
+		Bold(true)
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorDim)
-)
+		Foreground(ColorDim)
+
+	CopyTargetStyle = lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Underline(true)
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme background modes, which pick the Markdown and code highlighting
+// palettes.
+const (
+	BackgroundAuto  = "auto" // detect from the terminal
+	BackgroundDark  = "dark"
+	BackgroundLight = "light"
+)
+
+// Theme is a named color palette for the REPL, selectors and setup wizard.
+// Colors are ANSI 256 codes ("205") or hex ("#ff5faf").
+type Theme struct {
+	Name       string
+	Background string
+
+	Primary   lipgloss.Color
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Error     lipgloss.Color
+	Dim       lipgloss.Color
+	Accent    lipgloss.Color
+	Highlight lipgloss.Color
+	Text      lipgloss.Color
+	Border    lipgloss.Color
+}
+
+// CustomTheme names a theme built from theme_colors on top of dark.
+const CustomTheme = "custom"
+
+var builtinThemes = []Theme{
+	{
+		Name:       "dark",
+		Background: BackgroundDark,
+		Primary:    "205", // Pink/magenta
+		Success:    "35",  // Green
+		Warning:    "214", // Gold/yellow
+		Error:      "196", // Red
+		Dim:        "241", // Gray
+		Accent:     "39",  // Blue
+		Highlight:  "212", // Light pink
+		Text:       "252",
+		Border:     "62", // Purple
+	},
+	{
+		Name:       "light",
+		Background: BackgroundLight,
+		Primary:    "162",
+		Success:    "28",
+		Warning:    "130",
+		Error:      "160",
+		Dim:        "244",
+		Accent:     "25",
+		Highlight:  "125",
+		Text:       "235",
+		Border:     "61",
+	},
+	{
+		// Bright base colors only, for low-vision use and limited terminals.
+		Name:       "high-contrast",
+		Background: BackgroundDark,
+		Primary:    "13",
+		Success:    "10",
+		Warning:    "11",
+		Error:      "9",
+		Dim:        "7",
+		Accent:     "14",
+		Highlight:  "15",
+		Text:       "15",
+		Border:     "15",
+	},
+}
+
+var currentTheme Theme
+
+// Themes returns the built-in themes.
+func Themes() []Theme {
+	return append([]Theme(nil), builtinThemes...)
+}
+
+// DefaultTheme is used when no theme is configured: the dark palette, with
+// Markdown and code colors still following the terminal background.
+func DefaultTheme() Theme {
+	t := builtinThemes[0]
+	t.Background = BackgroundAuto
+	return t
+}
+
+// CurrentTheme returns the active theme.
+func CurrentTheme() Theme {
+	return currentTheme
+}
+
+// ApplyTheme makes t the active theme and rebuilds the package styles.
+// Components holding copies of styles (prompts, glamour renderers) must be
+// refreshed by their owners.
+func ApplyTheme(t Theme) {
+	currentTheme = t
+	ColorPrimary = t.Primary
+	ColorSuccess = t.Success
+	ColorWarning = t.Warning
+	ColorError = t.Error
+	ColorDim = t.Dim
+	ColorAccent = t.Accent
+	ColorHighlight = t.Highlight
+	ColorText = t.Text
+	ColorBorder = t.Border
+	buildStyles()
+	resetHighlighter()
+}
+
+// LoadTheme resolves a configured theme name ("" for the default) and
+// applies per-color overrides such as {"accent": "#00afff"}. The "custom"
+// theme is dark plus the overrides.
+func LoadTheme(name string, overrides map[string]string) (Theme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var t Theme
+	switch name {
+	case "":
+		t = DefaultTheme()
+	case CustomTheme:
+		t = builtinThemes[0]
+		t.Name = CustomTheme
+	default:
+		found := false
+		for _, bt := range builtinThemes {
+			if bt.Name == name {
+				t, found = bt, true
+				break
+			}
+		}
+		if !found {
+			return Theme{}, fmt.Errorf("unknown theme %q (available: %s, %s)", name, strings.Join(ThemeNames(), ", "), CustomTheme)
+		}
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := t.set(k, overrides[k]); err != nil {
+			return Theme{}, err
+		}
+	}
+	return t, nil
+}
+
+// ThemeNames returns the built-in theme names.
+func ThemeNames() []string {
+	names := make([]string, len(builtinThemes))
+	for i, t := range builtinThemes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func (t *Theme) set(key, value string) error {
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if key == "background" {
+		switch value {
+		case BackgroundAuto, BackgroundDark, BackgroundLight:
+			t.Background = value
+			return nil
+		}
+		return fmt.Errorf("theme_colors.background must be auto, dark or light, got %q", value)
+	}
+
+	if n, err := strconv.Atoi(value); (err != nil || n < 0 || n > 255) && !hexColor.MatchString(value) {
+		return fmt.Errorf("theme_colors.%s: %q is not an ANSI color (0-255) or #hex color", key, value)
+	}
+	c := lipgloss.Color(value)
+	switch key {
+	case "primary":
+		t.Primary = c
+	case "success":
+		t.Success = c
+	case "warning":
+		t.Warning = c
+	case "error":
+		t.Error = c
+	case "dim":
+		t.Dim = c
+	case "accent":
+		t.Accent = c
+	case "highlight":
+		t.Highlight = c
+	case "text":
+		t.Text = c
+	case "border":
+		t.Border = c
+	default:
+		return fmt.Errorf("unknown theme color %q", key)
+	}
+	return nil
+}
+
+// IsDark reports whether Markdown and code should use dark-background
+// palettes.
+func (t Theme) IsDark() bool {
+	switch t.Background {
+	case BackgroundDark:
+		return true
+	case BackgroundLight:
+		return false
+	}
+	return lipgloss.HasDarkBackground()
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTheme(t *testing.T) {
+	def, err := LoadTheme("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTheme(), def)
+	assert.Equal(t, BackgroundAuto, def.Background)
+
+	light, err := LoadTheme(" Light ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "light", light.Name)
+	assert.Equal(t, BackgroundLight, light.Background)
+
+	custom, err := LoadTheme("custom", map[string]string{
+		"accent":     "#00afff",
+		"Primary":    "99",
+		"background": "light",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CustomTheme, custom.Name)
+	assert.Equal(t, lipgloss.Color("#00afff"), custom.Accent)
+	assert.Equal(t, lipgloss.Color("99"), custom.Primary)
+	assert.Equal(t, BackgroundLight, custom.Background)
+	assert.Equal(t, builtinThemes[0].Success, custom.Success)
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	_, err := LoadTheme("solarized", nil)
+	assert.ErrorContains(t, err, `unknown theme "solarized"`)
+
+	for _, tc := range []struct {
+		key, value, want string
+	}{
+		{"accent", "256", "not an ANSI color"},
+		{"accent", "blue", "not an ANSI color"},
+		{"accent", "#12345", "not an ANSI color"},
+		{"shadow", "1", `unknown theme color "shadow"`},
+		{"background", "sepia", "must be auto, dark or light"},
+	} {
+		_, err := LoadTheme("dark", map[string]string{tc.key: tc.value})
+		assert.ErrorContains(t, err, tc.want, "%s=%s", tc.key, tc.value)
+	}
+}
+
+func TestApplyTheme(t *testing.T) {
+	t.Cleanup(func() { ApplyTheme(DefaultTheme()) })
+
+	hc, err := LoadTheme("high-contrast", nil)
+	require.NoError(t, err)
+	ApplyTheme(hc)
+
+	assert.Equal(t, "high-contrast", CurrentTheme().Name)
+	assert.Equal(t, hc.Primary, ColorPrimary)
+	assert.Equal(t, hc.Border, ColorBorder)
+	assert.Equal(t, lipgloss.TerminalColor(hc.Error), ErrorStyle.GetForeground())
+	assert.Equal(t, lipgloss.TerminalColor(hc.Accent), CopyTargetStyle.GetForeground())
+	assert.True(t, CurrentTheme().IsDark())
+}
@@ -0,0 +1,350 @@
+package wallet
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// backupVersion is the archive layout Backup writes and Restore reads.
+const backupVersion = 1
+
+// manifestFile describes a backup archive; it comes first.
+const manifestFile = "manifest.json"
+
+// keyDirs are the data dir's keystore directories: EVM, Solana and Cosmos.
+// Their files are encrypted with the wallet passwords.
+var keyDirs = []string{"keystore", "solana", "cosmos"}
+
+// externalDir holds a read-write wallet.keystore_dir's files in a backup.
+// They are restored to wherever new EVM wallets go.
+const externalDir = "external"
+
+// archiveDirs are the key directories a backup may hold.
+var archiveDirs = append(append([]string{}, keyDirs...), externalDir)
+
+// backupSources returns the directories Backup reads, by archive
+// directory: dataDir's keyDirs, and a read-write wallet.keystore_dir as
+// externalDir. A read-only one is left to whoever writes it.
+func backupSources(dataDir string) (map[string]string, error) {
+	src := make(map[string]string)
+	for _, dir := range keyDirs {
+		src[dir] = filepath.Join(dataDir, dir)
+	}
+	ext, ok, err := ConfiguredExternalKeystore()
+	if err != nil {
+		return nil, err
+	}
+	if ok && !ext.ReadOnly && filepath.Clean(ext.Dir) != filepath.Clean(src["keystore"]) {
+		src[externalDir] = ext.Dir
+	}
+	return src, nil
+}
+
+// BackupManifest describes a backup's contents.
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	// Files lists the key files by directory, e.g. keystore, solana.
+	Files map[string][]string `json:"files"`
+	// Book holds the wallet labels and default wallet.
+	Book bool `json:"book"`
+}
+
+// Keys counts the key files.
+func (m BackupManifest) Keys() int {
+	n := 0
+	for _, files := range m.Files {
+		n += len(files)
+	}
+	return n
+}
+
+// Backup writes an age archive of dataDir's keystore files, for every
+// chain family, a read-write wallet.keystore_dir's, and the wallet labels,
+// encrypted with passphrase. The key files stay encrypted with their own
+// passwords inside it.
+func Backup(dataDir string, w io.Writer, passphrase string) (BackupManifest, error) {
+	m := BackupManifest{Version: backupVersion, CreatedAt: time.Now().UTC(), Files: make(map[string][]string)}
+	sources, err := backupSources(dataDir)
+	if err != nil {
+		return m, err
+	}
+	for _, dir := range archiveDirs {
+		if sources[dir] == "" {
+			continue
+		}
+		entries, err := os.ReadDir(sources[dir])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return m, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+				m.Files[dir] = append(m.Files[dir], e.Name())
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, walletsFile)); err == nil {
+		m.Book = true
+	}
+	if m.Keys() == 0 {
+		return m, ErrNoAccounts
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return m, err
+	}
+	// The archive is as hard to brute-force as the key files themselves.
+	recipient.SetWorkFactor(bits.Len(uint(ConfiguredScrypt().N)) - 1)
+	enc, err := age.Encrypt(w, recipient)
+	if err != nil {
+		return m, fmt.Errorf("failed to encrypt backup: %w", err)
+	}
+	tw := tar.NewWriter(enc)
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	if err := addTarFile(tw, manifestFile, manifest); err != nil {
+		return m, err
+	}
+	// entry is a file and its name in the archive.
+	type entry struct{ name, file string }
+	var entries []entry
+	for _, dir := range archiveDirs {
+		for _, f := range m.Files[dir] {
+			entries = append(entries, entry{path.Join(dir, f), filepath.Join(sources[dir], f)})
+		}
+	}
+	if m.Book {
+		entries = append(entries, entry{walletsFile, filepath.Join(dataDir, walletsFile)})
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(e.file)
+		if err != nil {
+			return m, fmt.Errorf("failed to read %s: %w", e.name, err)
+		}
+		if err := addTarFile(tw, e.name, data); err != nil {
+			return m, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return m, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return m, fmt.Errorf("failed to encrypt backup: %w", err)
+	}
+	return m, nil
+}
+
+func addTarFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// RestoreResult is what Restore did with a backup's files.
+type RestoreResult struct {
+	Manifest BackupManifest
+	// Restored and Skipped list key files as dir/name; a file is skipped
+	// when the data dir already has its wallet.
+	Restored []string
+	Skipped  []string
+	// Labels counts the labels taken from the backup.
+	Labels int
+}
+
+// Restore decrypts a Backup archive with passphrase and adds its key files
+// to dataDir. Wallets dataDir already has are left alone, as are its
+// labels and default wallet; the backup's fill in what is missing.
+func Restore(dataDir string, r io.Reader, passphrase string) (RestoreResult, error) {
+	var res RestoreResult
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return res, err
+	}
+	dec, err := age.Decrypt(r, identity)
+	if err != nil {
+		return res, fmt.Errorf("failed to decrypt backup (wrong passphrase?): %w", err)
+	}
+	tr := tar.NewReader(dec)
+
+	// Read everything before writing anything, so a damaged archive
+	// restores nothing.
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return res, fmt.Errorf("failed to read backup: %w", err)
+		}
+		if !validBackupName(hdr.Name) {
+			return res, fmt.Errorf("unexpected file %q in backup", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return res, fmt.Errorf("failed to read backup: %w", err)
+		}
+		files[hdr.Name] = data
+	}
+	manifest, ok := files[manifestFile]
+	if !ok {
+		return res, errors.New("not a clifi wallet backup: no manifest")
+	}
+	if err := json.Unmarshal(manifest, &res.Manifest); err != nil {
+		return res, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+	if res.Manifest.Version > backupVersion {
+		return res, fmt.Errorf("backup version %d is newer than this clifi's %d; upgrade clifi", res.Manifest.Version, backupVersion)
+	}
+
+	km, err := NewKeystoreManager(dataDir)
+	if err != nil {
+		return res, err
+	}
+	for _, dir := range archiveDirs {
+		for _, name := range res.Manifest.Files[dir] {
+			key := path.Join(dir, name)
+			data, ok := files[key]
+			if !ok {
+				return res, fmt.Errorf("backup lists %s but lacks it", key)
+			}
+			dst := filepath.Join(dataDir, dir, name)
+			if dir == externalDir {
+				dst = filepath.Join(km.dirs[0].dir, name)
+			}
+			evm := dir == "keystore" || dir == externalDir
+			if _, err := os.Stat(dst); err == nil || (evm && km.hasKeyFile(data)) {
+				res.Skipped = append(res.Skipped, key)
+				continue
+			}
+			if err := writeFileAtomic(dst, data); err != nil {
+				return res, err
+			}
+			res.Restored = append(res.Restored, key)
+		}
+	}
+
+	if data, ok := files[walletsFile]; ok {
+		var backup walletBook
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return res, fmt.Errorf("failed to parse %s in backup: %w", walletsFile, err)
+		}
+		if res.Labels, err = km.mergeBook(&backup); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// validBackupName accepts the manifest, the wallet book and files directly
+// inside a key directory, so a crafted archive can't write elsewhere.
+func validBackupName(name string) bool {
+	if name == manifestFile || name == walletsFile {
+		return true
+	}
+	dir, file := path.Split(name)
+	if file == "" || file != path.Base(file) || strings.HasPrefix(file, ".") {
+		return false
+	}
+	for _, d := range archiveDirs {
+		if dir == d+"/" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKeyFile reports whether the keystore already holds the EVM key file
+// data's address, whatever the file is called.
+func (km *KeystoreManager) hasKeyFile(data []byte) bool {
+	var f struct {
+		Address string `json:"address"`
+	}
+	if json.Unmarshal(data, &f) != nil || !common.IsHexAddress(f.Address) {
+		return false
+	}
+	return km.HasAccount(common.HexToAddress(f.Address))
+}
+
+// mergeBook adds the backup's labels for wallets that have none and whose
+// label is free, and its default when none is set. It returns how many
+// labels it added.
+func (km *KeystoreManager) mergeBook(backup *walletBook) (int, error) {
+	book, err := km.loadBook()
+	if err != nil {
+		return 0, err
+	}
+	used := make(map[string]bool)
+	for _, l := range book.Labels {
+		used[strings.ToLower(l)] = true
+	}
+	added := 0
+	for addr, label := range backup.Labels {
+		if book.Labels[addr] != "" || used[strings.ToLower(label)] || ValidateLabel(label) != nil {
+			continue
+		}
+		book.Labels[addr] = label
+		used[strings.ToLower(label)] = true
+		added++
+	}
+	changed := added > 0
+	if book.Default == "" && backup.Default != "" {
+		book.Default = backup.Default
+		changed = true
+	}
+	if !changed {
+		return 0, nil
+	}
+	return added, km.saveBook(book)
+}
+
+// writeFileAtomic writes a key file through a temporary file, so it is
+// never seen half-written.
+func writeFileAtomic(dst string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dst)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
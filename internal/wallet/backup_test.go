@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func TestBackupRestore(t *testing.T) {
+	src := testutil.TempDir(t)
+	km, err := NewKeystoreManager(src)
+	require.NoError(t, err)
+	first, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	second, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	require.NoError(t, km.SetLabel(first.Address, "trading"))
+	require.NoError(t, km.SetDefault(second.Address))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "solana"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "solana", "Abc.json"), []byte(`{}`), 0600))
+
+	var archive bytes.Buffer
+	manifest, err := Backup(src, &archive, "backup-pass")
+	require.NoError(t, err)
+	assert.Equal(t, 3, manifest.Keys())
+	assert.True(t, manifest.Book)
+	assert.NotContains(t, archive.String(), first.Address.Hex()[2:], "the archive is encrypted")
+
+	_, err = Restore(testutil.TempDir(t), bytes.NewReader(archive.Bytes()), "wrong-pass")
+	assert.ErrorContains(t, err, "wrong passphrase")
+
+	// The new machine already has the second wallet, labelled differently.
+	dst := testutil.TempDir(t)
+	secondFile, err := os.ReadFile(second.URL.Path)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dst, "keystore"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "keystore", "renamed.json"), secondFile, 0600))
+	existing, err := NewKeystoreManager(dst)
+	require.NoError(t, err)
+	require.NoError(t, existing.SetLabel(second.Address, "savings"))
+
+	res, err := Restore(dst, bytes.NewReader(archive.Bytes()), "backup-pass")
+	require.NoError(t, err)
+	assert.Len(t, res.Restored, 2)
+	assert.Equal(t, []string{"keystore/" + filepath.Base(second.URL.Path)}, res.Skipped)
+	assert.Equal(t, 1, res.Labels)
+
+	restored, err := NewKeystoreManager(dst)
+	require.NoError(t, err)
+	assert.Len(t, restored.ListAccounts(), 2)
+	_, err = restored.GetSigner(first.Address, "password123")
+	assert.NoError(t, err, "key files keep their passwords")
+	assert.Equal(t, "trading", restored.Label(first.Address))
+	assert.Equal(t, "savings", restored.Label(second.Address), "existing labels win")
+	def, err := restored.DefaultAccount()
+	require.NoError(t, err)
+	assert.Equal(t, second.Address, def.Address)
+	_, err = os.Stat(filepath.Join(dst, "solana", "Abc.json"))
+	assert.NoError(t, err)
+}
+
+func TestBackupExternalKeystore(t *testing.T) {
+	ext := testutil.TempDir(t)
+	setExternalKeystore(t, ext, "read-write")
+	src := testutil.TempDir(t)
+	km, err := NewKeystoreManager(src)
+	require.NoError(t, err)
+	acc, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	require.Equal(t, ext, filepath.Dir(acc.URL.Path), "created in the external keystore")
+
+	var archive bytes.Buffer
+	manifest, err := Backup(src, &archive, "backup-pass")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Base(acc.URL.Path)}, manifest.Files[externalDir])
+
+	// Restored to wherever new wallets go: here the data dir.
+	setExternalKeystore(t, "", "")
+	dst := testutil.TempDir(t)
+	res, err := Restore(dst, bytes.NewReader(archive.Bytes()), "backup-pass")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"external/" + filepath.Base(acc.URL.Path)}, res.Restored)
+	restored, err := NewKeystoreManager(dst)
+	require.NoError(t, err)
+	assert.True(t, restored.HasAccount(acc.Address))
+
+	// A read-only one isn't clifi's to back up.
+	setExternalKeystore(t, ext, "read-only")
+	_, err = Backup(src, &bytes.Buffer{}, "backup-pass")
+	assert.ErrorIs(t, err, ErrNoAccounts)
+}
+
+func TestBackup_NoWallets(t *testing.T) {
+	var archive bytes.Buffer
+	_, err := Backup(testutil.TempDir(t), &archive, "backup-pass")
+	assert.ErrorIs(t, err, ErrNoAccounts)
+}
+
+func TestValidBackupName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"manifest.json":          true,
+		"wallets.json":           true,
+		"keystore/UTC--2026-abc": true,
+		"cosmos/ab12.json":       true,
+		"external/UTC--2026-def": true,
+		"../keystore/x":          false,
+		"keystore/../../etc/x":   false,
+		"keystore/sub/x":         false,
+		"keystore/.x.tmp":        false,
+		"/keystore/x":            false,
+		"config.yaml":            false,
+	} {
+		assert.Equal(t, want, validBackupName(name), name)
+	}
+}
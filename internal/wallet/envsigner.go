@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The environment signer is for testnet CI: a private key handed to the
+// process in an environment variable, or on a file descriptor, that
+// signs without a password and is never written to disk. It is UNSAFE
+// for real funds, since anything that can read the job's environment
+// has the key, and so refuses mainnets unless EnvSignerAllowMainnet is
+// set too.
+const (
+	// EnvSignerKey holds the private key as hex.
+	EnvSignerKey = "CLIFI_SIGNER_KEY"
+	// EnvSignerKeyFD names an open file descriptor to read the key from,
+	// e.g. 3 with 3< <(vault read ...), keeping it out of the environment.
+	EnvSignerKeyFD = "CLIFI_SIGNER_KEY_FD"
+	// EnvSignerAllowMainnet, set to 1, lets the environment signer sign
+	// on mainnets.
+	EnvSignerAllowMainnet = "CLIFI_SIGNER_ALLOW_MAINNET"
+)
+
+// ErrEnvSignerMainnet is returned when the environment signer would sign
+// on a mainnet without EnvSignerAllowMainnet.
+var ErrEnvSignerMainnet = fmt.Errorf("the environment signer (%s) is for testnets; set %s=1 to use it on mainnet at your own risk", EnvSignerKey, EnvSignerAllowMainnet)
+
+var (
+	envSignerOnce sync.Once
+	envSignerKey  *ecdsa.PrivateKey
+	envSignerErr  error
+)
+
+// loadEnvSigner reads the environment signer's key, once per process:
+// the file descriptor can only be read once, and EnvSignerKey is unset
+// after so that commands clifi runs don't inherit it. It returns nil
+// when neither is set.
+func loadEnvSigner() (*ecdsa.PrivateKey, error) {
+	envSignerOnce.Do(func() {
+		var raw string
+		switch {
+		case os.Getenv(EnvSignerKeyFD) != "":
+			fd, err := strconv.Atoi(os.Getenv(EnvSignerKeyFD))
+			if err != nil || fd < 0 {
+				envSignerErr = fmt.Errorf("%s: not a file descriptor: %q", EnvSignerKeyFD, os.Getenv(EnvSignerKeyFD))
+				return
+			}
+			f := os.NewFile(uintptr(fd), "signer-key")
+			data, err := io.ReadAll(io.LimitReader(f, 1024))
+			_ = f.Close()
+			if err != nil {
+				envSignerErr = fmt.Errorf("%s: %w", EnvSignerKeyFD, err)
+				return
+			}
+			raw = string(data)
+		case os.Getenv(EnvSignerKey) != "":
+			raw = os.Getenv(EnvSignerKey)
+			_ = os.Unsetenv(EnvSignerKey)
+		default:
+			return
+		}
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(raw), "0x"))
+		if err != nil {
+			envSignerErr = errors.New("environment signer: invalid private key")
+			return
+		}
+		envSignerKey = key
+		slog.Warn("environment signer in use: for testnet automation only, unsafe for real funds", "address", crypto.PubkeyToAddress(key.PublicKey).Hex())
+	})
+	return envSignerKey, envSignerErr
+}
+
+// EnvSignerMainnetAllowed reports whether EnvSignerAllowMainnet is set.
+func EnvSignerMainnetAllowed() bool {
+	return os.Getenv(EnvSignerAllowMainnet) == "1"
+}
+
+// envAccount is the environment signer's account; its URL shows where
+// the key came from.
+func envAccount(key *ecdsa.PrivateKey) accounts.Account {
+	return accounts.Account{
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+		URL:     accounts.URL{Scheme: "env", Path: EnvSignerKey},
+	}
+}
+
+// EnvSigner returns the environment signer's address, if one was given.
+func (km *KeystoreManager) EnvSigner() (common.Address, bool) {
+	if km.env == nil {
+		return common.Address{}, false
+	}
+	return envAccount(km.env).Address, true
+}
+
+// IsEnvSigner reports whether address is the environment signer's.
+func (km *KeystoreManager) IsEnvSigner(address common.Address) bool {
+	env, ok := km.EnvSigner()
+	return ok && env == address
+}
+
+// NeedsPassword reports whether address signs with a keystore password,
+// unlike the environment signer and the KMS keys.
+func (km *KeystoreManager) NeedsPassword(address common.Address) bool {
+	return !km.IsEnvSigner(address) && !km.IsKMS(address)
+}
+
+// envSigner returns a signer over a copy of the environment key, which
+// the caller may Lock without losing the key for later signers.
+func (km *KeystoreManager) envSigner() (*KeystoreSigner, error) {
+	key, err := crypto.ToECDSA(crypto.FromECDSA(km.env))
+	if err != nil {
+		return nil, err
+	}
+	return &KeystoreSigner{account: envAccount(km.env), key: key}, nil
+}
@@ -0,0 +1,96 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+// resetEnvSigner forgets the loaded environment signer, before and after
+// the test.
+func resetEnvSigner(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		envSignerOnce = sync.Once{}
+		envSignerKey, envSignerErr = nil, nil
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestEnvSigner(t *testing.T) {
+	resetEnvSigner(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	t.Setenv(EnvSignerKey, "0x"+hex.EncodeToString(crypto.FromECDSA(key)))
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	_, stillSet := os.LookupEnv(EnvSignerKey)
+	assert.False(t, stillSet, "the key is taken out of the environment")
+
+	_, err = km.CreateAccount("password123")
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 2)
+	assert.Equal(t, addr, accs[0].Address, "the environment signer comes first")
+	assert.True(t, km.IsEnvSigner(addr))
+	assert.True(t, km.HasAccount(addr))
+
+	signer, err := km.GetSigner(addr, "")
+	require.NoError(t, err)
+	signer.Lock()
+	// Locking one signer leaves the key for the next.
+	signer, err = km.GetSigner(addr, "")
+	require.NoError(t, err)
+	_, err = signer.SignMessage([]byte("hello"))
+	require.NoError(t, err)
+
+	assert.Error(t, km.ChangePassword(addr, "", "new-pass-123"))
+	_, err = km.ImportKey(hex.EncodeToString(crypto.FromECDSA(key)), "password123")
+	assert.Error(t, err, "the key is not copied into the keystore")
+}
+
+var pipes []*os.File
+
+func TestEnvSignerFromFD(t *testing.T) {
+	resetEnvSigner(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = w.WriteString(hex.EncodeToString(crypto.FromECDSA(key)) + "\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	// loadEnvSigner closes the descriptor itself; r is kept reachable so
+	// its finalizer never closes the number again once it is reused.
+	pipes = append(pipes, r)
+	t.Setenv(EnvSignerKeyFD, strconv.Itoa(int(r.Fd())))
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	assert.True(t, km.IsEnvSigner(crypto.PubkeyToAddress(key.PublicKey)))
+}
+
+func TestEnvSignerInvalidKey(t *testing.T) {
+	resetEnvSigner(t)
+	t.Setenv(EnvSignerKey, "not-a-key")
+	_, err := NewKeystoreManager(testutil.TempDir(t))
+	assert.ErrorContains(t, err, "environment signer")
+}
+
+func TestNoEnvSigner(t *testing.T) {
+	resetEnvSigner(t)
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	_, ok := km.EnvSigner()
+	assert.False(t, ok)
+}
@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// ErrReadOnlyKeystore is returned for changes to a wallet in a read-only
+// external keystore.
+var ErrReadOnlyKeystore = errors.New("wallet is in a read-only keystore (wallet.keystore_mode)")
+
+// ExternalKeystore is a geth-style keystore directory outside the data
+// dir, set with wallet.keystore_dir, whose wallets clifi uses in place
+// instead of importing them.
+type ExternalKeystore struct {
+	Dir string
+	// ReadOnly keeps clifi from writing to Dir, the default: new wallets
+	// go to the data dir and the external ones' passwords can't be
+	// changed. Otherwise new wallets are created in Dir.
+	ReadOnly bool
+}
+
+// ParseKeystoreMode parses a wallet.keystore_mode setting, read-only
+// (the default) or read-write, and reports whether it is read-only.
+func ParseKeystoreMode(v string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "read-only", "ro":
+		return true, nil
+	case "read-write", "rw":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected read-only or read-write, got %q", v)
+}
+
+// ConfiguredExternalKeystore returns the wallet.keystore_dir and
+// wallet.keystore_mode settings; ok is false when no directory is set.
+func ConfiguredExternalKeystore() (ext ExternalKeystore, ok bool, err error) {
+	dir := strings.TrimSpace(viper.GetString("wallet.keystore_dir"))
+	if dir == "" {
+		return ExternalKeystore{}, false, nil
+	}
+	if dir, err = ResolveKeystoreDir(dir); err != nil {
+		return ExternalKeystore{}, false, fmt.Errorf("wallet.keystore_dir: %w", err)
+	}
+	readOnly, err := ParseKeystoreMode(viper.GetString("wallet.keystore_mode"))
+	if err != nil {
+		return ExternalKeystore{}, false, fmt.Errorf("wallet.keystore_mode: %w", err)
+	}
+	return ExternalKeystore{Dir: dir, ReadOnly: readOnly}, true, nil
+}
+
+// ResolveKeystoreDir expands a leading ~ in dir to the home directory and
+// checks that it is an existing directory, as an external keystore must
+// be: clifi never creates one.
+func ResolveKeystoreDir(dir string) (string, error) {
+	if rest, found := strings.CutPrefix(dir, "~"); found && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, rest)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// keyDir is one keystore directory the manager reads wallets from.
+type keyDir struct {
+	ks       *keystore.KeyStore
+	dir      string
+	readOnly bool
+}
+
+// find returns the directory holding address's key, searched in order.
+func (km *KeystoreManager) find(address common.Address) (keyDir, accounts.Account, error) {
+	for _, d := range km.dirs {
+		if acc, err := d.ks.Find(accounts.Account{Address: address}); err == nil {
+			return d, acc, nil
+		}
+	}
+	return keyDir{}, accounts.Account{}, ErrAccountNotFound
+}
+
+// ReadOnly reports whether address's key is in a read-only keystore.
+func (km *KeystoreManager) ReadOnly(address common.Address) bool {
+	d, _, err := km.find(address)
+	return err == nil && d.readOnly
+}
+
+// KeyDir returns the directory holding address's key file.
+func (km *KeystoreManager) KeyDir(address common.Address) (string, error) {
+	d, _, err := km.find(address)
+	return d.dir, err
+}
@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+// setExternalKeystore points wallet.keystore_dir at dir for one test.
+func setExternalKeystore(t *testing.T, dir, mode string) {
+	t.Helper()
+	viper.Set("wallet.keystore_dir", dir)
+	viper.Set("wallet.keystore_mode", mode)
+	t.Cleanup(func() {
+		viper.Set("wallet.keystore_dir", "")
+		viper.Set("wallet.keystore_mode", "")
+	})
+}
+
+// gethKeystore returns a keystore directory outside any clifi data dir
+// holding one wallet, and that wallet's key.
+func gethKeystore(t *testing.T) (string, string) {
+	t.Helper()
+	dir := filepath.Join(testutil.TempDir(t), "keystore")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	_, err = ks.ImportECDSA(key, "geth-pass")
+	require.NoError(t, err)
+	return dir, hex.EncodeToString(crypto.FromECDSA(key))
+}
+
+func TestExternalKeystoreReadOnly(t *testing.T) {
+	ext, key := gethKeystore(t)
+	setExternalKeystore(t, ext, "")
+	dataDir := testutil.TempDir(t)
+
+	km, err := NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1)
+	external := accs[0].Address
+	assert.True(t, km.ReadOnly(external))
+
+	signer, err := km.GetSigner(external, "geth-pass")
+	require.NoError(t, err)
+	signer.Lock()
+
+	assert.ErrorIs(t, km.ChangePassword(external, "geth-pass", "new-pass-123"), ErrReadOnlyKeystore)
+	_, err = km.ImportKey(key, "password123")
+	assert.ErrorIs(t, err, keystore.ErrAccountAlreadyExists)
+
+	// New wallets stay in the data dir.
+	created, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	dir, err := km.KeyDir(created.Address)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "keystore"), dir)
+	assert.False(t, km.ReadOnly(created.Address))
+	assert.Len(t, km.ListAccounts(), 2)
+	entries, err := os.ReadDir(ext)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "nothing is written to a read-only keystore")
+}
+
+func TestExternalKeystoreReadWrite(t *testing.T) {
+	ext, _ := gethKeystore(t)
+	setExternalKeystore(t, ext, "read-write")
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	external := km.ListAccounts()[0].Address
+	assert.False(t, km.ReadOnly(external))
+	require.NoError(t, km.ChangePassword(external, "geth-pass", "new-pass-123"))
+
+	created, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+	dir, err := km.KeyDir(created.Address)
+	require.NoError(t, err)
+	assert.Equal(t, ext, dir)
+}
+
+func TestExternalKeystoreMissing(t *testing.T) {
+	setExternalKeystore(t, filepath.Join(testutil.TempDir(t), "nope"), "")
+	_, err := NewKeystoreManager(testutil.TempDir(t))
+	assert.ErrorContains(t, err, "wallet.keystore_dir")
+
+	setExternalKeystore(t, testutil.TempDir(t), "sideways")
+	_, err = NewKeystoreManager(testutil.TempDir(t))
+	assert.ErrorContains(t, err, "wallet.keystore_mode")
+}
+
+func TestConfiguredExternalKeystore(t *testing.T) {
+	_, ok, err := ConfiguredExternalKeystore()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	home := testutil.TempDir(t)
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".ethereum", "keystore"), 0700))
+	setExternalKeystore(t, "~/.ethereum/keystore", "rw")
+	ext, ok, err := ConfiguredExternalKeystore()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(home, ".ethereum", "keystore"), ext.Dir)
+	assert.False(t, ext.ReadOnly)
+}
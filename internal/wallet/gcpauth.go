@@ -0,0 +1,196 @@
+package wallet
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcpScope is the OAuth scope Cloud KMS calls need.
+const gcpScope = "https://www.googleapis.com/auth/cloudkms"
+
+// gcpDefaultTokenURI is Google's OAuth token endpoint.
+const gcpDefaultTokenURI = "https://oauth2.googleapis.com/token"
+
+// gcpCredentials is an Application Default Credentials file: a service
+// account key, or the user credentials of
+// `gcloud auth application-default login`.
+type gcpCredentials struct {
+	Type string `json:"type"`
+	// service_account
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	// authorized_user
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+
+	TokenURI string `json:"token_uri"`
+}
+
+// gcpTokenSource hands out OAuth access tokens for Google APIs, cached
+// until shortly before they expire.
+type gcpTokenSource struct {
+	http *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// accessToken returns GOOGLE_OAUTH_ACCESS_TOKEN as is, or else a token
+// for the Application Default Credentials: GOOGLE_APPLICATION_CREDENTIALS
+// or gcloud's application_default_credentials.json.
+func (s *gcpTokenSource) accessToken(ctx context.Context) (string, error) {
+	if tok := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); tok != "" {
+		return tok, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+	creds, err := loadGCPCredentials()
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	switch creds.Type {
+	case "service_account":
+		assertion, err := creds.jwt(time.Now())
+		if err != nil {
+			return "", err
+		}
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		form.Set("assertion", assertion)
+	case "authorized_user":
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", creds.ClientID)
+		form.Set("client_secret", creds.ClientSecret)
+		form.Set("refresh_token", creds.RefreshToken)
+	default:
+		return "", fmt.Errorf("unsupported Google credentials type %q", creds.Type)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, creds.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("google oauth: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("google oauth: %w", err)
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	_ = json.Unmarshal(data, &out)
+	if resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		if out.Error == "" {
+			out.Error = strings.TrimSpace(string(data))
+		}
+		return "", fmt.Errorf("google oauth: %s %s", out.Error, out.Description)
+	}
+	s.token = out.AccessToken
+	s.expiry = time.Now().Add(time.Duration(out.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// loadGCPCredentials reads GOOGLE_APPLICATION_CREDENTIALS, or gcloud's
+// application default credentials.
+func loadGCPCredentials() (gcpCredentials, error) {
+	path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if path == "" {
+		dir := os.Getenv("CLOUDSDK_CONFIG")
+		if dir == "" && runtime.GOOS == "windows" {
+			dir = filepath.Join(os.Getenv("APPDATA"), "gcloud")
+		}
+		if dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return gcpCredentials{}, err
+			}
+			dir = filepath.Join(home, ".config", "gcloud")
+		}
+		path = filepath.Join(dir, "application_default_credentials.json")
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return gcpCredentials{}, errors.New("no Google credentials: set GOOGLE_APPLICATION_CREDENTIALS to a service account key, or run `gcloud auth application-default login`")
+	}
+	if err != nil {
+		return gcpCredentials{}, err
+	}
+	var c gcpCredentials
+	if err := json.Unmarshal(data, &c); err != nil {
+		return gcpCredentials{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if c.TokenURI == "" {
+		c.TokenURI = gcpDefaultTokenURI
+	}
+	return c, nil
+}
+
+// jwt returns the service account's signed assertion for gcpScope,
+// valid for an hour from now.
+func (c gcpCredentials) jwt(now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(c.PrivateKey))
+	if block == nil {
+		return "", errors.New("service account private_key: not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("service account private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("service account private_key: not an RSA key")
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   c.ClientEmail,
+		"scope": gcpScope,
+		"aud":   c.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
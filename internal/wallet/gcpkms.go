@@ -0,0 +1,181 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// gcpKMSEndpoint is the Cloud KMS API.
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com"
+
+// gcpKeyVersionPattern matches a Cloud KMS key version's resource name.
+var gcpKeyVersionPattern = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+/cryptoKeyVersions/[^/]+$`)
+
+// crc32c is the checksum Cloud KMS uses to guard requests and replies.
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// GCPKMSConfig selects a Google Cloud KMS key version (algorithm
+// EC_SIGN_SECP256K1_SHA256, HSM protection) to sign with, from
+// wallet.gcp_kms.*. The private key never leaves Cloud KMS.
+type GCPKMSConfig struct {
+	// KeyVersion is the key version's resource name,
+	// projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>/cryptoKeyVersions/<v>.
+	KeyVersion string
+	// Endpoint overrides https://cloudkms.googleapis.com, e.g. for
+	// Private Service Connect.
+	Endpoint string
+}
+
+// ParseGCPKeyVersion checks a Cloud KMS key version resource name.
+func ParseGCPKeyVersion(s string) (string, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "/")
+	if !gcpKeyVersionPattern.MatchString(s) {
+		return "", errors.New("expected projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>/cryptoKeyVersions/<v>")
+	}
+	return s, nil
+}
+
+// ConfiguredGCPKMS returns the wallet.gcp_kms.key_version and
+// wallet.gcp_kms.endpoint settings; ok is false when no key is set.
+func ConfiguredGCPKMS() (cfg GCPKMSConfig, ok bool, err error) {
+	raw := strings.TrimSpace(viper.GetString("wallet.gcp_kms.key_version"))
+	if raw == "" {
+		return GCPKMSConfig{}, false, nil
+	}
+	name, err := ParseGCPKeyVersion(raw)
+	if err != nil {
+		return GCPKMSConfig{}, false, fmt.Errorf("wallet.gcp_kms.key_version: %w", err)
+	}
+	cfg = GCPKMSConfig{KeyVersion: name, Endpoint: strings.TrimSpace(viper.GetString("wallet.gcp_kms.endpoint"))}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = gcpKMSEndpoint
+	}
+	return cfg, true, nil
+}
+
+// gcpKMSClient calls the two Cloud KMS methods a signer needs.
+type gcpKMSClient struct {
+	cfg    GCPKMSConfig
+	http   *http.Client
+	tokens *gcpTokenSource
+}
+
+func newGCPKMSClient(cfg GCPKMSConfig) (*gcpKMSClient, error) {
+	client, err := kmsHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return &gcpKMSClient{cfg: cfg, http: client, tokens: &gcpTokenSource{http: client}}, nil
+}
+
+func (c *gcpKMSClient) keyID() string { return c.cfg.KeyVersion }
+
+func (c *gcpKMSClient) provider() string { return "Google Cloud KMS" }
+
+// call sends a Cloud KMS request for the key version's method, e.g.
+// ":asymmetricSign", and decodes the reply into out.
+func (c *gcpKMSClient) call(ctx context.Context, httpMethod, method string, in, out any) error {
+	token, err := c.tokens.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	u := strings.TrimSuffix(c.cfg.Endpoint, "/") + "/v1/" + c.cfg.KeyVersion + method
+	req, err := http.NewRequestWithContext(ctx, httpMethod, u, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloud kms %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("cloud kms %s: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(data, &e)
+		if e.Error.Message == "" {
+			e.Error.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("cloud kms %s: %s %s", method, e.Error.Status, e.Error.Message)
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (c *gcpKMSClient) publicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+		PemCrc32c int64  `json:"pemCrc32c,string"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/publicKey", nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Algorithm != "EC_SIGN_SECP256K1_SHA256" {
+		return nil, fmt.Errorf("cloud kms key %s is %s, not EC_SIGN_SECP256K1_SHA256", c.cfg.KeyVersion, out.Algorithm)
+	}
+	if out.PemCrc32c != 0 && int64(crc32.Checksum([]byte(out.Pem), crc32c)) != out.PemCrc32c {
+		return nil, errors.New("cloud kms public key: corrupted in transit (crc32c mismatch)")
+	}
+	block, _ := pem.Decode([]byte(out.Pem))
+	if block == nil {
+		return nil, errors.New("cloud kms public key: not PEM")
+	}
+	return parseSPKI(block.Bytes)
+}
+
+// sign has Cloud KMS sign digest, which goes in the request's sha256
+// field: KMS signs the 32 bytes as given, so a Keccak-256 hash works too.
+// Both directions are checked against their CRC32C.
+func (c *gcpKMSClient) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	in := map[string]any{
+		"digest":       map[string][]byte{"sha256": digest},
+		"digestCrc32c": fmt.Sprint(crc32.Checksum(digest, crc32c)),
+	}
+	var out struct {
+		Signature            []byte `json:"signature"`
+		SignatureCrc32c      int64  `json:"signatureCrc32c,string"`
+		VerifiedDigestCrc32c bool   `json:"verifiedDigestCrc32c"`
+	}
+	if err := c.call(ctx, http.MethodPost, ":asymmetricSign", in, &out); err != nil {
+		return nil, err
+	}
+	if !out.VerifiedDigestCrc32c {
+		return nil, errors.New("cloud kms sign: request corrupted in transit (digest crc32c not verified)")
+	}
+	if int64(crc32.Checksum(out.Signature, crc32c)) != out.SignatureCrc32c {
+		return nil, errors.New("cloud kms sign: signature corrupted in transit (crc32c mismatch)")
+	}
+	return out.Signature, nil
+}
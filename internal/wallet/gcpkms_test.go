@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+const testKeyVersion = "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+
+// fakeCloudKMS serves a service account token endpoint and the
+// publicKey and asymmetricSign methods for key, answering every other
+// signature in high-s form as an HSM may.
+func fakeCloudKMS(t *testing.T, key *ecdsa.PrivateKey, saKey *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+			parts := strings.Split(r.Form.Get("assertion"), ".")
+			require.Len(t, parts, 3)
+			sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+			require.NoError(t, err)
+			sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			require.NoError(t, rsa.VerifyPKCS1v15(&saKey.PublicKey, stdcrypto.SHA256, sum[:], sig), "the assertion is signed by the service account")
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "tok", "expires_in": 3600})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"status":"UNAUTHENTICATED","message":"no token"}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/" + testKeyVersion + "/publicKey":
+			spki, err := asn1.Marshal(struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}},
+				PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+			})
+			require.NoError(t, err)
+			p := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"pem": p, "algorithm": "EC_SIGN_SECP256K1_SHA256", "pemCrc32c": fmt.Sprint(crc32.Checksum([]byte(p), crc32c)),
+			})
+		case "/v1/" + testKeyVersion + ":asymmetricSign":
+			var in struct {
+				Digest struct {
+					SHA256 []byte `json:"sha256"`
+				} `json:"digest"`
+				DigestCrc32c string `json:"digestCrc32c"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			assert.Equal(t, fmt.Sprint(crc32.Checksum(in.Digest.SHA256, crc32c)), in.DigestCrc32c)
+			sig, err := crypto.Sign(in.Digest.SHA256, key)
+			require.NoError(t, err)
+			rr, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+			if calls++; calls%2 == 0 {
+				s.Sub(secp256k1N, s)
+			}
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{rr, s})
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"signature": der, "signatureCrc32c": fmt.Sprint(crc32.Checksum(der, crc32c)), "verifiedDigestCrc32c": true,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// setGCPKMS configures testKeyVersion served by srv, with a service
+// account key for saKey.
+func setGCPKMS(t *testing.T, srv *httptest.Server, saKey *rsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(saKey)
+	require.NoError(t, err)
+	creds, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "signer@p.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL + "/token",
+	})
+	require.NoError(t, err)
+	path := filepath.Join(testutil.TempDir(t), "sa.json")
+	require.NoError(t, os.WriteFile(path, creds, 0600))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	viper.Set("wallet.gcp_kms.key_version", testKeyVersion)
+	viper.Set("wallet.gcp_kms.endpoint", srv.URL)
+	t.Cleanup(func() {
+		viper.Set("wallet.gcp_kms.key_version", "")
+		viper.Set("wallet.gcp_kms.endpoint", "")
+	})
+}
+
+func TestGCPKMSSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	saKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	setGCPKMS(t, fakeCloudKMS(t, key, saKey), saKey)
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1)
+	assert.Equal(t, addr, accs[0].Address)
+	assert.Equal(t, "Google Cloud KMS", km.KMSProvider(addr))
+	assert.False(t, km.NeedsPassword(addr))
+
+	signer, err := km.GetSigner(addr, "")
+	require.NoError(t, err)
+	chainID := big.NewInt(11155111)
+	for range 4 { // alternating low-s and high-s from the HSM
+		tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), To: &addr, Value: big.NewInt(1)})
+		signed, err := signer.SignTransaction(tx, chainID)
+		require.NoError(t, err)
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, addr, from)
+		_, _, s := signed.RawSignatureValues()
+		assert.LessOrEqual(t, s.Cmp(secp256k1HalfN), 0, "low-s")
+	}
+}
+
+func TestGCPAndAWSKMSTogether(t *testing.T) {
+	awsKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	gcpKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	saKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	setKMS(t, fakeKMS(t, awsKey))
+	setGCPKMS(t, fakeCloudKMS(t, gcpKey, saKey), saKey)
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 2)
+	assert.Equal(t, "AWS KMS", km.KMSProvider(accs[0].Address))
+	assert.Equal(t, "Google Cloud KMS", km.KMSProvider(accs[1].Address))
+	assert.ErrorContains(t, km.ChangePassword(accs[1].Address, "", "new-pass-123"), "Google Cloud KMS")
+}
+
+func TestConfiguredGCPKMS(t *testing.T) {
+	_, ok, err := ConfiguredGCPKMS()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	viper.Set("wallet.gcp_kms.key_version", "/"+testKeyVersion)
+	t.Cleanup(func() { viper.Set("wallet.gcp_kms.key_version", "") })
+	cfg, ok, err := ConfiguredGCPKMS()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, testKeyVersion, cfg.KeyVersion)
+	assert.Equal(t, gcpKMSEndpoint, cfg.Endpoint)
+
+	viper.Set("wallet.gcp_kms.key_version", "projects/p/locations/global/keyRings/r/cryptoKeys/k")
+	_, _, err = ConfiguredGCPKMS()
+	assert.ErrorContains(t, err, "cryptoKeyVersions")
+}
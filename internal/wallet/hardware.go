@@ -41,3 +41,6 @@ func (hs *HardwareSigner) SignMessage(message []byte) ([]byte, error) {
 func (hs *HardwareSigner) SignTypedData(typedData []byte) ([]byte, error) {
 	return nil, ErrHardwareNotImplemented
 }
+
+// Lock does nothing: the key stays on the device
+func (hs *HardwareSigner) Lock() {}
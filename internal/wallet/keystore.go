@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 var (
@@ -32,26 +34,75 @@ type KeystoreSigner struct {
 	key     *ecdsa.PrivateKey // nil when locked
 }
 
-// KeystoreManager manages the keystore directory and accounts
+// KeystoreManager manages the keystore directories and accounts
 type KeystoreManager struct {
-	ks      *keystore.KeyStore
+	// ks is where new wallets are written.
+	ks *keystore.KeyStore
+	// dirs are the keystores wallets are read from, ks's first.
+	dirs []keyDir
+	// env is the environment signer's key, if one was given.
+	env *ecdsa.PrivateKey
+	// kms are the KMS keys set with wallet.kms.key_arn and
+	// wallet.gcp_kms.key_version.
+	kms     []*kmsWallet
 	dataDir string
 }
 
-// NewKeystoreManager creates a new keystore manager
+// NewKeystoreManager creates a new keystore manager over dataDir's
+// keystore, the external one set with wallet.keystore_dir, the
+// environment signer and the AWS and Google Cloud KMS keys, if any.
 func NewKeystoreManager(dataDir string) (*KeystoreManager, error) {
 	keystoreDir := filepath.Join(dataDir, "keystore")
 	if err := os.MkdirAll(keystoreDir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
 	}
 
-	// StandardScryptN and StandardScryptP are secure defaults
-	ks := keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	// New files get wallet.scrypt, StandardScrypt by default; existing
+	// ones decrypt with the parameters they record.
+	scrypt := ConfiguredScrypt()
+	local := keyDir{ks: keystore.NewKeyStore(keystoreDir, scrypt.N, scrypt.P), dir: keystoreDir}
+	env, err := loadEnvSigner()
+	if err != nil {
+		return nil, err
+	}
+	km := &KeystoreManager{ks: local.ks, dirs: []keyDir{local}, env: env, dataDir: dataDir}
+	kmsCfg, ok, err := ConfiguredKMS()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		client, err := newAWSKMSClient(kmsCfg)
+		if err != nil {
+			return nil, err
+		}
+		km.kms = append(km.kms, &kmsWallet{backend: client})
+	}
+	gcpCfg, ok, err := ConfiguredGCPKMS()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		client, err := newGCPKMSClient(gcpCfg)
+		if err != nil {
+			return nil, err
+		}
+		km.kms = append(km.kms, &kmsWallet{backend: client})
+	}
 
-	return &KeystoreManager{
-		ks:      ks,
-		dataDir: dataDir,
-	}, nil
+	ext, ok, err := ConfiguredExternalKeystore()
+	if err != nil {
+		return nil, err
+	}
+	if ok && filepath.Clean(ext.Dir) != filepath.Clean(keystoreDir) {
+		d := keyDir{ks: keystore.NewKeyStore(ext.Dir, scrypt.N, scrypt.P), dir: ext.Dir, readOnly: ext.ReadOnly}
+		if ext.ReadOnly {
+			km.dirs = append(km.dirs, d)
+		} else {
+			km.ks = d.ks
+			km.dirs = []keyDir{d, local}
+		}
+	}
+	return km, nil
 }
 
 // CreateAccount creates a new account with the given password
@@ -71,47 +122,93 @@ func (km *KeystoreManager) ImportKey(privateKeyHex string, password string) (acc
 		return accounts.Account{}, fmt.Errorf("%w: %v", ErrInvalidKey, err)
 	}
 
-	return km.ks.ImportECDSA(privateKey, password)
+	return km.importECDSA(privateKey, password)
 }
 
-// ListAccounts returns all accounts in the keystore
-func (km *KeystoreManager) ListAccounts() []accounts.Account {
-	return km.ks.Accounts()
+// importECDSA encrypts key into ks, unless any of the keystores already
+// has it.
+func (km *KeystoreManager) importECDSA(key *ecdsa.PrivateKey, password string) (accounts.Account, error) {
+	if km.HasAccount(crypto.PubkeyToAddress(key.PublicKey)) {
+		return accounts.Account{}, keystore.ErrAccountAlreadyExists
+	}
+	return km.ks.ImportECDSA(key, password)
 }
 
-// GetSigner returns a signer for the given address
-func (km *KeystoreManager) GetSigner(address common.Address, password string) (*KeystoreSigner, error) {
-	var targetAccount *accounts.Account
-	for _, acc := range km.ks.Accounts() {
-		if acc.Address == address {
-			targetAccount = &acc
-			break
+// ListAccounts returns all accounts in the keystores, each address once,
+// after the environment signer's and before the KMS keys'
+func (km *KeystoreManager) ListAccounts() []accounts.Account {
+	if len(km.dirs) == 1 && km.env == nil && len(km.kms) == 0 {
+		return km.ks.Accounts()
+	}
+	var all []accounts.Account
+	seen := make(map[common.Address]bool)
+	if km.env != nil {
+		acc := envAccount(km.env)
+		all = append(all, acc)
+		seen[acc.Address] = true
+	}
+	for _, d := range km.dirs {
+		for _, acc := range d.ks.Accounts() {
+			if !seen[acc.Address] {
+				seen[acc.Address] = true
+				all = append(all, acc)
+			}
 		}
 	}
-
-	if targetAccount == nil {
-		return nil, ErrAccountNotFound
+	for _, acc := range km.kmsAccounts() {
+		if !seen[acc.Address] {
+			seen[acc.Address] = true
+			all = append(all, acc)
+		}
 	}
+	return all
+}
 
-	// Unlock and get the key
-	if err := km.ks.Unlock(*targetAccount, password); err != nil {
-		return nil, fmt.Errorf("failed to unlock account: %w", err)
+// HasAccount reports whether address is stored in the keystores
+func (km *KeystoreManager) HasAccount(address common.Address) bool {
+	if km.IsEnvSigner(address) || km.IsKMS(address) {
+		return true
 	}
+	_, _, err := km.find(address)
+	return err == nil
+}
 
-	// Export the key to get access to it
-	keyJSON, err := km.ks.Export(*targetAccount, password, password)
+// GetSigner returns a signer for the given address. The environment
+// signer and the KMS keys need no password.
+func (km *KeystoreManager) GetSigner(address common.Address, password string) (Signer, error) {
+	if km.IsEnvSigner(address) {
+		s, err := km.envSigner()
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	if km.IsKMS(address) {
+		s, err := km.getKMSSigner(address)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	d, targetAccount, err := km.find(address)
 	if err != nil {
-		return nil, fmt.Errorf("failed to export key: %w", err)
+		return nil, err
 	}
 
+	// Decrypt the file once, with the scrypt parameters it was written
+	// with: exporting it would encrypt it again with wallet.scrypt's.
+	keyJSON, err := os.ReadFile(targetAccount.URL.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
 	key, err := keystore.DecryptKey(keyJSON, password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt key: %w", err)
+		return nil, fmt.Errorf("failed to unlock account: %w", err)
 	}
 
 	return &KeystoreSigner{
-		ks:      km.ks,
-		account: *targetAccount,
+		ks:      d.ks,
+		account: targetAccount,
 		key:     key.PrivateKey,
 	}, nil
 }
@@ -160,7 +257,8 @@ func (ks *KeystoreSigner) SignMessage(message []byte) ([]byte, error) {
 	return sig, nil
 }
 
-// SignTypedData signs EIP-712 typed data
+// SignTypedData signs EIP-712 typed data given as the JSON of
+// eth_signTypedData_v4: types, primaryType, domain and message.
 func (ks *KeystoreSigner) SignTypedData(typedData []byte) ([]byte, error) {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
@@ -169,8 +267,16 @@ func (ks *KeystoreSigner) SignTypedData(typedData []byte) ([]byte, error) {
 		return nil, ErrAccountLocked
 	}
 
-	// For now, just hash and sign - proper EIP-712 requires parsing the structured data
-	hash := crypto.Keccak256(typedData)
+	var td apitypes.TypedData
+	if err := json.Unmarshal(typedData, &td); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	// The digest binds the domain (chain, verifying contract) to the
+	// message, so a signature can't be replayed elsewhere.
+	hash, _, err := apitypes.TypedDataAndHash(td)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
 	sig, err := crypto.Sign(hash, ks.key)
 	if err != nil {
 		return nil, err
@@ -195,3 +301,71 @@ func (ks *KeystoreSigner) Lock() {
 		ks.key = nil
 	}
 }
+
+// ChangePassword re-encrypts address's keystore file with newPassword,
+// once oldPassword decrypts it, keeping the file's scrypt parameters. The
+// new file is written beside the old one, checked and renamed over it, so
+// an interruption leaves one or the other intact.
+func (km *KeystoreManager) ChangePassword(address common.Address, oldPassword, newPassword string) error {
+	if km.IsEnvSigner(address) {
+		return fmt.Errorf("%s is the environment signer, which has no password", address.Hex())
+	}
+	if provider := km.KMSProvider(address); provider != "" {
+		return fmt.Errorf("%s is a %s key, which has no password", address.Hex(), provider)
+	}
+	d, acc, err := km.find(address)
+	if err != nil {
+		return err
+	}
+	if d.readOnly {
+		return ErrReadOnlyKeystore
+	}
+	path := acc.URL.Path
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore file: %w", err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, oldPassword)
+	if err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+	defer func() { key.PrivateKey.D.SetInt64(0) }()
+	scrypt, err := fileScrypt(path)
+	if err != nil {
+		return err
+	}
+
+	newJSON, err := keystore.EncryptKey(key, newPassword, scrypt.N, scrypt.P)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(newJSON)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	// Never replace the old file with one the new password can't open.
+	written, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to verify keystore file: %w", err)
+	}
+	check, err := keystore.DecryptKey(written, newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to verify keystore file: %w", err)
+	}
+	check.PrivateKey.D.SetInt64(0)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace keystore file: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -115,6 +117,18 @@ func TestKeystoreManager_ImportKey(t *testing.T) {
 	})
 }
 
+func TestKeystoreManager_HasAccount(t *testing.T) {
+	dir := testutil.TempDir(t)
+	km, err := NewKeystoreManager(dir)
+	require.NoError(t, err)
+
+	acc, err := km.CreateAccount("pass1")
+	require.NoError(t, err)
+
+	assert.True(t, km.HasAccount(acc.Address))
+	assert.False(t, km.HasAccount(common.HexToAddress("0x1")))
+}
+
 func TestKeystoreManager_ListAccounts(t *testing.T) {
 	t.Run("returns empty list initially", func(t *testing.T) {
 		dir := testutil.TempDir(t)
@@ -186,3 +200,44 @@ func TestKeystoreManager_GetSigner(t *testing.T) {
 		assert.ErrorIs(t, err, ErrAccountNotFound)
 	})
 }
+
+func TestKeystoreManager_ChangePassword(t *testing.T) {
+	dir := testutil.TempDir(t)
+	km, err := NewKeystoreManager(dir)
+	require.NoError(t, err)
+	acc, err := km.CreateAccount("old-password")
+	require.NoError(t, err)
+	before, err := km.Scrypt(acc.Address)
+	require.NoError(t, err)
+
+	t.Run("wrong password changes nothing", func(t *testing.T) {
+		err := km.ChangePassword(acc.Address, "wrong-password", "new-password")
+		assert.Error(t, err)
+		_, err = km.GetSigner(acc.Address, "old-password")
+		assert.NoError(t, err)
+	})
+
+	t.Run("re-encrypts with the new password", func(t *testing.T) {
+		require.NoError(t, km.ChangePassword(acc.Address, "old-password", "new-password"))
+
+		km2, err := NewKeystoreManager(dir)
+		require.NoError(t, err)
+		_, err = km2.GetSigner(acc.Address, "old-password")
+		assert.Error(t, err)
+		signer, err := km2.GetSigner(acc.Address, "new-password")
+		require.NoError(t, err)
+		assert.Equal(t, acc.Address, signer.Address())
+
+		after, err := km2.Scrypt(acc.Address)
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "scrypt parameters are kept")
+		entries, err := os.ReadDir(filepath.Join(dir, "keystore"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "no temporary file left")
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		err := km.ChangePassword(common.HexToAddress("0x1234"), "a", "b")
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+	})
+}
@@ -0,0 +1,426 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/spf13/viper"
+	"github.com/yolodolo42/clifi/internal/netcfg"
+)
+
+// kmsTimeout bounds one call to a KMS.
+const kmsTimeout = 15 * time.Second
+
+// kmsRetryAfter is how long a KMS key that couldn't be reached is left
+// out before it is tried again.
+const kmsRetryAfter = time.Minute
+
+// kmsBackend is a cloud KMS holding a secp256k1 key: AWS KMS or Google
+// Cloud KMS.
+type kmsBackend interface {
+	// keyID names the key as configured.
+	keyID() string
+	// provider names the service, e.g. "AWS KMS".
+	provider() string
+	// publicKey returns the key's uncompressed secp256k1 public key.
+	publicKey(ctx context.Context) ([]byte, error)
+	// sign returns a DER ECDSA signature of digest.
+	sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// KMSConfig selects an AWS KMS asymmetric key (key spec ECC_SECG_P256K1)
+// to sign with, from wallet.kms.*. The private key never leaves KMS.
+type KMSConfig struct {
+	// KeyID is the key's ARN, or its ID or alias with Region.
+	KeyID string
+	// Region defaults to the ARN's, then AWS_REGION.
+	Region string
+	// Endpoint overrides https://kms.<region>.amazonaws.com, e.g. for a
+	// VPC endpoint.
+	Endpoint string
+}
+
+// ConfiguredKMS returns the wallet.kms.key_arn, wallet.kms.region and
+// wallet.kms.endpoint settings; ok is false when no key is set.
+func ConfiguredKMS() (cfg KMSConfig, ok bool, err error) {
+	cfg = KMSConfig{
+		KeyID:    strings.TrimSpace(viper.GetString("wallet.kms.key_arn")),
+		Region:   strings.TrimSpace(viper.GetString("wallet.kms.region")),
+		Endpoint: strings.TrimSpace(viper.GetString("wallet.kms.endpoint")),
+	}
+	if cfg.KeyID == "" {
+		return KMSConfig{}, false, nil
+	}
+	if cfg.Region == "" {
+		cfg.Region = kmsARNRegion(cfg.KeyID)
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if cfg.Region == "" {
+		return KMSConfig{}, false, errors.New("wallet.kms.region: not set, and wallet.kms.key_arn is not an ARN to take it from")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://kms." + cfg.Region + ".amazonaws.com"
+	}
+	return cfg, true, nil
+}
+
+// kmsARNRegion returns the region of a KMS ARN,
+// arn:aws:kms:<region>:<account>:key/<id>, or "".
+func kmsARNRegion(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "kms" {
+		return ""
+	}
+	return parts[3]
+}
+
+// awsKMSClient calls the two AWS KMS actions a signer needs.
+type awsKMSClient struct {
+	cfg  KMSConfig
+	http *http.Client
+	// creds returns the credentials to sign each call with.
+	creds func() (awsCredentials, error)
+}
+
+func newAWSKMSClient(cfg KMSConfig) (*awsKMSClient, error) {
+	client, err := kmsHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return &awsKMSClient{cfg: cfg, http: client, creds: loadAWSCredentials}, nil
+}
+
+// kmsHTTPClient returns a client for KMS requests with the kms proxy and
+// CA settings.
+func kmsHTTPClient() (*http.Client, error) {
+	t, err := netcfg.Transport(netcfg.ScopeKMS)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: t, Timeout: kmsTimeout}, nil
+}
+
+func (c *awsKMSClient) keyID() string { return c.cfg.KeyID }
+
+func (c *awsKMSClient) provider() string { return "AWS KMS" }
+
+// call posts a KMS JSON request for action and decodes the reply into out.
+func (c *awsKMSClient) call(ctx context.Context, action string, in, out any) error {
+	creds, err := c.creds()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.cfg.Endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	signV4(req, body, creds, c.cfg.Region, "kms", time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("kms %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("kms %s: %w", action, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &e)
+		if e.Message == "" {
+			e.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("kms %s: %s %s", action, e.Type, e.Message)
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (c *awsKMSClient) publicKey(ctx context.Context) ([]byte, error) {
+	var out struct {
+		PublicKey []byte `json:"PublicKey"`
+		KeySpec   string `json:"KeySpec"`
+	}
+	if err := c.call(ctx, "GetPublicKey", map[string]string{"KeyId": c.cfg.KeyID}, &out); err != nil {
+		return nil, err
+	}
+	if out.KeySpec != "" && out.KeySpec != "ECC_SECG_P256K1" {
+		return nil, fmt.Errorf("kms key %s is %s, not ECC_SECG_P256K1", c.cfg.KeyID, out.KeySpec)
+	}
+	return parseSPKI(out.PublicKey)
+}
+
+// parseSPKI returns the uncompressed secp256k1 public key of a DER
+// SubjectPublicKeyInfo, the form both KMSes export keys in.
+func parseSPKI(der []byte) ([]byte, error) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(der, &spki); err != nil {
+		return nil, fmt.Errorf("kms public key: %w", err)
+	}
+	if _, err := crypto.UnmarshalPubkey(spki.PublicKey.Bytes); err != nil {
+		return nil, fmt.Errorf("kms public key: %w", err)
+	}
+	return spki.PublicKey.Bytes, nil
+}
+
+func (c *awsKMSClient) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	var out struct {
+		Signature []byte `json:"Signature"`
+	}
+	in := map[string]any{
+		"KeyId":            c.cfg.KeyID,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	if err := c.call(ctx, "Sign", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+// secp256k1N and secp256k1HalfN bound a canonical signature's s.
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// KMSSigner signs with a KMS key, so the private key never exists on
+// this machine. Each signature is a call to the KMS.
+type KMSSigner struct {
+	backend kmsBackend
+	address common.Address
+	pubkey  []byte
+}
+
+// Address returns the address of the KMS key
+func (s *KMSSigner) Address() common.Address {
+	return s.address
+}
+
+// signHash signs a 32-byte hash, returning the 65-byte [R || S || V]
+// signature with V 0 or 1 as crypto.Sign does.
+func (s *KMSSigner) signHash(hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	der, err := s.backend.sign(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return nil, fmt.Errorf("kms signature: %w", err)
+	}
+	// Ethereum only accepts the low-s form of a signature.
+	if rs.S.Cmp(secp256k1HalfN) > 0 {
+		rs.S.Sub(secp256k1N, rs.S)
+	}
+	sig := make([]byte, 65)
+	rs.R.FillBytes(sig[:32])
+	rs.S.FillBytes(sig[32:64])
+	// Neither KMS returns the recovery ID, and flipping s above flips it
+	// too, so it is whichever of the two recovers the key.
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		if pub, err := crypto.Ecrecover(hash, sig); err == nil && bytes.Equal(pub, s.pubkey) {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("kms signature does not match the key's public key")
+}
+
+// SignTransaction signs a transaction
+func (s *KMSSigner) SignTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	sig, err := s.signHash(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// SignMessage signs an arbitrary message using EIP-191 personal sign
+func (s *KMSSigner) SignMessage(message []byte) ([]byte, error) {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	sig, err := s.signHash(crypto.Keccak256([]byte(prefix), message))
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// SignTypedData signs EIP-712 typed data given as the JSON of
+// eth_signTypedData_v4.
+func (s *KMSSigner) SignTypedData(typedData []byte) ([]byte, error) {
+	var td apitypes.TypedData
+	if err := json.Unmarshal(typedData, &td); err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(td)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+	sig, err := s.signHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// Lock does nothing: no key material is held.
+func (s *KMSSigner) Lock() {}
+
+// kmsWallet is a configured KMS key, resolved to an address on first
+// use.
+type kmsWallet struct {
+	backend kmsBackend
+
+	mu     sync.Mutex
+	signer *KMSSigner
+	// retryAt is when to try again after the KMS couldn't be reached.
+	retryAt time.Time
+}
+
+// kmsSigner returns w's signer, or nil when it can't be reached, in which
+// case it is tried again after kmsRetryAfter. The address is cached in
+// walletsFile, so listing wallets doesn't need the KMS; the public key is
+// fetched for the first signature.
+func (km *KeystoreManager) kmsSigner(w *kmsWallet) *KMSSigner {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.signer != nil || time.Now().Before(w.retryAt) {
+		return w.signer
+	}
+
+	book, err := km.loadBook()
+	keyID := w.backend.keyID()
+	if err == nil {
+		if cached := book.KMSKeys[keyID]; common.IsHexAddress(cached) {
+			w.signer = &KMSSigner{backend: w.backend, address: common.HexToAddress(cached)}
+			return w.signer
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	pub, err := w.backend.publicKey(ctx)
+	if err != nil {
+		slog.Warn("kms key unavailable", "provider", w.backend.provider(), "key", keyID, "err", err, "retry_in", kmsRetryAfter)
+		w.retryAt = time.Now().Add(kmsRetryAfter)
+		return nil
+	}
+	w.signer = newKMSSigner(w.backend, pub)
+	if book != nil {
+		if book.KMSKeys == nil {
+			book.KMSKeys = make(map[string]string)
+		}
+		book.KMSKeys[keyID] = w.signer.address.Hex()
+		if err := km.saveBook(book); err != nil {
+			slog.Warn("failed to cache kms address", "err", err)
+		}
+	}
+	return w.signer
+}
+
+func newKMSSigner(backend kmsBackend, pubkey []byte) *KMSSigner {
+	return &KMSSigner{backend: backend, pubkey: pubkey, address: common.BytesToAddress(crypto.Keccak256(pubkey[1:])[12:])}
+}
+
+// kmsAccounts returns the KMS keys' accounts that resolved, in
+// configuration order.
+func (km *KeystoreManager) kmsAccounts() []accounts.Account {
+	var accs []accounts.Account
+	for _, w := range km.kms {
+		if s := km.kmsSigner(w); s != nil {
+			accs = append(accs, accounts.Account{Address: s.address, URL: accounts.URL{Scheme: "kms", Path: w.backend.keyID()}})
+		}
+	}
+	return accs
+}
+
+// findKMS returns the KMS key whose address is address, or nil.
+func (km *KeystoreManager) findKMS(address common.Address) *kmsWallet {
+	for _, w := range km.kms {
+		if s := km.kmsSigner(w); s != nil && s.address == address {
+			return w
+		}
+	}
+	return nil
+}
+
+// IsKMS reports whether address is a KMS key's.
+func (km *KeystoreManager) IsKMS(address common.Address) bool {
+	return km.findKMS(address) != nil
+}
+
+// KMSProvider names the KMS holding address's key, e.g. "AWS KMS", or
+// returns "" when address isn't a KMS key's.
+func (km *KeystoreManager) KMSProvider(address common.Address) string {
+	if w := km.findKMS(address); w != nil {
+		return w.backend.provider()
+	}
+	return ""
+}
+
+// getKMSSigner returns address's KMS signer with its public key, fetching
+// it now if only the cached address is known.
+func (km *KeystoreManager) getKMSSigner(address common.Address) (*KMSSigner, error) {
+	w := km.findKMS(address)
+	if w == nil {
+		return nil, ErrAccountNotFound
+	}
+	s := km.kmsSigner(w)
+	if s.pubkey != nil {
+		return s, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+	pub, err := w.backend.publicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fresh := newKMSSigner(w.backend, pub)
+	if fresh.address != s.address {
+		return nil, fmt.Errorf("kms key %s is now %s, not the cached %s", w.backend.keyID(), fresh.address.Hex(), s.address.Hex())
+	}
+	w.mu.Lock()
+	w.signer = fresh
+	w.mu.Unlock()
+	return fresh, nil
+}
@@ -0,0 +1,208 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+// fakeKMS serves GetPublicKey and Sign for key, answering every other
+// signature in high-s form as KMS may.
+func fakeKMS(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDTEST/") {
+			http.Error(w, `{"__type":"UnrecognizedClientException","message":"unsigned"}`, http.StatusBadRequest)
+			return
+		}
+		var in struct {
+			Message []byte
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			spki, err := asn1.Marshal(struct {
+				Algorithm pkix.AlgorithmIdentifier
+				PublicKey asn1.BitString
+			}{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}},
+				PublicKey: asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+			})
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]any{"PublicKey": spki, "KeySpec": "ECC_SECG_P256K1"})
+		case "TrentService.Sign":
+			sig, err := crypto.Sign(in.Message, key)
+			require.NoError(t, err)
+			r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+			if calls++; calls%2 == 0 {
+				s.Sub(secp256k1N, s)
+			}
+			der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(map[string]any{"Signature": der})
+		default:
+			http.Error(w, "unknown target", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// setKMS configures a KMS key served by srv for one test.
+func setKMS(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	viper.Set("wallet.kms.key_arn", "arn:aws:kms:eu-west-1:111122223333:key/test")
+	viper.Set("wallet.kms.endpoint", srv.URL)
+	t.Cleanup(func() {
+		viper.Set("wallet.kms.key_arn", "")
+		viper.Set("wallet.kms.endpoint", "")
+	})
+}
+
+func TestKMSSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	setKMS(t, fakeKMS(t, key))
+	dataDir := testutil.TempDir(t)
+
+	km, err := NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1)
+	assert.Equal(t, addr, accs[0].Address)
+	assert.Equal(t, "kms", accs[0].URL.Scheme)
+	assert.True(t, km.IsKMS(addr))
+	assert.False(t, km.NeedsPassword(addr))
+	assert.Error(t, km.ChangePassword(addr, "", "new-pass-123"))
+
+	signer, err := km.GetSigner(addr, "")
+	require.NoError(t, err)
+	chainID := big.NewInt(11155111)
+	for range 2 { // low-s, then high-s from KMS
+		tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), To: &addr, Value: big.NewInt(1)})
+		signed, err := signer.SignTransaction(tx, chainID)
+		require.NoError(t, err)
+		from, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, addr, from)
+	}
+
+	msg := []byte("hello")
+	sig, err := signer.SignMessage(msg)
+	require.NoError(t, err)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n5"), msg), sig)
+	require.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubkeyToAddress(*pub))
+}
+
+func TestKMSAddressIsCached(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	srv := fakeKMS(t, key)
+	setKMS(t, srv)
+	dataDir := testutil.TempDir(t)
+
+	km, err := NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	require.Len(t, km.ListAccounts(), 1)
+
+	// Listing works offline once the address is known.
+	srv.Close()
+	km, err = NewKeystoreManager(dataDir)
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), accs[0].Address)
+	_, err = km.GetSigner(accs[0].Address, "")
+	assert.Error(t, err, "signing needs KMS")
+}
+
+func TestConfiguredKMS(t *testing.T) {
+	_, ok, err := ConfiguredKMS()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	viper.Set("wallet.kms.key_arn", "arn:aws:kms:us-east-2:111122223333:key/abc")
+	t.Cleanup(func() { viper.Set("wallet.kms.key_arn", "") })
+	cfg, ok, err := ConfiguredKMS()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "us-east-2", cfg.Region)
+	assert.Equal(t, "https://kms.us-east-2.amazonaws.com", cfg.Endpoint)
+
+	viper.Set("wallet.kms.key_arn", "alias/clifi")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	_, _, err = ConfiguredKMS()
+	assert.ErrorContains(t, err, "wallet.kms.region")
+}
+
+// TestSignV4 checks the signature against the example in AWS's Signature
+// Version 4 documentation.
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		req.Header.Get("Authorization"))
+}
+
+func TestKMSRetriesAfterFailure(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	srv := fakeKMS(t, key)
+	setKMS(t, srv)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	client := km.kms[0].backend.(*awsKMSClient)
+	client.cfg.Endpoint = down.URL
+	assert.Empty(t, km.ListAccounts(), "KMS unreachable")
+
+	client.cfg.Endpoint = srv.URL
+	assert.Empty(t, km.ListAccounts(), "not retried before kmsRetryAfter")
+	km.kms[0].retryAt = time.Now()
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1, "one failure doesn't disable the key for good")
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), accs[0].Address)
+}
+
+func TestKMSUsesProxySettings(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	// The fake KMS answers as the proxy for an endpoint that doesn't resolve.
+	proxy := fakeKMS(t, key)
+	setKMS(t, proxy)
+	viper.Set("wallet.kms.endpoint", "http://kms.example.invalid")
+	viper.Set("kms.proxy", proxy.URL)
+	t.Cleanup(func() { viper.Set("kms.proxy", "") })
+
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+	accs := km.ListAccounts()
+	require.Len(t, accs, 1, "reached through kms.proxy")
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), accs[0].Address)
+}
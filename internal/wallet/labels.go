@@ -0,0 +1,176 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNoAccounts is returned when the keystore is empty.
+var ErrNoAccounts = errors.New("no wallets found in keystore")
+
+// walletsFile holds wallet labels and the default wallet, next to the
+// keystore directory. It contains no key material.
+const walletsFile = "wallets.json"
+
+var labelPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,31}$`)
+
+// walletBook is the on-disk form of walletsFile.
+type walletBook struct {
+	Default string            `json:"default,omitempty"` // checksummed address
+	Labels  map[string]string `json:"labels,omitempty"`  // checksummed address -> label
+	// KMSKeys caches KMS key ID -> checksummed address, so listing
+	// wallets needs no call to the KMS.
+	KMSKeys map[string]string `json:"kms_keys,omitempty"`
+}
+
+func (km *KeystoreManager) loadBook() (*walletBook, error) {
+	book := &walletBook{Labels: make(map[string]string)}
+	data, err := os.ReadFile(filepath.Join(km.dataDir, walletsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", walletsFile, err)
+	}
+	if err := json.Unmarshal(data, book); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", walletsFile, err)
+	}
+	if book.Labels == nil {
+		book.Labels = make(map[string]string)
+	}
+	return book, nil
+}
+
+func (km *KeystoreManager) saveBook(book *walletBook) error {
+	data, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", walletsFile, err)
+	}
+
+	// Write to temp file first, then rename (atomic)
+	path := filepath.Join(km.dataDir, walletsFile)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", walletsFile, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) // Best-effort cleanup of temp file
+		return fmt.Errorf("failed to save %s: %w", walletsFile, err)
+	}
+	return nil
+}
+
+// Label returns the label set for address, or "".
+func (km *KeystoreManager) Label(address common.Address) string {
+	book, err := km.loadBook()
+	if err != nil {
+		return ""
+	}
+	return book.Labels[address.Hex()]
+}
+
+// ValidateLabel checks a wallet label: up to 32 letters, digits, '-' and
+// '_', starting with a letter.
+func ValidateLabel(label string) error {
+	if !labelPattern.MatchString(label) {
+		return fmt.Errorf("invalid label %q: use up to 32 letters, digits, '-' or '_', starting with a letter", label)
+	}
+	return nil
+}
+
+// SetLabel names a keystore account. Labels are unique ignoring case.
+func (km *KeystoreManager) SetLabel(address common.Address, label string) error {
+	if err := ValidateLabel(label); err != nil {
+		return err
+	}
+	if !km.HasAccount(address) {
+		return ErrAccountNotFound
+	}
+	book, err := km.loadBook()
+	if err != nil {
+		return err
+	}
+	for addr, l := range book.Labels {
+		if strings.EqualFold(l, label) && addr != address.Hex() {
+			return fmt.Errorf("label %q is already used by %s", label, addr)
+		}
+	}
+	book.Labels[address.Hex()] = label
+	return km.saveBook(book)
+}
+
+// DefaultAccount returns the wallet used when none is specified: the one
+// chosen with SetDefault, or else the first in the keystore.
+func (km *KeystoreManager) DefaultAccount() (accounts.Account, error) {
+	accs := km.ListAccounts()
+	if len(accs) == 0 {
+		return accounts.Account{}, ErrNoAccounts
+	}
+	if book, err := km.loadBook(); err == nil && book.Default != "" {
+		for _, acc := range accs {
+			if acc.Address.Hex() == book.Default {
+				return acc, nil
+			}
+		}
+	}
+	return accs[0], nil
+}
+
+// SetDefault makes address the default wallet.
+func (km *KeystoreManager) SetDefault(address common.Address) error {
+	if !km.HasAccount(address) {
+		return ErrAccountNotFound
+	}
+	book, err := km.loadBook()
+	if err != nil {
+		return err
+	}
+	book.Default = address.Hex()
+	return km.saveBook(book)
+}
+
+// FindAccount resolves a wallet reference: a label, an address, or a
+// 1-based position in ListAccounts.
+func (km *KeystoreManager) FindAccount(ref string) (accounts.Account, error) {
+	ref = strings.TrimSpace(ref)
+	accs := km.ListAccounts()
+	if len(accs) == 0 {
+		return accounts.Account{}, ErrNoAccounts
+	}
+
+	if common.IsHexAddress(ref) {
+		addr := common.HexToAddress(ref)
+		for _, acc := range accs {
+			if acc.Address == addr {
+				return acc, nil
+			}
+		}
+		return accounts.Account{}, ErrAccountNotFound
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(accs) {
+			return accounts.Account{}, fmt.Errorf("no wallet #%d (have %d)", n, len(accs))
+		}
+		return accs[n-1], nil
+	}
+
+	book, err := km.loadBook()
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	for _, acc := range accs {
+		if l := book.Labels[acc.Address.Hex()]; l != "" && strings.EqualFold(l, ref) {
+			return acc, nil
+		}
+	}
+	return accounts.Account{}, fmt.Errorf("no wallet labelled %q", ref)
+}
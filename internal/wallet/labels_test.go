@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+func TestKeystoreManager_Labels(t *testing.T) {
+	dir := testutil.TempDir(t)
+	km, err := NewKeystoreManager(dir)
+	require.NoError(t, err)
+
+	_, err = km.DefaultAccount()
+	require.ErrorIs(t, err, ErrNoAccounts)
+
+	first, err := km.ImportKey("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", "testpassword123")
+	require.NoError(t, err)
+	second, err := km.ImportKey("0x8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63", "testpassword123")
+	require.NoError(t, err)
+
+	def, err := km.DefaultAccount()
+	require.NoError(t, err)
+	assert.Equal(t, km.ListAccounts()[0].Address, def.Address, "first account until one is chosen")
+
+	require.NoError(t, km.SetLabel(second.Address, "trading"))
+	assert.Equal(t, "trading", km.Label(second.Address))
+	assert.Empty(t, km.Label(first.Address))
+
+	assert.ErrorContains(t, km.SetLabel(first.Address, "Trading"), "already used")
+	assert.ErrorContains(t, km.SetLabel(first.Address, "1st"), "invalid label")
+
+	acc, err := km.FindAccount("TRADING")
+	require.NoError(t, err)
+	assert.Equal(t, second.Address, acc.Address)
+	acc, err = km.FindAccount(first.Address.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, first.Address, acc.Address)
+	acc, err = km.FindAccount("2")
+	require.NoError(t, err)
+	assert.Equal(t, km.ListAccounts()[1].Address, acc.Address)
+	_, err = km.FindAccount("3")
+	assert.ErrorContains(t, err, "no wallet #3")
+	_, err = km.FindAccount("savings")
+	assert.ErrorContains(t, err, `no wallet labelled "savings"`)
+
+	require.NoError(t, km.SetDefault(second.Address))
+
+	// A fresh manager reads the same file.
+	km2, err := NewKeystoreManager(dir)
+	require.NoError(t, err)
+	def, err = km2.DefaultAccount()
+	require.NoError(t, err)
+	assert.Equal(t, second.Address, def.Address)
+
+	info, err := os.Stat(filepath.Join(dir, walletsFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
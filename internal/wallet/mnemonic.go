@@ -0,0 +1,94 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ErrInvalidMnemonic is returned for phrases that fail the BIP-39 wordlist
+// or checksum check.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// NormalizeMnemonic lowercases a pasted phrase and collapses its
+// whitespace, so line breaks and double spaces from copying don't change
+// the derived key.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+}
+
+// IsMnemonic reports whether input looks like a seed phrase rather than a
+// hex private key. It does not validate the phrase.
+func IsMnemonic(input string) bool {
+	return len(strings.Fields(input)) > 1
+}
+
+// KeyFromMnemonic derives the private key at path from a BIP-39 mnemonic,
+// using no BIP-39 passphrase. Wallets such as MetaMask put the first
+// account at accounts.DefaultBaseDerivationPath.
+func KeyFromMnemonic(mnemonic string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	mnemonic = NormalizeMnemonic(mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	seed := bip39.NewSeed(mnemonic, "")
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	n := crypto.S256().Params().N
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			data = append([]byte{0}, key...)
+		} else {
+			priv, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&priv.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		// BIP-32 says to skip to the next index when the tweak is out of
+		// range; the odds are below 2^-127, so treat it as an error instead.
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("unusable child key at index %d", index)
+		}
+		child := tweak.Add(tweak, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("unusable child key at index %d", index)
+		}
+		key = child.FillBytes(make([]byte, 32))
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(key)
+}
+
+// ImportMnemonic derives the key at path from mnemonic and encrypts it
+// into the keystore with password.
+func (km *KeystoreManager) ImportMnemonic(mnemonic string, path accounts.DerivationPath, password string) (accounts.Account, error) {
+	key, err := KeyFromMnemonic(mnemonic, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer key.D.SetInt64(0)
+	return km.importECDSA(key, password)
+}
@@ -0,0 +1,49 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+// The well-known Hardhat/Anvil development mnemonic (DO NOT use in production).
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestKeyFromMnemonic(t *testing.T) {
+	t.Run("derives standard Ethereum accounts", func(t *testing.T) {
+		key, err := KeyFromMnemonic(testMnemonic, accounts.DefaultBaseDerivationPath)
+		require.NoError(t, err)
+		assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+		second, err := accounts.ParseDerivationPath("m/44'/60'/0'/0/1")
+		require.NoError(t, err)
+		key, err = KeyFromMnemonic(testMnemonic, second)
+		require.NoError(t, err)
+		assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", crypto.PubkeyToAddress(key.PublicKey).Hex())
+	})
+
+	t.Run("ignores case and extra whitespace", func(t *testing.T) {
+		key, err := KeyFromMnemonic("  TEST test test test test test\ntest test test test test  junk ", accounts.DefaultBaseDerivationPath)
+		require.NoError(t, err)
+		assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", crypto.PubkeyToAddress(key.PublicKey).Hex())
+	})
+
+	t.Run("rejects bad checksum", func(t *testing.T) {
+		_, err := KeyFromMnemonic("test test test test test test test test test test test test", accounts.DefaultBaseDerivationPath)
+		assert.ErrorIs(t, err, ErrInvalidMnemonic)
+	})
+}
+
+func TestKeystoreManager_ImportMnemonic(t *testing.T) {
+	km, err := NewKeystoreManager(testutil.TempDir(t))
+	require.NoError(t, err)
+
+	account, err := km.ImportMnemonic(testMnemonic, accounts.DefaultBaseDerivationPath, "testpassword")
+	require.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", account.Address.Hex())
+	assert.True(t, km.HasAccount(account.Address))
+}
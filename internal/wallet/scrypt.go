@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Scrypt is the cost of the key derivation that encrypts a keystore file:
+// what makes a stolen file slow to brute-force, and every unlock slow.
+// Each file records the parameters it was encrypted with, so changing them
+// only applies to wallets created or imported afterwards.
+type Scrypt struct {
+	N int
+	P int
+}
+
+var (
+	// StandardScrypt takes about a second per unlock; the default.
+	StandardScrypt = Scrypt{N: keystore.StandardScryptN, P: keystore.StandardScryptP}
+	// LightScrypt unlocks in milliseconds, for test and dev wallets only.
+	LightScrypt = Scrypt{N: keystore.LightScryptN, P: keystore.LightScryptP}
+)
+
+// ParseScrypt parses a wallet.scrypt setting: standard, light, or N,P
+// with N a power of two, e.g. 65536,1.
+func ParseScrypt(v string) (Scrypt, error) {
+	switch v = strings.ToLower(strings.TrimSpace(v)); v {
+	case "", "standard":
+		return StandardScrypt, nil
+	case "light":
+		return LightScrypt, nil
+	}
+	n, p, ok := strings.Cut(v, ",")
+	if !ok {
+		return Scrypt{}, fmt.Errorf("expected standard, light or N,P, got %q", v)
+	}
+	s := Scrypt{}
+	var err error
+	if s.N, err = strconv.Atoi(strings.TrimSpace(n)); err != nil || s.N < 2 || s.N&(s.N-1) != 0 {
+		return Scrypt{}, fmt.Errorf("scrypt N must be a power of two above 1, got %q", n)
+	}
+	if s.P, err = strconv.Atoi(strings.TrimSpace(p)); err != nil || s.P < 1 {
+		return Scrypt{}, fmt.Errorf("scrypt P must be a positive integer, got %q", p)
+	}
+	return s, nil
+}
+
+// ConfiguredScrypt returns the wallet.scrypt setting new keystore files
+// are encrypted with, StandardScrypt unless set. An invalid setting also
+// falls back to StandardScrypt: it never weakens a wallet.
+func ConfiguredScrypt() Scrypt {
+	s, err := ParseScrypt(viper.GetString("wallet.scrypt"))
+	if err != nil {
+		return StandardScrypt
+	}
+	return s
+}
+
+// Weak reports whether s is cheaper than StandardScrypt.
+func (s Scrypt) Weak() bool {
+	return s.N*s.P < StandardScrypt.N*StandardScrypt.P
+}
+
+func (s Scrypt) String() string {
+	switch s {
+	case StandardScrypt:
+		return "standard"
+	case LightScrypt:
+		return "light"
+	}
+	return fmt.Sprintf("%d,%d", s.N, s.P)
+}
+
+// fileScrypt reads the parameters a keystore file was encrypted with.
+func fileScrypt(path string) (Scrypt, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Scrypt{}, err
+	}
+	var f struct {
+		Crypto keystore.CryptoJSON `json:"crypto"`
+	}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return Scrypt{}, fmt.Errorf("read keystore file: %w", err)
+	}
+	if f.Crypto.KDF != "scrypt" {
+		return Scrypt{}, fmt.Errorf("keystore file uses %s, not scrypt", f.Crypto.KDF)
+	}
+	// JSON numbers decode as float64.
+	n, _ := f.Crypto.KDFParams["n"].(float64)
+	p, _ := f.Crypto.KDFParams["p"].(float64)
+	return Scrypt{N: int(n), P: int(p)}, nil
+}
+
+// Scrypt returns the parameters address's keystore file was encrypted
+// with.
+func (km *KeystoreManager) Scrypt(address common.Address) (Scrypt, error) {
+	_, acc, err := km.find(address)
+	if err != nil {
+		return Scrypt{}, err
+	}
+	return fileScrypt(acc.URL.Path)
+}
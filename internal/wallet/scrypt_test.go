@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/internal/testutil"
+)
+
+// TestMain encrypts the tests' wallets with LightScrypt, so unlocking one
+// takes milliseconds rather than a second.
+func TestMain(m *testing.M) {
+	viper.Set("wallet.scrypt", "light")
+	os.Exit(m.Run())
+}
+
+func TestParseScrypt(t *testing.T) {
+	for in, want := range map[string]Scrypt{
+		"":         StandardScrypt,
+		"Standard": StandardScrypt,
+		"light":    LightScrypt,
+		"65536, 2": {N: 65536, P: 2},
+	} {
+		got, err := ParseScrypt(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"fast", "1000,1", "1,1", "4096,0", "4096"} {
+		_, err := ParseScrypt(in)
+		assert.Error(t, err, in)
+	}
+
+	assert.True(t, LightScrypt.Weak())
+	assert.False(t, StandardScrypt.Weak())
+	assert.Equal(t, "light", LightScrypt.String())
+	assert.Equal(t, "65536,2", Scrypt{N: 65536, P: 2}.String())
+}
+
+func TestConfiguredScrypt_RecordedPerFile(t *testing.T) {
+	t.Cleanup(func() { viper.Set("wallet.scrypt", "light") })
+	dir := testutil.TempDir(t)
+
+	km, err := NewKeystoreManager(dir)
+	require.NoError(t, err)
+	light, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+
+	viper.Set("wallet.scrypt", "16384,1")
+	km, err = NewKeystoreManager(dir)
+	require.NoError(t, err)
+	custom, err := km.CreateAccount("password123")
+	require.NoError(t, err)
+
+	got, err := km.Scrypt(light.Address)
+	require.NoError(t, err)
+	assert.Equal(t, LightScrypt, got, "a file keeps the parameters it was written with")
+	got, err = km.Scrypt(custom.Address)
+	require.NoError(t, err)
+	assert.Equal(t, Scrypt{N: 16384, P: 1}, got)
+
+	_, err = km.GetSigner(light.Address, "password123")
+	assert.NoError(t, err)
+
+	viper.Set("wallet.scrypt", "bogus")
+	assert.Equal(t, StandardScrypt, ConfiguredScrypt(), "an invalid setting never weakens a wallet")
+}
@@ -0,0 +1,237 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// Seed shares split a BIP-39 mnemonic's entropy with Shamir's secret
+// sharing over GF(256), in the spirit of SLIP-39: any threshold of the
+// shares rebuild the mnemonic, fewer reveal nothing about it. Each share
+// is written as BIP-39 words encoding
+//
+//	version (1 byte) | set ID (2) | threshold<<4 | index (1) | share (16 or 32) | checksum (4)
+//
+// so a share from another set, or one copied wrong, is caught.
+const shareVersion = 1
+
+// MaxShares is the most shares a seed can be split into.
+const MaxShares = 15
+
+var (
+	// ErrInvalidShare is returned for words that don't decode to a share.
+	ErrInvalidShare = errors.New("invalid share")
+	// ErrTooFewShares is returned when fewer shares than the threshold
+	// are given.
+	ErrTooFewShares = errors.New("not enough shares")
+)
+
+// Share is one decoded seed share.
+type Share struct {
+	SetID     uint16
+	Threshold int
+	Index     int
+	value     []byte
+}
+
+// NewMnemonic generates a BIP-39 mnemonic of 12 or 24 words.
+func NewMnemonic(words int) (string, error) {
+	if words != 12 && words != 24 {
+		return "", fmt.Errorf("mnemonics have 12 or 24 words, not %d", words)
+	}
+	entropy, err := bip39.NewEntropy(words / 3 * 32)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// SplitMnemonic splits mnemonic into n shares, any threshold of which
+// rebuild it.
+func SplitMnemonic(mnemonic string, threshold, n int) ([]string, error) {
+	if threshold < 2 || threshold > n || n > MaxShares {
+		return nil, fmt.Errorf("need 2 <= threshold <= shares <= %d, got %d-of-%d", MaxShares, threshold, n)
+	}
+	entropy, err := bip39.EntropyFromMnemonic(NormalizeMnemonic(mnemonic))
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+
+	// One random polynomial of degree threshold-1 per secret byte, with
+	// the byte as its constant term; share i is the polynomials at x=i.
+	values := make([][]byte, n)
+	for i := range values {
+		values[i] = make([]byte, len(entropy))
+	}
+	coeffs := make([]byte, threshold)
+	for b, secret := range entropy {
+		coeffs[0] = secret
+		if _, err := rand.Read(coeffs[1:]); err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i][b] = evalPoly(coeffs, byte(i+1))
+		}
+	}
+	clear(coeffs)
+
+	out := make([]string, n)
+	for i, v := range values {
+		out[i] = encodeShare(Share{SetID: uint16(id[0])<<8 | uint16(id[1]), Threshold: threshold, Index: i + 1, value: v})
+		clear(v)
+	}
+	return out, nil
+}
+
+// ParseShare decodes a share's words.
+func ParseShare(words string) (Share, error) {
+	var bits big.Int
+	fields := strings.Fields(strings.ToLower(words))
+	for _, w := range fields {
+		idx, ok := bip39.GetWordIndex(w)
+		if !ok {
+			return Share{}, fmt.Errorf("%w: %q is not a BIP-39 word", ErrInvalidShare, w)
+		}
+		bits.Lsh(&bits, 11)
+		bits.Or(&bits, big.NewInt(int64(idx)))
+	}
+	size := 0
+	for _, n := range []int{4 + 16 + 4, 4 + 32 + 4} {
+		if len(fields) == shareWords(n) {
+			size = n
+		}
+	}
+	if size == 0 {
+		return Share{}, fmt.Errorf("%w: %d words", ErrInvalidShare, len(fields))
+	}
+	// The last word's unused low bits are zero; otherwise it was mistyped,
+	// which the checksum can't see.
+	pad := uint(len(fields)*11 - size*8)
+	if new(big.Int).And(&bits, big.NewInt(1<<pad-1)).Sign() != 0 {
+		return Share{}, fmt.Errorf("%w: the last word is wrong, check it", ErrInvalidShare)
+	}
+	data := new(big.Int).Rsh(&bits, pad).FillBytes(make([]byte, size))
+	body, sum := data[:size-4], data[size-4:]
+	check := sha256.Sum256(body)
+	if string(check[:4]) != string(sum) {
+		return Share{}, fmt.Errorf("%w: checksum mismatch, check the words", ErrInvalidShare)
+	}
+	if body[0] != shareVersion {
+		return Share{}, fmt.Errorf("%w: unknown version %d", ErrInvalidShare, body[0])
+	}
+	s := Share{
+		SetID:     uint16(body[1])<<8 | uint16(body[2]),
+		Threshold: int(body[3] >> 4),
+		Index:     int(body[3] & 0x0f),
+		value:     body[4:],
+	}
+	if s.Threshold < 2 || s.Index < 1 {
+		return Share{}, fmt.Errorf("%w: bad header", ErrInvalidShare)
+	}
+	return s, nil
+}
+
+func encodeShare(s Share) string {
+	body := append([]byte{shareVersion, byte(s.SetID >> 8), byte(s.SetID), byte(s.Threshold<<4 | s.Index)}, s.value...)
+	sum := sha256.Sum256(body)
+	data := append(body, sum[:4]...)
+
+	nwords := shareWords(len(data))
+	bits := new(big.Int).SetBytes(data)
+	bits.Lsh(bits, uint(nwords*11-len(data)*8))
+	list := bip39.GetWordList()
+	words := make([]string, nwords)
+	mask := big.NewInt(0x7ff)
+	for i := nwords - 1; i >= 0; i-- {
+		words[i] = list[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+	return strings.Join(words, " ")
+}
+
+// shareWords is how many 11-bit words hold size bytes.
+func shareWords(size int) int {
+	return (size*8 + 10) / 11
+}
+
+// CombineShares rebuilds the mnemonic from at least a threshold of one
+// set's shares.
+func CombineShares(shares []Share) (string, error) {
+	if len(shares) == 0 {
+		return "", ErrTooFewShares
+	}
+	first := shares[0]
+	seen := make(map[int]bool)
+	var use []Share
+	for _, s := range shares {
+		if s.SetID != first.SetID || s.Threshold != first.Threshold || len(s.value) != len(first.value) {
+			return "", fmt.Errorf("%w: share #%d is from another set", ErrInvalidShare, s.Index)
+		}
+		if !seen[s.Index] {
+			seen[s.Index] = true
+			use = append(use, s)
+		}
+	}
+	if len(use) < first.Threshold {
+		return "", fmt.Errorf("%w: have %d, need %d", ErrTooFewShares, len(use), first.Threshold)
+	}
+	use = use[:first.Threshold]
+
+	// Lagrange interpolation at x=0.
+	entropy := make([]byte, len(first.value))
+	defer clear(entropy)
+	for i, si := range use {
+		xi := byte(si.Index)
+		basis := byte(1)
+		for j, sj := range use {
+			if i != j {
+				xj := byte(sj.Index)
+				basis = gfMul(basis, gfMul(xj, gfInv(xj^xi)))
+			}
+		}
+		for b := range entropy {
+			entropy[b] ^= gfMul(si.value[b], basis)
+		}
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// evalPoly evaluates coeffs, constant term first, at x in GF(256).
+func evalPoly(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coeffs[i]
+	}
+	return y
+}
+
+// gfMul multiplies in GF(256) with the AES polynomial.
+func gfMul(a, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= a & -(b & 1)
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns a's inverse, a^254.
+func gfInv(a byte) byte {
+	r := byte(1)
+	for range 254 {
+		r = gfMul(r, a)
+	}
+	return r
+}
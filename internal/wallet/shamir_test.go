@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestSplitCombineMnemonic(t *testing.T) {
+	// A share carries 8 bytes of header and checksum over the seed's
+	// entropy.
+	want := map[int]int{12: 18, 24: 30}
+	for _, words := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(words)
+		require.NoError(t, err)
+		assert.Len(t, strings.Fields(mnemonic), words)
+
+		shares, err := SplitMnemonic(mnemonic, 2, 3)
+		require.NoError(t, err)
+		require.Len(t, shares, 3)
+		parsed := make([]Share, len(shares))
+		for i, s := range shares {
+			assert.Len(t, strings.Fields(s), want[words], "share words for a %d-word seed", words)
+			parsed[i], err = ParseShare(s)
+			require.NoError(t, err)
+			assert.Equal(t, 2, parsed[i].Threshold)
+			assert.Equal(t, i+1, parsed[i].Index)
+		}
+
+		for i := range parsed {
+			for j := range parsed {
+				if i == j {
+					continue
+				}
+				got, err := CombineShares([]Share{parsed[i], parsed[j]})
+				require.NoError(t, err)
+				assert.Equal(t, mnemonic, got, "shares %d and %d", i+1, j+1)
+			}
+		}
+
+		_, err = CombineShares(parsed[:1])
+		assert.ErrorIs(t, err, ErrTooFewShares)
+		_, err = CombineShares([]Share{parsed[0], parsed[0]})
+		assert.ErrorIs(t, err, ErrTooFewShares, "a repeated share counts once")
+	}
+}
+
+func TestParseShareRejectsTypos(t *testing.T) {
+	mnemonic, err := NewMnemonic(12)
+	require.NoError(t, err)
+	shares, err := SplitMnemonic(mnemonic, 2, 3)
+	require.NoError(t, err)
+
+	words := strings.Fields(shares[0])
+	swapped := "abandon"
+	if words[5] == swapped {
+		swapped = "ability"
+	}
+	words[5] = swapped
+	_, err = ParseShare(strings.Join(words, " "))
+	assert.ErrorIs(t, err, ErrInvalidShare)
+
+	_, err = ParseShare(strings.Join(words[:17], " "))
+	assert.ErrorIs(t, err, ErrInvalidShare)
+	_, err = ParseShare("not a share")
+	assert.ErrorIs(t, err, ErrInvalidShare)
+}
+
+func TestParseShareRejectsWrongLastWord(t *testing.T) {
+	for _, seedWords := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(seedWords)
+		require.NoError(t, err)
+		shares, err := SplitMnemonic(mnemonic, 2, 3)
+		require.NoError(t, err)
+
+		// Flipping the last word's lowest bit only touches the padding,
+		// which the checksum doesn't cover.
+		words := strings.Fields(shares[0])
+		last := len(words) - 1
+		idx, ok := bip39.GetWordIndex(words[last])
+		require.True(t, ok)
+		words[last] = bip39.GetWordList()[idx^1]
+		_, err = ParseShare(strings.Join(words, " "))
+		assert.ErrorIs(t, err, ErrInvalidShare, "%d-word seed", seedWords)
+		assert.ErrorContains(t, err, "last word")
+	}
+}
+
+func TestCombineSharesRejectsMixedSets(t *testing.T) {
+	mnemonic, err := NewMnemonic(12)
+	require.NoError(t, err)
+	a, err := SplitMnemonic(mnemonic, 2, 3)
+	require.NoError(t, err)
+	b, err := SplitMnemonic(mnemonic, 2, 3)
+	require.NoError(t, err)
+	sa, err := ParseShare(a[0])
+	require.NoError(t, err)
+	sb, err := ParseShare(b[1])
+	require.NoError(t, err)
+	if sa.SetID == sb.SetID {
+		t.Skip("random set IDs collided")
+	}
+	_, err = CombineShares([]Share{sa, sb})
+	assert.ErrorIs(t, err, ErrInvalidShare)
+}
+
+func TestSplitMnemonicValidates(t *testing.T) {
+	mnemonic, err := NewMnemonic(12)
+	require.NoError(t, err)
+	for _, c := range [][2]int{{1, 3}, {4, 3}, {2, 16}} {
+		_, err := SplitMnemonic(mnemonic, c[0], c[1])
+		assert.Error(t, err, "%d-of-%d", c[0], c[1])
+	}
+	_, err = SplitMnemonic("abandon abandon", 2, 3)
+	assert.ErrorIs(t, err, ErrInvalidMnemonic)
+}
+
+func TestGFInverse(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfInv(byte(a))), "a=%d", a)
+	}
+}
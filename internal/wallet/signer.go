@@ -21,6 +21,9 @@ type Signer interface {
 
 	// SignTypedData signs EIP-712 typed data
 	SignTypedData(typedData []byte) ([]byte, error)
+
+	// Lock drops any key material held in memory
+	Lock()
 }
 
 // SignerType represents the type of signer
@@ -30,6 +33,7 @@ const (
 	SignerTypeKeystore SignerType = "keystore"
 	SignerTypeHardware SignerType = "hardware"
 	SignerTypeRemote   SignerType = "remote"
+	SignerTypeKMS      SignerType = "kms"
 )
 
 // Account represents a managed account
@@ -1,10 +1,13 @@
 package wallet
 
 import (
+	"encoding/hex"
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yolodolo42/clifi/internal/testutil"
@@ -122,22 +125,60 @@ func TestKeystoreSigner_SignMessage(t *testing.T) {
 }
 
 func TestKeystoreSigner_SignTypedData(t *testing.T) {
-	t.Run("signs typed data", func(t *testing.T) {
+	t.Run("signs the EIP-712 example", func(t *testing.T) {
 		dir := testutil.TempDir(t)
 		km, err := NewKeystoreManager(dir)
 		require.NoError(t, err)
 
-		account, err := km.CreateAccount("testpassword")
+		// The Mail example from the EIP-712 spec, signed by keccak256("cow").
+		key := hex.EncodeToString(crypto.Keccak256([]byte("cow")))
+		account, err := km.ImportKey(key, "testpassword")
 		require.NoError(t, err)
+		assert.Equal(t, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", account.Address.Hex())
 
 		signer, err := km.GetSigner(account.Address, "testpassword")
 		require.NoError(t, err)
 
-		// Simplified typed data (just bytes for now)
-		typedData := []byte(`{"types":{},"message":{}}`)
+		typedData := []byte(`{
+			"types": {
+				"EIP712Domain": [
+					{"name": "name", "type": "string"},
+					{"name": "version", "type": "string"},
+					{"name": "chainId", "type": "uint256"},
+					{"name": "verifyingContract", "type": "address"}
+				],
+				"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+				"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+			},
+			"primaryType": "Mail",
+			"domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+			"message": {
+				"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+				"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+				"contents": "Hello, Bob!"
+			}
+		}`)
 		sig, err := signer.SignTypedData(typedData)
 		require.NoError(t, err)
-		require.Len(t, sig, 65)
+		assert.Equal(t, "0x"+
+			"4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+			"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+
+			"1c", hexutil.Encode(sig))
+	})
+
+	t.Run("rejects data that is not EIP-712", func(t *testing.T) {
+		dir := testutil.TempDir(t)
+		km, err := NewKeystoreManager(dir)
+		require.NoError(t, err)
+
+		account, err := km.CreateAccount("testpassword")
+		require.NoError(t, err)
+
+		signer, err := km.GetSigner(account.Address, "testpassword")
+		require.NoError(t, err)
+
+		_, err = signer.SignTypedData([]byte(`{"types":{},"message":{}}`))
+		require.Error(t, err)
 	})
 
 	t.Run("returns error when locked", func(t *testing.T) {
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// Desktop shows alerts as desktop notifications, via notify-send on Linux
+// and osascript on macOS.
+type Desktop struct{}
+
+func (Desktop) Notify(ctx context.Context, a Alert) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux":
+		cmd = exec.CommandContext(ctx, "notify-send", "clifi", a.Message())
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title \"clifi\"", strconv.Quote(a.Message()))
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Webhook POSTs alerts as JSON to URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// webhookPayload is the body sent for each alert.
+type webhookPayload struct {
+	ID          int       `json:"id"`
+	Condition   string    `json:"condition,omitempty"`
+	Value       float64   `json:"value"`
+	Message     string    `json:"message"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+func (h Webhook) Notify(ctx context.Context, a Alert) error {
+	payload := webhookPayload{
+		ID:          a.Watch.ID,
+		Value:       a.Value,
+		Message:     a.Message(),
+		TriggeredAt: a.At,
+	}
+	// Alerts not raised by a watch, e.g. a settled transaction, have none.
+	if a.Watch.Condition.Kind != "" || a.Watch.Condition.Symbol != "" {
+		payload.Condition = a.Watch.Condition.String()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Writer prints alerts, one per line, e.g. to the serve command's output.
+type Writer struct {
+	W io.Writer
+}
+
+func (w Writer) Notify(_ context.Context, a Alert) error {
+	_, err := fmt.Fprintf(w.W, "%s  %s\n", a.At.Local().Format(time.DateTime), a.Message())
+	return err
+}
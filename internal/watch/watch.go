@@ -0,0 +1,277 @@
+// Package watch stores price and balance conditions ("ETH > 4000") and
+// checks them in the background, notifying when one starts to hold.
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// Kind is what a condition measures.
+type Kind string
+
+const (
+	// KindPrice is a token's USD price.
+	KindPrice Kind = "price"
+	// KindBalance is a wallet's native balance on one chain.
+	KindBalance Kind = "balance"
+)
+
+// DefaultPriceChain is where symbols are priced when the condition names
+// no chain.
+const DefaultPriceChain = "ethereum"
+
+// Condition compares a price or balance with a threshold.
+type Condition struct {
+	Kind   Kind    `json:"kind"`
+	Symbol string  `json:"symbol,omitempty"` // price: token symbol or address
+	Chain  string  `json:"chain"`
+	Wallet string  `json:"wallet,omitempty"` // balance: label or address until resolved, then an address
+	Op     string  `json:"op"`
+	Value  float64 `json:"value"`
+}
+
+var opPattern = regexp.MustCompile(`\s*(>=|<=|>|<)\s*`)
+
+// ParseCondition parses
+//
+//	<SYMBOL>[@chain] <op> <usd>            e.g. ETH > 4000, ARB@arbitrum < 0.5
+//	balance <chain> [wallet] <op> <amount> e.g. balance base < 0.1
+//
+// where op is >, >=, < or <=. A balance condition without a wallet means
+// the default wallet.
+func ParseCondition(expr string) (Condition, error) {
+	fields := strings.Fields(opPattern.ReplaceAllString(expr, " $1 "))
+	if len(fields) < 3 {
+		return Condition{}, fmt.Errorf("invalid condition %q: want e.g. \"ETH > 4000\" or \"balance base < 0.1\"", expr)
+	}
+	op := fields[len(fields)-2]
+	if !opPattern.MatchString(op) || strings.TrimSpace(op) != op {
+		return Condition{}, fmt.Errorf("invalid condition %q: comparison must be >, >=, < or <=", expr)
+	}
+	value, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "", "_", "").Replace(fields[len(fields)-1]), 64)
+	if err != nil || value < 0 {
+		return Condition{}, fmt.Errorf("invalid condition %q: %q is not a number", expr, fields[len(fields)-1])
+	}
+	subject := fields[:len(fields)-2]
+
+	c := Condition{Op: op, Value: value}
+	if strings.EqualFold(subject[0], "balance") {
+		if len(subject) < 2 || len(subject) > 3 {
+			return Condition{}, fmt.Errorf("invalid condition %q: want \"balance <chain> [wallet] <op> <amount>\"", expr)
+		}
+		c.Kind = KindBalance
+		c.Chain = strings.ToLower(subject[1])
+		if len(subject) == 3 {
+			c.Wallet = subject[2]
+		}
+		return c, nil
+	}
+	if len(subject) != 1 {
+		return Condition{}, fmt.Errorf("invalid condition %q: want \"<SYMBOL>[@chain] <op> <price>\"", expr)
+	}
+	symbol, chainName, _ := strings.Cut(subject[0], "@")
+	if symbol == "" {
+		return Condition{}, fmt.Errorf("invalid condition %q: missing token", expr)
+	}
+	c.Kind = KindPrice
+	c.Symbol = symbol
+	if !common.IsHexAddress(symbol) {
+		c.Symbol = strings.ToUpper(symbol)
+	}
+	c.Chain = strings.ToLower(chainName)
+	if c.Chain == "" {
+		c.Chain = DefaultPriceChain
+	}
+	return c, nil
+}
+
+// ResolveWallet turns a balance condition's wallet reference into an
+// address, so the watch keeps following that wallet if the default
+// changes.
+func (c *Condition) ResolveWallet(km *wallet.KeystoreManager) error {
+	if c.Kind != KindBalance || common.IsHexAddress(c.Wallet) {
+		return nil
+	}
+	if c.Wallet == "" {
+		acc, err := km.DefaultAccount()
+		if err != nil {
+			return fmt.Errorf("balance watch needs a wallet: %w", err)
+		}
+		c.Wallet = acc.Address.Hex()
+		return nil
+	}
+	acc, err := km.FindAccount(c.Wallet)
+	if err != nil {
+		return err
+	}
+	c.Wallet = acc.Address.Hex()
+	return nil
+}
+
+// Holds reports whether v satisfies the condition.
+func (c Condition) Holds(v float64) bool {
+	switch c.Op {
+	case ">":
+		return v > c.Value
+	case ">=":
+		return v >= c.Value
+	case "<":
+		return v < c.Value
+	case "<=":
+		return v <= c.Value
+	}
+	return false
+}
+
+func (c Condition) String() string {
+	value := strconv.FormatFloat(c.Value, 'f', -1, 64)
+	if c.Kind == KindBalance {
+		s := "balance " + c.Chain
+		if c.Wallet != "" {
+			s += " " + c.Wallet
+		}
+		return fmt.Sprintf("%s %s %s", s, c.Op, value)
+	}
+	subject := c.Symbol
+	if c.Chain != DefaultPriceChain {
+		subject += "@" + c.Chain
+	}
+	return fmt.Sprintf("%s %s %s", subject, c.Op, value)
+}
+
+// Watch is a stored condition and its state.
+type Watch struct {
+	ID        int       `json:"id"`
+	Condition Condition `json:"condition"`
+	// Once removes the watch after it fires; otherwise it re-arms when the
+	// condition stops holding.
+	Once      bool      `json:"once,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Triggered is set while the condition holds after firing, so each
+	// crossing notifies once.
+	Triggered   bool      `json:"triggered,omitempty"`
+	LastValue   float64   `json:"last_value,omitempty"`
+	LastChecked time.Time `json:"last_checked,omitzero"`
+	FiredAt     time.Time `json:"fired_at,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// File is where watches are kept, in the data dir.
+const File = "watches.json"
+
+// Store keeps watches in dataDir/watches.json. `clifi watch` and
+// `clifi serve` share it from separate processes, so every change is a
+// read-modify-write of the whole file.
+type Store struct {
+	path string
+}
+
+// NewStore returns the store in dataDir.
+func NewStore(dataDir string) *Store {
+	return &Store{path: filepath.Join(dataDir, File)}
+}
+
+type storeFile struct {
+	NextID  int     `json:"next_id"`
+	Watches []Watch `json:"watches"`
+}
+
+func (s *Store) load() (*storeFile, error) {
+	f := &storeFile{NextID: 1}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", File, err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", File, err)
+	}
+	return f, nil
+}
+
+func (s *Store) save(f *storeFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// List returns the watches, oldest first.
+func (s *Store) List() ([]Watch, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return f.Watches, nil
+}
+
+// Add stores a new watch and returns it with its ID.
+func (s *Store) Add(c Condition, once bool) (Watch, error) {
+	f, err := s.load()
+	if err != nil {
+		return Watch{}, err
+	}
+	w := Watch{ID: f.NextID, Condition: c, Once: once, CreatedAt: time.Now().UTC()}
+	f.NextID++
+	f.Watches = append(f.Watches, w)
+	return w, s.save(f)
+}
+
+// Remove deletes a watch, reporting whether it existed.
+func (s *Store) Remove(id int) (bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	for i, w := range f.Watches {
+		if w.ID == id {
+			f.Watches = append(f.Watches[:i], f.Watches[i+1:]...)
+			return true, s.save(f)
+		}
+	}
+	return false, nil
+}
+
+// update applies checked state to the stored watches by ID, dropping
+// fired one-shot watches. Watches added or removed since they were read
+// are left as they are.
+func (s *Store) update(checked map[int]Watch) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := f.Watches[:0]
+	for _, w := range f.Watches {
+		if c, ok := checked[w.ID]; ok {
+			if c.Once && c.Triggered {
+				continue
+			}
+			w = c
+		}
+		kept = append(kept, w)
+	}
+	f.Watches = kept
+	return s.save(f)
+}
@@ -0,0 +1,160 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Condition
+	}{
+		{"ETH > 4000", Condition{Kind: KindPrice, Symbol: "ETH", Chain: "ethereum", Op: ">", Value: 4000}},
+		{"eth>=$4,000", Condition{Kind: KindPrice, Symbol: "ETH", Chain: "ethereum", Op: ">=", Value: 4000}},
+		{"ARB@arbitrum < 0.5", Condition{Kind: KindPrice, Symbol: "ARB", Chain: "arbitrum", Op: "<", Value: 0.5}},
+		{"balance base < 0.1", Condition{Kind: KindBalance, Chain: "base", Op: "<", Value: 0.1}},
+		{"balance base hot <= 1", Condition{Kind: KindBalance, Chain: "base", Wallet: "hot", Op: "<=", Value: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseCondition(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			again, err := ParseCondition(got.String())
+			require.NoError(t, err)
+			assert.Equal(t, got, again, "String round-trips")
+		})
+	}
+
+	for _, bad := range []string{"", "ETH", "ETH = 4000", "ETH > lots", "ETH BTC > 1", "balance < 1", "ETH > -1"} {
+		_, err := ParseCondition(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+type fakeSource struct {
+	prices map[string]float64
+	calls  int
+}
+
+func (f *fakeSource) Price(_ context.Context, _, symbol string) (float64, error) {
+	f.calls++
+	return f.prices[symbol], nil
+}
+
+func (f *fakeSource) Balance(context.Context, string, common.Address) (float64, error) {
+	return 0.05, nil
+}
+
+type recorder struct{ alerts []Alert }
+
+func (r *recorder) Notify(_ context.Context, a Alert) error {
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+func TestWatcher_FiresOncePerCrossing(t *testing.T) {
+	store := NewStore(t.TempDir())
+	mustAdd := func(expr string, once bool) {
+		c, err := ParseCondition(expr)
+		require.NoError(t, err)
+		c.Wallet = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+		if c.Kind == KindPrice {
+			c.Wallet = ""
+		}
+		_, err = store.Add(c, once)
+		require.NoError(t, err)
+	}
+	mustAdd("ETH > 4000", false)
+	mustAdd("ETH < 3000", true)
+	mustAdd("balance base < 0.1", false)
+
+	src := &fakeSource{prices: map[string]float64{"ETH": 4100}}
+	rec := &recorder{}
+	w := &Watcher{Store: store, Source: src, Notifiers: []Notifier{rec}}
+	ctx := context.Background()
+
+	alerts, err := w.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, 1, alerts[0].Watch.ID)
+	assert.Equal(t, "Watch #1: ETH is $4100.00 (ETH > 4000)", alerts[0].Message())
+	assert.Equal(t, 3, alerts[1].Watch.ID)
+	assert.Equal(t, 1, src.calls, "one price lookup per token")
+	assert.Len(t, rec.alerts, 2)
+
+	// Still above: no repeat.
+	alerts, err = w.Check(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	// Dips below 3000 (the one-shot fires and is removed), then back above
+	// 4000 (watch 1 re-arms and fires again).
+	src.prices["ETH"] = 2900
+	alerts, err = w.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 2, alerts[0].Watch.ID)
+
+	src.prices["ETH"] = 4200
+	alerts, err = w.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 1, alerts[0].Watch.ID)
+
+	watches, err := store.List()
+	require.NoError(t, err)
+	var ids []int
+	for _, wt := range watches {
+		ids = append(ids, wt.ID)
+	}
+	assert.Equal(t, []int{1, 3}, ids)
+	assert.InDelta(t, 4200, watches[0].LastValue, 1e-9)
+}
+
+func TestStore_AddRemove(t *testing.T) {
+	store := NewStore(t.TempDir())
+	c, _ := ParseCondition("ETH > 1")
+	w1, err := store.Add(c, false)
+	require.NoError(t, err)
+	w2, err := store.Add(c, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, w2.ID)
+
+	ok, err := store.Remove(w1.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = store.Remove(w1.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// IDs are not reused.
+	w3, err := store.Add(c, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, w3.ID)
+}
+
+func TestWebhook(t *testing.T) {
+	var got webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	c, _ := ParseCondition("ETH > 4000")
+	err := Webhook{URL: srv.URL}.Notify(context.Background(), Alert{Watch: Watch{ID: 7, Condition: c}, Value: 4001})
+	require.NoError(t, err)
+	assert.Equal(t, 7, got.ID)
+	assert.Equal(t, "ETH > 4000", got.Condition)
+	assert.InDelta(t, 4001, got.Value, 1e-9)
+}
@@ -0,0 +1,183 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/yolodolo42/clifi/internal/chain"
+	"github.com/yolodolo42/clifi/internal/quote"
+)
+
+// DefaultInterval is how often `clifi serve` checks watches. Prices come
+// from a public API, so polling much faster mostly spends its rate limit.
+const DefaultInterval = time.Minute
+
+// Source reads the values conditions compare.
+type Source interface {
+	// Price returns a token's USD price on a chain.
+	Price(ctx context.Context, chainName, symbol string) (float64, error)
+	// Balance returns an address's native balance in whole units.
+	Balance(ctx context.Context, chainName string, address common.Address) (float64, error)
+}
+
+// ChainSource prices tokens through LI.FI and reads balances over RPC.
+type ChainSource struct {
+	Chains *chain.Client
+	Quotes *quote.Client
+}
+
+func (s ChainSource) Price(ctx context.Context, chainName, symbol string) (float64, error) {
+	cfg, err := s.Chains.GetChainConfig(chainName)
+	if err != nil {
+		return 0, err
+	}
+	return s.Quotes.TokenPrice(ctx, cfg.ChainID.Int64(), symbol)
+}
+
+func (s ChainSource) Balance(ctx context.Context, chainName string, address common.Address) (float64, error) {
+	// Each check wants a fresh balance, not the cached one.
+	s.Chains.InvalidateBalance(chainName, address)
+	wei, err := s.Chains.GetBalance(ctx, chainName, address)
+	if err != nil {
+		return 0, err
+	}
+	v, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return v, nil
+}
+
+// Alert is a watch whose condition started to hold.
+type Alert struct {
+	Watch Watch
+	Value float64
+	At    time.Time
+	// Text replaces the message, for alerts raised by other jobs such as
+	// limit orders.
+	Text string
+}
+
+// Message describes the alert in one line.
+func (a Alert) Message() string {
+	if a.Text != "" {
+		return a.Text
+	}
+	c := a.Watch.Condition
+	switch c.Kind {
+	case KindBalance:
+		return fmt.Sprintf("Watch #%d: balance on %s is %s (%s)", a.Watch.ID, c.Chain, formatValue(a.Value), c)
+	default:
+		return fmt.Sprintf("Watch #%d: %s is $%s (%s)", a.Watch.ID, c.Symbol, formatValue(a.Value), c)
+	}
+}
+
+func formatValue(v float64) string {
+	if v >= 100 {
+		return fmt.Sprintf("%.2f", v)
+	}
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.6f", v), "0"), ".")
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// Watcher checks stored watches against a Source and notifies when one
+// fires.
+type Watcher struct {
+	Store     *Store
+	Source    Source
+	Notifiers []Notifier
+	Interval  time.Duration
+}
+
+// Run checks watches every Interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			slog.Warn("watch check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check evaluates every watch once, notifies for those that fire and
+// returns their alerts. A watch fires when its condition starts to hold;
+// it fires again only after the condition has stopped holding.
+func (w *Watcher) Check(ctx context.Context) ([]Alert, error) {
+	watches, err := w.Store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	// Several watches on one token share a price lookup.
+	prices := make(map[string]float64)
+	now := time.Now().UTC()
+	checked := make(map[int]Watch, len(watches))
+	var alerts []Alert
+	for _, wt := range watches {
+		v, err := w.value(ctx, wt.Condition, prices)
+		wt.LastChecked = now
+		if err != nil {
+			wt.LastError = err.Error()
+			checked[wt.ID] = wt
+			slog.Warn("watch value unavailable", "watch", wt.ID, "condition", wt.Condition.String(), "err", err)
+			continue
+		}
+		wt.LastError = ""
+		wt.LastValue = v
+
+		holds := wt.Condition.Holds(v)
+		if holds && !wt.Triggered {
+			wt.FiredAt = now
+			alerts = append(alerts, Alert{Watch: wt, Value: v, At: now})
+		}
+		wt.Triggered = holds
+		checked[wt.ID] = wt
+	}
+
+	for _, a := range alerts {
+		for _, n := range w.Notifiers {
+			if err := n.Notify(ctx, a); err != nil {
+				slog.Warn("watch notification failed", "watch", a.Watch.ID, "notifier", fmt.Sprintf("%T", n), "err", err)
+			}
+		}
+	}
+	return alerts, w.Store.update(checked)
+}
+
+func (w *Watcher) value(ctx context.Context, c Condition, prices map[string]float64) (float64, error) {
+	switch c.Kind {
+	case KindPrice:
+		key := c.Chain + "/" + c.Symbol
+		if p, ok := prices[key]; ok {
+			return p, nil
+		}
+		p, err := w.Source.Price(ctx, c.Chain, c.Symbol)
+		if err != nil {
+			return 0, err
+		}
+		prices[key] = p
+		return p, nil
+	case KindBalance:
+		if !common.IsHexAddress(c.Wallet) {
+			return 0, fmt.Errorf("invalid wallet address %q", c.Wallet)
+		}
+		return w.Source.Balance(ctx, c.Chain, common.HexToAddress(c.Wallet))
+	}
+	return 0, fmt.Errorf("unknown watch kind %q", c.Kind)
+}
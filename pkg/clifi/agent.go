@@ -0,0 +1,137 @@
+package clifi
+
+import (
+	"context"
+	"time"
+
+	"github.com/yolodolo42/clifi/internal/agent"
+)
+
+// Agent runs conversations with an LLM provider and executes its tool
+// calls. Its own chat methods use its main conversation; NewSession opens
+// more.
+type Agent struct {
+	a     *agent.Agent
+	tools *ToolRegistry
+}
+
+// NewAgent creates an agent that uses provider and keeps wallets, receipts
+// and sessions in dataDir. Close it when done.
+func NewAgent(provider Provider, dataDir string) *Agent {
+	a := agent.NewWithProvider(provider.llmProvider(), dataDir)
+	return &Agent{a: a, tools: &ToolRegistry{a.Tools()}}
+}
+
+// ChatEvent is one step of a reply: a tool call, its result, text for the
+// user, or a notice.
+type ChatEvent struct {
+	Type    string // "tool_call", "tool_result", "content" or "notice"
+	Tool    string // for tool_call and tool_result
+	Args    string // tool_call arguments, secrets redacted
+	Content string
+	IsError bool          // the tool failed
+	Elapsed time.Duration // how long the tool ran, for tool_result
+}
+
+// Usage is a count of LLM tokens.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Chat sends a user message and returns the agent's reply.
+func (a *Agent) Chat(ctx context.Context, message string) (string, error) {
+	return a.a.Chat(ctx, message)
+}
+
+// ChatStream sends a user message and returns the reply's events, passing
+// each to onEvent, if set, as it happens.
+func (a *Agent) ChatStream(ctx context.Context, message string, onEvent func(ChatEvent)) ([]ChatEvent, error) {
+	events, err := a.a.ChatStream(ctx, message, streamTo(onEvent))
+	return chatEvents(events), err
+}
+
+// Reset clears the main conversation, once a running turn finishes.
+func (a *Agent) Reset() {
+	a.a.Reset()
+}
+
+// Model returns the model the agent uses.
+func (a *Agent) Model() string {
+	return a.a.CurrentModel()
+}
+
+// SetModel switches the provider's model. Conversations start over.
+func (a *Agent) SetModel(modelID string) error {
+	return a.a.SetModel(modelID)
+}
+
+// Usage returns the tokens used since the agent started.
+func (a *Agent) Usage() Usage {
+	u := a.a.Usage()
+	return Usage{InputTokens: u.InputTokens, OutputTokens: u.OutputTokens}
+}
+
+// Tools returns the agent's tool registry.
+func (a *Agent) Tools() *ToolRegistry {
+	return a.tools
+}
+
+// NewSession opens a conversation of its own that runs alongside the
+// others, e.g. one per client of a server.
+func (a *Agent) NewSession() *Session {
+	return &Session{a.a.NewSession()}
+}
+
+// Close releases the agent's connections and ends its sessions.
+func (a *Agent) Close() {
+	a.a.Close()
+}
+
+// Session is one conversation with an Agent. It runs one turn at a time;
+// separate sessions run theirs in parallel.
+type Session struct{ s *agent.Session }
+
+// Chat sends a user message and returns the agent's reply.
+func (s *Session) Chat(ctx context.Context, message string) (string, error) {
+	return s.s.Chat(ctx, message)
+}
+
+// ChatStream sends a user message and returns the reply's events, passing
+// each to onEvent, if set, as it happens.
+func (s *Session) ChatStream(ctx context.Context, message string, onEvent func(ChatEvent)) ([]ChatEvent, error) {
+	events, err := s.s.ChatStream(ctx, message, streamTo(onEvent))
+	return chatEvents(events), err
+}
+
+// Reset clears the conversation, once a running turn finishes.
+func (s *Session) Reset() {
+	s.s.Reset()
+}
+
+// Close ends the session.
+func (s *Session) Close() {
+	s.s.Close()
+}
+
+func streamTo(onEvent func(ChatEvent)) func(agent.ChatEvent) {
+	if onEvent == nil {
+		return nil
+	}
+	return func(e agent.ChatEvent) { onEvent(chatEvent(e)) }
+}
+
+func chatEvent(e agent.ChatEvent) ChatEvent {
+	return ChatEvent{Type: e.Type, Tool: e.Tool, Args: e.Args, Content: e.Content, IsError: e.IsError, Elapsed: e.Elapsed}
+}
+
+func chatEvents(events []agent.ChatEvent) []ChatEvent {
+	if events == nil {
+		return nil
+	}
+	out := make([]ChatEvent, len(events))
+	for i, e := range events {
+		out[i] = chatEvent(e)
+	}
+	return out
+}
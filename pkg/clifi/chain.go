@@ -0,0 +1,70 @@
+package clifi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/chain"
+)
+
+// ChainClient talks to EVM chains over JSON-RPC, by chain name such as
+// "base".
+type ChainClient struct{ c *chain.Client }
+
+// NewChainClient creates a chain client using dataDir's chains.yaml, or
+// the built-in chains when there is none. Close it when done.
+func NewChainClient(dataDir string) (*ChainClient, error) {
+	c, err := chain.NewConfiguredClient(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return &ChainClient{c}, nil
+}
+
+// ListChains returns the configured chain names.
+func (c *ChainClient) ListChains() []string {
+	return c.c.ListChains()
+}
+
+// ChainID returns a chain's ID.
+func (c *ChainClient) ChainID(chainName string) (*big.Int, error) {
+	cfg, err := c.c.GetChainConfig(chainName)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(cfg.ChainID), nil
+}
+
+// GetBalance returns an address's native balance in wei.
+func (c *ChainClient) GetBalance(ctx context.Context, chainName string, address common.Address) (*big.Int, error) {
+	return c.c.GetBalance(ctx, chainName, address)
+}
+
+// GetNonce returns an address's next nonce, counting its pending
+// transactions.
+func (c *ChainClient) GetNonce(ctx context.Context, chainName string, address common.Address) (uint64, error) {
+	return c.c.GetNonce(ctx, chainName, address)
+}
+
+// CallContract runs a read-only call against the latest block.
+func (c *ChainClient) CallContract(ctx context.Context, chainName string, msg ethereum.CallMsg) ([]byte, error) {
+	return c.c.CallContract(ctx, chainName, msg)
+}
+
+// SendTransaction broadcasts a signed transaction.
+func (c *ChainClient) SendTransaction(ctx context.Context, chainName string, tx *types.Transaction) error {
+	return c.c.SendTransaction(ctx, chainName, tx)
+}
+
+// WaitMined waits for a transaction's receipt.
+func (c *ChainClient) WaitMined(ctx context.Context, chainName string, txHash common.Hash) (*types.Receipt, error) {
+	return c.c.WaitMined(ctx, chainName, txHash)
+}
+
+// Close closes the RPC connections.
+func (c *ChainClient) Close() {
+	c.c.Close()
+}
@@ -0,0 +1,150 @@
+// Package clifi is the library surface for embedding clifi in other Go
+// programs: the agent, its tool registry, the EVM chain client and the
+// signer interfaces.
+//
+// Its types wrap clifi's internal ones and expose only the methods
+// declared here, which are the supported API; the internal packages may
+// change without notice. Chain values use go-ethereum's types.
+//
+//	provider, _ := clifi.NewProvider(clifi.ProviderAnthropic, os.Getenv("ANTHROPIC_API_KEY"))
+//	ag := clifi.NewAgent(provider, clifi.DefaultDataDir())
+//	defer ag.Close()
+//	reply, err := ag.Chat(ctx, "what's my balance on base?")
+package clifi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// DefaultDataDir is where the CLI keeps wallets, settings and sessions,
+// ~/.clifi. Sharing it lets an embedding program use the CLI's wallets.
+func DefaultDataDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".clifi"
+	}
+	return filepath.Join(home, ".clifi")
+}
+
+// ProviderID names an LLM provider.
+type ProviderID string
+
+// Provider IDs accepted by NewProvider.
+const (
+	ProviderAnthropic  = ProviderID(llm.ProviderAnthropic)
+	ProviderOpenAI     = ProviderID(llm.ProviderOpenAI)
+	ProviderVenice     = ProviderID(llm.ProviderVenice)
+	ProviderCopilot    = ProviderID(llm.ProviderCopilot)
+	ProviderGemini     = ProviderID(llm.ProviderGemini)
+	ProviderOpenRouter = ProviderID(llm.ProviderOpenRouter)
+)
+
+// Provider is an LLM provider for NewAgent, made by NewProvider or
+// NewMockProvider.
+type Provider interface {
+	ID() ProviderID
+	// Model is the model requests go to.
+	Model() string
+
+	llmProvider() llm.Provider
+}
+
+// provider is a Provider from NewProvider.
+type provider struct{ p llm.Provider }
+
+func (p provider) ID() ProviderID            { return ProviderID(p.p.ID()) }
+func (p provider) Model() string             { return p.p.DefaultModel() }
+func (p provider) llmProvider() llm.Provider { return p.p }
+
+// NewProvider creates an LLM provider from an API key, using the
+// provider's default model.
+func NewProvider(id ProviderID, apiKey string) (Provider, error) {
+	p, err := agent.NewProviderWithKey(llm.ProviderID(id), apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return provider{p}, nil
+}
+
+// Message is one message of a conversation.
+type Message struct {
+	Role    string // "user" or "assistant"
+	Content string
+}
+
+// ChatRequest is a request the agent sent to a provider.
+type ChatRequest struct {
+	Model        string
+	SystemPrompt string
+	Messages     []Message
+	Tools        []Tool
+}
+
+// ChatResponse is a provider's reply: text, or tool calls to run.
+type ChatResponse struct {
+	Content   string
+	ToolCalls []ToolCall
+}
+
+// ToolCall is a provider's request to run a tool.
+type ToolCall struct {
+	ID    string
+	Name  string
+	Input json.RawMessage
+}
+
+// MockTurn scripts the provider's side of one user turn. Responses[0]
+// answers the user's message and each later response answers the results
+// of the tool calls before it.
+type MockTurn struct {
+	// Match selects the turn when the user's message contains it
+	// (case-insensitive). Turns without Match are used in order for
+	// messages no Match fits.
+	Match     string
+	Responses []ChatResponse
+}
+
+// MockProvider replays scripted turns, for testing code that embeds the
+// agent without an API key.
+type MockProvider struct{ p *llm.MockProvider }
+
+// NewMockProvider returns a provider that plays turns.
+func NewMockProvider(turns ...MockTurn) *MockProvider {
+	out := make([]llm.MockTurn, len(turns))
+	for i, t := range turns {
+		out[i] = llm.MockTurn{Match: t.Match}
+		for _, r := range t.Responses {
+			resp := llm.ChatResponse{Content: r.Content}
+			for _, tc := range r.ToolCalls {
+				resp.ToolCalls = append(resp.ToolCalls, llm.ToolCall{ID: tc.ID, Name: tc.Name, Input: tc.Input})
+			}
+			out[i].Responses = append(out[i].Responses, resp)
+		}
+	}
+	return &MockProvider{llm.NewMockProvider(out...)}
+}
+
+func (p *MockProvider) ID() ProviderID            { return ProviderID(p.p.ID()) }
+func (p *MockProvider) Model() string             { return p.p.DefaultModel() }
+func (p *MockProvider) llmProvider() llm.Provider { return p.p }
+
+// Requests returns the requests received so far, oldest first.
+func (p *MockProvider) Requests() []ChatRequest {
+	reqs := p.p.Requests()
+	out := make([]ChatRequest, len(reqs))
+	for i, r := range reqs {
+		out[i] = ChatRequest{Model: r.Model, SystemPrompt: r.SystemPrompt}
+		for _, m := range r.Messages {
+			out[i].Messages = append(out[i].Messages, Message{Role: m.Role, Content: m.Content})
+		}
+		for _, t := range r.Tools {
+			out[i].Tools = append(out[i].Tools, Tool{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema})
+		}
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package clifi_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yolodolo42/clifi/pkg/clifi"
+)
+
+// The API is used from outside the module, so the test does the same.
+func TestEmbeddedAgent(t *testing.T) {
+	provider := clifi.NewMockProvider(clifi.MockTurn{Responses: []clifi.ChatResponse{
+		{ToolCalls: []clifi.ToolCall{{Name: "list_chains", Input: json.RawMessage(`{}`)}}},
+	}})
+	ag := clifi.NewAgent(provider, t.TempDir())
+	defer ag.Close()
+
+	var events []clifi.ChatEvent
+	_, err := ag.ChatStream(context.Background(), "chains?", func(e clifi.ChatEvent) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, "tool_call", events[0].Type)
+	assert.Equal(t, "list_chains", events[0].Tool)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dataDir := t.TempDir()
+	km, err := clifi.NewKeystore(dataDir)
+	require.NoError(t, err)
+	addr, err := km.CreateAccount("pw")
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{addr}, km.Accounts())
+
+	var signer clifi.Signer
+	signer, err = km.GetSigner(addr, "pw")
+	require.NoError(t, err)
+	assert.Equal(t, addr, signer.Address())
+
+	client, err := clifi.NewChainClient(dataDir)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Contains(t, client.ListChains(), "base")
+}
+
+func TestRegisterCustomTool(t *testing.T) {
+	provider := clifi.NewMockProvider(clifi.MockTurn{Responses: []clifi.ChatResponse{
+		{ToolCalls: []clifi.ToolCall{{Name: "get_vault_apy", Input: json.RawMessage(`{"vault":"usdc"}`)}}},
+	}})
+	ag := clifi.NewAgent(provider, t.TempDir())
+	defer ag.Close()
+
+	err := ag.Tools().RegisterTool(clifi.Tool{
+		Name:        "get_vault_apy",
+		Description: "Current APY of one of our vaults",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"vault":{"type":"string"}},"required":["vault"]}`),
+	}, func(ctx context.Context, input json.RawMessage) (clifi.ToolOutput, error) {
+		return clifi.ToolOutput{Text: "APY: 4.2%"}, nil
+	}, clifi.ToolReadOnly)
+	require.NoError(t, err)
+
+	reply, err := ag.Chat(context.Background(), "vault apy?")
+	require.NoError(t, err)
+	assert.Equal(t, "APY: 4.2%", reply)
+	var offered []string
+	for _, tool := range provider.Requests()[0].Tools {
+		offered = append(offered, tool.Name)
+	}
+	assert.Contains(t, offered, "get_vault_apy")
+}
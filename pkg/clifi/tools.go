@@ -0,0 +1,71 @@
+package clifi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/yolodolo42/clifi/internal/agent"
+	"github.com/yolodolo42/clifi/internal/llm"
+)
+
+// Tool describes a tool to the model.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema json.RawMessage // JSON Schema of the input object
+}
+
+// ToolOutput is a tool's result, as the model sees it.
+type ToolOutput struct {
+	Text string
+}
+
+// ToolHandler runs a tool on the model's JSON input.
+type ToolHandler func(ctx context.Context, input json.RawMessage) (ToolOutput, error)
+
+// ToolCapability says what a tool can do, so signing tools can be gated.
+type ToolCapability int
+
+// Tool capabilities for RegisterTool.
+const (
+	// ToolReadOnly tools only read state.
+	ToolReadOnly ToolCapability = iota
+	// ToolSigning tools can sign and broadcast transactions.
+	ToolSigning
+)
+
+// ToolRegistry holds the tools an agent can call and runs them. Add your
+// own with RegisterTool.
+type ToolRegistry struct{ tr *agent.ToolRegistry }
+
+// NewToolRegistry creates a standalone tool registry, for running clifi's
+// tools without an agent. An empty dataDir disables wallets and receipts.
+// Close it when done.
+func NewToolRegistry(dataDir string) *ToolRegistry {
+	return &ToolRegistry{agent.NewToolRegistryWithDataDir(dataDir)}
+}
+
+// RegisterTool adds a tool, or replaces one of the same name.
+func (r *ToolRegistry) RegisterTool(tool Tool, handler ToolHandler, capability ToolCapability) error {
+	c := agent.ToolReadOnly
+	if capability == ToolSigning {
+		c = agent.ToolSigning
+	}
+	return r.tr.RegisterTool(llm.Tool{Name: tool.Name, Description: tool.Description, InputSchema: tool.InputSchema},
+		func(ctx context.Context, input json.RawMessage) (agent.ToolOutput, error) {
+			out, err := handler(ctx, input)
+			return agent.ToolOutput{Text: out.Text}, err
+		}, c)
+}
+
+// ExecuteTool runs a tool on a JSON input.
+func (r *ToolRegistry) ExecuteTool(ctx context.Context, name string, input json.RawMessage) (ToolOutput, error) {
+	out, err := r.tr.ExecuteTool(ctx, name, input)
+	return ToolOutput{Text: out.Text}, err
+}
+
+// Close releases the registry's connections. An agent's registry closes
+// with the agent.
+func (r *ToolRegistry) Close() {
+	r.tr.Close()
+}
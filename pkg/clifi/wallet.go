@@ -0,0 +1,61 @@
+package clifi
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/yolodolo42/clifi/internal/wallet"
+)
+
+// Signer signs transactions and messages for one address.
+type Signer interface {
+	Address() common.Address
+	SignTransaction(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignMessage signs an EIP-191 personal message.
+	SignMessage(message []byte) ([]byte, error)
+	// SignTypedData signs EIP-712 typed data, given as JSON.
+	SignTypedData(typedData []byte) ([]byte, error)
+	// Lock drops any key material held in memory.
+	Lock()
+}
+
+// Keystore is the encrypted keystore the CLI keeps its wallets in.
+type Keystore struct{ km *wallet.KeystoreManager }
+
+// NewKeystore opens the keystore in dataDir; GetSigner unlocks an account
+// as a Signer.
+func NewKeystore(dataDir string) (*Keystore, error) {
+	km, err := wallet.NewKeystoreManager(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Keystore{km}, nil
+}
+
+// Accounts returns the keystore's addresses.
+func (k *Keystore) Accounts() []common.Address {
+	accs := k.km.ListAccounts()
+	out := make([]common.Address, len(accs))
+	for i, a := range accs {
+		out[i] = a.Address
+	}
+	return out
+}
+
+// CreateAccount generates a key encrypted with password.
+func (k *Keystore) CreateAccount(password string) (common.Address, error) {
+	acc, err := k.km.CreateAccount(password)
+	return acc.Address, err
+}
+
+// ImportKey stores a hex private key encrypted with password.
+func (k *Keystore) ImportKey(privateKeyHex, password string) (common.Address, error) {
+	acc, err := k.km.ImportKey(privateKeyHex, password)
+	return acc.Address, err
+}
+
+// GetSigner unlocks address with password.
+func (k *Keystore) GetSigner(address common.Address, password string) (Signer, error) {
+	return k.km.GetSigner(address, password)
+}